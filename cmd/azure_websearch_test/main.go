@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -42,7 +44,7 @@ func main() {
 		country = flag.String("country", "US", "2-letter country code for user_location (e.g. US)")
 		region  = flag.String("region", "", "optional region for user_location")
 		city    = flag.String("city", "", "optional city for user_location")
-		timeout = flag.Duration("timeout", 2*time.Minute, "request timeout")
+		timeout = flag.Duration("timeout", 2*time.Minute, "request timeout (also bounds connecting and waiting for response headers)")
 	)
 	flag.Parse()
 
@@ -116,9 +118,9 @@ func main() {
 	fmt.Fprintf(os.Stderr, "[azure_websearch_test] model=%s country=%s region=%s city=%s\n",
 		deployment, loc.Country, safePtr(loc.Region), safePtr(loc.City))
 
-	resp, err := (&http.Client{}).Do(httpReq)
+	resp, err := newHTTPClient(*timeout).Do(httpReq)
 	if err != nil {
-		log.Fatalf("request failed: %v", err)
+		log.Fatalf("request failed: %s", describeRequestError(err, *timeout))
 	}
 	defer resp.Body.Close()
 
@@ -137,6 +139,40 @@ func main() {
 	}
 }
 
+// maxConnectTimeout bounds dialing and the TLS handshake; a reachable
+// endpoint connects in well under it even when the response is slow.
+const maxConnectTimeout = 30 * time.Second
+
+// newHTTPClient returns a client whose dial, TLS handshake and wait for
+// response headers are bounded by timeout (connecting by at most
+// maxConnectTimeout), so a stuck connection fails even before the context
+// deadline applies.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	connectTimeout := min(timeout, maxConnectTimeout)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// describeRequestError says whether a failed request could not connect or
+// timed out, instead of the bare wrapped error.
+func describeRequestError(err error, timeout time.Duration) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		if opErr.Timeout() {
+			return fmt.Sprintf("could not connect to %v within %s: %v", opErr.Addr, min(timeout, maxConnectTimeout), err)
+		}
+		return fmt.Sprintf("could not connect to %v: %v", opErr.Addr, err)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Sprintf("no response within %s (--timeout): %v", timeout, err)
+	}
+	return err.Error()
+}
+
 func safePtr(s *string) string {
 	if s == nil {
 		return ""
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Prints a batch's status and counters, its runs per question tag (the tags
+// snapshotted on each run) and its stored processing errors, to answer "why
+// are questions missing from this batch".
+func run() int {
+	var (
+		batch     = flag.String("batch", "", "question_run_batch ID to report on")
+		maxErrors = flag.Int("max-errors", 200, "print at most this many errors (0 = all); the per-code counts always cover every error")
+		timeout   = flag.Duration("timeout", 2*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	batchID, err := uuid.Parse(*batch)
+	if err != nil {
+		log.Printf("[batch_report] --batch must be a UUID: %v", err)
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[batch_report] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	b, err := repos.QuestionRunBatchRepo.GetByID(ctx, batchID)
+	if err != nil {
+		log.Printf("[batch_report] failed to get batch %s: %v", batchID, err)
+		return fixer.ExitFatal
+	}
+	if b == nil {
+		log.Printf("[batch_report] batch %s not found", batchID)
+		return fixer.ExitFatal
+	}
+
+	fmt.Printf("batch=%s status=%s total=%d completed=%d failed=%d created=%s\n",
+		b.BatchID, b.Status, b.TotalQuestions, b.CompletedQuestions, b.FailedQuestions, b.CreatedAt.UTC().Format(time.RFC3339))
+
+	runsByTag, err := repos.QuestionRunTagRepo.CountByBatch(ctx, batchID)
+	if err != nil {
+		log.Printf("[batch_report] %v", err)
+		return fixer.ExitFatal
+	}
+	tags := make([]string, 0, len(runsByTag))
+	for tag := range runsByTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if runsByTag[tags[i]] != runsByTag[tags[j]] {
+			return runsByTag[tags[i]] > runsByTag[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	fmt.Printf("\nruns by tag (a run counts once per tag):\n")
+	for _, tag := range tags {
+		fmt.Printf("  %-24s %d\n", tag, runsByTag[tag])
+	}
+
+	batchErrors, err := repos.BatchErrorRepo.ListByBatch(ctx, batchID)
+	if err != nil {
+		log.Printf("[batch_report] %v", err)
+		return fixer.ExitFatal
+	}
+
+	fmt.Printf("\nerrors: %d\n", len(batchErrors))
+	byCode := make(map[services.ErrorCode]int)
+	for _, e := range batchErrors {
+		byCode[e.ErrorCode]++
+	}
+	codes := make([]services.ErrorCode, 0, len(byCode))
+	for code := range byCode {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return byCode[codes[i]] > byCode[codes[j]] })
+	for _, code := range codes {
+		fmt.Printf("  %-16s %d\n", code, byCode[code])
+	}
+
+	for i, e := range batchErrors {
+		if *maxErrors > 0 && i >= *maxErrors {
+			fmt.Printf("... %d more (raise --max-errors to see them)\n", len(batchErrors)-i)
+			break
+		}
+		question := "-"
+		if e.GeoQuestionID != nil {
+			question = e.GeoQuestionID.String()
+		}
+		fmt.Printf("%s code=%s model=%s location=%s question=%s %s\n",
+			e.CreatedAt.UTC().Format(time.RFC3339), e.ErrorCode, e.Model, e.Location, question, e.Message)
+	}
+	return fixer.ExitOK
+}
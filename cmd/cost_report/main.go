@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Writes an org's or network's AI spend for one month as CSV: one row per
+// source, category, provider and model (see services.CostRollup for what is
+// counted and how double counting is avoided), then "#" rows with the totals
+// per source and provider, the total cost and the billed usage.
+func run() int {
+	var (
+		org     = flag.String("org", "", "org ID to report on (its own runs plus its evaluations of network runs)")
+		network = flag.String("network", "", "network ID to report on (its runs and all their extractions)")
+		month   = flag.String("month", time.Now().UTC().Format("2006-01"), "month to report on (YYYY-MM, UTC)")
+		out     = flag.String("out", "", "CSV output path (empty = stdout)")
+		timeout = flag.Duration("timeout", 5*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	if (*org == "") == (*network == "") {
+		log.Printf("[cost_report] set exactly one of --org and --network")
+		return fixer.ExitFatal
+	}
+	scope, rawID := services.CostScopeOrg, *org
+	if *network != "" {
+		scope, rawID = services.CostScopeNetwork, *network
+	}
+	scopeID, err := uuid.Parse(rawID)
+	if err != nil {
+		log.Printf("[cost_report] --%s must be a UUID: %v", scope, err)
+		return fixer.ExitFatal
+	}
+	monthStart, err := services.ParseMonth(*month)
+	if err != nil {
+		log.Printf("[cost_report] --month: %v", err)
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[cost_report] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	rollup, err := repos.CostRollupRepo.Rollup(ctx, scope, scopeID, monthStart)
+	if err != nil {
+		log.Printf("[cost_report] %v", err)
+		return fixer.ExitFatal
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Printf("[cost_report] failed to create %s: %v", *out, err)
+			return fixer.ExitFatal
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeCostRollupCSV(w, rollup); err != nil {
+		log.Printf("[cost_report] failed to write report: %v", err)
+		return fixer.ExitFatal
+	}
+
+	log.Printf("[cost_report] %s=%s month=%s lines=%d total_cost=%.6f billed=%.6f",
+		scope, scopeID, rollup.MonthStart.Format("2006-01"), len(rollup.Lines), rollup.TotalCost, rollup.Billed)
+	return fixer.ExitOK
+}
+
+func writeCostRollupCSV(w io.Writer, rollup *services.CostRollup) error {
+	cw := csv.NewWriter(w)
+	month := rollup.MonthStart.Format("2006-01")
+	_ = cw.Write([]string{"scope", "scope_id", "month", "source", "category", "provider", "model", "calls", "cost"})
+	for _, line := range rollup.Lines {
+		_ = cw.Write([]string{string(rollup.Scope), rollup.ScopeID.String(), month,
+			line.Source, line.Category, line.Provider, line.Model, strconv.Itoa(line.Calls), formatCost(line.Cost)})
+	}
+
+	// Totals go in trailing comment-style rows so the file stays one table.
+	writeTotals := func(kind string, totals map[string]float64) {
+		keys := make([]string, 0, len(totals))
+		for key := range totals {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			_ = cw.Write([]string{"#" + kind, key, "", "", "", "", "", "", formatCost(totals[key])})
+		}
+	}
+	writeTotals("source", rollup.TotalsBy(func(l services.CostRollupLine) string { return l.Source }))
+	writeTotals("provider", rollup.TotalsBy(func(l services.CostRollupLine) string { return l.Provider }))
+	_ = cw.Write([]string{"#total_cost", "", "", "", "", "", "", "", formatCost(rollup.TotalCost)})
+	_ = cw.Write([]string{"#billed", "", "", "", "", "", "", "", formatCost(rollup.Billed)})
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 6, 64)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Step retries have left question runs with their mentions (and claims) stored
+// twice. By default the tool only prints what it would remove; --apply deletes
+// the newer duplicate sets of every affected run (see
+// services.CollapseDuplicateExtractions).
+func run() int {
+	var (
+		apply   = flag.Bool("apply", false, "delete the duplicates (default is a dry run that only prints them)")
+		days    = flag.Int("days", 30, "only scan runs created in the last N days")
+		timeout = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	if *days < 1 {
+		log.Printf("[duplicate_extraction_cleanup] --days must be >= 1")
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[duplicate_extraction_cleanup] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	since := time.Now().UTC().AddDate(0, 0, -*days)
+	log.Printf("[duplicate_extraction_cleanup] start apply=%t since=%s", *apply, since.Format(time.RFC3339))
+
+	results, err := services.CollapseDuplicateExtractions(ctx, services.NewQuestionRunStageRepo(dbClient), services.NewExtractionDedupRepo(dbClient),
+		services.DuplicateExtractionOptions{Since: since, Apply: *apply})
+	if err != nil {
+		log.Printf("[duplicate_extraction_cleanup] %v", err)
+		return fixer.ExitFatal
+	}
+
+	verb := "would_delete"
+	if *apply {
+		verb = "deleted"
+	}
+	totalMentions, totalClaims, failed := 0, 0, 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			log.Printf("[duplicate_extraction_cleanup] run=%s ERROR %v", r.QuestionRunID, r.Err)
+			continue
+		}
+		totalMentions += len(r.MentionIDs)
+		totalClaims += len(r.ClaimIDs)
+		log.Printf("[duplicate_extraction_cleanup] run=%s %s mentions=%d claims=%d", r.QuestionRunID, verb, len(r.MentionIDs), len(r.ClaimIDs))
+	}
+
+	code := fixer.FailurePolicy{FailOnAny: true}.ExitCode(failed, len(results))
+	log.Printf("[duplicate_extraction_cleanup] done runs=%d %s_mentions=%d %s_claims=%d failed=%d apply=%t exit=%d",
+		len(results), verb, totalMentions, verb, totalClaims, failed, *apply, code)
+	if !*apply {
+		log.Printf("[duplicate_extraction_cleanup] dry run: re-run with --apply to write changes")
+	}
+	return code
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Replays the current evaluation extraction against stored production
+// responses and writes the results to --out, without writing to the database.
+// Run it on two code versions and diff the outputs with --compare.
+func run() int {
+	var (
+		orgID     = flag.String("org", "", "org UUID the responses are evaluated for (name, websites and default network come from it)")
+		runIDFile = flag.String("run-ids", "", "file of question_run IDs to replay (one per line); otherwise the network's runs in --from/--to are replayed")
+		idsFormat = flag.String("ids-format", fixer.IDFormatAuto, "format of the --run-ids file: auto, plain or jsonl")
+		network   = flag.String("network", "", "network UUID to replay runs of (default: the org's network)")
+		from      = flag.String("from", "", "replay runs created on or after this UTC date (YYYY-MM-DD)")
+		to        = flag.String("to", "", "replay runs created before this UTC date (YYYY-MM-DD, default: the day after --from)")
+		mode      = flag.String("mode", services.ReplayModeNetworkOrg, "extraction to replay: network_org (pre-filter + ExtractNetworkOrgEvaluation) or mentions (ExtractMentions + metrics)")
+		maxRuns   = flag.Int("max-runs", 0, "optional max runs to replay (0 = all)")
+		out       = flag.String("out", "replay.json", "output path (.csv or .json)")
+		compare   = flag.String("compare", "", "compare two replay outputs instead of replaying: before,after (e.g. old.json,new.json)")
+		timeout   = flag.Duration("timeout", 60*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	if *compare != "" {
+		return runCompare(*compare)
+	}
+
+	orgUUID, err := uuid.Parse(*orgID)
+	if err != nil {
+		log.Printf("[extraction_replay] --org must be a UUID: %v", err)
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[extraction_replay] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	orgDetails, err := services.NewOrgService(cfg, repos).GetOrgDetails(ctx, orgUUID.String())
+	if err != nil {
+		log.Printf("[extraction_replay] org=%s ERROR get details: %v", orgUUID, err)
+		return fixer.ExitFatal
+	}
+	target := services.ReplayTarget{
+		OrgID:    orgUUID,
+		OrgName:  orgDetails.Org.Name,
+		Websites: orgDetails.Websites,
+	}
+
+	replayer := services.NewExtractionReplayer(cfg, repos)
+	var runs []*models.QuestionRun
+	if *runIDFile != "" {
+		ids, err := fixer.ReadIDs(*runIDFile, *idsFormat)
+		if err != nil {
+			log.Printf("[extraction_replay] failed reading run ids: %v", err)
+			return fixer.ExitFatal
+		}
+		runIDs := make([]uuid.UUID, 0, len(ids))
+		for _, id := range ids {
+			runID, err := uuid.Parse(id)
+			if err != nil {
+				log.Printf("[extraction_replay] invalid run id %q: %v", id, err)
+				return fixer.ExitFatal
+			}
+			runIDs = append(runIDs, runID)
+		}
+		runs, err = replayer.LoadRunsByID(ctx, runIDs)
+		if err != nil {
+			log.Printf("[extraction_replay] %v", err)
+			return fixer.ExitFatal
+		}
+	} else {
+		networkID := *network
+		if networkID == "" {
+			networkID = orgDetails.Org.NetworkID.String()
+		}
+		networkUUID, err := uuid.Parse(networkID)
+		if err != nil {
+			log.Printf("[extraction_replay] invalid network %q: %v", networkID, err)
+			return fixer.ExitFatal
+		}
+		start, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			log.Printf("[extraction_replay] --from is required with a network (YYYY-MM-DD): %v", err)
+			return fixer.ExitFatal
+		}
+		end := start.AddDate(0, 0, 1)
+		if *to != "" {
+			if end, err = time.Parse("2006-01-02", *to); err != nil {
+				log.Printf("[extraction_replay] invalid --to: %v", err)
+				return fixer.ExitFatal
+			}
+		}
+		runs, err = replayer.LoadNetworkRuns(ctx, networkUUID, start, end)
+		if err != nil {
+			log.Printf("[extraction_replay] %v", err)
+			return fixer.ExitFatal
+		}
+	}
+	if *maxRuns > 0 && *maxRuns < len(runs) {
+		runs = runs[:*maxRuns]
+	}
+
+	log.Printf("[extraction_replay] org=%s mode=%s runs=%d out=%s", orgUUID, *mode, len(runs), *out)
+	results, err := replayer.Replay(ctx, *mode, target, runs)
+	if err != nil && len(results) == 0 {
+		log.Printf("[extraction_replay] %v", err)
+		return fixer.ExitFatal
+	}
+	if err != nil {
+		log.Printf("[extraction_replay] stopped after %d/%d runs: %v", len(results), len(runs), err)
+	}
+
+	if writeErr := services.WriteReplayResults(*out, results); writeErr != nil {
+		log.Printf("[extraction_replay] ERROR writing results: %v", writeErr)
+		return fixer.ExitFatal
+	}
+
+	failed, mentioned, inputTokens, outputTokens := 0, 0, 0, 0
+	var cost float64
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+		if r.Mentioned {
+			mentioned++
+		}
+		inputTokens += r.InputTokens
+		outputTokens += r.OutputTokens
+		cost += r.Cost
+	}
+	log.Printf("[extraction_replay] done replayed=%d mentioned=%d failed=%d input_tokens=%d output_tokens=%d total_cost=%.6f",
+		len(results), mentioned, failed, inputTokens, outputTokens, cost)
+
+	if err != nil || failed > 0 {
+		return fixer.ExitPartialFailure
+	}
+	return fixer.ExitOK
+}
+
+// runCompare prints the per-run differences between two replay outputs.
+func runCompare(paths string) int {
+	beforePath, afterPath, ok := strings.Cut(paths, ",")
+	if !ok || strings.TrimSpace(beforePath) == "" || strings.TrimSpace(afterPath) == "" {
+		log.Printf("[extraction_replay] --compare wants two files: before,after")
+		return fixer.ExitFatal
+	}
+	before, err := services.ReadReplayResults(strings.TrimSpace(beforePath))
+	if err != nil {
+		log.Printf("[extraction_replay] %v", err)
+		return fixer.ExitFatal
+	}
+	after, err := services.ReadReplayResults(strings.TrimSpace(afterPath))
+	if err != nil {
+		log.Printf("[extraction_replay] %v", err)
+		return fixer.ExitFatal
+	}
+
+	diffs := services.CompareReplayResults(before, after)
+	for _, d := range diffs {
+		fmt.Printf("%s  %s\n", d.QuestionRunID, strings.Join(d.Changes, "; "))
+	}
+	log.Printf("[extraction_replay] compared before=%d after=%d runs_changed=%d", len(before), len(after), len(diffs))
+	return fixer.ExitOK
+}
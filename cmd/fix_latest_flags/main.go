@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Data repairs can leave several runs of a question flagged is_latest. The tool
+// recomputes the flags of every question of an org or network (newest run
+// wins, see services.RepairLatestFlags); --dry-run only reports the questions
+// it would fix.
+func run() int {
+	var (
+		orgID     = flag.String("org-id", "", "org whose questions to repair")
+		networkID = flag.String("network-id", "", "network whose questions to repair")
+		dryRun    = flag.Bool("dry-run", false, "report the questions with wrong flags without writing")
+		timeout   = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	if (*orgID == "") == (*networkID == "") {
+		log.Printf("[fix_latest_flags] set exactly one of --org-id and --network-id")
+		return fixer.ExitFatal
+	}
+	scope, rawID := "org", *orgID
+	if *networkID != "" {
+		scope, rawID = "network", *networkID
+	}
+	scopeID, err := uuid.Parse(rawID)
+	if err != nil {
+		log.Printf("[fix_latest_flags] --%s-id must be a UUID: %v", scope, err)
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[fix_latest_flags] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	var questionIDs []uuid.UUID
+	if scope == "org" {
+		questions, err := repos.GeoQuestionRepo.GetByOrgWithTags(ctx, scopeID)
+		if err != nil {
+			log.Printf("[fix_latest_flags] failed to get org questions: %v", err)
+			return fixer.ExitFatal
+		}
+		for _, q := range questions {
+			questionIDs = append(questionIDs, q.Question.GeoQuestionID)
+		}
+	} else {
+		questions, err := repos.GeoQuestionRepo.GetByNetwork(ctx, scopeID)
+		if err != nil {
+			log.Printf("[fix_latest_flags] failed to get network questions: %v", err)
+			return fixer.ExitFatal
+		}
+		for _, q := range questions {
+			questionIDs = append(questionIDs, q.GeoQuestionID)
+		}
+	}
+	log.Printf("[fix_latest_flags] start %s=%s questions=%d dry_run=%t", scope, scopeID, len(questionIDs), *dryRun)
+
+	results := services.RepairLatestFlags(ctx, repos, questionIDs, !*dryRun)
+	changed, failed := 0, 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			log.Printf("[fix_latest_flags] question=%s ERROR %v", r.QuestionID, r.Err)
+			continue
+		}
+		if !r.Changed {
+			continue
+		}
+		changed++
+		log.Printf("[fix_latest_flags] question=%s runs=%d flagged=%d latest=%s", r.QuestionID, r.Runs, r.Flagged, r.LatestRunID)
+	}
+
+	verb := "fixed"
+	if *dryRun {
+		verb = "would_fix"
+	}
+	code := fixer.FailurePolicy{FailOnAny: true}.ExitCode(failed, len(results))
+	log.Printf("[fix_latest_flags] done %s=%s questions=%d %s=%d failed=%d exit=%d", scope, scopeID, len(results), verb, changed, failed, code)
+	return code
+}
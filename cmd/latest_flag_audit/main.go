@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Prints the is_latest audit history of one question, oldest first, to answer
+// "why does the dashboard show this run" without reading code.
+func run() int {
+	var (
+		question = flag.String("question", "", "geo_question_id to dump the latest-flag history of")
+		timeout  = flag.Duration("timeout", 2*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	questionID, err := uuid.Parse(*question)
+	if err != nil {
+		log.Printf("[latest_flag_audit] --question must be a UUID: %v", err)
+		return fixer.ExitFatal
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[latest_flag_audit] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	transitions, err := services.NewLatestFlagAuditRepo(dbClient).ListByQuestion(ctx, questionID)
+	if err != nil {
+		log.Printf("[latest_flag_audit] %v", err)
+		return fixer.ExitFatal
+	}
+
+	log.Printf("[latest_flag_audit] question=%s transitions=%d", questionID, len(transitions))
+	for _, t := range transitions {
+		previous, batch := "-", "-"
+		if t.PreviousRunID != nil {
+			previous = t.PreviousRunID.String()
+		}
+		if t.BatchID != nil {
+			batch = t.BatchID.String()
+		}
+		fmt.Printf("%s source=%s batch=%s previous=%s new=%s\n",
+			t.CreatedAt.UTC().Format(time.RFC3339), t.Source, batch, previous, t.NewRunID)
+	}
+	return fixer.ExitOK
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"flag"
 	"fmt"
@@ -19,6 +18,7 @@ import (
 	"github.com/AI-Template-SDK/senso-api/pkg/database"
 	"github.com/AI-Template-SDK/senso-api/pkg/models"
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
 	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
@@ -47,33 +47,6 @@ func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*data
 	return &database.Client{DB: db}, nil
 }
 
-func readOrgIDs(path string) ([]string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var out []string
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		out = append(out, line)
-	}
-	if err := sc.Err(); err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func utcTodayStart(now time.Time) time.Time {
-	t := now.UTC()
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-}
-
 func modelNameMatches(candidate, desired string) bool {
 	c := strings.ToLower(strings.TrimSpace(candidate))
 	d := strings.ToLower(strings.TrimSpace(desired))
@@ -93,7 +66,11 @@ func modelNameContains(candidate, substr string) bool {
 	return strings.Contains(c, s)
 }
 
-func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, todayStart time.Time) (*models.QuestionRunBatch, error) {
+// findTodaysOrgBatch returns the org's newest batch created since todayStart.
+// With includeCompleted a completed batch is eligible too, so backfill runs
+// get attached to (and mixed into) a batch that already finished; without it
+// only unfinished batches are, and the caller creates a fresh one otherwise.
+func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, todayStart time.Time, includeCompleted bool) (*models.QuestionRunBatch, error) {
 	batches, err := repos.QuestionRunBatchRepo.GetByOrg(ctx, orgUUID)
 	if err != nil {
 		return nil, err
@@ -106,6 +83,9 @@ func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager,
 		if b.CreatedAt.Before(todayStart) {
 			continue
 		}
+		if !includeCompleted && b.Status == "completed" {
+			continue
+		}
 		if newestToday == nil || b.CreatedAt.After(newestToday.CreatedAt) {
 			newestToday = b
 		}
@@ -113,13 +93,13 @@ func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager,
 	return newestToday, nil
 }
 
-func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, error) {
+func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, totalQuestions int, batchType string) (*models.QuestionRunBatch, error) {
 	now := time.Now()
 	batch := &models.QuestionRunBatch{
 		BatchID:            uuid.New(),
 		Scope:              "org",
 		OrgID:              &orgUUID,
-		BatchType:          "openai_fixer",
+		BatchType:          batchType,
 		Status:             "running",
 		TotalQuestions:     totalQuestions,
 		CompletedQuestions: 0,
@@ -129,16 +109,33 @@ func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgU
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
-	if err := repos.QuestionRunBatchRepo.Create(ctx, batch); err != nil {
+	// Clear is_latest on the previous batch for this scope and create this one, atomically.
+	if err := services.CreateLatestBatch(ctx, repos, batch); err != nil {
 		return nil, err
 	}
 	return batch, nil
 }
 
+// completeOrgBatch records the final counts on a batch this run created and marks it completed.
+func completeOrgBatch(ctx context.Context, repos *services.RepositoryManager, b *models.QuestionRunBatch, completed, failed int) error {
+	if drift := services.ReconcileBatchCounts(b, completed, failed, 0); drift != 0 {
+		log.Printf("[openai_fixer] WARN batch=%s reconciliation: total=%d completed=%d failed=%d (drift=%d)", b.BatchID, b.TotalQuestions, completed, failed, drift)
+	}
+	now := time.Now()
+	b.Status = "completed"
+	b.CompletedAt = &now
+	b.UpdatedAt = now
+	return repos.QuestionRunBatchRepo.Update(ctx, b)
+}
+
+// retryBackoff is the base delay between retries of a failed provider call (scaled by attempt).
+const retryBackoff = 2 * time.Second
+
 type runJob struct {
 	orgID   string
 	qID     uuid.UUID
 	qText   string
+	tags    []string
 	model   *models.GeoModel
 	loc     *models.OrgLocation
 	batchID uuid.UUID
@@ -153,26 +150,67 @@ type runJobResult struct {
 }
 
 func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+func run() int {
 	var (
 		orgFile         = flag.String("org-file", filepath.Join(".", "example_orgs.txt"), "path to file containing org UUIDs (one per line)")
 		dryRun          = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
 		concurrency     = flag.Int("concurrency", 5, "number of concurrent OpenAI calls/inserts per org (bounded)")
 		maxOrgs         = flag.Int("max-orgs", 0, "optional max orgs to process (0 = all)")
+		maxQuestions    = flag.Int("max-questions", 0, "testing only: consider at most this many questions per org (0 = all), e.g. for smoke tests against production data")
 		timeout         = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
 		writeModelMatch = flag.String("write-model", "chatgpt", "geo_models name (or substring) to backfill (e.g. 'chatgpt'); runs will be written using that model_id/name")
 		apiModel        = flag.String("api-model", "gpt-5.2", "OpenAI model to use at runtime via Responses API (web search enabled)")
+		retries         = flag.Int("retries", 2, "max retries per provider call (0 = no retries)")
+		retryBudget     = flag.Int("retry-budget", 20, "max total retries shared across all jobs of one org; once spent, failures are not retried")
+		planOut         = flag.String("plan-out", "", "dry-run only: write every planned job plus skip counts to this path (.csv or .json)")
+		idsFormat       = flag.String("ids-format", fixer.IDFormatAuto, "format of the org id file: auto (detect per line), plain (one id per line) or jsonl ({\"id\": ...} per line)")
+		batchType       = flag.String("batch-type", "openai_fixer", "question_run_batches.batch_type to tag created batches with (e.g. to distinguish variant backfills)")
+		failOnAny       = flag.Bool("fail-on-any", true, "exit 2 if any job failed or was left unrun (see --fail-threshold to tolerate some)")
+		failThreshold   = flag.Float64("fail-threshold", 0, "with --fail-on-any=false: exit 2 only when the failed fraction of jobs exceeds this (0-1)")
+		shuffle         = flag.Bool("shuffle", false, "randomize the job order of each org before dispatch, so reruns don't always hit the same questions first")
+		shuffleSeed     = flag.Int64("shuffle-seed", 0, "with --shuffle: seed for the job order (0 = from the clock); the seed is logged so a run can be reproduced")
+		heartbeat       = flag.Duration("heartbeat", 30*time.Second, "log an in-progress line (created/failed/remaining/cost) per org at this interval while its jobs run (0 disables)")
+		countPlanned    = flag.Bool("count-planned", false, "when resuming today's existing batch, reset its total_questions to the runs already attached plus the jobs planned now (new batches are always sized by planned jobs); not for batches a pipeline is still filling")
+		withCompleted   = flag.Bool("include-completed-batches", true, "attach runs to today's newest batch even if it is completed (the default, for compatibility), which mixes backfill runs into a finished batch; set false to create a fresh batch instead")
 	)
 	flag.Parse()
 
+	var plan *fixer.Plan
+	if *dryRun && *planOut != "" {
+		plan = fixer.NewPlan()
+	}
+
+	var shuffler *fixer.Shuffler
+	if *shuffle {
+		shuffler = fixer.NewShuffler(*shuffleSeed)
+	}
+
 	// Load env vars like the main service (but this tool is intentionally standalone).
 	if err := godotenv.Load(); err != nil {
 		_ = godotenv.Load("dev.env")
 	}
 	cfg := config.Load()
+	// Write models no provider recognizes are refused; the providers config can route more
+	if _, err := services.LoadProvidersConfig(cfg.ProvidersConfigFile); err != nil {
+		log.Fatalf("Failed to load providers config: %v", err)
+	}
 
 	if *concurrency < 1 {
 		log.Fatalf("--concurrency must be >= 1")
 	}
+	if *maxQuestions < 0 {
+		log.Fatalf("--max-questions must be >= 0")
+	}
+	if strings.TrimSpace(*batchType) == "" {
+		log.Fatalf("--batch-type must not be empty")
+	}
+	if *failThreshold < 0 || *failThreshold > 1 {
+		log.Fatalf("--fail-threshold must be between 0 and 1")
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
@@ -192,10 +230,10 @@ func main() {
 		if strings.TrimSpace(cfg.AzureOpenAIEndpoint) == "" || strings.TrimSpace(cfg.AzureOpenAIKey) == "" || strings.TrimSpace(cfg.AzureOpenAIDeploymentName) == "" {
 			log.Fatalf("AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_KEY, and AZURE_OPENAI_DEPLOYMENT_NAME are required for live runs (Azure-only; web search required)")
 		}
-		provider = services.NewOpenAIProvider(cfg, *apiModel, services.NewCostService())
+		provider = services.NewOpenAIProvider(cfg, *apiModel, services.NewCostService(cfg))
 	}
 
-	orgIDs, err := readOrgIDs(*orgFile)
+	orgIDs, err := fixer.ReadIDs(*orgFile, *idsFormat)
 	if err != nil {
 		log.Fatalf("Failed reading org list: %v", err)
 	}
@@ -204,62 +242,74 @@ func main() {
 	}
 
 	log.Printf("[openai_fixer] orgs=%d dry_run=%t concurrency=%d write_model_match=%s api_model=%s", len(orgIDs), *dryRun, *concurrency, *writeModelMatch, *apiModel)
+	if shuffler != nil {
+		log.Printf("[openai_fixer] shuffling job order with seed=%d (rerun with --shuffle --shuffle-seed=%d for the same order)", shuffler.Seed, shuffler.Seed)
+	}
 	if *dryRun {
 		log.Printf("[openai_fixer] DRY RUN MODE: no DB writes, no OpenAI calls will be made")
 		log.Printf("[openai_fixer] To execute for real: AZURE_OPENAI_ENDPOINT=... AZURE_OPENAI_KEY=... AZURE_OPENAI_DEPLOYMENT_NAME=... go run ./cmd/openai_fixer --dry-run=false --write-model %s --api-model %s --concurrency %d", *writeModelMatch, *apiModel, *concurrency)
 	}
-	todayStart := utcTodayStart(time.Now())
-	log.Printf("[openai_fixer] todayStart(UTC)=%s", todayStart.Format(time.RFC3339))
+	// Each org/network's "today" starts at midnight in its batch day time zone
+	// (BATCH_DAY_TIMEZONES, default UTC), evaluated at this start time.
+	runStart := time.Now()
+	// Totals across all orgs for the exit code; jobs left unrun by the timeout count as failed.
+	totalJobs, totalFailed := 0, 0
 
-	for idx, orgID := range orgIDs {
+	unprocessed := fixer.EachScope(ctx, orgIDs, func(idx int, orgID string) {
 		log.Printf("[openai_fixer] (%d/%d) org=%s", idx+1, len(orgIDs), orgID)
 
 		orgDetails, err := orgService.GetOrgDetails(ctx, orgID)
 		if err != nil {
 			log.Printf("[openai_fixer] org=%s ERROR get details: %v", orgID, err)
-			continue
+			return
+		}
+		// GetOrgDetails already left these out of orgDetails.Locations
+		for _, invalid := range orgDetails.InvalidLocations {
+			log.Printf("[openai_fixer] org=%s WARN %s", orgID, invalid.Message())
+			plan.Skip("scope_invalid_location", 1)
+		}
+		if capped := fixer.CapQuestions(orgDetails.Questions, *maxQuestions); len(capped) < len(orgDetails.Questions) {
+			log.Printf("[openai_fixer] org=%s considering %d/%d questions (--max-questions)", orgID, len(capped), len(orgDetails.Questions))
+			orgDetails.Questions = capped
 		}
 
 		// Backfill ALL org geo_models that match write-model (typically "chatgpt").
+		// Models no provider recognizes are never backfilled, even when they match.
 		selectedModels := make([]*models.GeoModel, 0)
 		for _, m := range orgDetails.Models {
-			if modelNameContains(m.Name, *writeModelMatch) || modelNameMatches(m.Name, *writeModelMatch) {
-				selectedModels = append(selectedModels, m)
+			if !modelNameContains(m.Name, *writeModelMatch) && !modelNameMatches(m.Name, *writeModelMatch) {
+				continue
 			}
+			if !services.IsSupportedModel(cfg, m.Name) {
+				log.Printf("[openai_fixer] org=%s WARN refusing unconfigured model %q (no provider recognizes it)", orgID, m.Name)
+				plan.Skip("scope_unconfigured_model", 1)
+				continue
+			}
+			selectedModels = append(selectedModels, m)
 		}
 		if len(selectedModels) == 0 {
 			log.Printf("[openai_fixer] org=%s skip (no geo model matching %q configured on org)", orgID, *writeModelMatch)
-			continue
+			plan.Skip("scope_no_matching_model", 1)
+			return
 		}
 
 		orgUUID, err := uuid.Parse(orgID)
 		if err != nil {
 			log.Printf("[openai_fixer] org=%s invalid uuid: %v", orgID, err)
-			continue
+			return
 		}
+		dayLoc := services.BatchDayLocation(cfg, orgUUID)
+		todayStart := services.DayStart(runStart, dayLoc)
+		log.Printf("[openai_fixer] org=%s todayStart=%s (tz=%s)", orgID, todayStart.Format(time.RFC3339), dayLoc)
 
 		// Attach runs to today's org batch (create if missing; but NEVER create in dry-run).
-		totalQuestions := len(orgDetails.Questions) * len(selectedModels) * len(orgDetails.Locations)
-		batch, err := findTodaysOrgBatch(ctx, repos, orgUUID, todayStart)
+		batch, err := findTodaysOrgBatch(ctx, repos, orgUUID, todayStart, *withCompleted)
 		if err != nil {
 			log.Printf("[openai_fixer] org=%s ERROR finding today's batch: %v", orgID, err)
-			continue
+			return
 		}
 
 		isExisting := batch != nil
-		if !isExisting {
-			if *dryRun {
-				log.Printf("[openai_fixer] org=%s DRY RUN would create today's batch (type=openai_fixer total_questions=%d)", orgID, totalQuestions)
-			} else {
-				createdBatch, err := createOrgBatch(ctx, repos, orgUUID, totalQuestions)
-				if err != nil {
-					log.Printf("[openai_fixer] org=%s ERROR creating today's batch: %v", orgID, err)
-					continue
-				}
-				batch = createdBatch
-			}
-		}
-
 		batchID := uuid.Nil
 		batchStatus := ""
 		if batch != nil {
@@ -271,25 +321,35 @@ func main() {
 		// Build missing jobs for question × model × location (write-model(s)).
 		jobs := make([]runJob, 0)
 		seen := make(map[string]struct{})
-		skippedExisting := 0
+		skips := fixer.SkipCounts{}
 
 		for _, model := range selectedModels {
 			for _, loc := range orgDetails.Locations {
 				for _, qwt := range orgDetails.Questions {
 					q := qwt.Question
 
+					// Send the normalized text; the stored question is left untouched.
+					qText, err := services.NormalizeQuestionText(q.QuestionText, &workflowModels.Location{Country: loc.CountryCode, Region: loc.RegionName})
+					if err != nil {
+						log.Printf("[openai_fixer] org=%s skip question=%s: %v", orgID, q.GeoQuestionID, err)
+						skips.Add(fixer.SkipInvalidQuestion, 1)
+						continue
+					}
+
 					runs, err := repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
 					if err != nil {
 						// Be conservative: schedule if we can't verify.
 						key := fmt.Sprintf("%s|%s|%s", q.GeoQuestionID, model.GeoModelID, loc.OrgLocationID)
 						if _, ok := seen[key]; ok {
+							skips.Add(fixer.SkipDuplicateJob, 1)
 							continue
 						}
 						seen[key] = struct{}{}
 						jobs = append(jobs, runJob{
 							orgID:   orgID,
 							qID:     q.GeoQuestionID,
-							qText:   q.QuestionText,
+							qText:   qText,
+							tags:    services.QuestionTags(qwt),
 							model:   model,
 							loc:     loc,
 							batchID: batchID,
@@ -312,12 +372,13 @@ func main() {
 					}
 
 					if found {
-						skippedExisting++
+						skips.Add(fixer.SkipExistingRun, 1)
 						continue
 					}
 
 					key := fmt.Sprintf("%s|%s|%s", q.GeoQuestionID, model.GeoModelID, loc.OrgLocationID)
 					if _, ok := seen[key]; ok {
+						skips.Add(fixer.SkipDuplicateJob, 1)
 						continue
 					}
 					seen[key] = struct{}{}
@@ -325,7 +386,8 @@ func main() {
 					jobs = append(jobs, runJob{
 						orgID:   orgID,
 						qID:     q.GeoQuestionID,
-						qText:   q.QuestionText,
+						qText:   qText,
+						tags:    services.QuestionTags(qwt),
 						model:   model,
 						loc:     loc,
 						batchID: batchID,
@@ -334,11 +396,57 @@ func main() {
 			}
 		}
 
+		plan.SkipAll(skips)
+		for _, j := range jobs {
+			region := ""
+			if j.loc.RegionName != nil {
+				region = *j.loc.RegionName
+			}
+			plan.Add(fixer.PlanEntry{Scope: "org", ScopeID: orgID, QuestionID: j.qID.String(), Model: j.model.Name, Country: j.loc.CountryCode, Region: region})
+		}
+
 		if len(jobs) == 0 {
-			log.Printf("[openai_fixer] org=%s done (no missing runs) skipped_existing=%d", orgID, skippedExisting)
-			continue
+			log.Printf("[openai_fixer] org=%s done (no missing runs) skipped=%s", orgID, skips)
+			return
 		}
-		log.Printf("[openai_fixer] org=%s missing_jobs=%d skipped_existing=%d (executing with concurrency=%d)", orgID, len(jobs), skippedExisting, *concurrency)
+
+		// A new batch is sized by the jobs actually planned rather than questions ×
+		// models × locations, so runs that already exist never count against it;
+		// --count-planned re-plans a resumed batch the same way.
+		if !isExisting {
+			if *dryRun {
+				log.Printf("[openai_fixer] org=%s DRY RUN would create today's batch (type=openai_fixer total_questions=%d)", orgID, len(jobs))
+			} else {
+				createdBatch, err := createOrgBatch(ctx, repos, orgUUID, len(jobs), *batchType)
+				if err != nil {
+					log.Printf("[openai_fixer] org=%s ERROR creating today's batch: %v", orgID, err)
+					return
+				}
+				batch = createdBatch
+				for i := range jobs {
+					jobs[i].batchID = batch.BatchID
+				}
+				log.Printf("[openai_fixer] org=%s created batch=%s total_questions=%d day_tz=%s", orgID, batch.BatchID, len(jobs), dayLoc)
+			}
+		} else if *countPlanned {
+			if *dryRun {
+				log.Printf("[openai_fixer] org=%s DRY RUN would re-plan batch=%s total_questions=%d + attached runs", orgID, batch.BatchID, len(jobs))
+			} else {
+				total, err := services.PlanBatchTotal(ctx, repos, batch, len(jobs))
+				if err != nil {
+					log.Printf("[openai_fixer] org=%s WARN re-planning batch=%s: %v", orgID, batch.BatchID, err)
+				} else {
+					log.Printf("[openai_fixer] org=%s re-planned batch=%s total_questions=%d", orgID, batch.BatchID, total)
+				}
+			}
+		}
+
+		fixer.Shuffle(shuffler, jobs)
+
+		log.Printf("[openai_fixer] org=%s missing_jobs=%d skipped=%s (executing with concurrency=%d)", orgID, len(jobs), skips, *concurrency)
+
+		// Retries for every job of this org/network draw from one shared budget.
+		budget := fixer.NewRetryBudget(*retryBudget)
 
 		jobsCh := make(chan runJob)
 		resultsCh := make(chan runJobResult, len(jobs))
@@ -347,6 +455,10 @@ func main() {
 		worker := func() {
 			defer wg.Done()
 			for job := range jobsCh {
+				if ctx.Err() != nil {
+					// Overall timeout hit: stop picking up work instead of failing every remaining job.
+					return
+				}
 				if *dryRun {
 					resultsCh <- runJobResult{job: job, created: true}
 					continue
@@ -361,13 +473,26 @@ func main() {
 					Region:  job.loc.RegionName,
 				}
 
-				aiResp, err := provider.RunQuestion(ctx, job.qText, true, loc) // web search ON
+				var aiResp *services.AIResponse
+				err := fixer.Do(ctx, *retries, retryBackoff, budget, func(ctx context.Context) error {
+					var callErr error
+					aiResp, callErr = provider.RunQuestion(ctx, job.qText, true, loc) // web search ON
+					if callErr != nil {
+						// Classified so Do gives up on permanent failures (bad request, content policy)
+						return services.ClassifyProviderError(job.model.Name, callErr)
+					}
+					return aiResp.Err(job.model.Name)
+				})
 				if err != nil {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
 
 				responseText := aiResp.Response
+				if err := fixer.CheckResponseText(responseText, cfg.MinExtractionResponseChars); err != nil {
+					resultsCh <- runJobResult{job: job, failed: true, err: err}
+					continue
+				}
 				inputTokens := aiResp.InputTokens
 				outputTokens := aiResp.OutputTokens
 				totalCost := aiResp.Cost
@@ -405,6 +530,9 @@ func main() {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
+				if err := services.SetQuestionRunTags(ctx, repos, qr.QuestionRunID, job.tags); err != nil {
+					log.Printf("[openai_fixer] org=%s WARN storing tags of run %s: %v", job.orgID, qr.QuestionRunID, err)
+				}
 
 				resultsCh <- runJobResult{job: job, created: true, cost: totalCost}
 			}
@@ -416,10 +544,14 @@ func main() {
 		}
 
 		go func() {
+			defer close(jobsCh)
 			for _, j := range jobs {
-				jobsCh <- j
+				select {
+				case <-ctx.Done():
+					return
+				case jobsCh <- j:
+				}
 			}
-			close(jobsCh)
 		}()
 
 		go func() {
@@ -431,24 +563,57 @@ func main() {
 		failedCount := 0
 		var totalCost float64
 
+		progress := fixer.NewProgress(len(jobs))
+		stopHeartbeat := fixer.StartHeartbeat(*heartbeat, progress, func(line string) {
+			log.Printf("[openai_fixer] org=%s %s", orgID, line)
+		})
 		for res := range resultsCh {
 			if res.failed {
 				failedCount++
+				progress.Failed()
 				log.Printf("[openai_fixer] org=%s ERROR job question=%s location=%s: %v",
 					orgID, res.job.qID, res.job.loc.CountryCode, res.err)
 				continue
 			}
 			if res.created {
 				createdCount++
+				progress.Created(res.cost)
 				totalCost += res.cost
 				if *dryRun {
 					log.Printf("[openai_fixer] DRY RUN would insert run question=%s model=%s location=%s", res.job.qID, res.job.model.Name, res.job.loc.CountryCode)
 				}
 			}
 		}
+		stopHeartbeat()
+
+		totalJobs += len(jobs)
+		totalFailed += len(jobs) - createdCount
+		if !isExisting && batch != nil {
+			// Jobs left unrun by the timeout count as failed here too; the batch is
+			// still closed out after the overall timeout has cancelled ctx.
+			if err := completeOrgBatch(context.WithoutCancel(ctx), repos, batch, createdCount, len(jobs)-createdCount); err != nil {
+				log.Printf("[openai_fixer] org=%s WARN completing batch=%s: %v", orgID, batch.BatchID, err)
+			}
+		}
 
-		log.Printf("[openai_fixer] org=%s done created=%d skipped_existing=%d failed=%d total_cost=%.6f", orgID, createdCount, skippedExisting, failedCount, totalCost)
+		if ctx.Err() != nil {
+			log.Printf("[openai_fixer] org=%s interrupted by timeout: jobs_not_run=%d", orgID, len(jobs)-createdCount-failedCount)
+		}
+		log.Printf("[openai_fixer] org=%s done created=%d skipped=%s failed=%d retries_used=%d total_cost=%.6f", orgID, createdCount, skips, failedCount, budget.Used(), totalCost)
+	})
+	if unprocessed > 0 {
+		log.Printf("[openai_fixer] timeout reached, %d orgs unprocessed: %v", unprocessed, ctx.Err())
 	}
 
-	log.Printf("[openai_fixer] done")
+	if plan != nil {
+		if err := plan.WriteFile(*planOut); err != nil {
+			log.Printf("[openai_fixer] ERROR writing plan: %v", err)
+		} else {
+			log.Printf("[openai_fixer] plan written to %s (jobs=%d)", *planOut, len(plan.Entries))
+		}
+	}
+	policy := fixer.FailurePolicy{FailOnAny: *failOnAny, Threshold: *failThreshold}
+	code := policy.ExitCode(totalFailed, totalJobs)
+	log.Printf("[openai_fixer] done jobs=%d failed=%d exit=%d", totalJobs, totalFailed, code)
+	return code
 }
@@ -1,13 +1,13 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +20,7 @@ import (
 	"github.com/AI-Template-SDK/senso-api/pkg/models"
 	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
 	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
@@ -48,42 +49,6 @@ func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*data
 	return &database.Client{DB: db}, nil
 }
 
-func readIDs(path string) ([]string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var out []string
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		out = append(out, line)
-	}
-	if err := sc.Err(); err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func utcTodayStart(now time.Time) time.Time {
-	t := now.UTC()
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-}
-
-func modelNameContains(candidate, substr string) bool {
-	c := strings.ToLower(strings.TrimSpace(candidate))
-	s := strings.ToLower(strings.TrimSpace(substr))
-	if s == "" {
-		return false
-	}
-	return strings.Contains(c, s)
-}
-
 func regionString(region *string) string {
 	if region == nil {
 		return ""
@@ -175,13 +140,33 @@ func findTodaysNetworkBatch(ctx context.Context, repos *services.RepositoryManag
 	return newest, nil
 }
 
-func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager, networkUUID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, error) {
+// todaysNetworkBatch finds today's batch of a network and reports whether the
+// network has no question runs at all (or assumeEmpty is set). An empty network
+// has no batch today and no runs to skip, so neither per-question scan runs.
+func todaysNetworkBatch(ctx context.Context, db fixer.Getter, repos *services.RepositoryManager, networkID string, networkUUID uuid.UUID, todayStart time.Time, assumeEmpty bool) (*models.QuestionRunBatch, bool, error) {
+	empty := assumeEmpty
+	if !empty {
+		hasRuns, err := fixer.NetworkHasRuns(ctx, db, networkUUID)
+		if err != nil {
+			log.Printf("[openai_network_fixer] network=%s WARN empty-network check failed, scanning: %v", networkID, err)
+		}
+		empty = err == nil && !hasRuns
+	}
+	if empty {
+		log.Printf("[openai_network_fixer] network=%s has no question runs; skipping batch/run scans", networkID)
+		return nil, true, nil
+	}
+	batch, err := findTodaysNetworkBatch(ctx, repos, networkUUID, todayStart)
+	return batch, false, err
+}
+
+func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager, networkUUID uuid.UUID, totalQuestions int, batchType string) (*models.QuestionRunBatch, error) {
 	now := time.Now()
 	b := &models.QuestionRunBatch{
 		BatchID:            uuid.New(),
 		Scope:              "network",
 		NetworkID:          &networkUUID,
-		BatchType:          "openai_network_fixer",
+		BatchType:          batchType,
 		Status:             "running",
 		TotalQuestions:     totalQuestions,
 		CompletedQuestions: 0,
@@ -191,22 +176,107 @@ func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager,
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
-	if err := repos.QuestionRunBatchRepo.Create(ctx, b); err != nil {
+	// Clear is_latest on the previous batch for this scope and create this one, atomically.
+	if err := services.CreateLatestBatch(ctx, repos, b); err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
+// completeNetworkBatch records the final counts on a batch this run created and marks it completed.
+func completeNetworkBatch(ctx context.Context, repos *services.RepositoryManager, b *models.QuestionRunBatch, completed, failed int) error {
+	if drift := services.ReconcileBatchCounts(b, completed, failed, 0); drift != 0 {
+		log.Printf("[openai_network_fixer] WARN batch=%s reconciliation: total=%d completed=%d failed=%d (drift=%d)", b.BatchID, b.TotalQuestions, completed, failed, drift)
+	}
+	now := time.Now()
+	b.Status = "completed"
+	b.CompletedAt = &now
+	b.UpdatedAt = now
+	return repos.QuestionRunBatchRepo.Update(ctx, b)
+}
+
+// retryBackoff is the base delay between retries of a failed provider call (scaled by attempt).
+const retryBackoff = 2 * time.Second
+
 type runJob struct {
 	networkID  string
 	qID        uuid.UUID
 	qText      string
+	tags       []string
 	writeModel string
+	apiModel   string
 	country    string
 	region     *string
 	batchID    uuid.UUID
 }
 
+// networkTarget is the pre-pass verdict for one input network: either the
+// write models to backfill (and the API model serving each) or why it is skipped.
+type networkTarget struct {
+	networkID   string
+	networkUUID uuid.UUID
+	writeModels []string
+	apiModelFor map[string]string
+	// unconfigured are matched model names no provider recognizes; they are
+	// never backfilled
+	unconfigured []string
+	skipReason   string
+}
+
+// classifyNetwork decides from a network's configured model names whether it
+// has anything to backfill under modelMap. matched collects the write keys
+// that routed at least one model; a model no provider recognizes doesn't count.
+func classifyNetwork(cfg *config.Config, networkID string, networkUUID uuid.UUID, modelNames []string, modelMap fixer.ModelMap, matched map[string]bool) networkTarget {
+	t := networkTarget{networkID: networkID, networkUUID: networkUUID, apiModelFor: make(map[string]string)}
+	if len(modelNames) == 0 {
+		t.skipReason = "no_network_models"
+		return t
+	}
+	for _, name := range modelNames {
+		if mm, ok := modelMap.Resolve(name); ok {
+			if !services.IsSupportedModel(cfg, name) {
+				t.unconfigured = append(t.unconfigured, name)
+				continue
+			}
+			t.writeModels = append(t.writeModels, name)
+			t.apiModelFor[name] = mm.API
+			matched[mm.Write] = true
+		}
+	}
+	if len(t.writeModels) == 0 {
+		t.skipReason = "no_matching_model"
+		if len(t.unconfigured) > 0 {
+			t.skipReason = "unconfigured_model"
+		}
+	}
+	return t
+}
+
+// prepassNetworks loads the configured models for every input network before
+// any work starts so operators see up front how many networks have anything to do.
+func prepassNetworks(ctx context.Context, cfg *config.Config, repos *services.RepositoryManager, networkIDs []string, modelMap fixer.ModelMap) ([]networkTarget, map[string]bool) {
+	targets := make([]networkTarget, 0, len(networkIDs))
+	matched := make(map[string]bool)
+	for _, networkID := range networkIDs {
+		networkUUID, err := uuid.Parse(networkID)
+		if err != nil {
+			log.Printf("[openai_network_fixer] network=%s invalid uuid: %v", networkID, err)
+			targets = append(targets, networkTarget{networkID: networkID, skipReason: "invalid_uuid"})
+			continue
+		}
+
+		// Determine configured network models (do NOT fallback).
+		modelNames, err := repos.NetworkModelRepo.GetByNetworkID(ctx, networkUUID)
+		if err != nil {
+			log.Printf("[openai_network_fixer] network=%s ERROR get network models: %v", networkID, err)
+			targets = append(targets, networkTarget{networkID: networkID, networkUUID: networkUUID, skipReason: "model_lookup_error"})
+			continue
+		}
+		targets = append(targets, classifyNetwork(cfg, networkID, networkUUID, modelNames, modelMap, matched))
+	}
+	return targets, matched
+}
+
 type runJobResult struct {
 	job     runJob
 	created bool
@@ -216,25 +286,80 @@ type runJobResult struct {
 }
 
 func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Only question runs are inserted; no org websites are needed here. The
+// follow-up network.org.missing.process evaluation loads each member org's
+// websites itself (GetOrgDetailsForNetworkProcessing) before classifying
+// citations as primary or secondary.
+func run() int {
 	var (
-		networkFile = flag.String("network-file", filepath.Join(".", "example_networks.txt"), "path to file containing network UUIDs (one per line)")
-		dryRun      = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
-		concurrency = flag.Int("concurrency", 5, "number of concurrent OpenAI calls/inserts per network (bounded)")
-		maxNetworks = flag.Int("max-networks", 0, "optional max networks to process (0 = all)")
-		timeout     = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
-		writeModel  = flag.String("write-model", "chatgpt", "network model name (or substring) to backfill into question_runs.run_model (e.g. 'chatgpt')")
-		apiModel    = flag.String("api-model", "gpt-5.2", "OpenAI model to use at runtime via Responses API (web search enabled)")
+		networkFile   = flag.String("network-file", filepath.Join(".", "example_networks.txt"), "path to file containing network UUIDs (one per line)")
+		dryRun        = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
+		concurrency   = flag.Int("concurrency", 5, "number of concurrent OpenAI calls/inserts per network (bounded)")
+		maxNetworks   = flag.Int("max-networks", 0, "optional max networks to process (0 = all)")
+		maxQuestions  = flag.Int("max-questions", 0, "testing only: consider at most this many questions per network (0 = all), e.g. for smoke tests against production data")
+		timeout       = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+		writeModel    = flag.String("write-model", "chatgpt", "network model name (or substring) to backfill into question_runs.run_model (e.g. 'chatgpt'); ignored when --model-map is set")
+		apiModel      = flag.String("api-model", "gpt-5.2", "OpenAI model to use at runtime via Responses API (web search enabled); ignored when --model-map is set")
+		retries       = flag.Int("retries", 2, "max retries per provider call (0 = no retries)")
+		retryBudget   = flag.Int("retry-budget", 20, "max total retries shared across all jobs of one network; once spent, failures are not retried")
+		planOut       = flag.String("plan-out", "", "dry-run only: write every planned job plus skip counts to this path (.csv or .json)")
+		idsFormat     = flag.String("ids-format", fixer.IDFormatAuto, "format of the network id file: auto (detect per line), plain (one id per line) or jsonl ({\"id\": ...} per line)")
+		assumeEmpty   = flag.Bool("assume-empty", false, "treat every network as having no prior question runs: skip the today's-batch and existing-run scans (normally detected automatically)")
+		batchType     = flag.String("batch-type", "openai_network_fixer", "question_run_batches.batch_type to tag created batches with (e.g. to distinguish variant backfills)")
+		failOnAny     = flag.Bool("fail-on-any", true, "exit 2 if any job failed or was left unrun (see --fail-threshold to tolerate some)")
+		failThreshold = flag.Float64("fail-threshold", 0, "with --fail-on-any=false: exit 2 only when the failed fraction of jobs exceeds this (0-1)")
+		shuffle       = flag.Bool("shuffle", false, "randomize the job order of each network before dispatch, so reruns don't always hit the same questions first")
+		shuffleSeed   = flag.Int64("shuffle-seed", 0, "with --shuffle: seed for the job order (0 = from the clock); the seed is logged so a run can be reproduced")
+		heartbeat     = flag.Duration("heartbeat", 30*time.Second, "log an in-progress line (created/failed/remaining/cost) per network at this interval while its jobs run (0 disables)")
+		countPlanned  = flag.Bool("count-planned", false, "when resuming today's existing batch, reset its total_questions to the runs already attached plus the jobs planned now (new batches are always sized by planned jobs); not for batches a pipeline is still filling")
+		planOnly      = flag.Bool("plan-only", false, "load network models, print the processable/skipped summary and exit without running any jobs")
 	)
+	var modelMap fixer.ModelMap
+	flag.Var(&modelMap, "model-map", "repeatable write=api mapping (e.g. --model-map chatgpt=gpt-5.2 --model-map chatgpt-4o=gpt-4o); the longest matching write substring wins")
 	flag.Parse()
 
+	if len(modelMap) == 0 {
+		if err := modelMap.Set(*writeModel + "=" + *apiModel); err != nil {
+			log.Fatalf("invalid --write-model/--api-model: %v", err)
+		}
+	}
+
+	var plan *fixer.Plan
+	if *dryRun && *planOut != "" {
+		plan = fixer.NewPlan()
+	}
+
+	var shuffler *fixer.Shuffler
+	if *shuffle {
+		shuffler = fixer.NewShuffler(*shuffleSeed)
+	}
+
 	if err := godotenv.Load(); err != nil {
 		_ = godotenv.Load("dev.env")
 	}
 	cfg := config.Load()
+	// Write models no provider recognizes are refused; the providers config can route more
+	if _, err := services.LoadProvidersConfig(cfg.ProvidersConfigFile); err != nil {
+		log.Fatalf("Failed to load providers config: %v", err)
+	}
 
 	if *concurrency < 1 {
 		log.Fatalf("--concurrency must be >= 1")
 	}
+	if *maxQuestions < 0 {
+		log.Fatalf("--max-questions must be >= 0")
+	}
+	if strings.TrimSpace(*batchType) == "" {
+		log.Fatalf("--batch-type must not be empty")
+	}
+	if *failThreshold < 0 || *failThreshold > 1 {
+		log.Fatalf("--fail-threshold must be between 0 and 1")
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
@@ -247,16 +372,20 @@ func main() {
 
 	repos := services.NewRepositoryManager(dbClient)
 
-	var provider services.AIProvider
-	if !*dryRun {
+	// One provider per distinct API model.
+	providers := make(map[string]services.AIProvider)
+	if !*dryRun && !*planOnly {
 		// Azure-only: web search is required and must be executed via Azure OpenAI.
 		if strings.TrimSpace(cfg.AzureOpenAIEndpoint) == "" || strings.TrimSpace(cfg.AzureOpenAIKey) == "" || strings.TrimSpace(cfg.AzureOpenAIDeploymentName) == "" {
 			log.Fatalf("AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_KEY, and AZURE_OPENAI_DEPLOYMENT_NAME are required for live runs (Azure-only; web search required)")
 		}
-		provider = services.NewOpenAIProvider(cfg, *apiModel, services.NewCostService())
+		costService := services.NewCostService(cfg)
+		for _, api := range modelMap.APIModels() {
+			providers[api] = services.NewOpenAIProvider(cfg, api, costService)
+		}
 	}
 
-	networkIDs, err := readIDs(*networkFile)
+	networkIDs, err := fixer.ReadIDs(*networkFile, *idsFormat)
 	if err != nil {
 		log.Fatalf("Failed reading network list: %v", err)
 	}
@@ -264,73 +393,104 @@ func main() {
 		networkIDs = networkIDs[:*maxNetworks]
 	}
 
-	log.Printf("[openai_network_fixer] networks=%d dry_run=%t concurrency=%d write_model=%s api_model=%s", len(networkIDs), *dryRun, *concurrency, *writeModel, *apiModel)
+	log.Printf("[openai_network_fixer] networks=%d dry_run=%t concurrency=%d model_map=%s", len(networkIDs), *dryRun, *concurrency, modelMap.String())
+	if shuffler != nil {
+		log.Printf("[openai_network_fixer] shuffling job order with seed=%d (rerun with --shuffle --shuffle-seed=%d for the same order)", shuffler.Seed, shuffler.Seed)
+	}
 	if *dryRun {
 		log.Printf("[openai_network_fixer] DRY RUN MODE: no DB writes, no OpenAI calls will be made")
-		log.Printf("[openai_network_fixer] To execute for real: AZURE_OPENAI_ENDPOINT=... AZURE_OPENAI_KEY=... AZURE_OPENAI_DEPLOYMENT_NAME=... go run ./cmd/openai_network_fixer --dry-run=false --write-model %s --api-model %s --concurrency %d", *writeModel, *apiModel, *concurrency)
+		log.Printf("[openai_network_fixer] To execute for real: AZURE_OPENAI_ENDPOINT=... AZURE_OPENAI_KEY=... AZURE_OPENAI_DEPLOYMENT_NAME=... go run ./cmd/openai_network_fixer --dry-run=false --model-map %s --concurrency %d", modelMap.String(), *concurrency)
 	}
 
-	todayStart := utcTodayStart(time.Now())
-	log.Printf("[openai_network_fixer] todayStart(UTC)=%s", todayStart.Format(time.RFC3339))
-
-	for idx, networkID := range networkIDs {
-		log.Printf("[openai_network_fixer] (%d/%d) network=%s", idx+1, len(networkIDs), networkID)
-
-		networkUUID, err := uuid.Parse(networkID)
-		if err != nil {
-			log.Printf("[openai_network_fixer] network=%s invalid uuid: %v", networkID, err)
+	targets, matchedWrite := prepassNetworks(ctx, cfg, repos, networkIDs, modelMap)
+	processable := make([]networkTarget, 0, len(targets))
+	skipReasons := make(map[string]int)
+	for _, t := range targets {
+		if t.skipReason != "" {
+			skipReasons[t.skipReason]++
+			plan.Skip("scope_"+t.skipReason, 1)
 			continue
 		}
-
-		// Determine configured network models (do NOT fallback).
-		modelNames, err := repos.NetworkModelRepo.GetByNetworkID(ctx, networkUUID)
-		if err != nil {
-			log.Printf("[openai_network_fixer] network=%s ERROR get network models: %v", networkID, err)
-			continue
+		processable = append(processable, t)
+	}
+	log.Printf("[openai_network_fixer] pre-pass: %d processable, %d skipped", len(processable), len(targets)-len(processable))
+	reasons := make([]string, 0, len(skipReasons))
+	for reason := range skipReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		log.Printf("[openai_network_fixer] pre-pass:   skipped %s=%d", reason, skipReasons[reason])
+	}
+	for _, t := range targets {
+		if len(t.unconfigured) > 0 {
+			log.Printf("[openai_network_fixer] pre-pass:   network=%s WARN refusing unconfigured models %q (no provider recognizes them)", t.networkID, t.unconfigured)
 		}
-		if len(modelNames) == 0 {
-			log.Printf("[openai_network_fixer] network=%s skip (no network models configured; not using fallback defaults)", networkID)
-			continue
+		if t.skipReason != "" {
+			log.Printf("[openai_network_fixer] pre-pass:   network=%s skip (%s)", t.networkID, t.skipReason)
 		}
+	}
 
-		writeModels := make([]string, 0)
-		for _, name := range modelNames {
-			if modelNameContains(name, *writeModel) {
-				writeModels = append(writeModels, name)
-			}
-		}
-		if len(writeModels) == 0 {
-			log.Printf("[openai_network_fixer] network=%s skip (no network model matching %q configured)", networkID, *writeModel)
-			continue
+	// A mapping that routes no configured model is almost certainly a typo.
+	unmatched := 0
+	for _, mm := range modelMap {
+		if !matchedWrite[mm.Write] {
+			log.Printf("[openai_network_fixer] ERROR model mapping %s=%s matched no configured network model", mm.Write, mm.API)
+			unmatched++
 		}
+	}
+	if unmatched > 0 {
+		log.Fatalf("[openai_network_fixer] %d model mapping(s) matched no configured network model", unmatched)
+	}
+	if *planOnly {
+		log.Printf("[openai_network_fixer] --plan-only set; exiting before running any jobs")
+		return fixer.ExitOK
+	}
+
+	// Each org/network's "today" starts at midnight in its batch day time zone
+	// (BATCH_DAY_TIMEZONES, default UTC), evaluated at this start time.
+	runStart := time.Now()
+	// Totals across all networks for the exit code; jobs left unrun by the timeout count as failed.
+	totalJobs, totalFailed := 0, 0
+
+	unprocessed := fixer.EachScope(ctx, processable, func(idx int, target networkTarget) {
+		networkID, networkUUID := target.networkID, target.networkUUID
+		writeModels, apiModelFor := target.writeModels, target.apiModelFor
+		log.Printf("[openai_network_fixer] (%d/%d) network=%s", idx+1, len(processable), networkID)
+		dayLoc := services.BatchDayLocation(cfg, networkUUID)
+		todayStart := services.DayStart(runStart, dayLoc)
+		log.Printf("[openai_network_fixer] network=%s todayStart=%s (tz=%s)", networkID, todayStart.Format(time.RFC3339), dayLoc)
 
 		networkQuestions, networkLocations, err := loadNetworkQuestionsAndLocations(ctx, repos, networkUUID)
 		if err != nil {
 			log.Printf("[openai_network_fixer] network=%s ERROR load questions/locations: %v", networkID, err)
-			continue
+			return
+		}
+		// Same location validation as GetNetworkDetails: bad countries are left out
+		networkLocations, invalidLocations := services.ValidateLocations(networkLocations)
+		for _, invalid := range invalidLocations {
+			log.Printf("[openai_network_fixer] network=%s WARN %s", networkID, invalid.Message())
+			plan.Skip("scope_invalid_location", 1)
+		}
+		// Sampled networks only run today's sample; don't backfill the rest.
+		if sampler := services.NewQuestionSampler(cfg, networkUUID, time.Now()); sampler != nil {
+			sampled := sampler.Filter(networkQuestions)
+			log.Printf("[openai_network_fixer] network=%s sampling %.0f%% of questions: %d/%d selected for today", networkID, sampler.Fraction*100, len(sampled), len(networkQuestions))
+			networkQuestions = sampled
+		}
+		if capped := fixer.CapQuestions(networkQuestions, *maxQuestions); len(capped) < len(networkQuestions) {
+			log.Printf("[openai_network_fixer] network=%s considering %d/%d questions (--max-questions)", networkID, len(capped), len(networkQuestions))
+			networkQuestions = capped
 		}
 
-		totalQuestions := len(networkQuestions) * len(writeModels) * len(networkLocations)
-
-		batch, err := findTodaysNetworkBatch(ctx, repos, networkUUID, todayStart)
+		// A network with no runs at all has no batch today and nothing to skip,
+		// so the per-question scans are skipped.
+		batch, networkEmpty, err := todaysNetworkBatch(ctx, dbClient, repos, networkID, networkUUID, todayStart, *assumeEmpty)
 		if err != nil {
 			log.Printf("[openai_network_fixer] network=%s ERROR finding today's batch: %v", networkID, err)
-			continue
+			return
 		}
 		isExisting := batch != nil
-		if !isExisting {
-			if *dryRun {
-				log.Printf("[openai_network_fixer] network=%s DRY RUN would create today's batch (type=openai_network_fixer total_questions=%d)", networkID, totalQuestions)
-			} else {
-				createdBatch, err := createNetworkBatch(ctx, repos, networkUUID, totalQuestions)
-				if err != nil {
-					log.Printf("[openai_network_fixer] network=%s ERROR creating today's batch: %v", networkID, err)
-					continue
-				}
-				batch = createdBatch
-			}
-		}
-
 		batchID := uuid.Nil
 		batchStatus := ""
 		if batch != nil {
@@ -341,25 +501,39 @@ func main() {
 
 		jobs := make([]runJob, 0)
 		seen := make(map[string]struct{})
-		skippedExisting := 0
+		skips := fixer.SkipCounts{}
 
 		for _, writeModelName := range writeModels {
 			for _, loc := range networkLocations {
 				for _, qwt := range networkQuestions {
 					q := qwt.Question
 
-					runs, err := repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
+					// Send the normalized text; the stored question is left untouched.
+					qText, err := services.NormalizeQuestionText(q.QuestionText, &workflowModels.Location{Country: loc.CountryCode, Region: loc.RegionName})
+					if err != nil {
+						log.Printf("[openai_network_fixer] network=%s skip question=%s: %v", networkID, q.GeoQuestionID, err)
+						skips.Add(fixer.SkipInvalidQuestion, 1)
+						continue
+					}
+
+					var runs []*models.QuestionRun
+					if !networkEmpty {
+						runs, err = repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
+					}
 					if err != nil {
 						key := fmt.Sprintf("%s|%s|%s|%s", q.GeoQuestionID, writeModelName, loc.CountryCode, regionString(loc.RegionName))
 						if _, ok := seen[key]; ok {
+							skips.Add(fixer.SkipDuplicateJob, 1)
 							continue
 						}
 						seen[key] = struct{}{}
 						jobs = append(jobs, runJob{
 							networkID:  networkID,
 							qID:        q.GeoQuestionID,
-							qText:      q.QuestionText,
+							qText:      qText,
+							tags:       services.QuestionTags(qwt),
 							writeModel: writeModelName,
+							apiModel:   apiModelFor[writeModelName],
 							country:    loc.CountryCode,
 							region:     loc.RegionName,
 							batchID:    batchID,
@@ -388,12 +562,13 @@ func main() {
 					}
 
 					if found {
-						skippedExisting++
+						skips.Add(fixer.SkipExistingRun, 1)
 						continue
 					}
 
 					key := fmt.Sprintf("%s|%s|%s|%s", q.GeoQuestionID, writeModelName, loc.CountryCode, regionString(loc.RegionName))
 					if _, ok := seen[key]; ok {
+						skips.Add(fixer.SkipDuplicateJob, 1)
 						continue
 					}
 					seen[key] = struct{}{}
@@ -401,8 +576,10 @@ func main() {
 					jobs = append(jobs, runJob{
 						networkID:  networkID,
 						qID:        q.GeoQuestionID,
-						qText:      q.QuestionText,
+						qText:      qText,
+						tags:       services.QuestionTags(qwt),
 						writeModel: writeModelName,
+						apiModel:   apiModelFor[writeModelName],
 						country:    loc.CountryCode,
 						region:     loc.RegionName,
 						batchID:    batchID,
@@ -411,11 +588,57 @@ func main() {
 			}
 		}
 
+		plan.SkipAll(skips)
+		for _, j := range jobs {
+			region := ""
+			if j.region != nil {
+				region = *j.region
+			}
+			plan.Add(fixer.PlanEntry{Scope: "network", ScopeID: networkID, QuestionID: j.qID.String(), Model: j.writeModel, Country: j.country, Region: region})
+		}
+
 		if len(jobs) == 0 {
-			log.Printf("[openai_network_fixer] network=%s done (no missing runs) skipped_existing=%d", networkID, skippedExisting)
-			continue
+			log.Printf("[openai_network_fixer] network=%s done (no missing runs) skipped=%s", networkID, skips)
+			return
 		}
-		log.Printf("[openai_network_fixer] network=%s missing_jobs=%d skipped_existing=%d (executing with concurrency=%d)", networkID, len(jobs), skippedExisting, *concurrency)
+
+		// A new batch is sized by the jobs actually planned rather than questions ×
+		// models × locations, so runs that already exist never count against it;
+		// --count-planned re-plans a resumed batch the same way.
+		if !isExisting {
+			if *dryRun {
+				log.Printf("[openai_network_fixer] network=%s DRY RUN would create today's batch (type=%s total_questions=%d)", networkID, *batchType, len(jobs))
+			} else {
+				createdBatch, err := createNetworkBatch(ctx, repos, networkUUID, len(jobs), *batchType)
+				if err != nil {
+					log.Printf("[openai_network_fixer] network=%s ERROR creating today's batch: %v", networkID, err)
+					return
+				}
+				batch = createdBatch
+				for i := range jobs {
+					jobs[i].batchID = batch.BatchID
+				}
+				log.Printf("[openai_network_fixer] network=%s created batch=%s total_questions=%d day_tz=%s", networkID, batch.BatchID, len(jobs), dayLoc)
+			}
+		} else if *countPlanned {
+			if *dryRun {
+				log.Printf("[openai_network_fixer] network=%s DRY RUN would re-plan batch=%s total_questions=%d + attached runs", networkID, batch.BatchID, len(jobs))
+			} else {
+				total, err := services.PlanBatchTotal(ctx, repos, batch, len(jobs))
+				if err != nil {
+					log.Printf("[openai_network_fixer] network=%s WARN re-planning batch=%s: %v", networkID, batch.BatchID, err)
+				} else {
+					log.Printf("[openai_network_fixer] network=%s re-planned batch=%s total_questions=%d", networkID, batch.BatchID, total)
+				}
+			}
+		}
+
+		fixer.Shuffle(shuffler, jobs)
+
+		log.Printf("[openai_network_fixer] network=%s missing_jobs=%d skipped=%s (executing with concurrency=%d)", networkID, len(jobs), skips, *concurrency)
+
+		// Retries for every job of this org/network draw from one shared budget.
+		budget := fixer.NewRetryBudget(*retryBudget)
 
 		jobsCh := make(chan runJob)
 		resultsCh := make(chan runJobResult, len(jobs))
@@ -424,6 +647,10 @@ func main() {
 		worker := func() {
 			defer wg.Done()
 			for job := range jobsCh {
+				if ctx.Err() != nil {
+					// Overall timeout hit: stop picking up work instead of failing every remaining job.
+					return
+				}
 				if *dryRun {
 					resultsCh <- runJobResult{job: job, created: true}
 					continue
@@ -438,13 +665,26 @@ func main() {
 					Region:  job.region,
 				}
 
-				aiResp, err := provider.RunQuestion(ctx, job.qText, true, loc) // web search ON
+				var aiResp *services.AIResponse
+				err := fixer.Do(ctx, *retries, retryBackoff, budget, func(ctx context.Context) error {
+					var callErr error
+					aiResp, callErr = providers[job.apiModel].RunQuestion(ctx, job.qText, true, loc) // web search ON
+					if callErr != nil {
+						// Classified so Do gives up on permanent failures (bad request, content policy)
+						return services.ClassifyProviderError(job.apiModel, callErr)
+					}
+					return aiResp.Err(job.apiModel)
+				})
 				if err != nil {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
 
 				responseText := aiResp.Response
+				if err := fixer.CheckResponseText(responseText, cfg.MinExtractionResponseChars); err != nil {
+					resultsCh <- runJobResult{job: job, failed: true, err: err}
+					continue
+				}
 				inputTokens := aiResp.InputTokens
 				outputTokens := aiResp.OutputTokens
 				totalCost := aiResp.Cost
@@ -474,6 +714,9 @@ func main() {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
+				if err := services.SetQuestionRunTags(ctx, repos, qr.QuestionRunID, job.tags); err != nil {
+					log.Printf("[openai_network_fixer] network=%s WARN storing tags of run %s: %v", job.networkID, qr.QuestionRunID, err)
+				}
 
 				resultsCh <- runJobResult{job: job, created: true, cost: totalCost}
 			}
@@ -485,10 +728,14 @@ func main() {
 		}
 
 		go func() {
+			defer close(jobsCh)
 			for _, j := range jobs {
-				jobsCh <- j
+				select {
+				case <-ctx.Done():
+					return
+				case jobsCh <- j:
+				}
 			}
-			close(jobsCh)
 		}()
 
 		go func() {
@@ -500,24 +747,57 @@ func main() {
 		failedCount := 0
 		var totalCost float64
 
+		progress := fixer.NewProgress(len(jobs))
+		stopHeartbeat := fixer.StartHeartbeat(*heartbeat, progress, func(line string) {
+			log.Printf("[openai_network_fixer] network=%s %s", networkID, line)
+		})
 		for res := range resultsCh {
 			if res.failed {
 				failedCount++
-				log.Printf("[openai_network_fixer] network=%s ERROR job question=%s model=%s location=%s: %v",
-					networkID, res.job.qID, res.job.writeModel, res.job.country, res.err)
+				progress.Failed()
+				log.Printf("[openai_network_fixer] network=%s ERROR job question=%s model=%s api_model=%s location=%s: %v",
+					networkID, res.job.qID, res.job.writeModel, res.job.apiModel, res.job.country, res.err)
 				continue
 			}
 			if res.created {
 				createdCount++
+				progress.Created(res.cost)
 				totalCost += res.cost
 				if *dryRun {
-					log.Printf("[openai_network_fixer] DRY RUN would insert run question=%s model=%s location=%s", res.job.qID, res.job.writeModel, res.job.country)
+					log.Printf("[openai_network_fixer] DRY RUN would insert run question=%s model=%s api_model=%s location=%s", res.job.qID, res.job.writeModel, res.job.apiModel, res.job.country)
 				}
 			}
 		}
+		stopHeartbeat()
+
+		totalJobs += len(jobs)
+		totalFailed += len(jobs) - createdCount
+		if !isExisting && batch != nil {
+			// Jobs left unrun by the timeout count as failed here too; the batch is
+			// still closed out after the overall timeout has cancelled ctx.
+			if err := completeNetworkBatch(context.WithoutCancel(ctx), repos, batch, createdCount, len(jobs)-createdCount); err != nil {
+				log.Printf("[openai_network_fixer] network=%s WARN completing batch=%s: %v", networkID, batch.BatchID, err)
+			}
+		}
 
-		log.Printf("[openai_network_fixer] network=%s done created=%d skipped_existing=%d failed=%d total_cost=%.6f", networkID, createdCount, skippedExisting, failedCount, totalCost)
+		if ctx.Err() != nil {
+			log.Printf("[openai_network_fixer] network=%s interrupted by timeout: jobs_not_run=%d", networkID, len(jobs)-createdCount-failedCount)
+		}
+		log.Printf("[openai_network_fixer] network=%s done created=%d skipped=%s failed=%d retries_used=%d total_cost=%.6f", networkID, createdCount, skips, failedCount, budget.Used(), totalCost)
+	})
+	if unprocessed > 0 {
+		log.Printf("[openai_network_fixer] timeout reached, %d networks unprocessed: %v", unprocessed, ctx.Err())
 	}
 
-	log.Printf("[openai_network_fixer] done")
+	if plan != nil {
+		if err := plan.WriteFile(*planOut); err != nil {
+			log.Printf("[openai_network_fixer] ERROR writing plan: %v", err)
+		} else {
+			log.Printf("[openai_network_fixer] plan written to %s (jobs=%d)", *planOut, len(plan.Entries))
+		}
+	}
+	policy := fixer.FailurePolicy{FailOnAny: *failOnAny, Threshold: *failThreshold}
+	code := policy.ExitCode(totalFailed, totalJobs)
+	log.Printf("[openai_network_fixer] done jobs=%d failed=%d exit=%d", totalJobs, totalFailed, code)
+	return code
 }
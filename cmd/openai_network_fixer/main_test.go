@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/internal/sqltest"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+)
+
+func TestCreateNetworkBatchBatchType(t *testing.T) {
+	for _, batchType := range []string{"openai_network_fixer", "openai_network_fixer_rebackfill"} {
+		t.Run(batchType, func(t *testing.T) {
+			rec := &sqltest.Recorder{Exec: func(query string, args []any) (int64, error) { return 1, nil }}
+			repos := services.NewRepositoryManager(sqltest.NewClient(t, rec))
+
+			batch, err := createNetworkBatch(context.Background(), repos, uuid.New(), 12, batchType)
+			if err != nil {
+				t.Fatalf("createNetworkBatch() error = %v", err)
+			}
+			if batch.BatchType != batchType || batch.Scope != "network" || batch.TotalQuestions != 12 {
+				t.Errorf("batch = %+v, want a network batch of 12 questions typed %q", batch, batchType)
+			}
+			var inserted []any
+			for _, stmt := range rec.Statements() {
+				if strings.HasPrefix(stmt.Query, "INSERT INTO question_run_batches") {
+					inserted = stmt.Args
+				}
+			}
+			if len(inserted) < 5 || inserted[4] != batchType {
+				t.Errorf("inserted batch args = %v, want batch_type %q", inserted, batchType)
+			}
+		})
+	}
+}
+
+// TestClassifyNetworkModelMap fans one network's models out to the API model
+// of their longest matching --model-map entry.
+func TestClassifyNetworkModelMap(t *testing.T) {
+	var modelMap fixer.ModelMap
+	for _, mm := range []string{"chatgpt=gpt-5.2", "chatgpt-4o=gpt-4o", "claude=claude-sonnet-4"} {
+		if err := modelMap.Set(mm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	matched := make(map[string]bool)
+	target := classifyNetwork(&config.Config{}, "net-1", uuid.New(), []string{"chatgpt", "chatgpt-4o", "perplexity"}, modelMap, matched)
+
+	if target.skipReason != "" {
+		t.Fatalf("network skipped (%s), want it processed", target.skipReason)
+	}
+	if want := []string{"chatgpt", "chatgpt-4o"}; !reflect.DeepEqual(target.writeModels, want) {
+		t.Errorf("write models = %q, want %q", target.writeModels, want)
+	}
+	if want := map[string]string{"chatgpt": "gpt-5.2", "chatgpt-4o": "gpt-4o"}; !reflect.DeepEqual(target.apiModelFor, want) {
+		t.Errorf("API models = %v, want %v", target.apiModelFor, want)
+	}
+	if want := map[string]bool{"chatgpt": true, "chatgpt-4o": true}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("matched mappings = %v, want %v (claude matched nothing)", matched, want)
+	}
+}
+
+// networkModels is a NetworkModelRepository over a fixed network -> models map.
+type networkModels struct {
+	models map[uuid.UUID][]string
+	errs   map[uuid.UUID]error
+}
+
+func (r networkModels) GetByNetworkID(ctx context.Context, networkID uuid.UUID) ([]string, error) {
+	return r.models[networkID], r.errs[networkID]
+}
+
+func TestPrepassNetworks(t *testing.T) {
+	matching, noModels, noMatch, unconfigured, lookupFails := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repos := &services.RepositoryManager{NetworkModelRepo: networkModels{
+		models: map[uuid.UUID][]string{
+			matching:     {"perplexity", "chatgpt"},
+			noMatch:      {"perplexity", "gemini"},
+			unconfigured: {"copilot"},
+		},
+		errs: map[uuid.UUID]error{lookupFails: errors.New("connection reset")},
+	}}
+	var modelMap fixer.ModelMap
+	for _, mm := range []string{"chatgpt=gpt-5.2", "copilot=gpt-5.2"} {
+		if err := modelMap.Set(mm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	networkIDs := []string{matching.String(), "not-a-uuid", noModels.String(), noMatch.String(), unconfigured.String(), lookupFails.String()}
+
+	targets, matched := prepassNetworks(context.Background(), &config.Config{}, repos, networkIDs, modelMap)
+
+	wantReasons := []string{"", "invalid_uuid", "no_network_models", "no_matching_model", "unconfigured_model", "model_lookup_error"}
+	if len(targets) != len(wantReasons) {
+		t.Fatalf("%d targets, want one per input network (%d)", len(targets), len(wantReasons))
+	}
+	for i, target := range targets {
+		if target.networkID != networkIDs[i] || target.skipReason != wantReasons[i] {
+			t.Errorf("target %d = network %s skip %q, want network %s skip %q", i, target.networkID, target.skipReason, networkIDs[i], wantReasons[i])
+		}
+	}
+	if got := targets[0].writeModels; !reflect.DeepEqual(got, []string{"chatgpt"}) {
+		t.Errorf("processable network write models = %q, want [chatgpt]", got)
+	}
+	if got := targets[4].unconfigured; !reflect.DeepEqual(got, []string{"copilot"}) {
+		t.Errorf("unconfigured models = %q, want [copilot]", got)
+	}
+	// copilot only matched a model no provider serves, so it counts as unmatched
+	if want := map[string]bool{"chatgpt": true}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("matched mappings = %v, want %v", matched, want)
+	}
+}
+
+// hasRuns answers fixer.NetworkHasRuns' EXISTS query.
+type hasRuns struct {
+	exists bool
+	err    error
+}
+
+func (h hasRuns) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	*dest.(*bool) = h.exists
+	return h.err
+}
+
+type networkQuestions struct {
+	interfaces.GeoQuestionRepository
+	questions []*models.GeoQuestion
+}
+
+func (r networkQuestions) GetByNetwork(ctx context.Context, networkID uuid.UUID) ([]*models.GeoQuestion, error) {
+	return r.questions, nil
+}
+
+// scannedRuns counts the per-question run scans.
+type scannedRuns struct {
+	interfaces.QuestionRunRepository
+	scans *int
+}
+
+func (r scannedRuns) GetByQuestion(ctx context.Context, questionID uuid.UUID) ([]*models.QuestionRun, error) {
+	*r.scans++
+	return nil, nil
+}
+
+func TestTodaysNetworkBatchSkipsEmptyNetworks(t *testing.T) {
+	questions := []*models.GeoQuestion{{GeoQuestionID: uuid.New()}, {GeoQuestionID: uuid.New()}}
+
+	tests := []struct {
+		name        string
+		db          hasRuns
+		assumeEmpty bool
+		wantEmpty   bool
+		wantScans   int
+	}{
+		{name: "network without runs", db: hasRuns{exists: false}, wantEmpty: true},
+		{name: "--assume-empty", db: hasRuns{exists: true}, assumeEmpty: true, wantEmpty: true},
+		{name: "network with runs", db: hasRuns{exists: true}, wantScans: len(questions)},
+		{name: "failed check scans", db: hasRuns{err: errors.New("connection reset")}, wantScans: len(questions)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scans := 0
+			repos := &services.RepositoryManager{
+				GeoQuestionRepo: networkQuestions{questions: questions},
+				QuestionRunRepo: scannedRuns{scans: &scans},
+			}
+			batch, empty, err := todaysNetworkBatch(context.Background(), tt.db, repos, "net-1", uuid.New(), time.Now(), tt.assumeEmpty)
+			if err != nil || batch != nil {
+				t.Fatalf("todaysNetworkBatch() = %v, %v; want no batch", batch, err)
+			}
+			if empty != tt.wantEmpty {
+				t.Errorf("empty = %t, want %t", empty, tt.wantEmpty)
+			}
+			if scans != tt.wantScans {
+				t.Errorf("%d per-question run scans, want %d", scans, tt.wantScans)
+			}
+		})
+	}
+}
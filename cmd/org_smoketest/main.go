@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+)
+
+// Standalone onboarding tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+// --- no-op write repos (--no-persist) ---
+// Reads go to the real repository; writes are dropped.
+
+type noopQuestionRunRepo struct {
+	interfaces.QuestionRunRepository
+}
+
+func (noopQuestionRunRepo) Create(ctx context.Context, run *models.QuestionRun) error { return nil }
+func (noopQuestionRunRepo) Update(ctx context.Context, run *models.QuestionRun) error { return nil }
+
+type noopMentionRepo struct {
+	interfaces.QuestionRunMentionRepository
+}
+
+func (noopMentionRepo) BulkCreate(ctx context.Context, mentions []*models.QuestionRunMention) error {
+	return nil
+}
+
+type noopClaimRepo struct {
+	interfaces.QuestionRunClaimRepository
+}
+
+func (noopClaimRepo) BulkCreate(ctx context.Context, claims []*models.QuestionRunClaim) error {
+	return nil
+}
+
+type noopCitationRepo struct {
+	interfaces.QuestionRunCitationRepository
+}
+
+func (noopCitationRepo) BulkCreate(ctx context.Context, citations []*models.QuestionRunCitation) error {
+	return nil
+}
+
+// --- recording extractor ---
+
+// recordingExtractor wraps the real DataExtractionService so the report can show
+// what each stage produced and whether it failed; ProcessSingleQuestion only logs
+// extraction errors as warnings. Models run sequentially, so no locking.
+type recordingExtractor struct {
+	services.DataExtractionService
+
+	mentions  []*models.QuestionRunMention
+	claims    []*models.QuestionRunClaim
+	citations []*models.QuestionRunCitation
+	metrics   *services.CompetitiveMetrics
+	stageErrs map[string]error // stage name -> error, for stages that ran
+}
+
+func (r *recordingExtractor) reset() {
+	r.mentions, r.claims, r.citations, r.metrics = nil, nil, nil, nil
+	r.stageErrs = map[string]error{}
+}
+
+func (r *recordingExtractor) record(stage string, err error) {
+	r.stageErrs[stage] = err
+}
+
+func (r *recordingExtractor) ExtractMentions(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error) {
+	mentions, err := r.DataExtractionService.ExtractMentions(ctx, questionRunID, orgID, response, targetCompany, orgWebsites)
+	r.record("mentions", err)
+	r.mentions = mentions
+	return mentions, err
+}
+
+func (r *recordingExtractor) ExtractClaims(ctx context.Context, questionRunID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunClaim, error) {
+	claims, err := r.DataExtractionService.ExtractClaims(ctx, questionRunID, response, targetCompany, orgWebsites)
+	r.record("claims", err)
+	r.claims = claims
+	return claims, err
+}
+
+func (r *recordingExtractor) ExtractCitations(ctx context.Context, claims []*models.QuestionRunClaim, response string, orgWebsites []string) ([]*models.QuestionRunCitation, error) {
+	citations, err := r.DataExtractionService.ExtractCitations(ctx, claims, response, orgWebsites)
+	r.record("citations", err)
+	r.citations = citations
+	return citations, err
+}
+
+func (r *recordingExtractor) CalculateMetrics(ctx context.Context, mentions []*models.QuestionRunMention, response string, targetCompany string) (*services.CompetitiveMetrics, error) {
+	metrics, err := r.DataExtractionService.CalculateMetrics(ctx, mentions, response, targetCompany)
+	r.record("metrics", err)
+	r.metrics = metrics
+	return metrics, err
+}
+
+// --- report helpers ---
+
+func snippet(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
+
+func floatOrZero(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+func main() {
+	var (
+		orgID      = flag.String("org-id", "", "org UUID to smoke test (required)")
+		questionID = flag.String("question-id", "", "optional geo_question UUID (defaults to the org's first question)")
+		noPersist  = flag.Bool("no-persist", false, "run everything without DB writes (no-op question run/mention/claim/citation repos)")
+		maxSpend   = flag.Float64("max-spend", 1.00, "hard spend cap in USD across all models; remaining models are skipped once reached")
+		timeout    = flag.Duration("timeout", 15*time.Minute, "overall timeout for the script")
+		regexCites = flag.Bool("citation-regex-fallback", false, "add the URLs around a claim as citations when the LLM returns none (overrides CITATION_REGEX_FALLBACK)")
+	)
+	flag.Parse()
+
+	if strings.TrimSpace(*orgID) == "" {
+		log.Fatalf("--org-id is required")
+	}
+	if *maxSpend <= 0 {
+		log.Fatalf("--max-spend must be > 0")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+	if *regexCites {
+		cfg.CitationRegexFallback = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("DB connect failed: %v", err)
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	if *noPersist {
+		repos.QuestionRunRepo = noopQuestionRunRepo{repos.QuestionRunRepo}
+		repos.MentionRepo = noopMentionRepo{repos.MentionRepo}
+		repos.ClaimRepo = noopClaimRepo{repos.ClaimRepo}
+		repos.CitationRepo = noopCitationRepo{repos.CitationRepo}
+		repos.QuestionRunStageRepo = nil
+		repos.QuestionRunTruncationRepo = nil
+		repos.QuestionRunWebSearchRepo = nil
+		repos.QuestionRunLanguageRepo = nil
+		repos.LatestFlagAuditRepo = nil
+		repos.QuestionRunMetricsRepo = nil
+		repos.BatchErrorRepo = nil
+		repos.QuestionRunSOVRepo = nil
+		repos.QuestionRunTagRepo = nil
+		repos.NetworkOrgDeadLetterRepo = nil
+		repos.QuestionRunReuseRepo = nil
+	}
+
+	orgService := services.NewOrgService(cfg, repos)
+	extractor := &recordingExtractor{DataExtractionService: services.NewDataExtractionService(cfg)}
+	runner := services.NewQuestionRunnerService(cfg, repos, extractor, orgService)
+
+	details, err := orgService.GetOrgDetails(ctx, *orgID)
+	if err != nil {
+		log.Fatalf("Failed to load org details: %v", err)
+	}
+
+	failed := false
+	fail := func(format string, args ...interface{}) {
+		failed = true
+		fmt.Printf("  ❌ "+format+"\n", args...)
+	}
+
+	fmt.Printf("Org smoke test: %s (%s)\n", details.Org.Name, *orgID)
+	fmt.Printf("  target company: %s\n", details.TargetCompany)
+	fmt.Printf("  models: %d  locations: %d  questions: %d  websites: %d\n",
+		len(details.Models), len(details.Locations), len(details.Questions), len(details.Websites))
+	for _, w := range details.Websites {
+		fmt.Printf("    website: %s\n", w)
+	}
+	planSkipped := services.NewConfigOrgFeatureProvider(cfg).FeaturesForOrg(ctx, details.Org.OrgID).PlanSkipped()
+	if len(planSkipped) > 0 {
+		fmt.Printf("  plan-skipped stages: %s\n", strings.Join(planSkipped, ", "))
+	}
+	fmt.Printf("  persist: %t  spend cap: $%.2f\n\n", !*noPersist, *maxSpend)
+
+	if len(details.Models) == 0 {
+		fail("org has no models configured")
+	}
+	if len(details.Locations) == 0 {
+		fail("org has no locations configured")
+	}
+	if len(details.Questions) == 0 {
+		fail("org has no questions configured")
+	}
+	if len(details.Websites) == 0 {
+		fmt.Printf("  ⚠️ org has no websites; all citations will be classified secondary\n")
+	}
+	if failed {
+		os.Exit(1)
+	}
+
+	var question *models.GeoQuestion
+	var tags []string
+	if *questionID != "" {
+		for i := range details.Questions {
+			if details.Questions[i].Question.GeoQuestionID.String() == *questionID {
+				question = details.Questions[i].Question
+				tags = services.QuestionTags(details.Questions[i])
+				break
+			}
+		}
+		if question == nil {
+			log.Fatalf("question %s not found for org %s", *questionID, *orgID)
+		}
+	} else {
+		question = details.Questions[0].Question
+		tags = services.QuestionTags(details.Questions[0])
+	}
+	location := details.Locations[0]
+
+	fmt.Printf("Question: %s\n  %s\n", question.GeoQuestionID, question.QuestionText)
+	fmt.Printf("Location: %s %s\n\n", location.CountryCode, regionString(location.RegionName))
+
+	var totalCost float64
+	for _, model := range details.Models {
+		fmt.Printf("=== Model: %s ===\n", model.Name)
+		if totalCost >= *maxSpend {
+			fail("skipped: spend cap $%.2f reached ($%.4f spent)", *maxSpend, totalCost)
+			continue
+		}
+
+		extractor.reset()
+		start := time.Now()
+		result, err := runner.ProcessSingleQuestion(ctx, question, tags, model, location, details.TargetCompany, details.Websites)
+		if err != nil {
+			fail("provider/pipeline failed after %v: %v", time.Since(start).Round(time.Millisecond), err)
+			continue
+		}
+		run := result.Run
+
+		// Rows of one extraction call all carry the call's cost; the yield counts it once
+		runCost := floatOrZero(run.TotalCost) + result.Yield.ExtractionCost
+		totalCost += runCost
+
+		response := ""
+		if run.ResponseText != nil {
+			response = *run.ResponseText
+		}
+		fmt.Printf("  run: %s (%v)\n", run.QuestionRunID, time.Since(start).Round(time.Millisecond))
+		fmt.Printf("  response: %s\n", snippet(response, 240))
+
+		for _, stage := range []string{"mentions", "claims", "citations", "metrics"} {
+			if err := extractor.stageErrs[stage]; err != nil {
+				fail("%s extraction failed: %v", stage, err)
+			}
+		}
+
+		fmt.Printf("  mentions: %d\n", len(extractor.mentions))
+		for _, m := range extractor.mentions {
+			marker := " "
+			if m.TargetOrg {
+				marker = "*"
+			}
+			fmt.Printf("    %s %s\n", marker, m.MentionOrg)
+		}
+		if run.TargetSOV != nil {
+			fmt.Printf("  share of voice: %.1f%%  target mentioned: %t\n", *run.TargetSOV, run.TargetMentioned)
+		} else {
+			fmt.Printf("  share of voice: n/a  target mentioned: %t\n", run.TargetMentioned)
+		}
+		fmt.Printf("  claims: %d\n", len(extractor.claims))
+
+		primary, secondary := 0, 0
+		for _, c := range extractor.citations {
+			if c.CitationType == "primary" {
+				primary++
+			} else {
+				secondary++
+			}
+		}
+		if slices.Contains(planSkipped, services.FeatureCitations) {
+			fmt.Printf("  citations: skipped (plan)\n")
+		} else {
+			fmt.Printf("  citations: %d (primary=%d secondary=%d)\n", len(extractor.citations), primary, secondary)
+		}
+		for _, c := range extractor.citations {
+			if c.SourceURL != nil {
+				fmt.Printf("    [%s] %s\n", c.CitationType, *c.SourceURL)
+			}
+		}
+		fmt.Printf("  cost: $%.4f\n\n", runCost)
+	}
+
+	fmt.Printf("Total cost: $%.4f\n", totalCost)
+	if failed {
+		fmt.Printf("Result: FAILED\n")
+		os.Exit(1)
+	}
+	fmt.Printf("Result: OK\n")
+}
+
+func regionString(region *string) string {
+	if region == nil {
+		return ""
+	}
+	return *region
+}
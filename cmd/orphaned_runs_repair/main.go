@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+// scopeSummary counts the orphaned runs of one network or org by outcome.
+type scopeSummary struct {
+	scope    string
+	scopeID  string
+	days     int
+	runs     int
+	repaired int
+	failed   int
+	actions  map[string]int
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Question runs whose batch_id points at a deleted batch break batch-scoped
+// queries. By default the tool only prints the plan; --apply reattaches each
+// run to a same-day batch of its network/org (creating a completed "repair"
+// batch when there is none), or clears batch_id with --detach.
+func run() int {
+	var (
+		apply   = flag.Bool("apply", false, "write the changes (default is a dry run that only prints the plan)")
+		detach  = flag.Bool("detach", false, "clear batch_id on orphaned runs instead of reattaching them")
+		timeout = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[orphaned_runs_repair] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	log.Printf("[orphaned_runs_repair] start apply=%t detach=%t", *apply, *detach)
+
+	results, err := services.RepairOrphanedRuns(ctx, services.NewOrphanedRunRepo(dbClient), repos.QuestionRunBatchRepo,
+		services.OrphanRepairOptions{Detach: *detach, Apply: *apply})
+	if err != nil {
+		log.Printf("[orphaned_runs_repair] %v", err)
+		return fixer.ExitFatal
+	}
+
+	summaries := make(map[string]*scopeSummary)
+	totalRuns, totalFailed := 0, 0
+	for _, r := range results {
+		g := r.Group
+		scope := g.Scope
+		if scope == "" {
+			scope = "unscoped"
+		}
+		key := scope + "/" + g.ScopeID.String()
+		sum, ok := summaries[key]
+		if !ok {
+			sum = &scopeSummary{scope: scope, scopeID: g.ScopeID.String(), actions: make(map[string]int)}
+			summaries[key] = sum
+		}
+		sum.days++
+		sum.runs += len(g.Runs)
+		totalRuns += len(g.Runs)
+
+		batch := "-"
+		if r.BatchID != nil {
+			batch = r.BatchID.String()
+		}
+		if r.Err != nil {
+			sum.failed += len(g.Runs)
+			totalFailed += len(g.Runs)
+			log.Printf("[orphaned_runs_repair] %s=%s day=%s runs=%d action=%s ERROR %v",
+				scope, g.ScopeID, g.Day.Format("2006-01-02"), len(g.Runs), r.Action, r.Err)
+			continue
+		}
+		sum.actions[r.Action] += len(g.Runs)
+		sum.repaired += len(g.Runs)
+		log.Printf("[orphaned_runs_repair] %s=%s day=%s runs=%d action=%s batch=%s",
+			scope, g.ScopeID, g.Day.Format("2006-01-02"), len(g.Runs), r.Action, batch)
+	}
+
+	keys := make([]string, 0, len(summaries))
+	for k := range summaries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	verb := "would_repair"
+	if *apply {
+		verb = "repaired"
+	}
+	for _, k := range keys {
+		sum := summaries[k]
+		log.Printf("[orphaned_runs_repair] summary %s=%s days=%d runs=%d %s=%d failed=%d reattach=%d create=%d detach=%d",
+			sum.scope, sum.scopeID, sum.days, sum.runs, verb, sum.repaired, sum.failed,
+			sum.actions[services.OrphanActionReattach], sum.actions[services.OrphanActionCreate], sum.actions[services.OrphanActionDetach])
+	}
+
+	code := fixer.FailurePolicy{FailOnAny: true}.ExitCode(totalFailed, totalRuns)
+	log.Printf("[orphaned_runs_repair] done groups=%d runs=%d failed=%d apply=%t exit=%d", len(results), totalRuns, totalFailed, *apply, code)
+	if !*apply {
+		log.Printf("[orphaned_runs_repair] dry run: re-run with --apply to write changes")
+	}
+	return code
+}
@@ -1,14 +1,10 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +18,8 @@ import (
 	"github.com/AI-Template-SDK/senso-api/pkg/database"
 	"github.com/AI-Template-SDK/senso-api/pkg/models"
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
 )
@@ -49,140 +47,6 @@ func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*data
 	return &database.Client{DB: db}, nil
 }
 
-type perplexityChatRequest struct {
-	Model    string                  `json:"model"`
-	Messages []perplexityChatMessage `json:"messages"`
-	// Leaving out optional params on purpose (single-use fixer).
-}
-
-type perplexityChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type perplexityChatResponse struct {
-	Model string `json:"model"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-		Cost             struct {
-			// Per the example payload.
-			TotalCost float64 `json:"total_cost"`
-		} `json:"cost"`
-	} `json:"usage"`
-	Citations []string `json:"citations"`
-	Choices   []struct {
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-}
-
-type perplexityClient struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	model      string
-}
-
-func newPerplexityClientFromEnv() (*perplexityClient, error) {
-	apiKey := strings.TrimSpace(os.Getenv("PERPLEXITY_API_KEY"))
-	if apiKey == "" {
-		return nil, fmt.Errorf("PERPLEXITY_API_KEY is not set")
-	}
-	baseURL := strings.TrimSpace(os.Getenv("PERPLEXITY_BASE_URL"))
-	if baseURL == "" {
-		baseURL = "https://api.perplexity.ai"
-	}
-	model := strings.TrimSpace(os.Getenv("PERPLEXITY_CHAT_MODEL"))
-	if model == "" {
-		model = "sonar"
-	}
-
-	return &perplexityClient{
-		apiKey:  apiKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
-		model:   model,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
-	}, nil
-}
-
-func (c *perplexityClient) chatCompletion(ctx context.Context, prompt string) (*perplexityChatResponse, error) {
-	reqBody := perplexityChatRequest{
-		Model: c.model,
-		Messages: []perplexityChatMessage{
-			{Role: "user", Content: prompt},
-		},
-	}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	url := c.baseURL + "/chat/completions"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var b bytes.Buffer
-		_, _ = b.ReadFrom(resp.Body)
-		return nil, fmt.Errorf("perplexity http %d: %s", resp.StatusCode, strings.TrimSpace(b.String()))
-	}
-
-	var out perplexityChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-	return &out, nil
-}
-
-func readOrgIDs(path string) ([]string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var out []string
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		out = append(out, line)
-	}
-	if err := sc.Err(); err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func isPerplexityModelName(name string) bool {
-	return strings.Contains(strings.ToLower(name), "perplexity")
-}
-
-func utcTodayStart(now time.Time) time.Time {
-	t := now.UTC()
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-}
-
 // Mirrors services.formatLocationForPrompt (unexported) used by providers for localization instructions.
 func formatLocationForPrompt(country string, region *string) string {
 	if strings.TrimSpace(country) == "" && (region == nil || strings.TrimSpace(*region) == "") {
@@ -207,25 +71,34 @@ func buildLocalizedPrompt(query string, country string, region *string) string {
 	return fmt.Sprintf("Ensure your response is localized to %s. Answer the following question: %s", locationDescription, query)
 }
 
+// retryBackoff is the base delay between retries of a failed provider call (scaled by attempt).
+const retryBackoff = 2 * time.Second
+
 type runJob struct {
 	orgID   string
 	qID     uuid.UUID
 	qText   string
+	tags    []string
 	model   *models.GeoModel
 	loc     *models.OrgLocation
 	batchID uuid.UUID
 }
 
 type runJobResult struct {
-	job     runJob
-	created bool
-	skipped bool
-	failed  bool
-	err     error
-	cost    float64
+	job       runJob
+	created   bool
+	truncated bool // stream hit its deadline; the partial response was stored
+	skipped   bool
+	failed    bool
+	err       error
+	cost      float64
 }
 
-func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, todayStart time.Time) (*models.QuestionRunBatch, error) {
+// findTodaysOrgBatch returns the org's newest batch created since todayStart.
+// With includeCompleted a completed batch is eligible too, so backfill runs
+// get attached to (and mixed into) a batch that already finished; without it
+// only unfinished batches are, and the caller creates a fresh one otherwise.
+func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, todayStart time.Time, includeCompleted bool) (*models.QuestionRunBatch, error) {
 	batches, err := repos.QuestionRunBatchRepo.GetByOrg(ctx, orgUUID)
 	if err != nil {
 		return nil, err
@@ -238,6 +111,9 @@ func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager,
 		if b.CreatedAt.Before(todayStart) {
 			continue
 		}
+		if !includeCompleted && b.Status == "completed" {
+			continue
+		}
 		if newestToday == nil || b.CreatedAt.After(newestToday.CreatedAt) {
 			newestToday = b
 		}
@@ -245,13 +121,13 @@ func findTodaysOrgBatch(ctx context.Context, repos *services.RepositoryManager,
 	return newestToday, nil
 }
 
-func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, error) {
+func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgUUID uuid.UUID, totalQuestions int, batchType string) (*models.QuestionRunBatch, error) {
 	now := time.Now()
 	batch := &models.QuestionRunBatch{
 		BatchID:            uuid.New(),
 		Scope:              "org",
 		OrgID:              &orgUUID,
-		BatchType:          "perplexity_fixer",
+		BatchType:          batchType,
 		Status:             "running",
 		TotalQuestions:     totalQuestions,
 		CompletedQuestions: 0,
@@ -261,27 +137,74 @@ func createOrgBatch(ctx context.Context, repos *services.RepositoryManager, orgU
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
-	if err := repos.QuestionRunBatchRepo.Create(ctx, batch); err != nil {
+	// Clear is_latest on the previous batch for this scope and create this one, atomically.
+	if err := services.CreateLatestBatch(ctx, repos, batch); err != nil {
 		return nil, err
 	}
 	return batch, nil
 }
 
+// completeOrgBatch records the final counts on a batch this run created and marks it completed.
+func completeOrgBatch(ctx context.Context, repos *services.RepositoryManager, b *models.QuestionRunBatch, completed, failed int) error {
+	if drift := services.ReconcileBatchCounts(b, completed, failed, 0); drift != 0 {
+		log.Printf("[perplexity_fixer] WARN batch=%s reconciliation: total=%d completed=%d failed=%d (drift=%d)", b.BatchID, b.TotalQuestions, completed, failed, drift)
+	}
+	now := time.Now()
+	b.Status = "completed"
+	b.CompletedAt = &now
+	b.UpdatedAt = now
+	return repos.QuestionRunBatchRepo.Update(ctx, b)
+}
+
 func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+func run() int {
 	var (
-		orgFile     = flag.String("org-file", filepath.Join(".", "example_orgs.txt"), "path to file containing org UUIDs (one per line)")
-		dryRun      = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
-		concurrency = flag.Int("concurrency", 5, "number of concurrent Perplexity calls/inserts per org (bounded)")
-		maxOrgs     = flag.Int("max-orgs", 0, "optional max orgs to process (0 = all)")
-		timeout     = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+		orgFile       = flag.String("org-file", filepath.Join(".", "example_orgs.txt"), "path to file containing org UUIDs (one per line)")
+		dryRun        = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
+		concurrency   = flag.Int("concurrency", 5, "number of concurrent Perplexity calls/inserts per org (bounded)")
+		maxOrgs       = flag.Int("max-orgs", 0, "optional max orgs to process (0 = all)")
+		maxQuestions  = flag.Int("max-questions", 0, "testing only: consider at most this many questions per org (0 = all), e.g. for smoke tests against production data")
+		timeout       = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+		retries       = flag.Int("retries", 2, "max retries per provider call (0 = no retries)")
+		retryBudget   = flag.Int("retry-budget", 20, "max total retries shared across all jobs of one org; once spent, failures are not retried")
+		planOut       = flag.String("plan-out", "", "dry-run only: write every planned job plus skip counts to this path (.csv or .json)")
+		idsFormat     = flag.String("ids-format", fixer.IDFormatAuto, "format of the org id file: auto (detect per line), plain (one id per line) or jsonl ({\"id\": ...} per line)")
+		batchType     = flag.String("batch-type", "perplexity_fixer", "question_run_batches.batch_type to tag created batches with (e.g. to distinguish variant backfills)")
+		failOnAny     = flag.Bool("fail-on-any", true, "exit 2 if any job failed or was left unrun (see --fail-threshold to tolerate some)")
+		failThreshold = flag.Float64("fail-threshold", 0, "with --fail-on-any=false: exit 2 only when the failed fraction of jobs exceeds this (0-1)")
+		shuffle       = flag.Bool("shuffle", false, "randomize the job order of each org before dispatch, so reruns don't always hit the same questions first")
+		shuffleSeed   = flag.Int64("shuffle-seed", 0, "with --shuffle: seed for the job order (0 = from the clock); the seed is logged so a run can be reproduced")
+		heartbeat     = flag.Duration("heartbeat", 30*time.Second, "log an in-progress line (created/failed/remaining/cost) per org at this interval while its jobs run (0 disables)")
+		countPlanned  = flag.Bool("count-planned", false, "when resuming today's existing batch, reset its total_questions to the runs already attached plus the jobs planned now (new batches are always sized by planned jobs); not for batches a pipeline is still filling")
+		withCompleted = flag.Bool("include-completed-batches", true, "attach runs to today's newest batch even if it is completed (the default, for compatibility), which mixes backfill runs into a finished batch; set false to create a fresh batch instead")
 	)
+	perplexityAliases := append(fixer.ModelAliases(nil), fixer.DefaultPerplexityAliases...)
+	flag.Var(&perplexityAliases, "perplexity-aliases", "comma-separated model name fragments that mark a configured model as Perplexity, matched case-insensitively as whole words (e.g. pplx-sonar)")
 	flag.Parse()
 
+	var plan *fixer.Plan
+	if *dryRun && *planOut != "" {
+		plan = fixer.NewPlan()
+	}
+
+	var shuffler *fixer.Shuffler
+	if *shuffle {
+		shuffler = fixer.NewShuffler(*shuffleSeed)
+	}
+
 	// Load env vars like the main service (but this tool is intentionally standalone).
 	if err := godotenv.Load(); err != nil {
 		_ = godotenv.Load("dev.env")
 	}
 	cfg := config.Load()
+	// Write models no provider recognizes are refused; the providers config can route more
+	if _, err := services.LoadProvidersConfig(cfg.ProvidersConfigFile); err != nil {
+		log.Fatalf("Failed to load providers config: %v", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
@@ -295,16 +218,16 @@ func main() {
 	repos := services.NewRepositoryManager(dbClient)
 	orgService := services.NewOrgService(cfg, repos)
 
-	var pplx *perplexityClient
+	var pplx *fixer.PerplexityClient
 	if !*dryRun {
-		pplxClient, err := newPerplexityClientFromEnv()
+		pplxClient, err := fixer.NewPerplexityClientFromEnv()
 		if err != nil {
 			log.Fatalf("Perplexity client init failed: %v", err)
 		}
 		pplx = pplxClient
 	}
 
-	orgIDs, err := readOrgIDs(*orgFile)
+	orgIDs, err := fixer.ReadIDs(*orgFile, *idsFormat)
 	if err != nil {
 		log.Fatalf("Failed reading org list: %v", err)
 	}
@@ -315,70 +238,93 @@ func main() {
 	if *concurrency < 1 {
 		log.Fatalf("--concurrency must be >= 1")
 	}
+	if *maxQuestions < 0 {
+		log.Fatalf("--max-questions must be >= 0")
+	}
+	if strings.TrimSpace(*batchType) == "" {
+		log.Fatalf("--batch-type must not be empty")
+	}
+	if *failThreshold < 0 || *failThreshold > 1 {
+		log.Fatalf("--fail-threshold must be between 0 and 1")
+	}
 
 	modelName := ""
 	baseURL := ""
 	if pplx != nil {
-		modelName = pplx.model
-		baseURL = pplx.baseURL
+		modelName = pplx.Model
+		baseURL = pplx.BaseURL
 	}
 	log.Printf("[perplexity_fixer] orgs=%d dry_run=%t concurrency=%d model=%s base_url=%s", len(orgIDs), *dryRun, *concurrency, modelName, baseURL)
+	if shuffler != nil {
+		log.Printf("[perplexity_fixer] shuffling job order with seed=%d (rerun with --shuffle --shuffle-seed=%d for the same order)", shuffler.Seed, shuffler.Seed)
+	}
 	if *dryRun {
 		log.Printf("[perplexity_fixer] DRY RUN MODE: no DB writes, no Perplexity calls will be made")
-		log.Printf("[perplexity_fixer] To execute for real: PERPLEXITY_API_KEY=... go run ./cmd/perplexity_fixer --dry-run=false --concurrency %d", *concurrency)
+		log.Printf("[perplexity_fixer] To execute for real: PERPLEXITY_API_KEYS=key1,key2 (or PERPLEXITY_API_KEY=...) go run ./cmd/perplexity_fixer --dry-run=false --concurrency %d", *concurrency)
 	}
-	todayStart := utcTodayStart(time.Now())
-	log.Printf("[perplexity_fixer] todayStart(UTC)=%s", todayStart.Format(time.RFC3339))
+	// Each org/network's "today" starts at midnight in its batch day time zone
+	// (BATCH_DAY_TIMEZONES, default UTC), evaluated at this start time.
+	runStart := time.Now()
+	// Totals across all orgs for the exit code; jobs left unrun by the timeout count as failed.
+	totalJobs, totalFailed := 0, 0
 
-	for idx, orgID := range orgIDs {
+	unprocessed := fixer.EachScope(ctx, orgIDs, func(idx int, orgID string) {
 		log.Printf("[perplexity_fixer] (%d/%d) org=%s", idx+1, len(orgIDs), orgID)
 
 		orgDetails, err := orgService.GetOrgDetails(ctx, orgID)
 		if err != nil {
 			log.Printf("[perplexity_fixer] org=%s ERROR get details: %v", orgID, err)
-			continue
+			return
+		}
+		// GetOrgDetails already left these out of orgDetails.Locations
+		for _, invalid := range orgDetails.InvalidLocations {
+			log.Printf("[perplexity_fixer] org=%s WARN %s", orgID, invalid.Message())
+			plan.Skip("scope_invalid_location", 1)
+		}
+		if capped := fixer.CapQuestions(orgDetails.Questions, *maxQuestions); len(capped) < len(orgDetails.Questions) {
+			log.Printf("[perplexity_fixer] org=%s considering %d/%d questions (--max-questions)", orgID, len(capped), len(orgDetails.Questions))
+			orgDetails.Questions = capped
 		}
 
 		perplexityModels := make([]*models.GeoModel, 0)
+		var otherModelNames []string
 		for _, m := range orgDetails.Models {
-			if isPerplexityModelName(m.Name) {
+			switch {
+			case !perplexityAliases.Matches(m.Name):
+				otherModelNames = append(otherModelNames, m.Name)
+			case !services.IsSupportedModel(cfg, m.Name):
+				log.Printf("[perplexity_fixer] org=%s WARN refusing unconfigured model %q (no provider recognizes it)", orgID, m.Name)
+				plan.Skip("scope_unconfigured_model", 1)
+			default:
 				perplexityModels = append(perplexityModels, m)
 			}
 		}
+		if len(otherModelNames) > 0 {
+			log.Printf("[perplexity_fixer] org=%s skipping non-perplexity models %v (--perplexity-aliases=%s)", orgID, otherModelNames, perplexityAliases.String())
+		}
 		if len(perplexityModels) == 0 {
 			log.Printf("[perplexity_fixer] org=%s skip (no perplexity model configured)", orgID)
-			continue
+			plan.Skip("scope_no_matching_model", 1)
+			return
 		}
 
 		orgUUID, err := uuid.Parse(orgID)
 		if err != nil {
 			log.Printf("[perplexity_fixer] org=%s invalid uuid: %v", orgID, err)
-			continue
+			return
 		}
+		dayLoc := services.BatchDayLocation(cfg, orgUUID)
+		todayStart := services.DayStart(runStart, dayLoc)
+		log.Printf("[perplexity_fixer] org=%s todayStart=%s (tz=%s)", orgID, todayStart.Format(time.RFC3339), dayLoc)
 
 		// Attach runs to today's org batch (create if missing; but NEVER create in dry-run).
-		// Note: we only run Perplexity, so totalQuestions here is Perplexity-scoped.
-		totalQuestions := len(orgDetails.Questions) * len(perplexityModels) * len(orgDetails.Locations)
-		batch, err := findTodaysOrgBatch(ctx, repos, orgUUID, todayStart)
+		batch, err := findTodaysOrgBatch(ctx, repos, orgUUID, todayStart, *withCompleted)
 		if err != nil {
 			log.Printf("[perplexity_fixer] org=%s ERROR finding today's batch: %v", orgID, err)
-			continue
+			return
 		}
 
 		isExisting := batch != nil
-		if !isExisting {
-			if *dryRun {
-				log.Printf("[perplexity_fixer] org=%s DRY RUN would create today's batch (type=perplexity_fixer total_questions=%d)", orgID, totalQuestions)
-			} else {
-				createdBatch, err := createOrgBatch(ctx, repos, orgUUID, totalQuestions)
-				if err != nil {
-					log.Printf("[perplexity_fixer] org=%s ERROR creating today's batch: %v", orgID, err)
-					continue
-				}
-				batch = createdBatch
-			}
-		}
-
 		batchIDForRuns := uuid.Nil
 		batchStatus := ""
 		if batch != nil {
@@ -388,7 +334,8 @@ func main() {
 		log.Printf("[perplexity_fixer] org=%s batch=%s (existing=%t status=%s)", orgID, batchIDForRuns, isExisting, batchStatus)
 
 		createdCount := 0
-		skippedExisting := 0
+		truncatedCount := 0
+		skips := fixer.SkipCounts{}
 		failedJobs := 0
 
 		// Build the full missing-job list first, then execute with a bounded worker pool.
@@ -401,18 +348,28 @@ func main() {
 				for _, qwt := range orgDetails.Questions {
 					q := qwt.Question
 
+					// Send the normalized text; the stored question is left untouched.
+					qText, err := services.NormalizeQuestionText(q.QuestionText, &workflowModels.Location{Country: loc.CountryCode, Region: loc.RegionName})
+					if err != nil {
+						log.Printf("[perplexity_fixer] org=%s skip question=%s: %v", orgID, q.GeoQuestionID, err)
+						skips.Add(fixer.SkipInvalidQuestion, 1)
+						continue
+					}
+
 					runs, err := repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
 					if err != nil {
 						// Be conservative: schedule the run if we can't verify existence.
 						key := fmt.Sprintf("%s|%s|%s", q.GeoQuestionID, model.GeoModelID, loc.OrgLocationID)
 						if _, ok := seen[key]; ok {
+							skips.Add(fixer.SkipDuplicateJob, 1)
 							continue
 						}
 						seen[key] = struct{}{}
 						jobs = append(jobs, runJob{
 							orgID:   orgID,
 							qID:     q.GeoQuestionID,
-							qText:   q.QuestionText,
+							qText:   qText,
+							tags:    services.QuestionTags(qwt),
 							model:   model,
 							loc:     loc,
 							batchID: batchIDForRuns,
@@ -435,12 +392,13 @@ func main() {
 					}
 
 					if found {
-						skippedExisting++
+						skips.Add(fixer.SkipExistingRun, 1)
 						continue
 					}
 
 					key := fmt.Sprintf("%s|%s|%s", q.GeoQuestionID, model.GeoModelID, loc.OrgLocationID)
 					if _, ok := seen[key]; ok {
+						skips.Add(fixer.SkipDuplicateJob, 1)
 						continue
 					}
 					seen[key] = struct{}{}
@@ -448,7 +406,8 @@ func main() {
 					jobs = append(jobs, runJob{
 						orgID:   orgID,
 						qID:     q.GeoQuestionID,
-						qText:   q.QuestionText,
+						qText:   qText,
+						tags:    services.QuestionTags(qwt),
 						model:   model,
 						loc:     loc,
 						batchID: batchIDForRuns,
@@ -457,12 +416,57 @@ func main() {
 			}
 		}
 
+		plan.SkipAll(skips)
+		for _, j := range jobs {
+			region := ""
+			if j.loc.RegionName != nil {
+				region = *j.loc.RegionName
+			}
+			plan.Add(fixer.PlanEntry{Scope: "org", ScopeID: orgID, QuestionID: j.qID.String(), Model: j.model.Name, Country: j.loc.CountryCode, Region: region})
+		}
+
 		if len(jobs) == 0 {
-			log.Printf("[perplexity_fixer] org=%s done (no missing runs) skipped_existing=%d", orgID, skippedExisting)
-			continue
+			log.Printf("[perplexity_fixer] org=%s done (no missing runs) skipped=%s", orgID, skips)
+			return
+		}
+
+		// A new batch is sized by the jobs actually planned rather than questions ×
+		// models × locations, so runs that already exist never count against it;
+		// --count-planned re-plans a resumed batch the same way.
+		if !isExisting {
+			if *dryRun {
+				log.Printf("[perplexity_fixer] org=%s DRY RUN would create today's batch (type=perplexity_fixer total_questions=%d)", orgID, len(jobs))
+			} else {
+				createdBatch, err := createOrgBatch(ctx, repos, orgUUID, len(jobs), *batchType)
+				if err != nil {
+					log.Printf("[perplexity_fixer] org=%s ERROR creating today's batch: %v", orgID, err)
+					return
+				}
+				batch = createdBatch
+				for i := range jobs {
+					jobs[i].batchID = batch.BatchID
+				}
+				log.Printf("[perplexity_fixer] org=%s created batch=%s total_questions=%d day_tz=%s", orgID, batch.BatchID, len(jobs), dayLoc)
+			}
+		} else if *countPlanned {
+			if *dryRun {
+				log.Printf("[perplexity_fixer] org=%s DRY RUN would re-plan batch=%s total_questions=%d + attached runs", orgID, batch.BatchID, len(jobs))
+			} else {
+				total, err := services.PlanBatchTotal(ctx, repos, batch, len(jobs))
+				if err != nil {
+					log.Printf("[perplexity_fixer] org=%s WARN re-planning batch=%s: %v", orgID, batch.BatchID, err)
+				} else {
+					log.Printf("[perplexity_fixer] org=%s re-planned batch=%s total_questions=%d", orgID, batch.BatchID, total)
+				}
+			}
 		}
 
-		log.Printf("[perplexity_fixer] org=%s missing_jobs=%d skipped_existing=%d (executing with concurrency=%d)", orgID, len(jobs), skippedExisting, *concurrency)
+		fixer.Shuffle(shuffler, jobs)
+
+		log.Printf("[perplexity_fixer] org=%s missing_jobs=%d skipped=%s (executing with concurrency=%d)", orgID, len(jobs), skips, *concurrency)
+
+		// Retries for every job of this org/network draw from one shared budget.
+		budget := fixer.NewRetryBudget(*retryBudget)
 
 		jobsCh := make(chan runJob)
 		resultsCh := make(chan runJobResult, len(jobs))
@@ -471,6 +475,10 @@ func main() {
 		worker := func() {
 			defer wg.Done()
 			for job := range jobsCh {
+				if ctx.Err() != nil {
+					// Overall timeout hit: stop picking up work instead of failing every remaining job.
+					return
+				}
 				if *dryRun {
 					resultsCh <- runJobResult{
 						job:     job,
@@ -486,7 +494,12 @@ func main() {
 				}
 
 				prompt := buildLocalizedPrompt(job.qText, job.loc.CountryCode, job.loc.RegionName)
-				resp, err := pplx.chatCompletion(ctx, prompt)
+				var resp *fixer.PerplexityChatResponse
+				err := fixer.Do(ctx, *retries, retryBackoff, budget, func(ctx context.Context) error {
+					var callErr error
+					resp, callErr = pplx.ChatCompletion(ctx, prompt)
+					return callErr
+				})
 				if err != nil {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
@@ -497,6 +510,10 @@ func main() {
 				}
 
 				content := resp.Choices[0].Message.Content
+				if err := fixer.CheckResponseText(content, cfg.MinExtractionResponseChars); err != nil {
+					resultsCh <- runJobResult{job: job, failed: true, err: err}
+					continue
+				}
 				inputTokens := resp.Usage.PromptTokens
 				outputTokens := resp.Usage.CompletionTokens
 				totalCost := resp.Usage.Cost.TotalCost
@@ -534,8 +551,20 @@ func main() {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
+				if err := services.SetQuestionRunTags(ctx, repos, qr.QuestionRunID, job.tags); err != nil {
+					log.Printf("[perplexity_fixer] org=%s WARN storing tags of run %s: %v", job.orgID, qr.QuestionRunID, err)
+				}
+				// Partial responses are kept for backfills, but marked so they can be told apart.
+				if resp.Truncated {
+					if resp.LengthLimited() {
+						log.Printf("[perplexity_fixer] org=%s run %s hit the output token limit (model=%s max_tokens=%d)", job.orgID, qr.QuestionRunID, pplx.Model, pplx.MaxTokens)
+					}
+					if err := services.MarkQuestionRunTruncated(ctx, repos, qr.QuestionRunID, outputTokens); err != nil {
+						log.Printf("[perplexity_fixer] org=%s WARN marking run %s truncated: %v", job.orgID, qr.QuestionRunID, err)
+					}
+				}
 
-				resultsCh <- runJobResult{job: job, created: true, cost: totalCost}
+				resultsCh <- runJobResult{job: job, created: true, truncated: resp.Truncated, cost: totalCost}
 			}
 		}
 
@@ -545,10 +574,14 @@ func main() {
 		}
 
 		go func() {
+			defer close(jobsCh)
 			for _, j := range jobs {
-				jobsCh <- j
+				select {
+				case <-ctx.Done():
+					return
+				case jobsCh <- j:
+				}
 			}
-			close(jobsCh)
 		}()
 
 		go func() {
@@ -556,25 +589,64 @@ func main() {
 			close(resultsCh)
 		}()
 
+		progress := fixer.NewProgress(len(jobs))
+		stopHeartbeat := fixer.StartHeartbeat(*heartbeat, progress, func(line string) {
+			log.Printf("[perplexity_fixer] org=%s %s", orgID, line)
+		})
 		var totalCost float64
 		for res := range resultsCh {
 			if res.failed {
 				failedJobs++
+				progress.Failed()
 				log.Printf("[perplexity_fixer] org=%s ERROR job question=%s model=%s location=%s: %v",
 					orgID, res.job.qID, res.job.model.Name, res.job.loc.CountryCode, res.err)
 				continue
 			}
 			if res.created {
 				createdCount++
+				progress.Created(res.cost)
+				if res.truncated {
+					truncatedCount++
+				}
 				totalCost += res.cost
 				if *dryRun {
 					log.Printf("[perplexity_fixer] DRY RUN would insert run question=%s model=%s location=%s", res.job.qID, res.job.model.Name, res.job.loc.CountryCode)
 				}
 			}
 		}
+		stopHeartbeat()
+
+		totalJobs += len(jobs)
+		totalFailed += len(jobs) - createdCount
+		if !isExisting && batch != nil {
+			// Jobs left unrun by the timeout count as failed here too; the batch is
+			// still closed out after the overall timeout has cancelled ctx.
+			if err := completeOrgBatch(context.WithoutCancel(ctx), repos, batch, createdCount, len(jobs)-createdCount); err != nil {
+				log.Printf("[perplexity_fixer] org=%s WARN completing batch=%s: %v", orgID, batch.BatchID, err)
+			}
+		}
 
-		log.Printf("[perplexity_fixer] org=%s done created=%d skipped_existing=%d failed=%d total_cost=%.6f", orgID, createdCount, skippedExisting, failedJobs, totalCost)
+		if ctx.Err() != nil {
+			log.Printf("[perplexity_fixer] org=%s interrupted by timeout: jobs_not_run=%d", orgID, len(jobs)-createdCount-failedJobs)
+		}
+		log.Printf("[perplexity_fixer] org=%s done created=%d truncated=%d skipped=%s failed=%d retries_used=%d total_cost=%.6f", orgID, createdCount, truncatedCount, skips, failedJobs, budget.Used(), totalCost)
+	})
+	if unprocessed > 0 {
+		log.Printf("[perplexity_fixer] timeout reached, %d orgs unprocessed: %v", unprocessed, ctx.Err())
 	}
 
-	log.Printf("[perplexity_fixer] done")
+	if plan != nil {
+		if err := plan.WriteFile(*planOut); err != nil {
+			log.Printf("[perplexity_fixer] ERROR writing plan: %v", err)
+		} else {
+			log.Printf("[perplexity_fixer] plan written to %s (jobs=%d)", *planOut, len(plan.Entries))
+		}
+	}
+	policy := fixer.FailurePolicy{FailOnAny: *failOnAny, Threshold: *failThreshold}
+	code := policy.ExitCode(totalFailed, totalJobs)
+	if pplx != nil {
+		log.Printf("[perplexity_fixer] perplexity key usage: %s", pplx.KeyUsage())
+	}
+	log.Printf("[perplexity_fixer] done jobs=%d failed=%d exit=%d", totalJobs, totalFailed, code)
+	return code
 }
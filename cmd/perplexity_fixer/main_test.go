@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/sqltest"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+)
+
+func TestCreateOrgBatchBatchType(t *testing.T) {
+	for _, batchType := range []string{"perplexity_fixer", "perplexity_fixer_rebackfill"} {
+		t.Run(batchType, func(t *testing.T) {
+			rec := &sqltest.Recorder{Exec: func(query string, args []any) (int64, error) { return 1, nil }}
+			repos := services.NewRepositoryManager(sqltest.NewClient(t, rec))
+
+			batch, err := createOrgBatch(context.Background(), repos, uuid.New(), 12, batchType)
+			if err != nil {
+				t.Fatalf("createOrgBatch() error = %v", err)
+			}
+			if batch.BatchType != batchType || batch.Scope != "org" || batch.TotalQuestions != 12 {
+				t.Errorf("batch = %+v, want a org batch of 12 questions typed %q", batch, batchType)
+			}
+			var inserted []any
+			for _, stmt := range rec.Statements() {
+				if strings.HasPrefix(stmt.Query, "INSERT INTO question_run_batches") {
+					inserted = stmt.Args
+				}
+			}
+			if len(inserted) < 5 || inserted[4] != batchType {
+				t.Errorf("inserted batch args = %v, want batch_type %q", inserted, batchType)
+			}
+		})
+	}
+}
@@ -1,14 +1,10 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,6 +19,8 @@ import (
 	"github.com/AI-Template-SDK/senso-api/pkg/models"
 	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
 )
@@ -50,137 +48,6 @@ func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*data
 	return &database.Client{DB: db}, nil
 }
 
-type perplexityChatRequest struct {
-	Model    string                  `json:"model"`
-	Messages []perplexityChatMessage `json:"messages"`
-}
-
-type perplexityChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type perplexityChatResponse struct {
-	Model string `json:"model"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-		Cost             struct {
-			TotalCost float64 `json:"total_cost"`
-		} `json:"cost"`
-	} `json:"usage"`
-	Choices []struct {
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-}
-
-type perplexityClient struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	model      string
-}
-
-func newPerplexityClientFromEnv() (*perplexityClient, error) {
-	apiKey := strings.TrimSpace(os.Getenv("PERPLEXITY_API_KEY"))
-	if apiKey == "" {
-		return nil, fmt.Errorf("PERPLEXITY_API_KEY is not set")
-	}
-	baseURL := strings.TrimSpace(os.Getenv("PERPLEXITY_BASE_URL"))
-	if baseURL == "" {
-		baseURL = "https://api.perplexity.ai"
-	}
-	model := strings.TrimSpace(os.Getenv("PERPLEXITY_CHAT_MODEL"))
-	if model == "" {
-		model = "sonar"
-	}
-
-	return &perplexityClient{
-		apiKey:  apiKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
-		model:   model,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
-	}, nil
-}
-
-func (c *perplexityClient) chatCompletion(ctx context.Context, prompt string) (*perplexityChatResponse, error) {
-	reqBody := perplexityChatRequest{
-		Model: c.model,
-		Messages: []perplexityChatMessage{
-			{Role: "user", Content: prompt},
-		},
-	}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	url := c.baseURL + "/chat/completions"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var b bytes.Buffer
-		_, _ = b.ReadFrom(resp.Body)
-		return nil, fmt.Errorf("perplexity http %d: %s", resp.StatusCode, strings.TrimSpace(b.String()))
-	}
-
-	var out perplexityChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-	return &out, nil
-}
-
-func readIDs(path string) ([]string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var out []string
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		out = append(out, line)
-	}
-	if err := sc.Err(); err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func utcTodayStart(now time.Time) time.Time {
-	t := now.UTC()
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-}
-
-func isPerplexityModelName(name string) bool {
-	return strings.Contains(strings.ToLower(name), "perplexity")
-}
-
 // Mirrors provider localization prompt format (region, country).
 func formatLocationForPrompt(country string, region *string) string {
 	if strings.TrimSpace(country) == "" && (region == nil || strings.TrimSpace(*region) == "") {
@@ -243,13 +110,33 @@ func findTodaysNetworkBatch(ctx context.Context, repos *services.RepositoryManag
 	return newest, nil
 }
 
-func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager, networkUUID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, error) {
+// todaysNetworkBatch finds today's batch of a network and reports whether the
+// network has no question runs at all (or assumeEmpty is set). An empty network
+// has no batch today and no runs to skip, so neither per-question scan runs.
+func todaysNetworkBatch(ctx context.Context, db fixer.Getter, repos *services.RepositoryManager, networkID string, networkUUID uuid.UUID, todayStart time.Time, assumeEmpty bool) (*models.QuestionRunBatch, bool, error) {
+	empty := assumeEmpty
+	if !empty {
+		hasRuns, err := fixer.NetworkHasRuns(ctx, db, networkUUID)
+		if err != nil {
+			log.Printf("[perplexity_network_fixer] network=%s WARN empty-network check failed, scanning: %v", networkID, err)
+		}
+		empty = err == nil && !hasRuns
+	}
+	if empty {
+		log.Printf("[perplexity_network_fixer] network=%s has no question runs; skipping batch/run scans", networkID)
+		return nil, true, nil
+	}
+	batch, err := findTodaysNetworkBatch(ctx, repos, networkUUID, todayStart)
+	return batch, false, err
+}
+
+func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager, networkUUID uuid.UUID, totalQuestions int, batchType string) (*models.QuestionRunBatch, error) {
 	now := time.Now()
 	b := &models.QuestionRunBatch{
 		BatchID:            uuid.New(),
 		Scope:              "network",
 		NetworkID:          &networkUUID,
-		BatchType:          "perplexity_network_fixer",
+		BatchType:          batchType,
 		Status:             "running",
 		TotalQuestions:     totalQuestions,
 		CompletedQuestions: 0,
@@ -259,12 +146,25 @@ func createNetworkBatch(ctx context.Context, repos *services.RepositoryManager,
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
-	if err := repos.QuestionRunBatchRepo.Create(ctx, b); err != nil {
+	// Clear is_latest on the previous batch for this scope and create this one, atomically.
+	if err := services.CreateLatestBatch(ctx, repos, b); err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
+// completeNetworkBatch records the final counts on a batch this run created and marks it completed.
+func completeNetworkBatch(ctx context.Context, repos *services.RepositoryManager, b *models.QuestionRunBatch, completed, failed int) error {
+	if drift := services.ReconcileBatchCounts(b, completed, failed, 0); drift != 0 {
+		log.Printf("[perplexity_network_fixer] WARN batch=%s reconciliation: total=%d completed=%d failed=%d (drift=%d)", b.BatchID, b.TotalQuestions, completed, failed, drift)
+	}
+	now := time.Now()
+	b.Status = "completed"
+	b.CompletedAt = &now
+	b.UpdatedAt = now
+	return repos.QuestionRunBatchRepo.Update(ctx, b)
+}
+
 func regionString(region *string) string {
 	if region == nil {
 		return ""
@@ -317,10 +217,14 @@ func loadNetworkQuestionsAndLocations(
 	return questions, locations, nil
 }
 
+// retryBackoff is the base delay between retries of a failed provider call (scaled by attempt).
+const retryBackoff = 2 * time.Second
+
 type runJob struct {
 	networkID string
 	qID       uuid.UUID
 	qText     string
+	tags      []string
 	modelName string
 	country   string
 	region    *string
@@ -328,27 +232,67 @@ type runJob struct {
 }
 
 type runJobResult struct {
-	job     runJob
-	created bool
-	failed  bool
-	err     error
-	cost    float64
+	job       runJob
+	created   bool
+	truncated bool // stream hit its deadline; the partial response was stored
+	failed    bool
+	err       error
+	cost      float64
 }
 
 func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Only question runs are inserted; no org websites are needed here. The
+// follow-up network.org.missing.process evaluation loads each member org's
+// websites itself (GetOrgDetailsForNetworkProcessing) before classifying
+// citations as primary or secondary.
+func run() int {
 	var (
-		networkFile = flag.String("network-file", filepath.Join(".", "example_networks.txt"), "path to file containing network UUIDs (one per line)")
-		dryRun      = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
-		concurrency = flag.Int("concurrency", 5, "number of concurrent Perplexity calls/inserts per network (bounded)")
-		maxNetworks = flag.Int("max-networks", 0, "optional max networks to process (0 = all)")
-		timeout     = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+		networkFile   = flag.String("network-file", filepath.Join(".", "example_networks.txt"), "path to file containing network UUIDs (one per line)")
+		dryRun        = flag.Bool("dry-run", true, "if true, do not write to DB (prints what would happen)")
+		concurrency   = flag.Int("concurrency", 5, "number of concurrent Perplexity calls/inserts per network (bounded)")
+		maxNetworks   = flag.Int("max-networks", 0, "optional max networks to process (0 = all)")
+		maxQuestions  = flag.Int("max-questions", 0, "testing only: consider at most this many questions per network (0 = all), e.g. for smoke tests against production data")
+		timeout       = flag.Duration("timeout", 30*time.Minute, "overall timeout for the script")
+		retries       = flag.Int("retries", 2, "max retries per provider call (0 = no retries)")
+		retryBudget   = flag.Int("retry-budget", 20, "max total retries shared across all jobs of one network; once spent, failures are not retried")
+		planOut       = flag.String("plan-out", "", "dry-run only: write every planned job plus skip counts to this path (.csv or .json)")
+		idsFormat     = flag.String("ids-format", fixer.IDFormatAuto, "format of the network id file: auto (detect per line), plain (one id per line) or jsonl ({\"id\": ...} per line)")
+		assumeEmpty   = flag.Bool("assume-empty", false, "treat every network as having no prior question runs: skip the today's-batch and existing-run scans (normally detected automatically)")
+		batchType     = flag.String("batch-type", "perplexity_network_fixer", "question_run_batches.batch_type to tag created batches with (e.g. to distinguish variant backfills)")
+		failOnAny     = flag.Bool("fail-on-any", true, "exit 2 if any job failed or was left unrun (see --fail-threshold to tolerate some)")
+		failThreshold = flag.Float64("fail-threshold", 0, "with --fail-on-any=false: exit 2 only when the failed fraction of jobs exceeds this (0-1)")
+		shuffle       = flag.Bool("shuffle", false, "randomize the job order of each network before dispatch, so reruns don't always hit the same questions first")
+		shuffleSeed   = flag.Int64("shuffle-seed", 0, "with --shuffle: seed for the job order (0 = from the clock); the seed is logged so a run can be reproduced")
+		heartbeat     = flag.Duration("heartbeat", 30*time.Second, "log an in-progress line (created/failed/remaining/cost) per network at this interval while its jobs run (0 disables)")
+		countPlanned  = flag.Bool("count-planned", false, "when resuming today's existing batch, reset its total_questions to the runs already attached plus the jobs planned now (new batches are always sized by planned jobs); not for batches a pipeline is still filling")
 	)
+	perplexityAliases := append(fixer.ModelAliases(nil), fixer.DefaultPerplexityAliases...)
+	flag.Var(&perplexityAliases, "perplexity-aliases", "comma-separated model name fragments that mark a configured model as Perplexity, matched case-insensitively as whole words (e.g. pplx-sonar)")
 	flag.Parse()
 
+	var plan *fixer.Plan
+	if *dryRun && *planOut != "" {
+		plan = fixer.NewPlan()
+	}
+
+	var shuffler *fixer.Shuffler
+	if *shuffle {
+		shuffler = fixer.NewShuffler(*shuffleSeed)
+	}
+
 	if err := godotenv.Load(); err != nil {
 		_ = godotenv.Load("dev.env")
 	}
 	cfg := config.Load()
+	// Write models no provider recognizes are refused; the providers config can route more
+	if _, err := services.LoadProvidersConfig(cfg.ProvidersConfigFile); err != nil {
+		log.Fatalf("Failed to load providers config: %v", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
@@ -364,17 +308,26 @@ func main() {
 	if *concurrency < 1 {
 		log.Fatalf("--concurrency must be >= 1")
 	}
+	if *maxQuestions < 0 {
+		log.Fatalf("--max-questions must be >= 0")
+	}
+	if strings.TrimSpace(*batchType) == "" {
+		log.Fatalf("--batch-type must not be empty")
+	}
+	if *failThreshold < 0 || *failThreshold > 1 {
+		log.Fatalf("--fail-threshold must be between 0 and 1")
+	}
 
-	var pplx *perplexityClient
+	var pplx *fixer.PerplexityClient
 	if !*dryRun {
-		pplxClient, err := newPerplexityClientFromEnv()
+		pplxClient, err := fixer.NewPerplexityClientFromEnv()
 		if err != nil {
 			log.Fatalf("Perplexity client init failed: %v", err)
 		}
 		pplx = pplxClient
 	}
 
-	networkIDs, err := readIDs(*networkFile)
+	networkIDs, err := fixer.ReadIDs(*networkFile, *idsFormat)
 	if err != nil {
 		log.Fatalf("Failed reading network list: %v", err)
 	}
@@ -385,77 +338,100 @@ func main() {
 	modelName := ""
 	baseURL := ""
 	if pplx != nil {
-		modelName = pplx.model
-		baseURL = pplx.baseURL
+		modelName = pplx.Model
+		baseURL = pplx.BaseURL
 	}
 	log.Printf("[perplexity_network_fixer] networks=%d dry_run=%t concurrency=%d model=%s base_url=%s", len(networkIDs), *dryRun, *concurrency, modelName, baseURL)
+	if shuffler != nil {
+		log.Printf("[perplexity_network_fixer] shuffling job order with seed=%d (rerun with --shuffle --shuffle-seed=%d for the same order)", shuffler.Seed, shuffler.Seed)
+	}
 	if *dryRun {
 		log.Printf("[perplexity_network_fixer] DRY RUN MODE: no DB writes, no Perplexity calls will be made")
-		log.Printf("[perplexity_network_fixer] To execute for real: PERPLEXITY_API_KEY=... go run ./cmd/perplexity_network_fixer --dry-run=false --concurrency %d", *concurrency)
+		log.Printf("[perplexity_network_fixer] To execute for real: PERPLEXITY_API_KEYS=key1,key2 (or PERPLEXITY_API_KEY=...) go run ./cmd/perplexity_network_fixer --dry-run=false --concurrency %d", *concurrency)
 	}
 
-	todayStart := utcTodayStart(time.Now())
-	log.Printf("[perplexity_network_fixer] todayStart(UTC)=%s", todayStart.Format(time.RFC3339))
+	// Each org/network's "today" starts at midnight in its batch day time zone
+	// (BATCH_DAY_TIMEZONES, default UTC), evaluated at this start time.
+	runStart := time.Now()
+	// Totals across all networks for the exit code; jobs left unrun by the timeout count as failed.
+	totalJobs, totalFailed := 0, 0
 
-	for idx, networkID := range networkIDs {
+	unprocessed := fixer.EachScope(ctx, networkIDs, func(idx int, networkID string) {
 		log.Printf("[perplexity_network_fixer] (%d/%d) network=%s", idx+1, len(networkIDs), networkID)
 
 		networkUUID, err := uuid.Parse(networkID)
 		if err != nil {
 			log.Printf("[perplexity_network_fixer] network=%s invalid uuid: %v", networkID, err)
-			continue
+			return
 		}
+		dayLoc := services.BatchDayLocation(cfg, networkUUID)
+		todayStart := services.DayStart(runStart, dayLoc)
+		log.Printf("[perplexity_network_fixer] network=%s todayStart=%s (tz=%s)", networkID, todayStart.Format(time.RFC3339), dayLoc)
 
 		// Determine configured network models (do NOT fallback like the pipeline).
 		modelNames, err := repos.NetworkModelRepo.GetByNetworkID(ctx, networkUUID)
 		if err != nil {
 			log.Printf("[perplexity_network_fixer] network=%s ERROR get network models: %v", networkID, err)
-			continue
+			return
 		}
 		if len(modelNames) == 0 {
 			log.Printf("[perplexity_network_fixer] network=%s skip (no network models configured; not using fallback defaults)", networkID)
-			continue
+			plan.Skip("scope_no_network_models", 1)
+			return
 		}
 
-		perplexityModelNames := make([]string, 0)
-		for _, name := range modelNames {
-			if isPerplexityModelName(name) {
-				perplexityModelNames = append(perplexityModelNames, name)
+		matchedModelNames, otherModelNames := perplexityAliases.Split(modelNames)
+		if len(otherModelNames) > 0 {
+			log.Printf("[perplexity_network_fixer] network=%s skipping non-perplexity models %v (--perplexity-aliases=%s)", networkID, otherModelNames, perplexityAliases.String())
+		}
+		// Models no provider recognizes are never backfilled, even when they match.
+		perplexityModelNames := make([]string, 0, len(matchedModelNames))
+		for _, name := range matchedModelNames {
+			if !services.IsSupportedModel(cfg, name) {
+				log.Printf("[perplexity_network_fixer] network=%s WARN refusing unconfigured model %q (no provider recognizes it)", networkID, name)
+				plan.Skip("scope_unconfigured_model", 1)
+				continue
 			}
+			perplexityModelNames = append(perplexityModelNames, name)
 		}
 		if len(perplexityModelNames) == 0 {
 			log.Printf("[perplexity_network_fixer] network=%s skip (no perplexity model configured)", networkID)
-			continue
+			plan.Skip("scope_no_matching_model", 1)
+			return
 		}
 
 		// Load questions + locations (with same US-location fallback behavior as pipeline).
 		networkQuestions, networkLocations, err := loadNetworkQuestionsAndLocations(ctx, repos, networkUUID)
 		if err != nil {
 			log.Printf("[perplexity_network_fixer] network=%s ERROR load questions/locations: %v", networkID, err)
-			continue
+			return
+		}
+		// Same location validation as GetNetworkDetails: bad countries are left out
+		networkLocations, invalidLocations := services.ValidateLocations(networkLocations)
+		for _, invalid := range invalidLocations {
+			log.Printf("[perplexity_network_fixer] network=%s WARN %s", networkID, invalid.Message())
+			plan.Skip("scope_invalid_location", 1)
+		}
+		// Sampled networks only run today's sample; don't backfill the rest.
+		if sampler := services.NewQuestionSampler(cfg, networkUUID, time.Now()); sampler != nil {
+			sampled := sampler.Filter(networkQuestions)
+			log.Printf("[perplexity_network_fixer] network=%s sampling %.0f%% of questions: %d/%d selected for today", networkID, sampler.Fraction*100, len(sampled), len(networkQuestions))
+			networkQuestions = sampled
+		}
+		if capped := fixer.CapQuestions(networkQuestions, *maxQuestions); len(capped) < len(networkQuestions) {
+			log.Printf("[perplexity_network_fixer] network=%s considering %d/%d questions (--max-questions)", networkID, len(capped), len(networkQuestions))
+			networkQuestions = capped
 		}
 
 		// Find/create today's network batch.
-		totalQuestions := len(networkQuestions) * len(perplexityModelNames) * len(networkLocations)
-		batch, err := findTodaysNetworkBatch(ctx, repos, networkUUID, todayStart)
+		// A network with no runs at all has no batch today and nothing to skip,
+		// so the per-question scans are skipped.
+		batch, networkEmpty, err := todaysNetworkBatch(ctx, dbClient, repos, networkID, networkUUID, todayStart, *assumeEmpty)
 		if err != nil {
 			log.Printf("[perplexity_network_fixer] network=%s ERROR finding today's batch: %v", networkID, err)
-			continue
+			return
 		}
 		isExisting := batch != nil
-		if !isExisting {
-			if *dryRun {
-				log.Printf("[perplexity_network_fixer] network=%s DRY RUN would create today's batch (type=perplexity_network_fixer total_questions=%d)", networkID, totalQuestions)
-			} else {
-				createdBatch, err := createNetworkBatch(ctx, repos, networkUUID, totalQuestions)
-				if err != nil {
-					log.Printf("[perplexity_network_fixer] network=%s ERROR creating today's batch: %v", networkID, err)
-					continue
-				}
-				batch = createdBatch
-			}
-		}
-
 		batchID := uuid.Nil
 		batchStatus := ""
 		if batch != nil {
@@ -467,24 +443,37 @@ func main() {
 		// Build missing job list (question × model × location).
 		jobs := make([]runJob, 0)
 		seen := make(map[string]struct{})
-		skippedExisting := 0
+		skips := fixer.SkipCounts{}
 
 		for _, modelName := range perplexityModelNames {
 			for _, loc := range networkLocations {
 				for _, qwt := range networkQuestions {
 					q := qwt.Question
 
-					runs, err := repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
+					// Send the normalized text; the stored question is left untouched.
+					qText, err := services.NormalizeQuestionText(q.QuestionText, &workflowModels.Location{Country: loc.CountryCode, Region: loc.RegionName})
+					if err != nil {
+						log.Printf("[perplexity_network_fixer] network=%s skip question=%s: %v", networkID, q.GeoQuestionID, err)
+						skips.Add(fixer.SkipInvalidQuestion, 1)
+						continue
+					}
+
+					var runs []*models.QuestionRun
+					if !networkEmpty {
+						runs, err = repos.QuestionRunRepo.GetByQuestion(ctx, q.GeoQuestionID)
+					}
 					if err != nil {
 						key := fmt.Sprintf("%s|%s|%s|%s", q.GeoQuestionID, modelName, loc.CountryCode, regionString(loc.RegionName))
 						if _, ok := seen[key]; ok {
+							skips.Add(fixer.SkipDuplicateJob, 1)
 							continue
 						}
 						seen[key] = struct{}{}
 						jobs = append(jobs, runJob{
 							networkID: networkID,
 							qID:       q.GeoQuestionID,
-							qText:     q.QuestionText,
+							qText:     qText,
+							tags:      services.QuestionTags(qwt),
 							modelName: modelName,
 							country:   loc.CountryCode,
 							region:    loc.RegionName,
@@ -515,12 +504,13 @@ func main() {
 					}
 
 					if found {
-						skippedExisting++
+						skips.Add(fixer.SkipExistingRun, 1)
 						continue
 					}
 
 					key := fmt.Sprintf("%s|%s|%s|%s", q.GeoQuestionID, modelName, loc.CountryCode, regionString(loc.RegionName))
 					if _, ok := seen[key]; ok {
+						skips.Add(fixer.SkipDuplicateJob, 1)
 						continue
 					}
 					seen[key] = struct{}{}
@@ -528,7 +518,8 @@ func main() {
 					jobs = append(jobs, runJob{
 						networkID: networkID,
 						qID:       q.GeoQuestionID,
-						qText:     q.QuestionText,
+						qText:     qText,
+						tags:      services.QuestionTags(qwt),
 						modelName: modelName,
 						country:   loc.CountryCode,
 						region:    loc.RegionName,
@@ -538,11 +529,57 @@ func main() {
 			}
 		}
 
+		plan.SkipAll(skips)
+		for _, j := range jobs {
+			region := ""
+			if j.region != nil {
+				region = *j.region
+			}
+			plan.Add(fixer.PlanEntry{Scope: "network", ScopeID: networkID, QuestionID: j.qID.String(), Model: j.modelName, Country: j.country, Region: region})
+		}
+
 		if len(jobs) == 0 {
-			log.Printf("[perplexity_network_fixer] network=%s done (no missing runs) skipped_existing=%d", networkID, skippedExisting)
-			continue
+			log.Printf("[perplexity_network_fixer] network=%s done (no missing runs) skipped=%s", networkID, skips)
+			return
+		}
+
+		// A new batch is sized by the jobs actually planned rather than questions ×
+		// models × locations, so runs that already exist never count against it;
+		// --count-planned re-plans a resumed batch the same way.
+		if !isExisting {
+			if *dryRun {
+				log.Printf("[perplexity_network_fixer] network=%s DRY RUN would create today's batch (type=perplexity_network_fixer total_questions=%d)", networkID, len(jobs))
+			} else {
+				createdBatch, err := createNetworkBatch(ctx, repos, networkUUID, len(jobs), *batchType)
+				if err != nil {
+					log.Printf("[perplexity_network_fixer] network=%s ERROR creating today's batch: %v", networkID, err)
+					return
+				}
+				batch = createdBatch
+				for i := range jobs {
+					jobs[i].batchID = batch.BatchID
+				}
+				log.Printf("[perplexity_network_fixer] network=%s created batch=%s total_questions=%d day_tz=%s", networkID, batch.BatchID, len(jobs), dayLoc)
+			}
+		} else if *countPlanned {
+			if *dryRun {
+				log.Printf("[perplexity_network_fixer] network=%s DRY RUN would re-plan batch=%s total_questions=%d + attached runs", networkID, batch.BatchID, len(jobs))
+			} else {
+				total, err := services.PlanBatchTotal(ctx, repos, batch, len(jobs))
+				if err != nil {
+					log.Printf("[perplexity_network_fixer] network=%s WARN re-planning batch=%s: %v", networkID, batch.BatchID, err)
+				} else {
+					log.Printf("[perplexity_network_fixer] network=%s re-planned batch=%s total_questions=%d", networkID, batch.BatchID, total)
+				}
+			}
 		}
-		log.Printf("[perplexity_network_fixer] network=%s missing_jobs=%d skipped_existing=%d (executing with concurrency=%d)", networkID, len(jobs), skippedExisting, *concurrency)
+
+		fixer.Shuffle(shuffler, jobs)
+
+		log.Printf("[perplexity_network_fixer] network=%s missing_jobs=%d skipped=%s (executing with concurrency=%d)", networkID, len(jobs), skips, *concurrency)
+
+		// Retries for every job of this org/network draw from one shared budget.
+		budget := fixer.NewRetryBudget(*retryBudget)
 
 		jobsCh := make(chan runJob)
 		resultsCh := make(chan runJobResult, len(jobs))
@@ -551,6 +588,10 @@ func main() {
 		worker := func() {
 			defer wg.Done()
 			for job := range jobsCh {
+				if ctx.Err() != nil {
+					// Overall timeout hit: stop picking up work instead of failing every remaining job.
+					return
+				}
 				if *dryRun {
 					resultsCh <- runJobResult{job: job, created: true, cost: 0}
 					continue
@@ -561,7 +602,12 @@ func main() {
 				}
 
 				prompt := buildLocalizedPrompt(job.qText, job.country, job.region)
-				resp, err := pplx.chatCompletion(ctx, prompt)
+				var resp *fixer.PerplexityChatResponse
+				err := fixer.Do(ctx, *retries, retryBackoff, budget, func(ctx context.Context) error {
+					var callErr error
+					resp, callErr = pplx.ChatCompletion(ctx, prompt)
+					return callErr
+				})
 				if err != nil {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
@@ -572,6 +618,10 @@ func main() {
 				}
 
 				content := resp.Choices[0].Message.Content
+				if err := fixer.CheckResponseText(content, cfg.MinExtractionResponseChars); err != nil {
+					resultsCh <- runJobResult{job: job, failed: true, err: err}
+					continue
+				}
 				inputTokens := resp.Usage.PromptTokens
 				outputTokens := resp.Usage.CompletionTokens
 				totalCost := resp.Usage.Cost.TotalCost
@@ -601,8 +651,20 @@ func main() {
 					resultsCh <- runJobResult{job: job, failed: true, err: err}
 					continue
 				}
+				if err := services.SetQuestionRunTags(ctx, repos, qr.QuestionRunID, job.tags); err != nil {
+					log.Printf("[perplexity_network_fixer] network=%s WARN storing tags of run %s: %v", job.networkID, qr.QuestionRunID, err)
+				}
+				// Partial responses are kept for backfills, but marked so they can be told apart.
+				if resp.Truncated {
+					if resp.LengthLimited() {
+						log.Printf("[perplexity_network_fixer] network=%s run %s hit the output token limit (model=%s max_tokens=%d)", job.networkID, qr.QuestionRunID, pplx.Model, pplx.MaxTokens)
+					}
+					if err := services.MarkQuestionRunTruncated(ctx, repos, qr.QuestionRunID, outputTokens); err != nil {
+						log.Printf("[perplexity_network_fixer] network=%s WARN marking run %s truncated: %v", job.networkID, qr.QuestionRunID, err)
+					}
+				}
 
-				resultsCh <- runJobResult{job: job, created: true, cost: totalCost}
+				resultsCh <- runJobResult{job: job, created: true, truncated: resp.Truncated, cost: totalCost}
 			}
 		}
 
@@ -612,10 +674,14 @@ func main() {
 		}
 
 		go func() {
+			defer close(jobsCh)
 			for _, j := range jobs {
-				jobsCh <- j
+				select {
+				case <-ctx.Done():
+					return
+				case jobsCh <- j:
+				}
 			}
-			close(jobsCh)
 		}()
 
 		go func() {
@@ -624,27 +690,67 @@ func main() {
 		}()
 
 		createdCount := 0
+		truncatedCount := 0
 		failedCount := 0
 		var totalCost float64
 
+		progress := fixer.NewProgress(len(jobs))
+		stopHeartbeat := fixer.StartHeartbeat(*heartbeat, progress, func(line string) {
+			log.Printf("[perplexity_network_fixer] network=%s %s", networkID, line)
+		})
 		for res := range resultsCh {
 			if res.failed {
 				failedCount++
+				progress.Failed()
 				log.Printf("[perplexity_network_fixer] network=%s ERROR job question=%s model=%s location=%s: %v",
 					networkID, res.job.qID, res.job.modelName, res.job.country, res.err)
 				continue
 			}
 			if res.created {
 				createdCount++
+				progress.Created(res.cost)
+				if res.truncated {
+					truncatedCount++
+				}
 				totalCost += res.cost
 				if *dryRun {
 					log.Printf("[perplexity_network_fixer] DRY RUN would insert run question=%s model=%s location=%s", res.job.qID, res.job.modelName, res.job.country)
 				}
 			}
 		}
+		stopHeartbeat()
+
+		totalJobs += len(jobs)
+		totalFailed += len(jobs) - createdCount
+		if !isExisting && batch != nil {
+			// Jobs left unrun by the timeout count as failed here too; the batch is
+			// still closed out after the overall timeout has cancelled ctx.
+			if err := completeNetworkBatch(context.WithoutCancel(ctx), repos, batch, createdCount, len(jobs)-createdCount); err != nil {
+				log.Printf("[perplexity_network_fixer] network=%s WARN completing batch=%s: %v", networkID, batch.BatchID, err)
+			}
+		}
 
-		log.Printf("[perplexity_network_fixer] network=%s done created=%d skipped_existing=%d failed=%d total_cost=%.6f", networkID, createdCount, skippedExisting, failedCount, totalCost)
+		if ctx.Err() != nil {
+			log.Printf("[perplexity_network_fixer] network=%s interrupted by timeout: jobs_not_run=%d", networkID, len(jobs)-createdCount-failedCount)
+		}
+		log.Printf("[perplexity_network_fixer] network=%s done created=%d truncated=%d skipped=%s failed=%d retries_used=%d total_cost=%.6f", networkID, createdCount, truncatedCount, skips, failedCount, budget.Used(), totalCost)
+	})
+	if unprocessed > 0 {
+		log.Printf("[perplexity_network_fixer] timeout reached, %d networks unprocessed: %v", unprocessed, ctx.Err())
 	}
 
-	log.Printf("[perplexity_network_fixer] done")
+	if plan != nil {
+		if err := plan.WriteFile(*planOut); err != nil {
+			log.Printf("[perplexity_network_fixer] ERROR writing plan: %v", err)
+		} else {
+			log.Printf("[perplexity_network_fixer] plan written to %s (jobs=%d)", *planOut, len(plan.Entries))
+		}
+	}
+	policy := fixer.FailurePolicy{FailOnAny: *failOnAny, Threshold: *failThreshold}
+	code := policy.ExitCode(totalFailed, totalJobs)
+	if pplx != nil {
+		log.Printf("[perplexity_network_fixer] perplexity key usage: %s", pplx.KeyUsage())
+	}
+	log.Printf("[perplexity_network_fixer] done jobs=%d failed=%d exit=%d", totalJobs, totalFailed, code)
+	return code
 }
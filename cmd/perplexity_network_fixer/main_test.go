@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/sqltest"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+)
+
+func TestCreateNetworkBatchBatchType(t *testing.T) {
+	for _, batchType := range []string{"perplexity_network_fixer", "perplexity_network_fixer_rebackfill"} {
+		t.Run(batchType, func(t *testing.T) {
+			rec := &sqltest.Recorder{Exec: func(query string, args []any) (int64, error) { return 1, nil }}
+			repos := services.NewRepositoryManager(sqltest.NewClient(t, rec))
+
+			batch, err := createNetworkBatch(context.Background(), repos, uuid.New(), 12, batchType)
+			if err != nil {
+				t.Fatalf("createNetworkBatch() error = %v", err)
+			}
+			if batch.BatchType != batchType || batch.Scope != "network" || batch.TotalQuestions != 12 {
+				t.Errorf("batch = %+v, want a network batch of 12 questions typed %q", batch, batchType)
+			}
+			var inserted []any
+			for _, stmt := range rec.Statements() {
+				if strings.HasPrefix(stmt.Query, "INSERT INTO question_run_batches") {
+					inserted = stmt.Args
+				}
+			}
+			if len(inserted) < 5 || inserted[4] != batchType {
+				t.Errorf("inserted batch args = %v, want batch_type %q", inserted, batchType)
+			}
+		})
+	}
+}
+
+// hasRuns answers fixer.NetworkHasRuns' EXISTS query.
+type hasRuns struct {
+	exists bool
+	err    error
+}
+
+func (h hasRuns) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	*dest.(*bool) = h.exists
+	return h.err
+}
+
+type networkQuestions struct {
+	interfaces.GeoQuestionRepository
+	questions []*models.GeoQuestion
+}
+
+func (r networkQuestions) GetByNetwork(ctx context.Context, networkID uuid.UUID) ([]*models.GeoQuestion, error) {
+	return r.questions, nil
+}
+
+// scannedRuns counts the per-question run scans.
+type scannedRuns struct {
+	interfaces.QuestionRunRepository
+	scans *int
+}
+
+func (r scannedRuns) GetByQuestion(ctx context.Context, questionID uuid.UUID) ([]*models.QuestionRun, error) {
+	*r.scans++
+	return nil, nil
+}
+
+func TestTodaysNetworkBatchSkipsEmptyNetworks(t *testing.T) {
+	questions := []*models.GeoQuestion{{GeoQuestionID: uuid.New()}, {GeoQuestionID: uuid.New()}}
+
+	tests := []struct {
+		name        string
+		db          hasRuns
+		assumeEmpty bool
+		wantEmpty   bool
+		wantScans   int
+	}{
+		{name: "network without runs", db: hasRuns{exists: false}, wantEmpty: true},
+		{name: "--assume-empty", db: hasRuns{exists: true}, assumeEmpty: true, wantEmpty: true},
+		{name: "network with runs", db: hasRuns{exists: true}, wantScans: len(questions)},
+		{name: "failed check scans", db: hasRuns{err: errors.New("connection reset")}, wantScans: len(questions)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scans := 0
+			repos := &services.RepositoryManager{
+				GeoQuestionRepo: networkQuestions{questions: questions},
+				QuestionRunRepo: scannedRuns{scans: &scans},
+			}
+			batch, empty, err := todaysNetworkBatch(context.Background(), tt.db, repos, "net-1", uuid.New(), time.Now(), tt.assumeEmpty)
+			if err != nil || batch != nil {
+				t.Fatalf("todaysNetworkBatch() = %v, %v; want no batch", batch, err)
+			}
+			if empty != tt.wantEmpty {
+				t.Errorf("empty = %t, want %t", empty, tt.wantEmpty)
+			}
+			if scans != tt.wantScans {
+				t.Errorf("%d per-question run scans, want %d", scans, tt.wantScans)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/fixer"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// Standalone one-off tool: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the tool and returns its exit code (see fixer.ExitOK and friends).
+//
+// Re-extracts one question run from its stored response after an extraction
+// prompt fix (see services.ReextractQuestionRun): the stored outputs of the
+// selected stages are deleted and extracted again. Org and network runs are
+// told apart from the run. --dry-run only lists what would be deleted and
+// recreated.
+func run() int {
+	var (
+		runID   = flag.String("run-id", "", "question run ID to re-extract")
+		stages  = flag.String("stages", "", "comma-separated stages (org runs: mentions,claims,citations,metrics; network runs: evals,competitors,citations; empty = all)")
+		orgID   = flag.String("org-id", "", "network runs only: org to re-extract for (empty = every org with outputs on the run)")
+		dryRun  = flag.Bool("dry-run", false, "list what would be deleted and recreated without changing anything")
+		timeout = flag.Duration("timeout", 15*time.Minute, "overall timeout for the script")
+	)
+	flag.Parse()
+
+	questionRunID, err := uuid.Parse(strings.TrimSpace(*runID))
+	if err != nil {
+		log.Printf("[reextract] --run-id must be a UUID: %v", err)
+		return fixer.ExitFatal
+	}
+	opts := services.ReextractOptions{DryRun: *dryRun}
+	if *orgID != "" {
+		if opts.OrgID, err = uuid.Parse(strings.TrimSpace(*orgID)); err != nil {
+			log.Printf("[reextract] --org-id must be a UUID: %v", err)
+			return fixer.ExitFatal
+		}
+	}
+	var stageList []string
+	if *stages != "" {
+		stageList = strings.Split(*stages, ",")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		_ = godotenv.Load("dev.env")
+	}
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		log.Printf("[reextract] DB connect failed: %v", err)
+		return fixer.ExitFatal
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	runner := services.NewQuestionRunnerService(cfg, repos, services.NewDataExtractionService(cfg), services.NewOrgService(cfg, repos))
+
+	log.Printf("[reextract] start run=%s stages=%q dry_run=%t", questionRunID, *stages, *dryRun)
+	plan, err := runner.ReextractQuestionRun(ctx, questionRunID, stageList, opts)
+	if err != nil {
+		log.Printf("[reextract] %v", err)
+		return fixer.ExitFatal
+	}
+
+	verb := "deleted"
+	if plan.DryRun {
+		verb = "would_delete"
+	}
+	for _, target := range plan.Targets {
+		org := ""
+		if target.OrgID != uuid.Nil {
+			org = " org=" + target.OrgID.String()
+		}
+		outcome := ""
+		if !plan.DryRun {
+			outcome = " outcome=" + target.Outcome
+		}
+		log.Printf("[reextract] run=%s%s stage=%s %s=%d%s", plan.QuestionRunID, org, target.Stage, verb, target.Stored, outcome)
+	}
+
+	failed := len(plan.Failed())
+	code := fixer.FailurePolicy{FailOnAny: true}.ExitCode(failed, len(plan.Targets))
+	log.Printf("[reextract] done run=%s scope=%s owner=%s stages=%s failed=%d dry_run=%t exit=%d",
+		plan.QuestionRunID, plan.Scope, plan.OwnerID, strings.Join(plan.Stages, ","), failed, plan.DryRun, code)
+	if plan.DryRun {
+		log.Printf("[reextract] dry run: re-run without --dry-run to re-extract")
+	}
+	return code
+}
@@ -3,9 +3,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
@@ -14,6 +14,23 @@ import (
 )
 
 func main() {
+	var (
+		deterministic = flag.Bool("deterministic", false, "temperature 0 (where supported) and a fixed seed")
+		seed          = flag.Int64("seed", 0, "seed used with -deterministic (0 = service default)")
+		minCount      = flag.Int("min", 0, "minimum variation count; re-prompts once when below (0 = no minimum)")
+		maxCount      = flag.Int("max", 0, "maximum variation count; extra AI variations are truncated (0 = no maximum)")
+		orgName       = flag.String("org", "", "run a single ad-hoc org name instead of the built-in test cases")
+		websites      = flag.String("websites", "", "comma-separated websites for -org")
+	)
+	flag.Parse()
+
+	opts := services.NameVariationOptions{
+		Deterministic: *deterministic,
+		Seed:          *seed,
+		MinCount:      *minCount,
+		MaxCount:      *maxCount,
+	}
+
 	fmt.Println("=== Testing GenerateNameVariations Service Function ===")
 
 	// Load .env file
@@ -54,21 +71,37 @@ func main() {
 		},
 	}
 
+	if *orgName != "" {
+		tc := struct {
+			OrgName  string
+			Websites []string
+		}{OrgName: *orgName}
+		for _, w := range strings.Split(*websites, ",") {
+			if w = strings.TrimSpace(w); w != "" {
+				tc.Websites = append(tc.Websites, w)
+			}
+		}
+		testCases = []struct {
+			OrgName  string
+			Websites []string
+		}{tc}
+	}
+
 	ctx := context.Background()
 
-	// Allow user to specify which test case to run via command line arg
+	// Allow user to specify which test case to run via positional arg
 	testIndex := -1 // -1 means run all
-	if len(os.Args) > 1 {
-		fmt.Sscanf(os.Args[1], "%d", &testIndex)
+	if flag.NArg() > 0 {
+		fmt.Sscanf(flag.Arg(0), "%d", &testIndex)
 	}
 
 	if testIndex >= 0 && testIndex < len(testCases) {
 		// Run single test case
-		runTest(ctx, service, testCases[testIndex], testIndex+1)
+		runTest(ctx, service, testCases[testIndex], testIndex+1, opts)
 	} else {
 		// Run all test cases
 		for i, tc := range testCases {
-			runTest(ctx, service, tc, i+1)
+			runTest(ctx, service, tc, i+1, opts)
 			if i < len(testCases)-1 {
 				fmt.Println("\n" + strings.Repeat("-", 80) + "\n")
 			}
@@ -81,14 +114,15 @@ func main() {
 func runTest(ctx context.Context, service services.OrgEvaluationService, tc struct {
 	OrgName  string
 	Websites []string
-}, testNum int) {
+}, testNum int, opts services.NameVariationOptions) {
 	fmt.Printf("📝 Test Case #%d\n", testNum)
 	fmt.Printf("   Organization: %s\n", tc.OrgName)
 	fmt.Printf("   Websites: %v\n", tc.Websites)
+	fmt.Printf("   Options: deterministic=%t seed=%d min=%d max=%d\n", opts.Deterministic, opts.Seed, opts.MinCount, opts.MaxCount)
 	fmt.Println()
 
 	// Call the service function
-	variations, err := service.GenerateNameVariations(ctx, tc.OrgName, tc.Websites)
+	variations, err := service.GenerateNameVariationsWithOptions(ctx, tc.OrgName, tc.Websites, opts)
 	if err != nil {
 		log.Printf("❌ Error generating variations: %v\n", err)
 		return
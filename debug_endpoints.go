@@ -0,0 +1,87 @@
+// debug_endpoints.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// adHocQuestionRunner runs one question synchronously (see
+// services.QuestionRunnerService.RunAdHocQuestion).
+type adHocQuestionRunner interface {
+	RunAdHocQuestion(ctx context.Context, questionText, modelName string, location *workflowModels.Location, webSearch bool) (*services.AIResponse, error)
+}
+
+type debugRunQuestionRequest struct {
+	Question  string  `json:"question"`
+	Model     string  `json:"model"`
+	Country   string  `json:"country"`
+	Region    *string `json:"region,omitempty"`
+	WebSearch *bool   `json:"webSearch,omitempty"`
+}
+
+// registerDebugEndpoints mounts POST /debug/run-question, which runs one
+// question against one model/location and returns the provider's response
+// inline. It spends real provider money, so unless ENVIRONMENT=development it is only
+// registered with DEBUG_ENDPOINTS=true and TEST_TRIGGER_TOKEN set, and every
+// request must carry the token as a bearer token.
+func registerDebugEndpoints(mux *http.ServeMux, cfg *config.Config, runner adHocQuestionRunner) {
+	wrap := func(h http.HandlerFunc) http.Handler { return h }
+	if !cfg.ExplicitDevelopment {
+		if !cfg.DebugEndpoints || cfg.TestTriggerToken == "" {
+			log.Printf("Debug endpoints disabled (environment=%s, DEBUG_ENDPOINTS=%t, TEST_TRIGGER_TOKEN set=%t)", cfg.Environment, cfg.DebugEndpoints, cfg.TestTriggerToken != "")
+			return
+		}
+		wrap = func(h http.HandlerFunc) http.Handler { return requireBearerToken(cfg.TestTriggerToken, h) }
+		log.Printf("Debug endpoints enabled with bearer token auth")
+	}
+
+	mux.Handle("/debug/run-question", wrap(debugRunQuestionHandler(runner)))
+}
+
+// debugRunQuestionHandler runs the question in the JSON body and writes the
+// AIResponse. Web search defaults to on, as in the question pipelines.
+func debugRunQuestionHandler(runner adHocQuestionRunner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req debugRunQuestionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		req.Question = strings.TrimSpace(req.Question)
+		req.Model = strings.TrimSpace(req.Model)
+		req.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+		if req.Question == "" || req.Model == "" || req.Country == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "question, model and country are required"})
+			return
+		}
+		webSearch := true
+		if req.WebSearch != nil {
+			webSearch = *req.WebSearch
+		}
+		location := &workflowModels.Location{Country: req.Country, Region: req.Region}
+
+		response, err := runner.RunAdHocQuestion(r.Context(), req.Question, req.Model, location, webSearch)
+		if err != nil {
+			log.Printf("Debug run-question failed (model=%s country=%s): %v", req.Model, req.Country, err)
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+
+		log.Printf("Debug run-question completed (model=%s country=%s cost=$%.6f)", req.Model, req.Country, response.Cost)
+		writeJSON(w, http.StatusOK, response)
+	}
+}
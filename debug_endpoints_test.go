@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/AI-Template-SDK/senso-workflows/services/providertest"
+)
+
+// TestDebugRunQuestion posts to /debug/run-question and runs the question
+// through the real RunAdHocQuestion on a fake provider.
+func TestDebugRunQuestion(t *testing.T) {
+	const question = `Best  bank in {{country}}?`
+	dev := &config.Config{Environment: "development", ExplicitDevelopment: true}
+	production := &config.Config{Environment: "production", DebugEndpoints: true, TestTriggerToken: "secret"}
+	answer := &services.AIResponse{Response: "Acme Bank is the best.", InputTokens: 120, OutputTokens: 45, Cost: 0.0123, ShouldProcessEvaluation: true}
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		method        string
+		auth          string
+		body          string
+		provider      *providertest.FakeProvider
+		wantStatus    int
+		wantBody      string
+		wantWebSearch []bool // websearch flag of each provider call
+		wantRegion    string
+		wantCost      float64
+	}{
+		{
+			name: "runs with web search by default", cfg: dev,
+			body:       `{"question":"` + question + `","model":"gpt-4.1","country":" us ","region":"CA"}`,
+			provider:   &providertest.FakeProvider{WebSearch: true, Default: answer},
+			wantStatus: http.StatusOK, wantBody: "Acme Bank is the best.", wantWebSearch: []bool{true}, wantRegion: "CA", wantCost: 0.0123,
+		},
+		{
+			name: "web search off", cfg: dev,
+			body:       `{"question":"` + question + `","model":"gpt-4.1","country":"US","webSearch":false}`,
+			provider:   &providertest.FakeProvider{Default: answer},
+			wantStatus: http.StatusOK, wantWebSearch: []bool{false}, wantCost: 0.0123,
+		},
+		{
+			name: "unsupported web search is skipped", cfg: dev,
+			body:       `{"question":"` + question + `","model":"sonar","country":"US"}`,
+			provider:   &providertest.FakeProvider{Default: answer},
+			wantStatus: http.StatusBadGateway, wantBody: "web search requested but not supported",
+		},
+		{
+			name: "unsupported web search is downgraded",
+			cfg:  &config.Config{Environment: "development", ExplicitDevelopment: true, UnsupportedWebSearchPolicy: services.WebSearchPolicyDowngrade},
+			body: `{"question":"` + question + `","model":"sonar","country":"US"}`, provider: &providertest.FakeProvider{Default: answer},
+			wantStatus: http.StatusOK, wantBody: `"WebSearchDowngraded":true`, wantWebSearch: []bool{false}, wantCost: 0.0123,
+		},
+		{
+			name: "provider error", cfg: dev,
+			body:       `{"question":"` + question + `","model":"gpt-4.1","country":"US"}`,
+			provider:   &providertest.FakeProvider{WebSearch: true, Err: errors.New("upstream timeout")},
+			wantStatus: http.StatusBadGateway, wantBody: "upstream timeout", wantWebSearch: []bool{true},
+		},
+		{
+			name: "missing model", cfg: dev, body: `{"question":"Best bank?","country":"US"}`,
+			provider: &providertest.FakeProvider{}, wantStatus: http.StatusBadRequest, wantBody: "question, model and country are required",
+		},
+		{
+			name: "invalid body", cfg: dev, body: `{"question":`,
+			provider: &providertest.FakeProvider{}, wantStatus: http.StatusBadRequest, wantBody: "invalid request body",
+		},
+		{
+			name: "GET not allowed", cfg: dev, method: http.MethodGet,
+			provider: &providertest.FakeProvider{}, wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "disabled outside development", cfg: &config.Config{Environment: "production", TestTriggerToken: "secret"}, auth: "Bearer secret",
+			body:     `{"question":"Best bank?","model":"gpt-4.1","country":"US"}`,
+			provider: &providertest.FakeProvider{WebSearch: true}, wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "missing token", cfg: production,
+			body:     `{"question":"Best bank?","model":"gpt-4.1","country":"US"}`,
+			provider: &providertest.FakeProvider{WebSearch: true}, wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "with token", cfg: production, auth: "Bearer secret",
+			body:       `{"question":"` + question + `","model":"gpt-4.1","country":"US"}`,
+			provider:   &providertest.FakeProvider{WebSearch: true, Default: answer},
+			wantStatus: http.StatusOK, wantWebSearch: []bool{true}, wantCost: 0.0123,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := services.NewQuestionRunnerServiceWithProviders(tt.cfg, &services.RepositoryManager{}, nil, nil,
+				func(model string) (services.AIProvider, error) { return tt.provider, nil })
+			mux := http.NewServeMux()
+			registerDebugEndpoints(mux, tt.cfg, runner)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/debug/run-question", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+
+			calls := tt.provider.Calls()
+			if len(calls) != len(tt.wantWebSearch) {
+				t.Fatalf("provider called %d times, want %d", len(calls), len(tt.wantWebSearch))
+			}
+			for i, call := range calls {
+				if call.WebSearch != tt.wantWebSearch[i] {
+					t.Errorf("call %d web search = %t, want %t", i, call.WebSearch, tt.wantWebSearch[i])
+				}
+				region := ""
+				if call.Location != nil && call.Location.Region != nil {
+					region = *call.Location.Region
+				}
+				if call.Location == nil || call.Location.Country != "US" || region != tt.wantRegion {
+					t.Errorf("call %d location = %+v, want country US, region %q", i, call.Location, tt.wantRegion)
+				}
+				want, _ := services.NormalizeQuestionText(question, call.Location)
+				if call.Query != want {
+					t.Errorf("call %d query = %q, want the normalized question %q", i, call.Query, want)
+				}
+			}
+
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var got services.AIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if got.Response != answer.Response || got.Cost != tt.wantCost || got.InputTokens != answer.InputTokens || got.OutputTokens != answer.OutputTokens {
+				t.Errorf("response = %+v, want %q costing %f", got, answer.Response, tt.wantCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+// eval_testing/from_db.go
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// --- From-DB Comparison (-from-db) ---
+//
+// Re-runs the current evaluation extraction on a sample of recent production
+// runs and scores its agreement with the evaluation rows stored for them:
+// org_evals for an org's own questions, network_org_evals with -network. The
+// database is only read; the fresh results are logged and optionally written
+// to a CSV of disagreements for manual review.
+
+// DBCompareOptions holds the -from-db flags.
+type DBCompareOptions struct {
+	OrgID   string
+	Network bool
+	Sample  int
+	Since   time.Duration
+	Seed    int64
+	// SOVTolerance is in percentage points, like the golden test.
+	SOVTolerance  float64
+	NormalizedSOV bool
+	// DisagreementsOut is an optional CSV path for the disagreement list.
+	DisagreementsOut string
+}
+
+// EvalSnapshot is the part of an evaluation the comparison scores.
+type EvalSnapshot struct {
+	Mentioned   bool
+	Sentiment   string
+	SOV         float64 // percent of the response, as in golden_data.csv
+	MentionRank *int
+}
+
+// DBComparison is the outcome of one sampled run.
+type DBComparison struct {
+	QuestionRunID  uuid.UUID
+	Stored         EvalSnapshot
+	Fresh          EvalSnapshot
+	MentionAgree   bool
+	SentimentAgree bool
+	SOVAgree       bool
+	RankAgree      bool
+	// Error is set when the run could not be compared (no stored row, or the
+	// fresh extraction failed); the agreement fields are then meaningless.
+	Error string
+}
+
+// Agrees reports whether every scored field agrees.
+func (c DBComparison) Agrees() bool {
+	return c.Error == "" && c.MentionAgree && c.SentimentAgree && c.SOVAgree && c.RankAgree
+}
+
+// Differences describes the disagreeing fields, e.g. "sentiment: positive -> neutral".
+func (c DBComparison) Differences() []string {
+	if c.Error != "" {
+		return []string{c.Error}
+	}
+	var diffs []string
+	if !c.MentionAgree {
+		diffs = append(diffs, fmt.Sprintf("mentioned: %t -> %t", c.Stored.Mentioned, c.Fresh.Mentioned))
+	}
+	if !c.SentimentAgree {
+		diffs = append(diffs, fmt.Sprintf("sentiment: %q -> %q", c.Stored.Sentiment, c.Fresh.Sentiment))
+	}
+	if !c.SOVAgree {
+		diffs = append(diffs, fmt.Sprintf("sov: %.2f%% -> %.2f%%", c.Stored.SOV, c.Fresh.SOV))
+	}
+	if !c.RankAgree {
+		diffs = append(diffs, fmt.Sprintf("mention_rank: %s -> %s", formatRank(c.Stored.MentionRank), formatRank(c.Fresh.MentionRank)))
+	}
+	return diffs
+}
+
+// CompareSnapshots scores a fresh evaluation against the stored one.
+// Sentiment and rank only count for mentioned evaluations, so a run neither
+// side mentions agrees on both regardless of leftovers.
+func CompareSnapshots(runID uuid.UUID, stored, fresh EvalSnapshot, sovTolerance float64) DBComparison {
+	c := DBComparison{QuestionRunID: runID, Stored: stored, Fresh: fresh}
+	c.MentionAgree = stored.Mentioned == fresh.Mentioned
+	c.SentimentAgree = mentionedSentiment(stored) == mentionedSentiment(fresh)
+	c.SOVAgree = math.Abs(stored.SOV-fresh.SOV) <= sovTolerance
+	c.RankAgree = sameRank(mentionedRank(stored), mentionedRank(fresh))
+	return c
+}
+
+func mentionedSentiment(s EvalSnapshot) string {
+	if !s.Mentioned {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(s.Sentiment))
+}
+
+func mentionedRank(s EvalSnapshot) *int {
+	if !s.Mentioned {
+		return nil
+	}
+	return s.MentionRank
+}
+
+func sameRank(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func formatRank(rank *int) string {
+	if rank == nil {
+		return "-"
+	}
+	return strconv.Itoa(*rank)
+}
+
+// snapshotFromEval builds a snapshot from an evaluation's fields; SOV is the
+// mention text's share of the response (or of services.SOVDenominator).
+func snapshotFromEval(mentioned bool, sentiment, mentionText *string, rank *int, responseText string, normalizedSOV bool) EvalSnapshot {
+	s := EvalSnapshot{Mentioned: mentioned, MentionRank: rank}
+	if sentiment != nil {
+		s.Sentiment = *sentiment
+	}
+	denominator := len(responseText)
+	if normalizedSOV {
+		denominator = len(services.SOVDenominator(responseText))
+	}
+	if mentioned && mentionText != nil && denominator > 0 {
+		s.SOV = float64(len(*mentionText)) / float64(denominator) * 100.0
+	}
+	return s
+}
+
+// SampleRuns returns up to n runs with a response created at or after since,
+// chosen at random with rng and ordered newest first. n <= 0 keeps them all.
+func SampleRuns(runs []*models.QuestionRun, since time.Time, n int, rng *rand.Rand) []*models.QuestionRun {
+	var eligible []*models.QuestionRun
+	for _, run := range runs {
+		if run == nil || run.CreatedAt.Before(since) || run.ResponseText == nil || strings.TrimSpace(*run.ResponseText) == "" {
+			continue
+		}
+		eligible = append(eligible, run)
+	}
+	if n > 0 && len(eligible) > n {
+		rng.Shuffle(len(eligible), func(i, j int) { eligible[i], eligible[j] = eligible[j], eligible[i] })
+		eligible = eligible[:n]
+	}
+	sort.SliceStable(eligible, func(i, j int) bool { return eligible[i].CreatedAt.After(eligible[j].CreatedAt) })
+	return eligible
+}
+
+// DBCompareSummary counts agreement per field over the compared runs.
+type DBCompareSummary struct {
+	Runs      int
+	Compared  int
+	Errors    int
+	Agreed    int
+	Mention   int
+	Sentiment int
+	SOV       int
+	Rank      int
+}
+
+// SummarizeComparisons counts the agreeing fields; runs with an Error only
+// count towards Runs and Errors.
+func SummarizeComparisons(comparisons []DBComparison) DBCompareSummary {
+	s := DBCompareSummary{Runs: len(comparisons)}
+	for _, c := range comparisons {
+		if c.Error != "" {
+			s.Errors++
+			continue
+		}
+		s.Compared++
+		if c.Agrees() {
+			s.Agreed++
+		}
+		if c.MentionAgree {
+			s.Mention++
+		}
+		if c.SentimentAgree {
+			s.Sentiment++
+		}
+		if c.SOVAgree {
+			s.SOV++
+		}
+		if c.RankAgree {
+			s.Rank++
+		}
+	}
+	return s
+}
+
+// FormatDBCompareReport renders the summary and the disagreement list.
+func FormatDBCompareReport(comparisons []DBComparison, sovTolerance float64) string {
+	s := SummarizeComparisons(comparisons)
+	pct := func(n int) float64 {
+		if s.Compared == 0 {
+			return 0
+		}
+		return float64(n) / float64(s.Compared) * 100
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- 📊 Stored vs Fresh Agreement (%d runs, %d compared, %d not comparable) ---\n", s.Runs, s.Compared, s.Errors)
+	fmt.Fprintf(&b, "  - Mention:          %.2f%% (%d/%d)\n", pct(s.Mention), s.Mention, s.Compared)
+	fmt.Fprintf(&b, "  - Sentiment:        %.2f%% (%d/%d)\n", pct(s.Sentiment), s.Sentiment, s.Compared)
+	fmt.Fprintf(&b, "  - SOV (±%.2f%%):    %.2f%% (%d/%d)\n", sovTolerance, pct(s.SOV), s.SOV, s.Compared)
+	fmt.Fprintf(&b, "  - Mention rank:     %.2f%% (%d/%d)\n", pct(s.Rank), s.Rank, s.Compared)
+	fmt.Fprintf(&b, "🎯 Full agreement: %.2f%% (%d/%d)\n", pct(s.Agreed), s.Agreed, s.Compared)
+
+	disagreements := 0
+	for _, c := range comparisons {
+		if c.Agrees() {
+			continue
+		}
+		if disagreements == 0 {
+			b.WriteString("--- Disagreements (for manual review) ---\n")
+		}
+		disagreements++
+		fmt.Fprintf(&b, "  run=%s %s\n", c.QuestionRunID, strings.Join(c.Differences(), "; "))
+	}
+	return b.String()
+}
+
+var disagreementCSVHeader = []string{"question_run_id", "stored_mentioned", "fresh_mentioned", "stored_sentiment", "fresh_sentiment", "stored_sov", "fresh_sov", "stored_rank", "fresh_rank", "differences"}
+
+// writeDisagreements writes the comparisons that don't agree as CSV.
+func writeDisagreements(path string, comparisons []DBComparison) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write(disagreementCSVHeader)
+	for _, c := range comparisons {
+		if c.Agrees() {
+			continue
+		}
+		_ = w.Write([]string{
+			c.QuestionRunID.String(),
+			strconv.FormatBool(c.Stored.Mentioned), strconv.FormatBool(c.Fresh.Mentioned),
+			c.Stored.Sentiment, c.Fresh.Sentiment,
+			strconv.FormatFloat(c.Stored.SOV, 'f', 2, 64), strconv.FormatFloat(c.Fresh.SOV, 'f', 2, 64),
+			formatRank(c.Stored.MentionRank), formatRank(c.Fresh.MentionRank),
+			strings.Join(c.Differences(), "; "),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// Standalone harness mode: intentionally duplicates DB bootstrapping from main.go
+func createDatabaseClient(ctx context.Context, cfg config.DatabaseConfig) (*database.Client, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &database.Client{DB: db}, nil
+}
+
+// runFromDBComparison samples recent runs of the org (or of its network with
+// opts.Network), re-runs the extraction and logs the agreement report.
+func runFromDBComparison(ctx context.Context, cfg *config.Config, opts DBCompareOptions) error {
+	orgUUID, err := uuid.Parse(opts.OrgID)
+	if err != nil {
+		return fmt.Errorf("-org must be a UUID: %w", err)
+	}
+
+	dbClient, err := createDatabaseClient(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	repos := services.NewRepositoryManager(dbClient)
+	orgDetails, err := services.NewOrgService(cfg, repos).GetOrgDetails(ctx, orgUUID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get org details: %w", err)
+	}
+	orgName := orgDetails.Org.Name
+	orgEvalSvc := services.NewOrgEvaluationService(cfg, repos, services.NewDataExtractionService(cfg))
+
+	now := time.Now().UTC()
+	since := now.Add(-opts.Since)
+	var runs []*models.QuestionRun
+	if opts.Network {
+		networkID := orgDetails.Org.NetworkID
+		log.Printf("Loading runs of network %s since %s", networkID, since.Format(time.RFC3339))
+		runs, err = services.NewExtractionReplayer(cfg, repos).LoadNetworkRuns(ctx, networkID, since, now)
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Printf("Loading runs of org %s (%d questions) since %s", orgName, len(orgDetails.Questions), since.Format(time.RFC3339))
+		for _, q := range orgDetails.Questions {
+			questionRuns, err := repos.QuestionRunRepo.GetByQuestion(ctx, q.Question.GeoQuestionID)
+			if err != nil {
+				return fmt.Errorf("failed to get runs for question %s: %w", q.Question.GeoQuestionID, err)
+			}
+			runs = append(runs, questionRuns...)
+		}
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	sampled := SampleRuns(runs, since, opts.Sample, rand.New(rand.NewSource(seed)))
+	log.Printf("Sampled %d of %d runs (seed=%d, rerun with -seed=%d for the same sample)", len(sampled), len(runs), seed, seed)
+
+	nameVariations, err := orgEvalSvc.GenerateNameVariations(ctx, orgName, orgDetails.Websites)
+	if err != nil {
+		return fmt.Errorf("failed to generate name variations: %w", err)
+	}
+	log.Printf("Generated %d name variations for %s", len(nameVariations), orgName)
+
+	var evaluate func(ctx context.Context, run *models.QuestionRun, responseText string) (stored, fresh *EvalSnapshot, err error)
+	if opts.Network {
+		replayer := services.NewExtractionReplayer(cfg, repos)
+		target := services.ReplayTarget{OrgID: orgUUID, OrgName: orgName, Websites: orgDetails.Websites, NameVariations: nameVariations}
+		questionTexts := make(map[uuid.UUID]string)
+		evaluate = func(ctx context.Context, run *models.QuestionRun, responseText string) (*EvalSnapshot, *EvalSnapshot, error) {
+			evals, err := repos.NetworkOrgEvalRepo.GetByQuestionRunAndOrg(ctx, run.QuestionRunID, orgUUID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get stored evaluation: %w", err)
+			}
+			var latest *models.NetworkOrgEval
+			for _, eval := range evals {
+				if eval != nil && (latest == nil || eval.CreatedAt.After(latest.CreatedAt)) {
+					latest = eval
+				}
+			}
+			if latest == nil {
+				return nil, nil, nil
+			}
+			stored := snapshotFromEval(latest.Mentioned, latest.Sentiment, latest.MentionText, latest.MentionRank, responseText, opts.NormalizedSOV)
+
+			questionText, ok := questionTexts[run.GeoQuestionID]
+			if !ok {
+				if question, err := repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID); err == nil && question != nil {
+					questionText = question.QuestionText
+				}
+				questionTexts[run.GeoQuestionID] = questionText
+			}
+			fresh := EvalSnapshot{}
+			result, err := replayer.EvaluateNetworkOrg(ctx, target, run.QuestionRunID, questionText, responseText)
+			if err != nil {
+				return &stored, nil, err
+			}
+			if result != nil && result.Evaluation != nil {
+				e := result.Evaluation
+				fresh = snapshotFromEval(e.Mentioned, e.Sentiment, e.MentionText, e.MentionRank, responseText, opts.NormalizedSOV)
+			}
+			return &stored, &fresh, nil
+		}
+	} else {
+		evaluate = func(ctx context.Context, run *models.QuestionRun, responseText string) (*EvalSnapshot, *EvalSnapshot, error) {
+			evals, err := repos.OrgEvalRepo.GetByQuestionRunAndOrg(ctx, run.QuestionRunID, orgUUID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get stored evaluation: %w", err)
+			}
+			var latest *models.OrgEval
+			for _, eval := range evals {
+				if eval != nil && (latest == nil || eval.CreatedAt.After(latest.CreatedAt)) {
+					latest = eval
+				}
+			}
+			if latest == nil {
+				return nil, nil, nil
+			}
+			stored := snapshotFromEval(latest.Mentioned, latest.Sentiment, latest.MentionText, latest.MentionRank, responseText, opts.NormalizedSOV)
+
+			// Same pre-filter as the pipeline: no variation in the text, no LLM call
+			fresh := EvalSnapshot{}
+			responseLower := strings.ToLower(responseText)
+			for _, name := range nameVariations {
+				if !strings.Contains(responseLower, strings.ToLower(name)) {
+					continue
+				}
+				result, err := orgEvalSvc.ExtractOrgEvaluation(ctx, run.QuestionRunID, orgUUID, orgName, orgDetails.Websites, nameVariations, responseText)
+				if err != nil {
+					return &stored, nil, err
+				}
+				if result != nil && result.Evaluation != nil {
+					e := result.Evaluation
+					fresh = snapshotFromEval(e.Mentioned, e.Sentiment, e.MentionText, e.MentionRank, responseText, opts.NormalizedSOV)
+				}
+				break
+			}
+			return &stored, &fresh, nil
+		}
+	}
+
+	comparisons := make([]DBComparison, 0, len(sampled))
+	for i, run := range sampled {
+		if ctx.Err() != nil {
+			log.Printf("Stopping early: %v", ctx.Err())
+			break
+		}
+		stored, fresh, err := evaluate(ctx, run, *run.ResponseText)
+		var c DBComparison
+		switch {
+		case err != nil:
+			c = DBComparison{QuestionRunID: run.QuestionRunID, Error: fmt.Sprintf("extraction failed: %v", err)}
+		case stored == nil:
+			c = DBComparison{QuestionRunID: run.QuestionRunID, Error: "no stored evaluation"}
+		default:
+			c = CompareSnapshots(run.QuestionRunID, *stored, *fresh, opts.SOVTolerance)
+		}
+		log.Printf("(%d/%d) run=%s agree=%t", i+1, len(sampled), run.QuestionRunID, c.Agrees())
+		comparisons = append(comparisons, c)
+	}
+
+	log.Print("\n" + FormatDBCompareReport(comparisons, opts.SOVTolerance))
+	if opts.DisagreementsOut != "" {
+		if err := writeDisagreements(opts.DisagreementsOut, comparisons); err != nil {
+			return err
+		}
+		log.Printf("Disagreements written to %s", opts.DisagreementsOut)
+	}
+	return nil
+}
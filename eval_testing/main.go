@@ -81,7 +81,17 @@ func main() {
 	// Define and parse command-line flags
 	testType := flag.String("type", "baseline", "Type of test: 'baseline', 'improvement', or 'deadlink'")
 	modelFlag := flag.String("model", "", "Override Azure deployment name (e.g., gpt-4.1-mini, gpt-5)")
+	providerFlag := flag.String("provider", "", "Extraction backend: 'azure' or 'openai' (default: Azure when configured, else OpenAI)")
 	sovTolerance := flag.Float64("sov-tolerance", 10.0, "Allowed % tolerance for SOV comparison")
+	lenientHeaders := flag.Bool("lenient-headers", false, "Map golden_data.csv columns by position even when the header names don't match")
+	normalizedSOV := flag.Bool("normalized-sov", false, "Score SOV against the normalized denominator (services.SOVDenominator) instead of the raw response length")
+	fromDB := flag.Bool("from-db", false, "Compare a fresh extraction against the stored evaluations of recent production runs instead of golden_data.csv (read-only)")
+	orgFlag := flag.String("org", "", "With -from-db: org ID whose runs and evaluations are compared")
+	networkFlag := flag.Bool("network", false, "With -from-db: compare the org's network_org_evals over its network's runs instead of its org_evals")
+	sampleFlag := flag.Int("sample", 50, "With -from-db: number of runs to sample (0 = all)")
+	sinceDays := flag.Int("since-days", 7, "With -from-db: only sample runs from the last N days")
+	seedFlag := flag.Int64("seed", 0, "With -from-db: sampling seed (0 = random, logged for reruns)")
+	disagreementsOut := flag.String("disagreements-out", "", "With -from-db: optional CSV path for the disagreement list")
 	flag.Parse()
 
 	// Route to dead link testing if requested
@@ -98,11 +108,12 @@ func main() {
 	}
 	cfg := config.Load()
 
-	// Override config with model flag if provided
-	if *modelFlag != "" {
-		cfg.AzureOpenAIDeploymentName = *modelFlag
-		// Log this override *after* logger is set up
+	// Select the extraction backend and apply the model flag if provided
+	if err := applyExtractionProvider(cfg, *providerFlag, *modelFlag); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
+	// Log these overrides *after* logger is set up
 
 	// 2. Set up Logging
 	logDir := "logs"
@@ -128,11 +139,33 @@ func main() {
 	log.Println("--- 🚀 Starting Evaluation Test Harness ---")
 	log.Printf("Test Type: %s", *testType)
 	log.Printf("SOV Tolerance: %.2f%%", *sovTolerance)
+	log.Printf("Normalized SOV: %t", *normalizedSOV)
 	log.Printf("Log file: %s", logFileName)
 	if *modelFlag != "" {
 		log.Printf("Overriding config. Using model from flag: %s", *modelFlag)
 	}
-	log.Printf("Config loaded. Using Azure Deployment: %s", cfg.AzureOpenAIDeploymentName)
+	log.Printf("Config loaded. Extraction provider: %s", extractionProviderLabel(cfg))
+
+	// Route to the stored-vs-fresh comparison if requested
+	if *fromDB {
+		if *orgFlag == "" {
+			log.Fatalf("-from-db requires -org")
+		}
+		opts := DBCompareOptions{
+			OrgID:            *orgFlag,
+			Network:          *networkFlag,
+			Sample:           *sampleFlag,
+			Since:            time.Duration(*sinceDays) * 24 * time.Hour,
+			Seed:             *seedFlag,
+			SOVTolerance:     *sovTolerance,
+			NormalizedSOV:    *normalizedSOV,
+			DisagreementsOut: *disagreementsOut,
+		}
+		if err := runFromDBComparison(context.Background(), cfg, opts); err != nil {
+			log.Fatalf("From-DB comparison failed: %v", err)
+		}
+		return
+	}
 
 	// 3. Initialize Services
 	var repoManager *services.RepositoryManager = nil
@@ -141,7 +174,7 @@ func main() {
 	log.Println("OrgEvaluationService initialized.")
 
 	// 4. Load Golden Data Set
-	records, err := loadGoldenData("golden_data.csv")
+	records, err := loadGoldenData("golden_data.csv", *lenientHeaders)
 	if err != nil {
 		log.Fatalf("Failed to load golden_data.csv: %v", err)
 	}
@@ -153,7 +186,7 @@ func main() {
 		ctx := context.Background()
 		log.Printf("--- Running Test for Org: '%s' ---", record.OrgName)
 		// NEW: Pass sovTolerance to runTest
-		result := runTest(ctx, orgEvaluationService, record, *sovTolerance)
+		result := runTest(ctx, orgEvaluationService, record, *sovTolerance, *normalizedSOV)
 		results = append(results, result)
 	}
 
@@ -233,7 +266,7 @@ func main() {
 }
 
 // runTest executes the "sieve" logic and calculates individual metrics
-func runTest(ctx context.Context, orgEvalSvc services.OrgEvaluationService, record GoldenRecord, sovTolerance float64) TestResult {
+func runTest(ctx context.Context, orgEvalSvc services.OrgEvaluationService, record GoldenRecord, sovTolerance float64, normalizedSOV bool) TestResult {
 
 	result := TestResult{Record: record} // Initialize result
 	dummyUUID := uuid.New()              // Re-use this
@@ -310,10 +343,15 @@ func runTest(ctx context.Context, orgEvalSvc services.OrgEvaluationService, reco
 
 	// --- Step 3: Calculate Actual SOV ---
 	responseTextLen := len(record.ResponseText)
+	denominatorLabel := "ResponseLen"
+	if normalizedSOV {
+		responseTextLen = len(services.SOVDenominator(record.ResponseText))
+		denominatorLabel = "NormalizedLen"
+	}
 	if result.ActualMention && responseTextLen > 0 && mentionTextPtr != nil {
 		mentionTextLen := len(*mentionTextPtr)
 		result.ActualSOV = (float64(mentionTextLen) / float64(responseTextLen)) * 100.0
-		log.Printf("[Test: %s] Calculated SOV: %.2f%% (MentionLen: %d / ResponseLen: %d)", record.OrgName, result.ActualSOV, mentionTextLen, responseTextLen)
+		log.Printf("[Test: %s] Calculated SOV: %.2f%% (MentionLen: %d / %s: %d)", record.OrgName, result.ActualSOV, mentionTextLen, denominatorLabel, responseTextLen)
 	} else {
 		result.ActualSOV = 0.0 // Set to 0 if not mentioned or response text is empty
 		if result.ActualMention {
@@ -409,8 +447,13 @@ func runTest(ctx context.Context, orgEvalSvc services.OrgEvaluationService, reco
 	return result
 }
 
-// loadGoldenData reads and parses the CSV file including the new SOV column
-func loadGoldenData(path string) ([]GoldenRecord, error) {
+// goldenHeaders are the golden_data.csv columns, in order.
+var goldenHeaders = []string{"org_name", "response_text", "expected_mention", "expected_sentiment", "expected_sov", "expected_citations"}
+
+// loadGoldenData reads and parses the CSV file including the new SOV column.
+// The header must name goldenHeaders in order, so a reordered file can't map
+// e.g. sentiment into SOV; lenient falls back to positional mapping with a warning.
+func loadGoldenData(path string, lenient bool) ([]GoldenRecord, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
@@ -422,16 +465,17 @@ func loadGoldenData(path string) ([]GoldenRecord, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read header row: %w", err)
 	}
-	expectedHeaders := []string{"org_name", "response_text", "expected_mention", "expected_sentiment", "expected_sov", "expected_citations"}
+	expectedHeaders := goldenHeaders
 	if len(header) != len(expectedHeaders) {
 		return nil, fmt.Errorf("incorrect number of columns: expected %d, got %d. Headers: %v", len(expectedHeaders), len(header), header)
 	}
-	// Optional: Check header names
-	// for i, h := range expectedHeaders {
-	// 	if strings.TrimSpace(header[i]) != h {
-	// 		return nil, fmt.Errorf("incorrect header in column %d: expected '%s', got '%s'", i+1, h, header[i])
-	// 	}
-	// }
+	if mismatches := goldenHeaderMismatches(header); len(mismatches) > 0 {
+		if !lenient {
+			return nil, fmt.Errorf("header does not match %v: %s (use -lenient-headers to map columns by position)",
+				expectedHeaders, strings.Join(mismatches, "; "))
+		}
+		log.Printf("Warning: header mismatches, mapping columns by position (-lenient-headers): %s", strings.Join(mismatches, "; "))
+	}
 
 	rows, err := reader.ReadAll()
 	if err != nil {
@@ -486,6 +530,19 @@ func loadGoldenData(path string) ([]GoldenRecord, error) {
 	return records, nil
 }
 
+// goldenHeaderMismatches describes each column whose header is not the
+// expected name (case and surrounding whitespace are ignored).
+func goldenHeaderMismatches(header []string) []string {
+	var mismatches []string
+	for i, want := range goldenHeaders {
+		got := strings.TrimPrefix(header[i], "\ufeff") // Excel's UTF-8 BOM
+		if !strings.EqualFold(strings.TrimSpace(got), want) {
+			mismatches = append(mismatches, fmt.Sprintf("column %d: expected %q, got %q", i+1, want, header[i]))
+		}
+	}
+	return mismatches
+}
+
 // --- Dead Link Testing Functions ---
 
 func runDeadLinkTest() {
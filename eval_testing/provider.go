@@ -0,0 +1,68 @@
+// eval_testing/provider.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// Extraction providers selectable with -provider. The services pick their
+// backend from config (Azure when an Azure endpoint, key and deployment are
+// set, standard OpenAI otherwise), so selecting one means adjusting cfg
+// before NewDataExtractionService / NewOrgEvaluationService are constructed.
+const (
+	providerAzure     = "azure"
+	providerOpenAI    = "openai"
+	providerAnthropic = "anthropic"
+)
+
+// applyExtractionProvider points cfg at the given extraction backend; an empty
+// provider keeps the config's choice. modelOverride (-model) replaces the
+// Azure deployment; the standard OpenAI path uses the services' built-in
+// model names and rejects it.
+func applyExtractionProvider(cfg *config.Config, provider, modelOverride string) error {
+	switch strings.ToLower(provider) {
+	case "":
+		if modelOverride != "" {
+			cfg.AzureOpenAIDeploymentName = modelOverride
+			cfg.AzureOpenAIFullDeploymentName = modelOverride
+		}
+		return nil
+	case providerAzure:
+		if modelOverride != "" {
+			cfg.AzureOpenAIDeploymentName = modelOverride
+			cfg.AzureOpenAIFullDeploymentName = modelOverride
+		}
+		if cfg.AzureOpenAIEndpoint == "" || cfg.AzureOpenAIKey == "" || cfg.AzureOpenAIDeploymentName == "" {
+			return fmt.Errorf("-provider=azure needs AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_KEY and a deployment (AZURE_OPENAI_DEPLOYMENT_NAME or -model)")
+		}
+		return nil
+	case providerOpenAI:
+		if cfg.OpenAIAPIKey == "" {
+			return fmt.Errorf("-provider=openai needs OPENAI_API_KEY")
+		}
+		if modelOverride != "" {
+			return fmt.Errorf("-model overrides the Azure deployment and can't be used with -provider=openai")
+		}
+		// With no deployment the services build a standard OpenAI client and
+		// use OpenAI model names
+		cfg.AzureOpenAIDeploymentName = ""
+		cfg.AzureOpenAIFullDeploymentName = ""
+		cfg.AzureOpenAIMiniDeploymentName = ""
+		return nil
+	case providerAnthropic:
+		return fmt.Errorf("-provider=anthropic: extraction has no Anthropic backend yet (the extraction services only speak OpenAI structured outputs)")
+	default:
+		return fmt.Errorf("unknown -provider %q (want %s or %s)", provider, providerAzure, providerOpenAI)
+	}
+}
+
+// extractionProviderLabel names the backend the services will use with cfg.
+func extractionProviderLabel(cfg *config.Config) string {
+	if cfg.AzureOpenAIEndpoint != "" && cfg.AzureOpenAIKey != "" && cfg.AzureOpenAIDeploymentName != "" {
+		return providerAzure + ":" + cfg.AzureOpenAIDeploymentName
+	}
+	return providerOpenAI
+}
@@ -15,6 +15,7 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/openai/openai-go v1.7.0
 	golang.org/x/net v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
 	mvdan.cc/xurls/v2 v2.5.0
 )
 
@@ -46,5 +47,4 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/grpc v1.73.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
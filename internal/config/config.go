@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -27,7 +28,183 @@ type Config struct {
 	GeminiDatasetID           string
 	LinkupAPIKey              string
 	EnableScheduledPipelines  bool
-	Database                  DatabaseConfig
+	// NetworkDatasets routes a network's BrightData-backed traffic to its own
+	// datasets (NETWORK_BRIGHTDATA_DATASETS, "<network-uuid>=chatgpt:gd_x|
+	// perplexity:gd_y|gemini:gd_z"). Datasets a network does not list fall
+	// back to the global IDs above.
+	NetworkDatasets map[string]BrightDataDatasets
+	// ModelProviderOverrides maps a model-name substring to a provider key
+	// (brightdata, perplexity, gemini, linkup, openai, anthropic). It is
+	// consulted before the built-in model-name routing in getProvider.
+	ModelProviderOverrides map[string]string
+	// OrgEvalSoftDeadlineMinutes bounds how long one org evaluation run keeps
+	// starting new questions and extractions; the runs extracted by then are
+	// flagged and the batch is marked partial. 0 (the default) disables it.
+	// When set, keep it below the timeout of the process-org-evaluation step.
+	OrgEvalSoftDeadlineMinutes int
+	// CostDebug logs the breakdown of every cost calculation (COST_DEBUG).
+	CostDebug bool
+	// OpenAIMinResponseChars marks OpenAI/Azure responses shorter than this
+	// (after trimming) as non-processable so terse refusals and empty messages
+	// are skipped instead of extracted. 0 (the default) disables the check.
+	OpenAIMinResponseChars int
+	// MinExtractionResponseChars is the shortest response (after trimming)
+	// the pipeline extracts from; shorter ones fail the run as empty
+	// responses. Empty text is always rejected.
+	MinExtractionResponseChars int
+	// MinMentionRunes is the shortest mention text (in runes, after trimming)
+	// extraction keeps; shorter fragments are discarded as not mentioned.
+	// 0 or 1 keeps every non-empty mention.
+	MinMentionRunes int
+	// AsyncPollInitialIntervalSeconds, AsyncPollMaxIntervalSeconds and
+	// AsyncPollDeadlineMinutes control how the async (BrightData-backed)
+	// providers poll job progress: the interval doubles from the initial to
+	// the max value; a deadline of 0 waits as long as the context allows.
+	AsyncPollInitialIntervalSeconds int
+	AsyncPollMaxIntervalSeconds     int
+	AsyncPollDeadlineMinutes        int
+	// BatchProgressFlushSeconds and BatchProgressFlushRuns set how often a
+	// running matrix commits its batch progress counters: after this many
+	// seconds or this many runs, whichever comes first (0 disables either).
+	BatchProgressFlushSeconds int
+	BatchProgressFlushRuns    int
+	// OrgDetailsCacheTTLSeconds lets OrgService reuse GetOrgDetails results
+	// for the same org within this many seconds (0 disables the cache).
+	OrgDetailsCacheTTLSeconds int
+	// ReconcileAutoFix lets the weekly reconciliation apply its safe fixes
+	// (recount batch totals, clear duplicate is_latest flags); otherwise it
+	// only reports. ReconcileLookbackDays bounds the batch count check.
+	ReconcileAutoFix      bool
+	ReconcileLookbackDays int
+	// NetworkOrgDeadLetterMaxAttempts is how many failed attempts a dead-lettered
+	// network org extraction gets before replay stops retrying it and the
+	// weekly reconciliation reports it (NETWORK_ORG_DEAD_LETTER_MAX_ATTEMPTS, default 5).
+	NetworkOrgDeadLetterMaxAttempts int
+	// NetworkOrgClaimTTLMinutes is how long a network org extraction claim
+	// holds before another processor may take it over, e.g. after a crash
+	// (NETWORK_ORG_CLAIM_TTL_MINUTES, default 30).
+	NetworkOrgClaimTTLMinutes int
+	// NetworkRunReuse lets the org evaluation pipeline reuse today's network
+	// run of the same question, model and location instead of calling the
+	// provider again (NETWORK_RUN_REUSE, default off). NetworkRunReuseOrgs
+	// overrides it per org as "<org uuid>=on|off" pairs (NETWORK_RUN_REUSE_ORGS).
+	NetworkRunReuse     bool
+	NetworkRunReuseOrgs map[string]string
+	// SOVNormalization also computes share of voice against the response
+	// without reference sections and link URLs and stores it next to the raw
+	// value (SOV_NORMALIZATION, default off); target_sov stays raw.
+	SOVNormalization bool
+	// StoreTruncatedResponses keeps partial responses from streaming providers
+	// that hit their deadline. Off by default: the run is treated as failed.
+	StoreTruncatedResponses bool
+	// ExplicitDevelopment is true only when ENVIRONMENT is set to
+	// "development". Environment defaults to development when unset, which
+	// must not open the test and debug endpoints.
+	ExplicitDevelopment bool
+	// TestTriggerToken enables the /test/trigger-* endpoints outside
+	// development; requests must send it as a bearer token.
+	TestTriggerToken string
+	// DebugEndpoints enables the /debug/* endpoints outside development
+	// (DEBUG_ENDPOINTS); they also require TestTriggerToken there.
+	DebugEndpoints bool
+	// OrgDisabledStages maps org UUID to the extraction stages its plan
+	// excludes, e.g. "<uuid>=competitors|citations" (ORG_DISABLED_STAGES).
+	OrgDisabledStages map[string]string
+	// NetworkQuestionSampling maps network UUID to the fraction of its
+	// questions run each day, e.g. "<uuid>=0.2" (NETWORK_QUESTION_SAMPLING).
+	// Networks not listed run every question.
+	NetworkQuestionSampling map[string]string
+	// BatchDayTimezones maps org or network UUID to the IANA time zone whose
+	// midnight starts its batch day, e.g. "<uuid>=Australia/Sydney"
+	// (BATCH_DAY_TIMEZONES). Unlisted orgs and networks use UTC.
+	BatchDayTimezones map[string]string
+	// PriorityQuestionTag is the question tag that sampled networks run
+	// every day regardless of sampling (PRIORITY_QUESTION_TAG, default
+	// "priority"). Matched against normalized tag names.
+	PriorityQuestionTag string
+	// UnsupportedWebSearchPolicy decides what happens when web search is
+	// requested for a model whose provider cannot search: "skip" (default)
+	// fails the combination, "downgrade" runs it without search and marks
+	// the run.
+	UnsupportedWebSearchPolicy string
+	// ProvidersConfigFile is an optional JSON/YAML file defining, per model,
+	// the provider, capabilities and pricing (PROVIDERS_CONFIG_FILE, or the
+	// server's --providers-config flag); see services/providers_config.go.
+	ProvidersConfigFile string
+	// ProviderReplayDir, when set, answers every provider call from the
+	// fixtures in that directory instead of the network (PROVIDER_REPLAY_DIR);
+	// ProviderRecordDir saves every provider response there as a fixture
+	// (PROVIDER_RECORD_DIR). Replay wins when both are set; see
+	// services/provider_replay.go.
+	ProviderReplayDir string
+	ProviderRecordDir string
+	// CompetitorExclusionsFile is an optional JSON file extending the built-in
+	// competitor exclusion lists (COMPETITOR_EXCLUSIONS_FILE);
+	// CompetitorExclusionVertical is the list applied to orgs the file does
+	// not assign (COMPETITOR_EXCLUSION_VERTICAL, default financial_services).
+	CompetitorExclusionsFile    string
+	CompetitorExclusionVertical string
+	// OrgVerticals assigns orgs to a vertical ("<org-uuid>=healthcare",
+	// ORG_VERTICALS), selecting the extraction prompt framing and competitor
+	// exclusion list until the API exposes a vertical field.
+	OrgVerticals map[string]string
+	// CompetitorListDelimiters are the characters a competitor list returned
+	// as one joined string is split on (COMPETITOR_LIST_DELIMITERS, with \n
+	// for a newline; default ",;\n").
+	CompetitorListDelimiters string
+	// CompetitorListMaxNames caps the competitors kept per response
+	// (COMPETITOR_LIST_MAX_NAMES); 0 keeps all.
+	CompetitorListMaxNames int
+	// CompetitorNameMaxLength drops competitor names longer than this many
+	// characters (COMPETITOR_NAME_MAX_LENGTH); 0 keeps all.
+	CompetitorNameMaxLength int
+	// CompetitorExtractionModel is the OpenAI model used for network org
+	// competitor extraction (COMPETITOR_EXTRACTION_MODEL, default gpt-4.1-mini).
+	CompetitorExtractionModel string
+	// AzureOpenAIFullDeploymentName is the Azure deployment for full-size
+	// extraction calls: evaluation, mentions, claims and citations
+	// (AZURE_OPENAI_FULL_DEPLOYMENT_NAME, defaults to AZURE_OPENAI_DEPLOYMENT_NAME).
+	AzureOpenAIFullDeploymentName string
+	// AzureOpenAIMiniDeploymentName is the Azure deployment for small
+	// extraction calls: competitors and name variations
+	// (AZURE_OPENAI_MINI_DEPLOYMENT_NAME, defaults to the full deployment).
+	AzureOpenAIMiniDeploymentName string
+	// CitationExtractionConcurrency bounds how many claims of one response
+	// have their citations extracted in parallel (CITATION_EXTRACTION_CONCURRENCY,
+	// default 4; 1 runs them sequentially).
+	CitationExtractionConcurrency int
+	// CitationRegexFallback adds the URLs around a claim as citations when the
+	// LLM returns none for it (CITATION_REGEX_FALLBACK, default off).
+	CitationRegexFallback bool
+	// ExtractionTemperature and NameVariationTemperature are the sampling
+	// temperatures of the extraction and name-variation calls
+	// (EXTRACTION_TEMPERATURE, default 0.1; NAME_VARIATION_TEMPERATURE, default
+	// 0.3). Reasoning models take no temperature and ignore them.
+	ExtractionTemperature    float64
+	NameVariationTemperature float64
+	// ExtractionParamStyles says which sampling parameter an extraction
+	// model or Azure deployment takes: "temperature" or "reasoning"
+	// (reasoning_effort), as "<model>=<style>" pairs (EXTRACTION_PARAM_STYLES).
+	// Unlisted models are guessed from their name; either way a model that
+	// rejects the parameter is retried once with the other.
+	ExtractionParamStyles map[string]string
+	// MentionsMaxTokens, ClaimsMaxTokens and CitationsMaxTokens cap the
+	// output (max_completion_tokens) of the mentions, claims and per-claim
+	// citations extraction calls (EXTRACTION_MENTIONS_MAX_TOKENS, default
+	// 8000; EXTRACTION_CLAIMS_MAX_TOKENS, default 8000;
+	// EXTRACTION_CITATIONS_MAX_TOKENS, default 2000). 0 leaves a call uncapped.
+	MentionsMaxTokens  int
+	ClaimsMaxTokens    int
+	CitationsMaxTokens int
+	Database           DatabaseConfig
+}
+
+// BrightDataDatasets are the BrightData dataset IDs the ChatGPT, Perplexity
+// and Gemini providers submit to.
+type BrightDataDatasets struct {
+	BrightDataDatasetID string
+	PerplexityDatasetID string
+	GeminiDatasetID     string
 }
 
 // DatabaseConfig matches the senso-api database configuration structure exactly
@@ -45,24 +222,73 @@ type DatabaseConfig struct {
 
 func Load() *Config {
 	config := &Config{
-		Port:                      getEnv("PORT", "8000"),
-		Environment:               getEnv("ENVIRONMENT", "development"),
-		InngestEventKey:           os.Getenv("INNGEST_EVENT_KEY"),
-		InngestSigningKey:         os.Getenv("INNGEST_SIGNING_KEY"),
-		OpenAIAPIKey:              os.Getenv("OPENAI_API_KEY"),
-		AnthropicAPIKey:           os.Getenv("ANTHROPIC_API_KEY"),
-		AzureOpenAIEndpoint:       os.Getenv("AZURE_OPENAI_ENDPOINT"),
-		AzureOpenAIKey:            os.Getenv("AZURE_OPENAI_KEY"),
-		AzureOpenAIDeploymentName: os.Getenv("AZURE_OPENAI_DEPLOYMENT_NAME"),
-		ApplicationAPIURL:         os.Getenv("APPLICATION_API_URL"),
-		DatabaseURL:               os.Getenv("DATABASE_URL"),
-		APIToken:                  os.Getenv("API_TOKEN"),
-		BrightDataAPIKey:          os.Getenv("BRIGHTDATA_API_KEY"),
-		BrightDataDatasetID:       os.Getenv("BRIGHTDATA_DATASET_ID"),
-		PerplexityDatasetID:       os.Getenv("PERPLEXITY_DATASET_ID"),
-		GeminiDatasetID:           os.Getenv("GEMINI_DATASET_ID"),
-		LinkupAPIKey:              os.Getenv("LINKUP_API_KEY"),
-		EnableScheduledPipelines:  getEnvBool("ENABLE_SCHEDULED_PIPELINES", true),
+		Port:                            getEnv("PORT", "8000"),
+		Environment:                     getEnv("ENVIRONMENT", "development"),
+		InngestEventKey:                 os.Getenv("INNGEST_EVENT_KEY"),
+		InngestSigningKey:               os.Getenv("INNGEST_SIGNING_KEY"),
+		OpenAIAPIKey:                    os.Getenv("OPENAI_API_KEY"),
+		AnthropicAPIKey:                 os.Getenv("ANTHROPIC_API_KEY"),
+		AzureOpenAIEndpoint:             os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		AzureOpenAIKey:                  os.Getenv("AZURE_OPENAI_KEY"),
+		AzureOpenAIDeploymentName:       os.Getenv("AZURE_OPENAI_DEPLOYMENT_NAME"),
+		ApplicationAPIURL:               os.Getenv("APPLICATION_API_URL"),
+		DatabaseURL:                     os.Getenv("DATABASE_URL"),
+		APIToken:                        os.Getenv("API_TOKEN"),
+		BrightDataAPIKey:                os.Getenv("BRIGHTDATA_API_KEY"),
+		BrightDataDatasetID:             os.Getenv("BRIGHTDATA_DATASET_ID"),
+		PerplexityDatasetID:             os.Getenv("PERPLEXITY_DATASET_ID"),
+		GeminiDatasetID:                 os.Getenv("GEMINI_DATASET_ID"),
+		LinkupAPIKey:                    os.Getenv("LINKUP_API_KEY"),
+		EnableScheduledPipelines:        getEnvBool("ENABLE_SCHEDULED_PIPELINES", true),
+		NetworkDatasets:                 getEnvNetworkDatasets("NETWORK_BRIGHTDATA_DATASETS"),
+		ModelProviderOverrides:          getEnvMap("MODEL_PROVIDER_OVERRIDES"),
+		OrgEvalSoftDeadlineMinutes:      getEnvInt("ORG_EVAL_SOFT_DEADLINE_MINUTES", 0),
+		CostDebug:                       getEnvBool("COST_DEBUG", false),
+		OpenAIMinResponseChars:          getEnvInt("OPENAI_MIN_RESPONSE_CHARS", 0),
+		MinExtractionResponseChars:      getEnvInt("MIN_EXTRACTION_RESPONSE_CHARS", 20),
+		MinMentionRunes:                 getEnvInt("MIN_MENTION_RUNES", 2),
+		AsyncPollInitialIntervalSeconds: getEnvInt("ASYNC_POLL_INITIAL_INTERVAL_SECONDS", 5),
+		AsyncPollMaxIntervalSeconds:     getEnvInt("ASYNC_POLL_MAX_INTERVAL_SECONDS", 60),
+		AsyncPollDeadlineMinutes:        getEnvInt("ASYNC_POLL_DEADLINE_MINUTES", 0),
+		BatchProgressFlushSeconds:       getEnvInt("BATCH_PROGRESS_FLUSH_SECONDS", 30),
+		BatchProgressFlushRuns:          getEnvInt("BATCH_PROGRESS_FLUSH_RUNS", 25),
+		OrgDetailsCacheTTLSeconds:       getEnvInt("ORG_DETAILS_CACHE_TTL_SECONDS", 0),
+		ReconcileAutoFix:                getEnvBool("RECONCILE_AUTO_FIX", false),
+		ReconcileLookbackDays:           getEnvInt("RECONCILE_LOOKBACK_DAYS", 7),
+		NetworkOrgDeadLetterMaxAttempts: getEnvInt("NETWORK_ORG_DEAD_LETTER_MAX_ATTEMPTS", 5),
+		NetworkOrgClaimTTLMinutes:       getEnvInt("NETWORK_ORG_CLAIM_TTL_MINUTES", 30),
+		NetworkRunReuse:                 getEnvBool("NETWORK_RUN_REUSE", false),
+		NetworkRunReuseOrgs:             getEnvMap("NETWORK_RUN_REUSE_ORGS"),
+		SOVNormalization:                getEnvBool("SOV_NORMALIZATION", false),
+		StoreTruncatedResponses:         getEnvBool("STORE_TRUNCATED_RESPONSES", false),
+		ExplicitDevelopment:             os.Getenv("ENVIRONMENT") == "development",
+		TestTriggerToken:                os.Getenv("TEST_TRIGGER_TOKEN"),
+		DebugEndpoints:                  getEnvBool("DEBUG_ENDPOINTS", false),
+		OrgDisabledStages:               getEnvMap("ORG_DISABLED_STAGES"),
+		NetworkQuestionSampling:         getEnvMap("NETWORK_QUESTION_SAMPLING"),
+		BatchDayTimezones:               getEnvTimezones("BATCH_DAY_TIMEZONES"),
+		PriorityQuestionTag:             getEnv("PRIORITY_QUESTION_TAG", "priority"),
+		UnsupportedWebSearchPolicy:      strings.ToLower(getEnv("UNSUPPORTED_WEB_SEARCH_POLICY", "skip")),
+		ProvidersConfigFile:             os.Getenv("PROVIDERS_CONFIG_FILE"),
+		ProviderReplayDir:               os.Getenv("PROVIDER_REPLAY_DIR"),
+		ProviderRecordDir:               os.Getenv("PROVIDER_RECORD_DIR"),
+		CompetitorExclusionsFile:        os.Getenv("COMPETITOR_EXCLUSIONS_FILE"),
+		CompetitorExclusionVertical:     getEnv("COMPETITOR_EXCLUSION_VERTICAL", "financial_services"),
+		OrgVerticals:                    getEnvMap("ORG_VERTICALS"),
+		CompetitorListDelimiters:        strings.ReplaceAll(getEnv("COMPETITOR_LIST_DELIMITERS", ",;\\n"), `\n`, "\n"),
+		CompetitorListMaxNames:          getEnvInt("COMPETITOR_LIST_MAX_NAMES", 0),
+		CompetitorNameMaxLength:         getEnvInt("COMPETITOR_NAME_MAX_LENGTH", 0),
+		CompetitorExtractionModel:       getEnv("COMPETITOR_EXTRACTION_MODEL", "gpt-4.1-mini"),
+		AzureOpenAIFullDeploymentName:   os.Getenv("AZURE_OPENAI_FULL_DEPLOYMENT_NAME"),
+		AzureOpenAIMiniDeploymentName:   os.Getenv("AZURE_OPENAI_MINI_DEPLOYMENT_NAME"),
+		CitationExtractionConcurrency:   getEnvInt("CITATION_EXTRACTION_CONCURRENCY", 4),
+		CitationRegexFallback:           getEnvBool("CITATION_REGEX_FALLBACK", false),
+		ExtractionTemperature:           getEnvFloat("EXTRACTION_TEMPERATURE", 0.1),
+		MentionsMaxTokens:               getEnvInt("EXTRACTION_MENTIONS_MAX_TOKENS", 8000),
+		ClaimsMaxTokens:                 getEnvInt("EXTRACTION_CLAIMS_MAX_TOKENS", 8000),
+		CitationsMaxTokens:              getEnvInt("EXTRACTION_CITATIONS_MAX_TOKENS", 2000),
+		NameVariationTemperature:        getEnvFloat("NAME_VARIATION_TEMPERATURE", 0.3),
+		ExtractionParamStyles:           getEnvMap("EXTRACTION_PARAM_STYLES"),
 	}
 
 	// Parse database configuration
@@ -137,6 +363,92 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
+// getEnvMap parses a comma-separated list of key=value pairs (e.g. "chatgpt=openai,foo=anthropic").
+// Keys and values are lowercased and trimmed; malformed entries are ignored.
+func getEnvMap(key string) map[string]string {
+	out := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return out
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// getEnvTimezones parses "<uuid>=<IANA zone>" pairs like getEnvMap, but keeps
+// the case of the zone names (Australia/Sydney). Zones are not validated here.
+func getEnvTimezones(key string) map[string]string {
+	out := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return out
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// getEnvNetworkDatasets parses per-network dataset overrides
+// ("<network-uuid>=chatgpt:gd_x|perplexity:gd_y|gemini:gd_z,..."). Network IDs
+// are lowercased; dataset IDs keep their case. Unknown providers and malformed
+// entries are ignored.
+func getEnvNetworkDatasets(key string) map[string]BrightDataDatasets {
+	out := make(map[string]BrightDataDatasets)
+	value := os.Getenv(key)
+	if value == "" {
+		return out
+	}
+	for _, pair := range strings.Split(value, ",") {
+		networkID, list, ok := strings.Cut(pair, "=")
+		networkID = strings.ToLower(strings.TrimSpace(networkID))
+		if !ok || networkID == "" {
+			continue
+		}
+		datasets := out[networkID]
+		for _, entry := range strings.Split(list, "|") {
+			provider, datasetID, ok := strings.Cut(entry, ":")
+			datasetID = strings.TrimSpace(datasetID)
+			if !ok || datasetID == "" {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(provider)) {
+			case "chatgpt", "brightdata":
+				datasets.BrightDataDatasetID = datasetID
+			case "perplexity":
+				datasets.PerplexityDatasetID = datasetID
+			case "gemini":
+				datasets.GeminiDatasetID = datasetID
+			}
+		}
+		out[networkID] = datasets
+	}
+	return out
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		switch value {
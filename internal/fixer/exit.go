@@ -0,0 +1,36 @@
+// internal/fixer/exit.go
+package fixer
+
+// Exit codes shared by the fixer tools, so cron/CI can tell a clean run from
+// one that left work undone:
+//
+//	0  every planned job succeeded (or the failures stayed within --fail-threshold)
+//	1  fatal setup error (bad flags, DB connect, provider init)
+//	2  partial failure: jobs failed or were not run before the timeout
+const (
+	ExitOK             = 0
+	ExitFatal          = 1
+	ExitPartialFailure = 2
+)
+
+// FailurePolicy decides when failed jobs turn into ExitPartialFailure.
+type FailurePolicy struct {
+	// FailOnAny exits non-zero on the first failed job; Threshold is ignored.
+	FailOnAny bool
+	// Threshold is the tolerated fraction (0-1) of failed jobs when FailOnAny is off.
+	Threshold float64
+}
+
+// ExitCode maps the run's failed/total job counts to an exit code.
+func (p FailurePolicy) ExitCode(failed, total int) int {
+	if failed <= 0 {
+		return ExitOK
+	}
+	if p.FailOnAny || total <= 0 {
+		return ExitPartialFailure
+	}
+	if float64(failed)/float64(total) > p.Threshold {
+		return ExitPartialFailure
+	}
+	return ExitOK
+}
@@ -0,0 +1,31 @@
+package fixer
+
+import "testing"
+
+func TestFailurePolicyExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy FailurePolicy
+		failed int
+		total  int
+		want   int
+	}{
+		{name: "all succeeded", policy: FailurePolicy{FailOnAny: true}, failed: 0, total: 10, want: ExitOK},
+		{name: "nothing to do", policy: FailurePolicy{FailOnAny: true}, failed: 0, total: 0, want: ExitOK},
+		{name: "fail on any", policy: FailurePolicy{FailOnAny: true}, failed: 1, total: 100, want: ExitPartialFailure},
+		{name: "fail on any ignores threshold", policy: FailurePolicy{FailOnAny: true, Threshold: 0.5}, failed: 1, total: 100, want: ExitPartialFailure},
+		{name: "zero threshold fails on any", policy: FailurePolicy{}, failed: 1, total: 100, want: ExitPartialFailure},
+		{name: "within threshold", policy: FailurePolicy{Threshold: 0.1}, failed: 5, total: 100, want: ExitOK},
+		{name: "at threshold", policy: FailurePolicy{Threshold: 0.1}, failed: 10, total: 100, want: ExitOK},
+		{name: "over threshold", policy: FailurePolicy{Threshold: 0.1}, failed: 11, total: 100, want: ExitPartialFailure},
+		{name: "every job failed", policy: FailurePolicy{Threshold: 0.99}, failed: 10, total: 10, want: ExitPartialFailure},
+		{name: "failures without a total", policy: FailurePolicy{Threshold: 1}, failed: 3, total: 0, want: ExitPartialFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ExitCode(tt.failed, tt.total); got != tt.want {
+				t.Errorf("%+v.ExitCode(%d, %d) = %d, want %d", tt.policy, tt.failed, tt.total, got, tt.want)
+			}
+		})
+	}
+}
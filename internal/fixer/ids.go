@@ -0,0 +1,71 @@
+// internal/fixer/ids.go
+package fixer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ID file formats accepted by ReadIDs.
+const (
+	IDFormatAuto  = "auto"
+	IDFormatPlain = "plain"
+	IDFormatJSONL = "jsonl"
+)
+
+// idLine is one JSONL record; fields other than id (priority, note, ...) are ignored.
+type idLine struct {
+	ID string `json:"id"`
+}
+
+// ReadIDs reads one ID per line from path. Blank lines and lines starting with
+// "#" are skipped. With format "plain" each line is the ID; with "jsonl" each
+// line is an object with an "id" field. "auto" (or "") decides per line: lines
+// starting with "{" are parsed as JSON, everything else as plain.
+func ReadIDs(path, format string) ([]string, error) {
+	switch format {
+	case "", IDFormatAuto, IDFormatPlain, IDFormatJSONL:
+	default:
+		return nil, fmt.Errorf("unknown id file format %q (want auto, plain or jsonl)", format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []string
+	sc := bufio.NewScanner(f)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		isJSON := format == IDFormatJSONL || ((format == "" || format == IDFormatAuto) && strings.HasPrefix(line, "{"))
+		if !isJSON {
+			out = append(out, line)
+			continue
+		}
+
+		var rec idLine
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid JSON line: %w", path, lineNo, err)
+		}
+		id := strings.TrimSpace(rec.ID)
+		if id == "" {
+			return nil, fmt.Errorf("%s:%d: JSON line has no \"id\" field", path, lineNo)
+		}
+		out = append(out, id)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
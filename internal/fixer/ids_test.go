@@ -0,0 +1,94 @@
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "plain",
+			format:  IDFormatPlain,
+			content: "# orgs to fix\norg-1\n\n  org-2  \n",
+			want:    []string{"org-1", "org-2"},
+		},
+		{
+			name:    "jsonl with extra fields",
+			format:  IDFormatJSONL,
+			content: "{\"id\": \"org-1\", \"priority\": 1}\n# comment\n\n{\"note\": \"re-run\", \"id\": \" org-2 \"}\n",
+			want:    []string{"org-1", "org-2"},
+		},
+		{
+			name:    "auto detects each line",
+			format:  IDFormatAuto,
+			content: "org-1\n{\"id\": \"org-2\", \"note\": \"x\"}\norg-3\n",
+			want:    []string{"org-1", "org-2", "org-3"},
+		},
+		{
+			name:    "empty format is auto",
+			content: "{\"id\": \"org-1\"}\norg-2\n",
+			want:    []string{"org-1", "org-2"},
+		},
+		{
+			name:    "plain keeps json-looking lines verbatim",
+			format:  IDFormatPlain,
+			content: "{\"id\": \"org-1\"}\n",
+			want:    []string{"{\"id\": \"org-1\"}"},
+		},
+		{
+			name:    "jsonl rejects a plain line",
+			format:  IDFormatJSONL,
+			content: "{\"id\": \"org-1\"}\norg-2\n",
+			wantErr: "ids.txt:2: invalid JSON line",
+		},
+		{
+			name:    "invalid json line",
+			format:  IDFormatAuto,
+			content: "org-1\n{\"id\": \"org-2\"\n",
+			wantErr: "ids.txt:2: invalid JSON line",
+		},
+		{
+			name:    "json line without id",
+			format:  IDFormatAuto,
+			content: "# header\n{\"org\": \"org-1\"}\n",
+			wantErr: "ids.txt:2: JSON line has no \"id\" field",
+		},
+		{
+			name:    "unknown format",
+			format:  "csv",
+			content: "org-1\n",
+			wantErr: "unknown id file format \"csv\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ids.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadIDs(path, tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadIDs() = %q, %v; want an error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadIDs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// internal/fixer/model_aliases.go
+package fixer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPerplexityAliases are the name fragments that mark a configured model
+// as a Perplexity model, e.g. "perplexity-sonar-pro", "pplx-sonar" or "sonar".
+var DefaultPerplexityAliases = ModelAliases{"perplexity", "pplx", "sonar"}
+
+// ModelAliases is a comma-separated list flag (flag.Value) of model name
+// fragments, e.g. --perplexity-aliases perplexity,pplx,sonar. Setting it
+// replaces the defaults rather than adding to them.
+type ModelAliases []string
+
+func (a *ModelAliases) String() string {
+	if a == nil {
+		return ""
+	}
+	return strings.Join(*a, ",")
+}
+
+func (a *ModelAliases) Set(value string) error {
+	var aliases ModelAliases
+	for _, part := range strings.Split(value, ",") {
+		alias := strings.ToLower(strings.TrimSpace(part))
+		if alias == "" {
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+	if len(aliases) == 0 {
+		return fmt.Errorf("invalid model alias list %q (want at least one alias)", value)
+	}
+	*a = aliases
+	return nil
+}
+
+// Matches reports whether modelName contains one of the aliases as a whole
+// word: case-insensitively, and not directly preceded or followed by another
+// letter. Digits and separators may border it, so "pplx-sonar" and "sonar2"
+// match "sonar" while "personar" and "sonaric" don't.
+func (a ModelAliases) Matches(modelName string) bool {
+	name := strings.ToLower(strings.TrimSpace(modelName))
+	for _, alias := range a {
+		alias = strings.ToLower(alias)
+		if alias != "" && containsWord(name, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split partitions modelNames into the ones Matches and the rest, keeping order.
+func (a ModelAliases) Split(modelNames []string) (matched, other []string) {
+	for _, name := range modelNames {
+		if a.Matches(name) {
+			matched = append(matched, name)
+		} else {
+			other = append(other, name)
+		}
+	}
+	return matched, other
+}
+
+func containsWord(s, word string) bool {
+	for start := 0; start <= len(s)-len(word); {
+		i := strings.Index(s[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(word)
+		if !letterAt(s, i-1) && !letterAt(s, end) {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+func letterAt(s string, i int) bool {
+	return i >= 0 && i < len(s) && s[i] >= 'a' && s[i] <= 'z'
+}
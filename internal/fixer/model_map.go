@@ -0,0 +1,75 @@
+// internal/fixer/model_map.go
+package fixer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelMapping routes configured models whose name contains Write to the
+// runtime API model API.
+type ModelMapping struct {
+	Write string
+	API   string
+}
+
+// ModelMap is a repeatable "write=api" flag (flag.Value), e.g.
+// --model-map chatgpt=gpt-5.2 --model-map chatgpt-4o=gpt-4o.
+type ModelMap []ModelMapping
+
+func (m *ModelMap) String() string {
+	if m == nil {
+		return ""
+	}
+	parts := make([]string, len(*m))
+	for i, mm := range *m {
+		parts[i] = mm.Write + "=" + mm.API
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m *ModelMap) Set(value string) error {
+	write, api, ok := strings.Cut(value, "=")
+	write = strings.TrimSpace(write)
+	api = strings.TrimSpace(api)
+	if !ok || write == "" || api == "" {
+		return fmt.Errorf("invalid model mapping %q (want write=api)", value)
+	}
+	for _, mm := range *m {
+		if strings.EqualFold(mm.Write, write) {
+			return fmt.Errorf("duplicate model mapping for %q", write)
+		}
+	}
+	*m = append(*m, ModelMapping{Write: write, API: api})
+	return nil
+}
+
+// Resolve picks the API model for a configured model name. Write keys match by
+// case-insensitive substring and the longest matching key wins, so
+// "chatgpt-4o" routes by the chatgpt-4o entry even though "chatgpt" also matches.
+func (m ModelMap) Resolve(modelName string) (ModelMapping, bool) {
+	name := strings.ToLower(strings.TrimSpace(modelName))
+	var best ModelMapping
+	found := false
+	for _, mm := range m {
+		key := strings.ToLower(mm.Write)
+		if strings.Contains(name, key) && (!found || len(key) > len(best.Write)) {
+			best = mm
+			found = true
+		}
+	}
+	return best, found
+}
+
+// APIModels returns the distinct API models in flag order.
+func (m ModelMap) APIModels() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, mm := range m {
+		if !seen[mm.API] {
+			seen[mm.API] = true
+			out = append(out, mm.API)
+		}
+	}
+	return out
+}
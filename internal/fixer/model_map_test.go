@@ -0,0 +1,82 @@
+package fixer
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModelMapFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    ModelMap
+		wantErr string
+	}{
+		{
+			name: "repeated",
+			args: []string{"--model-map", "chatgpt=gpt-5.2", "--model-map", " chatgpt-4o = gpt-4o "},
+			want: ModelMap{{Write: "chatgpt", API: "gpt-5.2"}, {Write: "chatgpt-4o", API: "gpt-4o"}},
+		},
+		{name: "none", args: nil, want: nil},
+		{name: "missing api model", args: []string{"--model-map", "chatgpt="}, wantErr: `invalid model mapping "chatgpt="`},
+		{name: "no separator", args: []string{"--model-map", "chatgpt"}, wantErr: `invalid model mapping "chatgpt"`},
+		{name: "duplicate write model", args: []string{"--model-map", "chatgpt=gpt-5.2", "--model-map", "ChatGPT=gpt-4o"}, wantErr: `duplicate model mapping for "ChatGPT"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m ModelMap
+			fs := flag.NewFlagSet("fixer", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(&m, "model-map", "write=api mapping")
+
+			err := fs.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(m, tt.want) {
+				t.Errorf("model map = %+v, want %+v", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelMapResolve(t *testing.T) {
+	m := ModelMap{{Write: "chatgpt", API: "gpt-5.2"}, {Write: "chatgpt-4o", API: "gpt-4o"}, {Write: "gpt", API: "gpt-5.2"}}
+
+	tests := []struct {
+		model   string
+		want    string
+		wantOK  bool
+		wantKey string
+	}{
+		{model: "chatgpt", want: "gpt-5.2", wantOK: true, wantKey: "chatgpt"},
+		{model: "ChatGPT-4o", want: "gpt-4o", wantOK: true, wantKey: "chatgpt-4o"},
+		{model: " chatgpt-4o-mini ", want: "gpt-4o", wantOK: true, wantKey: "chatgpt-4o"},
+		{model: "gpt-4.1", want: "gpt-5.2", wantOK: true, wantKey: "gpt"},
+		{model: "perplexity", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got, ok := m.Resolve(tt.model)
+			if ok != tt.wantOK || got.API != tt.want || got.Write != tt.wantKey {
+				t.Errorf("Resolve(%q) = %+v, %t; want %s=%s, %t", tt.model, got, ok, tt.wantKey, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got, want := m.APIModels(), []string{"gpt-5.2", "gpt-4o"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("APIModels() = %q, want %q", got, want)
+	}
+	if got, want := m.String(), "chatgpt=gpt-5.2,chatgpt-4o=gpt-4o,gpt=gpt-5.2"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+// internal/fixer/network_runs.go
+package fixer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Getter is the sqlx single-row query method (satisfied by *sqlx.DB and *database.Client).
+type Getter interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// NetworkHasRuns reports whether any question of the network has ever been run.
+// A brand-new network has none, so the per-question scans for today's batch and
+// for existing runs can be skipped entirely.
+func NetworkHasRuns(ctx context.Context, db Getter, networkID uuid.UUID) (bool, error) {
+	var exists bool
+	err := db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM question_runs qr
+			JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+			WHERE gq.network_id = $1
+		)`, networkID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check network runs: %w", err)
+	}
+	return exists, nil
+}
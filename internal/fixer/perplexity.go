@@ -0,0 +1,297 @@
+// internal/fixer/perplexity.go
+package fixer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PerplexityTimeout bounds one streamed sonar call. Long questions can take
+// 3-4 minutes, so whatever has streamed by then is returned as a truncated
+// response instead of being thrown away.
+const PerplexityTimeout = 5 * time.Minute
+
+// HTTPStatusError is a non-2xx provider response. Do stops retrying on the
+// ones a retry can't fix (bad request, auth, content policy).
+type HTTPStatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s http %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// authFailure reports whether the key was rejected (401/403).
+func (e *HTTPStatusError) authFailure() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// Retryable reports whether the status is a rate limit, timeout or server error.
+func (e *HTTPStatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode >= 500
+}
+
+type perplexityChatRequest struct {
+	Model     string              `json:"model"`
+	Messages  []PerplexityMessage `json:"messages"`
+	Stream    bool                `json:"stream"`
+	MaxTokens int                 `json:"max_tokens,omitempty"`
+}
+
+type PerplexityMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type PerplexityUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	Cost             struct {
+		TotalCost float64 `json:"total_cost"`
+	} `json:"cost"`
+}
+
+type PerplexityChoice struct {
+	Message      PerplexityMessage `json:"message"`
+	Delta        PerplexityMessage `json:"delta"`
+	FinishReason string            `json:"finish_reason"`
+}
+
+// PerplexityChatResponse is the accumulated result of a streamed chat
+// completion. Truncated is set when the stream was cut off (deadline or early
+// EOF) before a finish_reason arrived, in which case Usage holds the last usage
+// the stream reported and may undercount the partial output, and when the
+// answer hit max_tokens (finish_reason "length", see LengthLimited).
+type PerplexityChatResponse struct {
+	Model     string             `json:"model"`
+	Usage     PerplexityUsage    `json:"usage"`
+	Citations []string           `json:"citations"`
+	Choices   []PerplexityChoice `json:"choices"`
+	Truncated bool               `json:"-"`
+}
+
+// LengthLimited reports whether the answer was cut at max_tokens.
+func (r *PerplexityChatResponse) LengthLimited() bool {
+	return len(r.Choices) > 0 && r.Choices[0].FinishReason == "length"
+}
+
+// PerplexityClient calls the Perplexity chat completions API directly,
+// shared by the Perplexity fixer tools. MaxTokens 0 leaves the answer length
+// to the API default.
+//
+// Clients from NewPerplexityClientFromEnv rotate over PERPLEXITY_API_KEYS
+// (comma-separated; PERPLEXITY_API_KEY alone still works) round-robin per
+// request. A key answered with 401/403 is skipped for PerplexityKeyCooldown
+// and the request is retried on the next key. A client without a key pool
+// uses APIKey.
+type PerplexityClient struct {
+	APIKey     string
+	BaseURL    string
+	Model      string
+	MaxTokens  int
+	Timeout    time.Duration
+	HTTPClient *http.Client
+
+	keys *perplexityKeyPool
+}
+
+func NewPerplexityClientFromEnv() (*PerplexityClient, error) {
+	keys := parsePerplexityKeys(os.Getenv("PERPLEXITY_API_KEYS"))
+	if len(keys) == 0 {
+		apiKey := strings.TrimSpace(os.Getenv("PERPLEXITY_API_KEY"))
+		if apiKey == "" {
+			return nil, fmt.Errorf("neither PERPLEXITY_API_KEYS nor PERPLEXITY_API_KEY is set")
+		}
+		keys = []string{apiKey}
+	}
+	baseURL := strings.TrimSpace(os.Getenv("PERPLEXITY_BASE_URL"))
+	if baseURL == "" {
+		baseURL = "https://api.perplexity.ai"
+	}
+	model := strings.TrimSpace(os.Getenv("PERPLEXITY_CHAT_MODEL"))
+	if model == "" {
+		model = "sonar"
+	}
+	maxTokens := 0
+	if v := strings.TrimSpace(os.Getenv("PERPLEXITY_MAX_TOKENS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("PERPLEXITY_MAX_TOKENS must be a non-negative integer, got %q", v)
+		}
+		maxTokens = n
+	}
+
+	return &PerplexityClient{
+		APIKey:    keys[0],
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		Model:     model,
+		MaxTokens: maxTokens,
+		Timeout:   PerplexityTimeout,
+		// No client-level timeout: the per-call context deadline is what
+		// lets a slow stream be returned as partial content.
+		HTTPClient: &http.Client{},
+		keys:       newPerplexityKeyPool(keys, PerplexityKeyCooldown),
+	}, nil
+}
+
+// KeyUsage renders the per-key request and auth failure counts (keys masked)
+// for logs; empty for a client without a key pool.
+func (c *PerplexityClient) KeyUsage() string {
+	if c.keys == nil {
+		return ""
+	}
+	return c.keys.usage()
+}
+
+// ChatCompletion streams a chat completion and accumulates the content chunks.
+// If the call deadline (or ctx) expires mid-stream after some content arrived,
+// the partial response is returned with Truncated=true and a nil error; the
+// caller decides whether to keep it.
+func (c *PerplexityClient) ChatCompletion(ctx context.Context, prompt string) (*PerplexityChatResponse, error) {
+	reqBody := perplexityChatRequest{
+		Model: c.Model,
+		Messages: []PerplexityMessage{
+			{Role: "user", Content: prompt},
+		},
+		Stream:    true,
+		MaxTokens: c.MaxTokens,
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	attempts := 1
+	if c.keys != nil {
+		attempts = len(c.keys.keys)
+	}
+	for attempt := 1; ; attempt++ {
+		apiKey := c.APIKey
+		var key *perplexityKey
+		if c.keys != nil {
+			key = c.keys.pick()
+			apiKey = key.value
+		}
+		resp, err := c.chatCompletion(ctx, bodyBytes, apiKey)
+		var statusErr *HTTPStatusError
+		if key == nil || !errors.As(err, &statusErr) || !statusErr.authFailure() {
+			return resp, err
+		}
+		// The key was rejected (often mid-rotation): cool it down, try the next one
+		c.keys.demote(key)
+		if attempt >= attempts {
+			return nil, err
+		}
+	}
+}
+
+// chatCompletion sends one streamed chat completion request with apiKey.
+func (c *PerplexityClient) chatCompletion(ctx context.Context, bodyBytes []byte, apiKey string) (*PerplexityChatResponse, error) {
+	url := c.BaseURL + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("accept", "text/event-stream")
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("authorization", "Bearer "+apiKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var b bytes.Buffer
+		_, _ = b.ReadFrom(resp.Body)
+		return nil, &HTTPStatusError{Provider: "perplexity", StatusCode: resp.StatusCode, Body: strings.TrimSpace(b.String())}
+	}
+
+	return readPerplexityStream(ctx, resp.Body)
+}
+
+// readPerplexityStream accumulates an SSE chat completion stream ("data: {...}"
+// lines, optionally terminated by "data: [DONE]").
+func readPerplexityStream(ctx context.Context, body io.Reader) (*PerplexityChatResponse, error) {
+	out := &PerplexityChatResponse{}
+	var content strings.Builder
+	role := "assistant"
+	finishReason := ""
+
+	reader := bufio.NewReader(body)
+	var readErr error
+	for {
+		line, err := reader.ReadString('\n')
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data:"); ok {
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+			var chunk PerplexityChatResponse
+			if jsonErr := json.Unmarshal([]byte(data), &chunk); jsonErr != nil {
+				return nil, fmt.Errorf("decode stream chunk: %w", jsonErr)
+			}
+			if chunk.Model != "" {
+				out.Model = chunk.Model
+			}
+			if chunk.Usage.TotalTokens > 0 || chunk.Usage.Cost.TotalCost > 0 {
+				out.Usage = chunk.Usage
+			}
+			if len(chunk.Citations) > 0 {
+				out.Citations = chunk.Citations
+			}
+			if len(chunk.Choices) > 0 {
+				choice := chunk.Choices[0]
+				if choice.Delta.Role != "" {
+					role = choice.Delta.Role
+				}
+				content.WriteString(choice.Delta.Content)
+				if choice.FinishReason != "" {
+					finishReason = choice.FinishReason
+				}
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				readErr = err
+			}
+			break
+		}
+	}
+
+	if readErr != nil && (ctx.Err() == nil || content.Len() == 0) {
+		return nil, fmt.Errorf("read stream: %w", readErr)
+	}
+	if content.Len() == 0 && finishReason == "" {
+		return nil, fmt.Errorf("perplexity stream ended without content")
+	}
+
+	out.Truncated = finishReason == "" || finishReason == "length"
+	out.Choices = []PerplexityChoice{{
+		Message:      PerplexityMessage{Role: role, Content: content.String()},
+		FinishReason: finishReason,
+	}}
+	return out, nil
+}
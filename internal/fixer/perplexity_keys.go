@@ -0,0 +1,107 @@
+// internal/fixer/perplexity_keys.go
+package fixer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PerplexityKeyCooldown is how long a key that returned 401/403 is skipped.
+// Keys are rotated on the Perplexity side, so a rejected key usually works
+// again (or has been replaced) once the rotation window is over.
+const PerplexityKeyCooldown = 10 * time.Minute
+
+// perplexityKeyPool hands out API keys round-robin and skips keys demoted
+// after an auth failure until their cooldown ends. It is safe for concurrent
+// use.
+type perplexityKeyPool struct {
+	mu       sync.Mutex
+	keys     []*perplexityKey
+	next     int
+	cooldown time.Duration
+	now      func() time.Time
+}
+
+type perplexityKey struct {
+	value          string
+	unhealthyUntil time.Time
+	requests       int
+	authFailures   int
+}
+
+func newPerplexityKeyPool(keys []string, cooldown time.Duration) *perplexityKeyPool {
+	p := &perplexityKeyPool{cooldown: cooldown, now: time.Now}
+	for _, k := range keys {
+		p.keys = append(p.keys, &perplexityKey{value: k})
+	}
+	return p
+}
+
+// pick returns the next healthy key. When every key is cooling down, the one
+// whose cooldown ends first is used rather than failing outright.
+func (p *perplexityKeyPool) pick() *perplexityKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var fallback *perplexityKey
+	for i := 0; i < len(p.keys); i++ {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if !now.Before(k.unhealthyUntil) {
+			p.next = (p.next + i + 1) % len(p.keys)
+			k.requests++
+			return k
+		}
+		if fallback == nil || k.unhealthyUntil.Before(fallback.unhealthyUntil) {
+			fallback = k
+		}
+	}
+	fallback.requests++
+	return fallback
+}
+
+// demote marks key unhealthy for the pool's cooldown.
+func (p *perplexityKeyPool) demote(key *perplexityKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key.authFailures++
+	key.unhealthyUntil = p.now().Add(p.cooldown)
+}
+
+// usage renders per-key request and auth failure counts, keys masked, e.g.
+// "***a1b2:req=120,auth_fail=0 ***c3d4:req=118,auth_fail=2".
+func (p *perplexityKeyPool) usage() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	parts := make([]string, len(p.keys))
+	for i, k := range p.keys {
+		parts[i] = fmt.Sprintf("%s:req=%d,auth_fail=%d", maskKey(k.value), k.requests, k.authFailures)
+	}
+	return strings.Join(parts, " ")
+}
+
+// maskKey keeps the last 4 characters of a key for logs.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "***"
+	}
+	return "***" + key[len(key)-4:]
+}
+
+// parsePerplexityKeys splits PERPLEXITY_API_KEYS (comma-separated), dropping
+// blanks and duplicates while keeping the order.
+func parsePerplexityKeys(value string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,145 @@
+package fixer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseChunk renders one stream chunk with a content delta.
+func sseChunk(content, finishReason string, usageTokens int) string {
+	usage := ""
+	if usageTokens > 0 {
+		usage = fmt.Sprintf(`,"usage":{"prompt_tokens":10,"completion_tokens":%d,"total_tokens":%d}`, usageTokens, usageTokens+10)
+	}
+	return fmt.Sprintf(`data: {"model":"sonar","citations":["https://acme.example"],"choices":[{"delta":{"role":"assistant","content":%q},"finish_reason":%q}]%s}`+"\n\n", content, finishReason, usage)
+}
+
+func TestPerplexityChatCompletionStream(t *testing.T) {
+	tests := []struct {
+		name string
+		// chunks are written and flushed in order; hang then keeps the
+		// stream open until the client gives up.
+		chunks        []string
+		hang          bool
+		status        int
+		wantContent   string
+		wantTruncated bool
+		wantLength    bool
+		wantTokens    int
+		wantErr       string
+	}{
+		{
+			name:        "complete stream",
+			chunks:      []string{sseChunk("Acme Bank ", "", 0), sseChunk("is best.", "", 0), sseChunk("", "stop", 42), "data: [DONE]\n\n"},
+			wantContent: "Acme Bank is best.",
+			wantTokens:  52,
+		},
+		{
+			name:        "complete without done marker",
+			chunks:      []string{sseChunk("Acme Bank.", "stop", 5)},
+			wantContent: "Acme Bank.",
+			wantTokens:  15,
+		},
+		{
+			name:          "deadline mid-stream keeps the partial answer",
+			chunks:        []string{sseChunk("Acme Bank ", "", 3), sseChunk("has the", "", 0)},
+			hang:          true,
+			wantContent:   "Acme Bank has the",
+			wantTruncated: true,
+			wantTokens:    13,
+		},
+		{
+			name:          "stream closed before finishing",
+			chunks:        []string{sseChunk("Acme Bank ", "", 0)},
+			wantContent:   "Acme Bank ",
+			wantTruncated: true,
+		},
+		{
+			name:          "cut at max tokens",
+			chunks:        []string{sseChunk("Acme Bank has", "length", 8), "data: [DONE]\n\n"},
+			wantContent:   "Acme Bank has",
+			wantTruncated: true,
+			wantLength:    true,
+			wantTokens:    18,
+		},
+		{
+			name:    "deadline before any content",
+			hang:    true,
+			wantErr: "read stream",
+		},
+		{
+			name:    "empty stream",
+			chunks:  []string{"data: [DONE]\n\n"},
+			wantErr: "ended without content",
+		},
+		{
+			name:    "rejected key",
+			status:  http.StatusUnauthorized,
+			wantErr: "perplexity http 401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/chat/completions" || r.Header.Get("authorization") != "Bearer test-key" {
+					t.Errorf("request %s with authorization %q", r.URL.Path, r.Header.Get("authorization"))
+				}
+				if tt.status != 0 {
+					http.Error(w, "invalid api key", tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				for _, chunk := range tt.chunks {
+					fmt.Fprint(w, chunk)
+					w.(http.Flusher).Flush()
+				}
+				if tt.hang {
+					select {
+					case <-r.Context().Done():
+					case <-stop:
+					}
+				}
+			}))
+			defer server.Close()
+			defer close(stop)
+
+			client := &PerplexityClient{APIKey: "test-key", BaseURL: server.URL, Model: "sonar", Timeout: 200 * time.Millisecond, HTTPClient: server.Client()}
+			resp, err := client.ChatCompletion(context.Background(), "Which bank is best?")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ChatCompletion() = %+v, %v; want an error containing %q", resp, err, tt.wantErr)
+				}
+				var statusErr *HTTPStatusError
+				if tt.status != 0 && (!errors.As(err, &statusErr) || statusErr.Retryable()) {
+					t.Errorf("error %v is not a non-retryable HTTPStatusError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ChatCompletion() error = %v", err)
+			}
+			if got := resp.Choices[0].Message.Content; got != tt.wantContent {
+				t.Errorf("content = %q, want %q", got, tt.wantContent)
+			}
+			if resp.Truncated != tt.wantTruncated || resp.LengthLimited() != tt.wantLength {
+				t.Errorf("Truncated = %t, LengthLimited = %t; want %t, %t", resp.Truncated, resp.LengthLimited(), tt.wantTruncated, tt.wantLength)
+			}
+			if resp.Usage.TotalTokens != tt.wantTokens {
+				t.Errorf("usage total tokens = %d, want %d", resp.Usage.TotalTokens, tt.wantTokens)
+			}
+			if resp.Model != "sonar" || !reflect.DeepEqual(resp.Citations, []string{"https://acme.example"}) {
+				t.Errorf("model %q, citations %q; want sonar and the streamed citation", resp.Model, resp.Citations)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// internal/fixer/plan.go
+package fixer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlanEntry is one question run a fixer would create.
+type PlanEntry struct {
+	Scope      string `json:"scope"` // "org" or "network"
+	ScopeID    string `json:"scope_id"`
+	QuestionID string `json:"question_id"`
+	Model      string `json:"model"`
+	Country    string `json:"country"`
+	Region     string `json:"region,omitempty"`
+}
+
+// Plan collects the jobs a dry run would execute plus skip counts per reason,
+// so dry-run output can be reviewed and diffed between runs. A nil *Plan is a
+// no-op, which lets callers record unconditionally.
+type Plan struct {
+	mu      sync.Mutex
+	Entries []PlanEntry    `json:"entries"`
+	Skipped map[string]int `json:"skipped"`
+}
+
+func NewPlan() *Plan {
+	return &Plan{Skipped: make(map[string]int)}
+}
+
+// Add records a job that would be created.
+func (p *Plan) Add(entry PlanEntry) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Entries = append(p.Entries, entry)
+}
+
+// Skip records n skipped jobs (or scopes) for reason.
+func (p *Plan) Skip(reason string, n int) {
+	if p == nil || n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Skipped[reason] += n
+}
+
+// SkipAll records every reason of a scope's skip counts.
+func (p *Plan) SkipAll(counts SkipCounts) {
+	for reason, n := range counts {
+		p.Skip(reason, n)
+	}
+}
+
+// WriteFile writes the plan as CSV when path ends in ".csv", JSON otherwise.
+// Entries are sorted so two plans for the same input diff cleanly.
+func (p *Plan) WriteFile(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sort.Slice(p.Entries, func(i, j int) bool {
+		a, b := p.Entries[i], p.Entries[j]
+		if a.ScopeID != b.ScopeID {
+			return a.ScopeID < b.ScopeID
+		}
+		if a.QuestionID != b.QuestionID {
+			return a.QuestionID < b.QuestionID
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		if a.Country != b.Country {
+			return a.Country < b.Country
+		}
+		return a.Region < b.Region
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create plan file: %w", err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := csv.NewWriter(f)
+		_ = w.Write([]string{"scope", "scope_id", "question_id", "model", "country", "region"})
+		for _, e := range p.Entries {
+			_ = w.Write([]string{e.Scope, e.ScopeID, e.QuestionID, e.Model, e.Country, e.Region})
+		}
+		// Skip counts go in trailing comment-style rows so the file stays one table.
+		reasons := make([]string, 0, len(p.Skipped))
+		for reason := range p.Skipped {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			_ = w.Write([]string{"#skipped", reason, fmt.Sprintf("%d", p.Skipped[reason]), "", "", ""})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(p); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package fixer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// buildPlan plans questions × models × locations of one org the way the fixers
+// do: combinations in existing already ran today and are skipped, the rest
+// are planned. Jobs are added in reverse so WriteFile has to sort them.
+func buildPlan(questions, models []string, locations [][2]string, existing map[string]bool) *Plan {
+	plan := NewPlan()
+	skips := SkipCounts{}
+	var entries []PlanEntry
+	for _, q := range questions {
+		for _, m := range models {
+			for _, loc := range locations {
+				if existing[q+"|"+m+"|"+loc[0]+loc[1]] {
+					skips.Add(SkipExistingRun, 1)
+					continue
+				}
+				entries = append(entries, PlanEntry{Scope: "org", ScopeID: "org-1", QuestionID: q, Model: m, Country: loc[0], Region: loc[1]})
+			}
+		}
+	}
+	skips.Add(SkipInvalidQuestion, 1)
+	plan.SkipAll(skips)
+	for i := len(entries) - 1; i >= 0; i-- {
+		plan.Add(entries[i])
+	}
+	return plan
+}
+
+func TestPlanWriteFile(t *testing.T) {
+	questions := []string{"q1", "q2"}
+	models := []string{"chatgpt", "perplexity"}
+	locations := [][2]string{{"US", ""}, {"US", "Texas"}}
+	existing := map[string]bool{
+		"q1|chatgpt|US":         true,
+		"q2|perplexity|USTexas": true,
+		"q2|chatgpt|US":         true,
+	}
+	wantEntries := [][]string{
+		{"org", "org-1", "q1", "chatgpt", "US", "Texas"},
+		{"org", "org-1", "q1", "perplexity", "US", ""},
+		{"org", "org-1", "q1", "perplexity", "US", "Texas"},
+		{"org", "org-1", "q2", "chatgpt", "US", "Texas"},
+		{"org", "org-1", "q2", "perplexity", "US", ""},
+	}
+	wantSkipped := map[string]int{SkipExistingRun: 3, SkipInvalidQuestion: 1}
+
+	t.Run("csv", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plan.csv")
+		if err := buildPlan(questions, models, locations, existing).WriteFile(path); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		rows, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			t.Fatalf("plan is not valid CSV: %v", err)
+		}
+		want := [][]string{{"scope", "scope_id", "question_id", "model", "country", "region"}}
+		want = append(want, wantEntries...)
+		want = append(want,
+			[]string{"#skipped", SkipExistingRun, "3", "", "", ""},
+			[]string{"#skipped", SkipInvalidQuestion, "1", "", "", ""},
+		)
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("plan rows =\n%q\nwant\n%q", rows, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plan.json")
+		if err := buildPlan(questions, models, locations, existing).WriteFile(path); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Plan
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("plan is not valid JSON: %v", err)
+		}
+		var entries [][]string
+		for _, e := range got.Entries {
+			entries = append(entries, []string{e.Scope, e.ScopeID, e.QuestionID, e.Model, e.Country, e.Region})
+		}
+		if !reflect.DeepEqual(entries, wantEntries) {
+			t.Errorf("entries =\n%q\nwant\n%q", entries, wantEntries)
+		}
+		if !reflect.DeepEqual(got.Skipped, wantSkipped) {
+			t.Errorf("skipped = %v, want %v", got.Skipped, wantSkipped)
+		}
+	})
+
+	t.Run("same input diffs cleanly", func(t *testing.T) {
+		dir := t.TempDir()
+		a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+		if err := buildPlan(questions, models, locations, existing).WriteFile(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := buildPlan(questions, models, locations, existing).WriteFile(b); err != nil {
+			t.Fatal(err)
+		}
+		first, _ := os.ReadFile(a)
+		second, _ := os.ReadFile(b)
+		if string(first) != string(second) {
+			t.Errorf("two plans of the same input differ:\n%s\n---\n%s", first, second)
+		}
+	})
+}
+
+func TestNilPlanIsNoop(t *testing.T) {
+	var plan *Plan
+	plan.Add(PlanEntry{QuestionID: "q1"})
+	plan.Skip(SkipExistingRun, 2)
+	plan.SkipAll(SkipCounts{SkipDuplicateJob: 1})
+}
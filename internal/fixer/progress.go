@@ -0,0 +1,78 @@
+// internal/fixer/progress.go
+package fixer
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Progress counts the outcomes of one org/network's jobs while they run, for
+// the heartbeat log. It is safe for concurrent use.
+type Progress struct {
+	total    int64
+	created  atomic.Int64
+	failed   atomic.Int64
+	costBits atomic.Uint64
+}
+
+// NewProgress returns a Progress for total jobs.
+func NewProgress(total int) *Progress {
+	return &Progress{total: int64(total)}
+}
+
+// Created records a created run and its cost.
+func (p *Progress) Created(cost float64) {
+	p.created.Add(1)
+	for {
+		old := p.costBits.Load()
+		if p.costBits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+cost)) {
+			return
+		}
+	}
+}
+
+// Failed records a failed job.
+func (p *Progress) Failed() {
+	p.failed.Add(1)
+}
+
+// String renders "created=X failed=Y remaining=Z cost=$C".
+func (p *Progress) String() string {
+	created, failed := p.created.Load(), p.failed.Load()
+	return fmt.Sprintf("created=%d failed=%d remaining=%d cost=$%.6f",
+		created, failed, p.total-created-failed, math.Float64frombits(p.costBits.Load()))
+}
+
+// StartHeartbeat calls logf with "in-progress: <progress>" every interval
+// until the returned stop function is called, so long runs don't look hung
+// between job completions. stop waits for the heartbeat goroutine to exit and
+// may be called more than once. interval <= 0 disables the heartbeat.
+func StartHeartbeat(interval time.Duration, progress *Progress, logf func(line string)) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logf("in-progress: " + progress.String())
+			}
+		}
+	}()
+	var stopped atomic.Bool
+	return func() {
+		if stopped.CompareAndSwap(false, true) {
+			close(done)
+		}
+		<-exited
+	}
+}
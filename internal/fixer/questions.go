@@ -0,0 +1,11 @@
+// internal/fixer/questions.go
+package fixer
+
+// CapQuestions returns at most max questions, keeping the loaded order, for
+// the --max-questions testing cap. max <= 0 means no cap.
+func CapQuestions[T any](questions []T, max int) []T {
+	if max <= 0 || len(questions) <= max {
+		return questions
+	}
+	return questions[:max]
+}
@@ -0,0 +1,22 @@
+// internal/fixer/response.go
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyResponse is returned for provider responses too short to store.
+var ErrEmptyResponse = errors.New("empty response from provider")
+
+// CheckResponseText rejects a response that is empty or shorter than
+// minChars after trimming, so fixers never insert a run the pipeline would
+// refuse to extract from (see MIN_EXTRACTION_RESPONSE_CHARS).
+func CheckResponseText(text string, minChars int) error {
+	n := len(strings.TrimSpace(text))
+	if n == 0 || n < minChars {
+		return fmt.Errorf("%w (%d chars)", ErrEmptyResponse, n)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// internal/fixer/retry.go
+package fixer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudget caps the total number of retries shared by every job of a single
+// org/network. Once exhausted, failing calls return immediately instead of
+// retrying, so a provider outage can't turn into an hour-long retry thrash.
+// A nil *RetryBudget means "unlimited".
+type RetryBudget struct {
+	remaining atomic.Int64
+	used      atomic.Int64
+}
+
+// NewRetryBudget returns a budget allowing at most n retries (n <= 0 disables retries).
+func NewRetryBudget(n int) *RetryBudget {
+	b := &RetryBudget{}
+	b.remaining.Store(int64(n))
+	return b
+}
+
+// Take reserves one retry from the budget. It returns false once the budget is spent.
+func (b *RetryBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		cur := b.remaining.Load()
+		if cur <= 0 {
+			return false
+		}
+		if b.remaining.CompareAndSwap(cur, cur-1) {
+			b.used.Add(1)
+			return true
+		}
+	}
+}
+
+// Used returns how many retries have been consumed so far.
+func (b *RetryBudget) Used() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.used.Load())
+}
+
+// Exhausted reports whether no retries remain.
+func (b *RetryBudget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	return b.remaining.Load() <= 0
+}
+
+// Do calls fn up to 1+maxRetries times, drawing every retry from budget. A
+// linear backoff (attempt * backoff) is applied between attempts. The last
+// error is returned when all attempts fail, the budget runs out, ctx is done,
+// or the error reports itself as not retryable (see IsRetryable).
+func Do(ctx context.Context, maxRetries int, backoff time.Duration, budget *RetryBudget, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt >= maxRetries || ctx.Err() != nil || !IsRetryable(err) {
+			return err
+		}
+		if !budget.Take() {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt+1) * backoff):
+		}
+	}
+}
+
+// IsRetryable reports whether err may succeed on a retry. Errors that carry a
+// Retryable() bool method (such as services.ProviderError) decide for
+// themselves; any other error is assumed transient.
+func IsRetryable(err error) bool {
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package fixer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type retryableError struct{ retryable bool }
+
+func (e retryableError) Error() string   { return "provider error" }
+func (e retryableError) Retryable() bool { return e.retryable }
+
+func TestDo(t *testing.T) {
+	errTransient := errors.New("transient")
+
+	tests := []struct {
+		name        string
+		maxRetries  int
+		budget      *RetryBudget
+		failures    int // calls that fail before fn succeeds
+		err         error
+		wantCalls   int
+		wantErr     bool
+		wantUsed    int
+		wantExhaust bool
+	}{
+		{name: "first call succeeds", maxRetries: 3, budget: NewRetryBudget(5), failures: 0, err: errTransient, wantCalls: 1},
+		{name: "succeeds on a retry", maxRetries: 3, budget: NewRetryBudget(5), failures: 2, err: errTransient, wantCalls: 3, wantUsed: 2},
+		{name: "max retries reached", maxRetries: 2, budget: NewRetryBudget(5), failures: 10, err: errTransient, wantCalls: 3, wantErr: true, wantUsed: 2},
+		{name: "budget runs out first", maxRetries: 5, budget: NewRetryBudget(1), failures: 10, err: errTransient, wantCalls: 2, wantErr: true, wantUsed: 1, wantExhaust: true},
+		{name: "zero budget disables retries", maxRetries: 5, budget: NewRetryBudget(0), failures: 10, err: errTransient, wantCalls: 1, wantErr: true, wantExhaust: true},
+		{name: "nil budget is unlimited", maxRetries: 3, budget: nil, failures: 10, err: errTransient, wantCalls: 4, wantErr: true},
+		{name: "not retryable stops at once", maxRetries: 3, budget: NewRetryBudget(5), failures: 10, err: retryableError{retryable: false}, wantCalls: 1, wantErr: true},
+		{name: "retryable provider error retries", maxRetries: 1, budget: NewRetryBudget(5), failures: 1, err: retryableError{retryable: true}, wantCalls: 2, wantUsed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), tt.maxRetries, time.Microsecond, tt.budget, func(ctx context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if got := tt.budget.Used(); got != tt.wantUsed {
+				t.Errorf("Used() = %d, want %d", got, tt.wantUsed)
+			}
+			if got := tt.budget.Exhausted(); got != tt.wantExhaust {
+				t.Errorf("Exhausted() = %t, want %t", got, tt.wantExhaust)
+			}
+		})
+	}
+}
+
+func TestDoStopsAtTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		backoff time.Duration
+		block   bool // fn waits for the deadline instead of failing at once
+	}{
+		// The deadline passes during the first backoff wait
+		{name: "deadline during backoff", timeout: 20 * time.Millisecond, backoff: time.Hour},
+		// fn itself outlives the deadline, so there is no retry
+		{name: "deadline during the call", timeout: 10 * time.Millisecond, backoff: time.Microsecond, block: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			calls := 0
+			start := time.Now()
+			err := Do(ctx, 10, tt.backoff, nil, func(ctx context.Context) error {
+				calls++
+				if tt.block {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return errors.New("transient")
+			})
+			if err == nil {
+				t.Fatal("Do() error = nil, want the last error")
+			}
+			if calls != 1 {
+				t.Errorf("calls = %d, want 1", calls)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Do() returned after %s, want it to stop at the deadline", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryBudgetShared(t *testing.T) {
+	budget := NewRetryBudget(3)
+	taken := 0
+	for i := 0; i < 5; i++ {
+		if budget.Take() {
+			taken++
+		}
+	}
+	if taken != 3 || budget.Used() != 3 || !budget.Exhausted() {
+		t.Errorf("taken = %d, Used() = %d, Exhausted() = %t; want 3, 3, true", taken, budget.Used(), budget.Exhausted())
+	}
+}
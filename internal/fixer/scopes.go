@@ -0,0 +1,17 @@
+// internal/fixer/scopes.go
+package fixer
+
+import "context"
+
+// EachScope calls run for each org or network in order, and starts no new one
+// once ctx is done (the --timeout expired). It returns how many were left
+// unprocessed, so the caller can report them.
+func EachScope[T any](ctx context.Context, scopes []T, run func(idx int, scope T)) int {
+	for idx, scope := range scopes {
+		if ctx.Err() != nil {
+			return len(scopes) - idx
+		}
+		run(idx, scope)
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package fixer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestEachScope(t *testing.T) {
+	networks := []string{"network-a", "network-b", "network-c"}
+
+	tests := []struct {
+		name            string
+		cancelAfter     int // cancel ctx once this many networks ran; -1 never
+		wantQueried     []string
+		wantUnprocessed int
+	}{
+		{name: "all networks", cancelAfter: -1, wantQueried: networks},
+		{name: "canceled after the first network", cancelAfter: 1, wantQueried: networks[:1], wantUnprocessed: 2},
+		{name: "canceled during the last network", cancelAfter: 3, wantQueried: networks},
+		{name: "canceled before the start", cancelAfter: 0, wantQueried: nil, wantUnprocessed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter == 0 {
+				cancel()
+			}
+
+			var queried []string
+			unprocessed := EachScope(ctx, networks, func(idx int, network string) {
+				if idx != len(queried) {
+					t.Errorf("network %s got index %d, want %d", network, idx, len(queried))
+				}
+				queried = append(queried, network)
+				if len(queried) == tt.cancelAfter {
+					cancel()
+				}
+			})
+
+			if !reflect.DeepEqual(queried, tt.wantQueried) {
+				t.Errorf("queried %v, want %v", queried, tt.wantQueried)
+			}
+			if unprocessed != tt.wantUnprocessed {
+				t.Errorf("unprocessed = %d, want %d", unprocessed, tt.wantUnprocessed)
+			}
+		})
+	}
+}
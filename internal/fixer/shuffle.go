@@ -0,0 +1,37 @@
+// internal/fixer/shuffle.go
+package fixer
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Shuffler randomizes the order jobs are dispatched in. Jobs are built in
+// discovery order (model, location, question), so during an outage the same
+// early questions always fail first; shuffling spreads that across reruns.
+// The seed is kept so a run's order can be reproduced. A nil *Shuffler keeps
+// the order unchanged.
+type Shuffler struct {
+	Seed int64
+	rng  *rand.Rand
+}
+
+// NewShuffler returns a shuffler seeded with seed, or with the current time
+// when seed is 0.
+func NewShuffler(seed int64) *Shuffler {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Shuffler{Seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Shuffle permutes jobs in place. Scopes shuffled in the same order with the
+// same seed get the same permutations. Not safe for concurrent use.
+func Shuffle[T any](s *Shuffler, jobs []T) {
+	if s == nil {
+		return
+	}
+	s.rng.Shuffle(len(jobs), func(i, j int) {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	})
+}
@@ -0,0 +1,53 @@
+// internal/fixer/skips.go
+package fixer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reasons a job is left out of an org/network's missing-job list.
+const (
+	SkipExistingRun     = "existing_run_today"
+	SkipInvalidQuestion = "invalid_question_text"
+	SkipDuplicateJob    = "duplicate_job"
+)
+
+// SkipCounts counts the skipped jobs of one org/network per reason, for the
+// breakdown logged when the scope is done.
+type SkipCounts map[string]int
+
+// Add records n skipped jobs for reason.
+func (c SkipCounts) Add(reason string, n int) {
+	if n <= 0 {
+		return
+	}
+	c[reason] += n
+}
+
+// Total is the number of skipped jobs over all reasons.
+func (c SkipCounts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// String renders the counts as "reason:n,..." sorted by reason, or "none".
+func (c SkipCounts) String() string {
+	if len(c) == 0 {
+		return "none"
+	}
+	reasons := make([]string, 0, len(c))
+	for reason := range c {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s:%d", reason, c[reason])
+	}
+	return strings.Join(parts, ",")
+}
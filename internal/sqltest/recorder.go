@@ -0,0 +1,118 @@
+// internal/sqltest/recorder.go
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/jmoiron/sqlx"
+)
+
+// Recorder is a database/sql connector that records statements instead of
+// running them, so SQL paths can be tested without Postgres. Transaction
+// boundaries are recorded as BEGIN, COMMIT and ROLLBACK. Exec, when set,
+// decides each Exec's rows affected or error; queries return no rows.
+type Recorder struct {
+	Exec func(query string, args []any) (int64, error)
+
+	mu    sync.Mutex
+	stmts []Statement
+}
+
+// Statement is one recorded statement, its text collapsed to one line.
+type Statement struct {
+	Query string
+	Args  []any
+}
+
+// NewClient returns a database client whose database is r, closed when t ends.
+func NewClient(t testing.TB, r *Recorder) *database.Client {
+	t.Helper()
+	db := sqlx.NewDb(sql.OpenDB(r), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return &database.Client{DB: db}
+}
+
+// Statements returns the recorded statements, in order.
+func (r *Recorder) Statements() []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Statement(nil), r.stmts...)
+}
+
+// Queries returns the recorded statements' text, in order.
+func (r *Recorder) Queries() []string {
+	var queries []string
+	for _, stmt := range r.Statements() {
+		queries = append(queries, stmt.Query)
+	}
+	return queries
+}
+
+func (r *Recorder) record(query string, args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	r.mu.Lock()
+	r.stmts = append(r.stmts, Statement{Query: strings.Join(strings.Fields(query), " "), Args: values})
+	r.mu.Unlock()
+	return values
+}
+
+func (r *Recorder) Connect(context.Context) (driver.Conn, error) { return &conn{r: r}, nil }
+func (r *Recorder) Driver() driver.Driver                        { return recorderDriver{} }
+
+type recorderDriver struct{}
+
+func (recorderDriver) Open(string) (driver.Conn, error) { return nil, driver.ErrSkip }
+
+type conn struct{ r *Recorder }
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *conn) Close() error                              { return nil }
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.r.record("BEGIN", nil)
+	return tx{r: c.r}, nil
+}
+
+// CheckNamedValue passes every argument to the recorder unconverted.
+func (c *conn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values := c.r.record(query, args)
+	var affected int64
+	if c.r.Exec != nil {
+		var err error
+		if affected, err = c.r.Exec(strings.Join(strings.Fields(query), " "), values); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.r.record(query, args)
+	return emptyRows{}, nil
+}
+
+type tx struct{ r *Recorder }
+
+func (t tx) Commit() error   { t.r.record("COMMIT", nil); return nil }
+func (t tx) Rollback() error { t.r.record("ROLLBACK", nil); return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
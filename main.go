@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -58,6 +59,8 @@ func logAIServiceConfiguration(cfg *config.Config) {
 	log.Printf("  - Endpoint: %s", cfg.AzureOpenAIEndpoint)
 	log.Printf("  - API Key: %s", ifString(cfg.AzureOpenAIKey != "", "SET", "NOT SET"))
 	log.Printf("  - Deployment Name: %s", cfg.AzureOpenAIDeploymentName)
+	log.Printf("  - Full Deployment (evaluation, mentions, claims, citations): %s", ifString(cfg.AzureOpenAIFullDeploymentName != "", cfg.AzureOpenAIFullDeploymentName, "NOT SET (uses Deployment Name)"))
+	log.Printf("  - Mini Deployment (competitors, name variations): %s", ifString(cfg.AzureOpenAIMiniDeploymentName != "", cfg.AzureOpenAIMiniDeploymentName, "NOT SET (uses Full Deployment)"))
 	log.Printf("  - Fully Configured: %t", azureConfigured)
 
 	log.Printf("Standard OpenAI Configuration:")
@@ -99,6 +102,9 @@ func ifString(condition bool, trueVal, falseVal string) string {
 }
 
 func main() {
+	providersConfig := flag.String("providers-config", "", "JSON/YAML file defining model provider, capabilities and pricing (overrides PROVIDERS_CONFIG_FILE)")
+	flag.Parse()
+
 	// Load environment variables from .env file first (standard practice)
 	// If not found, try dev.env for local development
 	if err := godotenv.Load(); err != nil {
@@ -124,6 +130,21 @@ func main() {
 	// Log AI service configuration
 	logAIServiceConfiguration(cfg)
 
+	if *providersConfig != "" {
+		cfg.ProvidersConfigFile = *providersConfig
+	}
+	if pc, err := services.LoadProvidersConfig(cfg.ProvidersConfigFile); err != nil {
+		log.Fatalf("Failed to load providers config: %v", err)
+	} else if pc != nil {
+		log.Printf("✅ Loaded providers config %s (%d models)", cfg.ProvidersConfigFile, len(pc.Models))
+	}
+
+	// Fail fast if a response struct no longer produces a strict-mode schema
+	if err := services.CheckResponseSchemas(); err != nil {
+		log.Fatalf("Structured output schema self-test failed: %v", err)
+	}
+	log.Printf("✅ Structured output schemas passed self-test")
+
 	// Initialize database connection using our custom function
 	ctx := context.Background()
 	dbClient, err := createDatabaseClient(ctx, cfg.Database)
@@ -180,7 +201,7 @@ func main() {
 		usageService,
 		cfg,
 	)
-	scheduledProcessor := workflows.NewScheduledProcessor(orgService, repoManager)
+	scheduledProcessor := workflows.NewScheduledProcessor(cfg, orgService, repoManager)
 	networkProcessor := workflows.NewNetworkProcessor( // ** THIS IS THE NETWORK QUESTION RUNNER **
 		questionRunnerService,
 		usageService,
@@ -197,7 +218,7 @@ func main() {
 	)
 
 	// Initialize org re-evaluation processor
-	orgReevalProcessor := workflows.NewOrgReevalProcessor(cfg, orgService, orgEvaluationService)
+	orgReevalProcessor := workflows.NewOrgReevalProcessor(cfg, orgService, orgEvaluationService, questionRunnerService)
 
 	// Initialize network org re-evaluation processor (enhanced)
 	networkOrgReevalProcessor := workflows.NewNetworkOrgReevalProcessor(cfg, orgService, orgEvaluationService, questionRunnerService)
@@ -231,6 +252,7 @@ func main() {
 		scheduledProcessor.DailyOrgProcessor()
 		scheduledProcessor.DailyNetworkProcessor()
 		scheduledProcessor.WeeklyLoadAnalyzer()
+		scheduledProcessor.WeeklyReconciliation()
 	} else {
 		log.Printf("Scheduled pipelines disabled via ENABLE_SCHEDULED_PIPELINES=false")
 	}
@@ -240,6 +262,7 @@ func main() {
 	orgReevalProcessor.ProcessOrgReeval()
 	networkOrgReevalProcessor.ProcessNetworkOrgReeval()
 	networkOrgMissingProcessor.ProcessNetworkOrgMissing()
+	networkOrgMissingProcessor.ReplayNetworkOrgDeadLetters()
 	dummyProcessor.ProcessDummy()
 
 	// Create handler
@@ -263,101 +286,11 @@ func main() {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
-	// Test endpoint to trigger ProcessOrg workflow
-	mux.HandleFunc("/test/trigger-org", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Create test event
-		testOrgID := "test-org-123"
-		evt := inngestgo.Event{
-			Name: "org.process",
-			Data: map[string]interface{}{
-				"org_id":       testOrgID,
-				"triggered_by": "manual_test",
-				"user_id":      "test-user",
-			},
-		}
-
-		// Send event
-		result, err := client.Send(r.Context(), evt)
-		if err != nil {
-			log.Printf("Failed to send test event: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf(`{"error":"Failed to send event: %v"}`, err)))
-			return
-		}
-
-		log.Printf("Test event sent successfully: %+v", result)
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(fmt.Sprintf(`{"status":"success","message":"Test event sent for org %s","event_ids":["%s"]}`, testOrgID, result)))
-	})
-
-	// Test endpoint to trigger ProcessOrgEvaluation workflow
-	mux.HandleFunc("/test/trigger-org-evaluation", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
+	// Test trigger endpoints (dev only, or bearer-token protected)
+	registerTestTriggers(mux, cfg, client)
 
-		// Create test event for org evaluation
-		testOrgID := "test-org-123"
-		evt := inngestgo.Event{
-			Name: "org.evaluation.process",
-			Data: map[string]interface{}{
-				"org_id":       testOrgID,
-				"triggered_by": "manual_test",
-				"user_id":      "test-user",
-			},
-		}
-
-		// Send event
-		result, err := client.Send(r.Context(), evt)
-		if err != nil {
-			log.Printf("Failed to send org evaluation test event: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf(`{"error":"Failed to send event: %v"}`, err)))
-			return
-		}
-
-		log.Printf("Org evaluation test event sent successfully: %+v", result)
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(fmt.Sprintf(`{"status":"success","message":"Org evaluation test event sent for org %s","event_ids":["%s"]}`, testOrgID, result)))
-	})
-
-	// Test endpoint to trigger ProcessOrgReeval workflow
-	mux.HandleFunc("/test/trigger-org-reeval", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Create test event for org re-evaluation
-		testOrgID := "test-org-123"
-		evt := inngestgo.Event{
-			Name: "org.reeval.all.process",
-			Data: map[string]interface{}{
-				"org_id":       testOrgID,
-				"triggered_by": "manual_test",
-				"user_id":      "test-user",
-			},
-		}
-
-		// Send event
-		result, err := client.Send(r.Context(), evt)
-		if err != nil {
-			log.Printf("Failed to send org re-evaluation test event: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf(`{"error":"Failed to send event: %v"}`, err)))
-			return
-		}
-
-		log.Printf("Org re-evaluation test event sent successfully: %+v", result)
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(fmt.Sprintf(`{"status":"success","message":"Org re-evaluation test event sent for org %s","event_ids":["%s"]}`, testOrgID, result)))
-	})
+	// Synchronous single-question runs for support (dev only, or flag + bearer token)
+	registerDebugEndpoints(mux, cfg, questionRunnerService)
 
 	// Start server
 	port := cfg.Port
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS question_run_stage_status (
+    question_run_id  UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+    mentions_status  TEXT NOT NULL DEFAULT 'pending',
+    claims_status    TEXT NOT NULL DEFAULT 'pending',
+    citations_status TEXT NOT NULL DEFAULT 'pending',
+    metrics_status   TEXT NOT NULL DEFAULT 'pending',
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
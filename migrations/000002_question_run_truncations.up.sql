@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS question_run_truncations (
+    question_run_id UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+    output_tokens   INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
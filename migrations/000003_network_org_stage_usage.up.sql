@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS network_org_stage_usage (
+    question_run_id UUID NOT NULL REFERENCES question_runs(question_run_id),
+    org_id          UUID NOT NULL,
+    stage           TEXT NOT NULL, -- evaluation | competitors | citations
+    status          TEXT NOT NULL DEFAULT 'ok', -- ok | skipped_plan | filtered | empty_response
+    model           TEXT NOT NULL DEFAULT '',
+    input_tokens    INTEGER NOT NULL DEFAULT 0,
+    output_tokens   INTEGER NOT NULL DEFAULT 0,
+    total_cost      NUMERIC NOT NULL DEFAULT 0,
+    duration_ms     BIGINT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_run_id, org_id, stage)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS weekly_load_reports (
+    window_start TIMESTAMPTZ PRIMARY KEY,
+    window_end   TIMESTAMPTZ NOT NULL,
+    report       JSONB NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
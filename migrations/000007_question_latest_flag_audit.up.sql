@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS question_latest_flag_audit (
+    audit_id        UUID PRIMARY KEY,
+    geo_question_id UUID NOT NULL,
+    previous_run_id UUID,
+    new_run_id      UUID NOT NULL,
+    batch_id        UUID,
+    source          TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS question_latest_flag_audit_geo_question_id_created_at_idx ON question_latest_flag_audit (geo_question_id, created_at);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS question_run_batch_sampling (
+    batch_id           UUID PRIMARY KEY,
+    sampling_fraction  DOUBLE PRECISION NOT NULL,
+    rotation_days      INT NOT NULL,
+    rotation_day       INT NOT NULL,
+    selected_questions INT NOT NULL,
+    total_questions    INT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    generated_at TIMESTAMPTZ PRIMARY KEY,
+    report       JSONB NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS question_run_batch_errors (
+    batch_error_id  UUID PRIMARY KEY,
+    batch_id        UUID NOT NULL,
+    geo_question_id UUID,
+    model           TEXT NOT NULL,
+    location        TEXT NOT NULL,
+    error_code      TEXT NOT NULL,
+    message         TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS question_run_batch_errors_batch_id_created_at_idx ON question_run_batch_errors (batch_id, created_at);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS question_run_sov (
+    question_run_id UUID PRIMARY KEY,
+    raw_sov         DOUBLE PRECISION NOT NULL,
+    normalized_sov  DOUBLE PRECISION NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
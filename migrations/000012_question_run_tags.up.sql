@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS question_run_tags (
+    question_run_id UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+    tags            TEXT[] NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
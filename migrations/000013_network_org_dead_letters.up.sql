@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS network_org_dead_letters (
+    question_run_id UUID NOT NULL,
+    org_id          UUID NOT NULL,
+    error           TEXT NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 1,
+    first_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_failed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_run_id, org_id)
+);
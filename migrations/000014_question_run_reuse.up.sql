@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS question_run_reuse (
+    question_run_id        UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+    source_question_run_id UUID NOT NULL REFERENCES question_runs(question_run_id),
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
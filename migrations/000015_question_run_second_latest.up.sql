@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS question_run_second_latest (
+    question_run_id UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+    geo_question_id UUID NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_run_second_latest_question
+    ON question_run_second_latest (geo_question_id);
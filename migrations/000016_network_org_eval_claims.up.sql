@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS network_org_eval_claims (
+    question_run_id UUID NOT NULL,
+    org_id          UUID NOT NULL,
+    claimed_by      TEXT NOT NULL,
+    claimed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_run_id, org_id)
+);
@@ -0,0 +1,230 @@
+// pkg/trigger/trigger.go
+
+// Package trigger sends the Inngest events that start senso-workflows
+// pipelines. It owns the event names and payload schemas: the workflow
+// processors decode exactly these types, so services that enqueue runs
+// programmatically don't have to hand-build event maps.
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngestgo"
+)
+
+// Event names the workflow processors are triggered by.
+const (
+	EventOrgProcess           = "org.process"
+	EventOrgEvaluation        = "org.evaluation.process"
+	EventOrgReeval            = "org.reeval.all.process"
+	EventNetworkQuestions     = "network.questions.process"
+	EventNetworkOrgProcess    = "network.org.process"
+	EventNetworkOrgMissing    = "network.org.missing.process"
+	EventNetworkOrgReeval     = "network.org.reeval"
+	EventNetworkOrgReevalFull = "network.org.reeval.enhanced"
+	EventNetworkOrgDeadLetter = "network.org.dead_letters.replay"
+	EventDummyOrgProcess      = "dummy.org.process"
+)
+
+// Common triggered_by values. TriggeredByAPI is used when none is given.
+const (
+	TriggeredByAPI             = "api"
+	TriggeredByManualTest      = "manual_test"
+	TriggeredByScheduler       = "automatic_scheduler"
+	TriggeredByNetworkComplete = "network_completion"
+)
+
+// Sender sends one event and returns its ID; inngestgo.Client implements it.
+type Sender interface {
+	Send(ctx context.Context, evt any) (string, error)
+}
+
+// OrgProcessEvent starts the full org pipeline (EventOrgProcess).
+type OrgProcessEvent struct {
+	OrgID         string `json:"org_id"`
+	TriggeredBy   string `json:"triggered_by"`
+	UserID        string `json:"user_id,omitempty"`
+	ScheduledDate string `json:"scheduled_date,omitempty"`
+}
+
+// OrgEvaluationProcessEvent starts an org evaluation run (EventOrgEvaluation).
+type OrgEvaluationProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// OrgReevalProcessEvent re-evaluates an org's runs (EventOrgReeval).
+type OrgReevalProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	// RepairFailedStages re-runs only the failed/pending extraction stages
+	// (mentions, claims, citations, metrics) instead of re-evaluating every run.
+	RepairFailedStages bool `json:"repair_failed_stages,omitempty"`
+}
+
+// NetworkProcessEvent runs a network's question matrix (EventNetworkQuestions).
+type NetworkProcessEvent struct {
+	NetworkID   string `json:"network_id"`
+	TriggeredBy string `json:"triggered_by"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// NetworkOrgProcessEvent evaluates an org's network runs (EventNetworkOrgProcess).
+type NetworkOrgProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// NetworkOrgMissingProcessEvent evaluates the network runs an org has no
+// evaluation for yet (EventNetworkOrgMissing).
+type NetworkOrgMissingProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by"`
+	UserID      string `json:"user_id,omitempty"`
+	// NetworkID is informational; the processor resolves the org's network.
+	NetworkID string `json:"network_id,omitempty"`
+}
+
+// NetworkReevalProcessEvent re-evaluates an org's network runs (EventNetworkOrgReeval).
+type NetworkReevalProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// NetworkOrgReevalProcessEvent re-runs the full network org extraction
+// (EventNetworkOrgReevalFull).
+type NetworkOrgReevalProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// NetworkOrgDeadLetterReplayEvent retries an org's failed network org
+// extractions (EventNetworkOrgDeadLetter).
+type NetworkOrgDeadLetterReplayEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+}
+
+// DummyProcessEvent triggers the scheduler test workflow (EventDummyOrgProcess).
+type DummyProcessEvent struct {
+	OrgID       string `json:"org_id"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
+}
+
+// TriggerOrgProcess sends EventOrgProcess and returns the event ID.
+func TriggerOrgProcess(ctx context.Context, s Sender, evt OrgProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventOrgProcess, evt)
+}
+
+// TriggerOrgEvaluation sends EventOrgEvaluation and returns the event ID.
+func TriggerOrgEvaluation(ctx context.Context, s Sender, evt OrgEvaluationProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventOrgEvaluation, evt)
+}
+
+// TriggerOrgReeval sends EventOrgReeval and returns the event ID.
+func TriggerOrgReeval(ctx context.Context, s Sender, evt OrgReevalProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventOrgReeval, evt)
+}
+
+// TriggerNetworkQuestions sends EventNetworkQuestions and returns the event ID.
+func TriggerNetworkQuestions(ctx context.Context, s Sender, evt NetworkProcessEvent) (string, error) {
+	if err := normalize("network_id", &evt.NetworkID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventNetworkQuestions, evt)
+}
+
+// TriggerNetworkOrgProcess sends EventNetworkOrgProcess and returns the event ID.
+func TriggerNetworkOrgProcess(ctx context.Context, s Sender, evt NetworkOrgProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventNetworkOrgProcess, evt)
+}
+
+// TriggerNetworkOrgMissing sends EventNetworkOrgMissing and returns the event ID.
+func TriggerNetworkOrgMissing(ctx context.Context, s Sender, evt NetworkOrgMissingProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	if evt.NetworkID != "" {
+		if err := normalizeID("network_id", &evt.NetworkID); err != nil {
+			return "", err
+		}
+	}
+	return send(ctx, s, EventNetworkOrgMissing, evt)
+}
+
+// TriggerNetworkReeval sends EventNetworkOrgReeval and returns the event ID.
+func TriggerNetworkReeval(ctx context.Context, s Sender, evt NetworkReevalProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventNetworkOrgReeval, evt)
+}
+
+// TriggerNetworkOrgReeval sends EventNetworkOrgReevalFull and returns the event ID.
+func TriggerNetworkOrgReeval(ctx context.Context, s Sender, evt NetworkOrgReevalProcessEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventNetworkOrgReevalFull, evt)
+}
+
+// TriggerNetworkOrgDeadLetterReplay sends EventNetworkOrgDeadLetter and returns the event ID.
+func TriggerNetworkOrgDeadLetterReplay(ctx context.Context, s Sender, evt NetworkOrgDeadLetterReplayEvent) (string, error) {
+	if err := normalize("org_id", &evt.OrgID, &evt.TriggeredBy); err != nil {
+		return "", err
+	}
+	return send(ctx, s, EventNetworkOrgDeadLetter, evt)
+}
+
+// normalize validates and canonicalizes the event's ID field and defaults an
+// empty triggered_by.
+func normalize(field string, id *string, triggeredBy *string) error {
+	if err := normalizeID(field, id); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*triggeredBy) == "" {
+		*triggeredBy = TriggeredByAPI
+	}
+	return nil
+}
+
+func normalizeID(field string, id *string) error {
+	parsed, err := uuid.Parse(strings.TrimSpace(*id))
+	if err != nil {
+		return fmt.Errorf("%s must be a valid UUID: %w", field, err)
+	}
+	*id = parsed.String()
+	return nil
+}
+
+func send[T any](ctx context.Context, s Sender, name string, data T) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("no event sender for %s", name)
+	}
+	id, err := s.Send(ctx, inngestgo.GenericEvent[T]{Name: name, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s event: %w", name, err)
+	}
+	return id, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
 	"github.com/AI-Template-SDK/senso-workflows/internal/models"
@@ -35,7 +36,10 @@ func (p *anthropicProvider) GetProviderName() string {
 	return "anthropic"
 }
 
-func (p *anthropicProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *models.Location) (*AIResponse, error) {
+func (p *anthropicProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *models.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	// Build location-aware prompt
 	prompt := p.buildLocationPrompt(query, location)
 
@@ -69,9 +73,10 @@ Remember: Return ONLY the JSON object, no other text.`, query)
 		Role: anthropic.MessageParamRoleUser,
 	}}
 
+	maxTokens := maxOutputTokens(p.model, 2000)
 	response, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:       anthropic.Model(p.model),
-		MaxTokens:   2000,
+		MaxTokens:   int64(maxTokens),
 		Messages:    messages,
 		Temperature: anthropic.Float(0.7),
 	})
@@ -92,6 +97,9 @@ Remember: Return ONLY the JSON object, no other text.`, query)
 		Cost:                    p.costService.CalculateCost(p.GetProviderName(), p.model, int(response.Usage.InputTokens), int(response.Usage.OutputTokens), false),
 		ShouldProcessEvaluation: true,
 	}
+	if response.StopReason == anthropic.StopReasonMaxTokens {
+		markLengthTruncated(result, "AnthropicProvider", p.model, maxTokens)
+	}
 
 	return result, nil
 }
@@ -194,13 +202,24 @@ func (p *anthropicProvider) SupportsBatching() bool {
 	return false
 }
 
+// SupportsWebSearch returns false for Anthropic: RunQuestion ignores websearch (no search tool wired up yet)
+func (p *anthropicProvider) SupportsWebSearch() bool {
+	return false
+}
+
 // GetMaxBatchSize returns 1 for Anthropic (no batching)
 func (p *anthropicProvider) GetMaxBatchSize() int {
 	return 1
 }
 
+// RunQuestionBatchAwait calls RunQuestionBatch: Anthropic answers synchronously
+func (p *anthropicProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *models.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatch(ctx, batch, websearch, location)
+}
+
 // RunQuestionBatch processes questions sequentially for Anthropic (no batching support)
-func (p *anthropicProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *models.Location) ([]*AIResponse, error) {
+func (p *anthropicProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *models.Location) ([]*AIResponse, error) {
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[AnthropicProvider] 🔄 Processing %d questions sequentially (no batching support)\n", len(queries))
 
 	responses := make([]*AIResponse, len(queries))
@@ -212,5 +231,6 @@ func (p *anthropicProvider) RunQuestionBatch(ctx context.Context, queries []stri
 		responses[i] = response
 	}
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
@@ -0,0 +1,114 @@
+// services/async_poll.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// The BrightData-backed providers (brightdata, perplexity, gemini) submit a
+// job and poll its progress until the snapshot is ready. Polling starts at
+// PollInitialInterval and doubles after every poll up to PollMaxInterval, so
+// short jobs finish quickly and long ones don't hammer the progress endpoint.
+
+// AsyncJobWaiter is implemented by providers whose questions run as async
+// jobs. WaitForJob blocks until the job is ready, failed, or the poll
+// deadline or ctx expires.
+type AsyncJobWaiter interface {
+	WaitForJob(ctx context.Context, jobID string) error
+}
+
+var (
+	_ AsyncJobWaiter = (*brightDataProvider)(nil)
+	_ AsyncJobWaiter = (*perplexityProvider)(nil)
+	_ AsyncJobWaiter = (*geminiProvider)(nil)
+)
+
+// PollConfig controls how async provider jobs are polled.
+type PollConfig struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// Deadline bounds the whole wait; 0 waits until ctx is done.
+	Deadline time.Duration
+}
+
+func pollConfigFromConfig(cfg *config.Config) PollConfig {
+	return PollConfig{
+		InitialInterval: time.Duration(cfg.AsyncPollInitialIntervalSeconds) * time.Second,
+		MaxInterval:     time.Duration(cfg.AsyncPollMaxIntervalSeconds) * time.Second,
+		Deadline:        time.Duration(cfg.AsyncPollDeadlineMinutes) * time.Minute,
+	}
+}
+
+// next returns the interval after current: doubled, capped at MaxInterval.
+func (c PollConfig) next(current time.Duration) time.Duration {
+	next := current * 2
+	if c.MaxInterval > 0 && next > c.MaxInterval {
+		next = c.MaxInterval
+	}
+	return next
+}
+
+// pollJobStatus calls check with backoff until it reports "ready" (nil) or
+// "failed" (error). Failed checks are logged and retried. logTag prefixes the
+// progress lines, e.g. "BrightDataProvider".
+func pollJobStatus(ctx context.Context, cfg PollConfig, logTag, jobID string, check func(ctx context.Context) (string, error)) error {
+	if cfg.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Deadline)
+		defer cancel()
+	}
+	interval := cfg.InitialInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	pollCount := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("job %s not ready after %d polls: %w", jobID, pollCount, ctx.Err())
+		case <-timer.C:
+		}
+		pollCount++
+		status, err := check(ctx)
+		switch {
+		case err != nil:
+			fmt.Printf("[%s] ⚠️ Progress check failed (attempt %d), retrying: %v\n", logTag, pollCount, err)
+		case status == "ready":
+			fmt.Printf("[%s] ✅ Job completed after %d polls, retrieving results\n", logTag, pollCount)
+			return nil
+		case status == "failed":
+			return fmt.Errorf("%s job failed for snapshot %s", logTag, jobID)
+		default:
+			fmt.Printf("[%s] 📊 Job status: %s (poll #%d, next in %s)\n", logTag, status, pollCount, cfg.next(interval))
+		}
+		interval = cfg.next(interval)
+		timer.Reset(interval)
+	}
+}
+
+// awaitJob runs one async batch job to completion for RunQuestionBatchAwait:
+// submit it, wait for it with waiter's backoff, then retrieve its results.
+// provider names the provider in logs and errors, e.g. "BrightData".
+func awaitJob[R any](ctx context.Context, waiter AsyncJobWaiter, provider string, submit func(ctx context.Context) (string, error), retrieve func(ctx context.Context, jobID string) (R, error)) (R, error) {
+	var results R
+	jobID, err := submit(ctx)
+	if err != nil {
+		return results, fmt.Errorf("failed to submit %s batch job: %w", provider, err)
+	}
+	fmt.Printf("[%sProvider] 📋 Batch job submitted with snapshot ID: %s\n", provider, jobID)
+
+	if err := waiter.WaitForJob(ctx, jobID); err != nil {
+		return results, fmt.Errorf("failed to poll %s batch job: %w", provider, err)
+	}
+	if results, err = retrieve(ctx, jobID); err != nil {
+		return results, fmt.Errorf("failed to retrieve %s batch job: %w", provider, err)
+	}
+	return results, nil
+}
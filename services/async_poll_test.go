@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+// asyncFake is an async batch provider: a submitted job is ready after
+// readyAfter progress checks (or fails when failJob is set), and its results
+// come back in reverse order, as a snapshot may return them.
+type asyncFake struct {
+	AIProvider
+	readyAfter int
+	failJob    bool
+	submitErr  error
+
+	steps  []string
+	checks int
+	jobs   map[string][]BatchQuery
+}
+
+func (f *asyncFake) WaitForJob(ctx context.Context, jobID string) error {
+	f.steps = append(f.steps, "wait "+jobID)
+	poll := PollConfig{InitialInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+	return pollJobStatus(ctx, poll, "AsyncFake", jobID, func(ctx context.Context) (string, error) {
+		f.checks++
+		switch {
+		case f.failJob:
+			return "failed", nil
+		case f.checks < f.readyAfter:
+			return "running", nil
+		}
+		return "ready", nil
+	})
+}
+
+func (f *asyncFake) SupportsBatching() bool { return true }
+
+func (f *asyncFake) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return f.RunQuestionBatchAwait(ctx, batch, websearch, location)
+}
+
+func (f *asyncFake) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return awaitJob(ctx, f, "AsyncFake", func(ctx context.Context) (string, error) {
+		if f.submitErr != nil {
+			return "", f.submitErr
+		}
+		jobID := fmt.Sprintf("s_%d", len(f.jobs)+1)
+		f.steps = append(f.steps, "submit "+jobID)
+		if f.jobs == nil {
+			f.jobs = make(map[string][]BatchQuery)
+		}
+		f.jobs[jobID] = batch
+		return jobID, nil
+	}, func(ctx context.Context, jobID string) ([]*AIResponse, error) {
+		f.steps = append(f.steps, "retrieve "+jobID)
+		queries := f.jobs[jobID]
+		responses := make([]*AIResponse, 0, len(queries))
+		for i := len(queries) - 1; i >= 0; i-- {
+			responses = append(responses, &AIResponse{Response: "answer to " + queries[i].Query, CorrelationID: queries[i].CorrelationID})
+		}
+		return responses, nil
+	})
+}
+
+// TestRunQuestionBatchAwait runs a batch through RunQuestionBatchAwait on a
+// sync provider (replayed fixtures) and an async one, and checks every query
+// gets its own answer in one call.
+func TestRunQuestionBatchAwait(t *testing.T) {
+	batch := []BatchQuery{
+		{CorrelationID: "q1", Query: "Best bank?"},
+		{CorrelationID: "q2", Query: "Best credit union?"},
+		{CorrelationID: "q3", Query: "Best broker?"},
+	}
+	us := &workflowModels.Location{Country: "US"}
+
+	dir := t.TempDir()
+	for _, q := range batch {
+		if err := SaveFixture(dir, FixtureRequest{Model: "gpt-4.1", Query: q.Query, WebSearch: true, Location: us}, &AIResponse{Response: "answer to " + q.Query}); err != nil {
+			t.Fatalf("SaveFixture() error = %v", err)
+		}
+	}
+	async := &asyncFake{readyAfter: 3}
+
+	tests := []struct {
+		name      string
+		provider  AIProvider
+		wantSteps []string
+	}{
+		{name: "sync provider", provider: &ReplayProvider{Dir: dir, Model: "gpt-4.1", Batching: true}},
+		{name: "async provider", provider: async, wantSteps: []string{"submit s_1", "wait s_1", "retrieve s_1"}},
+		{name: "async provider without web search", provider: &noWebSearchProvider{AIProvider: &asyncFake{readyAfter: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses, err := tt.provider.RunQuestionBatchAwait(context.Background(), batch, true, us)
+			if err != nil {
+				t.Fatalf("RunQuestionBatchAwait() error = %v", err)
+			}
+			byID, err := MatchBatchResponses(batch, responses)
+			if err != nil {
+				t.Fatalf("MatchBatchResponses() error = %v", err)
+			}
+			for _, q := range batch {
+				if got := byID[q.CorrelationID].Response; got != "answer to "+q.Query {
+					t.Errorf("%s answered %q, want the answer to %q", q.CorrelationID, got, q.Query)
+				}
+			}
+			if tt.wantSteps != nil && !reflect.DeepEqual(async.steps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", async.steps, tt.wantSteps)
+			}
+		})
+	}
+	if async.checks != 3 {
+		t.Errorf("async job checked %d times, want 3 (ready on the third check)", async.checks)
+	}
+}
+
+func TestAwaitJobFailures(t *testing.T) {
+	batch := []BatchQuery{{CorrelationID: "q1", Query: "Best bank?"}}
+	errSubmit := errors.New("trigger rejected")
+
+	tests := []struct {
+		name      string
+		provider  *asyncFake
+		wantErr   string
+		wantSteps []string
+	}{
+		{name: "submit fails", provider: &asyncFake{submitErr: errSubmit}, wantErr: "failed to submit AsyncFake batch job"},
+		{name: "job fails", provider: &asyncFake{failJob: true}, wantErr: "failed to poll AsyncFake batch job", wantSteps: []string{"submit s_1", "wait s_1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses, err := tt.provider.RunQuestionBatchAwait(context.Background(), batch, true, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || responses != nil {
+				t.Fatalf("RunQuestionBatchAwait() = %v, %v; want an error containing %q", responses, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.provider.steps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", tt.provider.steps, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestPollConfigBackoff(t *testing.T) {
+	cfg := pollConfigFromConfig(&config.Config{AsyncPollInitialIntervalSeconds: 5, AsyncPollMaxIntervalSeconds: 60, AsyncPollDeadlineMinutes: 30})
+	if cfg.InitialInterval != 5*time.Second || cfg.Deadline != 30*time.Minute {
+		t.Fatalf("pollConfigFromConfig() = %+v", cfg)
+	}
+	var got []time.Duration
+	for interval := cfg.InitialInterval; len(got) < 6; interval = cfg.next(interval) {
+		got = append(got, interval)
+	}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 60 * time.Second, 60 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("intervals = %v, want %v", got, want)
+	}
+	if uncapped := (PollConfig{}).next(time.Minute); uncapped != 2*time.Minute {
+		t.Errorf("next() without MaxInterval = %s, want 2m", uncapped)
+	}
+}
+
+// TestPollJobStatus polls a job that is ready after a number of checks and
+// checks the waits between them back off up to MaxInterval.
+func TestPollJobStatus(t *testing.T) {
+	poll := PollConfig{InitialInterval: 2 * time.Millisecond, MaxInterval: 8 * time.Millisecond}
+	errProgress := errors.New("progress check returned status 502")
+
+	tests := []struct {
+		name       string
+		poll       PollConfig
+		statuses   []string // one per check; errors where "error"
+		wantChecks int
+		wantErr    string
+	}{
+		{name: "ready at once", poll: poll, statuses: []string{"ready"}, wantChecks: 1},
+		{name: "ready after five polls", poll: poll, statuses: []string{"starting", "running", "running", "running", "ready"}, wantChecks: 5},
+		{name: "failed checks are retried", poll: poll, statuses: []string{"running", "error", "error", "ready"}, wantChecks: 4},
+		{name: "job fails", poll: poll, statuses: []string{"running", "failed"}, wantChecks: 2, wantErr: "job failed for snapshot s_1"},
+		{
+			name:     "deadline",
+			poll:     PollConfig{InitialInterval: 2 * time.Millisecond, MaxInterval: 2 * time.Millisecond, Deadline: 30 * time.Millisecond},
+			statuses: []string{"running"},
+			wantErr:  "job s_1 not ready after",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var at []time.Time
+			start := time.Now()
+			err := pollJobStatus(context.Background(), tt.poll, "Test", "s_1", func(ctx context.Context) (string, error) {
+				at = append(at, time.Now())
+				status := tt.statuses[min(len(at), len(tt.statuses))-1]
+				if status == "error" {
+					return "", errProgress
+				}
+				return status, nil
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("pollJobStatus() error = %v, want one containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("pollJobStatus() error = %v", err)
+			}
+			if tt.poll.Deadline > 0 {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("pollJobStatus() error = %v, want it to wrap the deadline", err)
+				}
+				return
+			}
+			if len(at) != tt.wantChecks {
+				t.Errorf("checked %d times, want %d", len(at), tt.wantChecks)
+			}
+
+			// Timers never fire early, so each wait is at least its interval
+			interval, previous := tt.poll.InitialInterval, start
+			for i, checked := range at {
+				if wait := checked.Sub(previous); wait < interval {
+					t.Errorf("wait before check %d = %s, want at least %s", i+1, wait, interval)
+				}
+				previous, interval = checked, tt.poll.next(interval)
+			}
+		})
+	}
+}
+
+func TestPollJobStatusCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	checks := 0
+	err := pollJobStatus(ctx, PollConfig{InitialInterval: time.Millisecond}, "Test", "s_1", func(ctx context.Context) (string, error) {
+		checks++
+		if checks == 2 {
+			cancel()
+		}
+		return "running", nil
+	})
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "not ready after 2 polls") {
+		t.Errorf("pollJobStatus() error = %v, want cancellation after 2 polls", err)
+	}
+}
+
+// TestBrightDataWaitForJob polls a progress endpoint that is ready on the
+// fourth request, after a transient 502.
+func TestBrightDataWaitForJob(t *testing.T) {
+	statuses := []int{http.StatusOK, http.StatusBadGateway, http.StatusOK, http.StatusOK}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/progress/s_1" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request "+r.URL.Path, http.StatusBadRequest)
+			return
+		}
+		requests++
+		code := statuses[min(requests, len(statuses))-1]
+		if code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		status := "running"
+		if requests >= len(statuses) {
+			status = "ready"
+		}
+		fmt.Fprintf(w, `{"status":%q,"snapshot_id":"s_1"}`, status)
+	}))
+	defer server.Close()
+
+	provider := &brightDataProvider{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: server.Client(),
+		poll:       PollConfig{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Deadline: 5 * time.Second},
+	}
+	if err := provider.WaitForJob(context.Background(), "s_1"); err != nil {
+		t.Fatalf("WaitForJob() error = %v", err)
+	}
+	if requests != len(statuses) {
+		t.Errorf("progress requested %d times, want %d", requests, len(statuses))
+	}
+}
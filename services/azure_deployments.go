@@ -0,0 +1,42 @@
+// services/azure_deployments.go
+package services
+
+import (
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/openai/openai-go"
+)
+
+// Extraction calls come in two sizes: full (evaluation, mentions, claims,
+// citations) and mini (competitors, name variations). On Azure the model
+// parameter is a deployment name, so each size goes to its own configured
+// deployment rather than an OpenAI model name like gpt-4.1-mini, which only
+// works if a deployment happens to be called that.
+
+// azureFullDeployment returns the deployment for full-size extraction calls,
+// falling back to AZURE_OPENAI_DEPLOYMENT_NAME.
+func azureFullDeployment(cfg *config.Config) string {
+	if deployment := strings.TrimSpace(cfg.AzureOpenAIFullDeploymentName); deployment != "" {
+		return deployment
+	}
+	return cfg.AzureOpenAIDeploymentName
+}
+
+// azureMiniDeployment returns the deployment for small extraction calls,
+// falling back to the full deployment.
+func azureMiniDeployment(cfg *config.Config) string {
+	if deployment := strings.TrimSpace(cfg.AzureOpenAIMiniDeploymentName); deployment != "" {
+		return deployment
+	}
+	return azureFullDeployment(cfg)
+}
+
+// miniExtractionModel returns the model for a small extraction call: the mini
+// deployment on Azure, openAIModel otherwise.
+func miniExtractionModel(cfg *config.Config, openAIModel string) openai.ChatModel {
+	if cfg.AzureOpenAIDeploymentName != "" {
+		return openai.ChatModel(azureMiniDeployment(cfg))
+	}
+	return openai.ChatModel(openAIModel)
+}
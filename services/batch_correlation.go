@@ -0,0 +1,69 @@
+// services/batch_correlation.go
+package services
+
+import "fmt"
+
+// BatchQuery is one query in a provider batch. CorrelationID is opaque to the
+// provider and is copied onto the matching AIResponse so callers can associate
+// responses with their questions without relying on slice order.
+type BatchQuery struct {
+	CorrelationID string
+	Query         string
+}
+
+// batchQueryTexts returns the query strings in submission order.
+func batchQueryTexts(batch []BatchQuery) []string {
+	queries := make([]string, len(batch))
+	for i, q := range batch {
+		queries[i] = q.Query
+	}
+	return queries
+}
+
+// tagBatchResponses copies correlation IDs onto responses that the provider has
+// already matched to batch positions (by index or prompt text).
+func tagBatchResponses(batch []BatchQuery, responses []*AIResponse) {
+	for i := range responses {
+		if i < len(batch) && responses[i] != nil {
+			responses[i].CorrelationID = batch[i].CorrelationID
+		}
+	}
+}
+
+// MatchBatchResponses indexes responses by CorrelationID and verifies every
+// query in batch has exactly one response. A missing, duplicate or unknown ID is
+// an error: misassigning an answer to the wrong question is worse than failing
+// the batch.
+func MatchBatchResponses(batch []BatchQuery, responses []*AIResponse) (map[string]*AIResponse, error) {
+	expected := make(map[string]bool, len(batch))
+	for _, q := range batch {
+		if q.CorrelationID == "" {
+			return nil, fmt.Errorf("batch query has empty correlation ID")
+		}
+		if expected[q.CorrelationID] {
+			return nil, fmt.Errorf("duplicate correlation ID in batch: %s", q.CorrelationID)
+		}
+		expected[q.CorrelationID] = true
+	}
+
+	matched := make(map[string]*AIResponse, len(responses))
+	for i, resp := range responses {
+		if resp == nil {
+			return nil, fmt.Errorf("batch response %d is nil", i+1)
+		}
+		if !expected[resp.CorrelationID] {
+			return nil, fmt.Errorf("batch response %d has unknown correlation ID %q", i+1, resp.CorrelationID)
+		}
+		if _, dup := matched[resp.CorrelationID]; dup {
+			return nil, fmt.Errorf("batch returned more than one response for correlation ID %s", resp.CorrelationID)
+		}
+		matched[resp.CorrelationID] = resp
+	}
+
+	for _, q := range batch {
+		if _, ok := matched[q.CorrelationID]; !ok {
+			return nil, fmt.Errorf("batch returned no response for correlation ID %s", q.CorrelationID)
+		}
+	}
+	return matched, nil
+}
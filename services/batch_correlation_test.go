@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+// shuffledSnapshotHandler fakes the BrightData trigger, progress and snapshot
+// endpoints. The snapshot answers every submitted prompt, shuffled; indexed
+// controls whether results carry their input index, at the top level or (as
+// error results do) only in the echoed input.
+func shuffledSnapshotHandler(t *testing.T, seed int64, indexed string) http.Handler {
+	var inputs []BrightDataInput
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/trigger"):
+			var req BrightDataRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode trigger request: %v", err)
+			}
+			inputs = req.Input
+			_ = json.NewEncoder(w).Encode(BrightDataTriggerResponse{SnapshotID: "s_1"})
+		case strings.HasSuffix(r.URL.Path, "/progress/s_1"):
+			_ = json.NewEncoder(w).Encode(BrightDataProgressResponse{Status: "ready", SnapshotID: "s_1"})
+		case strings.HasSuffix(r.URL.Path, "/snapshot/s_1"):
+			results := make([]BrightDataResult, len(inputs))
+			for i, in := range inputs {
+				result := BrightDataResult{Prompt: in.Prompt, AnswerTextMarkdown: "The answer to: " + in.Prompt}
+				switch indexed {
+				case "top level":
+					result.Index = in.Index
+				case "input echo":
+					result.Input = &BrightDataInputEcho{Prompt: in.Prompt, Index: in.Index}
+				}
+				results[i] = result
+			}
+			rand.New(rand.NewSource(seed)).Shuffle(len(results), func(i, j int) { results[i], results[j] = results[j], results[i] })
+			_ = json.NewEncoder(w).Encode(results)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// TestBatchResponsesFollowCorrelationIDs shuffles a BrightData snapshot and
+// checks every response comes back tagged with its own query's ID.
+func TestBatchResponsesFollowCorrelationIDs(t *testing.T) {
+	batch := []BatchQuery{
+		{CorrelationID: "q1|US", Query: "Which bank has the best savings rate?"},
+		{CorrelationID: "q2|US", Query: "Which credit union has the lowest fees?"},
+		{CorrelationID: "q3|US", Query: "Who offers the best mortgage?"},
+		{CorrelationID: "q4|US", Query: "Which broker is best for beginners?"},
+		{CorrelationID: "q5|US", Query: "Which card has the best cashback?"},
+	}
+
+	for _, indexed := range []string{"top level", "input echo", "none"} {
+		for seed := int64(1); seed <= 3; seed++ {
+			server := httptest.NewServer(shuffledSnapshotHandler(t, seed, indexed))
+			p := &brightDataProvider{
+				apiKey:     "test-key",
+				datasetID:  "test-dataset",
+				baseURL:    server.URL,
+				httpClient: server.Client(),
+				poll:       PollConfig{InitialInterval: time.Millisecond},
+			}
+
+			responses, err := p.RunQuestionBatch(context.Background(), batch, true, &workflowModels.Location{Country: "US"})
+			server.Close()
+			if err != nil {
+				t.Fatalf("indices %s, seed %d: RunQuestionBatch() error = %v", indexed, seed, err)
+			}
+			byID, err := MatchBatchResponses(batch, responses)
+			if err != nil {
+				t.Fatalf("indices %s, seed %d: MatchBatchResponses() error = %v", indexed, seed, err)
+			}
+			for _, q := range batch {
+				if got := byID[q.CorrelationID].Response; !strings.HasSuffix(got, q.Query) {
+					t.Errorf("indices %s, seed %d: %s answered %q, want the answer to %q", indexed, seed, q.CorrelationID, got, q.Query)
+				}
+			}
+		}
+	}
+}
+
+func TestMatchBatchResponses(t *testing.T) {
+	batch := []BatchQuery{{CorrelationID: "a", Query: "A?"}, {CorrelationID: "b", Query: "B?"}, {CorrelationID: "c", Query: "C?"}}
+	resp := func(id string) *AIResponse { return &AIResponse{Response: "answer " + id, CorrelationID: id} }
+
+	tests := []struct {
+		name      string
+		batch     []BatchQuery
+		responses []*AIResponse
+		wantErr   string
+	}{
+		{name: "shuffled", batch: batch, responses: []*AIResponse{resp("c"), resp("a"), resp("b")}},
+		{name: "missing", batch: batch, responses: []*AIResponse{resp("c"), resp("a")}, wantErr: "no response for correlation ID b"},
+		{name: "duplicate", batch: batch, responses: []*AIResponse{resp("a"), resp("a"), resp("c")}, wantErr: "more than one response for correlation ID a"},
+		{name: "unknown", batch: batch, responses: []*AIResponse{resp("a"), resp("b"), resp("z")}, wantErr: `unknown correlation ID "z"`},
+		{name: "untagged", batch: batch, responses: []*AIResponse{resp("a"), resp("b"), {Response: "c"}}, wantErr: `unknown correlation ID ""`},
+		{name: "nil response", batch: batch, responses: []*AIResponse{resp("a"), nil, resp("c")}, wantErr: "batch response 2 is nil"},
+		{name: "empty batch ID", batch: []BatchQuery{{Query: "A?"}}, responses: []*AIResponse{{}}, wantErr: "empty correlation ID"},
+		{name: "duplicate batch ID", batch: []BatchQuery{{CorrelationID: "a"}, {CorrelationID: "a"}}, wantErr: "duplicate correlation ID in batch: a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byID, err := MatchBatchResponses(tt.batch, tt.responses)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MatchBatchResponses() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchBatchResponses() error = %v", err)
+			}
+			for _, q := range tt.batch {
+				if got := byID[q.CorrelationID].Response; got != "answer "+q.CorrelationID {
+					t.Errorf("%s matched %q", q.CorrelationID, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// services/batch_day.go
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// A batch day is the calendar day runs and batches are deduplicated by: a
+// batch created earlier "today" is resumed instead of starting a new one, and
+// the fixers skip jobs that already have a run from today. It starts at
+// midnight in the org's or network's BATCH_DAY_TIMEZONES zone, so a network
+// whose daily run spans midnight UTC (22:00 UTC in Australia) keeps the whole
+// run in one day.
+
+// BatchDayLocation returns the time zone of the org's or network's batch day:
+// its BATCH_DAY_TIMEZONES entry, or UTC when it has none or the zone is unknown.
+func BatchDayLocation(cfg *config.Config, scopeID uuid.UUID) *time.Location {
+	if cfg == nil {
+		return time.UTC
+	}
+	name, ok := cfg.BatchDayTimezones[strings.ToLower(scopeID.String())]
+	if !ok {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		fmt.Printf("[BatchDayLocation] Warning: ignoring time zone %q for %s, using UTC: %v\n", name, scopeID, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// DayStart returns midnight of the day containing now in loc.
+func DayStart(now time.Time, loc *time.Location) time.Time {
+	t := now.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// BatchDayStart returns the start of the org's or network's current batch day.
+func BatchDayStart(cfg *config.Config, scopeID uuid.UUID, now time.Time) time.Time {
+	return DayStart(now, BatchDayLocation(cfg, scopeID))
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// TestBatchDayStart checks the batch day boundaries around local midnight in
+// zones ahead of UTC (Brisbane, UTC+10) and behind it (Phoenix, UTC-7), neither
+// of which observes daylight saving time.
+func TestBatchDayStart(t *testing.T) {
+	brisbane, phoenix, unknown, unset := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	cfg := &config.Config{BatchDayTimezones: map[string]string{
+		strings.ToLower(brisbane.String()): "Australia/Brisbane",
+		strings.ToLower(phoenix.String()):  "America/Phoenix",
+		strings.ToLower(unknown.String()):  "Mars/Olympus_Mons",
+	}}
+	utc := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("bad time %q: %v", s, err)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		scopeID uuid.UUID
+		now     string
+		want    string // UTC instant of the batch day start
+	}{
+		// Brisbane midnight is 14:00 UTC the day before
+		{name: "UTC+10 just before midnight", cfg: cfg, scopeID: brisbane, now: "2026-03-02T13:59:59Z", want: "2026-03-01T14:00:00Z"},
+		{name: "UTC+10 at midnight", cfg: cfg, scopeID: brisbane, now: "2026-03-02T14:00:00Z", want: "2026-03-02T14:00:00Z"},
+		{name: "UTC+10 run spanning UTC midnight", cfg: cfg, scopeID: brisbane, now: "2026-03-03T00:30:00Z", want: "2026-03-02T14:00:00Z"},
+		{name: "UTC+10 just before the next midnight", cfg: cfg, scopeID: brisbane, now: "2026-03-03T13:59:59Z", want: "2026-03-02T14:00:00Z"},
+		// Phoenix midnight is 07:00 UTC the same day
+		{name: "UTC-7 just before midnight", cfg: cfg, scopeID: phoenix, now: "2026-03-02T06:59:59Z", want: "2026-03-01T07:00:00Z"},
+		{name: "UTC-7 at midnight", cfg: cfg, scopeID: phoenix, now: "2026-03-02T07:00:00Z", want: "2026-03-02T07:00:00Z"},
+		{name: "UTC-7 at UTC midnight", cfg: cfg, scopeID: phoenix, now: "2026-03-03T00:00:00Z", want: "2026-03-02T07:00:00Z"},
+		{name: "UTC-7 just before the next midnight", cfg: cfg, scopeID: phoenix, now: "2026-03-03T06:59:59Z", want: "2026-03-02T07:00:00Z"},
+		// Everything else falls back to UTC
+		{name: "no time zone", cfg: cfg, scopeID: unset, now: "2026-03-02T23:59:59Z", want: "2026-03-02T00:00:00Z"},
+		{name: "unknown time zone", cfg: cfg, scopeID: unknown, now: "2026-03-02T00:00:00Z", want: "2026-03-02T00:00:00Z"},
+		{name: "nil config", cfg: nil, scopeID: brisbane, now: "2026-03-02T13:59:59Z", want: "2026-03-02T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BatchDayStart(tt.cfg, tt.scopeID, utc(tt.now))
+			if want := utc(tt.want); !got.Equal(want) {
+				t.Errorf("BatchDayStart(%s) = %s, want %s", tt.now, got.UTC().Format(time.RFC3339), tt.want)
+			}
+			loc := BatchDayLocation(tt.cfg, tt.scopeID)
+			if got.Location().String() != loc.String() {
+				t.Errorf("day start in %s, want %s", got.Location(), loc)
+			}
+			if h, m, s := got.Clock(); h != 0 || m != 0 || s != 0 {
+				t.Errorf("day start %s is not local midnight", got)
+			}
+			if !DayStart(utc(tt.now), loc).Equal(got) {
+				t.Errorf("DayStart and BatchDayStart disagree")
+			}
+		})
+	}
+}
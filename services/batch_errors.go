@@ -0,0 +1,139 @@
+// services/batch_errors.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// NetworkProcessingSummary.ProcessingErrors only lives in memory and in the
+// Inngest step output, so a week later nothing says why questions are missing
+// from a batch. Every processing error of a network matrix is also stored as
+// it occurs, queryable by batch. Rows are occurrences: a retried matrix step
+// records the errors it hits again.
+// Schema:
+//
+//	migrations/000010_question_run_batch_errors.up.sql
+
+// maxBatchErrorMessageLen caps stored messages (in bytes); provider bodies can
+// be arbitrarily long.
+const maxBatchErrorMessageLen = 2000
+
+// BatchError is one stored processing error of a batch. GeoQuestionID is nil
+// for errors that are not about a single question.
+type BatchError struct {
+	BatchErrorID  uuid.UUID  `db:"batch_error_id" json:"batch_error_id"`
+	BatchID       uuid.UUID  `db:"batch_id" json:"batch_id"`
+	GeoQuestionID *uuid.UUID `db:"geo_question_id" json:"geo_question_id,omitempty"`
+	Model         string     `db:"model" json:"model"`
+	Location      string     `db:"location" json:"location"`
+	ErrorCode     ErrorCode  `db:"error_code" json:"error_code"`
+	Message       string     `db:"message" json:"message"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// BatchErrorRepository stores the processing errors of batches.
+type BatchErrorRepository interface {
+	Record(ctx context.Context, batchError *BatchError) error
+	// ListByBatch returns a batch's errors, oldest first.
+	ListByBatch(ctx context.Context, batchID uuid.UUID) ([]*BatchError, error)
+	CountByBatch(ctx context.Context, batchID uuid.UUID) (int, error)
+}
+
+type batchErrorRepo struct {
+	db *database.Client
+}
+
+func NewBatchErrorRepo(db *database.Client) BatchErrorRepository {
+	return &batchErrorRepo{db: db}
+}
+
+func (r *batchErrorRepo) Record(ctx context.Context, e *BatchError) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_batch_errors (batch_error_id, batch_id, geo_question_id, model, location, error_code, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.BatchErrorID, e.BatchID, e.GeoQuestionID, e.Model, e.Location, e.ErrorCode, truncateBatchErrorMessage(e.Message), e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record error for batch %s: %w", e.BatchID, err)
+	}
+	return nil
+}
+
+func (r *batchErrorRepo) ListByBatch(ctx context.Context, batchID uuid.UUID) ([]*BatchError, error) {
+	var batchErrors []*BatchError
+	err := r.db.SelectContext(ctx, &batchErrors, `
+		SELECT batch_error_id, batch_id, geo_question_id, model, location, error_code, message, created_at
+		FROM question_run_batch_errors
+		WHERE batch_id = $1
+		ORDER BY created_at`, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list errors for batch %s: %w", batchID, err)
+	}
+	return batchErrors, nil
+}
+
+func (r *batchErrorRepo) CountByBatch(ctx context.Context, batchID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM question_run_batch_errors WHERE batch_id = $1`, batchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count errors for batch %s: %w", batchID, err)
+	}
+	return count, nil
+}
+
+// truncateBatchErrorMessage cuts msg to maxBatchErrorMessageLen bytes without
+// splitting a UTF-8 sequence.
+func truncateBatchErrorMessage(msg string) string {
+	if len(msg) <= maxBatchErrorMessageLen {
+		return msg
+	}
+	cut := maxBatchErrorMessageLen
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "…"
+}
+
+// recordNetworkBatchError adds msg to the summary's ProcessingErrors and
+// stores it for the batch. questionID is nil for errors not tied to one
+// question; a failed store is logged, the summary entry is kept regardless.
+func (s *questionRunnerService) recordNetworkBatchError(ctx context.Context, summary *NetworkProcessingSummary, batchID uuid.UUID, questionID *uuid.UUID, pair ModelLocationPair, code ErrorCode, msg string) {
+	summary.ProcessingErrors = append(summary.ProcessingErrors, msg)
+	s.storeNetworkBatchError(ctx, batchID, questionID, pair, code, msg)
+}
+
+// storeNetworkBatchError stores msg for the batch without counting it as a
+// failed run in ProcessingErrors.
+func (s *questionRunnerService) storeNetworkBatchError(ctx context.Context, batchID uuid.UUID, questionID *uuid.UUID, pair ModelLocationPair, code ErrorCode, msg string) {
+	if s.repos.BatchErrorRepo == nil {
+		return
+	}
+	if code == "" {
+		code = ErrorCodeUnknown
+	}
+	batchError := &BatchError{
+		BatchErrorID:  uuid.New(),
+		BatchID:       batchID,
+		GeoQuestionID: questionID,
+		ErrorCode:     code,
+		Message:       msg,
+		CreatedAt:     time.Now(),
+	}
+	if pair.Model != nil {
+		batchError.Model = pair.Model.Name
+	}
+	if pair.Location != nil {
+		batchError.Location = pair.Location.CountryCode
+		if pair.Location.RegionName != nil && *pair.Location.RegionName != "" {
+			batchError.Location += "/" + *pair.Location.RegionName
+		}
+	}
+	if err := s.repos.BatchErrorRepo.Record(ctx, batchError); err != nil {
+		fmt.Printf("[storeNetworkBatchError] Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,74 @@
+// services/batch_latest.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateLatestBatch inserts batch as the only is_latest batch within its org or
+// network scope. Clearing the previous latest batch and inserting the new one
+// happen in a single transaction so the dashboard never sees two (or zero)
+// latest batches. Every batch creator (the nightly org and network pipelines
+// and the fixer tools) goes through it.
+func CreateLatestBatch(ctx context.Context, repos *RepositoryManager, batch *models.QuestionRunBatch) error {
+	if batch.OrgID == nil && batch.NetworkID == nil {
+		return fmt.Errorf("batch %s has neither org_id nor network_id", batch.BatchID)
+	}
+
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback on error
+
+	now := time.Now()
+	if err := clearLatestBatch(ctx, tx, batch, now); err != nil {
+		return err
+	}
+
+	createdAt := batch.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO question_run_batches (
+			batch_id, scope, org_id, network_id, batch_type, status,
+			total_questions, completed_questions, failed_questions, is_latest,
+			started_at, completed_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true, $10, $11, $12, $13)`,
+		batch.BatchID, batch.Scope, batch.OrgID, batch.NetworkID, batch.BatchType, batch.Status,
+		batch.TotalQuestions, batch.CompletedQuestions, batch.FailedQuestions,
+		batch.StartedAt, batch.CompletedAt, createdAt, now); err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	batch.IsLatest = true
+	batch.CreatedAt = createdAt
+	batch.UpdatedAt = now
+	return nil
+}
+
+// clearLatestBatch clears is_latest on every other batch of batch's scope.
+func clearLatestBatch(ctx context.Context, tx *sqlx.Tx, batch *models.QuestionRunBatch, now time.Time) error {
+	scopeColumn, scopeID := "network_id", batch.NetworkID
+	if batch.OrgID != nil {
+		scopeColumn, scopeID = "org_id", batch.OrgID
+	}
+	clearQuery := fmt.Sprintf(`
+		UPDATE question_run_batches
+		SET is_latest = false, updated_at = $1
+		WHERE %s = $2 AND scope = $3 AND batch_id <> $4 AND is_latest = true AND deleted_at IS NULL`, scopeColumn)
+	if _, err := tx.ExecContext(ctx, clearQuery, now, *scopeID, batch.Scope, batch.BatchID); err != nil {
+		return fmt.Errorf("failed to clear previous latest batch: %w", err)
+	}
+	return nil
+}
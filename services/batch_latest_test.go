@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// batchTable is an in-memory question_run_batches: the batch repository the
+// pipelines read from, and the exec hook CreateLatestBatch's SQL runs against.
+type batchTable struct {
+	rows []*models.QuestionRunBatch
+}
+
+func (b *batchTable) exec(query string, args []any) (int64, error) {
+	switch {
+	case strings.HasPrefix(query, "UPDATE question_run_batches SET is_latest = false"):
+		scopeID, scope, keep := args[1].(uuid.UUID), args[2].(string), args[3].(uuid.UUID)
+		byOrg := strings.Contains(query, "WHERE org_id = $2")
+		var cleared int64
+		for _, row := range b.rows {
+			owner := row.NetworkID
+			if byOrg {
+				owner = row.OrgID
+			}
+			if owner != nil && *owner == scopeID && row.Scope == scope && row.BatchID != keep && row.IsLatest {
+				row.IsLatest = false
+				cleared++
+			}
+		}
+		return cleared, nil
+	case strings.HasPrefix(query, "INSERT INTO question_run_batches"):
+		b.rows = append(b.rows, &models.QuestionRunBatch{
+			BatchID:   args[0].(uuid.UUID),
+			Scope:     args[1].(string),
+			OrgID:     args[2].(*uuid.UUID),
+			NetworkID: args[3].(*uuid.UUID),
+			IsLatest:  true,
+			CreatedAt: args[11].(time.Time),
+		})
+		return 1, nil
+	}
+	return 0, fmt.Errorf("unexpected statement: %s", query)
+}
+
+func (b *batchTable) latest() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, row := range b.rows {
+		if row.IsLatest {
+			ids = append(ids, row.BatchID)
+		}
+	}
+	return ids
+}
+
+func (b *batchTable) Create(ctx context.Context, batch *models.QuestionRunBatch) error {
+	return errors.New("batches must be created with CreateLatestBatch")
+}
+
+func (b *batchTable) Update(ctx context.Context, batch *models.QuestionRunBatch) error { return nil }
+
+func (b *batchTable) GetByID(ctx context.Context, id uuid.UUID) (*models.QuestionRunBatch, error) {
+	for _, row := range b.rows {
+		if row.BatchID == id {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *batchTable) GetByOrg(ctx context.Context, orgID uuid.UUID) ([]*models.QuestionRunBatch, error) {
+	var out []*models.QuestionRunBatch
+	for _, row := range b.rows {
+		if row.OrgID != nil && *row.OrgID == orgID {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (b *batchTable) GetByNetwork(ctx context.Context, networkID uuid.UUID) ([]*models.QuestionRunBatch, error) {
+	var out []*models.QuestionRunBatch
+	for _, row := range b.rows {
+		if row.NetworkID != nil && *row.NetworkID == networkID {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// TestGetOrCreateBatchClearsPreviousLatest creates a batch for a scope whose
+// previous batch is latest, and checks only the new batch is latest after.
+func TestGetOrCreateBatchClearsPreviousLatest(t *testing.T) {
+	scopeID, otherID := uuid.New(), uuid.New()
+	yesterday := time.Now().Add(-36 * time.Hour)
+
+	tests := []struct {
+		name   string
+		scope  string
+		create func(repos *RepositoryManager) (*models.QuestionRunBatch, bool, error)
+	}{
+		{
+			name:  "org batch",
+			scope: "org",
+			create: func(repos *RepositoryManager) (*models.QuestionRunBatch, bool, error) {
+				s := &orgEvaluationService{cfg: &config.Config{}, repos: repos}
+				return s.GetOrCreateTodaysBatch(context.Background(), scopeID, 10)
+			},
+		},
+		{
+			name:  "network batch",
+			scope: "network",
+			create: func(repos *RepositoryManager) (*models.QuestionRunBatch, bool, error) {
+				s := &questionRunnerService{cfg: &config.Config{}, repos: repos}
+				return s.GetOrCreateNetworkBatch(context.Background(), scopeID, 10)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := func(owner uuid.UUID) *models.QuestionRunBatch {
+				b := &models.QuestionRunBatch{BatchID: uuid.New(), Scope: tt.scope, IsLatest: true, CreatedAt: yesterday}
+				if tt.scope == "org" {
+					b.OrgID = &owner
+				} else {
+					b.NetworkID = &owner
+				}
+				return b
+			}
+			previous, other := batch(scopeID), batch(otherID)
+			table := &batchTable{rows: []*models.QuestionRunBatch{previous, other}}
+			rec := &sqlRecorder{Exec: table.exec}
+			repos := newRecordingRepos(t, rec)
+			repos.QuestionRunBatchRepo = table
+
+			created, existed, err := tt.create(repos)
+			if err != nil || existed {
+				t.Fatalf("create = %v, existed %t; want a new batch", err, existed)
+			}
+			if got := table.latest(); len(got) != 2 || got[0] != other.BatchID || got[1] != created.BatchID {
+				t.Errorf("latest batches = %v, want the other scope's %s and the new %s", got, other.BatchID, created.BatchID)
+			}
+			if previous.IsLatest {
+				t.Errorf("previous batch %s is still latest", previous.BatchID)
+			}
+			wantQueries := []string{"BEGIN", "UPDATE question_run_batches SET is_latest = false", "INSERT INTO question_run_batches", "COMMIT"}
+			queries := rec.Queries()
+			if len(queries) != len(wantQueries) {
+				t.Fatalf("statements = %q, want %q", queries, wantQueries)
+			}
+			for i, want := range wantQueries {
+				if !strings.HasPrefix(queries[i], want) {
+					t.Errorf("statement %d = %q, want it to start with %q", i, queries[i], want)
+				}
+			}
+
+			// A second run on the same day reuses the batch
+			again, existed, err := tt.create(repos)
+			if err != nil || !existed || again.BatchID != created.BatchID {
+				t.Errorf("second create = %v, %t, %v; want the batch of the first", again, existed, err)
+			}
+			if len(table.rows) != 3 {
+				t.Errorf("%d batches after two creations on one day, want 3", len(table.rows))
+			}
+		})
+	}
+}
+
+func TestCreateLatestBatch(t *testing.T) {
+	orgID, networkID := uuid.New(), uuid.New()
+	errClear := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		batch       *models.QuestionRunBatch
+		execErr     error
+		wantColumn  string
+		wantQueries []string
+		wantErr     string
+	}{
+		{
+			name:        "org scope",
+			batch:       &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "org", OrgID: &orgID},
+			wantColumn:  "WHERE org_id = $2",
+			wantQueries: []string{"BEGIN", "UPDATE", "INSERT", "COMMIT"},
+		},
+		{
+			name:        "network scope",
+			batch:       &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "network", NetworkID: &networkID},
+			wantColumn:  "WHERE network_id = $2",
+			wantQueries: []string{"BEGIN", "UPDATE", "INSERT", "COMMIT"},
+		},
+		{
+			name:    "no scope",
+			batch:   &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "org"},
+			wantErr: "neither org_id nor network_id",
+		},
+		{
+			name:        "failed clear creates nothing",
+			batch:       &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "org", OrgID: &orgID},
+			execErr:     errClear,
+			wantQueries: []string{"BEGIN", "UPDATE", "ROLLBACK"},
+			wantErr:     "failed to clear previous latest batch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &sqlRecorder{Exec: func(query string, args []any) (int64, error) { return 1, tt.execErr }}
+			err := CreateLatestBatch(context.Background(), newRecordingRepos(t, rec), tt.batch)
+
+			var got []string
+			for _, query := range rec.Queries() {
+				got = append(got, strings.Fields(query)[0])
+			}
+			if strings.Join(got, " ") != strings.Join(tt.wantQueries, " ") {
+				t.Errorf("statements = %v, want %v", got, tt.wantQueries)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateLatestBatch() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				if tt.batch.IsLatest {
+					t.Error("IsLatest set on a batch that was not created")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateLatestBatch() error = %v", err)
+			}
+			if clear := rec.Queries()[1]; !strings.Contains(clear, tt.wantColumn) {
+				t.Errorf("clear statement %q does not filter %q", clear, tt.wantColumn)
+			}
+			if !tt.batch.IsLatest || tt.batch.CreatedAt.IsZero() {
+				t.Errorf("batch = %+v, want it latest with a creation time", tt.batch)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// services/batch_progress.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Long matrices used to write batch progress only at the end, so a batch
+// showed 0 completed for an hour and monitoring could not tell stuck from
+// slow. batchProgress commits the counters as the matrix runs, at most every
+// BATCH_PROGRESS_FLUSH_SECONDS or every BATCH_PROGRESS_FLUSH_RUNS runs, as
+// an in-place increment instead of a read-modify-write of the batch row. The
+// final counts are still reconciled by the caller once the matrix completes.
+
+// BatchProgressRepository increments batch counters in place.
+type BatchProgressRepository interface {
+	IncrementProgress(ctx context.Context, batchID uuid.UUID, completed, failed int) error
+}
+
+type batchProgressRepo struct {
+	db *database.Client
+}
+
+func NewBatchProgressRepo(db *database.Client) BatchProgressRepository {
+	return &batchProgressRepo{db: db}
+}
+
+func (r *batchProgressRepo) IncrementProgress(ctx context.Context, batchID uuid.UUID, completed, failed int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE question_run_batches
+		SET completed_questions = completed_questions + $2,
+		    failed_questions = failed_questions + $3,
+		    updated_at = NOW()
+		WHERE batch_id = $1`, batchID, completed, failed)
+	if err != nil {
+		return fmt.Errorf("failed to increment batch progress: %w", err)
+	}
+	return nil
+}
+
+// batchProgress buffers counter deltas for one batch and commits them on the
+// configured cadence. It is safe for concurrent use; a nil *batchProgress
+// does nothing.
+type batchProgress struct {
+	repos     *RepositoryManager
+	batchID   uuid.UUID
+	interval  time.Duration
+	everyRuns int
+	now       func() time.Time
+
+	mu        sync.Mutex
+	lastFlush time.Time
+	completed int
+	failed    int
+	// observed totals, for Observe
+	seenCompleted int
+	seenFailed    int
+}
+
+func newBatchProgress(cfg *config.Config, repos *RepositoryManager, batchID uuid.UUID) *batchProgress {
+	if batchID == uuid.Nil {
+		return nil
+	}
+	p := &batchProgress{
+		repos:     repos,
+		batchID:   batchID,
+		now:       time.Now,
+		lastFlush: time.Now(),
+	}
+	if cfg != nil {
+		p.interval = time.Duration(cfg.BatchProgressFlushSeconds) * time.Second
+		p.everyRuns = cfg.BatchProgressFlushRuns
+	}
+	return p
+}
+
+// Add records completed and failed runs, committing when the cadence is due.
+func (p *batchProgress) Add(ctx context.Context, completed, failed int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.completed += completed
+	p.failed += failed
+	due := p.dueLocked()
+	p.mu.Unlock()
+	if due {
+		p.Flush(ctx)
+	}
+}
+
+// Observe records running totals (e.g. from a summary) as deltas against the
+// totals seen last time.
+func (p *batchProgress) Observe(ctx context.Context, completedTotal, failedTotal int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	completed, failed := completedTotal-p.seenCompleted, failedTotal-p.seenFailed
+	p.seenCompleted, p.seenFailed = completedTotal, failedTotal
+	p.mu.Unlock()
+	if completed > 0 || failed > 0 {
+		p.Add(ctx, completed, failed)
+	}
+}
+
+func (p *batchProgress) dueLocked() bool {
+	pending := p.completed + p.failed
+	if pending == 0 {
+		return false
+	}
+	if p.everyRuns > 0 && pending >= p.everyRuns {
+		return true
+	}
+	return p.interval > 0 && p.now().Sub(p.lastFlush) >= p.interval
+}
+
+// Flush commits the pending deltas. Failures are logged and the deltas kept
+// for the next attempt.
+func (p *batchProgress) Flush(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	completed, failed := p.completed, p.failed
+	p.completed, p.failed = 0, 0
+	p.lastFlush = p.now()
+	p.mu.Unlock()
+	if completed == 0 && failed == 0 {
+		return
+	}
+
+	if err := p.increment(ctx, completed, failed); err != nil {
+		fmt.Printf("[batchProgress] Warning: batch %s: %v\n", p.batchID, err)
+		p.mu.Lock()
+		p.completed += completed
+		p.failed += failed
+		p.mu.Unlock()
+		return
+	}
+	fmt.Printf("[batchProgress] Batch %s progress +%d completed, +%d failed\n", p.batchID, completed, failed)
+}
+
+func (p *batchProgress) increment(ctx context.Context, completed, failed int) error {
+	if p.repos.BatchProgressRepo != nil {
+		return p.repos.BatchProgressRepo.IncrementProgress(ctx, p.batchID, completed, failed)
+	}
+	batch, err := p.repos.QuestionRunBatchRepo.GetByID(ctx, p.batchID)
+	if err != nil {
+		return fmt.Errorf("failed to get batch: %w", err)
+	}
+	batch.CompletedQuestions += completed
+	batch.FailedQuestions += failed
+	batch.UpdatedAt = time.Now()
+	return p.repos.QuestionRunBatchRepo.Update(ctx, batch)
+}
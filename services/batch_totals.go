@@ -0,0 +1,51 @@
+// services/batch_totals.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+)
+
+// TotalQuestions is the canonical progress denominator and means the jobs
+// planned for the batch, not questions × models × locations. The pipelines
+// only skip runs already attached to the same batch, so the two agree when
+// the batch is created and skipped jobs are taken off at completion
+// (ReconcileBatchCounts). The fixers skip any run from today, so they size a
+// new batch by the jobs they build, and with --count-planned re-plan a
+// resumed one with PlanBatchTotal.
+
+// ReconcileBatchCounts records the final counts of a finished batch. Skipped
+// jobs were planned but never attempted (e.g. a model dropped by the web search
+// policy), so they are taken off TotalQuestions; a finished batch then reads
+// CompletedQuestions+FailedQuestions == TotalQuestions instead of sitting at
+// e.g. 140/600 forever.
+//
+// It returns how far the planned total is from completed+failed+skipped; a
+// non-zero drift means some planned jobs were neither run nor accounted for.
+func ReconcileBatchCounts(batch *models.QuestionRunBatch, completed, failed, skipped int) int {
+	drift := batch.TotalQuestions - (completed + failed + skipped)
+	batch.CompletedQuestions = completed
+	batch.FailedQuestions = failed
+	batch.TotalQuestions = max(batch.TotalQuestions-skipped, 0)
+	return drift
+}
+
+// PlanBatchTotal sets the total of a resumed batch to the runs already
+// attached to it plus plannedJobs, the jobs about to run into it, and returns
+// the new total. Runs another process is still adding to the batch are not
+// accounted for.
+func PlanBatchTotal(ctx context.Context, repos *RepositoryManager, batch *models.QuestionRunBatch, plannedJobs int) (int, error) {
+	attached, err := repos.QuestionRunRepo.GetByBatch(ctx, batch.BatchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get batch runs: %w", err)
+	}
+	batch.TotalQuestions = len(attached) + plannedJobs
+	batch.UpdatedAt = time.Now()
+	if err := repos.QuestionRunBatchRepo.Update(ctx, batch); err != nil {
+		return 0, fmt.Errorf("failed to update batch total questions: %w", err)
+	}
+	return batch.TotalQuestions, nil
+}
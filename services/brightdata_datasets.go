@@ -0,0 +1,84 @@
+// services/brightdata_datasets.go
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// datasetProvider is implemented by the BrightData-backed providers.
+type datasetProvider interface {
+	DatasetID() string
+}
+
+var (
+	_ datasetProvider = (*brightDataProvider)(nil)
+	_ datasetProvider = (*perplexityProvider)(nil)
+	_ datasetProvider = (*geminiProvider)(nil)
+)
+
+// defaultBrightDataDatasets are the global dataset IDs from cfg.
+func defaultBrightDataDatasets(cfg *config.Config) config.BrightDataDatasets {
+	if cfg == nil {
+		return config.BrightDataDatasets{}
+	}
+	return config.BrightDataDatasets{
+		BrightDataDatasetID: cfg.BrightDataDatasetID,
+		PerplexityDatasetID: cfg.PerplexityDatasetID,
+		GeminiDatasetID:     cfg.GeminiDatasetID,
+	}
+}
+
+// brightDataDatasetsFor returns the datasets the network's traffic runs in:
+// its NETWORK_BRIGHTDATA_DATASETS entry, with the global IDs filling in any
+// dataset the entry leaves out.
+func brightDataDatasetsFor(cfg *config.Config, networkID uuid.UUID) config.BrightDataDatasets {
+	datasets := defaultBrightDataDatasets(cfg)
+	if cfg == nil {
+		return datasets
+	}
+	override, ok := cfg.NetworkDatasets[strings.ToLower(networkID.String())]
+	if !ok {
+		return datasets
+	}
+	if override.BrightDataDatasetID != "" {
+		datasets.BrightDataDatasetID = override.BrightDataDatasetID
+	}
+	if override.PerplexityDatasetID != "" {
+		datasets.PerplexityDatasetID = override.PerplexityDatasetID
+	}
+	if override.GeminiDatasetID != "" {
+		datasets.GeminiDatasetID = override.GeminiDatasetID
+	}
+	return datasets
+}
+
+// maskDatasetID hides all but the last 4 characters of a dataset ID for logs.
+func maskDatasetID(id string) string {
+	if len(id) <= 4 {
+		return "***"
+	}
+	return "***" + id[len(id)-4:]
+}
+
+// logProviderDataset logs which dataset a BrightData-backed provider submits
+// to for the network; other providers are not logged.
+func logProviderDataset(logTag string, cfg *config.Config, provider AIProvider, model string, networkID uuid.UUID) {
+	if wrapped, ok := provider.(*capabilityProvider); ok {
+		provider = wrapped.AIProvider
+	}
+	p, ok := provider.(datasetProvider)
+	if !ok {
+		return
+	}
+	id := p.DatasetID()
+	source := "network override"
+	if defaults := defaultBrightDataDatasets(cfg); id == defaults.BrightDataDatasetID || id == defaults.PerplexityDatasetID || id == defaults.GeminiDatasetID {
+		source = "default"
+	}
+	fmt.Printf("[%s] 🗄️ Model %s uses BrightData dataset %s (%s) for network %s\n",
+		logTag, model, maskDatasetID(id), source, networkID)
+}
@@ -22,17 +22,21 @@ type brightDataProvider struct {
 	baseURL     string
 	costService CostService
 	httpClient  *http.Client
+	poll        PollConfig
 }
 
-func NewBrightDataProvider(cfg *config.Config, model string, costService CostService) AIProvider {
+// NewBrightDataProvider submits to datasetID (see brightDataDatasetsFor);
+// the API key and polling settings come from cfg.
+func NewBrightDataProvider(cfg *config.Config, datasetID string, model string, costService CostService) AIProvider {
 	return &brightDataProvider{
 		apiKey:      cfg.BrightDataAPIKey,
-		datasetID:   cfg.BrightDataDatasetID,
+		datasetID:   datasetID,
 		baseURL:     "https://api.brightdata.com/datasets/v3",
 		costService: costService,
 		httpClient: &http.Client{
 			Timeout: 20 * time.Minute, // Long timeout for async operations
 		},
+		poll: pollConfigFromConfig(cfg),
 	}
 }
 
@@ -40,6 +44,11 @@ func (p *brightDataProvider) GetProviderName() string {
 	return "brightdata"
 }
 
+// DatasetID is the BrightData dataset jobs are submitted to.
+func (p *brightDataProvider) DatasetID() string {
+	return p.datasetID
+}
+
 // BrightData API request structures
 type BrightDataRequest struct {
 	Input []BrightDataInput `json:"input"`
@@ -96,7 +105,10 @@ type BrightDataInputEcho struct {
 	AdditionalPrompt string `json:"additional_prompt"`
 }
 
-func (p *brightDataProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+func (p *brightDataProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	fmt.Printf("[BrightDataProvider] 🚀 Making BrightData call for query: %s\n", query)
 
 	// 1. Submit job to BrightData
@@ -134,14 +146,18 @@ func (p *brightDataProvider) RunQuestion(ctx context.Context, query string, webs
 	// 4. Handle response - use answer_text_markdown if available, otherwise create failed response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[BrightDataProvider] ⚠️ BrightData returned error: %s\n", result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[BrightDataProvider] ⚠️ BrightData returned empty answer_text_markdown\n")
 	} else {
 		// Fix citations in the response text by converting [position] to [position](url)
@@ -168,6 +184,8 @@ func (p *brightDataProvider) RunQuestion(ctx context.Context, query string, webs
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               citations,
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}, nil
 }
 
@@ -255,41 +273,25 @@ func (p *brightDataProvider) submitJob(ctx context.Context, query string, locati
 	}
 
 	fmt.Printf("[BrightDataProvider] ❌ Trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("BrightData API returned status %d: %s", lastStatus, lastBody)
+	return "", newHTTPStatusError("BrightData", lastStatus, lastBody)
 }
 
-func (p *brightDataProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*BrightDataResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[BrightDataProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue // Retry on error
-			}
-
-			fmt.Printf("[BrightDataProvider] 📊 Job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[BrightDataProvider] ✅ Job completed after %d polls, retrieving results\n", pollCount)
-				return p.getResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("BrightData job failed for snapshot %s", snapshotID)
-			}
-
-			// Continue polling if status is "running" or other non-terminal states
+// WaitForJob polls the job's progress with backoff until it is ready.
+func (p *brightDataProvider) WaitForJob(ctx context.Context, jobID string) error {
+	return pollJobStatus(ctx, p.poll, "BrightDataProvider", jobID, func(ctx context.Context) (string, error) {
+		status, err := p.checkProgress(ctx, jobID)
+		if err != nil {
+			return "", err
 		}
+		return status.Status, nil
+	})
+}
+
+func (p *brightDataProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*BrightDataResult, error) {
+	if err := p.WaitForJob(ctx, snapshotID); err != nil {
+		return nil, err
 	}
+	return p.getResults(ctx, snapshotID)
 }
 
 func (p *brightDataProvider) checkProgress(ctx context.Context, snapshotID string) (*BrightDataProgressResponse, error) {
@@ -367,13 +369,28 @@ func (p *brightDataProvider) SupportsBatching() bool {
 	return true
 }
 
+// SupportsWebSearch returns true for BrightData (web_search is passed through to the ChatGPT scraper)
+func (p *brightDataProvider) SupportsWebSearch() bool {
+	return true
+}
+
 // GetMaxBatchSize returns 20 for BrightData (can batch up to 20 questions)
 func (p *brightDataProvider) GetMaxBatchSize() int {
 	return 1 // 20
 }
 
-// RunQuestionBatch processes multiple questions in a single BrightData API call
-func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+// RunQuestionBatch awaits the batch job, like RunQuestionBatchAwait.
+func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatchAwait(ctx, batch, websearch, location)
+}
+
+// RunQuestionBatchAwait processes multiple questions in a single BrightData API call:
+// submit, WaitForJob, then retrieve the snapshot.
+func (p *brightDataProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) (out []*AIResponse, err error) {
+	defer stampBatchLatency(time.Now(), &out)
+	defer sanitizeBatchResponses(&out)
+
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[BrightDataProvider] 🚀 Making batched BrightData call for %d queries\n", len(queries))
 
 	if len(queries) > 20 {
@@ -387,18 +404,12 @@ func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, queries []str
 	}
 	queries = localizedQueries
 
-	// 1. Submit batch job to BrightData
-	snapshotID, err := p.submitBatchJob(ctx, queries, location, websearch)
+	// 1-2. Submit the batch job, wait for it and retrieve its results
+	results, err := awaitJob(ctx, p, "BrightData", func(ctx context.Context) (string, error) {
+		return p.submitBatchJob(ctx, queries, location, websearch)
+	}, p.getBatchResults)
 	if err != nil {
-		return nil, fmt.Errorf("failed to submit BrightData batch job: %w", err)
-	}
-
-	fmt.Printf("[BrightDataProvider] 📋 Batch job submitted with snapshot ID: %s\n", snapshotID)
-
-	// 2. Poll until completion
-	results, err := p.pollBatchUntilComplete(ctx, snapshotID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to poll BrightData batch job: %w", err)
+		return nil, err
 	}
 
 	fmt.Printf("[BrightDataProvider] 📊 Retrieved %d results for %d queries\n", len(results), len(queries))
@@ -482,7 +493,7 @@ func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, queries []str
 				prompt = result.Input.Prompt // Error results have prompt in input
 			}
 			if prompt != "" {
-				allResults[prompt] = result
+				allResults[normalizeQuestionText(prompt)] = result
 			}
 		}
 		// Add any results that had invalid indices
@@ -497,16 +508,17 @@ func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, queries []str
 					prompt = results[i].Input.Prompt
 				}
 				if prompt != "" {
-					allResults[prompt] = &results[i]
+					allResults[normalizeQuestionText(prompt)] = &results[i]
 				}
 			}
 		}
 
 		fmt.Printf("[BrightDataProvider] 📊 Built result map with %d prompts\n", len(allResults))
 
-		// Match each query to its result by prompt text
+		// Match each query to its result by prompt text (normalized, so echoed
+		// whitespace or quote changes still match)
 		for i, query := range queries {
-			result, exists := allResults[query]
+			result, exists := allResults[normalizeQuestionText(query)]
 			if !exists {
 				return nil, fmt.Errorf("no result found for query: %q (have %d results)", query, len(allResults))
 			}
@@ -518,6 +530,7 @@ func (p *brightDataProvider) RunQuestionBatch(ctx context.Context, queries []str
 	fmt.Printf("[BrightDataProvider] ✅ Batch completed: %d questions processed, total cost: $%.4f\n",
 		len(responses), float64(len(responses))*0.0015)
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
 
@@ -543,14 +556,18 @@ func (p *brightDataProvider) convertResultToResponse(result *BrightDataResult, d
 	// Handle response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[BrightDataProvider] ⚠️ Question %d returned error: %s\n", displayIndex, result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[BrightDataProvider] ⚠️ Question %d returned empty answer_text_markdown\n", displayIndex)
 	} else {
 		// Fix citations in the response text by converting [position] to [position](url)
@@ -569,6 +586,8 @@ func (p *brightDataProvider) convertResultToResponse(result *BrightDataResult, d
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               citations,
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}
 }
 
@@ -652,40 +671,7 @@ func (p *brightDataProvider) submitBatchJob(ctx context.Context, queries []strin
 	}
 
 	fmt.Printf("[BrightDataProvider] ❌ Batch trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("BrightData API returned status %d: %s", lastStatus, lastBody)
-}
-
-// pollBatchUntilComplete polls for batch completion and returns all results
-func (p *brightDataProvider) pollBatchUntilComplete(ctx context.Context, snapshotID string) ([]BrightDataResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[BrightDataProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue
-			}
-
-			fmt.Printf("[BrightDataProvider] 📊 Batch job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[BrightDataProvider] ✅ Batch job completed after %d polls, retrieving results\n", pollCount)
-				return p.getBatchResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("BrightData batch job failed for snapshot %s", snapshotID)
-			}
-		}
-	}
+	return "", newHTTPStatusError("BrightData", lastStatus, lastBody)
 }
 
 // getBatchResults retrieves all results from a completed batch job
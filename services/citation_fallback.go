@@ -0,0 +1,65 @@
+// services/citation_fallback.go
+package services
+
+import (
+	"strings"
+
+	"mvdan.cc/xurls/v2"
+)
+
+// The per-claim citation prompt is deliberately conservative and often
+// returns nothing, even for claims with an inline or footnote URL right next
+// to them. With CITATION_REGEX_FALLBACK (--citation-regex-fallback in the
+// smoke test) enabled, a claim the LLM found no citations for gets the URLs of
+// its context instead, classified primary/secondary by domain like the
+// network org regex extraction.
+
+// citationFallbackURL is one URL found by the regex fallback.
+type citationFallbackURL struct {
+	URL  string
+	Type string
+}
+
+// regexCitationsForClaim returns the distinct URLs in the claim's context,
+// in order of appearance.
+func regexCitationsForClaim(claimText, response string, orgWebsites []string) []citationFallbackURL {
+	var found []citationFallbackURL
+	seen := make(map[string]bool)
+	orgDomains := normalizeOrgWebsites(orgWebsites)
+	for _, match := range xurls.Relaxed().FindAllString(claimContext(claimText, response), -1) {
+		url := strings.TrimSpace(match)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+
+		normalizedURL := strings.ToLower(url)
+		if !strings.HasPrefix(normalizedURL, "http://") && !strings.HasPrefix(normalizedURL, "https://") {
+			normalizedURL = "https://" + normalizedURL
+		}
+		citationType := "secondary"
+		if isPrimaryDomain(strings.TrimRight(normalizedURL, "/"), orgDomains) {
+			citationType = "primary"
+		}
+		found = append(found, citationFallbackURL{URL: url, Type: citationType})
+	}
+	return found
+}
+
+// claimContext is the claim plus the rest of the line(s) it sits on in the
+// response, where inline links and footnote markers usually are. Claims not
+// found verbatim in the response fall back to the claim text alone.
+func claimContext(claimText, response string) string {
+	idx := strings.Index(response, claimText)
+	if claimText == "" || idx < 0 {
+		return claimText
+	}
+	start := strings.LastIndex(response[:idx], "\n") + 1
+	end := idx + len(claimText)
+	if next := strings.Index(response[end:], "\n"); next >= 0 {
+		end += next
+	} else {
+		end = len(response)
+	}
+	return response[start:end]
+}
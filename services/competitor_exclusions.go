@@ -0,0 +1,222 @@
+// services/competitor_exclusions.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Competitor extraction keeps returning regulators, card networks and bureaus
+// ("NCUA", "FDIC", "Visa") that are not competitors. Extracted names matching
+// the exclusion list of the org's vertical are dropped before storage.
+//
+// The built-in lists can be extended without a deploy through a JSON file
+// (COMPETITOR_EXCLUSIONS_FILE); names are compared after canonicalization
+// (case, punctuation and corporate suffixes ignored, so "N.C.U.A." matches
+// "NCUA"), patterns are case-insensitive regexes matched against the raw name:
+//
+//	{
+//	  "verticals": {
+//	    "financial_services": {"names": ["Zelle"], "patterns": ["^federal home loan bank"]}
+//	  },
+//	  "orgs": {"<org-uuid>": "financial_services"}
+//	}
+//
+// Orgs can also be assigned with ORG_VERTICALS ("<org-uuid>=healthcare");
+// orgs not listed use COMPETITOR_EXCLUSION_VERTICAL. The vertical also selects
+// the extraction prompt framing (see extraction_vertical.go).
+
+// CompetitorExclusionList is one vertical's exclusion list as written in the file.
+type CompetitorExclusionList struct {
+	Names    []string `json:"names"`
+	Patterns []string `json:"patterns"`
+}
+
+// defaultCompetitorExclusions ship with the service; the file extends them.
+var defaultCompetitorExclusions = map[string]CompetitorExclusionList{
+	"financial_services": {
+		Names: []string{
+			"NCUA", "National Credit Union Administration",
+			"FDIC", "Federal Deposit Insurance Corporation",
+			"CFPB", "Consumer Financial Protection Bureau",
+			"OCC", "Office of the Comptroller of the Currency",
+			"Federal Reserve", "The Fed", "SEC", "FINRA", "IRS",
+			"Fannie Mae", "Freddie Mac", "HUD", "FHA",
+			"Visa", "Mastercard", "Equifax", "Experian", "TransUnion", "FICO",
+		},
+		Patterns: []string{
+			`^federal reserve bank( of .+)?$`,
+			`^(u\.?s\.? )?department of (the )?(treasury|housing and urban development)$`,
+		},
+	},
+}
+
+type competitorExclusionFile struct {
+	Verticals map[string]CompetitorExclusionList `json:"verticals"`
+	Orgs      map[string]string                  `json:"orgs"`
+}
+
+type compiledExclusionList struct {
+	names    map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// CompetitorExclusions resolves and applies the exclusion list of an org.
+type CompetitorExclusions struct {
+	defaultVertical string
+	verticals       map[string]*compiledExclusionList
+	orgs            map[uuid.UUID]string
+}
+
+// LoadCompetitorExclusions builds the exclusions from the defaults plus the
+// optional file at path (empty path = defaults only).
+func LoadCompetitorExclusions(path string, defaultVertical string) (*CompetitorExclusions, error) {
+	lists := make(map[string]CompetitorExclusionList, len(defaultCompetitorExclusions))
+	for vertical, list := range defaultCompetitorExclusions {
+		lists[vertical] = list
+	}
+
+	var file competitorExclusionFile
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read competitor exclusions file: %w", err)
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse competitor exclusions file: %w", err)
+		}
+	}
+	for vertical, extra := range file.Verticals {
+		vertical = strings.ToLower(strings.TrimSpace(vertical))
+		list := lists[vertical]
+		list.Names = append(append([]string(nil), list.Names...), extra.Names...)
+		list.Patterns = append(append([]string(nil), list.Patterns...), extra.Patterns...)
+		lists[vertical] = list
+	}
+
+	e := &CompetitorExclusions{
+		defaultVertical: strings.ToLower(strings.TrimSpace(defaultVertical)),
+		verticals:       make(map[string]*compiledExclusionList, len(lists)),
+		orgs:            make(map[uuid.UUID]string, len(file.Orgs)),
+	}
+	for vertical, list := range lists {
+		compiled := &compiledExclusionList{names: make(map[string]bool, len(list.Names))}
+		for _, name := range list.Names {
+			if key := canonicalCompetitorName(name); key != "" {
+				compiled.names[key] = true
+			}
+		}
+		for _, pattern := range list.Patterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid competitor exclusion pattern %q for %s: %w", pattern, vertical, err)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+		e.verticals[vertical] = compiled
+	}
+	for orgID, vertical := range file.Orgs {
+		id, err := uuid.Parse(strings.TrimSpace(orgID))
+		if err != nil {
+			return nil, fmt.Errorf("invalid org ID %q in competitor exclusions file: %w", orgID, err)
+		}
+		e.orgs[id] = strings.ToLower(strings.TrimSpace(vertical))
+	}
+	return e, nil
+}
+
+// newCompetitorExclusions loads the exclusions configured in cfg, falling back
+// to the built-in lists when the file cannot be loaded, and applies the
+// ORG_VERTICALS assignments on top of the file's.
+func newCompetitorExclusions(cfg *config.Config) *CompetitorExclusions {
+	exclusions, err := LoadCompetitorExclusions(cfg.CompetitorExclusionsFile, cfg.CompetitorExclusionVertical)
+	if err != nil {
+		fmt.Printf("[newCompetitorExclusions] ⚠️ Competitor exclusions file not loaded, using built-in lists: %v\n", err)
+		exclusions, _ = LoadCompetitorExclusions("", cfg.CompetitorExclusionVertical)
+	}
+	for orgID, vertical := range cfg.OrgVerticals {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			fmt.Printf("[newCompetitorExclusions] ⚠️ Ignoring ORG_VERTICALS entry %q: %v\n", orgID, err)
+			continue
+		}
+		exclusions.orgs[id] = vertical
+	}
+	return exclusions
+}
+
+// VerticalForOrg returns the vertical assigned to the org, or the default
+// vertical (DefaultExtractionVertical for a nil receiver).
+func (e *CompetitorExclusions) VerticalForOrg(orgID uuid.UUID) string {
+	if e == nil {
+		return DefaultExtractionVertical
+	}
+	if vertical, ok := e.orgs[orgID]; ok {
+		return vertical
+	}
+	return e.defaultVertical
+}
+
+// Filter splits names into those kept and those excluded for orgID's vertical.
+// A nil receiver or an unknown vertical excludes nothing.
+func (e *CompetitorExclusions) Filter(orgID uuid.UUID, names []string) (kept []string, excluded []string) {
+	if e == nil {
+		return names, nil
+	}
+	list := e.verticals[e.VerticalForOrg(orgID)]
+	if list == nil {
+		return names, nil
+	}
+
+	kept = make([]string, 0, len(names))
+	for _, name := range names {
+		if list.matches(name) {
+			excluded = append(excluded, name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	return kept, excluded
+}
+
+func (l *compiledExclusionList) matches(name string) bool {
+	if l.names[canonicalCompetitorName(name)] {
+		return true
+	}
+	trimmed := strings.TrimSpace(name)
+	for _, re := range l.patterns {
+		if re.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalCompetitorName reduces a name to a comparison key: lowercase,
+// trailing corporate suffixes dropped, punctuation and spaces removed
+// ("N.C.U.A." -> "ncua", "Visa, Inc." -> "visa").
+func canonicalCompetitorName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '&'
+	})
+	for len(words) > 1 && corporateSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	var b strings.Builder
+	for _, word := range words {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
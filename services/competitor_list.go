@@ -0,0 +1,106 @@
+// services/competitor_list.go
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// CompetitorListOptions controls how a competitor list is split and capped.
+type CompetitorListOptions struct {
+	// Delimiters are the characters a single joined element is split on. A
+	// comma separates names within a line (corporate suffixes after it are
+	// re-attached); every other delimiter separates lines.
+	Delimiters string
+	// MaxNames keeps only the first names of a list; 0 keeps all.
+	MaxNames int
+	// MaxNameLength drops names longer than this (in runes); 0 keeps all.
+	MaxNameLength int
+}
+
+// DefaultCompetitorListDelimiters splits on commas, semicolons and newlines.
+const DefaultCompetitorListDelimiters = ",;\n"
+
+// competitorListOptions returns the configured options, with the default
+// delimiters when none are set.
+func competitorListOptions(cfg *config.Config) CompetitorListOptions {
+	opts := CompetitorListOptions{Delimiters: DefaultCompetitorListDelimiters}
+	if cfg == nil {
+		return opts
+	}
+	if cfg.CompetitorListDelimiters != "" {
+		opts.Delimiters = cfg.CompetitorListDelimiters
+	}
+	opts.MaxNames = cfg.CompetitorListMaxNames
+	opts.MaxNameLength = cfg.CompetitorNameMaxLength
+	return opts
+}
+
+// corporateSuffixes are comma-separated tails that belong to the preceding
+// name ("Acme, Inc."), so splitting a joined list must not turn them into
+// competitors of their own.
+var corporateSuffixes = map[string]bool{
+	"inc": true, "inc.": true, "llc": true, "l.l.c.": true, "ltd": true, "ltd.": true,
+	"co": true, "co.": true, "corp": true, "corp.": true, "plc": true, "gmbh": true,
+	"ag": true, "sa": true, "s.a.": true, "lp": true, "llp": true, "n.a.": true,
+}
+
+// Names returns the competitor names, trimmed and deduplicated
+// (case-insensitive, first spelling wins) and capped by opts. Models sometimes
+// return the whole list as one comma- or newline-joined string (["A, B, C"]);
+// a single element like that is split back into separate names.
+func (r CompetitorListResponse) Names(opts CompetitorListOptions) []string {
+	raw := r.Competitors
+	if len(raw) == 1 && opts.Delimiters != "" && strings.ContainsAny(raw[0], opts.Delimiters) {
+		raw = splitJoinedCompetitors(raw[0], opts.Delimiters)
+	}
+
+	seen := make(map[string]bool, len(raw))
+	names := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		if name == "" || (opts.MaxNameLength > 0 && utf8.RuneCountInString(name) > opts.MaxNameLength) {
+			continue
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+		if opts.MaxNames > 0 && len(names) == opts.MaxNames {
+			break
+		}
+	}
+	return names
+}
+
+// splitJoinedCompetitors splits into lines on the delimiters other than the
+// comma, strips list bullets, splits lines on commas (when a delimiter) and
+// re-attaches corporate suffixes to the name before them.
+func splitJoinedCompetitors(joined, delimiters string) []string {
+	lineDelimiters := strings.ReplaceAll(delimiters, ",", "")
+	commas := strings.Contains(delimiters, ",")
+	var parts []string
+	for _, line := range strings.FieldsFunc(joined, func(r rune) bool { return strings.ContainsRune(lineDelimiters, r) }) {
+		line = strings.TrimLeft(strings.TrimSpace(line), "-*• ")
+		items := []string{line}
+		if commas {
+			items = strings.Split(line, ",")
+		}
+		for _, part := range items {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if corporateSuffixes[strings.ToLower(part)] && len(parts) > 0 {
+				parts[len(parts)-1] += ", " + part
+				continue
+			}
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
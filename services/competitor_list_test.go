@@ -0,0 +1,93 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+func TestCompetitorListNames(t *testing.T) {
+	defaults := competitorListOptions(nil)
+
+	tests := []struct {
+		name        string
+		competitors []string
+		opts        CompetitorListOptions
+		want        []string
+	}{
+		{
+			name:        "comma-joined single element",
+			competitors: []string{"A, B, C"},
+			opts:        defaults,
+			want:        []string{"A", "B", "C"},
+		},
+		{
+			name:        "bulleted lines and corporate suffixes",
+			competitors: []string{"- Acme, Inc.\n* Globex Corp; Initech, LLC"},
+			opts:        defaults,
+			want:        []string{"Acme, Inc.", "Globex Corp", "Initech, LLC"},
+		},
+		{
+			name:        "already a list is not split",
+			competitors: []string{"Acme, Inc.", " Globex ", "acme, inc.", ""},
+			opts:        defaults,
+			want:        []string{"Acme, Inc.", "Globex"},
+		},
+		{
+			name:        "configured delimiters",
+			competitors: []string{"A, B | C"},
+			opts:        CompetitorListOptions{Delimiters: "|"},
+			want:        []string{"A, B", "C"},
+		},
+		{
+			name:        "no delimiters keeps the element",
+			competitors: []string{"A, B, C"},
+			opts:        CompetitorListOptions{},
+			want:        []string{"A, B, C"},
+		},
+		{
+			name:        "max names",
+			competitors: []string{"A, B, a, C"},
+			opts:        CompetitorListOptions{Delimiters: ",", MaxNames: 2},
+			want:        []string{"A", "B"},
+		},
+		{
+			name:        "max name length",
+			competitors: []string{"Acme", "A very long sentence that is not a company name"},
+			opts:        CompetitorListOptions{MaxNameLength: 20},
+			want:        []string{"Acme"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompetitorListResponse{Competitors: tt.competitors}.Names(tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Names() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompetitorListOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want CompetitorListOptions
+	}{
+		{name: "nil config", cfg: nil, want: CompetitorListOptions{Delimiters: DefaultCompetitorListDelimiters}},
+		{name: "empty delimiters use the default", cfg: &config.Config{CompetitorListMaxNames: 5}, want: CompetitorListOptions{Delimiters: DefaultCompetitorListDelimiters, MaxNames: 5}},
+		{
+			name: "configured",
+			cfg:  &config.Config{CompetitorListDelimiters: "|", CompetitorListMaxNames: 10, CompetitorNameMaxLength: 80},
+			want: CompetitorListOptions{Delimiters: "|", MaxNames: 10, MaxNameLength: 80},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := competitorListOptions(tt.cfg); got != tt.want {
+				t.Errorf("competitorListOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// services/content_filter.go
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openai/openai-go"
+)
+
+// Azure OpenAI sometimes blocks an extraction prompt (usually because the
+// quoted response text trips a filter) and returns finish_reason
+// "content_filter" with empty content. Parsing that content fails with
+// "unexpected end of JSON input", which looks like a parse bug; the same
+// prompt is filtered again on every retry, so callers treat it as a terminal
+// "filtered" outcome instead.
+
+// ErrContentFiltered matches any *ContentFilteredError via errors.Is.
+var ErrContentFiltered = errors.New("response blocked by content filter")
+
+// ContentFilteredError is returned by extraction calls whose completion was
+// blocked by the content filter. Categories lists the filter categories that
+// fired (e.g. "hate", "violence") when the provider reports them.
+type ContentFilteredError struct {
+	Call       string
+	Categories []string
+}
+
+func (e *ContentFilteredError) Error() string {
+	if len(e.Categories) == 0 {
+		return fmt.Sprintf("%s: %v", e.Call, ErrContentFiltered)
+	}
+	return fmt.Sprintf("%s: %v (%s)", e.Call, ErrContentFiltered, strings.Join(e.Categories, ", "))
+}
+
+func (e *ContentFilteredError) Is(target error) bool {
+	return target == ErrContentFiltered
+}
+
+// checkContentFilter returns a *ContentFilteredError when the first choice
+// of the completion finished because of the content filter.
+func checkContentFilter(call string, chatResponse *openai.ChatCompletion) error {
+	if chatResponse == nil || len(chatResponse.Choices) == 0 {
+		return nil
+	}
+	choice := chatResponse.Choices[0]
+	if choice.FinishReason != "content_filter" {
+		return nil
+	}
+	return &ContentFilteredError{Call: call, Categories: contentFilterCategories(choice)}
+}
+
+// contentFilterCategories reads Azure's content_filter_results extension of
+// the choice, e.g. {"hate": {"filtered": true, "severity": "medium"}}, and
+// returns the categories marked filtered.
+func contentFilterCategories(choice openai.ChatCompletionChoice) []string {
+	field, ok := choice.JSON.ExtraFields["content_filter_results"]
+	if !ok {
+		return nil
+	}
+	var results map[string]struct {
+		Filtered bool `json:"filtered"`
+	}
+	if err := json.Unmarshal([]byte(field.Raw()), &results); err != nil {
+		return nil
+	}
+	var categories []string
+	for category, result := range results {
+		if result.Filtered {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// extractionFailureStatus is the stage status for a failed extraction call:
+// filtered responses are final, anything else is retried by a repair.
+func extractionFailureStatus(err error) StageStatus {
+	if errors.Is(err, ErrContentFiltered) {
+		return StageFiltered
+	}
+	return StageFailed
+}
@@ -0,0 +1,33 @@
+// services/correlation.go
+package services
+
+import "context"
+
+// One question run logs from many places (provider call, each extraction
+// stage). ProcessSingleQuestion puts a correlation ID in the context and the
+// run's log lines carry it as "cid=<id>" so they can be grepped together.
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns ctx carrying the correlation ID id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// correlationID returns the correlation ID in ctx, or "" when there is none.
+func correlationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// correlationTag returns tag for a "[tag]" log prefix, followed by the context's
+// correlation ID when it has one.
+func correlationTag(ctx context.Context, tag string) string {
+	if id := correlationID(ctx); id != "" {
+		return tag + " cid=" + id
+	}
+	return tag
+}
@@ -0,0 +1,247 @@
+// services/cost_rollup.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Monthly AI spend of an org or network: question run (provider call) costs
+// plus every extraction call made on those runs, broken down by source,
+// category, provider and model. Everything is aggregated in SQL, like the
+// weekly load report.
+//
+// Double counting: a run's total_cost is its provider call only; extraction
+// calls are stored on their own rows and added separately. Those rows repeat
+// the cost of the call that produced them (every mention of a response
+// carries the mentions call's cost, every citation of a claim the citation
+// call's), so extraction costs are taken once per call: per run for mentions
+// and claims, per claim for citations and per run and org for competitors.
+// Evaluations are one row per call. Nil costs count as zero.
+//
+// Scope: an org's rollup covers its own questions' runs and their
+// extractions plus its evaluations and competitors on its network's runs; the
+// network runs themselves belong to the network's rollup. Billed is what the
+// usage service charged for the scope's runs (credit_ledger), not spend.
+//
+// Source: runs of batches whose batch_type contains "fixer" are "fixer",
+// other runs "pipeline". Extraction created a day or more after its run is
+// "reeval" (re-evaluations replace the extraction of old runs); other
+// extraction takes its run's source.
+
+// CostScope is what a cost rollup is for.
+type CostScope string
+
+const (
+	CostScopeOrg     CostScope = "org"
+	CostScopeNetwork CostScope = "network"
+)
+
+// Cost rollup sources.
+const (
+	CostSourcePipeline = "pipeline"
+	CostSourceFixer    = "fixer"
+	CostSourceReeval   = "reeval"
+)
+
+// extractionCostProvider is the provider of every extraction call; the
+// extraction rows don't record their model.
+const extractionCostProvider = "openai"
+
+// CostRollupLine is the spend of one source, category, provider and model.
+// Calls counts provider or extraction calls, not rows.
+type CostRollupLine struct {
+	Source   string  `db:"source" json:"source"`
+	Category string  `db:"category" json:"category"`
+	Provider string  `db:"provider" json:"provider"`
+	Model    string  `db:"model" json:"model"`
+	Calls    int     `db:"calls" json:"calls"`
+	Cost     float64 `db:"cost" json:"cost"`
+}
+
+// CostRollup is the spend of an org or network in [MonthStart, MonthEnd).
+type CostRollup struct {
+	Scope      CostScope        `json:"scope"`
+	ScopeID    uuid.UUID        `json:"scope_id"`
+	MonthStart time.Time        `json:"month_start"`
+	MonthEnd   time.Time        `json:"month_end"`
+	Lines      []CostRollupLine `json:"lines"`
+	TotalCost  float64          `json:"total_cost"`
+	// Billed is the usage charged for the scope's runs in the month.
+	Billed float64 `json:"billed"`
+}
+
+// TotalsBy sums the lines' cost per key(line).
+func (r *CostRollup) TotalsBy(key func(CostRollupLine) string) map[string]float64 {
+	totals := make(map[string]float64)
+	for _, line := range r.Lines {
+		totals[key(line)] += line.Cost
+	}
+	return totals
+}
+
+// MonthWindow returns the UTC calendar month containing t.
+func MonthWindow(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// ParseMonth parses a "2006-01" month.
+func ParseMonth(s string) (time.Time, error) {
+	month, err := time.Parse("2006-01", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q (want YYYY-MM): %w", s, err)
+	}
+	return month, nil
+}
+
+// CostRollupRepository aggregates monthly spend.
+type CostRollupRepository interface {
+	Rollup(ctx context.Context, scope CostScope, scopeID uuid.UUID, month time.Time) (*CostRollup, error)
+}
+
+type costRollupRepo struct {
+	db *database.Client
+}
+
+func NewCostRollupRepo(db *database.Client) CostRollupRepository {
+	return &costRollupRepo{db: db}
+}
+
+// runCostSourceSQL classifies a run by its batch.
+const runCostSourceSQL = `CASE WHEN b.batch_type ILIKE '%fixer%' THEN 'fixer' ELSE 'pipeline' END`
+
+// extractionCalls are the per-call subqueries of the extraction categories:
+// question_run_id, org_id (NULL for the org-independent stages), cost and
+// created_at of each call in the window ($2, $3).
+var extractionCalls = []struct {
+	category string
+	query    string
+	perOrg   bool
+}{
+	{"mentions", `
+		SELECT question_run_id, NULL::uuid AS org_id, MAX(total_cost) AS cost, MIN(created_at) AS created_at
+		FROM question_run_mentions
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY question_run_id`, false},
+	{"claims", `
+		SELECT question_run_id, NULL::uuid AS org_id, MAX(total_cost) AS cost, MIN(created_at) AS created_at
+		FROM question_run_claims
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY question_run_id`, false},
+	{"citations", `
+		SELECT cl.question_run_id, NULL::uuid AS org_id, MAX(c.total_cost) AS cost, MIN(c.created_at) AS created_at
+		FROM question_run_citations c
+		JOIN question_run_claims cl ON cl.question_run_claim_id = c.question_run_claim_id
+		WHERE c.created_at >= $2 AND c.created_at < $3
+		GROUP BY c.question_run_claim_id, cl.question_run_id`, false},
+	{"org_evals", `
+		SELECT question_run_id, org_id, total_cost AS cost, created_at
+		FROM org_evals
+		WHERE created_at >= $2 AND created_at < $3`, true},
+	{"org_competitors", `
+		SELECT question_run_id, org_id, MAX(total_cost) AS cost, MIN(created_at) AS created_at
+		FROM org_competitors
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY question_run_id, org_id`, true},
+	{"network_org_evals", `
+		SELECT question_run_id, org_id, total_cost AS cost, created_at
+		FROM network_org_evals
+		WHERE created_at >= $2 AND created_at < $3`, true},
+	{"network_org_competitors", `
+		SELECT question_run_id, org_id, MAX(total_cost) AS cost, MIN(created_at) AS created_at
+		FROM network_org_competitors
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY question_run_id, org_id`, true},
+}
+
+func (r *costRollupRepo) Rollup(ctx context.Context, scope CostScope, scopeID uuid.UUID, month time.Time) (*CostRollup, error) {
+	var runScope string
+	switch scope {
+	case CostScopeOrg:
+		runScope = "gq.org_id = $1"
+	case CostScopeNetwork:
+		runScope = "gq.network_id = $1"
+	default:
+		return nil, fmt.Errorf("unknown cost scope %q", scope)
+	}
+	start, end := MonthWindow(month)
+	rollup := &CostRollup{Scope: scope, ScopeID: scopeID, MonthStart: start, MonthEnd: end}
+
+	var runLines []CostRollupLine
+	if err := r.db.SelectContext(ctx, &runLines, `
+		SELECT `+runCostSourceSQL+` AS source, 'question_runs' AS category, `+runProviderSQL+` AS provider,
+		       COALESCE(qr.run_model, '') AS model, COUNT(*) AS calls, COALESCE(SUM(qr.total_cost), 0) AS cost
+		FROM question_runs qr
+		JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+		LEFT JOIN question_run_batches b ON b.batch_id = qr.batch_id
+		WHERE `+runScope+` AND qr.created_at >= $2 AND qr.created_at < $3
+		GROUP BY 1, 2, 3, 4`, scopeID, start, end); err != nil {
+		return nil, fmt.Errorf("failed to roll up question run costs: %w", err)
+	}
+	rollup.Lines = append(rollup.Lines, runLines...)
+
+	for _, calls := range extractionCalls {
+		// An org's evaluations count on any run, its network's included
+		callScope := runScope
+		if scope == CostScopeOrg && calls.perOrg {
+			callScope = "x.org_id = $1"
+		}
+		var lines []CostRollupLine
+		query := fmt.Sprintf(`
+			SELECT CASE WHEN x.created_at >= qr.created_at + INTERVAL '1 day' THEN 'reeval' ELSE %s END AS source,
+			       '%s' AS category, '%s' AS provider, '' AS model,
+			       COUNT(*) AS calls, COALESCE(SUM(x.cost), 0) AS cost
+			FROM (%s) x
+			JOIN question_runs qr ON qr.question_run_id = x.question_run_id
+			JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+			LEFT JOIN question_run_batches b ON b.batch_id = qr.batch_id
+			WHERE %s
+			GROUP BY 1, 2, 3, 4`, runCostSourceSQL, calls.category, extractionCostProvider, calls.query, callScope)
+		if err := r.db.SelectContext(ctx, &lines, query, scopeID, start, end); err != nil {
+			return nil, fmt.Errorf("failed to roll up %s costs: %w", calls.category, err)
+		}
+		rollup.Lines = append(rollup.Lines, lines...)
+	}
+
+	sort.Slice(rollup.Lines, func(i, j int) bool {
+		a, b := rollup.Lines[i], rollup.Lines[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.Model < b.Model
+	})
+	for _, line := range rollup.Lines {
+		rollup.TotalCost += line.Cost
+	}
+
+	billedScope := "cl.org_id = $1"
+	if scope == CostScopeNetwork {
+		billedScope = `cl.source_id IN (
+			SELECT qr.question_run_id::text
+			FROM question_runs qr
+			JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+			WHERE gq.network_id = $1)`
+	}
+	if err := r.db.GetContext(ctx, &rollup.Billed, `
+		SELECT COALESCE(-SUM(cl.amount), 0)
+		FROM credit_ledger cl
+		WHERE cl.source_type = 'question_run' AND cl.created_at >= $2 AND cl.created_at < $3 AND `+billedScope,
+		scopeID, start, end); err != nil {
+		return nil, fmt.Errorf("failed to roll up billed usage: %w", err)
+	}
+	return rollup, nil
+}
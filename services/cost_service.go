@@ -1,29 +1,40 @@
 // services/cost_service.go
 package services
 
-import "strings"
+import (
+	"fmt"
+	"strings"
 
-type costService struct{}
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
 
-func NewCostService() CostService {
-	return &costService{}
+type costService struct {
+	// debug logs every breakdown (COST_DEBUG)
+	debug bool
 }
 
-// Cost per 1M tokens
-var costPerToken = map[string]struct{ input, output float64 }{
-	"gpt-4.1":           {input: 2.00, output: 8.00},
-	"gpt-4o-2024-08-06": {input: 2.50, output: 10.00}, // GPT-4o structured outputs pricing
+func NewCostService(cfg *config.Config) CostService {
+	return &costService{debug: cfg != nil && cfg.CostDebug}
+}
+
+type tokenRates struct{ input, cached, output float64 }
+
+// Cost per 1M tokens. cached is the rate for cached input tokens (OpenAI
+// prompt caching); 0 means cached tokens are billed at the input rate.
+var costPerToken = map[string]tokenRates{
+	"gpt-4.1":           {input: 2.00, cached: 0.50, output: 8.00},
+	"gpt-4o-2024-08-06": {input: 2.50, cached: 1.25, output: 10.00}, // GPT-4o structured outputs pricing
 	// GPT-5 pricing (Standard) - per 1M text tokens
 	// Source: user-provided OpenAI pricing screenshot (Dec 2025)
-	"gpt-5.2":    {input: 1.75, output: 14.00},
-	"gpt-5.1":    {input: 1.25, output: 10.00},
-	"gpt-5":      {input: 1.25, output: 10.00},
-	"gpt-5-mini": {input: 0.25, output: 2.00},
-	"gpt-5-nano": {input: 0.05, output: 0.40},
+	"gpt-5.2":    {input: 1.75, cached: 0.175, output: 14.00},
+	"gpt-5.1":    {input: 1.25, cached: 0.125, output: 10.00},
+	"gpt-5":      {input: 1.25, cached: 0.125, output: 10.00},
+	"gpt-5-mini": {input: 0.25, cached: 0.025, output: 2.00},
+	"gpt-5-nano": {input: 0.05, cached: 0.005, output: 0.40},
 	// Chat-latest aliases at same rates
-	"gpt-5.2-chat-latest":      {input: 1.75, output: 14.00},
-	"gpt-5.1-chat-latest":      {input: 1.25, output: 10.00},
-	"gpt-5-chat-latest":        {input: 1.25, output: 10.00},
+	"gpt-5.2-chat-latest":      {input: 1.75, cached: 0.175, output: 14.00},
+	"gpt-5.1-chat-latest":      {input: 1.25, cached: 0.125, output: 10.00},
+	"gpt-5-chat-latest":        {input: 1.25, cached: 0.125, output: 10.00},
 	"claude-sonnet-4-20250514": {input: 3.00, output: 15.00},
 	"sonar":                    {input: 1.00, output: 1.00}, // Perplexity Sonar pricing (estimated)
 }
@@ -38,38 +49,107 @@ var costPerWebSearch = map[string]float64{
 	"linkup":     5.50, // Linkup pricing: €0.005 per search = $0.0055 per search = $5.50 per 1000 searches
 }
 
+// CostBreakdown shows how a cost was computed, for auditing. Rates are per 1M
+// tokens; RateKey is the pricing entry applied, from the providers config when
+// it prices the model (DefaultRate when the model was not found and gpt-4.1
+// pricing was used).
+type CostBreakdown struct {
+	Provider    string
+	Model       string
+	RateKey     string
+	DefaultRate bool
+	// InputTokens includes CachedInputTokens; InputCost covers only the uncached part.
+	InputTokens       int
+	CachedInputTokens int
+	OutputTokens      int
+	InputRate         float64
+	CachedInputRate   float64
+	OutputRate        float64
+	InputCost         float64
+	CachedInputCost   float64
+	OutputCost        float64
+	WebSearchCost     float64
+	TotalCost         float64
+}
+
+// String renders the breakdown with rates per 1k tokens, for log lines.
+func (b CostBreakdown) String() string {
+	return fmt.Sprintf("provider=%s model=%s rate_key=%s default_rate=%t in=%d cached_in=%d out=%d "+
+		"in_per_1k=$%.6f cached_in_per_1k=$%.6f out_per_1k=$%.6f in_cost=$%.6f cached_in_cost=$%.6f out_cost=$%.6f search_cost=$%.6f total=$%.6f",
+		b.Provider, b.Model, b.RateKey, b.DefaultRate, b.InputTokens, b.CachedInputTokens, b.OutputTokens,
+		b.InputRate/1000.0, b.CachedInputRate/1000.0, b.OutputRate/1000.0, b.InputCost, b.CachedInputCost, b.OutputCost, b.WebSearchCost, b.TotalCost)
+}
+
 func (s *costService) CalculateCost(provider string, model string, inputTokens int, outputTokens int, websearch bool) float64 {
+	return s.CalculateCostDetailed(provider, model, inputTokens, outputTokens, websearch).TotalCost
+}
+
+func (s *costService) CalculateCostDetailed(provider string, model string, inputTokens int, outputTokens int, websearch bool) CostBreakdown {
+	return s.CalculateCostCached(provider, model, inputTokens, 0, outputTokens, websearch)
+}
+
+// CalculateCostCached prices cachedInputTokens (a subset of inputTokens, as
+// reported by OpenAI's prompt_tokens_details.cached_tokens) at the model's
+// cached-input rate and the rest at the regular input rate.
+func (s *costService) CalculateCostCached(provider string, model string, inputTokens int, cachedInputTokens int, outputTokens int, websearch bool) CostBreakdown {
 	// Calculate token costs
 	modelKey := strings.ToLower(strings.TrimSpace(model))
+	rateKey := modelKey
 	modelCosts, exists := costPerToken[modelKey]
+	// The providers config takes precedence over the built-in table
+	configKey, entry, configured := providerModelConfig(model)
+	configured = configured && entry.Pricing != nil
+	if configured {
+		modelCosts = tokenRates{input: entry.Pricing.Input, cached: entry.Pricing.Cached, output: entry.Pricing.Output}
+		rateKey = configKey
+		exists = true
+	}
 	if !exists {
-		// Try prefix match (e.g. "gpt-5.2-2025-..." or other suffixed variants)
+		// Try prefix match (e.g. "gpt-5.2-2025-..." or other suffixed variants);
+		// the longest prefix wins so "gpt-5.2-..." is not priced as "gpt-5"
 		for k, v := range costPerToken {
-			if strings.HasPrefix(modelKey, k) {
+			if strings.HasPrefix(modelKey, k) && (!exists || len(k) > len(rateKey)) {
 				modelCosts = v
+				rateKey = k
 				exists = true
-				break
 			}
 		}
-		if !exists {
-			// Default to GPT-4.1 costs if model not found
-			modelCosts = costPerToken["gpt-4.1"]
-		}
 	}
+	b := CostBreakdown{Provider: provider, Model: model, InputTokens: inputTokens, OutputTokens: outputTokens}
+	if !exists {
+		// Default to GPT-4.1 costs if model not found
+		rateKey = "gpt-4.1"
+		modelCosts = costPerToken[rateKey]
+		b.DefaultRate = true
+	}
+	b.RateKey = rateKey
+	b.InputRate = modelCosts.input
+	b.CachedInputRate = modelCosts.cached
+	if b.CachedInputRate == 0 {
+		b.CachedInputRate = modelCosts.input
+	}
+	b.OutputRate = modelCosts.output
 
-	inputCost := (float64(inputTokens) / 1_000_000.0) * modelCosts.input
-	outputCost := (float64(outputTokens) / 1_000_000.0) * modelCosts.output
-	totalCost := inputCost + outputCost
+	b.CachedInputTokens = min(max(cachedInputTokens, 0), inputTokens)
+	b.InputCost = (float64(inputTokens-b.CachedInputTokens) / 1_000_000.0) * b.InputRate
+	b.CachedInputCost = (float64(b.CachedInputTokens) / 1_000_000.0) * b.CachedInputRate
+	b.OutputCost = (float64(outputTokens) / 1_000_000.0) * modelCosts.output
 
 	// Add web search cost if applicable
 	if websearch {
 		providerKey := s.getProviderKey(provider)
-		if searchCost, exists := costPerWebSearch[providerKey]; exists {
-			totalCost += searchCost / 1000.0
+		if configured && entry.Pricing.WebSearchPer1K != nil {
+			b.WebSearchCost = *entry.Pricing.WebSearchPer1K / 1000.0
+		} else if searchCost, exists := costPerWebSearch[providerKey]; exists {
+			b.WebSearchCost = searchCost / 1000.0
 		}
 	}
+	b.TotalCost = b.InputCost + b.CachedInputCost + b.OutputCost + b.WebSearchCost
 
-	return totalCost
+	if s.debug {
+		fmt.Printf("[CalculateCost] %s\n", b)
+	}
+	return b
 }
 
 func (s *costService) getProviderKey(provider string) string {
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateCostCached(t *testing.T) {
+	costs := NewCostService(nil)
+
+	tests := []struct {
+		name        string
+		model       string
+		input       int
+		cached      int
+		output      int
+		wantRateKey string
+		wantCached  int
+		wantTotal   float64
+		wantDefault bool
+		wantCheaper bool
+	}{
+		{
+			name: "uncached", model: "gpt-4.1", input: 1_000_000, output: 1_000_000,
+			wantRateKey: "gpt-4.1", wantTotal: 2.00 + 8.00,
+		},
+		{
+			name: "half cached", model: "gpt-4.1", input: 1_000_000, cached: 500_000, output: 1_000_000,
+			wantRateKey: "gpt-4.1", wantCached: 500_000, wantTotal: 1.00 + 0.25 + 8.00, wantCheaper: true,
+		},
+		{
+			name: "cached capped at input", model: "gpt-5", input: 1_000_000, cached: 2_000_000,
+			wantRateKey: "gpt-5", wantCached: 1_000_000, wantTotal: 0.125, wantCheaper: true,
+		},
+		{
+			name: "no cached rate bills cached at input rate", model: "sonar", input: 1_000_000, cached: 1_000_000,
+			wantRateKey: "sonar", wantCached: 1_000_000, wantTotal: 1.00,
+		},
+		{
+			name: "dated variant uses longest prefix", model: "gpt-5.2-2025-12-11", input: 1_000_000, cached: 1_000_000,
+			wantRateKey: "gpt-5.2", wantCached: 1_000_000, wantTotal: 0.175, wantCheaper: true,
+		},
+		{
+			name: "unknown model defaults to gpt-4.1", model: "mystery-model", input: 1_000_000,
+			wantRateKey: "gpt-4.1", wantTotal: 2.00, wantDefault: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := costs.CalculateCostCached("openai", tt.model, tt.input, tt.cached, tt.output, false)
+			if b.RateKey != tt.wantRateKey || b.DefaultRate != tt.wantDefault {
+				t.Errorf("rate key = %q (default %t), want %q (default %t)", b.RateKey, b.DefaultRate, tt.wantRateKey, tt.wantDefault)
+			}
+			if b.CachedInputTokens != tt.wantCached {
+				t.Errorf("CachedInputTokens = %d, want %d", b.CachedInputTokens, tt.wantCached)
+			}
+			if math.Abs(b.TotalCost-tt.wantTotal) > 1e-9 {
+				t.Errorf("TotalCost = %.6f, want %.6f", b.TotalCost, tt.wantTotal)
+			}
+			uncached := costs.CalculateCostDetailed("openai", tt.model, tt.input, tt.output, false)
+			if got := b.TotalCost < uncached.TotalCost; got != tt.wantCheaper {
+				t.Errorf("cached cost %.6f < uncached %.6f is %t, want %t", b.TotalCost, uncached.TotalCost, got, tt.wantCheaper)
+			}
+		})
+	}
+}
+
+func TestCalculateCostWebSearch(t *testing.T) {
+	costs := NewCostService(nil)
+	tests := []struct {
+		provider string
+		want     float64
+	}{
+		{provider: "openai", want: 0.010},
+		{provider: "perplexity", want: 0.008},
+		{provider: "linkup", want: 0.0055},
+		{provider: "unknown", want: 0.010},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			b := costs.CalculateCostDetailed(tt.provider, "gpt-4.1", 0, 0, true)
+			if math.Abs(b.WebSearchCost-tt.want) > 1e-9 {
+				t.Errorf("WebSearchCost = %.6f, want %.6f", b.WebSearchCost, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AI-Template-SDK/senso-api/pkg/models"
@@ -21,8 +23,16 @@ type dataExtractionService struct {
 	cfg          *config.Config
 	openAIClient *openai.Client
 	costService  CostService
+	features     OrgFeatureProvider
+	exclusions   *CompetitorExclusions
 }
 
+// NewDataExtractionService returns the extraction service. It is safe for
+// concurrent use: all goroutines share one openai.Client, which is never
+// modified after construction. Per-call behavior (model, token limit,
+// temperature or reasoning effort) lives in each request's params, and a
+// per-call HTTP timeout in its context (WithExtractionTimeout). The only
+// shared mutable state is the per-model sampling style cache, a sync.Map.
 func NewDataExtractionService(cfg *config.Config) DataExtractionService {
 	fmt.Printf("[NewDataExtractionService] Creating service with OpenAI key (length: %d)\n", len(cfg.OpenAIAPIKey))
 
@@ -52,41 +62,44 @@ func NewDataExtractionService(cfg *config.Config) DataExtractionService {
 	return &dataExtractionService{
 		cfg:          cfg,
 		openAIClient: &client,
-		costService:  NewCostService(),
+		costService:  NewCostService(cfg),
+		features:     NewConfigOrgFeatureProvider(cfg),
+		exclusions:   newCompetitorExclusions(cfg),
 	}
 }
 
 // ExtractMentions parses AI response and extracts company mentions
-func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRunID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error) {
-	fmt.Printf("[ExtractMentions] 🔍 Processing mentions for question run %s", questionRunID)
+func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error) {
+	fmt.Printf("[%s] 🔍 Processing mentions for question run %s", correlationTag(ctx, "ExtractMentions"), questionRunID)
 
-	prompt := s.buildMentionsExtractionPrompt(response, targetCompany, orgWebsites)
+	vertical := s.vertical(orgID)
+	prompt := s.buildMentionsExtractionPrompt(vertical, response, targetCompany, orgWebsites)
 
 	// Use a model that supports structured outputs
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
 		// Use Azure deployment name
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		fmt.Printf("[ExtractMentions] 🎯 Using Azure OpenAI deployment: %s", s.cfg.AzureOpenAIDeploymentName)
+		model = openai.ChatModel(azureFullDeployment(s.cfg))
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI deployment: %s", correlationTag(ctx, "ExtractMentions"), model)
 	} else {
 		// Use standard OpenAI model
 		model = openai.ChatModelGPT4_1
-		fmt.Printf("[ExtractMentions] 🎯 Using Standard OpenAI model: %s", model)
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s", correlationTag(ctx, "ExtractMentions"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
 		Name:        "company_mentions_extraction",
-		Description: openai.String("Extract mentions of financial institutions from AI response"),
+		Description: openai.String(fmt.Sprintf("Extract mentions of %s from AI response", vertical.Entities)),
 		Schema:      GenerateSchema[MentionsExtractionResponse](),
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[ExtractMentions] 🚀 Making AI call for mentions extraction...")
+	fmt.Printf("[%s] 🚀 Making AI call for mentions extraction...", correlationTag(ctx, "ExtractMentions"))
 
 	// Create the extraction request with structured output
 	params := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage("You are an expert financial services analyst specializing in credit unions and banks. Extract company mentions accurately and comprehensively."),
+			openai.SystemMessage(vertical.mentionsSystemMessage()),
 			openai.UserMessage(prompt),
 		},
 		Model: model,
@@ -95,30 +108,30 @@ func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRun
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractMentions] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractMentions] Skipping temperature setting for model gpt-5\n")
-	}
+	setMaxCompletionTokens(&params, s.cfg.MentionsMaxTokens)
 
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, correlationTag(ctx, "ExtractMentions"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract mentions: %w", err)
 	}
 
-	fmt.Printf("[ExtractMentions] ✅ AI call completed successfully")
-	fmt.Printf("[ExtractMentions]   - Input tokens: %d", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[ExtractMentions]   - Output tokens: %d", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully", correlationTag(ctx, "ExtractMentions"))
+	fmt.Printf("[%s]   - Input tokens: %d", correlationTag(ctx, "ExtractMentions"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d", correlationTag(ctx, "ExtractMentions"), chatResponse.Usage.CompletionTokens)
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
 		return nil, fmt.Errorf("no response choices returned from OpenAI")
 	}
 
+	if err := checkContentFilter("ExtractMentions", chatResponse); err != nil {
+		return nil, err
+	}
+	if err := checkOutputLimit("ExtractMentions", chatResponse, s.cfg.MentionsMaxTokens); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	// Parse the structured response
@@ -130,7 +143,7 @@ func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRun
 	// Capture token and cost data from the AI call
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	var mentions []*models.QuestionRunMention
 	now := time.Now()
@@ -139,7 +152,7 @@ func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRun
 	if extractedData.TargetCompany != nil {
 		rawMentionText := extractedData.TargetCompany.MentionedText
 		trimmedLower := strings.ToLower(strings.TrimSpace(rawMentionText))
-		if trimmedLower != "" && trimmedLower != "null" {
+		if trimmedLower != "" && trimmedLower != "null" && !isNoiseMention(s.cfg, rawMentionText) {
 			sentiment := s.normalizeSentiment(extractedData.TargetCompany.TextSentiment)
 			mentions = append(mentions, &models.QuestionRunMention{
 				QuestionRunMentionID: uuid.New(),
@@ -156,12 +169,16 @@ func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRun
 				UpdatedAt:            now,
 			})
 		} else {
-			fmt.Printf("[ExtractMentions] Skipping target_company mention due to empty/invalid mentioned_text: '%s'", rawMentionText)
+			fmt.Printf("[%s] Skipping target_company mention due to empty/invalid/too-short mentioned_text: '%s'", correlationTag(ctx, "ExtractMentions"), rawMentionText)
 		}
 	}
 
 	// Process competitors
 	for _, comp := range extractedData.Competitors {
+		if isNoiseMention(s.cfg, comp.MentionedText) {
+			fmt.Printf("[%s] Skipping %s mention with too-short mentioned_text: '%s'\n", correlationTag(ctx, "ExtractMentions"), comp.Name, comp.MentionedText)
+			continue
+		}
 		sentiment := s.normalizeSentiment(comp.TextSentiment)
 		mentions = append(mentions, &models.QuestionRunMention{
 			QuestionRunMentionID: uuid.New(),
@@ -179,13 +196,13 @@ func (s *dataExtractionService) ExtractMentions(ctx context.Context, questionRun
 		})
 	}
 
-	fmt.Printf("[ExtractMentions] ✅ Successfully extracted %d mentions", len(mentions))
+	fmt.Printf("[%s] ✅ Successfully extracted %d mentions", correlationTag(ctx, "ExtractMentions"), len(mentions))
 	return mentions, nil
 }
 
 // ExtractClaims parses AI response and extracts factual claims
 func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunClaim, error) {
-	fmt.Printf("[ExtractClaims] 🔍 Processing claims for question run %s", questionRunID)
+	fmt.Printf("[%s] 🔍 Processing claims for question run %s", correlationTag(ctx, "ExtractClaims"), questionRunID)
 
 	prompt := s.buildClaimsExtractionPrompt(response, targetCompany, orgWebsites)
 
@@ -193,12 +210,12 @@ func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
 		// Use Azure deployment name
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		fmt.Printf("[ExtractClaims] 🎯 Using Azure OpenAI deployment: %s", s.cfg.AzureOpenAIDeploymentName)
+		model = openai.ChatModel(azureFullDeployment(s.cfg))
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI deployment: %s", correlationTag(ctx, "ExtractClaims"), model)
 	} else {
 		// Use standard OpenAI model
 		model = openai.ChatModelGPT4_1
-		fmt.Printf("[ExtractClaims] 🎯 Using Standard OpenAI model: %s", model)
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s", correlationTag(ctx, "ExtractClaims"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
@@ -208,7 +225,7 @@ func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[ExtractClaims] 🚀 Making AI call for claims extraction...")
+	fmt.Printf("[%s] 🚀 Making AI call for claims extraction...", correlationTag(ctx, "ExtractClaims"))
 
 	// Create the extraction request
 	params := openai.ChatCompletionNewParams{
@@ -222,30 +239,30 @@ func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractClaims] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractClaims] Skipping temperature setting for model gpt-5\n")
-	}
+	setMaxCompletionTokens(&params, s.cfg.ClaimsMaxTokens)
 
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, correlationTag(ctx, "ExtractClaims"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract claims: %w", err)
 	}
 
-	fmt.Printf("[ExtractClaims] ✅ AI call completed successfully")
-	fmt.Printf("[ExtractClaims]   - Input tokens: %d", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[ExtractClaims]   - Output tokens: %d", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully", correlationTag(ctx, "ExtractClaims"))
+	fmt.Printf("[%s]   - Input tokens: %d", correlationTag(ctx, "ExtractClaims"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d", correlationTag(ctx, "ExtractClaims"), chatResponse.Usage.CompletionTokens)
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
 		return nil, fmt.Errorf("no response choices returned from OpenAI")
 	}
 
+	if err := checkContentFilter("ExtractClaims", chatResponse); err != nil {
+		return nil, err
+	}
+	if err := checkOutputLimit("ExtractClaims", chatResponse, s.cfg.ClaimsMaxTokens); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	var extractedData ClaimsExtractionResponse
@@ -256,7 +273,7 @@ func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID
 	// Capture token and cost data from the AI call
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	var claims []*models.QuestionRunClaim
 	now := time.Now()
@@ -280,27 +297,48 @@ func (s *dataExtractionService) ExtractClaims(ctx context.Context, questionRunID
 		})
 	}
 
-	fmt.Printf("[ExtractClaims] ✅ Successfully extracted %d claims", len(claims))
+	fmt.Printf("[%s] ✅ Successfully extracted %d claims", correlationTag(ctx, "ExtractClaims"), len(claims))
 	return claims, nil
 }
 
 // ExtractCitations parses AI response and finds citations for claims
 func (s *dataExtractionService) ExtractCitations(ctx context.Context, claims []*models.QuestionRunClaim, response string, orgWebsites []string) ([]*models.QuestionRunCitation, error) {
-	fmt.Printf("[ExtractCitations] Processing citations for %d claims\n", len(claims))
+	fmt.Printf("[%s] Processing citations for %d claims\n", correlationTag(ctx, "ExtractCitations"), len(claims))
+
+	// Each claim is its own LLM call; run up to CitationExtractionConcurrency at
+	// once. Results are collected per claim index and joined in claim order, so
+	// the output (and each claim's CitationOrder) matches the sequential run.
+	perClaim := make([][]*models.QuestionRunCitation, len(claims))
+	workers := min(max(s.cfg.CitationExtractionConcurrency, 1), len(claims))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				claim := claims[i]
+				citations, err := s.extractCitationsForClaim(ctx, claim, response, orgWebsites)
+				if err != nil {
+					fmt.Printf("[%s] Warning: Failed to extract citations for claim %s: %v\n", correlationTag(ctx, "ExtractCitations"), claim.QuestionRunClaimID, err)
+					continue
+				}
+				perClaim[i] = citations
+			}
+		}()
+	}
+	for i := range claims {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
 	var allCitations []*models.QuestionRunCitation
-
-	// Process each claim individually to find its citations
-	for _, claim := range claims {
-		citations, err := s.extractCitationsForClaim(ctx, claim, response, orgWebsites)
-		if err != nil {
-			fmt.Printf("[ExtractCitations] Warning: Failed to extract citations for claim %s: %v\n", claim.QuestionRunClaimID, err)
-			continue
-		}
+	for _, citations := range perClaim {
 		allCitations = append(allCitations, citations...)
 	}
 
-	fmt.Printf("[ExtractCitations] Successfully extracted %d total citations\n", len(allCitations))
+	fmt.Printf("[%s] Successfully extracted %d total citations\n", correlationTag(ctx, "ExtractCitations"), len(allCitations))
 	return allCitations, nil
 }
 
@@ -328,6 +366,11 @@ func (s *dataExtractionService) CalculateMetrics(ctx context.Context, mentions [
 			shareOfVoice := targetTextLen / responseLen
 			metrics.ShareOfVoice = &shareOfVoice
 		}
+		if s.cfg.SOVNormalization {
+			if normalized, ok := normalizedShareOfVoice(len(targetMention.MentionText), response); ok {
+				metrics.NormalizedShareOfVoice = &normalized
+			}
+		}
 
 		// Target rank from mention (ensure it's not null)
 		if targetMention.MentionRank != nil {
@@ -348,83 +391,22 @@ func (s *dataExtractionService) CalculateMetrics(ctx context.Context, mentions [
 	return metrics, nil
 }
 
-// ExtractNetworkOrgEvaluation extracts network org evaluation data (similar to ExtractOrgEvaluation but for network tables)
-func (s *dataExtractionService) ExtractNetworkOrgEvaluation(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgEvaluationResult, error) {
-	fmt.Printf("[ExtractNetworkOrgEvaluation] 🔍 Processing network org evaluation for question run %s, org %s\n", questionRunID, orgName)
+// ExtractNetworkOrgEvaluation extracts network org evaluation data (similar to ExtractOrgEvaluation but for network tables).
+// confidence selects the prompt: MentionConfirmed tells the model the org is mentioned,
+// MentionUncertain asks it to decide and leaves Mentioned to the extracted text.
+func (s *dataExtractionService) ExtractNetworkOrgEvaluation(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string, confidence MentionConfidence) (*NetworkOrgEvaluationResult, error) {
+	fmt.Printf("[%s] 🔍 Processing network org evaluation for question run %s, org %s (mention %s)\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), questionRunID, orgName, confidence)
 
-	nameVariationsStr := strings.Join(nameVariations, ", ")
-	websitesList := ""
-	if len(orgWebsites) > 0 {
-		websitesList = "\n## ORGANIZATION DOMAINS (SUPPORTING SIGNALS):\n"
-		for _, website := range orgWebsites {
-			websitesList += fmt.Sprintf("- %s\n", website)
-		}
-		websitesList += "\n"
-	}
-
-	prompt := fmt.Sprintf(`You are an expert text extraction specialist. The target organization IS MENTIONED in this text. Your task is to extract ALL text that mentions the organization and determine the overall sentiment.
-
-**TARGET ORGANIZATION:** %s
-**Organization name variations:** %s
-%s
-**QUESTION:** %s
-
-**TASK 1: EXTRACT MENTION TEXT**
-
-Find EVERY occurrence where the target organization is mentioned by name (including variations) and extract the text with perfect formatting preservation.
-
-**EXTRACTION RULES:**
-- **PRESERVE EXACT FORMATTING**: Copy text character-for-character including:
-  - All punctuation marks (periods, commas, colons, semicolons, etc.)
-  - All markdown formatting (**, *, ##, [], (), etc.)
-  - All spacing, line breaks, and indentation
-  - All special characters and symbols
-- **INCLUDE CITATIONS**: Always include URLs, links, and citation references that appear with mentions
-- **ALL FORMATS**: Extract from paragraphs, lists, tables, headers, footnotes, structured data
-- **COMPLETE CONTEXT**: Extract the full sentence/paragraph/section that contains the mention
-
-**SPAN DEFINITION:**
-- **Single occurrence** = Complete sentence, bullet point, table row, or logical text unit that mentions the organization
-- **Adjacent context** = If consecutive sentences form one continuous thought about the organization, keep them together
-- **Aggregation** = Use exact delimiter " || " (space-pipe-pipe-space) between separate occurrences
-
-**TASK 2: DETERMINE SENTIMENT**
-
-Analyze the overall sentiment toward the target organization across all mentions:
-- **positive**: Favorable language, praise, recommendations ("excellent", "best", "recommended", "leading")
-- **negative**: Critical language, problems, warnings ("poor", "issues", "avoid", "problematic")
-- **neutral**: Factual, descriptive, balanced content without clear bias
-
-**TASK 3: CITATION CHECK**
-
-Determine if the response contains URLs that relate to this specific organization:
-- Set citation=true if URLs from organization's domains are present OR if external URLs mention the organization
-- Set citation=false if no relevant URLs found
-
-**TASK 4: MENTION RANK**
-
-Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=not mentioned)
-
-**RESPONSE TO ANALYZE:**
-`+"`"+`
-%s
-`+"`"+`
-
-**OUTPUT REQUIREMENTS:**
-- mentioned: true (we already confirmed it's mentioned)
-- mention_text: ALL extracted text with perfect formatting, separated by " || "
-- sentiment: exactly one of "positive", "negative", or "neutral" (lowercase)
-- citation: true or false
-- mention_rank: integer (1 for most prominent)`, "`"+orgName+"`", nameVariationsStr, websitesList, questionText, responseText)
+	systemPrompt, prompt := buildNetworkOrgEvaluationPrompt(confidence, orgName, nameVariations, orgWebsites, questionText, responseText)
 
 	// Use Azure or standard OpenAI with gpt-4.1
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		fmt.Printf("[ExtractNetworkOrgEvaluation] 🎯 Using Azure OpenAI deployment: %s\n", s.cfg.AzureOpenAIDeploymentName)
+		model = openai.ChatModel(azureFullDeployment(s.cfg))
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI deployment: %s\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), model)
 	} else {
 		model = openai.ChatModelGPT4_1
-		fmt.Printf("[ExtractNetworkOrgEvaluation] 🎯 Using Standard OpenAI model: %s\n", model)
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
@@ -434,12 +416,12 @@ Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=no
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[ExtractNetworkOrgEvaluation] 🚀 Making AI call for network org evaluation...\n")
+	fmt.Printf("[%s] 🚀 Making AI call for network org evaluation...\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"))
 
 	// Create the extraction request with structured output
 	params := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage("You are an expert text extraction specialist. Extract mention text with perfect formatting and determine sentiment. The organization is already confirmed to be mentioned."),
+			openai.SystemMessage(systemPrompt),
 			openai.UserMessage(prompt),
 		},
 		Model: model,
@@ -448,30 +430,25 @@ Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=no
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractNetworkOrgEvaluation] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractNetworkOrgEvaluation] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, correlationTag(ctx, "ExtractNetworkOrgEvaluation"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract network org evaluation: %w", err)
 	}
 
-	fmt.Printf("[ExtractNetworkOrgEvaluation] ✅ AI call completed successfully\n")
-	fmt.Printf("[ExtractNetworkOrgEvaluation]   - Input tokens: %d\n", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[ExtractNetworkOrgEvaluation]   - Output tokens: %d\n", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"))
+	fmt.Printf("[%s]   - Input tokens: %d\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), chatResponse.Usage.CompletionTokens)
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
 		return nil, fmt.Errorf("no response choices returned from OpenAI")
 	}
 
+	if err := checkContentFilter("ExtractNetworkOrgEvaluation", chatResponse); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	// Parse the structured response
@@ -483,15 +460,20 @@ Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=no
 	// Capture token and cost data from the AI call
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	// Create the network org evaluation model
 	now := time.Now()
+	mentioned := extractedData.mentioned(confidence)
+	if mentioned && isNoiseMention(s.cfg, extractedData.MentionText) {
+		fmt.Printf("[%s] Treating too-short mention_text '%s' as not mentioned\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"), extractedData.MentionText)
+		mentioned = false
+	}
 	networkOrgEval := &models.NetworkOrgEval{
 		NetworkOrgEvalID: uuid.New(),
 		QuestionRunID:    questionRunID,
 		OrgID:            orgID,
-		Mentioned:        true, // We already know it's mentioned from pre-filtering
+		Mentioned:        mentioned,
 		Citation:         extractedData.Citation,
 		Sentiment:        stringPtr(extractedData.Sentiment),
 		MentionText:      stringPtr(extractedData.MentionText),
@@ -502,33 +484,39 @@ Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=no
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
+	if !mentioned {
+		// Pre-filter false positive or noise mention: store it like the non-mentioned minimal record, keeping the cost
+		networkOrgEval.Sentiment = nil
+		networkOrgEval.MentionText = nil
+		networkOrgEval.MentionRank = nil
+	}
 
-	fmt.Printf("[ExtractNetworkOrgEvaluation] ✅ Created network org evaluation: mentioned=true, sentiment=%s, citation=%t, mention_rank=%d\n",
-		extractedData.Sentiment, extractedData.Citation, extractedData.MentionRank)
+	fmt.Printf("[%s] ✅ Created network org evaluation: mentioned=%t, sentiment=%s, citation=%t, mention_rank=%d\n", correlationTag(ctx, "ExtractNetworkOrgEvaluation"),
+		mentioned, extractedData.Sentiment, extractedData.Citation, extractedData.MentionRank)
 
 	return &NetworkOrgEvaluationResult{
 		Evaluation:   networkOrgEval,
+		Model:        string(model),
 		InputTokens:  inputTokens,
 		OutputTokens: outputTokens,
 		TotalCost:    totalCost,
 	}, nil
 }
 
-// ExtractNetworkOrgCompetitors extracts competitors for network org processing (separate AI call with gpt-4.1-mini)
+// ExtractNetworkOrgCompetitors extracts competitors for network org processing (separate AI call with
+// cfg.CompetitorExtractionModel, gpt-4.1-mini by default)
 func (s *dataExtractionService) ExtractNetworkOrgCompetitors(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, responseText string) (*NetworkOrgCompetitorResult, error) {
-	fmt.Printf("[ExtractNetworkOrgCompetitors] 🔍 Processing competitors for network org question run %s, org %s\n", questionRunID, orgName)
+	fmt.Printf("[%s] 🔍 Processing competitors for network org question run %s, org %s\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), questionRunID, orgName)
 
-	prompt := fmt.Sprintf("You are an expert in competitive analysis and brand identification. Your task is to identify ALL competitor brands, companies, products, or services mentioned in the response text that are NOT the target organization.\n\n**TARGET ORGANIZATION:** %s\n\n**COMPETITOR IDENTIFICATION RULES:**\n\n1. **What to Include:**\n   - Company names (e.g., \"Microsoft\", \"Google\", \"Apple\")\n   - Product names (e.g., \"ChatGPT\", \"Claude\", \"Gemini\", \"Perplexity\")\n   - Service names (e.g., \"Ahrefs Brand Radar\", \"Surfer SEO AI Tracker\")\n   - Platform names (e.g., \"LinkedIn\", \"Facebook\", \"Twitter\")\n   - Tool names (e.g., \"Profound\", \"Promptmonitor\", \"Writesonic GEO Platform\")\n   - Any branded entity that could be considered competition or alternative\n\n2. **What to Exclude:**\n   - The target organization itself and its variations\n   - Generic terms (e.g., \"AI tools\", \"analytics platforms\", \"search engines\")\n   - Non-competitive entities (e.g., \"users\", \"customers\", \"developers\")\n   - Technical terms or concepts (e.g., \"machine learning\", \"natural language processing\")\n   - Industry terms (e.g., \"credit unions\", \"financial services\")\n\n3. **Extraction Guidelines:**\n   - Extract the most commonly used or official name for each competitor\n   - If a company has multiple products mentioned, list each product separately\n   - Remove duplicates and variations of the same entity\n   - Focus on entities that could be considered alternatives or competitors\n   - Include both direct competitors and indirect competitors mentioned\n\n**EXAMPLES:**\n\nExample 1: \"Leading AI tools include ChatGPT, Claude, Gemini, and Senso.ai for content optimization.\"\n→ Extract: [\"ChatGPT\", \"Claude\", \"Gemini\"] (exclude Senso.ai as it's the target)\n\nExample 2: \"Microsoft's Azure competes with Google Cloud and Amazon Web Services in the enterprise market.\"\n→ Extract: [\"Microsoft\", \"Azure\", \"Google Cloud\", \"Amazon Web Services\"]\n\nExample 3: \"Popular analytics platforms like Google Analytics, Adobe Analytics, and Mixpanel offer similar features.\"\n→ Extract: [\"Google Analytics\", \"Adobe Analytics\", \"Mixpanel\"]\n\n**RESPONSE TO ANALYZE:**\n```\n%s\n```\n\n**INSTRUCTIONS:**\n- Return only the list of competitor names\n- Use the most recognizable/official name for each competitor\n- Remove any duplicates or very similar variations\n- If no competitors are mentioned, return an empty list\n- Do not include the target organization or generic terms", "`"+orgName+"`", responseText)
+	prompt := buildCompetitorExtractionPrompt(s.vertical(orgID), orgName, responseText)
 
-	// ALWAYS use gpt-4.1-mini for competitors (cost-effective)
-	var model openai.ChatModel
+	// Competitors use a small model (cost-effective); configurable via COMPETITOR_EXTRACTION_MODEL,
+	// or the mini deployment on Azure
+	model := miniExtractionModel(s.cfg, s.competitorModel())
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		// Use Azure with mini model
-		model = openai.ChatModel("gpt-4.1-mini")
-		fmt.Printf("[ExtractNetworkOrgCompetitors] 🎯 Using Azure SDK with model: gpt-4.1-mini\n")
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI mini deployment: %s\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), model)
 	} else {
-		model = openai.ChatModel("gpt-4.1-mini")
-		fmt.Printf("[ExtractNetworkOrgCompetitors] 🎯 Using Standard OpenAI model: gpt-4.1-mini\n")
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
@@ -538,7 +526,7 @@ func (s *dataExtractionService) ExtractNetworkOrgCompetitors(ctx context.Context
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[ExtractNetworkOrgCompetitors] 🚀 Making AI call for competitor extraction...\n")
+	fmt.Printf("[%s] 🚀 Making AI call for competitor extraction...\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"))
 
 	// Create the extraction request with structured output
 	params := openai.ChatCompletionNewParams{
@@ -552,35 +540,30 @@ func (s *dataExtractionService) ExtractNetworkOrgCompetitors(ctx context.Context
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractNetworkOrgCompetitors] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractNetworkOrgCompetitors] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, correlationTag(ctx, "ExtractNetworkOrgCompetitors"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract network org competitors: %w", err)
 	}
 
-	fmt.Printf("[ExtractNetworkOrgCompetitors] ✅ AI call completed successfully\n")
-	fmt.Printf("[ExtractNetworkOrgCompetitors]   - Input tokens: %d\n", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[ExtractNetworkOrgCompetitors]   - Output tokens: %d\n", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"))
+	fmt.Printf("[%s]   - Input tokens: %d\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), chatResponse.Usage.CompletionTokens)
 
 	// Calculate cost
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
 		return nil, fmt.Errorf("no response choices returned from OpenAI")
 	}
 
+	if err := checkContentFilter("ExtractNetworkOrgCompetitors", chatResponse); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	// Parse the structured response
@@ -593,16 +576,19 @@ func (s *dataExtractionService) ExtractNetworkOrgCompetitors(ctx context.Context
 	var competitors []*models.NetworkOrgCompetitor
 	now := time.Now()
 
-	for _, competitorName := range extractedData.Competitors {
-		if strings.TrimSpace(competitorName) == "" {
-			continue // Skip empty names
-		}
+	// Names() trims, dedupes and splits a comma-joined single-element list;
+	// regulators, card networks etc. on the exclusion list are dropped
+	names, excluded := s.exclusions.Filter(orgID, extractedData.Names(competitorListOptions(s.cfg)))
+	if len(excluded) > 0 {
+		fmt.Printf("[%s] Excluded %d non-competitor names: %s\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), len(excluded), strings.Join(excluded, ", "))
+	}
+	for _, competitorName := range names {
 
 		competitor := &models.NetworkOrgCompetitor{
 			NetworkOrgCompetitorID: uuid.New(),
 			QuestionRunID:          questionRunID,
 			OrgID:                  orgID,
-			Name:                   strings.TrimSpace(competitorName),
+			Name:                   competitorName,
 			InputTokens:            &inputTokens,
 			OutputTokens:           &outputTokens,
 			TotalCost:              &totalCost,
@@ -613,18 +599,28 @@ func (s *dataExtractionService) ExtractNetworkOrgCompetitors(ctx context.Context
 		competitors = append(competitors, competitor)
 	}
 
-	fmt.Printf("[ExtractNetworkOrgCompetitors] ✅ Extracted %d competitors (cost: $%.6f)\n", len(competitors), totalCost)
+	fmt.Printf("[%s] ✅ Extracted %d competitors, excluded %d (cost: $%.6f)\n", correlationTag(ctx, "ExtractNetworkOrgCompetitors"), len(competitors), len(excluded), totalCost)
 	return &NetworkOrgCompetitorResult{
 		Competitors:  competitors,
+		Excluded:     excluded,
+		Model:        string(model),
 		InputTokens:  inputTokens,
 		OutputTokens: outputTokens,
 		TotalCost:    totalCost,
 	}, nil
 }
 
+// competitorModel returns the configured competitor extraction model.
+func (s *dataExtractionService) competitorModel() string {
+	if model := strings.TrimSpace(s.cfg.CompetitorExtractionModel); model != "" {
+		return model
+	}
+	return "gpt-4.1-mini"
+}
+
 // ExtractNetworkOrgCitations extracts citations using regex (no AI call, reliable URL extraction)
 func (s *dataExtractionService) ExtractNetworkOrgCitations(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, responseText string, orgWebsites []string) (*NetworkOrgCitationResult, error) {
-	fmt.Printf("[ExtractNetworkOrgCitations] 🔍 Processing citations for network org question run %s\n", questionRunID)
+	fmt.Printf("[%s] 🔍 Processing citations for network org question run %s\n", correlationTag(ctx, "ExtractNetworkOrgCitations"), questionRunID)
 
 	// Use xurls relaxed mode to find all URLs in the text (same as org evaluation)
 	matches := xurls.Relaxed().FindAllString(responseText, -1)
@@ -632,6 +628,7 @@ func (s *dataExtractionService) ExtractNetworkOrgCitations(ctx context.Context,
 	var citations []*models.NetworkOrgCitation
 	seenURLs := make(map[string]bool)
 	now := time.Now()
+	orgDomains := normalizeOrgWebsites(orgWebsites)
 
 	for _, match := range matches {
 		// Clean up the match
@@ -653,7 +650,7 @@ func (s *dataExtractionService) ExtractNetworkOrgCitations(ctx context.Context,
 
 		// Determine if this is a primary or secondary citation using proper domain parsing
 		citationType := "secondary" // Default to secondary
-		if isPrimaryDomain(normalizedURL, orgWebsites) {
+		if isPrimaryDomain(normalizedURL, orgDomains) {
 			citationType = "primary"
 		}
 
@@ -681,7 +678,7 @@ func (s *dataExtractionService) ExtractNetworkOrgCitations(ctx context.Context,
 		}
 	}
 
-	fmt.Printf("[ExtractNetworkOrgCitations] ✅ Extracted %d citations (%d primary, %d secondary) - REGEX-BASED\n",
+	fmt.Printf("[%s] ✅ Extracted %d citations (%d primary, %d secondary) - REGEX-BASED\n", correlationTag(ctx, "ExtractNetworkOrgCitations"),
 		len(citations), primaryCount, secondaryCount)
 
 	// Citations use regex (no AI cost)
@@ -698,40 +695,40 @@ func (s *dataExtractionService) ExtractNetworkOrgCitations(ctx context.Context,
 // 1. Generate name variations (once) - or use pre-generated ones if provided
 // 2. Check if organization is mentioned
 // 3. Extract evaluation: ONLY if mentioned (AI with gpt-4.1), otherwise create minimal record
-// 4. Extract competitors: ALWAYS (AI with gpt-4.1-mini) - regardless of mention status
+// 4. Extract competitors: ALWAYS (AI with the competitor extraction model) - regardless of mention status
 // 5. Extract citations: ALWAYS (regex-based) - regardless of mention status
+// Competitors and citations are skipped entirely (status skipped_plan) when the
+// org's plan does not include them.
 func (s *dataExtractionService) ExtractNetworkOrgData(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, questionText string, responseText string, nameVariations []string) (*NetworkOrgExtractionResult, error) {
-	fmt.Printf("[ExtractNetworkOrgData] 🔍 Processing network org data for question run %s, org %s\n", questionRunID, orgName)
-	fmt.Printf("[ExtractNetworkOrgData] 🎯 Using NEW THREE-METHOD APPROACH (like org evaluation pipeline)\n")
+	fmt.Printf("[%s] 🔍 Processing network org data for question run %s, org %s\n", correlationTag(ctx, "ExtractNetworkOrgData"), questionRunID, orgName)
+	fmt.Printf("[%s] 🎯 Using NEW THREE-METHOD APPROACH (like org evaluation pipeline)\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+
+	// An empty response would only yield a misleading "not mentioned" evaluation
+	if isEmptyResponse(s.cfg, responseText) {
+		fmt.Printf("[%s] ⚠️ Empty response for question run %s (question %q), skipping extraction\n", correlationTag(ctx, "ExtractNetworkOrgData"), questionRunID, questionText)
+		return nil, emptyResponseError(responseText)
+	}
 
 	// Step 1: Generate name variations for mention detection (if not provided)
 	if len(nameVariations) == 0 {
-		fmt.Printf("[ExtractNetworkOrgData] Generating name variations for org: %s\n", orgName)
+		fmt.Printf("[%s] Generating name variations for org: %s\n", correlationTag(ctx, "ExtractNetworkOrgData"), orgName)
 		var err error
 		nameVariations, err = s.generateNameVariations(ctx, orgName, orgWebsites)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate name variations: %w", err)
 		}
-		fmt.Printf("[ExtractNetworkOrgData] ✅ Generated %d name variations\n", len(nameVariations))
+		fmt.Printf("[%s] ✅ Generated %d name variations\n", correlationTag(ctx, "ExtractNetworkOrgData"), len(nameVariations))
 	} else {
-		fmt.Printf("[ExtractNetworkOrgData] ✅ Using %d pre-generated name variations\n", len(nameVariations))
+		fmt.Printf("[%s] ✅ Using %d pre-generated name variations\n", correlationTag(ctx, "ExtractNetworkOrgData"), len(nameVariations))
 	}
 
 	// Step 2: Check if organization is mentioned (using name variations)
-	mentioned := false
-	responseTextLower := strings.ToLower(responseText)
-	for _, name := range nameVariations {
-		if strings.Contains(responseTextLower, strings.ToLower(name)) {
-			mentioned = true
-			break
-		}
-	}
-	fmt.Printf("[ExtractNetworkOrgData] Organization mentioned: %t\n", mentioned)
+	mentioned, confidence := detectMention(responseText, nameVariations)
+	fmt.Printf("[%s] Organization mentioned: %t (%s)\n", correlationTag(ctx, "ExtractNetworkOrgData"), mentioned, confidence)
 
-	// Initialize cost tracking
-	totalInputTokens := 0
-	totalOutputTokens := 0
-	totalCost := 0.0
+	// Per-stage cost tracking; the result totals are the sum of the stages
+	var stages NetworkOrgStageBreakdown
+	features := s.features.FeaturesForOrg(ctx, orgID)
 
 	var evaluation *models.NetworkOrgEval
 	var competitors []*models.NetworkOrgCompetitor
@@ -739,75 +736,137 @@ func (s *dataExtractionService) ExtractNetworkOrgData(ctx context.Context, quest
 
 	// Step 3: Extract evaluation ONLY if mentioned (following org evaluation logic)
 	if mentioned {
-		fmt.Printf("[ExtractNetworkOrgData] 📊 Step 1/3: Extracting evaluation (AI call with gpt-4.1)...\n")
-		evalResult, err := s.ExtractNetworkOrgEvaluation(ctx, questionRunID, orgID, orgName, orgWebsites, nameVariations, questionText, responseText)
-		if err != nil {
+		fmt.Printf("[%s] 📊 Step 1/3: Extracting evaluation (AI call with gpt-4.1)...\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+		start := time.Now()
+		evalResult, err := s.ExtractNetworkOrgEvaluation(ctx, questionRunID, orgID, orgName, orgWebsites, nameVariations, questionText, responseText, confidence)
+		switch {
+		case errors.Is(err, ErrContentFiltered):
+			// Retrying would be filtered again: keep the pre-filter's verdict on a minimal evaluation
+			fmt.Printf("[%s] 🚫 Evaluation blocked by content filter (%v) - creating minimal evaluation with mentioned=%t\n", correlationTag(ctx, "ExtractNetworkOrgData"), err, mentioned)
+			evaluation = minimalNetworkOrgEval(questionRunID, orgID, mentioned)
+			stages.Evaluation = ExtractionStageUsage{
+				Status:     StageFiltered,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+		case err != nil:
 			return nil, fmt.Errorf("failed to extract network org evaluation: %w", err)
+		default:
+			evaluation = evalResult.Evaluation
+			stages.Evaluation = ExtractionStageUsage{
+				Status:       StageOK,
+				Model:        evalResult.Model,
+				InputTokens:  evalResult.InputTokens,
+				OutputTokens: evalResult.OutputTokens,
+				Cost:         evalResult.TotalCost,
+				DurationMs:   time.Since(start).Milliseconds(),
+			}
+			fmt.Printf("[%s] ✅ Evaluation extracted (cost: $%.6f)\n", correlationTag(ctx, "ExtractNetworkOrgData"), evalResult.TotalCost)
 		}
-		evaluation = evalResult.Evaluation
-		totalInputTokens += evalResult.InputTokens
-		totalOutputTokens += evalResult.OutputTokens
-		totalCost += evalResult.TotalCost
-		fmt.Printf("[ExtractNetworkOrgData] ✅ Evaluation extracted (cost: $%.6f)\n", evalResult.TotalCost)
 	} else {
 		// Create minimal evaluation for non-mentioned case
-		fmt.Printf("[ExtractNetworkOrgData] ⚪ Organization not mentioned - creating minimal evaluation\n")
-		now := time.Now()
-		evaluation = &models.NetworkOrgEval{
-			NetworkOrgEvalID: uuid.New(),
-			QuestionRunID:    questionRunID,
-			OrgID:            orgID,
-			Mentioned:        false,
-			Citation:         false, // Will be determined by citation extraction below
-			Sentiment:        nil,
-			MentionText:      nil,
-			MentionRank:      nil,
-			InputTokens:      nil,
-			OutputTokens:     nil,
-			TotalCost:        nil,
-			CreatedAt:        now,
-			UpdatedAt:        now,
-		}
-		fmt.Printf("[ExtractNetworkOrgData] ✅ Minimal evaluation created\n")
+		fmt.Printf("[%s] ⚪ Organization not mentioned - creating minimal evaluation\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+		evaluation = minimalNetworkOrgEval(questionRunID, orgID, false)
+		fmt.Printf("[%s] ✅ Minimal evaluation created\n", correlationTag(ctx, "ExtractNetworkOrgData"))
 	}
 
 	// Step 4: ALWAYS extract competitors (regardless of mention status - following org evaluation logic)
-	fmt.Printf("[ExtractNetworkOrgData] 🏢 Step 2/3: Extracting competitors (AI call with gpt-4.1-mini)...\n")
-	competitorResult, err := s.ExtractNetworkOrgCompetitors(ctx, questionRunID, orgID, orgName, responseText)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract network org competitors: %w", err)
+	if features.Competitors {
+		fmt.Printf("[%s] 🏢 Step 2/3: Extracting competitors (AI call with %s)...\n", correlationTag(ctx, "ExtractNetworkOrgData"), s.competitorModel())
+		start := time.Now()
+		competitorResult, err := s.ExtractNetworkOrgCompetitors(ctx, questionRunID, orgID, orgName, responseText)
+		switch {
+		case errors.Is(err, ErrContentFiltered):
+			fmt.Printf("[%s] 🚫 Competitors blocked by content filter (%v) - storing none\n", correlationTag(ctx, "ExtractNetworkOrgData"), err)
+			stages.Competitors = ExtractionStageUsage{
+				Status:     StageFiltered,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to extract network org competitors: %w", err)
+		default:
+			competitors = competitorResult.Competitors
+			stages.Competitors = ExtractionStageUsage{
+				Status:       StageOK,
+				Model:        competitorResult.Model,
+				InputTokens:  competitorResult.InputTokens,
+				OutputTokens: competitorResult.OutputTokens,
+				Cost:         competitorResult.TotalCost,
+				DurationMs:   time.Since(start).Milliseconds(),
+			}
+			fmt.Printf("[%s] ✅ %d competitors extracted, %d excluded (cost: $%.6f)\n", correlationTag(ctx, "ExtractNetworkOrgData"), len(competitors), len(competitorResult.Excluded), competitorResult.TotalCost)
+		}
+	} else {
+		fmt.Printf("[%s] ⏭️ Step 2/3: Competitors disabled by plan - skipping\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+		stages.Competitors = ExtractionStageUsage{Status: StageSkippedPlan}
 	}
-	competitors = competitorResult.Competitors
-	totalInputTokens += competitorResult.InputTokens
-	totalOutputTokens += competitorResult.OutputTokens
-	totalCost += competitorResult.TotalCost
-	fmt.Printf("[ExtractNetworkOrgData] ✅ %d competitors extracted (cost: $%.6f)\n", len(competitors), competitorResult.TotalCost)
 
 	// Step 5: ALWAYS extract citations (regardless of mention status - following org evaluation logic)
-	fmt.Printf("[ExtractNetworkOrgData] 🔗 Step 3/3: Extracting citations (regex-based, no AI cost)...\n")
-	citationResult, err := s.ExtractNetworkOrgCitations(ctx, questionRunID, orgID, responseText, orgWebsites)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract network org citations: %w", err)
+	if features.Citations {
+		fmt.Printf("[%s] 🔗 Step 3/3: Extracting citations (regex-based, no AI cost)...\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+		start := time.Now()
+		citationResult, err := s.ExtractNetworkOrgCitations(ctx, questionRunID, orgID, responseText, orgWebsites)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract network org citations: %w", err)
+		}
+		citations = citationResult.Citations
+		// Citations have no cost (regex-based); record the zeros explicitly
+		stages.Citations = ExtractionStageUsage{
+			Status:       StageOK,
+			InputTokens:  citationResult.InputTokens,
+			OutputTokens: citationResult.OutputTokens,
+			Cost:         citationResult.TotalCost,
+			DurationMs:   time.Since(start).Milliseconds(),
+		}
+		fmt.Printf("[%s] ✅ %d citations extracted (regex, $0.00 cost)\n", correlationTag(ctx, "ExtractNetworkOrgData"), len(citations))
+	} else {
+		fmt.Printf("[%s] ⏭️ Step 3/3: Citations disabled by plan - skipping\n", correlationTag(ctx, "ExtractNetworkOrgData"))
+		stages.Citations = ExtractionStageUsage{Status: StageSkippedPlan}
 	}
-	citations = citationResult.Citations
-	// Citations have no cost (regex-based)
-	fmt.Printf("[ExtractNetworkOrgData] ✅ %d citations extracted (regex, $0.00 cost)\n", len(citations))
 
-	fmt.Printf("[ExtractNetworkOrgData] 🎉 COMPLETE: 1 evaluation, %d competitors, %d citations | Total cost: $%.6f\n",
-		len(competitors), len(citations), totalCost)
+	total := stages.Total()
+	fmt.Printf("[%s] 🎉 COMPLETE: 1 evaluation, %d competitors, %d citations | Total cost: $%.6f\n", correlationTag(ctx, "ExtractNetworkOrgData"),
+		len(competitors), len(citations), total.Cost)
+	fmt.Printf("[%s] stage_usage question_run=%s org=%s %s\n", correlationTag(ctx, "ExtractNetworkOrgData"), questionRunID, orgID, stages)
 
 	return &NetworkOrgExtractionResult{
 		Evaluation:   evaluation,
 		Competitors:  competitors,
 		Citations:    citations,
-		InputTokens:  totalInputTokens,
-		OutputTokens: totalOutputTokens,
-		TotalCost:    totalCost,
+		InputTokens:  total.InputTokens,
+		OutputTokens: total.OutputTokens,
+		TotalCost:    total.Cost,
+		Stages:       stages,
 	}, nil
 }
 
+// minimalNetworkOrgEval is the evaluation stored without an AI call: for orgs
+// the pre-filter did not find, or when the evaluation call was filtered.
+func minimalNetworkOrgEval(questionRunID uuid.UUID, orgID uuid.UUID, mentioned bool) *models.NetworkOrgEval {
+	now := time.Now()
+	return &models.NetworkOrgEval{
+		NetworkOrgEvalID: uuid.New(),
+		QuestionRunID:    questionRunID,
+		OrgID:            orgID,
+		Mentioned:        mentioned,
+		Citation:         false, // Will be determined by citation extraction below
+		Sentiment:        nil,
+		MentionText:      nil,
+		MentionRank:      nil,
+		InputTokens:      nil,
+		OutputTokens:     nil,
+		TotalCost:        nil,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
 // Helper methods
-func (s *dataExtractionService) buildMentionsExtractionPrompt(response, targetCompany string, orgWebsites []string) string {
+// vertical returns the prompt framing of the org's vertical.
+func (s *dataExtractionService) vertical(orgID uuid.UUID) ExtractionVertical {
+	return extractionVerticalByName(s.exclusions.VerticalForOrg(orgID))
+}
+
+func (s *dataExtractionService) buildMentionsExtractionPrompt(vertical ExtractionVertical, response, targetCompany string, orgWebsites []string) string {
 	websitesList := ""
 	if len(orgWebsites) > 0 {
 		websitesList = "## ORGANIZATION DOMAINS (SUPPORTING SIGNALS, NOT PRIMARY):\n"
@@ -840,6 +899,7 @@ func (s *dataExtractionService) buildMentionsExtractionPrompt(response, targetCo
 %s
 
 5) Exclusions: Ignore any companies that appear in these instructions; analyze ONLY the text in the RESPONSE TEXT section.
+   - Generic industry terms (e.g., %s) are not companies; never report them as competitors.
 
 6) De-duplication: If the same sentence appears more than once, include it only once.
 
@@ -860,7 +920,7 @@ func (s *dataExtractionService) buildMentionsExtractionPrompt(response, targetCo
 ## RESPONSE TEXT (analyze ONLY this):
 """
 %s
-"""`, targetCompany, websitesList, response)
+"""`, targetCompany, websitesList, vertical.quotedIndustryTerms(), response)
 }
 
 func (s *dataExtractionService) buildClaimsExtractionPrompt(response, targetCompany string, orgWebsites []string) string {
@@ -1018,7 +1078,7 @@ Remember: Your role is extraction, not editing. The downstream system requires e
 }
 
 func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, claim *models.QuestionRunClaim, response string, orgWebsites []string) ([]*models.QuestionRunCitation, error) {
-	fmt.Printf("[extractCitationsForClaim] 🔍 Processing citations for claim %s", claim.QuestionRunClaimID)
+	fmt.Printf("[%s] 🔍 Processing citations for claim %s", correlationTag(ctx, "extractCitationsForClaim"), claim.QuestionRunClaimID)
 
 	prompt := s.buildCitationsExtractionPrompt(claim.ClaimText, response, orgWebsites)
 
@@ -1026,12 +1086,12 @@ func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, cl
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
 		// Use Azure deployment name
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		fmt.Printf("[extractCitationsForClaim] 🎯 Using Azure OpenAI deployment: %s", s.cfg.AzureOpenAIDeploymentName)
+		model = openai.ChatModel(azureFullDeployment(s.cfg))
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI deployment: %s", correlationTag(ctx, "extractCitationsForClaim"), model)
 	} else {
 		// Use standard OpenAI model
 		model = openai.ChatModelGPT4_1
-		fmt.Printf("[extractCitationsForClaim] 🎯 Using Standard OpenAI model: %s", model)
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s", correlationTag(ctx, "extractCitationsForClaim"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
@@ -1041,7 +1101,7 @@ func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, cl
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[extractCitationsForClaim] 🚀 Making AI call for citations extraction...")
+	fmt.Printf("[%s] 🚀 Making AI call for citations extraction...", correlationTag(ctx, "extractCitationsForClaim"))
 
 	params := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
@@ -1054,29 +1114,29 @@ func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, cl
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[extractCitationsForClaim] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[extractCitationsForClaim] Skipping temperature setting for model gpt-5\n")
-	}
+	setMaxCompletionTokens(&params, s.cfg.CitationsMaxTokens)
 
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, correlationTag(ctx, "extractCitationsForClaim"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract citations: %w", err)
 	}
 
-	fmt.Printf("[extractCitationsForClaim] ✅ AI call completed successfully")
-	fmt.Printf("[extractCitationsForClaim]   - Input tokens: %d", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[extractCitationsForClaim]   - Output tokens: %d", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully", correlationTag(ctx, "extractCitationsForClaim"))
+	fmt.Printf("[%s]   - Input tokens: %d", correlationTag(ctx, "extractCitationsForClaim"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d", correlationTag(ctx, "extractCitationsForClaim"), chatResponse.Usage.CompletionTokens)
 
 	if len(chatResponse.Choices) == 0 {
 		return []*models.QuestionRunCitation{}, nil
 	}
 
+	if err := checkContentFilter("extractCitationsForClaim", chatResponse); err != nil {
+		return nil, err
+	}
+	if err := checkOutputLimit("extractCitationsForClaim", chatResponse, s.cfg.CitationsMaxTokens); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	var extractedData CitationsExtractionResponse
@@ -1087,7 +1147,7 @@ func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, cl
 	// Capture token and cost data from the AI call
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	var citations []*models.QuestionRunCitation
 	now := time.Now()
@@ -1107,7 +1167,28 @@ func (s *dataExtractionService) extractCitationsForClaim(ctx context.Context, cl
 		})
 	}
 
-	fmt.Printf("[extractCitationsForClaim] ✅ Successfully extracted %d citations", len(citations))
+	if len(citations) == 0 && s.cfg.CitationRegexFallback {
+		for i, found := range regexCitationsForClaim(claim.ClaimText, response, orgWebsites) {
+			sourceURL := found.URL
+			citations = append(citations, &models.QuestionRunCitation{
+				QuestionRunCitationID: uuid.New(),
+				QuestionRunClaimID:    claim.QuestionRunClaimID,
+				SourceURL:             &sourceURL,
+				CitationType:          found.Type,
+				CitationOrder:         i + 1,
+				InputTokens:           &inputTokens,
+				OutputTokens:          &outputTokens,
+				TotalCost:             &totalCost,
+				CreatedAt:             now,
+				UpdatedAt:             now,
+			})
+		}
+		if len(citations) > 0 {
+			fmt.Printf("[%s] 🔗 LLM returned none; regex fallback found %d citations\n", correlationTag(ctx, "extractCitationsForClaim"), len(citations))
+		}
+	}
+
+	fmt.Printf("[%s] ✅ Successfully extracted %d citations", correlationTag(ctx, "extractCitationsForClaim"), len(citations))
 	return citations, nil
 }
 
@@ -1357,7 +1438,7 @@ func (s *dataExtractionService) GenerateNameVariations(ctx context.Context, orgN
 
 // generateNameVariations is the internal implementation
 func (s *dataExtractionService) generateNameVariations(ctx context.Context, orgName string, websites []string) ([]string, error) {
-	fmt.Printf("[generateNameVariations] 🔍 Generating name variations for org: %s\n", orgName)
+	fmt.Printf("[%s] 🔍 Generating name variations for org: %s\n", correlationTag(ctx, "generateNameVariations"), orgName)
 
 	websitesFormatted := ""
 	for _, website := range websites {
@@ -1416,14 +1497,14 @@ The brand name is %s
 Associated websites:
 %s`, "`"+orgName+"`", websitesFormatted)
 
-	// Use gpt-4.1-mini for name variations (cost-effective)
+	// Name variations are a small call: the mini deployment on Azure, gpt-5 otherwise
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		model = openai.ChatModel("gpt-5")
-		fmt.Printf("[generateNameVariations] 🎯 Using Azure SDK with model: gpt-4.1-mini\n")
+		model = openai.ChatModel(azureMiniDeployment(s.cfg))
+		fmt.Printf("[%s] 🎯 Using Azure OpenAI mini deployment: %s\n", correlationTag(ctx, "generateNameVariations"), model)
 	} else {
 		model = openai.ChatModel("gpt-5")
-		fmt.Printf("[generateNameVariations] 🎯 Using Standard OpenAI model: gpt-4.1-mini\n")
+		fmt.Printf("[%s] 🎯 Using Standard OpenAI model: %s\n", correlationTag(ctx, "generateNameVariations"), model)
 	}
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
@@ -1433,7 +1514,7 @@ Associated websites:
 		Strict:      openai.Bool(true),
 	}
 
-	fmt.Printf("[generateNameVariations] 🚀 Making AI call for name variations...\n")
+	fmt.Printf("[%s] 🚀 Making AI call for name variations...\n", correlationTag(ctx, "generateNameVariations"))
 
 	// Create the extraction request with structured output
 	params := openai.ChatCompletionNewParams{
@@ -1448,30 +1529,25 @@ Associated websites:
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.3) // Keep low for consistency in extraction when verified
-		fmt.Printf("[generateNameVariations] Setting temperature to 0.3 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[generateNameVariations] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.NameVariationTemperature, correlationTag(ctx, "generateNameVariations"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate name variations: %w", err)
 	}
 
-	fmt.Printf("[generateNameVariations] ✅ AI call completed successfully\n")
-	fmt.Printf("[generateNameVariations]   - Input tokens: %d\n", chatResponse.Usage.PromptTokens)
-	fmt.Printf("[generateNameVariations]   - Output tokens: %d\n", chatResponse.Usage.CompletionTokens)
+	fmt.Printf("[%s] ✅ AI call completed successfully\n", correlationTag(ctx, "generateNameVariations"))
+	fmt.Printf("[%s]   - Input tokens: %d\n", correlationTag(ctx, "generateNameVariations"), chatResponse.Usage.PromptTokens)
+	fmt.Printf("[%s]   - Output tokens: %d\n", correlationTag(ctx, "generateNameVariations"), chatResponse.Usage.CompletionTokens)
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
 		return nil, fmt.Errorf("no response choices returned from OpenAI")
 	}
 
+	if err := checkContentFilter("generateNameVariations", chatResponse); err != nil {
+		return nil, err
+	}
+
 	responseContent := chatResponse.Choices[0].Message.Content
 
 	// Parse the structured response
@@ -1480,8 +1556,10 @@ Associated websites:
 		return nil, fmt.Errorf("failed to parse name variations response: %w", err)
 	}
 
-	fmt.Printf("[generateNameVariations] ✅ Generated %d name variations\n", len(extractedData.Names))
-	return extractedData.Names, nil
+	// Always include the programmatic forms so detection never hinges on the model output
+	names := mergeNameVariations(ProgrammaticNameVariations(orgName, websites), extractedData.Names)
+	fmt.Printf("[%s] ✅ Generated %d name variations\n", correlationTag(ctx, "generateNameVariations"), len(names))
+	return names, nil
 }
 
 // Response types for network org extraction
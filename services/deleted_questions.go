@@ -0,0 +1,40 @@
+// services/deleted_questions.go
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+)
+
+// SkipReasonQuestionDeleted marks runs skipped because their GeoQuestion was
+// deleted in the product while a batch was running. These are expected, so
+// they are counted rather than logged as failures.
+const SkipReasonQuestionDeleted = "question_deleted"
+
+// isQuestionNotFound reports whether a GeoQuestionRepo.GetByID result means
+// the question no longer exists (sql.ErrNoRows, or no row and no error).
+func isQuestionNotFound(question *models.GeoQuestion, err error) bool {
+	if err != nil {
+		return errors.Is(err, sql.ErrNoRows)
+	}
+	return question == nil
+}
+
+// liveNetworkQuestionIDs returns the IDs of the network's current questions,
+// used to leave questions deleted mid-batch out of latest-flag updates.
+func (s *questionRunnerService) liveNetworkQuestionIDs(ctx context.Context, networkID uuid.UUID) (map[uuid.UUID]bool, error) {
+	questions, err := s.repos.GeoQuestionRepo.GetByNetwork(ctx, networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network questions: %w", err)
+	}
+	live := make(map[uuid.UUID]bool, len(questions))
+	for _, q := range questions {
+		live[q.GeoQuestionID] = true
+	}
+	return live, nil
+}
@@ -0,0 +1,55 @@
+// services/empty_response.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Providers occasionally answer 200 with no content. Extracting such a
+// response costs three AI calls and stores a misleading "not mentioned"
+// evaluation, so the pipeline treats it as a failed run instead: org runs are
+// not stored, network org runs get an empty_response stage status and no
+// evaluation.
+
+// ErrEmptyResponse is returned when a response is empty or shorter than
+// MIN_EXTRACTION_RESPONSE_CHARS after trimming.
+var ErrEmptyResponse = errors.New("empty response text")
+
+// isEmptyResponse reports whether responseText is too short to extract from.
+func isEmptyResponse(cfg *config.Config, responseText string) bool {
+	trimmed := strings.TrimSpace(responseText)
+	if trimmed == "" {
+		return true
+	}
+	return cfg != nil && len(trimmed) < cfg.MinExtractionResponseChars
+}
+
+// emptyResponseError wraps ErrEmptyResponse with the response length.
+func emptyResponseError(responseText string) error {
+	return fmt.Errorf("%w (%d chars)", ErrEmptyResponse, len(strings.TrimSpace(responseText)))
+}
+
+// emptyNetworkOrgStages is the stage breakdown stored for a network org run
+// whose response was empty: nothing was extracted.
+func emptyNetworkOrgStages() NetworkOrgStageBreakdown {
+	return NetworkOrgStageBreakdown{
+		Evaluation:  ExtractionStageUsage{Status: StageEmptyResponse},
+		Competitors: ExtractionStageUsage{Status: StageEmptyResponse},
+		Citations:   ExtractionStageUsage{Status: StageEmptyResponse},
+	}
+}
+
+// rejectEmptyNetworkOrgRun logs an empty network run for follow-up, records
+// its empty_response stage status and returns the error callers count as failed.
+func rejectEmptyNetworkOrgRun(ctx context.Context, repos *RepositoryManager, logTag string, questionRunID, orgID uuid.UUID, questionText, responseText string) error {
+	fmt.Printf("[%s] ⚠️ Empty response for question run %s (org %s, question %q), skipping extraction\n",
+		logTag, questionRunID, orgID, questionText)
+	saveNetworkOrgStageUsage(ctx, repos, questionRunID, orgID, &NetworkOrgExtractionResult{Stages: emptyNetworkOrgStages()})
+	return emptyResponseError(responseText)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+func TestIsEmptyResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		minChars int
+		nilCfg   bool
+		text     string
+		want     bool
+	}{
+		{name: "empty", text: "", want: true},
+		{name: "whitespace only", text: " \n\t ", want: true},
+		{name: "any text without a minimum", text: "ok", want: false},
+		{name: "below the minimum", minChars: 10, text: "  too short  ", want: true},
+		{name: "at the minimum", minChars: 10, text: "ten chars!", want: false},
+		{name: "empty without config", nilCfg: true, text: "  ", want: true},
+		{name: "text without config", nilCfg: true, text: "x", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{MinExtractionResponseChars: tt.minChars}
+			if tt.nilCfg {
+				cfg = nil
+			}
+			if got := isEmptyResponse(cfg, tt.text); got != tt.want {
+				t.Errorf("isEmptyResponse(%q) = %t, want %t", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// countingExtractor fails the test if any extraction runs.
+type countingExtractor struct {
+	DataExtractionService
+	calls int
+}
+
+func (e *countingExtractor) ExtractNetworkOrgData(ctx context.Context, questionRunID, orgID uuid.UUID, orgName string, orgWebsites []string, questionText, responseText string, nameVariations []string) (*NetworkOrgExtractionResult, error) {
+	e.calls++
+	return &NetworkOrgExtractionResult{}, nil
+}
+
+// networkOrgRows counts the network_org rows written.
+type networkOrgRows struct {
+	evals, competitors, citations int
+}
+
+type networkOrgEvalRows struct {
+	interfaces.NetworkOrgEvalRepository
+	rows *networkOrgRows
+}
+
+func (r networkOrgEvalRows) Create(ctx context.Context, eval *models.NetworkOrgEval) error {
+	r.rows.evals++
+	return nil
+}
+
+func (r networkOrgEvalRows) DeleteByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) error {
+	return nil
+}
+
+type networkOrgCompetitorRows struct {
+	interfaces.NetworkOrgCompetitorRepository
+	rows *networkOrgRows
+}
+
+func (r networkOrgCompetitorRows) Create(ctx context.Context, c *models.NetworkOrgCompetitor) error {
+	r.rows.competitors++
+	return nil
+}
+
+func (r networkOrgCompetitorRows) DeleteByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) error {
+	return nil
+}
+
+type networkOrgCitationRows struct {
+	interfaces.NetworkOrgCitationRepository
+	rows *networkOrgRows
+}
+
+func (r networkOrgCitationRows) Create(ctx context.Context, c *models.NetworkOrgCitation) error {
+	r.rows.citations++
+	return nil
+}
+
+func (r networkOrgCitationRows) DeleteByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) error {
+	return nil
+}
+
+// stageUsageRows records the saved stage breakdowns.
+type stageUsageRows struct {
+	saved []NetworkOrgStageBreakdown
+}
+
+func (r *stageUsageRows) Save(ctx context.Context, questionRunID, orgID uuid.UUID, stages NetworkOrgStageBreakdown) error {
+	r.saved = append(r.saved, stages)
+	return nil
+}
+
+// TestNetworkOrgEntryPointsRejectEmptyResponses checks that every network org
+// entry point rejects an empty or too short response before extracting: no
+// extraction call, no network_org eval, competitor or citation rows, and an
+// empty_response stage status.
+func TestNetworkOrgEntryPointsRejectEmptyResponses(t *testing.T) {
+	questionRunID, orgID := uuid.New(), uuid.New()
+
+	entryPoints := []struct {
+		name string
+		run  func(s *questionRunnerService, responseText string) error
+	}{
+		{
+			name: "ProcessNetworkOrgQuestionRun",
+			run: func(s *questionRunnerService, responseText string) error {
+				_, err := s.ProcessNetworkOrgQuestionRun(context.Background(), questionRunID, orgID, "Acme", nil, "best bank?", responseText)
+				return err
+			},
+		},
+		{
+			name: "ProcessNetworkOrgQuestionRunWithCleanup",
+			run: func(s *questionRunnerService, responseText string) error {
+				_, err := s.ProcessNetworkOrgQuestionRunWithCleanup(context.Background(), questionRunID, orgID, "Acme", nil, []string{"Acme"}, "best bank?", responseText)
+				return err
+			},
+		},
+	}
+	responses := []struct {
+		name string
+		text string
+	}{
+		{name: "empty", text: ""},
+		{name: "whitespace", text: "   \n"},
+		{name: "below minimum", text: "Sorry."},
+	}
+	for _, entry := range entryPoints {
+		for _, resp := range responses {
+			t.Run(entry.name+"/"+resp.name, func(t *testing.T) {
+				rows := &networkOrgRows{}
+				stages := &stageUsageRows{}
+				extractor := &countingExtractor{}
+				s := &questionRunnerService{
+					cfg:                   &config.Config{MinExtractionResponseChars: 20},
+					dataExtractionService: extractor,
+					repos: &RepositoryManager{
+						NetworkOrgEvalRepo:       networkOrgEvalRows{rows: rows},
+						NetworkOrgCompetitorRepo: networkOrgCompetitorRows{rows: rows},
+						NetworkOrgCitationRepo:   networkOrgCitationRows{rows: rows},
+						NetworkOrgStageUsageRepo: stages,
+					},
+				}
+
+				err := entry.run(s, resp.text)
+				if !errors.Is(err, ErrEmptyResponse) {
+					t.Fatalf("error = %v, want ErrEmptyResponse", err)
+				}
+				if extractor.calls != 0 {
+					t.Errorf("%d extraction calls, want none", extractor.calls)
+				}
+				if *rows != (networkOrgRows{}) {
+					t.Errorf("network_org rows written: %+v, want none", *rows)
+				}
+				if len(stages.saved) != 1 || stages.saved[0] != emptyNetworkOrgStages() {
+					t.Errorf("stage usage saved = %+v, want one empty_response breakdown", stages.saved)
+				}
+			})
+		}
+	}
+
+	t.Run("ExtractNetworkOrgData", func(t *testing.T) {
+		// No OpenAI client: any AI call would panic
+		s := &dataExtractionService{cfg: &config.Config{MinExtractionResponseChars: 20}}
+		result, err := s.ExtractNetworkOrgData(context.Background(), questionRunID, orgID, "Acme", nil, "best bank?", "Sorry.", nil)
+		if !errors.Is(err, ErrEmptyResponse) || result != nil {
+			t.Errorf("ExtractNetworkOrgData() = %v, %v; want no result and ErrEmptyResponse", result, err)
+		}
+	})
+}
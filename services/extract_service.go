@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
@@ -50,7 +49,7 @@ func NewExtractService(cfg *config.Config) ExtractService {
 	return &extractService{
 		cfg:          cfg,
 		openAIClient: &client,
-		costService:  NewCostService(),
+		costService:  NewCostService(cfg),
 	}
 }
 
@@ -70,7 +69,7 @@ func (s *extractService) ExtractCompanyMentions(ctx context.Context, question st
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
 		// Use Azure deployment name
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
+		model = openai.ChatModel(azureFullDeployment(s.cfg))
 	} else {
 		// Use standard OpenAI model
 		model = openai.ChatModel("gpt-4.1")
@@ -95,16 +94,7 @@ func (s *extractService) ExtractCompanyMentions(ctx context.Context, question st
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractCompanyMentions] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractCompanyMentions] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, "ExtractCompanyMentions")
 
 	if err != nil {
 		return &models.ExtractResult{
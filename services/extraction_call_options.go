@@ -0,0 +1,34 @@
+// services/extraction_call_options.go
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/openai/openai-go/option"
+)
+
+// Per-call behavior of an extraction call travels with the call, never on
+// the shared openai.Client: the model, token limit and sampling parameters
+// are request params (see newExtractionCompletion), and an HTTP timeout is
+// carried by the call's context.
+
+type extractionTimeoutKey struct{}
+
+// WithExtractionTimeout returns ctx carrying a per-attempt HTTP timeout for
+// the extraction calls made with it; retried attempts each get the full
+// timeout. A zero or negative d removes an inherited timeout.
+func WithExtractionTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, extractionTimeoutKey{}, d)
+}
+
+// extractionRequestOptions returns the request options ctx asks for.
+func extractionRequestOptions(ctx context.Context) []option.RequestOption {
+	if ctx == nil {
+		return nil
+	}
+	if d, ok := ctx.Value(extractionTimeoutKey{}).(time.Duration); ok && d > 0 {
+		return []option.RequestOption{option.WithRequestTimeout(d)}
+	}
+	return nil
+}
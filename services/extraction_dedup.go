@@ -0,0 +1,227 @@
+// services/extraction_dedup.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// A step retry after a transient error that hit once the insert had gone
+// through stores a run's extraction rows twice: the same texts and ranks,
+// seconds apart. runExtractionStages therefore checks a retried run for stored
+// rows before calling the extractor and reuses them instead, and
+// CollapseDuplicateExtractions removes the duplicates already in the database.
+//
+// Rows of one extraction share their created_at (ExtractMentions and
+// ExtractClaims stamp every row with the same time), which is what tells the
+// sets of a run apart.
+
+// storedStageRows returns the rows an earlier attempt already stored for the
+// run's stage, or nil when there are none, stage tracking is off, or they
+// can't be read (the caller then extracts and inserts as usual).
+func storedStageRows[T any](ctx context.Context, repo QuestionRunStageRepository, questionRunID uuid.UUID, stage, logTag string, load func(QuestionRunStageRepository, context.Context, uuid.UUID) ([]T, error)) []T {
+	if repo == nil {
+		return nil
+	}
+	rows, err := load(repo, ctx, questionRunID)
+	if err != nil {
+		fmt.Printf("[%s] Warning: failed to check for stored %s: %v\n", logTag, stage, err)
+		return nil
+	}
+	if len(rows) > 0 {
+		fmt.Printf("[%s] %d %s already stored for run %s, skipping extraction\n", logTag, len(rows), stage, questionRunID)
+	}
+	return rows
+}
+
+// ExtractionDedupRepository finds runs with duplicated extraction rows and
+// deletes rows by ID.
+type ExtractionDedupRepository interface {
+	// ListRunsWithDuplicateMentions returns the runs created since the given
+	// time that store the same mention (org and text) more than once.
+	ListRunsWithDuplicateMentions(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+	DeleteMentions(ctx context.Context, mentionIDs []uuid.UUID) error
+	// DeleteClaims deletes the claims and their citations.
+	DeleteClaims(ctx context.Context, claimIDs []uuid.UUID) error
+}
+
+type extractionDedupRepo struct {
+	db *database.Client
+}
+
+func NewExtractionDedupRepo(db *database.Client) ExtractionDedupRepository {
+	return &extractionDedupRepo{db: db}
+}
+
+func (r *extractionDedupRepo) ListRunsWithDuplicateMentions(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	var runIDs []uuid.UUID
+	err := r.db.SelectContext(ctx, &runIDs, `
+		SELECT DISTINCT m.question_run_id
+		FROM question_run_mentions m
+		JOIN question_runs qr ON qr.question_run_id = m.question_run_id
+		WHERE qr.created_at >= $1
+		GROUP BY m.question_run_id, m.mention_org, m.mention_text
+		HAVING COUNT(*) > 1`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs with duplicate mentions: %w", err)
+	}
+	return runIDs, nil
+}
+
+func (r *extractionDedupRepo) DeleteMentions(ctx context.Context, mentionIDs []uuid.UUID) error {
+	if len(mentionIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM question_run_mentions WHERE question_run_mention_id = ANY($1::uuid[])`, pq.Array(uuidStrings(mentionIDs)))
+	if err != nil {
+		return fmt.Errorf("failed to delete %d mentions: %w", len(mentionIDs), err)
+	}
+	return nil
+}
+
+func (r *extractionDedupRepo) DeleteClaims(ctx context.Context, claimIDs []uuid.UUID) error {
+	if len(claimIDs) == 0 {
+		return nil
+	}
+	ids := pq.Array(uuidStrings(claimIDs))
+	if _, err := r.db.ExecContext(ctx, `
+		DELETE FROM question_run_citations WHERE question_run_claim_id = ANY($1::uuid[])`, ids); err != nil {
+		return fmt.Errorf("failed to delete citations of %d claims: %w", len(claimIDs), err)
+	}
+	if _, err := r.db.ExecContext(ctx, `
+		DELETE FROM question_run_claims WHERE question_run_claim_id = ANY($1::uuid[])`, ids); err != nil {
+		return fmt.Errorf("failed to delete %d claims: %w", len(claimIDs), err)
+	}
+	return nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+// DuplicateMentionSets returns the IDs of the mentions to delete from one
+// run: every extraction set whose mention_org+mention_text multiset equals
+// that of an older set. The oldest set is kept; sets that differ are never
+// touched.
+func DuplicateMentionSets(mentions []*models.QuestionRunMention) []uuid.UUID {
+	return newerDuplicateSets(mentions,
+		func(m *models.QuestionRunMention) time.Time { return m.CreatedAt },
+		func(m *models.QuestionRunMention) uuid.UUID { return m.QuestionRunMentionID },
+		func(m *models.QuestionRunMention) string { return m.MentionOrg + "\x00" + m.MentionText })
+}
+
+// DuplicateClaimSets is DuplicateMentionSets for claims, compared by
+// claim_order+claim_text.
+func DuplicateClaimSets(claims []*models.QuestionRunClaim) []uuid.UUID {
+	return newerDuplicateSets(claims,
+		func(c *models.QuestionRunClaim) time.Time { return c.CreatedAt },
+		func(c *models.QuestionRunClaim) uuid.UUID { return c.QuestionRunClaimID },
+		func(c *models.QuestionRunClaim) string { return fmt.Sprintf("%d\x00%s", c.ClaimOrder, c.ClaimText) })
+}
+
+// newerDuplicateSets groups rows into sets by created_at and returns the IDs
+// of every set whose multiset of keys was already seen in an older set.
+func newerDuplicateSets[T any](rows []T, createdAt func(T) time.Time, id func(T) uuid.UUID, key func(T) string) []uuid.UUID {
+	type extractionSet struct {
+		createdAt time.Time
+		keys      []string
+		ids       []uuid.UUID
+	}
+	index := make(map[time.Time]int)
+	var sets []*extractionSet
+	for _, row := range rows {
+		t := createdAt(row).UTC()
+		i, ok := index[t]
+		if !ok {
+			i = len(sets)
+			index[t] = i
+			sets = append(sets, &extractionSet{createdAt: t})
+		}
+		sets[i].keys = append(sets[i].keys, key(row))
+		sets[i].ids = append(sets[i].ids, id(row))
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].createdAt.Before(sets[j].createdAt) })
+
+	seen := make(map[string]bool)
+	var duplicates []uuid.UUID
+	for _, set := range sets {
+		sort.Strings(set.keys)
+		signature := strings.Join(set.keys, "\x01")
+		if seen[signature] {
+			duplicates = append(duplicates, set.ids...)
+			continue
+		}
+		seen[signature] = true
+	}
+	return duplicates
+}
+
+// DuplicateExtractionResult is what was (or, in dry-run, would be) removed
+// from one run.
+type DuplicateExtractionResult struct {
+	QuestionRunID uuid.UUID
+	MentionIDs    []uuid.UUID
+	ClaimIDs      []uuid.UUID
+	Err           error
+}
+
+// DuplicateExtractionOptions controls CollapseDuplicateExtractions.
+type DuplicateExtractionOptions struct {
+	// Since limits the scan to runs created at or after it.
+	Since time.Time
+	// Apply deletes the duplicates; without it the cleanup only reports them.
+	Apply bool
+}
+
+// CollapseDuplicateExtractions finds the runs with duplicated mention sets
+// and removes the newer duplicate mention and claim sets (with the claims'
+// citations). Run failures are reported per result; only listing the runs is
+// fatal.
+func CollapseDuplicateExtractions(ctx context.Context, stageRepo QuestionRunStageRepository, dedupRepo ExtractionDedupRepository, opts DuplicateExtractionOptions) ([]DuplicateExtractionResult, error) {
+	runIDs, err := dedupRepo.ListRunsWithDuplicateMentions(ctx, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DuplicateExtractionResult
+	for _, runID := range runIDs {
+		result := DuplicateExtractionResult{QuestionRunID: runID}
+		mentions, err := stageRepo.GetMentions(ctx, runID)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		claims, err := stageRepo.GetClaims(ctx, runID)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		result.MentionIDs = DuplicateMentionSets(mentions)
+		result.ClaimIDs = DuplicateClaimSets(claims)
+
+		if opts.Apply {
+			if err := dedupRepo.DeleteClaims(ctx, result.ClaimIDs); err != nil {
+				result.Err = err
+			} else if err := dedupRepo.DeleteMentions(ctx, result.MentionIDs); err != nil {
+				result.Err = err
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
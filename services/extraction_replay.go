@@ -0,0 +1,369 @@
+// services/extraction_replay.go
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Offline replay of the evaluation extraction against stored responses, for
+// A/B testing prompt changes on real production data. Runs are only read; the
+// extraction results go to a JSON/CSV file and nothing is written to the
+// database. Two files from different code versions are compared with
+// CompareReplayResults.
+
+// Replay modes: the extraction code path run against each stored response.
+const (
+	// ReplayModeNetworkOrg runs the network org pre-filter and, when the org
+	// is mentioned, ExtractNetworkOrgEvaluation.
+	ReplayModeNetworkOrg = "network_org"
+	// ReplayModeMentions runs ExtractMentions and CalculateMetrics.
+	ReplayModeMentions = "mentions"
+)
+
+// ReplayTarget is the org the replayed responses are evaluated for.
+type ReplayTarget struct {
+	OrgID    uuid.UUID
+	OrgName  string
+	Websites []string
+	// NameVariations for the network org pre-filter; generated once when empty.
+	NameVariations []string
+}
+
+// ReplayResult is the extraction outcome of one stored run.
+type ReplayResult struct {
+	QuestionRunID uuid.UUID `json:"question_run_id"`
+	Mode          string    `json:"mode"`
+	Mentioned     bool      `json:"mentioned"`
+	Sentiment     string    `json:"sentiment,omitempty"`
+	MentionRank   *int      `json:"mention_rank,omitempty"`
+	// ShareOfVoice is only computed in mentions mode.
+	ShareOfVoice *float64 `json:"share_of_voice,omitempty"`
+	InputTokens  int      `json:"input_tokens"`
+	OutputTokens int      `json:"output_tokens"`
+	Cost         float64  `json:"cost"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// ExtractionReplayer runs the current extraction code against stored runs
+// using read-only repository calls.
+type ExtractionReplayer struct {
+	repos     *RepositoryManager
+	extractor *dataExtractionService
+}
+
+func NewExtractionReplayer(cfg *config.Config, repos *RepositoryManager) *ExtractionReplayer {
+	return &ExtractionReplayer{
+		repos:     repos,
+		extractor: NewDataExtractionService(cfg).(*dataExtractionService),
+	}
+}
+
+// LoadRunsByID loads the given question runs; IDs that match no run are
+// reported and skipped.
+func (r *ExtractionReplayer) LoadRunsByID(ctx context.Context, runIDs []uuid.UUID) ([]*models.QuestionRun, error) {
+	runs, err := r.repos.QuestionRunRepo.GetByIDs(ctx, runIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question runs: %w", err)
+	}
+	if len(runs) < len(runIDs) {
+		fmt.Printf("[LoadRunsByID] Warning: %d of %d question runs not found\n", len(runIDs)-len(runs), len(runIDs))
+	}
+	return runs, nil
+}
+
+// LoadNetworkRuns loads the runs of a network's questions created in [from, to).
+func (r *ExtractionReplayer) LoadNetworkRuns(ctx context.Context, networkID uuid.UUID, from, to time.Time) ([]*models.QuestionRun, error) {
+	questions, err := r.repos.GeoQuestionRepo.GetByNetwork(ctx, networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network questions: %w", err)
+	}
+	var runs []*models.QuestionRun
+	for _, question := range questions {
+		questionRuns, err := r.repos.QuestionRunRepo.GetByQuestion(ctx, question.GeoQuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get runs for question %s: %w", question.GeoQuestionID, err)
+		}
+		for _, run := range questionRuns {
+			if !run.CreatedAt.Before(from) && run.CreatedAt.Before(to) {
+				runs = append(runs, run)
+			}
+		}
+	}
+	return runs, nil
+}
+
+// Replay runs mode's extraction against each run's stored response. A failed
+// extraction is recorded in the result's Error and does not stop the replay.
+func (r *ExtractionReplayer) Replay(ctx context.Context, mode string, target ReplayTarget, runs []*models.QuestionRun) ([]*ReplayResult, error) {
+	if mode != ReplayModeNetworkOrg && mode != ReplayModeMentions {
+		return nil, fmt.Errorf("unknown replay mode %q (want %s or %s)", mode, ReplayModeNetworkOrg, ReplayModeMentions)
+	}
+	if mode == ReplayModeNetworkOrg && len(target.NameVariations) == 0 {
+		variations, err := r.extractor.GenerateNameVariations(ctx, target.OrgName, target.Websites)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate name variations: %w", err)
+		}
+		target.NameVariations = variations
+	}
+
+	questionTexts := make(map[uuid.UUID]string)
+	results := make([]*ReplayResult, 0, len(runs))
+	for i, run := range runs {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		result := &ReplayResult{QuestionRunID: run.QuestionRunID, Mode: mode}
+		responseText := ""
+		if run.ResponseText != nil {
+			responseText = *run.ResponseText
+		}
+
+		var err error
+		if mode == ReplayModeMentions {
+			err = r.replayMentions(ctx, result, target, run.QuestionRunID, responseText)
+		} else {
+			questionText, ok := questionTexts[run.GeoQuestionID]
+			if !ok {
+				question, qErr := r.repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID)
+				if qErr == nil && question != nil {
+					questionText = question.QuestionText
+				}
+				questionTexts[run.GeoQuestionID] = questionText
+			}
+			err = r.replayNetworkOrg(ctx, result, target, run.QuestionRunID, questionText, responseText)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		fmt.Printf("[Replay] (%d/%d) run=%s mentioned=%t cost=$%.6f\n", i+1, len(runs), run.QuestionRunID, result.Mentioned, result.Cost)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// EvaluateNetworkOrg runs the network org pre-filter and, when the org is
+// mentioned, ExtractNetworkOrgEvaluation against one stored response. It
+// returns nil when the pre-filter finds no mention. target.NameVariations
+// must be set. Nothing is stored.
+func (r *ExtractionReplayer) EvaluateNetworkOrg(ctx context.Context, target ReplayTarget, questionRunID uuid.UUID, questionText, responseText string) (*NetworkOrgEvaluationResult, error) {
+	mentioned, confidence := detectMention(responseText, target.NameVariations)
+	if !mentioned {
+		return nil, nil
+	}
+	return r.extractor.ExtractNetworkOrgEvaluation(ctx, questionRunID, target.OrgID, target.OrgName, target.Websites, target.NameVariations, questionText, responseText, confidence)
+}
+
+func (r *ExtractionReplayer) replayNetworkOrg(ctx context.Context, result *ReplayResult, target ReplayTarget, questionRunID uuid.UUID, questionText, responseText string) error {
+	evalResult, err := r.EvaluateNetworkOrg(ctx, target, questionRunID, questionText, responseText)
+	if err != nil || evalResult == nil {
+		return err
+	}
+	eval := evalResult.Evaluation
+	result.Mentioned = eval.Mentioned
+	if eval.Sentiment != nil {
+		result.Sentiment = *eval.Sentiment
+	}
+	result.MentionRank = eval.MentionRank
+	result.InputTokens = evalResult.InputTokens
+	result.OutputTokens = evalResult.OutputTokens
+	result.Cost = evalResult.TotalCost
+	return nil
+}
+
+func (r *ExtractionReplayer) replayMentions(ctx context.Context, result *ReplayResult, target ReplayTarget, questionRunID uuid.UUID, responseText string) error {
+	mentions, err := r.extractor.ExtractMentions(ctx, questionRunID, target.OrgID, responseText, target.OrgName, target.Websites)
+	if err != nil {
+		return err
+	}
+	// Every mention of a call carries that call's token counts and cost
+	if len(mentions) > 0 {
+		if mentions[0].InputTokens != nil {
+			result.InputTokens = *mentions[0].InputTokens
+		}
+		if mentions[0].OutputTokens != nil {
+			result.OutputTokens = *mentions[0].OutputTokens
+		}
+		if mentions[0].TotalCost != nil {
+			result.Cost = *mentions[0].TotalCost
+		}
+	}
+	metrics, err := r.extractor.CalculateMetrics(ctx, mentions, responseText, target.OrgName)
+	if err != nil {
+		return err
+	}
+	result.Mentioned = metrics.TargetMentioned
+	result.ShareOfVoice = metrics.ShareOfVoice
+	result.MentionRank = metrics.TargetRank
+	for _, mention := range mentions {
+		if mention.TargetOrg && mention.MentionSentiment != nil {
+			result.Sentiment = *mention.MentionSentiment
+			break
+		}
+	}
+	return nil
+}
+
+var replayCSVHeader = []string{"question_run_id", "mode", "mentioned", "sentiment", "mention_rank", "share_of_voice", "input_tokens", "output_tokens", "cost", "error"}
+
+// WriteReplayResults writes the results as CSV when path ends in ".csv", JSON otherwise.
+func WriteReplayResults(path string, results []*ReplayResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create replay output: %w", err)
+	}
+	defer f.Close()
+
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return fmt.Errorf("failed to write replay output: %w", err)
+		}
+		return nil
+	}
+
+	w := csv.NewWriter(f)
+	_ = w.Write(replayCSVHeader)
+	for _, r := range results {
+		rank, sov := "", ""
+		if r.MentionRank != nil {
+			rank = strconv.Itoa(*r.MentionRank)
+		}
+		if r.ShareOfVoice != nil {
+			sov = strconv.FormatFloat(*r.ShareOfVoice, 'f', -1, 64)
+		}
+		_ = w.Write([]string{
+			r.QuestionRunID.String(), r.Mode, strconv.FormatBool(r.Mentioned), r.Sentiment, rank, sov,
+			strconv.Itoa(r.InputTokens), strconv.Itoa(r.OutputTokens), strconv.FormatFloat(r.Cost, 'f', -1, 64), r.Error,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// ReadReplayResults reads a file written by WriteReplayResults.
+func ReadReplayResults(path string) ([]*ReplayResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*ReplayResult
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("%s: invalid replay JSON: %w", path, err)
+		}
+		return results, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid replay CSV: %w", path, err)
+	}
+	for i, rec := range records {
+		if i == 0 || len(rec) != len(replayCSVHeader) {
+			continue
+		}
+		runID, err := uuid.Parse(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid question_run_id: %w", path, i+1, err)
+		}
+		r := &ReplayResult{QuestionRunID: runID, Mode: rec[1], Sentiment: rec[3], Error: rec[9]}
+		r.Mentioned, _ = strconv.ParseBool(rec[2])
+		if rank, err := strconv.Atoi(rec[4]); err == nil {
+			r.MentionRank = &rank
+		}
+		if sov, err := strconv.ParseFloat(rec[5], 64); err == nil {
+			r.ShareOfVoice = &sov
+		}
+		r.InputTokens, _ = strconv.Atoi(rec[6])
+		r.OutputTokens, _ = strconv.Atoi(rec[7])
+		r.Cost, _ = strconv.ParseFloat(rec[8], 64)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// ReplayDiff lists the fields of one run that differ between two replays.
+type ReplayDiff struct {
+	QuestionRunID uuid.UUID
+	// Changes are "field: before -> after" descriptions; "only in before" or
+	// "only in after" for runs missing from one side.
+	Changes []string
+}
+
+// replaySOVTolerance ignores share-of-voice differences from rounding.
+const replaySOVTolerance = 0.0005
+
+// CompareReplayResults returns the per-run differences in mentioned,
+// sentiment and share of voice between two replays, sorted by run ID.
+func CompareReplayResults(before, after []*ReplayResult) []ReplayDiff {
+	beforeByRun := make(map[uuid.UUID]*ReplayResult, len(before))
+	for _, r := range before {
+		beforeByRun[r.QuestionRunID] = r
+	}
+	afterByRun := make(map[uuid.UUID]*ReplayResult, len(after))
+	for _, r := range after {
+		afterByRun[r.QuestionRunID] = r
+	}
+
+	var diffs []ReplayDiff
+	for runID, b := range beforeByRun {
+		a, ok := afterByRun[runID]
+		if !ok {
+			diffs = append(diffs, ReplayDiff{QuestionRunID: runID, Changes: []string{"only in before"}})
+			continue
+		}
+		var changes []string
+		if a.Mentioned != b.Mentioned {
+			changes = append(changes, fmt.Sprintf("mentioned: %t -> %t", b.Mentioned, a.Mentioned))
+		}
+		if a.Sentiment != b.Sentiment {
+			changes = append(changes, fmt.Sprintf("sentiment: %q -> %q", b.Sentiment, a.Sentiment))
+		}
+		if sovChanged(b.ShareOfVoice, a.ShareOfVoice) {
+			changes = append(changes, fmt.Sprintf("share_of_voice: %s -> %s", formatSOV(b.ShareOfVoice), formatSOV(a.ShareOfVoice)))
+		}
+		if len(changes) > 0 {
+			diffs = append(diffs, ReplayDiff{QuestionRunID: runID, Changes: changes})
+		}
+	}
+	for runID := range afterByRun {
+		if _, ok := beforeByRun[runID]; !ok {
+			diffs = append(diffs, ReplayDiff{QuestionRunID: runID, Changes: []string{"only in after"}})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].QuestionRunID.String() < diffs[j].QuestionRunID.String()
+	})
+	return diffs
+}
+
+func sovChanged(before, after *float64) bool {
+	if before == nil || after == nil {
+		return (before == nil) != (after == nil)
+	}
+	return math.Abs(*before-*after) > replaySOVTolerance
+}
+
+func formatSOV(sov *float64) string {
+	if sov == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*sov, 'f', 4, 64)
+}
@@ -0,0 +1,37 @@
+// services/extraction_token_caps.go
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openai/openai-go"
+)
+
+// Extraction calls over huge responses can generate long, costly outputs, so
+// mentions, claims and citations extraction cap max_completion_tokens (see
+// config MentionsMaxTokens and friends). A capped completion ends mid-JSON;
+// it is reported as ErrExtractionOutputLimit instead of a parse error, so a
+// retry can raise the cap rather than repeat the same call.
+
+// ErrExtractionOutputLimit is returned when an extraction completion stopped
+// at its max_completion_tokens cap.
+var ErrExtractionOutputLimit = errors.New("extraction output hit max_completion_tokens")
+
+// setMaxCompletionTokens caps the completion at maxTokens; 0 leaves it
+// uncapped. MaxCompletionTokens (not MaxTokens) is what Azure and the gpt-5
+// models accept.
+func setMaxCompletionTokens(params *openai.ChatCompletionNewParams, maxTokens int) {
+	if maxTokens > 0 {
+		params.MaxCompletionTokens = openai.Int(int64(maxTokens))
+	}
+}
+
+// checkOutputLimit returns ErrExtractionOutputLimit (wrapped with the call and
+// cap) when the first choice finished because of the length limit.
+func checkOutputLimit(call string, chatResponse *openai.ChatCompletion, maxTokens int) error {
+	if chatResponse == nil || len(chatResponse.Choices) == 0 || chatResponse.Choices[0].FinishReason != "length" {
+		return nil
+	}
+	return fmt.Errorf("%s: %w (cap %d, output_tokens=%d)", call, ErrExtractionOutputLimit, maxTokens, chatResponse.Usage.CompletionTokens)
+}
@@ -0,0 +1,71 @@
+// services/extraction_vertical.go
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The extraction prompts were written for credit unions and banks, which makes
+// other industries' extractions miss mentions or keep generic category words.
+// An org's vertical (ORG_VERTICALS, or the "orgs" section of the competitor
+// exclusions file, defaulting to COMPETITOR_EXCLUSION_VERTICAL) selects the
+// domain framing below as well as its competitor exclusion list. Unknown
+// verticals use the financial framing.
+
+// DefaultExtractionVertical is the framing used when an org has no vertical.
+const DefaultExtractionVertical = "financial_services"
+
+// ExtractionVertical is the domain framing of the mention and competitor prompts.
+type ExtractionVertical struct {
+	Name string
+	// Analyst completes "You are ..." in the mentions system message.
+	Analyst string
+	// Entities names the organizations being extracted, for schema descriptions.
+	Entities string
+	// IndustryTerms are generic category words that never name a company.
+	IndustryTerms []string
+}
+
+var extractionVerticals = map[string]ExtractionVertical{
+	"financial_services": {
+		Name:          "financial_services",
+		Analyst:       "an expert financial services analyst specializing in credit unions and banks",
+		Entities:      "financial institutions",
+		IndustryTerms: []string{"credit unions", "financial services"},
+	},
+	"healthcare": {
+		Name:          "healthcare",
+		Analyst:       "an expert healthcare market analyst specializing in hospitals and health systems",
+		Entities:      "healthcare organizations",
+		IndustryTerms: []string{"hospitals", "health systems", "clinics", "healthcare providers"},
+	},
+}
+
+// extractionVerticalByName returns the framing of a vertical, falling back to
+// the financial framing for unknown names.
+func extractionVerticalByName(name string) ExtractionVertical {
+	if v, ok := extractionVerticals[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return v
+	}
+	return extractionVerticals[DefaultExtractionVertical]
+}
+
+// quotedIndustryTerms renders IndustryTerms as `"a", "b"` for prompt examples.
+func (v ExtractionVertical) quotedIndustryTerms() string {
+	quoted := make([]string, len(v.IndustryTerms))
+	for i, term := range v.IndustryTerms {
+		quoted[i] = fmt.Sprintf("%q", term)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (v ExtractionVertical) mentionsSystemMessage() string {
+	return fmt.Sprintf("You are %s. Extract company mentions accurately and comprehensively.", v.Analyst)
+}
+
+// buildCompetitorExtractionPrompt is the competitor prompt shared by the org
+// and network org pipelines; only the industry-terms exclusion is per vertical.
+func buildCompetitorExtractionPrompt(vertical ExtractionVertical, orgName, responseText string) string {
+	return fmt.Sprintf("You are an expert in competitive analysis and brand identification. Your task is to identify ALL competitor brands, companies, products, or services mentioned in the response text that are NOT the target organization.\n\n**TARGET ORGANIZATION:** %s\n\n**COMPETITOR IDENTIFICATION RULES:**\n\n1. **What to Include:**\n   - Company names (e.g., \"Microsoft\", \"Google\", \"Apple\")\n   - Product names (e.g., \"ChatGPT\", \"Claude\", \"Gemini\", \"Perplexity\")\n   - Service names (e.g., \"Ahrefs Brand Radar\", \"Surfer SEO AI Tracker\")\n   - Platform names (e.g., \"LinkedIn\", \"Facebook\", \"Twitter\")\n   - Tool names (e.g., \"Profound\", \"Promptmonitor\", \"Writesonic GEO Platform\")\n   - Any branded entity that could be considered competition or alternative\n\n2. **What to Exclude:**\n   - The target organization itself and its variations\n   - Generic terms (e.g., \"AI tools\", \"analytics platforms\", \"search engines\")\n   - Non-competitive entities (e.g., \"users\", \"customers\", \"developers\")\n   - Technical terms or concepts (e.g., \"machine learning\", \"natural language processing\")\n   - Industry terms (e.g., %s)\n\n3. **Extraction Guidelines:**\n   - Extract the most commonly used or official name for each competitor\n   - If a company has multiple products mentioned, list each product separately\n   - Remove duplicates and variations of the same entity\n   - Focus on entities that could be considered alternatives or competitors\n   - Include both direct competitors and indirect competitors mentioned\n\n**EXAMPLES:**\n\nExample 1: \"Leading AI tools include ChatGPT, Claude, Gemini, and Senso.ai for content optimization.\"\n→ Extract: [\"ChatGPT\", \"Claude\", \"Gemini\"] (exclude Senso.ai as it's the target)\n\nExample 2: \"Microsoft's Azure competes with Google Cloud and Amazon Web Services in the enterprise market.\"\n→ Extract: [\"Microsoft\", \"Azure\", \"Google Cloud\", \"Amazon Web Services\"]\n\nExample 3: \"Popular analytics platforms like Google Analytics, Adobe Analytics, and Mixpanel offer similar features.\"\n→ Extract: [\"Google Analytics\", \"Adobe Analytics\", \"Mixpanel\"]\n\n**RESPONSE TO ANALYZE:**\n```\n%s\n```\n\n**INSTRUCTIONS:**\n- Return only the list of competitor names\n- Use the most recognizable/official name for each competitor\n- Remove any duplicates or very similar variations\n- If no competitors are mentioned, return an empty list\n- Do not include the target organization or generic terms", "`"+orgName+"`", vertical.quotedIndustryTerms(), responseText)
+}
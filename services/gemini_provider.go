@@ -22,25 +22,29 @@ type geminiProvider struct {
 	baseURL     string
 	costService CostService
 	httpClient  *http.Client
+	poll        PollConfig
 }
 
-func NewGeminiProvider(cfg *config.Config, model string, costService CostService) AIProvider {
+// NewGeminiProvider submits to datasetID (see brightDataDatasetsFor);
+// the API key and polling settings come from cfg.
+func NewGeminiProvider(cfg *config.Config, datasetID string, model string, costService CostService) AIProvider {
 	fmt.Printf("[NewGeminiProvider] Creating Gemini provider\n")
 	fmt.Printf("[NewGeminiProvider]   - API Key: %s\n", maskAPIKey(cfg.BrightDataAPIKey))
-	fmt.Printf("[NewGeminiProvider]   - Dataset ID: %s\n", cfg.GeminiDatasetID)
+	fmt.Printf("[NewGeminiProvider]   - Dataset ID: %s\n", datasetID)
 
-	if cfg.GeminiDatasetID == "" {
+	if datasetID == "" {
 		fmt.Printf("[NewGeminiProvider] ⚠️ WARNING: GEMINI_DATASET_ID is empty!\n")
 	}
 
 	return &geminiProvider{
 		apiKey:      cfg.BrightDataAPIKey,
-		datasetID:   cfg.GeminiDatasetID,
+		datasetID:   datasetID,
 		baseURL:     "https://api.brightdata.com/datasets/v3",
 		costService: costService,
 		httpClient: &http.Client{
 			Timeout: 20 * time.Minute, // Long timeout for async operations
 		},
+		poll: pollConfigFromConfig(cfg),
 	}
 }
 
@@ -48,6 +52,11 @@ func (p *geminiProvider) GetProviderName() string {
 	return "gemini"
 }
 
+// DatasetID is the BrightData dataset jobs are submitted to.
+func (p *geminiProvider) DatasetID() string {
+	return p.datasetID
+}
+
 // Gemini API request structures
 type GeminiRequest []GeminiInput
 
@@ -88,7 +97,10 @@ type GeminiInputEcho struct {
 	Index   int    `json:"index"`
 }
 
-func (p *geminiProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+func (p *geminiProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	fmt.Printf("[GeminiProvider] 🚀 Making Gemini call for query: %s\n", query)
 
 	// 1. Submit job to Gemini dataset
@@ -108,14 +120,18 @@ func (p *geminiProvider) RunQuestion(ctx context.Context, query string, websearc
 	// 3. Handle response - use answer_text_markdown if available, otherwise create failed response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[GeminiProvider] ⚠️ Gemini returned error: %s\n", result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[GeminiProvider] ⚠️ Gemini returned empty answer_text_markdown\n")
 	} else {
 		responseText = result.AnswerTextMarkdown
@@ -135,6 +151,8 @@ func (p *geminiProvider) RunQuestion(ctx context.Context, query string, websearc
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               []string{},
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}, nil
 }
 
@@ -221,41 +239,25 @@ func (p *geminiProvider) submitJob(ctx context.Context, query string, location *
 	}
 
 	fmt.Printf("[GeminiProvider] ❌ Trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("Gemini API returned status %d: %s", lastStatus, lastBody)
+	return "", newHTTPStatusError("Gemini", lastStatus, lastBody)
 }
 
-func (p *geminiProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*GeminiResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[GeminiProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue // Retry on error
-			}
-
-			fmt.Printf("[GeminiProvider] 📊 Job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[GeminiProvider] ✅ Job completed after %d polls, retrieving results\n", pollCount)
-				return p.getResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("Gemini job failed for snapshot %s", snapshotID)
-			}
-
-			// Continue polling if status is "running" or other non-terminal states
+// WaitForJob polls the job's progress with backoff until it is ready.
+func (p *geminiProvider) WaitForJob(ctx context.Context, jobID string) error {
+	return pollJobStatus(ctx, p.poll, "GeminiProvider", jobID, func(ctx context.Context) (string, error) {
+		status, err := p.checkProgress(ctx, jobID)
+		if err != nil {
+			return "", err
 		}
+		return status.Status, nil
+	})
+}
+
+func (p *geminiProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*GeminiResult, error) {
+	if err := p.WaitForJob(ctx, snapshotID); err != nil {
+		return nil, err
 	}
+	return p.getResults(ctx, snapshotID)
 }
 
 func (p *geminiProvider) checkProgress(ctx context.Context, snapshotID string) (*GeminiProgressResponse, error) {
@@ -333,13 +335,28 @@ func (p *geminiProvider) SupportsBatching() bool {
 	return true
 }
 
+// SupportsWebSearch returns true for Gemini (answers are search-grounded)
+func (p *geminiProvider) SupportsWebSearch() bool {
+	return true
+}
+
 // GetMaxBatchSize returns 20 for Gemini (can batch up to 20 questions)
 func (p *geminiProvider) GetMaxBatchSize() int {
 	return 20
 }
 
-// RunQuestionBatch processes multiple questions in a single Gemini API call
-func (p *geminiProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+// RunQuestionBatch awaits the batch job, like RunQuestionBatchAwait.
+func (p *geminiProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatchAwait(ctx, batch, websearch, location)
+}
+
+// RunQuestionBatchAwait processes multiple questions in a single Gemini API call:
+// submit, WaitForJob, then retrieve the snapshot.
+func (p *geminiProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) (out []*AIResponse, err error) {
+	defer stampBatchLatency(time.Now(), &out)
+	defer sanitizeBatchResponses(&out)
+
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[GeminiProvider] 🚀 Making batched Gemini call for %d queries\n", len(queries))
 
 	if len(queries) > 20 {
@@ -353,18 +370,12 @@ func (p *geminiProvider) RunQuestionBatch(ctx context.Context, queries []string,
 	}
 	queries = localizedQueries
 
-	// 1. Submit batch job to Gemini
-	snapshotID, err := p.submitBatchJob(ctx, queries, location)
-	if err != nil {
-		return nil, fmt.Errorf("failed to submit Gemini batch job: %w", err)
-	}
-
-	fmt.Printf("[GeminiProvider] 📋 Batch job submitted with snapshot ID: %s\n", snapshotID)
-
-	// 2. Poll until completion
-	results, err := p.pollBatchUntilComplete(ctx, snapshotID)
+	// 1-2. Submit the batch job, wait for it and retrieve its results
+	results, err := awaitJob(ctx, p, "Gemini", func(ctx context.Context) (string, error) {
+		return p.submitBatchJob(ctx, queries, location)
+	}, p.getBatchResults)
 	if err != nil {
-		return nil, fmt.Errorf("failed to poll Gemini batch job: %w", err)
+		return nil, err
 	}
 
 	// 3. Match results to queries
@@ -429,7 +440,7 @@ func (p *geminiProvider) RunQuestionBatch(ctx context.Context, queries []string,
 				prompt = result.Input.Prompt // Error results have prompt in input
 			}
 			if prompt != "" {
-				allResults[prompt] = result
+				allResults[normalizeQuestionText(prompt)] = result
 			}
 		}
 		for _, result := range unmatchedResults {
@@ -438,15 +449,16 @@ func (p *geminiProvider) RunQuestionBatch(ctx context.Context, queries []string,
 				prompt = result.Input.Prompt // Error results have prompt in input
 			}
 			if prompt != "" {
-				allResults[prompt] = result
+				allResults[normalizeQuestionText(prompt)] = result
 			}
 		}
 
 		fmt.Printf("[GeminiProvider] 📊 Built result map with %d prompts\n", len(allResults))
 
-		// Match each query to its result by prompt text
+		// Match each query to its result by prompt text (normalized, so echoed
+		// whitespace or quote changes still match)
 		for i, query := range queries {
-			result, exists := allResults[query]
+			result, exists := allResults[normalizeQuestionText(query)]
 			if !exists {
 				return nil, fmt.Errorf("no result found for query: %q (have %d results)", query, len(allResults))
 			}
@@ -458,6 +470,7 @@ func (p *geminiProvider) RunQuestionBatch(ctx context.Context, queries []string,
 	fmt.Printf("[GeminiProvider] ✅ Batch completed: %d questions processed, total cost: $%.4f\n",
 		len(responses), float64(len(responses))*0.0015)
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
 
@@ -466,14 +479,18 @@ func (p *geminiProvider) convertResultToResponse(result *GeminiResult, displayIn
 	// Handle response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[GeminiProvider] ⚠️ Question %d returned error: %s\n", displayIndex, result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[GeminiProvider] ⚠️ Question %d returned empty answer_text_markdown\n", displayIndex)
 	} else {
 		responseText = result.AnswerTextMarkdown
@@ -487,6 +504,8 @@ func (p *geminiProvider) convertResultToResponse(result *GeminiResult, displayIn
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               []string{},
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}
 }
 
@@ -566,40 +585,7 @@ func (p *geminiProvider) submitBatchJob(ctx context.Context, queries []string, l
 	}
 
 	fmt.Printf("[GeminiProvider] ❌ Batch trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("Gemini API returned status %d: %s", lastStatus, lastBody)
-}
-
-// pollBatchUntilComplete polls for batch completion and returns all results
-func (p *geminiProvider) pollBatchUntilComplete(ctx context.Context, snapshotID string) ([]GeminiResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[GeminiProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue
-			}
-
-			fmt.Printf("[GeminiProvider] 📊 Batch job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[GeminiProvider] ✅ Batch job completed after %d polls, retrieving results\n", pollCount)
-				return p.getBatchResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("Gemini batch job failed for snapshot %s", snapshotID)
-			}
-		}
-	}
+	return "", newHTTPStatusError("Gemini", lastStatus, lastBody)
 }
 
 // getBatchResults retrieves all results from a completed batch job
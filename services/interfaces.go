@@ -53,6 +53,46 @@ type RepositoryManager struct {
 	WholesalePricingConfigRepo interfaces.WholesalePricingConfigRepository
 	// Network repository
 	NetworkRepo interfaces.NetworkRepository
+	// Bulk is_latest updates for question runs (optional; nil falls back to per-run updates)
+	QuestionRunLatestRepo QuestionRunLatestFlagRepository
+	// Per-stage extraction status for question runs (optional; nil disables tracking)
+	QuestionRunStageRepo QuestionRunStageRepository
+	// Partial-response markers for question runs (optional; nil skips marking)
+	QuestionRunTruncationRepo QuestionRunTruncationRepository
+	// Per-stage network org extraction accounting (optional; nil skips persisting)
+	NetworkOrgStageUsageRepo NetworkOrgStageUsageRepository
+	// Web-search-downgrade markers for question runs (optional; nil skips marking)
+	QuestionRunWebSearchRepo QuestionRunWebSearchRepository
+	// Detected response languages for question runs (optional; nil skips storing)
+	QuestionRunLanguageRepo QuestionRunLanguageRepository
+	// Weekly load reports (optional; nil skips the weekly aggregation)
+	WeeklyLoadRepo WeeklyLoadRepository
+	// Audit trail of is_latest transitions (optional; nil skips auditing)
+	LatestFlagAuditRepo LatestFlagAuditRepository
+	// Metric-only question run updates (optional; nil falls back to QuestionRunRepo.Update)
+	QuestionRunMetricsRepo QuestionRunMetricsRepository
+	// In-place batch counter increments (optional; nil falls back to read-modify-write)
+	BatchProgressRepo BatchProgressRepository
+	// Question sampling notes for network batches (optional; nil skips recording)
+	BatchSamplingRepo BatchSamplingRepository
+	// Invariant checks across batches, runs and evals (optional; nil skips reconciliation)
+	ReconciliationRepo ReconciliationRepository
+	// Processing errors of network batches (optional; nil keeps them in the summary only)
+	BatchErrorRepo BatchErrorRepository
+	// Raw and normalized share of voice of question runs (optional; nil skips storing)
+	QuestionRunSOVRepo QuestionRunSOVRepository
+	// Question tag snapshots of question runs (optional; nil skips storing)
+	QuestionRunTagRepo QuestionRunTagRepository
+	// Failed network org extractions awaiting replay (optional; nil skips recording)
+	NetworkOrgDeadLetterRepo NetworkOrgDeadLetterRepository
+	// Source runs of question runs that reused a network response (optional; nil skips recording)
+	QuestionRunReuseRepo QuestionRunReuseRepository
+	// Monthly spend per org or network (optional; nil disables cost rollups)
+	CostRollupRepo CostRollupRepository
+	// In-progress network org extractions (optional; nil skips claiming)
+	NetworkOrgClaimRepo NetworkOrgClaimRepository
+	// Owners and stage outputs of single question runs (optional; nil disables re-extraction)
+	QuestionRunReextractRepo QuestionRunReextractRepository
 }
 
 // NewRepositoryManager creates a new repository manager with all repositories
@@ -94,6 +134,46 @@ func NewRepositoryManager(db *database.Client) *RepositoryManager {
 		WholesalePricingConfigRepo: postgresql.NewWholesalePricingConfigRepo(db),
 		// Network respository
 		NetworkRepo: postgresql.NewNetworkRepo(db),
+		// Bulk is_latest updates for question runs
+		QuestionRunLatestRepo: NewQuestionRunLatestFlagRepo(db),
+		// Per-stage extraction status for question runs
+		QuestionRunStageRepo: NewQuestionRunStageRepo(db),
+		// Partial-response markers for question runs
+		QuestionRunTruncationRepo: NewQuestionRunTruncationRepo(db),
+		// Per-stage network org extraction accounting
+		NetworkOrgStageUsageRepo: NewNetworkOrgStageUsageRepo(db),
+		// Web-search-downgrade markers for question runs
+		QuestionRunWebSearchRepo: NewQuestionRunWebSearchRepo(db),
+		// Detected response languages for question runs
+		QuestionRunLanguageRepo: NewQuestionRunLanguageRepo(db),
+		// Weekly load reports
+		WeeklyLoadRepo: NewWeeklyLoadRepo(db),
+		// Audit trail of is_latest transitions
+		LatestFlagAuditRepo: NewLatestFlagAuditRepo(db),
+		// Metric-only question run updates
+		QuestionRunMetricsRepo: NewQuestionRunMetricsRepo(db),
+		// In-place batch counter increments
+		BatchProgressRepo: NewBatchProgressRepo(db),
+		// Question sampling notes for network batches
+		BatchSamplingRepo: NewBatchSamplingRepo(db),
+		// Invariant checks across batches, runs and evals
+		ReconciliationRepo: NewReconciliationRepo(db),
+		// Processing errors of network batches
+		BatchErrorRepo: NewBatchErrorRepo(db),
+		// Raw and normalized share of voice of question runs
+		QuestionRunSOVRepo: NewQuestionRunSOVRepo(db),
+		// Question tag snapshots of question runs
+		QuestionRunTagRepo: NewQuestionRunTagRepo(db),
+		// Failed network org extractions awaiting replay
+		NetworkOrgDeadLetterRepo: NewNetworkOrgDeadLetterRepo(db),
+		// Source runs of question runs that reused a network response
+		QuestionRunReuseRepo: NewQuestionRunReuseRepo(db),
+		// Monthly spend per org or network
+		CostRollupRepo: NewCostRollupRepo(db),
+		// In-progress network org extractions
+		NetworkOrgClaimRepo: NewNetworkOrgClaimRepo(db),
+		// Owners and stage outputs of single question runs
+		QuestionRunReextractRepo: NewQuestionRunReextractRepo(db),
 	}
 }
 
@@ -111,6 +191,8 @@ type RealOrgDetails struct {
 	TargetCompany string // From geo profile
 	Profiles      []*models.GeoProfile
 	Websites      []string // Organization website URLs for citation classification
+	// InvalidLocations are left out of Locations (see ValidateLocations)
+	InvalidLocations []InvalidLocation
 }
 
 // NetworkDetails contains complete network data from database
@@ -119,14 +201,19 @@ type NetworkDetails struct {
 	Models    []*models.GeoModel
 	Locations []*models.OrgLocation // Networks can use same location structure as orgs
 	Questions []interfaces.GeoQuestionWithTags
+	// InvalidLocations are left out of Locations (see ValidateLocations)
+	InvalidLocations []InvalidLocation
 }
 
 // CompetitiveMetrics contains calculated competitive intelligence metrics
 type CompetitiveMetrics struct {
 	TargetMentioned bool
 	ShareOfVoice    *float64
-	TargetRank      *int
-	TargetSentiment *float64
+	// NormalizedShareOfVoice divides by the response without reference
+	// sections and link URLs (see SOVDenominator); only set with SOV_NORMALIZATION.
+	NormalizedShareOfVoice *float64
+	TargetRank             *int
+	TargetSentiment        *float64
 }
 
 // ExtractedData contains all extracted data from AI responses
@@ -140,11 +227,23 @@ type ExtractedData struct {
 type AIProvider interface {
 	RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error)
 	RunQuestionWebSearch(ctx context.Context, query string) (*AIResponse, error)
+	// SupportsWebSearch reports whether websearch=true actually grounds the
+	// answer in a web search (see applyWebSearchPolicy).
+	SupportsWebSearch() bool
 
 	// Batch processing support
 	SupportsBatching() bool
 	GetMaxBatchSize() int
-	RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *workflowModels.Location) ([]*AIResponse, error)
+	// RunQuestionBatch returns one response per query, each tagged with the
+	// query's CorrelationID. Callers match by ID, never by position. It blocks
+	// until every response is available: async providers submit the job,
+	// wait for it (AsyncJobWaiter) and retrieve the results; sync providers
+	// run the queries one by one. Callers never poll themselves.
+	RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error)
+	// RunQuestionBatchAwait runs the batch to completion in one call: async
+	// providers submit the job, wait for it and retrieve the results (see
+	// awaitJob); sync providers just call RunQuestionBatch.
+	RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error)
 }
 
 // AIResponse contains the response from an AI provider
@@ -155,6 +254,29 @@ type AIResponse struct {
 	Cost                    float64
 	Citations               []string
 	ShouldProcessEvaluation bool
+	// SkipReason explains why ShouldProcessEvaluation is false, when the provider knows.
+	SkipReason string
+	// ErrorCode classifies why ShouldProcessEvaluation is false (see
+	// provider_errors.go); ErrorDetail is the provider's native message.
+	ErrorCode   ErrorCode
+	ErrorDetail string
+	// CorrelationID echoes BatchQuery.CorrelationID for batch responses.
+	CorrelationID string
+	// CachedInputTokens is the part of InputTokens served from the provider's
+	// prompt cache (billed at the cached rate); 0 when not reported.
+	CachedInputTokens int
+	// LatencyMs is the wall time of the provider call (the whole job for batched providers).
+	LatencyMs int64
+	// Truncated is set by streaming providers that hit their deadline and
+	// returned only the content generated so far, and by providers whose
+	// answer was cut at the output token limit (TruncationReason "length").
+	Truncated        bool
+	TruncationReason string
+	// WebSearchDowngraded is set when web search was requested but the provider
+	// cannot do it and UNSUPPORTED_WEB_SEARCH_POLICY=downgrade ran it without.
+	WebSearchDowngraded bool
+	// DetectedLanguage is the language code of Response, set once the run is stored.
+	DetectedLanguage string
 }
 
 // NetworkOrgProcessingResult represents the result of processing network org data
@@ -167,6 +289,8 @@ type NetworkOrgProcessingResult struct {
 	Citations    int
 	Status       string
 	Error        error
+	// Stages aggregates the per-stage extraction accounting across the org's runs.
+	Stages NetworkOrgStageBreakdown
 }
 
 // NetworkOrgExtractionResult represents the extracted data for a network org (with cost tracking)
@@ -177,11 +301,14 @@ type NetworkOrgExtractionResult struct {
 	InputTokens  int     // Total input tokens used (from all AI calls)
 	OutputTokens int     // Total output tokens used (from all AI calls)
 	TotalCost    float64 // Total cost of all AI calls
+	// Stages breaks the totals down per extraction stage; the totals above are its sum.
+	Stages NetworkOrgStageBreakdown
 }
 
 // NetworkOrgEvaluationResult represents the result of extracting network org evaluation
 type NetworkOrgEvaluationResult struct {
 	Evaluation   *models.NetworkOrgEval
+	Model        string
 	InputTokens  int
 	OutputTokens int
 	TotalCost    float64
@@ -189,7 +316,10 @@ type NetworkOrgEvaluationResult struct {
 
 // NetworkOrgCompetitorResult represents the result of extracting network org competitors
 type NetworkOrgCompetitorResult struct {
-	Competitors  []*models.NetworkOrgCompetitor
+	Competitors []*models.NetworkOrgCompetitor
+	// Excluded lists extracted names dropped by the competitor exclusion list (not stored)
+	Excluded     []string
+	Model        string
 	InputTokens  int
 	OutputTokens int
 	TotalCost    float64
@@ -214,6 +344,8 @@ type OrgDetailsForNetworkProcessing struct {
 // OrgService interface for organization operations
 type OrgService interface {
 	GetOrgDetails(ctx context.Context, orgID string) (*RealOrgDetails, error)
+	// InvalidateOrgDetails drops any cached GetOrgDetails result for the org.
+	InvalidateOrgDetails(orgID string)
 	GetOrgsByCreationWeekday(ctx context.Context, weekday time.Weekday) ([]*workflowModels.OrgSummary, error)
 	GetOrgIDsByScheduledDOW(ctx context.Context, dow int) ([]uuid.UUID, error)
 	GetOrgsScheduledForDate(ctx context.Context, date time.Time) ([]string, error)
@@ -222,39 +354,59 @@ type OrgService interface {
 
 // Updated QuestionRunnerService interface for database persistence
 type QuestionRunnerService interface {
-	RunQuestionMatrix(ctx context.Context, orgDetails *RealOrgDetails) ([]*models.QuestionRun, error)
-	ProcessSingleQuestion(ctx context.Context, question *models.GeoQuestion, model *models.GeoModel, location *models.OrgLocation, targetCompany string, orgWebsites []string) (*models.QuestionRun, error)
+	RunQuestionMatrix(ctx context.Context, orgDetails *RealOrgDetails) (*QuestionMatrixSummary, error)
+	ProcessSingleQuestion(ctx context.Context, question *models.GeoQuestion, tags []string, model *models.GeoModel, location *models.OrgLocation, targetCompany string, orgWebsites []string) (*QuestionRunResult, error)
+	ListRunsWithIncompleteStages(ctx context.Context, orgID uuid.UUID) ([]*QuestionRunStageStatus, error)
+	RepairQuestionRunStages(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, targetCompany string, orgWebsites []string) (*QuestionRunStageStatus, error)
+	// ReextractQuestionRun redoes the selected extraction stages of one org or network run (see SelectReextractStages).
+	ReextractQuestionRun(ctx context.Context, questionRunID uuid.UUID, stages []string, opts ReextractOptions) (*ReextractPlan, error)
 	RunNetworkQuestionsQuestionOnly(ctx context.Context, networkID string) ([]*models.QuestionRun, error)
 	GetNetworkQuestions(ctx context.Context, networkID string) ([]*models.GeoQuestion, error)
-	ProcessNetworkQuestionOnly(ctx context.Context, question *models.GeoQuestion) (*models.QuestionRun, error)
+	ProcessNetworkQuestionOnly(ctx context.Context, question *models.GeoQuestion, tags []string) (*models.QuestionRun, error)
 	UpdateNetworkLatestFlags(ctx context.Context, networkID string) error
 	RunNetworkOrgProcessing(ctx context.Context, orgID string) ([]*NetworkOrgProcessingResult, error)
 	GetOrgDetailsForNetworkProcessing(ctx context.Context, orgID string) (*OrgDetailsForNetworkProcessing, error)
-	GetLatestNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, error)
-	GetAllNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, error)
+	// GetLatestNetworkQuestionRuns and GetAllNetworkQuestionRuns also return how many
+	// runs were skipped because their question was deleted (SkipReasonQuestionDeleted).
+	GetLatestNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, int, error)
+	GetAllNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, int, error)
 	GetMissingNetworkOrgQuestionRuns(ctx context.Context, networkID string, orgID string) ([]map[string]interface{}, error)
 	ProcessNetworkOrgQuestionRun(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error)
 	ProcessNetworkOrgQuestionRunWithCleanup(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error)
+	// ProcessMissingNetworkOrgQuestionRun is ProcessNetworkOrgQuestionRunWithCleanup for
+	// a run listed as missing: it claims the pair first and skips it when another
+	// processor holds it (ErrNetworkOrgRunClaimed) or an evaluation has appeared
+	// since the listing (ErrNetworkOrgEvalExists).
+	ProcessMissingNetworkOrgQuestionRun(ctx context.Context, claimHolder string, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error)
+	ProcessNetworkOrgCompetitorsOnly(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, responseText string) (*NetworkOrgCompetitorResult, error)
 	GenerateOrgNameVariations(ctx context.Context, orgName string, orgWebsites []string) ([]string, error)
+	// ReplayNetworkOrgDeadLetters retries the org's failed network org extractions (see NetworkOrgDeadLetterRepository).
+	ReplayNetworkOrgDeadLetters(ctx context.Context, orgID string) (*NetworkOrgDeadLetterReplaySummary, error)
 
 	// Network batch processing with multi-model/location support
 	GetNetworkDetails(ctx context.Context, networkID string) (*NetworkDetails, error)
+	// RunNetworkQuestionMatrix runs only the day's sample of questions for
+	// networks configured for sampling (see QuestionSampler).
 	RunNetworkQuestionMatrix(ctx context.Context, networkDetails *NetworkDetails, batchID uuid.UUID) (*NetworkProcessingSummary, error)
 	GetOrCreateNetworkBatch(ctx context.Context, networkID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, bool, error)
 	StartNetworkBatch(ctx context.Context, batchID uuid.UUID) error
 	FailNetworkBatch(ctx context.Context, batchID uuid.UUID) error
 	UpdateNetworkBatchProgress(ctx context.Context, batchID uuid.UUID, completedCount, failedCount int) error
-	CompleteNetworkBatch(ctx context.Context, batchID uuid.UUID, totalProcessed int, totalFailed int) error
+	CompleteNetworkBatch(ctx context.Context, batchID uuid.UUID, totalProcessed int, totalFailed int, totalSkipped int) error
 	CheckQuestionRunExists(ctx context.Context, questionID uuid.UUID, modelName, countryCode string, batchID uuid.UUID) (*models.QuestionRun, error)
+	// RunAdHocQuestion runs one question against the model's provider and
+	// returns the response without storing anything (debug endpoint).
+	RunAdHocQuestion(ctx context.Context, questionText, modelName string, location *workflowModels.Location, webSearch bool) (*AIResponse, error)
 }
 
 // New DataExtractionService interface for parsing AI responses
 type DataExtractionService interface {
-	ExtractMentions(ctx context.Context, questionRunID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error)
+	ExtractMentions(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error)
 	ExtractClaims(ctx context.Context, questionRunID uuid.UUID, response string, targetCompany string, orgWebsites []string) ([]*models.QuestionRunClaim, error)
 	ExtractCitations(ctx context.Context, claims []*models.QuestionRunClaim, response string, orgWebsites []string) ([]*models.QuestionRunCitation, error)
 	CalculateMetrics(ctx context.Context, mentions []*models.QuestionRunMention, response string, targetCompany string) (*CompetitiveMetrics, error)
 	ExtractNetworkOrgData(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, questionText string, responseText string, nameVariations []string) (*NetworkOrgExtractionResult, error)
+	ExtractNetworkOrgCompetitors(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, responseText string) (*NetworkOrgCompetitorResult, error)
 	GenerateNameVariations(ctx context.Context, orgName string, websites []string) ([]string, error)
 }
 
@@ -266,6 +418,11 @@ type AnalyticsService interface {
 
 type CostService interface {
 	CalculateCost(provider, model string, inputTokens, outputTokens int, webSearch bool) float64
+	// CalculateCostDetailed returns the rates and subtotals behind CalculateCost.
+	CalculateCostDetailed(provider, model string, inputTokens, outputTokens int, webSearch bool) CostBreakdown
+	// CalculateCostCached is CalculateCostDetailed with cachedInputTokens (part of
+	// inputTokens) billed at the model's cached-input rate.
+	CalculateCostCached(provider, model string, inputTokens, cachedInputTokens, outputTokens int, webSearch bool) CostBreakdown
 }
 
 type ExtractService interface {
@@ -275,6 +432,7 @@ type ExtractService interface {
 // NEW: OrgEvaluationService interface for the new org evaluation pipeline
 type OrgEvaluationService interface {
 	GenerateNameVariations(ctx context.Context, orgName string, websites []string) ([]string, error)
+	GenerateNameVariationsWithOptions(ctx context.Context, orgName string, websites []string, opts NameVariationOptions) ([]string, error)
 	ExtractOrgEvaluation(ctx context.Context, questionRunID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, responseText string) (*OrgEvaluationResult, error)
 	ExtractCompetitors(ctx context.Context, questionRunID, orgID uuid.UUID, orgName string, responseText string) (*CompetitorExtractionResult, error)
 	ExtractCitations(ctx context.Context, questionRunID, orgID uuid.UUID, responseText string, orgWebsites []string) (*CitationExtractionResult, error)
@@ -286,6 +444,7 @@ type OrgEvaluationService interface {
 	StartBatch(ctx context.Context, batchID uuid.UUID) error
 	CompleteBatch(ctx context.Context, batchID uuid.UUID) error
 	FailBatch(ctx context.Context, batchID uuid.UUID) error
+	PartialBatch(ctx context.Context, batchID uuid.UUID, remainingQuestions int) error
 	UpdateBatchProgress(ctx context.Context, batchID uuid.UUID, completed, failed int) error
 	// Question matrix breakdown methods
 	CalculateQuestionMatrix(ctx context.Context, orgDetails *RealOrgDetails) ([]*QuestionJob, error)
@@ -328,11 +487,38 @@ type OrgEvaluationSummary struct {
 	TotalCompetitors int
 	TotalCost        float64
 	ProcessingErrors []string
+	// DeadlineReached is set when the soft deadline stopped new questions from
+	// starting; RemainingQuestions counts the question runs that were not started.
+	DeadlineReached    bool
+	RemainingQuestions int
+	// Languages counts stored responses by detected language code
+	Languages map[string]int
+	// ReusedRuns counts runs that reused today's network response instead of
+	// calling the provider (see NetworkRunReuseEnabled)
+	ReusedRuns int
+	// InvalidLocations describes each location left out of the matrix because
+	// its country isn't a valid code (see ValidateLocations)
+	InvalidLocations []string
 }
 
 // NetworkProcessingSummary represents the summary of network question processing
 type NetworkProcessingSummary struct {
-	TotalProcessed   int
+	TotalProcessed int
+	// TotalResumed counts runs already stored for the batch by an earlier
+	// attempt; TotalSkipped counts planned runs that were never attempted.
+	TotalResumed int
+	TotalSkipped int
+	// QuestionDeleted counts runs whose question was deleted mid-batch; they are
+	// left out of the latest-flag update.
+	QuestionDeleted int
+	// Languages counts stored responses by detected language code
+	Languages map[string]int
+	// UnconfiguredModels are the network's configured model names no provider
+	// recognizes; their pairs are skipped (see IsSupportedModel).
+	UnconfiguredModels []string
+	// InvalidLocations describes each location left out of the matrix because
+	// its country isn't a valid code (see ValidateLocations)
+	InvalidLocations []string
 	TotalCost        float64
 	ProcessingErrors []string
 }
@@ -348,18 +534,23 @@ type QuestionJob struct {
 	LocationName string    `json:"location_name"`
 	JobIndex     int       `json:"job_index"`
 	TotalJobs    int       `json:"total_jobs"`
+	// Tags are the question's normalized tags, snapshotted onto the run
+	Tags []string `json:"tags,omitempty"`
 }
 
 // QuestionJobResult represents the result of processing a single question job
 type QuestionJobResult struct {
 	QuestionRunID   uuid.UUID `json:"question_run_id"`
 	JobIndex        int       `json:"job_index"`
-	Status          string    `json:"status"` // "completed" or "failed"
+	Status          string    `json:"status"` // "completed", "failed" or "skipped" (claimed by another processor)
 	HasEvaluation   bool      `json:"has_evaluation"`
 	CompetitorCount int       `json:"competitor_count"`
 	CitationCount   int       `json:"citation_count"`
 	TotalCost       float64   `json:"total_cost"`
 	ErrorMessage    string    `json:"error_message,omitempty"`
+	// ReusedFromRunID is the network run whose response was reused instead of
+	// calling the provider (see NetworkRunReuseEnabled)
+	ReusedFromRunID *uuid.UUID `json:"reused_from_run_id,omitempty"`
 }
 
 // OrgQuestionRun represents an existing question run for re-evaluation
@@ -377,7 +568,7 @@ type OrgReevalResult struct {
 	CompetitorCount int       `json:"competitor_count"`
 	CitationCount   int       `json:"citation_count"`
 	TotalCost       float64   `json:"total_cost"`
-	Status          string    `json:"status"` // "completed" or "failed"
+	Status          string    `json:"status"` // "completed", "failed" or "skipped" (claimed by another processor)
 	ErrorMessage    string    `json:"error_message,omitempty"`
 }
 
@@ -0,0 +1,26 @@
+// services/latency.go
+package services
+
+import "time"
+
+// QuestionRun has no latency column, so LatencyMs is surfaced through the
+// run-creation logs rather than persisted.
+
+// stampLatency sets LatencyMs on *resp to the time elapsed since start.
+// Providers defer it at the top of RunQuestion so every return path is covered.
+func stampLatency(start time.Time, resp **AIResponse) {
+	if *resp != nil {
+		(*resp).LatencyMs = time.Since(start).Milliseconds()
+	}
+}
+
+// stampBatchLatency sets the same batch wall time on every response, for
+// providers that submit a whole batch as one job.
+func stampBatchLatency(start time.Time, responses *[]*AIResponse) {
+	elapsed := time.Since(start).Milliseconds()
+	for _, r := range *responses {
+		if r != nil {
+			r.LatencyMs = elapsed
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+// delayed answers every request of next after delay.
+func delayed(delay time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func answer(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+// TestProviderLatency runs each provider against a mock server that takes
+// 100ms to answer and checks the responses carry that latency.
+func TestProviderLatency(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	us := &models.Location{Country: "US"}
+	batch := []BatchQuery{{CorrelationID: "q1", Query: "Best bank?"}, {CorrelationID: "q2", Query: "Best broker?"}}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		run     func(url string, client *http.Client) ([]*AIResponse, error)
+	}{
+		{
+			name:    "openai",
+			handler: answer(`{"status": "completed", "output": [{"type": "message", "content": [{"type": "output_text", "text": "Acme Bank."}]}], "usage": {"input_tokens": 10, "output_tokens": 5}}`),
+			run: func(url string, client *http.Client) ([]*AIResponse, error) {
+				p := &openAIProvider{
+					model:       "gpt-4.1",
+					costService: NewCostService(nil),
+					cfg:         &config.Config{AzureOpenAIEndpoint: url + "/", AzureOpenAIKey: "test-key", AzureOpenAIDeploymentName: "test-deployment"},
+				}
+				resp, err := p.RunQuestion(context.Background(), "Best bank?", true, us)
+				return []*AIResponse{resp}, err
+			},
+		},
+		{
+			name:    "linkup",
+			handler: answer(`{"answer": "Acme Bank [1].", "sources": [{"name": "Acme", "url": "https://acme.example"}]}`),
+			run: func(url string, client *http.Client) ([]*AIResponse, error) {
+				p := &linkupProvider{apiKey: "test-key", baseURL: url, costService: NewCostService(nil), httpClient: client}
+				resp, err := p.RunQuestion(context.Background(), "Best bank?", true, us)
+				return []*AIResponse{resp}, err
+			},
+		},
+		{
+			name:    "brightdata batch",
+			handler: shuffledSnapshotHandler(t, 1, "top level"),
+			run: func(url string, client *http.Client) ([]*AIResponse, error) {
+				p := &brightDataProvider{apiKey: "test-key", datasetID: "test-dataset", baseURL: url, httpClient: client, poll: PollConfig{InitialInterval: time.Millisecond}}
+				return p.RunQuestionBatch(context.Background(), batch, true, us)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(delayed(delay, tt.handler))
+			defer server.Close()
+
+			responses, err := tt.run(server.URL, server.Client())
+			if err != nil {
+				t.Fatalf("provider call error = %v", err)
+			}
+			for i, resp := range responses {
+				if resp.LatencyMs < delay.Milliseconds() {
+					t.Errorf("response %d LatencyMs = %d, want at least %d", i, resp.LatencyMs, delay.Milliseconds())
+				}
+			}
+		})
+	}
+}
+
+func TestStampLatencyNilResponse(t *testing.T) {
+	var resp *AIResponse
+	stampLatency(time.Now(), &resp)
+	responses := []*AIResponse{nil, {}}
+	stampBatchLatency(time.Now().Add(-time.Second), &responses)
+	if responses[1].LatencyMs < 1000 {
+		t.Errorf("LatencyMs = %d, want the batch's 1s", responses[1].LatencyMs)
+	}
+}
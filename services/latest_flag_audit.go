@@ -0,0 +1,192 @@
+// services/latest_flag_audit.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// When the dashboard shows stale data because is_latest points at an older
+// run, the audit trail says which update moved the flag and when. Every
+// is_latest update records, per question whose latest run changed, the run
+// that was latest before and the one that is latest after. Re-running an
+// update that changes nothing records nothing. Rows are append-only:
+//
+//	migrations/000007_question_latest_flag_audit.up.sql
+
+// Sources of latest-flag updates, stored in question_latest_flag_audit.source.
+const (
+	LatestFlagSourceOrgBatch         = "org_batch"
+	LatestFlagSourceOrgQuestions     = "org_questions"
+	LatestFlagSourceNetworkBatch     = "network_batch"
+	LatestFlagSourceNetworkQuestions = "network_questions"
+	LatestFlagSourceRepair           = "repair"
+)
+
+// LatestFlagTransition is one audited change of a question's latest run.
+type LatestFlagTransition struct {
+	AuditID       uuid.UUID  `db:"audit_id" json:"audit_id"`
+	GeoQuestionID uuid.UUID  `db:"geo_question_id" json:"geo_question_id"`
+	PreviousRunID *uuid.UUID `db:"previous_run_id" json:"previous_run_id,omitempty"`
+	NewRunID      uuid.UUID  `db:"new_run_id" json:"new_run_id"`
+	BatchID       *uuid.UUID `db:"batch_id" json:"batch_id,omitempty"`
+	Source        string     `db:"source" json:"source"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// LatestFlagAuditRepository snapshots latest runs and stores the audit trail.
+type LatestFlagAuditRepository interface {
+	// LatestRuns returns the most recent is_latest run of each question,
+	// preferring runs outside batchID (uuid.Nil for none).
+	LatestRuns(ctx context.Context, questionIDs []uuid.UUID, batchID uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	Record(ctx context.Context, transitions []*LatestFlagTransition) error
+	// ListByQuestion returns a question's transitions, oldest first.
+	ListByQuestion(ctx context.Context, questionID uuid.UUID) ([]*LatestFlagTransition, error)
+}
+
+type latestFlagAuditRepo struct {
+	db *database.Client
+}
+
+func NewLatestFlagAuditRepo(db *database.Client) LatestFlagAuditRepository {
+	return &latestFlagAuditRepo{db: db}
+}
+
+func (r *latestFlagAuditRepo) LatestRuns(ctx context.Context, questionIDs []uuid.UUID, batchID uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	latest := make(map[uuid.UUID]uuid.UUID, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return latest, nil
+	}
+	ids := make([]string, len(questionIDs))
+	for i, id := range questionIDs {
+		ids[i] = id.String()
+	}
+
+	var rows []struct {
+		GeoQuestionID uuid.UUID `db:"geo_question_id"`
+		QuestionRunID uuid.UUID `db:"question_run_id"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT DISTINCT ON (geo_question_id) geo_question_id, question_run_id
+		FROM question_runs
+		WHERE geo_question_id = ANY($1::uuid[]) AND is_latest = true
+		ORDER BY geo_question_id, (batch_id IS DISTINCT FROM $2) DESC, created_at DESC`, pq.Array(ids), batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest runs: %w", err)
+	}
+	for _, row := range rows {
+		latest[row.GeoQuestionID] = row.QuestionRunID
+	}
+	return latest, nil
+}
+
+func (r *latestFlagAuditRepo) Record(ctx context.Context, transitions []*LatestFlagTransition) error {
+	for _, t := range transitions {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO question_latest_flag_audit (audit_id, geo_question_id, previous_run_id, new_run_id, batch_id, source, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			t.AuditID, t.GeoQuestionID, t.PreviousRunID, t.NewRunID, t.BatchID, t.Source, t.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record latest flag transition for question %s: %w", t.GeoQuestionID, err)
+		}
+	}
+	return nil
+}
+
+func (r *latestFlagAuditRepo) ListByQuestion(ctx context.Context, questionID uuid.UUID) ([]*LatestFlagTransition, error) {
+	var transitions []*LatestFlagTransition
+	err := r.db.SelectContext(ctx, &transitions, `
+		SELECT audit_id, geo_question_id, previous_run_id, new_run_id, batch_id, source, created_at
+		FROM question_latest_flag_audit
+		WHERE geo_question_id = $1
+		ORDER BY created_at`, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list latest flag audit: %w", err)
+	}
+	return transitions, nil
+}
+
+// latestFlagAudit holds the latest run of each question from before an
+// is_latest update. A nil *latestFlagAudit (repo not configured or the
+// snapshot failed) records nothing, so callers use it unconditionally.
+type latestFlagAudit struct {
+	repos   *RepositoryManager
+	source  string
+	batchID *uuid.UUID
+	before  map[uuid.UUID]uuid.UUID
+}
+
+// beginLatestFlagAudit snapshots the current latest runs of the questions;
+// call it before changing any flags. Failures are logged, not fatal.
+func beginLatestFlagAudit(ctx context.Context, repos *RepositoryManager, source string, batchID *uuid.UUID, questionIDs []uuid.UUID) *latestFlagAudit {
+	if repos.LatestFlagAuditRepo == nil || len(questionIDs) == 0 {
+		return nil
+	}
+	snapshotBatch := uuid.Nil
+	if batchID != nil {
+		snapshotBatch = *batchID
+	}
+	before, err := repos.LatestFlagAuditRepo.LatestRuns(ctx, questionIDs, snapshotBatch)
+	if err != nil {
+		fmt.Printf("[beginLatestFlagAudit] Warning: %v\n", err)
+		return nil
+	}
+	return &latestFlagAudit{repos: repos, source: source, batchID: batchID, before: before}
+}
+
+// record stores one transition per question whose latest run is now a
+// different run than in the snapshot. newLatest maps question to its new
+// latest run, e.g. from latestRunsByQuestion.
+func (a *latestFlagAudit) record(ctx context.Context, newLatest map[uuid.UUID]uuid.UUID) {
+	if a == nil {
+		return
+	}
+	now := time.Now()
+	var transitions []*LatestFlagTransition
+	for questionID, newRunID := range newLatest {
+		t := &LatestFlagTransition{
+			AuditID:       uuid.New(),
+			GeoQuestionID: questionID,
+			NewRunID:      newRunID,
+			BatchID:       a.batchID,
+			Source:        a.source,
+			CreatedAt:     now,
+		}
+		if previous, ok := a.before[questionID]; ok {
+			if previous == newRunID {
+				continue
+			}
+			t.PreviousRunID = &previous
+		}
+		transitions = append(transitions, t)
+	}
+	if len(transitions) == 0 {
+		return
+	}
+	if err := a.repos.LatestFlagAuditRepo.Record(ctx, transitions); err != nil {
+		fmt.Printf("[latestFlagAudit] Warning: %v\n", err)
+		return
+	}
+	fmt.Printf("[latestFlagAudit] Recorded %d latest flag transitions (source=%s)\n", len(transitions), a.source)
+}
+
+// latestRunsByQuestion returns the most recently created run of each question.
+func latestRunsByQuestion(runs []*models.QuestionRun) map[uuid.UUID]uuid.UUID {
+	newest := make(map[uuid.UUID]*models.QuestionRun)
+	for _, run := range runs {
+		if current, ok := newest[run.GeoQuestionID]; !ok || run.CreatedAt.After(current.CreatedAt) {
+			newest[run.GeoQuestionID] = run
+		}
+	}
+	latest := make(map[uuid.UUID]uuid.UUID, len(newest))
+	for questionID, run := range newest {
+		latest[questionID] = run.QuestionRunID
+	}
+	return latest
+}
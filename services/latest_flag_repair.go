@@ -0,0 +1,87 @@
+// services/latest_flag_repair.go
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+)
+
+// LatestFlagRepair is the recomputed is_latest state of one question.
+type LatestFlagRepair struct {
+	QuestionID uuid.UUID
+	// LatestRunID is the question's newest run, uuid.Nil when it has none.
+	LatestRunID uuid.UUID
+	Runs        int
+	// Flagged counts the runs that had is_latest=true before the repair.
+	Flagged int
+	// Changed is set when the flags differ from "only LatestRunID is latest".
+	Changed bool
+	Err     error
+}
+
+// RepairLatestFlags recomputes is_latest for each question the same way
+// UpdateNetworkLatestFlags does: the run with the newest CreatedAt is latest,
+// every other run of the question is not. Only questions whose flags are
+// wrong are written, and only when apply is set; written changes are audited
+// with LatestFlagSourceRepair. Per-question failures are reported on the
+// result and don't stop the repair.
+//
+// Org batches flag every run of the batch (one per model and location), so on
+// an org's questions this keeps a single run per question, like the network
+// recompute does.
+func RepairLatestFlags(ctx context.Context, repos *RepositoryManager, questionIDs []uuid.UUID, apply bool) []*LatestFlagRepair {
+	var audit *latestFlagAudit
+	if apply {
+		audit = beginLatestFlagAudit(ctx, repos, LatestFlagSourceRepair, nil, questionIDs)
+	}
+	updated := make(map[uuid.UUID]uuid.UUID)
+	defer audit.record(ctx, updated)
+
+	results := make([]*LatestFlagRepair, 0, len(questionIDs))
+	for _, questionID := range questionIDs {
+		result := &LatestFlagRepair{QuestionID: questionID}
+		results = append(results, result)
+
+		runs, err := repos.QuestionRunRepo.GetByQuestion(ctx, questionID)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to get runs for question %s: %w", questionID, err)
+			continue
+		}
+		latest := newestRun(runs)
+		if latest == nil {
+			continue
+		}
+		result.LatestRunID = latest.QuestionRunID
+		result.Runs = len(runs)
+		for _, run := range runs {
+			if run.IsLatest {
+				result.Flagged++
+			}
+		}
+		result.Changed = result.Flagged != 1 || !latest.IsLatest
+		if !result.Changed || !apply {
+			continue
+		}
+
+		if err := repos.QuestionRunRepo.UpdateLatestFlags(ctx, questionID, latest.QuestionRunID); err != nil {
+			result.Err = fmt.Errorf("failed to update latest flags for question %s: %w", questionID, err)
+			continue
+		}
+		updated[questionID] = latest.QuestionRunID
+	}
+	return results
+}
+
+// newestRun returns the run with the newest CreatedAt, nil for no runs.
+func newestRun(runs []*models.QuestionRun) *models.QuestionRun {
+	var newest *models.QuestionRun
+	for _, run := range runs {
+		if newest == nil || run.CreatedAt.After(newest.CreatedAt) {
+			newest = run
+		}
+	}
+	return newest
+}
@@ -65,7 +65,10 @@ type LinkupSource struct {
 	URL     string `json:"url"`
 }
 
-func (p *linkupProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+func (p *linkupProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	fmt.Printf("[LinkupProvider] 🚀 Making Linkup call for query: %s\n", query)
 
 	// Build location-aware prompt if location is provided
@@ -111,7 +114,7 @@ func (p *linkupProvider) RunQuestion(ctx context.Context, query string, websearc
 		var errorBody bytes.Buffer
 		errorBody.ReadFrom(resp.Body)
 		fmt.Printf("[LinkupProvider] ❌ Error response: %s\n", errorBody.String())
-		return nil, fmt.Errorf("Linkup API returned status %d: %s", resp.StatusCode, errorBody.String())
+		return nil, newHTTPStatusError("Linkup", resp.StatusCode, errorBody.String())
 	}
 
 	// Parse the response
@@ -150,14 +153,18 @@ func (p *linkupProvider) RunQuestion(ctx context.Context, query string, websearc
 	fmt.Printf("[LinkupProvider]   - Should process evaluation: %t\n", shouldProcessEvaluation)
 	fmt.Printf("[LinkupProvider]   - Cost: $%.4f\n", fixedCost)
 
-	return &AIResponse{
+	response := &AIResponse{
 		Response:                responseText,
 		InputTokens:             0, // Not available from Linkup
 		OutputTokens:            0, // Not available from Linkup
 		Cost:                    fixedCost,
 		Citations:               citations,
 		ShouldProcessEvaluation: shouldProcessEvaluation,
-	}, nil
+	}
+	if !shouldProcessEvaluation {
+		response.markFailed(ErrorCodeUnknown, "empty answer")
+	}
+	return response, nil
 }
 
 // RunQuestionWebSearch implements AIProvider for web search without location
@@ -212,13 +219,24 @@ func (p *linkupProvider) SupportsBatching() bool {
 	return false
 }
 
+// SupportsWebSearch returns true for Linkup (a search API)
+func (p *linkupProvider) SupportsWebSearch() bool {
+	return true
+}
+
 // GetMaxBatchSize returns 1 for Linkup (no batching)
 func (p *linkupProvider) GetMaxBatchSize() int {
 	return 1
 }
 
+// RunQuestionBatchAwait calls RunQuestionBatch: Linkup answers synchronously
+func (p *linkupProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatch(ctx, batch, websearch, location)
+}
+
 // RunQuestionBatch processes questions sequentially for Linkup (no batching support)
-func (p *linkupProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+func (p *linkupProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[LinkupProvider] 🔄 Processing %d questions sequentially (no batching support)\n", len(queries))
 
 	responses := make([]*AIResponse, len(queries))
@@ -230,6 +248,7 @@ func (p *linkupProvider) RunQuestionBatch(ctx context.Context, queries []string,
 		responses[i] = response
 	}
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
 
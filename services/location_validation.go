@@ -0,0 +1,131 @@
+// services/location_validation.go
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+)
+
+// Providers send a location's country as a 2-letter ISO 3166-1 code (the
+// Responses API rejects anything else in user_location with a 400), but some
+// location rows hold full country names. Locations are validated when org and
+// network details are loaded: names are normalized to their code and
+// locations that still aren't valid are left out of the matrix.
+
+// isoCountryCodes are the officially assigned ISO 3166-1 alpha-2 codes.
+var isoCountryCodes = func() map[string]bool {
+	codes := make(map[string]bool)
+	for _, code := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+		BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+		EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+		HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+		LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+		NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+		SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+		TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`) {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// countryAliases maps lowercased country names and common non-ISO codes to
+// their ISO 3166-1 alpha-2 code.
+var countryAliases = map[string]string{
+	"uk":                       "GB",
+	"usa":                      "US",
+	"united states":            "US",
+	"united states of america": "US",
+	"america":                  "US",
+	"united kingdom":           "GB",
+	"great britain":            "GB",
+	"britain":                  "GB",
+	"england":                  "GB",
+	"canada":                   "CA",
+	"australia":                "AU",
+	"new zealand":              "NZ",
+	"ireland":                  "IE",
+	"germany":                  "DE",
+	"france":                   "FR",
+	"italy":                    "IT",
+	"spain":                    "ES",
+	"portugal":                 "PT",
+	"netherlands":              "NL",
+	"the netherlands":          "NL",
+	"belgium":                  "BE",
+	"switzerland":              "CH",
+	"austria":                  "AT",
+	"sweden":                   "SE",
+	"norway":                   "NO",
+	"denmark":                  "DK",
+	"finland":                  "FI",
+	"poland":                   "PL",
+	"japan":                    "JP",
+	"south korea":              "KR",
+	"korea":                    "KR",
+	"china":                    "CN",
+	"india":                    "IN",
+	"singapore":                "SG",
+	"brazil":                   "BR",
+	"mexico":                   "MX",
+	"argentina":                "AR",
+	"south africa":             "ZA",
+	"united arab emirates":     "AE",
+	"uae":                      "AE",
+	"israel":                   "IL",
+}
+
+// NormalizeCountryCode returns the ISO 3166-1 alpha-2 code of a location's
+// country: the code itself (any case, surrounding space trimmed) or a known
+// alias such as "United States" or "UK". ok is false when neither applies.
+func NormalizeCountryCode(country string) (code string, ok bool) {
+	trimmed := strings.TrimSpace(country)
+	if upper := strings.ToUpper(trimmed); isoCountryCodes[upper] {
+		return upper, true
+	}
+	if code, ok := countryAliases[strings.ToLower(strings.Join(strings.Fields(trimmed), " "))]; ok {
+		return code, true
+	}
+	return "", false
+}
+
+// InvalidLocation is a location left out of the matrix because its country
+// isn't a valid code and has no known alias.
+type InvalidLocation struct {
+	Location *models.OrgLocation
+	Country  string
+}
+
+func (l InvalidLocation) String() string {
+	if l.Location.RegionName != nil && *l.Location.RegionName != "" {
+		return l.Country + "/" + *l.Location.RegionName
+	}
+	return l.Country
+}
+
+// Message describes the exclusion for batch errors and summaries.
+func (l InvalidLocation) Message() string {
+	return fmt.Sprintf("Invalid location %q (id %s): country is not an ISO 3166-1 alpha-2 code, location excluded from the matrix",
+		l.String(), l.Location.OrgLocationID)
+}
+
+// ValidateLocations normalizes each location's CountryCode in place and
+// returns the valid locations, in order, and the ones left out.
+func ValidateLocations(locations []*models.OrgLocation) (valid []*models.OrgLocation, invalid []InvalidLocation) {
+	valid = make([]*models.OrgLocation, 0, len(locations))
+	for _, location := range locations {
+		code, ok := NormalizeCountryCode(location.CountryCode)
+		if !ok {
+			invalid = append(invalid, InvalidLocation{Location: location, Country: location.CountryCode})
+			continue
+		}
+		if code != location.CountryCode {
+			fmt.Printf("[ValidateLocations] Normalized location %s country %q to %s\n", location.OrgLocationID, location.CountryCode, code)
+			location.CountryCode = code
+		}
+		valid = append(valid, location)
+	}
+	return valid, invalid
+}
@@ -0,0 +1,21 @@
+// services/mention_noise.go
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// isNoiseMention reports whether mention text is a stray fragment shorter
+// than MIN_MENTION_RUNES. Extraction sometimes returns a single token as the
+// mention text; counting it would inflate mention counts and share of voice.
+// Empty text is not noise: callers decide what a missing mention means.
+func isNoiseMention(cfg *config.Config, mentionText string) bool {
+	if cfg == nil {
+		return false
+	}
+	n := utf8.RuneCountInString(strings.TrimSpace(mentionText))
+	return n > 0 && n < cfg.MinMentionRunes
+}
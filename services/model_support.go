@@ -0,0 +1,79 @@
+// services/model_support.go
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// builtinModelPatterns are the model-name substrings selectProvider routes
+// without any configuration, in its order.
+var builtinModelPatterns = []string{
+	"chatgpt", "perplexity", "gemini", "linkup", "gpt", "4.1", "claude", "sonnet", "opus", "haiku",
+}
+
+// ListSupportedModelPatterns returns the patterns a configured model name must
+// match for getProvider to route it: MODEL_PROVIDER_OVERRIDES substrings and
+// providers config keys (prefixes) that name a known provider, then the
+// built-in substrings.
+func ListSupportedModelPatterns(cfg *config.Config) []string {
+	var patterns []string
+	if cfg != nil {
+		for substr, providerKey := range cfg.ModelProviderOverrides {
+			if providerKeys[providerKey] {
+				patterns = append(patterns, substr)
+			}
+		}
+	}
+	if providersCfg := activeProvidersConfig.Load(); providersCfg != nil {
+		for key, entry := range providersCfg.Models {
+			if entry.Provider != "" {
+				patterns = append(patterns, key)
+			}
+		}
+	}
+	sort.Strings(patterns)
+	return append(patterns, builtinModelPatterns...)
+}
+
+// IsSupportedModel reports whether getProvider recognizes the model name. It
+// does not check that the provider's credentials are configured.
+func IsSupportedModel(cfg *config.Config, model string) bool {
+	modelLower := strings.ToLower(strings.TrimSpace(model))
+	if modelLower == "" {
+		return false
+	}
+	if cfg != nil {
+		matched := ""
+		for substr := range cfg.ModelProviderOverrides {
+			if strings.Contains(modelLower, substr) && len(substr) > len(matched) {
+				matched = substr
+			}
+		}
+		if matched != "" {
+			return providerKeys[cfg.ModelProviderOverrides[matched]]
+		}
+	}
+	if _, entry, ok := providerModelConfig(model); ok && entry.Provider != "" {
+		return true
+	}
+	for _, pattern := range builtinModelPatterns {
+		if strings.Contains(modelLower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnconfiguredModels returns the model names IsSupportedModel rejects, in order.
+func UnconfiguredModels(cfg *config.Config, modelNames []string) []string {
+	var unknown []string
+	for _, name := range modelNames {
+		if !IsSupportedModel(cfg, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,109 @@
+// services/name_variations.go
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// NameVariationOptions tunes GenerateNameVariationsWithOptions. The zero value
+// keeps the previous behaviour (model default sampling, no count bounds).
+type NameVariationOptions struct {
+	// Deterministic sets temperature 0 (where the model supports it) and a fixed seed.
+	Deterministic bool
+	// Seed used in deterministic mode; 0 means defaultNameVariationSeed.
+	Seed int64
+	// MinCount triggers one re-prompt when fewer variations come back (0 = no minimum).
+	MinCount int
+	// MaxCount truncates the merged list (0 = no maximum). Programmatic variations
+	// are never dropped, so the effective maximum is at least their count.
+	MaxCount int
+}
+
+const defaultNameVariationSeed = 42
+
+// ProgrammaticNameVariations derives the forms mention detection must always
+// have, independent of the model: the original name, its lowercase form,
+// spaced/unspaced compound splits and the domain root of each website.
+func ProgrammaticNameVariations(orgName string, websites []string) []string {
+	name := strings.TrimSpace(orgName)
+	var out []string
+	add := func(v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v, strings.ToLower(v))
+		}
+	}
+
+	add(name)
+
+	// "SunLife" -> "Sun Life"
+	if spaced := splitCompoundName(name); spaced != name {
+		add(spaced)
+	}
+	// "Sun Life" -> "SunLife"
+	if strings.Contains(name, " ") {
+		add(strings.ReplaceAll(name, " ", ""))
+	}
+	// "Senso.ai" -> "Senso"
+	if dot := strings.Index(name, "."); dot > 0 && !strings.Contains(name, " ") {
+		add(name[:dot])
+	}
+
+	for _, website := range websites {
+		base, err := getBaseDomain(strings.TrimSpace(website))
+		if err != nil || base == "" {
+			continue
+		}
+		suffix, _ := publicsuffix.PublicSuffix(base)
+		root := strings.TrimSuffix(strings.TrimSuffix(base, suffix), ".")
+		add(root)
+	}
+
+	return mergeNameVariations(out)
+}
+
+// splitCompoundName inserts a space at lower->upper case boundaries ("TotalExpert" -> "Total Expert").
+func splitCompoundName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// mergeNameVariations concatenates the lists, dropping blanks and exact duplicates
+// while preserving first-seen order.
+func mergeNameVariations(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, list := range lists {
+		for _, v := range list {
+			v = strings.TrimSpace(v)
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// boundNameVariations merges programmatic and AI variations and applies maxCount.
+func boundNameVariations(programmatic, aiNames []string, maxCount int) []string {
+	merged := mergeNameVariations(programmatic, aiNames)
+	if maxCount > 0 && len(merged) > maxCount {
+		limit := maxCount
+		if limit < len(programmatic) {
+			limit = len(programmatic)
+		}
+		merged = merged[:limit]
+	}
+	return merged
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+func TestProgrammaticNameVariations(t *testing.T) {
+	tests := []struct {
+		name     string
+		orgName  string
+		websites []string
+		want     []string
+	}{
+		{name: "compound name", orgName: "SunLife", want: []string{"SunLife", "sunlife", "Sun Life", "sun life"}},
+		{name: "spaced name", orgName: "Total Expert", want: []string{"Total Expert", "total expert", "TotalExpert", "totalexpert"}},
+		{name: "dotted name", orgName: "Senso.ai", want: []string{"Senso.ai", "senso.ai", "Senso", "senso"}},
+		{
+			name:     "website roots",
+			orgName:  "Acme",
+			websites: []string{"https://www.acmebank.co.uk/about", "acme.com", "not a url"},
+			want:     []string{"Acme", "acme", "acmebank"},
+		},
+		{name: "blank name", orgName: "  ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProgrammaticNameVariations(tt.orgName, tt.websites); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProgrammaticNameVariations(%q, %q) = %q, want %q", tt.orgName, tt.websites, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundNameVariations(t *testing.T) {
+	programmatic := []string{"SunLife", "sunlife", "Sun Life"}
+	tests := []struct {
+		name     string
+		aiNames  []string
+		maxCount int
+		want     []string
+	}{
+		{name: "no bound", aiNames: []string{"Sun Life Financial", "sunlife"}, want: []string{"SunLife", "sunlife", "Sun Life", "Sun Life Financial"}},
+		{name: "bound above programmatic", aiNames: []string{"SLF", "Sun-Life"}, maxCount: 4, want: []string{"SunLife", "sunlife", "Sun Life", "SLF"}},
+		{name: "bound below programmatic keeps them all", aiNames: []string{"SLF"}, maxCount: 1, want: []string{"SunLife", "sunlife", "Sun Life"}},
+		{name: "no ai names", maxCount: 10, want: []string{"SunLife", "sunlife", "Sun Life"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boundNameVariations(programmatic, tt.aiNames, tt.maxCount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("boundNameVariations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGenerateNameVariationsKeepsProgrammatic mocks the name variation model
+// and checks the programmatic forms come back whatever the model returns.
+func TestGenerateNameVariationsKeepsProgrammatic(t *testing.T) {
+	const orgName = "SunLife"
+	websites := []string{"sunlife.com"}
+	programmatic := ProgrammaticNameVariations(orgName, websites)
+
+	tests := []struct {
+		name      string
+		replies   [][]string // one per call; the last one repeats
+		opts      NameVariationOptions
+		wantCalls int
+		wantLen   int
+	}{
+		{name: "model returns nothing", replies: [][]string{{}}, wantCalls: 1, wantLen: len(programmatic)},
+		{name: "model misses the spaced form", replies: [][]string{{"SUNLIFE", "Sun Life Financial"}}, wantCalls: 1, wantLen: len(programmatic) + 2},
+		{
+			name:      "max count below the model's list",
+			replies:   [][]string{{"SLF", "Sun-Life", "SUN LIFE", "Sun Life Financial", "Sun Life Assurance"}},
+			opts:      NameVariationOptions{MaxCount: len(programmatic) + 1},
+			wantCalls: 1,
+			wantLen:   len(programmatic) + 1,
+		},
+		{
+			name:      "min count re-prompts once",
+			replies:   [][]string{{"SLF"}, {"Sun-Life"}},
+			opts:      NameVariationOptions{MinCount: 50},
+			wantCalls: 2,
+			wantLen:   len(programmatic) + 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				names := tt.replies[min(calls, len(tt.replies)-1)]
+				calls++
+				content, _ := json.Marshal(NameListResponse{Names: names})
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4.1-mini","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":%q}}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`, content)
+			}))
+			defer server.Close()
+
+			client := openai.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test-key"), option.WithMaxRetries(0))
+			s := &orgEvaluationService{cfg: &config.Config{}, openAIClient: &client}
+
+			names, err := s.GenerateNameVariationsWithOptions(context.Background(), orgName, websites, tt.opts)
+			if err != nil {
+				t.Fatalf("GenerateNameVariationsWithOptions() error = %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("model called %d times, want %d", calls, tt.wantCalls)
+			}
+			if len(names) != tt.wantLen {
+				t.Errorf("got %d variations %q, want %d", len(names), names, tt.wantLen)
+			}
+			if len(names) < len(programmatic) || !reflect.DeepEqual(names[:len(programmatic)], programmatic) {
+				t.Errorf("variations %q do not start with the programmatic %q", names, programmatic)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// services/network_org_claims.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// The missing processor lists the (question run, org) pairs without a network
+// org evaluation and then extracts them one step at a time. Meanwhile the
+// re-eval processor (or another missing run) may store evaluations for the
+// same pairs, which the missing path's cleanup-then-insert would delete and
+// pay for again. Processors therefore claim a pair before touching it: the
+// claim is an insert-if-absent row, released when the processor is done. A
+// claim older than NETWORK_ORG_CLAIM_TTL_MINUTES (a crashed worker) can be
+// taken over, and the same holder may re-claim its own pair, so retried
+// workflow steps are not locked out.
+// Schema:
+//
+//	migrations/000016_network_org_eval_claims.up.sql
+
+// ErrNetworkOrgRunClaimed is returned when another processor is extracting
+// the same question run for the same org.
+var ErrNetworkOrgRunClaimed = errors.New("network org extraction claimed by another processor")
+
+// ErrNetworkOrgEvalExists is returned when a run listed as missing has been
+// evaluated since the listing.
+var ErrNetworkOrgEvalExists = errors.New("network org evaluation already exists")
+
+// NetworkOrgClaimRepository stores in-progress network org extractions.
+type NetworkOrgClaimRepository interface {
+	// Claim takes the pair for holder unless another holder claimed it less
+	// than ttl ago; it reports whether holder now has the claim.
+	Claim(ctx context.Context, questionRunID, orgID uuid.UUID, holder string, ttl time.Duration) (bool, error)
+	// Release drops holder's claim on the pair; other holders' claims are kept.
+	Release(ctx context.Context, questionRunID, orgID uuid.UUID, holder string) error
+	// EvaluationExists reports whether the pair has a network org evaluation.
+	EvaluationExists(ctx context.Context, questionRunID, orgID uuid.UUID) (bool, error)
+}
+
+type networkOrgClaimRepo struct {
+	db *database.Client
+}
+
+func NewNetworkOrgClaimRepo(db *database.Client) NetworkOrgClaimRepository {
+	return &networkOrgClaimRepo{db: db}
+}
+
+func (r *networkOrgClaimRepo) Claim(ctx context.Context, questionRunID, orgID uuid.UUID, holder string, ttl time.Duration) (bool, error) {
+	var claimed []uuid.UUID
+	err := r.db.SelectContext(ctx, &claimed, `
+		INSERT INTO network_org_eval_claims (question_run_id, org_id, claimed_by, claimed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (question_run_id, org_id) DO UPDATE
+		SET claimed_by = EXCLUDED.claimed_by, claimed_at = EXCLUDED.claimed_at
+		WHERE network_org_eval_claims.claimed_by = EXCLUDED.claimed_by
+		   OR network_org_eval_claims.claimed_at < NOW() - make_interval(secs => $4)
+		RETURNING question_run_id`, questionRunID, orgID, holder, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim question run %s for org %s: %w", questionRunID, orgID, err)
+	}
+	return len(claimed) > 0, nil
+}
+
+func (r *networkOrgClaimRepo) Release(ctx context.Context, questionRunID, orgID uuid.UUID, holder string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM network_org_eval_claims
+		WHERE question_run_id = $1 AND org_id = $2 AND claimed_by = $3`, questionRunID, orgID, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release claim on question run %s for org %s: %w", questionRunID, orgID, err)
+	}
+	return nil
+}
+
+func (r *networkOrgClaimRepo) EvaluationExists(ctx context.Context, questionRunID, orgID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM network_org_evals WHERE question_run_id = $1 AND org_id = $2
+		)`, questionRunID, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check evaluation of question run %s for org %s: %w", questionRunID, orgID, err)
+	}
+	return exists, nil
+}
+
+// claimNetworkOrgRun claims the pair for holder and returns the function that
+// releases it. Without a claim repository nothing is claimed. A failed claim
+// or release is logged and the extraction goes ahead, as before claims.
+func claimNetworkOrgRun(ctx context.Context, cfg *config.Config, repos *RepositoryManager, questionRunID, orgID uuid.UUID, holder string) (release func(), err error) {
+	release = func() {}
+	if repos.NetworkOrgClaimRepo == nil {
+		return release, nil
+	}
+	claimed, err := repos.NetworkOrgClaimRepo.Claim(ctx, questionRunID, orgID, holder, time.Duration(cfg.NetworkOrgClaimTTLMinutes)*time.Minute)
+	if err != nil {
+		fmt.Printf("[claimNetworkOrgRun] Warning: %v\n", err)
+		return release, nil
+	}
+	if !claimed {
+		return release, ErrNetworkOrgRunClaimed
+	}
+	return func() {
+		// The step's context may be done by now; the release must still happen
+		if err := repos.NetworkOrgClaimRepo.Release(context.WithoutCancel(ctx), questionRunID, orgID, holder); err != nil {
+			fmt.Printf("[claimNetworkOrgRun] Warning: %v\n", err)
+		}
+	}, nil
+}
+
+// ProcessMissingNetworkOrgQuestionRun claims the pair for claimHolder, checks
+// that the run is still missing its evaluation and only then runs the
+// cleanup-then-insert extraction, so a fresh evaluation is never deleted and
+// paid for again.
+func (s *questionRunnerService) ProcessMissingNetworkOrgQuestionRun(ctx context.Context, claimHolder string, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error) {
+	release, err := claimNetworkOrgRun(ctx, s.cfg, s.repos, questionRunID, orgID, claimHolder)
+	if err != nil {
+		fmt.Printf("[ProcessMissingNetworkOrgQuestionRun] Skipping question run %s for org %s: %v\n", questionRunID, orgID, err)
+		return nil, err
+	}
+	defer release()
+
+	if s.repos.NetworkOrgClaimRepo != nil {
+		exists, err := s.repos.NetworkOrgClaimRepo.EvaluationExists(ctx, questionRunID, orgID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			fmt.Printf("[ProcessMissingNetworkOrgQuestionRun] Skipping question run %s for org %s: evaluated since the listing\n", questionRunID, orgID)
+			return nil, ErrNetworkOrgEvalExists
+		}
+	}
+
+	return s.ProcessNetworkOrgQuestionRunWithCleanup(ctx, questionRunID, orgID, orgName, orgWebsites, nameVariations, questionText, responseText)
+}
@@ -0,0 +1,161 @@
+// services/network_org_dead_letters.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Dead letters: a network org extraction that fails (extraction or DB error)
+// used to stay missing until the next full missing-scan, which for big
+// networks runs rarely. ProcessNetworkOrgQuestionRunWithCleanup now records
+// each failed (question run, org) pair with its last error and attempt count,
+// and removes the entry once the pair succeeds. ReplayNetworkOrgDeadLetters
+// retries an org's entries below NETWORK_ORG_DEAD_LETTER_MAX_ATTEMPTS;
+// entries at the limit are no longer retried and show up in the weekly
+// reconciliation report instead. Empty responses are not dead-lettered: a
+// retry would fail the same way.
+// Schema:
+//
+//	migrations/000013_network_org_dead_letters.up.sql
+
+// NetworkOrgDeadLetter is a network org extraction that failed and has not
+// succeeded since.
+type NetworkOrgDeadLetter struct {
+	QuestionRunID uuid.UUID `db:"question_run_id" json:"question_run_id"`
+	OrgID         uuid.UUID `db:"org_id" json:"org_id"`
+	Error         string    `db:"error" json:"error"`
+	Attempts      int       `db:"attempts" json:"attempts"`
+	FirstFailedAt time.Time `db:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// Exhausted reports whether the entry has used up its attempts. A maxAttempts
+// of 0 or less never exhausts an entry.
+func (d NetworkOrgDeadLetter) Exhausted(maxAttempts int) bool {
+	return maxAttempts > 0 && d.Attempts >= maxAttempts
+}
+
+// SplitDeadLetters separates the entries still to retry from the exhausted ones.
+func SplitDeadLetters(entries []NetworkOrgDeadLetter, maxAttempts int) (pending, exhausted []NetworkOrgDeadLetter) {
+	for _, entry := range entries {
+		if entry.Exhausted(maxAttempts) {
+			exhausted = append(exhausted, entry)
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+	return pending, exhausted
+}
+
+// NetworkOrgDeadLetterRepository stores failed network org extractions.
+type NetworkOrgDeadLetterRepository interface {
+	// Record adds the pair with one attempt, or bumps the attempts and
+	// replaces the error of an existing entry.
+	Record(ctx context.Context, questionRunID, orgID uuid.UUID, errMsg string) error
+	Delete(ctx context.Context, questionRunID, orgID uuid.UUID) error
+	// ListByOrg returns the org's entries, oldest failure first.
+	ListByOrg(ctx context.Context, orgID uuid.UUID) ([]NetworkOrgDeadLetter, error)
+	// List returns every entry, oldest failure first.
+	List(ctx context.Context) ([]NetworkOrgDeadLetter, error)
+}
+
+type networkOrgDeadLetterRepo struct {
+	db *database.Client
+}
+
+func NewNetworkOrgDeadLetterRepo(db *database.Client) NetworkOrgDeadLetterRepository {
+	return &networkOrgDeadLetterRepo{db: db}
+}
+
+func (r *networkOrgDeadLetterRepo) Record(ctx context.Context, questionRunID, orgID uuid.UUID, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO network_org_dead_letters (question_run_id, org_id, error)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (question_run_id, org_id) DO UPDATE
+		SET error = EXCLUDED.error,
+		    attempts = network_org_dead_letters.attempts + 1,
+		    last_failed_at = NOW()`,
+		questionRunID, orgID, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to record network org dead letter: %w", err)
+	}
+	return nil
+}
+
+func (r *networkOrgDeadLetterRepo) Delete(ctx context.Context, questionRunID, orgID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM network_org_dead_letters
+		WHERE question_run_id = $1 AND org_id = $2`, questionRunID, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete network org dead letter: %w", err)
+	}
+	return nil
+}
+
+func (r *networkOrgDeadLetterRepo) ListByOrg(ctx context.Context, orgID uuid.UUID) ([]NetworkOrgDeadLetter, error) {
+	var rows []NetworkOrgDeadLetter
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT question_run_id, org_id, error, attempts, first_failed_at, last_failed_at
+		FROM network_org_dead_letters
+		WHERE org_id = $1
+		ORDER BY first_failed_at`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network org dead letters: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *networkOrgDeadLetterRepo) List(ctx context.Context) ([]NetworkOrgDeadLetter, error) {
+	var rows []NetworkOrgDeadLetter
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT question_run_id, org_id, error, attempts, first_failed_at, last_failed_at
+		FROM network_org_dead_letters
+		ORDER BY first_failed_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network org dead letters: %w", err)
+	}
+	return rows, nil
+}
+
+// updateNetworkOrgDeadLetter records a failed extraction of the pair, or
+// clears its entry after a success. Empty responses are never recorded.
+// Failures are logged, not fatal.
+func updateNetworkOrgDeadLetter(ctx context.Context, repos *RepositoryManager, questionRunID, orgID uuid.UUID, extractErr error) {
+	if repos.NetworkOrgDeadLetterRepo == nil {
+		return
+	}
+	var err error
+	switch {
+	case extractErr == nil:
+		err = repos.NetworkOrgDeadLetterRepo.Delete(ctx, questionRunID, orgID)
+	case errors.Is(extractErr, ErrEmptyResponse):
+		return
+	default:
+		err = repos.NetworkOrgDeadLetterRepo.Record(ctx, questionRunID, orgID, extractErr.Error())
+	}
+	if err != nil {
+		fmt.Printf("[updateNetworkOrgDeadLetter] Warning: run %s org %s: %v\n", questionRunID, orgID, err)
+	}
+}
+
+// NetworkOrgDeadLetterReplaySummary is the outcome of replaying an org's dead letters.
+type NetworkOrgDeadLetterReplaySummary struct {
+	OrgID string `json:"org_id"`
+	// Pending entries were below the attempt limit. Each was replayed and
+	// removed, failed again, or dropped because its run or question no
+	// longer exists.
+	Pending  int `json:"pending"`
+	Replayed int `json:"replayed"`
+	Failed   int `json:"failed"`
+	Dropped  int `json:"dropped"`
+	// Exhausted entries are at the attempt limit and were not retried.
+	Exhausted int `json:"exhausted"`
+	// ReplayedRunIDs are the runs of the replayed entries, for usage tracking.
+	ReplayedRunIDs []uuid.UUID `json:"replayed_run_ids,omitempty"`
+}
@@ -0,0 +1,159 @@
+// services/network_org_eval_prompt.go
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MentionConfidence is how sure the caller's name pre-filter is that the org
+// actually appears in the response; it selects the evaluation prompt variant.
+type MentionConfidence int
+
+const (
+	// MentionConfirmed: the prompt tells the model the org is mentioned.
+	MentionConfirmed MentionConfidence = iota
+	// MentionUncertain: the model decides, and returns empty mention text when
+	// the pre-filter hit was a false positive.
+	MentionUncertain
+)
+
+func (c MentionConfidence) String() string {
+	if c == MentionUncertain {
+		return "uncertain"
+	}
+	return "confirmed"
+}
+
+// minConfidentVariationLen is the shortest name variation whose substring match
+// counts as a confirmed mention; shorter ones (acronyms, short brands) often
+// match inside unrelated words.
+const minConfidentVariationLen = 4
+
+// detectMention runs the substring pre-filter over the response. A match on a
+// long enough variation is confirmed; matches only on short ones are uncertain.
+func detectMention(responseText string, nameVariations []string) (bool, MentionConfidence) {
+	responseTextLower := strings.ToLower(responseText)
+	mentioned := false
+	for _, name := range nameVariations {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || !strings.Contains(responseTextLower, name) {
+			continue
+		}
+		if len([]rune(name)) >= minConfidentVariationLen {
+			return true, MentionConfirmed
+		}
+		mentioned = true
+	}
+	return mentioned, MentionUncertain
+}
+
+type networkOrgEvalPromptVariant struct {
+	system       string
+	intro        string
+	mentionedReq string
+	rankReq      string
+}
+
+var networkOrgEvalPromptVariants = map[MentionConfidence]networkOrgEvalPromptVariant{
+	MentionConfirmed: {
+		system:       "You are an expert text extraction specialist. Extract mention text with perfect formatting and determine sentiment. The organization is already confirmed to be mentioned.",
+		intro:        "You are an expert text extraction specialist. The target organization IS MENTIONED in this text. Your task is to extract ALL text that mentions the organization and determine the overall sentiment.",
+		mentionedReq: "- mentioned: true (we already confirmed it's mentioned)\n",
+		rankReq:      "- mention_rank: integer (1 for most prominent)",
+	},
+	MentionUncertain: {
+		system: "You are an expert text extraction specialist. First decide whether the organization is actually mentioned. If it is, extract mention text with perfect formatting and determine sentiment; if not, return empty mention text.",
+		intro: "You are an expert text extraction specialist. A name pre-filter flagged this text as POSSIBLY mentioning the target organization, but the match may be a false positive " +
+			"(a short name inside another word, or a different entity with a similar name). First decide whether the target organization is actually mentioned. " +
+			"If it is, extract ALL text that mentions the organization and determine the overall sentiment.",
+		mentionedReq: "",
+		rankReq: "- mention_rank: integer (1 for most prominent)\n" +
+			"- If the organization is NOT actually mentioned: mention_text must be \"\", sentiment \"neutral\", citation false, mention_rank 0",
+	},
+}
+
+const networkOrgEvalPromptTemplate = `%s
+
+**TARGET ORGANIZATION:** %s
+**Organization name variations:** %s
+%s
+**QUESTION:** %s
+
+**TASK 1: EXTRACT MENTION TEXT**
+
+Find EVERY occurrence where the target organization is mentioned by name (including variations) and extract the text with perfect formatting preservation.
+
+**EXTRACTION RULES:**
+- **PRESERVE EXACT FORMATTING**: Copy text character-for-character including:
+  - All punctuation marks (periods, commas, colons, semicolons, etc.)
+  - All markdown formatting (**, *, ##, [], (), etc.)
+  - All spacing, line breaks, and indentation
+  - All special characters and symbols
+- **INCLUDE CITATIONS**: Always include URLs, links, and citation references that appear with mentions
+- **ALL FORMATS**: Extract from paragraphs, lists, tables, headers, footnotes, structured data
+- **COMPLETE CONTEXT**: Extract the full sentence/paragraph/section that contains the mention
+
+**SPAN DEFINITION:**
+- **Single occurrence** = Complete sentence, bullet point, table row, or logical text unit that mentions the organization
+- **Adjacent context** = If consecutive sentences form one continuous thought about the organization, keep them together
+- **Aggregation** = Use exact delimiter " || " (space-pipe-pipe-space) between separate occurrences
+
+**TASK 2: DETERMINE SENTIMENT**
+
+Analyze the overall sentiment toward the target organization across all mentions:
+- **positive**: Favorable language, praise, recommendations ("excellent", "best", "recommended", "leading")
+- **negative**: Critical language, problems, warnings ("poor", "issues", "avoid", "problematic")
+- **neutral**: Factual, descriptive, balanced content without clear bias
+
+**TASK 3: CITATION CHECK**
+
+Determine if the response contains URLs that relate to this specific organization:
+- Set citation=true if URLs from organization's domains are present OR if external URLs mention the organization
+- Set citation=false if no relevant URLs found
+
+**TASK 4: MENTION RANK**
+
+Assign prominence ranking (1=most prominent, higher numbers=less prominent, 0=not mentioned)
+
+**RESPONSE TO ANALYZE:**
+` + "`" + `
+%s
+` + "`" + `
+
+**OUTPUT REQUIREMENTS:**
+%s- mention_text: ALL extracted text with perfect formatting, separated by " || "
+- sentiment: exactly one of "positive", "negative", or "neutral" (lowercase)
+- citation: true or false
+%s`
+
+// buildNetworkOrgEvaluationPrompt returns the system and user prompt for
+// ExtractNetworkOrgEvaluation in the variant matching the pre-filter confidence.
+func buildNetworkOrgEvaluationPrompt(confidence MentionConfidence, orgName string, nameVariations []string, orgWebsites []string, questionText string, responseText string) (string, string) {
+	variant, ok := networkOrgEvalPromptVariants[confidence]
+	if !ok {
+		variant = networkOrgEvalPromptVariants[MentionConfirmed]
+	}
+
+	websitesList := ""
+	if len(orgWebsites) > 0 {
+		websitesList = "\n## ORGANIZATION DOMAINS (SUPPORTING SIGNALS):\n"
+		for _, website := range orgWebsites {
+			websitesList += fmt.Sprintf("- %s\n", website)
+		}
+		websitesList += "\n"
+	}
+
+	prompt := fmt.Sprintf(networkOrgEvalPromptTemplate, variant.intro, "`"+orgName+"`", strings.Join(nameVariations, ", "),
+		websitesList, questionText, responseText, variant.mentionedReq, variant.rankReq)
+	return variant.system, prompt
+}
+
+// mentioned reports whether the evaluation found the org. Confirmed prompts
+// always count as mentioned; uncertain ones only when mention text came back.
+func (r NetworkOrgEvaluationResponse) mentioned(confidence MentionConfidence) bool {
+	if confidence != MentionUncertain {
+		return true
+	}
+	return strings.TrimSpace(r.MentionText) != ""
+}
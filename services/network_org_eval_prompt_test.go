@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDetectMention(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		variations     []string
+		wantMentioned  bool
+		wantConfidence MentionConfidence
+	}{
+		{name: "long variation", response: "Try Acme Bank for savings.", variations: []string{"AB", "Acme Bank"}, wantMentioned: true, wantConfidence: MentionConfirmed},
+		{name: "case insensitive", response: "try ACME BANK for savings.", variations: []string{"Acme Bank"}, wantMentioned: true, wantConfidence: MentionConfirmed},
+		{name: "only a short variation", response: "The LAB offers savings.", variations: []string{"AB", "Acme Bank"}, wantMentioned: true, wantConfidence: MentionUncertain},
+		{name: "no match", response: "Globex offers savings.", variations: []string{"AB", "Acme Bank"}},
+		{name: "blank variations", response: "Globex offers savings.", variations: []string{"", "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mentioned, confidence := detectMention(tt.response, tt.variations)
+			if mentioned != tt.wantMentioned || (mentioned && confidence != tt.wantConfidence) {
+				t.Errorf("detectMention() = %t, %s; want %t, %s", mentioned, confidence, tt.wantMentioned, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestBuildNetworkOrgEvaluationPrompt(t *testing.T) {
+	confirmedOnly := []string{"already confirmed", "IS MENTIONED", "mentioned: true"}
+	tests := []struct {
+		confidence MentionConfidence
+		want       []string
+		wantNot    []string
+	}{
+		{confidence: MentionConfirmed, want: confirmedOnly},
+		{confidence: MentionUncertain, want: []string{"POSSIBLY", `mention_text must be ""`}, wantNot: confirmedOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.confidence.String(), func(t *testing.T) {
+			system, prompt := buildNetworkOrgEvaluationPrompt(tt.confidence, "Acme Bank", []string{"Acme Bank", "AB"},
+				[]string{"acmebank.com"}, "Which bank is best?", "The LAB offers savings.")
+			both := system + "\n" + prompt
+			for _, want := range append(tt.want, "Acme Bank", "acmebank.com", "Which bank is best?", "The LAB offers savings.", "- mention_text:") {
+				if !strings.Contains(both, want) {
+					t.Errorf("prompt does not contain %q", want)
+				}
+			}
+			for _, notWant := range tt.wantNot {
+				if strings.Contains(both, notWant) {
+					t.Errorf("prompt contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+// TestExtractNetworkOrgEvaluationConfidence checks the uncertain variant's
+// reply parses, and an empty mention text turns the pre-filter hit into a
+// not-mentioned evaluation that keeps the call's cost.
+func TestExtractNetworkOrgEvaluationConfidence(t *testing.T) {
+	tests := []struct {
+		name          string
+		confidence    MentionConfidence
+		reply         NetworkOrgEvaluationResponse
+		wantMentioned bool
+	}{
+		{
+			name:          "uncertain, model confirms",
+			confidence:    MentionUncertain,
+			reply:         NetworkOrgEvaluationResponse{MentionText: "AB offers savings", Sentiment: "positive", MentionRank: 1},
+			wantMentioned: true,
+		},
+		{
+			name:       "uncertain, false positive",
+			confidence: MentionUncertain,
+			reply:      NetworkOrgEvaluationResponse{Sentiment: "neutral"},
+		},
+		{
+			name:          "confirmed trusts the pre-filter",
+			confidence:    MentionConfirmed,
+			reply:         NetworkOrgEvaluationResponse{Sentiment: "neutral"},
+			wantMentioned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newExtractionService(t, nil)
+			mock.evaluation = tt.reply
+
+			result, err := s.ExtractNetworkOrgEvaluation(context.Background(), uuid.New(), uuid.New(), "Acme Bank",
+				[]string{"acmebank.com"}, []string{"Acme Bank", "AB"}, "Which bank is best?", "The LAB offers savings.", tt.confidence)
+			if err != nil {
+				t.Fatalf("ExtractNetworkOrgEvaluation() error = %v", err)
+			}
+			eval := result.Evaluation
+			if eval.Mentioned != tt.wantMentioned {
+				t.Errorf("Mentioned = %t, want %t", eval.Mentioned, tt.wantMentioned)
+			}
+			if !tt.wantMentioned && (eval.Sentiment != nil || eval.MentionText != nil || eval.MentionRank != nil) {
+				t.Errorf("not-mentioned evaluation = %+v, want no sentiment, mention text or rank", eval)
+			}
+			if eval.TotalCost == nil || *eval.TotalCost != result.TotalCost || result.TotalCost <= 0 {
+				t.Errorf("evaluation cost = %v, result cost %f; want the call's cost kept", eval.TotalCost, result.TotalCost)
+			}
+			if len(mock.prompts) != 1 {
+				t.Fatalf("%d evaluation prompts, want 1", len(mock.prompts))
+			}
+			if confirmed := strings.Contains(mock.prompts[0], "IS MENTIONED"); confirmed != (tt.confidence == MentionConfirmed) {
+				t.Errorf("prompt sent for %s confidence asserts the mention: %t", tt.confidence, confirmed)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// services/network_org_stage_usage.go
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Per-stage accounting for network org extraction, so a spend spike can be
+// traced to the evaluation or the competitor call. The eval/competitor rows
+// only carry their own tokens and cost, so the breakdown (with model and
+// duration) is stored separately, one row per stage:
+//
+//	migrations/000003_network_org_stage_usage.up.sql
+
+// ExtractionStageUsage is the token/cost/latency accounting of one extraction stage.
+// Model is empty for stages that made no AI call (regex citations, or the
+// evaluation when the org is not mentioned). Status is StageSkippedPlan when
+// the org's plan excludes the stage and StageFiltered when the content filter
+// blocked its AI call; empty means it ran.
+type ExtractionStageUsage struct {
+	Status       StageStatus
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+	DurationMs   int64
+}
+
+func (u *ExtractionStageUsage) add(other ExtractionStageUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.Cost += other.Cost
+	u.DurationMs += other.DurationMs
+	if u.Model == "" {
+		u.Model = other.Model
+	}
+	if u.Status == "" {
+		u.Status = other.Status
+	}
+}
+
+func (u ExtractionStageUsage) status() StageStatus {
+	if u.Status == "" {
+		return StageOK
+	}
+	return u.Status
+}
+
+// NetworkOrgStageBreakdown holds the per-stage accounting of a network org extraction.
+type NetworkOrgStageBreakdown struct {
+	Evaluation  ExtractionStageUsage
+	Competitors ExtractionStageUsage
+	Citations   ExtractionStageUsage
+}
+
+// Total sums tokens, cost and duration across all stages (Model is left empty).
+func (b NetworkOrgStageBreakdown) Total() ExtractionStageUsage {
+	return ExtractionStageUsage{
+		InputTokens:  b.Evaluation.InputTokens + b.Competitors.InputTokens + b.Citations.InputTokens,
+		OutputTokens: b.Evaluation.OutputTokens + b.Competitors.OutputTokens + b.Citations.OutputTokens,
+		Cost:         b.Evaluation.Cost + b.Competitors.Cost + b.Citations.Cost,
+		DurationMs:   b.Evaluation.DurationMs + b.Competitors.DurationMs + b.Citations.DurationMs,
+	}
+}
+
+// Add accumulates other into b stage by stage (used for org-level totals).
+func (b *NetworkOrgStageBreakdown) Add(other NetworkOrgStageBreakdown) {
+	b.Evaluation.add(other.Evaluation)
+	b.Competitors.add(other.Competitors)
+	b.Citations.add(other.Citations)
+}
+
+// PlanSkipped returns the stages skipped because the org's plan excludes them.
+func (b NetworkOrgStageBreakdown) PlanSkipped() []string {
+	var skipped []string
+	if b.Competitors.Status == StageSkippedPlan {
+		skipped = append(skipped, FeatureCompetitors)
+	}
+	if b.Citations.Status == StageSkippedPlan {
+		skipped = append(skipped, FeatureCitations)
+	}
+	return skipped
+}
+
+// String renders the breakdown as one key=value log line.
+func (b NetworkOrgStageBreakdown) String() string {
+	total := b.Total()
+	return fmt.Sprintf("eval_status=%s eval_model=%s eval_in=%d eval_out=%d eval_cost=%.6f eval_ms=%d "+
+		"comp_status=%s comp_model=%s comp_in=%d comp_out=%d comp_cost=%.6f comp_ms=%d "+
+		"cit_status=%s cit_in=%d cit_out=%d cit_cost=%.6f cit_ms=%d "+
+		"total_in=%d total_out=%d total_cost=%.6f total_ms=%d",
+		b.Evaluation.status(), b.Evaluation.Model, b.Evaluation.InputTokens, b.Evaluation.OutputTokens, b.Evaluation.Cost, b.Evaluation.DurationMs,
+		b.Competitors.status(), b.Competitors.Model, b.Competitors.InputTokens, b.Competitors.OutputTokens, b.Competitors.Cost, b.Competitors.DurationMs,
+		b.Citations.status(), b.Citations.InputTokens, b.Citations.OutputTokens, b.Citations.Cost, b.Citations.DurationMs,
+		total.InputTokens, total.OutputTokens, total.Cost, total.DurationMs)
+}
+
+// NetworkOrgStageUsageRepository persists NetworkOrgStageBreakdown per question run and org.
+type NetworkOrgStageUsageRepository interface {
+	Save(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, stages NetworkOrgStageBreakdown) error
+}
+
+type networkOrgStageUsageRepo struct {
+	db *database.Client
+}
+
+func NewNetworkOrgStageUsageRepo(db *database.Client) NetworkOrgStageUsageRepository {
+	return &networkOrgStageUsageRepo{db: db}
+}
+
+// Save writes all three stages, including zero rows for stages without AI
+// cost, replacing any earlier breakdown for the same run and org.
+func (r *networkOrgStageUsageRepo) Save(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, stages NetworkOrgStageBreakdown) error {
+	rows := []struct {
+		stage string
+		usage ExtractionStageUsage
+	}{
+		{"evaluation", stages.Evaluation},
+		{"competitors", stages.Competitors},
+		{"citations", stages.Citations},
+	}
+	for _, row := range rows {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO network_org_stage_usage
+				(question_run_id, org_id, stage, status, model, input_tokens, output_tokens, total_cost, duration_ms)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (question_run_id, org_id, stage) DO UPDATE SET
+				status = EXCLUDED.status,
+				model = EXCLUDED.model,
+				input_tokens = EXCLUDED.input_tokens,
+				output_tokens = EXCLUDED.output_tokens,
+				total_cost = EXCLUDED.total_cost,
+				duration_ms = EXCLUDED.duration_ms,
+				created_at = NOW()`,
+			questionRunID, orgID, row.stage, string(row.usage.status()), row.usage.Model, row.usage.InputTokens, row.usage.OutputTokens, row.usage.Cost, row.usage.DurationMs)
+		if err != nil {
+			return fmt.Errorf("failed to save %s stage usage: %w", row.stage, err)
+		}
+	}
+	return nil
+}
+
+// saveNetworkOrgStageUsage persists the breakdown when the repository is
+// configured; failures are logged, not fatal.
+func saveNetworkOrgStageUsage(ctx context.Context, repos *RepositoryManager, questionRunID, orgID uuid.UUID, result *NetworkOrgExtractionResult) {
+	if repos.NetworkOrgStageUsageRepo == nil || result == nil {
+		return
+	}
+	if err := repos.NetworkOrgStageUsageRepo.Save(ctx, questionRunID, orgID, result.Stages); err != nil {
+		fmt.Printf("[saveNetworkOrgStageUsage] Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// extractionUsage is the token usage the mock extraction model reports per
+// schema, so each stage's cost is distinct.
+var extractionUsage = map[string][2]int{
+	"network_org_evaluation_extraction": {1200, 300},
+	"network_org_competitor_extraction": {800, 40},
+}
+
+// extractionServer mocks the network org extraction calls: the evaluation
+// replies with evaluation (the org found, by default), the competitor call
+// returns one name. calls counts the requests per response_format schema name.
+type extractionServer struct {
+	mu         sync.Mutex
+	calls      map[string]int
+	evaluation NetworkOrgEvaluationResponse
+	prompts    []string // user prompts of the evaluation calls
+}
+
+func (e *extractionServer) count(schema string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls[schema]
+}
+
+// newExtractionService returns a dataExtractionService backed by the mock
+// extraction model, with features deciding the org's stages.
+func newExtractionService(t *testing.T, features OrgFeatureProvider) (*dataExtractionService, *extractionServer) {
+	t.Helper()
+	mock := &extractionServer{
+		calls:      map[string]int{},
+		evaluation: NetworkOrgEvaluationResponse{MentionText: "Acme Bank offers", Sentiment: "positive", MentionRank: 1},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+			ResponseFormat struct {
+				JSONSchema struct {
+					Name string `json:"name"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		schema := req.ResponseFormat.JSONSchema.Name
+		mock.mu.Lock()
+		mock.calls[schema]++
+		evaluation := mock.evaluation
+		if schema == "network_org_evaluation_extraction" {
+			for _, message := range req.Messages {
+				if message.Role == "user" {
+					mock.prompts = append(mock.prompts, message.Content)
+				}
+			}
+		}
+		mock.mu.Unlock()
+
+		var reply any
+		switch schema {
+		case "network_org_evaluation_extraction":
+			reply = evaluation
+		case "network_org_competitor_extraction":
+			reply = CompetitorListResponse{Competitors: []string{"Globex"}}
+		default:
+			http.Error(w, "unexpected schema "+schema, http.StatusBadRequest)
+			return
+		}
+		content, _ := json.Marshal(reply)
+		usage := extractionUsage[schema]
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4.1","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":%q}}],"usage":{"prompt_tokens":%d,"completion_tokens":%d,"total_tokens":%d}}`,
+			content, usage[0], usage[1], usage[0]+usage[1])
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	client := openai.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test-key"), option.WithMaxRetries(0))
+	return &dataExtractionService{
+		cfg:          cfg,
+		openAIClient: &client,
+		costService:  NewCostService(cfg),
+		features:     features,
+		exclusions:   newCompetitorExclusions(cfg),
+	}, mock
+}
+
+func TestNetworkOrgStageBreakdownTotal(t *testing.T) {
+	runs := []NetworkOrgStageBreakdown{
+		{
+			Evaluation:  ExtractionStageUsage{Status: StageOK, Model: "gpt-4.1", InputTokens: 1200, OutputTokens: 300, Cost: 0.0048, DurationMs: 900},
+			Competitors: ExtractionStageUsage{Status: StageOK, Model: "gpt-4.1-mini", InputTokens: 800, OutputTokens: 40, Cost: 0.0004, DurationMs: 300},
+			Citations:   ExtractionStageUsage{Status: StageOK, DurationMs: 1},
+		},
+		{
+			Evaluation:  ExtractionStageUsage{Status: StageFiltered, DurationMs: 200},
+			Competitors: ExtractionStageUsage{Status: StageOK, Model: "gpt-4.1-mini", InputTokens: 500, OutputTokens: 20, Cost: 0.0002, DurationMs: 250},
+			Citations:   ExtractionStageUsage{Status: StageOK},
+		},
+		{
+			Competitors: ExtractionStageUsage{Status: StageSkippedPlan},
+			Citations:   ExtractionStageUsage{Status: StageSkippedPlan},
+		},
+	}
+
+	var totals NetworkOrgStageBreakdown
+	var want ExtractionStageUsage
+	for _, run := range runs {
+		total := run.Total()
+		for _, stage := range []ExtractionStageUsage{run.Evaluation, run.Competitors, run.Citations} {
+			total.InputTokens -= stage.InputTokens
+			total.OutputTokens -= stage.OutputTokens
+			total.Cost -= stage.Cost
+			total.DurationMs -= stage.DurationMs
+		}
+		if total.InputTokens != 0 || total.OutputTokens != 0 || math.Abs(total.Cost) > 1e-12 || total.DurationMs != 0 {
+			t.Errorf("Total() of %+v differs from the sum of its stages by %+v", run, total)
+		}
+
+		totals.Add(run)
+		want.add(run.Total())
+	}
+
+	got := totals.Total()
+	if got.InputTokens != want.InputTokens || got.OutputTokens != want.OutputTokens || math.Abs(got.Cost-want.Cost) > 1e-12 || got.DurationMs != want.DurationMs {
+		t.Errorf("Total() of the summed runs = %+v, want the sum of the run totals %+v", got, want)
+	}
+	if totals.Evaluation.InputTokens != 1200 || totals.Competitors.InputTokens != 1300 || totals.Competitors.OutputTokens != 60 {
+		t.Errorf("summed stages = %+v, want per-stage sums", totals)
+	}
+	if totals.Evaluation.Model != "gpt-4.1" || totals.Evaluation.Status != StageOK {
+		t.Errorf("summed evaluation = %+v, want the first run's model and status", totals.Evaluation)
+	}
+	if skipped := runs[2].PlanSkipped(); strings.Join(skipped, ",") != "competitors,citations" {
+		t.Errorf("PlanSkipped() = %q, want competitors and citations", skipped)
+	}
+	if line := totals.String(); !strings.Contains(line, fmt.Sprintf("total_cost=%.6f", want.Cost)) {
+		t.Errorf("String() = %q, want total_cost=%.6f", line, want.Cost)
+	}
+}
+
+// TestExtractNetworkOrgDataTotalsAreStageSums runs the extraction against the
+// mock model and checks the result totals are the sum of the stage breakdown,
+// and each stage carries the cost of its own call.
+func TestExtractNetworkOrgDataTotalsAreStageSums(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		wantEvalCalls int
+	}{
+		{name: "mentioned", response: "Acme Bank offers the best rates, see https://acmebank.com/rates.", wantEvalCalls: 1},
+		{name: "not mentioned", response: "Globex offers the best rates, see https://globex.com/rates."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newExtractionService(t, NewConfigOrgFeatureProvider(nil))
+			result, err := s.ExtractNetworkOrgData(context.Background(), uuid.New(), uuid.New(), "Acme Bank",
+				[]string{"acmebank.com"}, "Which bank has the best rates?", tt.response, []string{"Acme Bank"})
+			if err != nil {
+				t.Fatalf("ExtractNetworkOrgData() error = %v", err)
+			}
+
+			total := result.Stages.Total()
+			if result.InputTokens != total.InputTokens || result.OutputTokens != total.OutputTokens || result.TotalCost != total.Cost {
+				t.Errorf("result totals in=%d out=%d cost=%f, want the stage sums in=%d out=%d cost=%f",
+					result.InputTokens, result.OutputTokens, result.TotalCost, total.InputTokens, total.OutputTokens, total.Cost)
+			}
+
+			wantStage := func(stage string, got ExtractionStageUsage, schema, model string) {
+				t.Helper()
+				usage := extractionUsage[schema]
+				wantCost := s.costService.CalculateCostCached("openai", model, usage[0], 0, usage[1], false).TotalCost
+				if got.Status != StageOK || got.Model != model || got.InputTokens != usage[0] || got.OutputTokens != usage[1] || got.Cost != wantCost {
+					t.Errorf("%s stage = %+v, want %s with in=%d out=%d cost=%f", stage, got, model, usage[0], usage[1], wantCost)
+				}
+			}
+			if tt.wantEvalCalls > 0 {
+				wantStage("evaluation", result.Stages.Evaluation, "network_org_evaluation_extraction", "gpt-4.1")
+			} else if result.Stages.Evaluation != (ExtractionStageUsage{}) {
+				t.Errorf("evaluation stage = %+v, want no usage without a mention", result.Stages.Evaluation)
+			}
+			wantStage("competitors", result.Stages.Competitors, "network_org_competitor_extraction", "gpt-4.1-mini")
+			if c := result.Stages.Citations; c.Status != StageOK || c.InputTokens != 0 || c.OutputTokens != 0 || c.Cost != 0 {
+				t.Errorf("citations stage = %+v, want ok with no AI usage", c)
+			}
+			if got := mock.count("network_org_evaluation_extraction"); got != tt.wantEvalCalls {
+				t.Errorf("evaluation called %d times, want %d", got, tt.wantEvalCalls)
+			}
+		})
+	}
+}
+
+func TestNetworkOrgStageUsageSave(t *testing.T) {
+	questionRunID, orgID := uuid.New(), uuid.New()
+	stages := NetworkOrgStageBreakdown{
+		Evaluation:  ExtractionStageUsage{Status: StageOK, Model: "gpt-4.1", InputTokens: 1200, OutputTokens: 300, Cost: 0.0048, DurationMs: 900},
+		Competitors: ExtractionStageUsage{Status: StageSkippedPlan},
+	}
+	rec := &sqlRecorder{Exec: func(query string, args []any) (int64, error) { return 1, nil }}
+	repo := NewNetworkOrgStageUsageRepo(newRecordingRepos(t, rec).db)
+
+	if err := repo.Save(context.Background(), questionRunID, orgID, stages); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	want := [][]any{
+		{questionRunID, orgID, "evaluation", "ok", "gpt-4.1", 1200, 300, 0.0048, int64(900)},
+		{questionRunID, orgID, "competitors", "skipped_plan", "", 0, 0, 0.0, int64(0)},
+		{questionRunID, orgID, "citations", "ok", "", 0, 0, 0.0, int64(0)},
+	}
+	statements := rec.Statements()
+	if len(statements) != len(want) {
+		t.Fatalf("%d statements, want one per stage: %q", len(statements), rec.Queries())
+	}
+	for i, statement := range statements {
+		if !strings.Contains(statement.Query, "INSERT INTO network_org_stage_usage") {
+			t.Errorf("statement %d = %q, want an insert into network_org_stage_usage", i, statement.Query)
+		}
+		if fmt.Sprint(statement.Args) != fmt.Sprint(want[i]) {
+			t.Errorf("statement %d args = %v, want %v", i, statement.Args, want[i])
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// services/network_run_reuse.go
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Network run reuse: many org questions of network member orgs are the same
+// questions the network already ran that day. With NETWORK_RUN_REUSE (or a
+// per-org NETWORK_RUN_REUSE_ORGS entry) the org evaluation pipeline looks for
+// today's network run of a question with the same normalized text, model and
+// location before executing an org question and, when there is one, stores its
+// response as the org's run instead of calling the provider. The org-specific
+// extraction still runs on it, so claims and citations are extracted from the
+// copied text as for any run (providers' own citation lists are not stored
+// per run). The reused run costs nothing, inherits the source's web search
+// and truncation markers, and is linked to its source here:
+//
+//	migrations/000014_question_run_reuse.up.sql
+
+// networkQuestionIndexTTL bounds how long an org's network and the network's
+// question texts are cached between questions of the same batch.
+const networkQuestionIndexTTL = 10 * time.Minute
+
+// QuestionRunReuseRepository links question runs to the run whose response they reused.
+type QuestionRunReuseRepository interface {
+	// Record links the runs and copies the source run's web search and
+	// truncation markers onto the reusing run.
+	Record(ctx context.Context, questionRunID, sourceQuestionRunID uuid.UUID) error
+	// ReusedFromRunID returns the source run, or nil when the run made its own provider call.
+	ReusedFromRunID(ctx context.Context, questionRunID uuid.UUID) (*uuid.UUID, error)
+}
+
+type questionRunReuseRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunReuseRepo(db *database.Client) QuestionRunReuseRepository {
+	return &questionRunReuseRepo{db: db}
+}
+
+func (r *questionRunReuseRepo) Record(ctx context.Context, questionRunID, sourceQuestionRunID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback on error
+
+	queries := []string{
+		`INSERT INTO question_run_reuse (question_run_id, source_question_run_id)
+		VALUES ($1, $2)
+		ON CONFLICT (question_run_id) DO UPDATE SET source_question_run_id = EXCLUDED.source_question_run_id`,
+		// The provider call's markers describe the reused response too
+		`INSERT INTO question_run_web_search (question_run_id, web_search_used)
+		SELECT $1, web_search_used FROM question_run_web_search WHERE question_run_id = $2
+		ON CONFLICT (question_run_id) DO UPDATE SET web_search_used = EXCLUDED.web_search_used`,
+		`INSERT INTO question_run_truncations (question_run_id, output_tokens)
+		SELECT $1, output_tokens FROM question_run_truncations WHERE question_run_id = $2
+		ON CONFLICT (question_run_id) DO UPDATE SET output_tokens = EXCLUDED.output_tokens`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, questionRunID, sourceQuestionRunID); err != nil {
+			return fmt.Errorf("failed to record question run reuse: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *questionRunReuseRepo) ReusedFromRunID(ctx context.Context, questionRunID uuid.UUID) (*uuid.UUID, error) {
+	var source uuid.UUID
+	err := r.db.GetContext(ctx, &source, `
+		SELECT source_question_run_id FROM question_run_reuse WHERE question_run_id = $1`, questionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question run reuse: %w", err)
+	}
+	return &source, nil
+}
+
+// recordQuestionRunReuse links a reused run to its source; failures are
+// logged, not fatal.
+func recordQuestionRunReuse(ctx context.Context, repos *RepositoryManager, questionRunID, sourceQuestionRunID uuid.UUID) {
+	if repos.QuestionRunReuseRepo == nil {
+		return
+	}
+	if err := repos.QuestionRunReuseRepo.Record(ctx, questionRunID, sourceQuestionRunID); err != nil {
+		fmt.Printf("[recordQuestionRunReuse] Warning: %v\n", err)
+	}
+}
+
+// NetworkRunReuseEnabled reports whether the org may reuse network runs: its
+// NETWORK_RUN_REUSE_ORGS entry if it has one, NETWORK_RUN_REUSE otherwise.
+func NetworkRunReuseEnabled(cfg *config.Config, orgID uuid.UUID) bool {
+	if cfg == nil {
+		return false
+	}
+	switch cfg.NetworkRunReuseOrgs[strings.ToLower(orgID.String())] {
+	case "on", "true":
+		return true
+	case "off", "false":
+		return false
+	}
+	return cfg.NetworkRunReuse
+}
+
+// networkQuestionIndex caches each org's network and, per network, the IDs
+// of its questions by normalized question text.
+type networkQuestionIndex struct {
+	mu       sync.Mutex
+	networks map[uuid.UUID]orgNetworkEntry
+	entries  map[uuid.UUID]networkQuestionIndexEntry
+}
+
+type orgNetworkEntry struct {
+	networkID uuid.UUID // uuid.Nil when the org is in no network
+	expiresAt time.Time
+}
+
+type networkQuestionIndexEntry struct {
+	byText    map[string][]uuid.UUID
+	expiresAt time.Time
+}
+
+// networkOf returns the org's network, or uuid.Nil when it has none.
+func (idx *networkQuestionIndex) networkOf(ctx context.Context, repos *RepositoryManager, orgID uuid.UUID) (uuid.UUID, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if entry, ok := idx.networks[orgID]; ok && time.Now().Before(entry.expiresAt) {
+		return entry.networkID, nil
+	}
+	org, err := repos.OrgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get org %s: %w", orgID, err)
+	}
+	networkID := uuid.Nil
+	if org != nil {
+		networkID = org.NetworkID
+	}
+	if idx.networks == nil {
+		idx.networks = make(map[uuid.UUID]orgNetworkEntry)
+	}
+	idx.networks[orgID] = orgNetworkEntry{networkID: networkID, expiresAt: time.Now().Add(networkQuestionIndexTTL)}
+	return networkID, nil
+}
+
+func (idx *networkQuestionIndex) questionIDs(ctx context.Context, repos *RepositoryManager, networkID uuid.UUID, questionText string) ([]uuid.UUID, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	entry, ok := idx.entries[networkID]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		questions, err := repos.GeoQuestionRepo.GetByNetwork(ctx, networkID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get network questions: %w", err)
+		}
+		entry = networkQuestionIndexEntry{byText: make(map[string][]uuid.UUID), expiresAt: time.Now().Add(networkQuestionIndexTTL)}
+		for _, q := range questions {
+			key := strings.ToLower(normalizeQuestionText(q.QuestionText))
+			entry.byText[key] = append(entry.byText[key], q.GeoQuestionID)
+		}
+		if idx.entries == nil {
+			idx.entries = make(map[uuid.UUID]networkQuestionIndexEntry)
+		}
+		idx.entries[networkID] = entry
+	}
+	return entry.byText[strings.ToLower(normalizeQuestionText(questionText))], nil
+}
+
+// reuseTarget is the org question execution a network run must match.
+type reuseTarget struct {
+	QuestionText string
+	Model        string
+	Country      string
+	Region       string
+}
+
+// matchesReuseTarget reports whether run answered the target's model and
+// location with a non-empty response created at or after since.
+func matchesReuseTarget(run *models.QuestionRun, target reuseTarget, since time.Time) bool {
+	if run == nil || run.CreatedAt.Before(since) || run.ResponseText == nil || strings.TrimSpace(*run.ResponseText) == "" {
+		return false
+	}
+	return strings.EqualFold(stringOrEmpty(run.RunModel), strings.TrimSpace(target.Model)) &&
+		strings.EqualFold(stringOrEmpty(run.RunCountry), strings.TrimSpace(target.Country)) &&
+		strings.EqualFold(stringOrEmpty(run.RunRegion), strings.TrimSpace(target.Region))
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+// findReusableNetworkRun returns the newest network run from the network's
+// current batch day matching the target, or nil when the org has reuse off,
+// is in no network or nothing matches.
+func (s *orgEvaluationService) findReusableNetworkRun(ctx context.Context, orgID uuid.UUID, target reuseTarget) (*models.QuestionRun, error) {
+	if !NetworkRunReuseEnabled(s.cfg, orgID) {
+		return nil, nil
+	}
+	networkID, err := s.networkQuestions.networkOf(ctx, s.repos, orgID)
+	if err != nil || networkID == uuid.Nil {
+		return nil, err
+	}
+
+	questionIDs, err := s.networkQuestions.questionIDs(ctx, s.repos, networkID, target.QuestionText)
+	if err != nil {
+		return nil, err
+	}
+	since := BatchDayStart(s.cfg, networkID, time.Now())
+	var newest *models.QuestionRun
+	for _, questionID := range questionIDs {
+		runs, err := s.repos.QuestionRunRepo.GetByQuestion(ctx, questionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get runs for network question %s: %w", questionID, err)
+		}
+		for _, run := range runs {
+			if matchesReuseTarget(run, target, since) && (newest == nil || run.CreatedAt.After(newest.CreatedAt)) {
+				newest = run
+			}
+		}
+	}
+	return newest, nil
+}
+
+// pairReuseTarget is the reuse target of a question executed for a model-location pair.
+func pairReuseTarget(question *models.GeoQuestion, pair ModelLocationPair) reuseTarget {
+	return reuseTarget{
+		QuestionText: question.QuestionText,
+		Model:        pair.Model.Name,
+		Country:      pair.Location.CountryCode,
+		Region:       stringOrEmpty(pair.Location.RegionName),
+	}
+}
+
+// lookupReusableNetworkRun is findReusableNetworkRun for the execution paths:
+// a failed lookup is logged and treated as no match, so the provider is called.
+func (s *orgEvaluationService) lookupReusableNetworkRun(ctx context.Context, orgID uuid.UUID, target reuseTarget, logTag string) *models.QuestionRun {
+	source, err := s.findReusableNetworkRun(ctx, orgID, target)
+	if err != nil {
+		fmt.Printf("[%s] Warning: network run reuse lookup failed, calling the provider: %v\n", logTag, err)
+		return nil
+	}
+	if source != nil {
+		fmt.Printf("[%s] ♻️ Reusing network run %s for model %s, location %s (no provider call)\n", logTag, source.QuestionRunID, target.Model, target.Country)
+	}
+	return source
+}
+
+// reusedAIResponse presents a stored network run's response as the result of
+// a provider call that cost nothing. The source's web search and truncation
+// markers are copied when the reuse is recorded (see
+// QuestionRunReuseRepository.Record).
+func reusedAIResponse(source *models.QuestionRun) *AIResponse {
+	return &AIResponse{
+		Response:                *source.ResponseText,
+		ShouldProcessEvaluation: true,
+	}
+}
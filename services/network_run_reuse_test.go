@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+)
+
+func TestMatchesReuseTarget(t *testing.T) {
+	since := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	target := reuseTarget{QuestionText: "best bank?", Model: "gpt-4.1", Country: "US", Region: ""}
+	run := func(created time.Time, response, model, country string, region *string) *models.QuestionRun {
+		return &models.QuestionRun{
+			CreatedAt:    created,
+			ResponseText: &response,
+			RunModel:     &model,
+			RunCountry:   &country,
+			RunRegion:    region,
+		}
+	}
+	california := "California"
+
+	tests := []struct {
+		name   string
+		run    *models.QuestionRun
+		target reuseTarget
+		want   bool
+	}{
+		{name: "same model and location today", run: run(since.Add(time.Hour), "Acme", "gpt-4.1", "US", nil), target: target, want: true},
+		{name: "created exactly at the batch day start", run: run(since, "Acme", "gpt-4.1", "US", nil), target: target, want: true},
+		{name: "case and whitespace insensitive", run: run(since, "Acme", " GPT-4.1 ", "us", nil), target: target, want: true},
+		{name: "before the batch day", run: run(since.Add(-time.Second), "Acme", "gpt-4.1", "US", nil), target: target, want: false},
+		{name: "blank response", run: run(since, "  ", "gpt-4.1", "US", nil), target: target, want: false},
+		{name: "nil response", run: &models.QuestionRun{CreatedAt: since}, target: target, want: false},
+		{name: "other model", run: run(since, "Acme", "gemini-2.5-flash", "US", nil), target: target, want: false},
+		{name: "other country", run: run(since, "Acme", "gpt-4.1", "GB", nil), target: target, want: false},
+		{name: "run has a region the target lacks", run: run(since, "Acme", "gpt-4.1", "US", &california), target: target, want: false},
+		{
+			name:   "same region",
+			run:    run(since, "Acme", "gpt-4.1", "US", &california),
+			target: reuseTarget{Model: "gpt-4.1", Country: "US", Region: "california"},
+			want:   true,
+		},
+		{name: "nil run", run: nil, target: target, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesReuseTarget(tt.run, tt.target, since); got != tt.want {
+				t.Errorf("matchesReuseTarget() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
 	"github.com/AI-Template-SDK/senso-workflows/internal/models"
@@ -74,6 +76,8 @@ type WebSearchRequest struct {
 	Model string          `json:"model"`
 	Tools []WebSearchTool `json:"tools"`
 	Input string          `json:"input"`
+	// MaxOutputTokens is omitted (API default) unless the providers config sets one
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
 }
 
 type WebSearchTool struct {
@@ -90,11 +94,22 @@ type WebUserLocation struct {
 
 // WebSearchResponse represents the response from OpenAI web search API
 type WebSearchResponse struct {
-	ID     string                `json:"id"`
-	Object string                `json:"object"`
-	Status string                `json:"status"`
-	Output []WebSearchOutputItem `json:"output"`
-	Usage  WebSearchUsage        `json:"usage"`
+	ID                string                      `json:"id"`
+	Object            string                      `json:"object"`
+	Status            string                      `json:"status"`
+	Output            []WebSearchOutputItem       `json:"output"`
+	Usage             WebSearchUsage              `json:"usage"`
+	Error             *WebSearchError             `json:"error,omitempty"`
+	IncompleteDetails *WebSearchIncompleteDetails `json:"incomplete_details,omitempty"`
+}
+
+type WebSearchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type WebSearchIncompleteDetails struct {
+	Reason string `json:"reason"`
 }
 
 type WebSearchOutputItem struct {
@@ -126,7 +141,10 @@ type WebSearchAction struct {
 }
 
 type WebSearchUsage struct {
-	InputTokens  int `json:"input_tokens"`
+	InputTokens        int `json:"input_tokens"`
+	InputTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
 	OutputTokens int `json:"output_tokens"`
 	TotalTokens  int `json:"total_tokens"`
 }
@@ -145,13 +163,20 @@ func GenerateQuestionSchema[T any]() interface{} {
 }
 
 // RunQuestion implements AIProvider using web search when enabled
-func (p *openAIProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *models.Location) (*AIResponse, error) {
+func (p *openAIProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *models.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	// Build location-aware prompt
 	prompt := p.buildLocationPrompt(query, location)
 
 	// Use web search API when websearch is enabled
 	if websearch {
-		return p.runWebSearch(ctx, prompt, location)
+		result, err := p.runWebSearch(ctx, prompt, location)
+		if err != nil {
+			return nil, err
+		}
+		return p.checkMinResponseChars(result), nil
 	}
 
 	// Use structured output for non-websearch queries via SDK
@@ -172,6 +197,7 @@ func (p *openAIProvider) RunQuestion(ctx context.Context, query string, websearc
 		modelParam = openai.ChatModel(p.model)
 	}
 
+	maxTokens := maxOutputTokens(p.model, 2000)
 	response, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.SystemMessage("You are a helpful assistant that provides accurate, comprehensive answers to questions."),
@@ -182,7 +208,7 @@ func (p *openAIProvider) RunQuestion(ctx context.Context, query string, websearc
 			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
 		},
 		Temperature: openai.Float(0.7),
-		MaxTokens:   openai.Int(2000),
+		MaxTokens:   openai.Int(int64(maxTokens)),
 	})
 
 	if err != nil {
@@ -209,15 +235,37 @@ func (p *openAIProvider) RunQuestion(ctx context.Context, query string, websearc
 		}
 	}
 
+	cachedTokens := int(response.Usage.PromptTokensDetails.CachedTokens)
 	result := &AIResponse{
 		Response:                responseContent,
 		InputTokens:             int(response.Usage.PromptTokens),
+		CachedInputTokens:       cachedTokens,
 		OutputTokens:            int(response.Usage.CompletionTokens),
-		Cost:                    p.costService.CalculateCost(p.GetProviderName(), p.model, int(response.Usage.PromptTokens), int(response.Usage.CompletionTokens), false),
+		Cost:                    p.costService.CalculateCostCached(p.GetProviderName(), p.model, int(response.Usage.PromptTokens), cachedTokens, int(response.Usage.CompletionTokens), false).TotalCost,
 		ShouldProcessEvaluation: true,
 	}
+	if response.Choices[0].FinishReason == "length" {
+		markLengthTruncated(result, "OpenAIProvider", p.model, maxTokens)
+	}
 
-	return result, nil
+	return p.checkMinResponseChars(result), nil
+}
+
+// checkMinResponseChars flags responses below cfg.OpenAIMinResponseChars as
+// non-processable so callers skip them rather than extracting from a refusal.
+func (p *openAIProvider) checkMinResponseChars(result *AIResponse) *AIResponse {
+	if p.cfg == nil || p.cfg.OpenAIMinResponseChars <= 0 {
+		return result
+	}
+	length := len([]rune(strings.TrimSpace(result.Response)))
+	if length >= p.cfg.OpenAIMinResponseChars {
+		return result
+	}
+
+	result.SkipReason = fmt.Sprintf("response too short (%d chars, minimum %d)", length, p.cfg.OpenAIMinResponseChars)
+	result.markFailed(ErrorCodeUnknown, result.SkipReason)
+	fmt.Printf("[OpenAIProvider] ⚠️ %s: %q\n", result.SkipReason, strings.TrimSpace(result.Response))
+	return result
 }
 
 // runWebSearch uses OpenAI's web search API directly
@@ -230,18 +278,6 @@ func (p *openAIProvider) runWebSearch(ctx context.Context, query string, locatio
 		return nil, fmt.Errorf("location is required for web search")
 	}
 
-	// Convert our location to web search format
-	userLocation := WebUserLocation{
-		Type:    "approximate",
-		Country: strings.ToUpper(location.Country), // API expects uppercase country codes
-	}
-	if location.Region != nil && *location.Region != "" {
-		userLocation.Region = location.Region
-	}
-	if location.City != nil && *location.City != "" {
-		userLocation.City = location.City
-	}
-
 	// Prepare the request
 	// For Azure OpenAI, the 'model' field should be your deployment name. We prefer the configured
 	// deployment name, but allow overriding via the provider's configured model (e.g. --api-model).
@@ -253,16 +289,8 @@ func (p *openAIProvider) runWebSearch(ctx context.Context, query string, locatio
 		return nil, fmt.Errorf("azure openai deployment name is empty (AZURE_OPENAI_DEPLOYMENT_NAME)")
 	}
 
-	requestBody := WebSearchRequest{
-		Model: modelName,
-		Tools: []WebSearchTool{
-			{
-				Type:         "web_search_preview",
-				UserLocation: userLocation,
-			},
-		},
-		Input: query,
-	}
+	requestBody := buildWebSearchRequest(modelName, query, location)
+	requestBody.MaxOutputTokens = maxOutputTokens(p.model, 0)
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
@@ -296,44 +324,139 @@ func (p *openAIProvider) runWebSearch(ctx context.Context, query string, locatio
 		if len(bodyStr) > 2000 {
 			bodyStr = bodyStr[:2000] + "...(truncated)"
 		}
-		return nil, fmt.Errorf("web search API returned status %d: %s", resp.StatusCode, bodyStr)
+		return nil, newHTTPStatusError("OpenAI web search", resp.StatusCode, bodyStr)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web search response: %w", err)
+	}
+
+	webSearchResp, responseText, err := parseWebSearchResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := webSearchResp.Usage
+	result := &AIResponse{
+		Response:                responseText,
+		InputTokens:             usage.InputTokens,
+		CachedInputTokens:       usage.InputTokensDetails.CachedTokens,
+		OutputTokens:            usage.OutputTokens,
+		Cost:                    p.costService.CalculateCostCached(p.GetProviderName(), modelName, usage.InputTokens, usage.InputTokensDetails.CachedTokens, usage.OutputTokens, true).TotalCost,
+		Citations:               webSearchCitations(webSearchResp),
+		ShouldProcessEvaluation: true,
+	}
+	if hitOutputLimit(webSearchResp) {
+		markLengthTruncated(result, "OpenAIProvider", p.model, requestBody.MaxOutputTokens)
+	}
+
+	return result, nil
+}
+
+// buildWebSearchRequest builds the Responses API body: the web search tool with an
+// approximate user_location derived from location (uppercase country, optional
+// region/city).
+func buildWebSearchRequest(modelName, query string, location *models.Location) WebSearchRequest {
+	userLocation := WebUserLocation{
+		Type:    "approximate",
+		Country: strings.ToUpper(strings.TrimSpace(location.Country)), // API expects uppercase country codes
+	}
+	if location.Region != nil && strings.TrimSpace(*location.Region) != "" {
+		region := strings.TrimSpace(*location.Region)
+		userLocation.Region = &region
+	}
+	if location.City != nil && strings.TrimSpace(*location.City) != "" {
+		city := strings.TrimSpace(*location.City)
+		userLocation.City = &city
 	}
 
-	// Parse the response
+	return WebSearchRequest{
+		Model: modelName,
+		Tools: []WebSearchTool{
+			{
+				Type:         "web_search_preview",
+				UserLocation: userLocation,
+			},
+		},
+		Input: query,
+	}
+}
+
+// parseWebSearchResponse decodes a Responses API body and joins the output_text
+// parts of every message item. Failed or empty responses are returned as errors;
+// an incomplete response (e.g. max_output_tokens) keeps whatever text was produced.
+func parseWebSearchResponse(body []byte) (*WebSearchResponse, string, error) {
 	var webSearchResp WebSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&webSearchResp); err != nil {
-		return nil, fmt.Errorf("failed to decode web search response: %w", err)
+	if err := json.Unmarshal(body, &webSearchResp); err != nil {
+		return nil, "", fmt.Errorf("failed to decode web search response: %w", err)
 	}
 
-	// Extract the final message content from the response
-	responseText := ""
+	if webSearchResp.Status == "failed" || webSearchResp.Error != nil {
+		msg := "unknown error"
+		if webSearchResp.Error != nil {
+			msg = fmt.Sprintf("%s: %s", webSearchResp.Error.Code, webSearchResp.Error.Message)
+		}
+		return nil, "", fmt.Errorf("web search response failed: %s", msg)
+	}
+
+	var parts []string
 	for _, output := range webSearchResp.Output {
-		if output.Type == "message" && len(output.Content) > 0 {
-			for _, content := range output.Content {
-				if content.Type == "output_text" {
-					responseText = content.Text
-					break
-				}
-			}
-			if responseText != "" {
-				break
+		if output.Type != "message" {
+			continue
+		}
+		for _, content := range output.Content {
+			if content.Type == "output_text" && strings.TrimSpace(content.Text) != "" {
+				parts = append(parts, content.Text)
 			}
 		}
 	}
+	responseText := strings.Join(parts, "\n\n")
 
 	if responseText == "" {
-		return nil, fmt.Errorf("no message content found in web search response")
+		if webSearchResp.Status == "incomplete" && webSearchResp.IncompleteDetails != nil {
+			return nil, "", fmt.Errorf("web search response incomplete (%s) with no message content", webSearchResp.IncompleteDetails.Reason)
+		}
+		return nil, "", fmt.Errorf("no message content found in web search response")
 	}
 
-	result := &AIResponse{
-		Response:                responseText,
-		InputTokens:             webSearchResp.Usage.InputTokens,
-		OutputTokens:            webSearchResp.Usage.OutputTokens,
-		Cost:                    p.costService.CalculateCost(p.GetProviderName(), modelName, webSearchResp.Usage.InputTokens, webSearchResp.Usage.OutputTokens, true),
-		ShouldProcessEvaluation: true,
+	if webSearchResp.Status == "incomplete" {
+		reason := ""
+		if webSearchResp.IncompleteDetails != nil {
+			reason = webSearchResp.IncompleteDetails.Reason
+		}
+		fmt.Printf("[parseWebSearchResponse] ⚠️ Response %s incomplete (%s), using partial text\n", webSearchResp.ID, reason)
 	}
 
-	return result, nil
+	return &webSearchResp, responseText, nil
+}
+
+// webSearchCitations returns the URLs of the url_citation annotations on the
+// message text, in order of first appearance.
+func webSearchCitations(resp *WebSearchResponse) []string {
+	citations := []string{}
+	seen := make(map[string]bool)
+	for _, output := range resp.Output {
+		if output.Type != "message" {
+			continue
+		}
+		for _, content := range output.Content {
+			for _, annotation := range content.Annotations {
+				url := strings.TrimSpace(annotation.URL)
+				if annotation.Type != "url_citation" || url == "" || seen[url] {
+					continue
+				}
+				seen[url] = true
+				citations = append(citations, url)
+			}
+		}
+	}
+	return citations
+}
+
+// hitOutputLimit reports whether a Responses API answer was cut at max_output_tokens.
+func hitOutputLimit(resp *WebSearchResponse) bool {
+	return resp.Status == "incomplete" && resp.IncompleteDetails != nil && resp.IncompleteDetails.Reason == "max_output_tokens"
 }
 
 func (p *openAIProvider) buildLocationPrompt(query string, location *models.Location) string {
@@ -370,7 +493,9 @@ func (p *openAIProvider) formatLocation(location *models.Location) string {
 }
 
 // RunQuestionWebSearch implements AIProvider for web search without location
-func (p *openAIProvider) RunQuestionWebSearch(ctx context.Context, query string) (*AIResponse, error) {
+func (p *openAIProvider) RunQuestionWebSearch(ctx context.Context, query string) (out *AIResponse, err error) {
+	defer sanitizeResponse(&out)
+
 	fmt.Printf("[RunQuestionWebSearch] 🚀 Making web search AI call for query: %s", query)
 
 	// Create a neutral location for the API call (required for web search)
@@ -387,13 +512,24 @@ func (p *openAIProvider) SupportsBatching() bool {
 	return false
 }
 
+// SupportsWebSearch returns true for OpenAI (Responses API web search tool)
+func (p *openAIProvider) SupportsWebSearch() bool {
+	return true
+}
+
 // GetMaxBatchSize returns 1 for OpenAI (no batching)
 func (p *openAIProvider) GetMaxBatchSize() int {
 	return 1
 }
 
+// RunQuestionBatchAwait calls RunQuestionBatch: OpenAI answers synchronously
+func (p *openAIProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *models.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatch(ctx, batch, websearch, location)
+}
+
 // RunQuestionBatch processes questions sequentially for OpenAI (no batching support)
-func (p *openAIProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *models.Location) ([]*AIResponse, error) {
+func (p *openAIProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *models.Location) ([]*AIResponse, error) {
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[OpenAIProvider] 🔄 Processing %d questions sequentially (no batching support)\n", len(queries))
 
 	responses := make([]*AIResponse, len(queries))
@@ -405,5 +541,6 @@ func (p *openAIProvider) RunQuestionBatch(ctx context.Context, queries []string,
 		responses[i] = response
 	}
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+func TestBuildWebSearchRequest(t *testing.T) {
+	region, city, blank := " California ", "San Francisco", "  "
+	tests := []struct {
+		name     string
+		location *models.Location
+		want     WebUserLocation
+	}{
+		{
+			name:     "country only",
+			location: &models.Location{Country: " us "},
+			want:     WebUserLocation{Type: "approximate", Country: "US"},
+		},
+		{
+			name:     "region and city",
+			location: &models.Location{Country: "us", Region: &region, City: &city},
+			want:     WebUserLocation{Type: "approximate", Country: "US", Region: strPtr("California"), City: strPtr("San Francisco")},
+		},
+		{
+			name:     "blank region dropped",
+			location: &models.Location{Country: "gb", Region: &blank},
+			want:     WebUserLocation{Type: "approximate", Country: "GB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := buildWebSearchRequest("my-deployment", "best bank?", tt.location)
+			if req.Model != "my-deployment" || req.Input != "best bank?" {
+				t.Errorf("model/input = %q/%q", req.Model, req.Input)
+			}
+			if len(req.Tools) != 1 || req.Tools[0].Type != "web_search_preview" {
+				t.Fatalf("tools = %+v, want one web_search_preview tool", req.Tools)
+			}
+			if got := req.Tools[0].UserLocation; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("user_location = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+// TestOpenAIWebSearch drives RunQuestion with web search against a mocked
+// Azure Responses API endpoint.
+func TestOpenAIWebSearch(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantText      string
+		wantCitations []string
+		wantInput     int
+		wantTruncated bool
+		wantErr       string
+		wantCode      ErrorCode
+	}{
+		{
+			name:   "text with citations",
+			status: http.StatusOK,
+			body: `{
+				"id": "resp_1", "status": "completed",
+				"output": [
+					{"type": "web_search_call", "status": "completed"},
+					{"type": "message", "content": [
+						{"type": "output_text", "text": "Acme Bank is popular.", "annotations": [
+							{"type": "url_citation", "url": "https://acme.example/rates", "title": "Rates"},
+							{"type": "url_citation", "url": "https://news.example/acme"}
+						]},
+						{"type": "output_text", "text": "Its rates are low.", "annotations": [
+							{"type": "url_citation", "url": "https://acme.example/rates"},
+							{"type": "file_citation", "url": "https://ignored.example"}
+						]}
+					]}
+				],
+				"usage": {"input_tokens": 1200, "output_tokens": 300, "input_tokens_details": {"cached_tokens": 200}}
+			}`,
+			wantText:      "Acme Bank is popular.\n\nIts rates are low.",
+			wantCitations: []string{"https://acme.example/rates", "https://news.example/acme"},
+			wantInput:     1200,
+		},
+		{
+			name:   "cut at output limit keeps partial text",
+			status: http.StatusOK,
+			body: `{"status": "incomplete", "incomplete_details": {"reason": "max_output_tokens"},
+				"output": [{"type": "message", "content": [{"type": "output_text", "text": "Partial answer"}]}],
+				"usage": {"input_tokens": 10, "output_tokens": 5}}`,
+			wantText:      "Partial answer",
+			wantCitations: []string{},
+			wantInput:     10,
+			wantTruncated: true,
+		},
+		{
+			name:    "empty output",
+			status:  http.StatusOK,
+			body:    `{"status": "completed", "output": [], "usage": {"input_tokens": 10}}`,
+			wantErr: "no message content",
+		},
+		{
+			name:    "failed response",
+			status:  http.StatusOK,
+			body:    `{"status": "failed", "error": {"code": "server_error", "message": "boom"}}`,
+			wantErr: "server_error: boom",
+		},
+		{
+			name:     "rate limited",
+			status:   http.StatusTooManyRequests,
+			body:     `{"error": {"message": "slow down"}}`,
+			wantErr:  "status 429",
+			wantCode: ErrorCodeRateLimited,
+		},
+		{
+			name:     "server error",
+			status:   http.StatusBadGateway,
+			body:     `upstream unavailable`,
+			wantErr:  "status 502",
+			wantCode: ErrorCodeProviderOutage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got WebSearchRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/openai/v1/responses" {
+					t.Errorf("path = %s, want /openai/v1/responses", r.URL.Path)
+				}
+				if r.Header.Get("api-key") != "test-key" {
+					t.Errorf("api-key header = %q", r.Header.Get("api-key"))
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := &openAIProvider{
+				model:       "gpt-4.1",
+				costService: NewCostService(nil),
+				cfg: &config.Config{
+					AzureOpenAIEndpoint:       server.URL + "/",
+					AzureOpenAIKey:            "test-key",
+					AzureOpenAIDeploymentName: "test-deployment",
+				},
+			}
+			resp, err := provider.RunQuestion(context.Background(), "Which bank is best?", true, &models.Location{Country: "us"})
+
+			if got.Model != "test-deployment" || len(got.Tools) != 1 || got.Tools[0].UserLocation.Country != "US" {
+				t.Errorf("request = %+v, want the deployment with a US web search tool", got)
+			}
+			if !strings.Contains(got.Input, "Which bank is best?") {
+				t.Errorf("request input %q does not contain the query", got.Input)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RunQuestion() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				if tt.wantCode != "" {
+					var providerErr *ProviderError
+					if !errors.As(err, &providerErr) || providerErr.Code != tt.wantCode {
+						t.Errorf("error code = %v, want %s", err, tt.wantCode)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunQuestion() error = %v", err)
+			}
+			if resp.Response != tt.wantText {
+				t.Errorf("Response = %q, want %q", resp.Response, tt.wantText)
+			}
+			if !reflect.DeepEqual(resp.Citations, tt.wantCitations) {
+				t.Errorf("Citations = %v, want %v", resp.Citations, tt.wantCitations)
+			}
+			if resp.InputTokens != tt.wantInput || resp.Cost <= 0 {
+				t.Errorf("InputTokens = %d, Cost = %f; want %d tokens and a cost", resp.InputTokens, resp.Cost, tt.wantInput)
+			}
+			if resp.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %t, want %t", resp.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestCheckMinResponseChars(t *testing.T) {
+	tests := []struct {
+		name        string
+		min         int
+		response    string
+		wantProcess bool
+	}{
+		{name: "off by default", min: 0, response: "No.", wantProcess: true},
+		{name: "long enough", min: 10, response: "Acme Bank is best.", wantProcess: true},
+		{name: "exactly the minimum", min: 4, response: "Acme", wantProcess: true},
+		{name: "below the minimum", min: 10, response: "I can't.", wantProcess: false},
+		{name: "whitespace not counted", min: 5, response: "  Acme  \n", wantProcess: false},
+		{name: "counted in runes", min: 4, response: "Café", wantProcess: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &openAIProvider{cfg: &config.Config{OpenAIMinResponseChars: tt.min}}
+			got := provider.checkMinResponseChars(&AIResponse{Response: tt.response, ShouldProcessEvaluation: true})
+			if got.ShouldProcessEvaluation != tt.wantProcess {
+				t.Fatalf("ShouldProcessEvaluation = %t, want %t", got.ShouldProcessEvaluation, tt.wantProcess)
+			}
+			if !tt.wantProcess && (got.SkipReason == "" || got.ErrorCode != ErrorCodeUnknown) {
+				t.Errorf("SkipReason = %q, ErrorCode = %q; want a reason and %s", got.SkipReason, got.ErrorCode, ErrorCodeUnknown)
+			}
+		})
+	}
+}
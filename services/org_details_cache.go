@@ -0,0 +1,75 @@
+// services/org_details_cache.go
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// orgDetailsCache keeps GetOrgDetails results for a short TTL so fixers that
+// revisit the same org (--parallel-networks, repeated passes) don't reload
+// every table each time. A nil *orgDetailsCache caches nothing.
+//
+// Callers get a shallow copy of the cached details: reassigning a field is
+// safe, but the slices are shared and must not be modified in place.
+type orgDetailsCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]orgDetailsCacheEntry
+}
+
+type orgDetailsCacheEntry struct {
+	details   *RealOrgDetails
+	expiresAt time.Time
+}
+
+// newOrgDetailsCache returns nil (no caching) when ttl <= 0.
+func newOrgDetailsCache(ttl time.Duration) *orgDetailsCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &orgDetailsCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]orgDetailsCacheEntry),
+	}
+}
+
+func (c *orgDetailsCache) get(orgID string) (*RealOrgDetails, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[orgID]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, orgID)
+		return nil, false
+	}
+	copied := *entry.details
+	return &copied, true
+}
+
+func (c *orgDetailsCache) put(orgID string, details *RealOrgDetails) {
+	if c == nil || details == nil {
+		return
+	}
+	copied := *details
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[orgID] = orgDetailsCacheEntry{details: &copied, expiresAt: c.now().Add(c.ttl)}
+}
+
+func (c *orgDetailsCache) invalidate(orgID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, orgID)
+}
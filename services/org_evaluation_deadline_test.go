@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// fakeClock returns now for the first n calls and a minute later after that.
+func fakeClock(now time.Time, n int) func() time.Time {
+	calls := 0
+	return func() time.Time {
+		calls++
+		if calls > n {
+			return now.Add(time.Minute)
+		}
+		return now
+	}
+}
+
+func TestSoftDeadlinePassed(t *testing.T) {
+	at := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		deadline softDeadline
+		want     bool
+	}{
+		{name: "zero value", deadline: softDeadline{}, want: false},
+		{name: "disabled", deadline: newSoftDeadline(0), want: false},
+		{name: "before", deadline: softDeadline{at: at, now: func() time.Time { return at.Add(-time.Second) }}, want: false},
+		{name: "at", deadline: softDeadline{at: at, now: func() time.Time { return at }}, want: false},
+		{name: "after", deadline: softDeadline{at: at, now: func() time.Time { return at.Add(time.Second) }}, want: true},
+		{name: "minutes from now", deadline: newSoftDeadline(5), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.deadline.passed(); got != tt.want {
+				t.Errorf("passed() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// runTable is an in-memory question_runs for the latest-flag updates.
+type runTable struct {
+	interfaces.QuestionRunRepository
+	rows []*models.QuestionRun
+}
+
+func (r *runTable) GetByQuestion(ctx context.Context, questionID uuid.UUID) ([]*models.QuestionRun, error) {
+	var out []*models.QuestionRun
+	for _, row := range r.rows {
+		if row.GeoQuestionID == questionID {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *runTable) Update(ctx context.Context, run *models.QuestionRun) error { return nil }
+
+// evalTable records the org evals written by the extraction phase.
+type evalTable struct {
+	interfaces.OrgEvalRepository
+	created []*models.OrgEval
+}
+
+func (e *evalTable) GetByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) ([]*models.OrgEval, error) {
+	return nil, nil
+}
+
+func (e *evalTable) Create(ctx context.Context, eval *models.OrgEval) error {
+	e.created = append(e.created, eval)
+	return nil
+}
+
+type noOrgCitations struct {
+	interfaces.OrgCitationRepository
+}
+
+func (noOrgCitations) GetByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) ([]*models.OrgCitation, error) {
+	return nil, nil
+}
+
+type noOrgCompetitors struct {
+	interfaces.OrgCompetitorRepository
+}
+
+func (noOrgCompetitors) GetByQuestionRunAndOrg(ctx context.Context, questionRunID, orgID uuid.UUID) ([]*models.OrgCompetitor, error) {
+	return nil, nil
+}
+
+// TestSoftDeadlinePartialBatch hits the deadline after K of N extractions
+// and checks that exactly the extracted runs become latest, the others keep
+// yesterday's latest runs, and the batch is marked partial with N-K remaining.
+func TestSoftDeadlinePartialBatch(t *testing.T) {
+	const total = 5
+	orgID, previousBatch := uuid.New(), uuid.New()
+	failedText := "Question run failed for this model and location"
+
+	for _, extracted := range []int{0, 1, 3, total} {
+		t.Run(fmt.Sprintf("%d of %d extracted", extracted, total), func(t *testing.T) {
+			batchID := uuid.New()
+			runs := &runTable{}
+			var newRuns []*models.QuestionRun
+			for i := 0; i < total; i++ {
+				questionID := uuid.New()
+				old := &models.QuestionRun{QuestionRunID: uuid.New(), GeoQuestionID: questionID, BatchID: &previousBatch, IsLatest: true}
+				run := &models.QuestionRun{QuestionRunID: uuid.New(), GeoQuestionID: questionID, BatchID: &batchID, ResponseText: &failedText}
+				runs.rows = append(runs.rows, old, run)
+				newRuns = append(newRuns, run)
+			}
+			evals := &evalTable{}
+			batches := &batchTable{rows: []*models.QuestionRunBatch{{BatchID: batchID, Scope: "org", OrgID: &orgID, TotalQuestions: total}}}
+			repos := &RepositoryManager{
+				QuestionRunRepo:      runs,
+				OrgEvalRepo:          evals,
+				OrgCitationRepo:      noOrgCitations{},
+				OrgCompetitorRepo:    noOrgCompetitors{},
+				QuestionRunBatchRepo: batches,
+			}
+			s := &orgEvaluationService{cfg: &config.Config{}, repos: repos}
+			at := time.Now()
+			deadline := softDeadline{at: at, now: fakeClock(at, extracted)}
+			summary := &OrgEvaluationSummary{}
+			ctx := context.Background()
+
+			// The pipeline's phase 3 and latest-flag update
+			done, err := s.processAllExtractions(ctx, newRuns, orgID, "Acme", nil, nil, batchID, deadline, summary)
+			if err != nil {
+				t.Fatalf("processAllExtractions() error = %v", err)
+			}
+			if err := s.updateLatestFlags(ctx, nil, done); err != nil {
+				t.Fatalf("updateLatestFlags() error = %v", err)
+			}
+
+			if len(done) != extracted || len(evals.created) != extracted {
+				t.Fatalf("extracted %d runs (%d evals), want %d", len(done), len(evals.created), extracted)
+			}
+			wantPartial := extracted < total
+			if summary.DeadlineReached != wantPartial || summary.RemainingQuestions != total-extracted {
+				t.Errorf("summary deadline %t remaining %d, want %t and %d", summary.DeadlineReached, summary.RemainingQuestions, wantPartial, total-extracted)
+			}
+			for i := 0; i < total; i++ {
+				old, run := runs.rows[2*i], runs.rows[2*i+1]
+				if wantNew := i < extracted; run.IsLatest != wantNew || old.IsLatest == wantNew {
+					t.Errorf("question %d: new run latest %t, previous run latest %t; want the new run latest only if extracted (%t)", i, run.IsLatest, old.IsLatest, wantNew)
+				}
+			}
+
+			if !wantPartial {
+				return
+			}
+			if err := s.PartialBatch(ctx, batchID, summary.RemainingQuestions); err != nil {
+				t.Fatalf("PartialBatch() error = %v", err)
+			}
+			if batch := batches.rows[0]; batch.Status != "partial" || batch.CompletedQuestions != extracted {
+				t.Errorf("batch status %q, %d completed; want partial with %d", batch.Status, batch.CompletedQuestions, extracted)
+			}
+		})
+	}
+}
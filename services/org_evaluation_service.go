@@ -28,6 +28,9 @@ type orgEvaluationService struct {
 	costService           CostService
 	repos                 *RepositoryManager
 	dataExtractionService DataExtractionService
+	exclusions            *CompetitorExclusions
+	// networkQuestions caches network question texts for network run reuse
+	networkQuestions *networkQuestionIndex
 }
 
 func NewOrgEvaluationService(cfg *config.Config, repos *RepositoryManager, dataExtractionService DataExtractionService) OrgEvaluationService {
@@ -59,9 +62,11 @@ func NewOrgEvaluationService(cfg *config.Config, repos *RepositoryManager, dataE
 	return &orgEvaluationService{
 		cfg:                   cfg,
 		openAIClient:          &client,
-		costService:           NewCostService(),
+		costService:           NewCostService(cfg),
 		repos:                 repos,
 		dataExtractionService: dataExtractionService,
+		exclusions:            newCompetitorExclusions(cfg),
+		networkQuestions:      &networkQuestionIndex{},
 	}
 }
 
@@ -87,14 +92,49 @@ type CitationInfo struct {
 
 // GenerateNameVariations implements the get_names() function from Python
 func (s *orgEvaluationService) GenerateNameVariations(ctx context.Context, orgName string, websites []string) ([]string, error) {
+	return s.GenerateNameVariationsWithOptions(ctx, orgName, websites, NameVariationOptions{})
+}
+
+// GenerateNameVariationsWithOptions merges the AI-generated variations with the
+// programmatic ones (which are always present) and applies the count bounds.
+func (s *orgEvaluationService) GenerateNameVariationsWithOptions(ctx context.Context, orgName string, websites []string, opts NameVariationOptions) ([]string, error) {
 	fmt.Printf("[GenerateNameVariations] 🔍 Generating name variations for org: %s\n", orgName)
 
+	prompt := buildNameVariationsPrompt(orgName, websites)
+	aiNames, err := s.requestNameVariations(ctx, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	programmatic := ProgrammaticNameVariations(orgName, websites)
+	names := boundNameVariations(programmatic, aiNames, opts.MaxCount)
+
+	if opts.MinCount > 0 && len(names) < opts.MinCount {
+		fmt.Printf("[GenerateNameVariations] ⚠️ Only %d variations (min %d), re-prompting once\n", len(names), opts.MinCount)
+		retryPrompt := prompt + fmt.Sprintf("\n\nReturn at least %d distinct realistic variations.", opts.MinCount)
+		moreNames, err := s.requestNameVariations(ctx, retryPrompt, opts)
+		if err != nil {
+			fmt.Printf("[GenerateNameVariations] Warning: re-prompt failed: %v\n", err)
+		} else {
+			names = boundNameVariations(programmatic, append(aiNames, moreNames...), opts.MaxCount)
+		}
+		if len(names) < opts.MinCount {
+			fmt.Printf("[GenerateNameVariations] Warning: still below minimum (%d < %d)\n", len(names), opts.MinCount)
+		}
+	}
+
+	fmt.Printf("[GenerateNameVariations] ✅ Generated %d name variations (%d programmatic)\n", len(names), len(programmatic))
+	return names, nil
+}
+
+// buildNameVariationsPrompt builds the get_names() prompt
+func buildNameVariationsPrompt(orgName string, websites []string) string {
 	websitesFormatted := ""
 	for _, website := range websites {
 		websitesFormatted += fmt.Sprintf("- %s\n", website)
 	}
 
-	prompt := fmt.Sprintf(`You are an expert in brand name analysis and variation generation. Your task is to generate a comprehensive list of brand name variations that a company might realistically use across different platforms, documents, and contexts.
+	return fmt.Sprintf(`You are an expert in brand name analysis and variation generation. Your task is to generate a comprehensive list of brand name variations that a company might realistically use across different platforms, documents, and contexts.
 
 Generate REALISTIC variations of this brand name that would actually be used by the company or found in business contexts. Focus on:
 
@@ -145,13 +185,16 @@ The brand name is %s
 
 Associated websites:
 %s`, "`"+orgName+"`", websitesFormatted)
+}
 
+// requestNameVariations makes a single structured-output call for name variations
+func (s *orgEvaluationService) requestNameVariations(ctx context.Context, prompt string, opts NameVariationOptions) ([]string, error) {
 	// Use configured model for name variations
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		// Use Azure with configured deployment
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		fmt.Printf("[GenerateNameVariations] 🎯 Using Azure SDK with model: %s\n", s.cfg.AzureOpenAIDeploymentName)
+		// Name variations are a small call: use the mini deployment
+		model = openai.ChatModel(azureMiniDeployment(s.cfg))
+		fmt.Printf("[GenerateNameVariations] 🎯 Using Azure OpenAI mini deployment: %s\n", model)
 	} else {
 		model = openai.ChatModel("gpt-4.1-mini")
 		fmt.Printf("[GenerateNameVariations] 🎯 Using Standard OpenAI model: gpt-4.1-mini\n")
@@ -178,15 +221,20 @@ Associated websites:
 		},
 	}
 
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.3) // Keep low for consistency in extraction when verified
-		fmt.Printf("[GenerateNameVariations] Setting temperature to 0.3 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[GenerateNameVariations] Skipping temperature setting for model gpt-5\n")
+	// Used when the model takes a temperature (see newExtractionCompletion)
+	temperature := s.cfg.NameVariationTemperature
+	if opts.Deterministic {
+		temperature = 0
+	}
+	if opts.Deterministic {
+		seed := opts.Seed
+		if seed == 0 {
+			seed = defaultNameVariationSeed
+		}
+		params.Seed = openai.Int(seed)
 	}
 
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, temperature, "GenerateNameVariations")
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate name variations: %w", err)
@@ -209,7 +257,6 @@ Associated websites:
 		return nil, fmt.Errorf("failed to parse name variations response: %w", err)
 	}
 
-	fmt.Printf("[GenerateNameVariations] ✅ Generated %d name variations", len(extractedData.Names))
 	return extractedData.Names, nil
 }
 
@@ -275,8 +322,8 @@ func (s *orgEvaluationService) ExtractOrgEvaluation(ctx context.Context, questio
 	var model openai.ChatModel
 	modelName := ""
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		model = openai.ChatModel(s.cfg.AzureOpenAIDeploymentName)
-		modelName = s.cfg.AzureOpenAIDeploymentName
+		modelName = azureFullDeployment(s.cfg)
+		model = openai.ChatModel(modelName)
 		fmt.Printf("[ExtractOrgEvaluation] 🎯 Using Azure OpenAI deployment: %s\n", modelName)
 	} else {
 		model = openai.ChatModelGPT4_1 // Fallback
@@ -307,16 +354,7 @@ func (s *orgEvaluationService) ExtractOrgEvaluation(ctx context.Context, questio
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractOrgEvaluation] Setting temperature to 0.1 for model %s\n", modelName)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractOrgEvaluation] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, "ExtractOrgEvaluation")
 
 	if err != nil {
 		// Log the raw error for debugging
@@ -346,7 +384,7 @@ func (s *orgEvaluationService) ExtractOrgEvaluation(ctx context.Context, questio
 	// Capture token and cost data
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", modelName, inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", modelName, inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	// Create the org evaluation model
 	now := time.Now()
@@ -421,14 +459,14 @@ func safeDerefString(s *string) string {
 func (s *orgEvaluationService) ExtractCompetitors(ctx context.Context, questionRunID, orgID uuid.UUID, orgName string, responseText string) (*CompetitorExtractionResult, error) {
 	fmt.Printf("[ExtractCompetitors] 🔍 Processing competitors for question run %s, org %s\n", questionRunID, orgName)
 
-	prompt := fmt.Sprintf("You are an expert in competitive analysis and brand identification. Your task is to identify ALL competitor brands, companies, products, or services mentioned in the response text that are NOT the target organization.\n\n**TARGET ORGANIZATION:** %s\n\n**COMPETITOR IDENTIFICATION RULES:**\n\n1. **What to Include:**\n   - Company names (e.g., \"Microsoft\", \"Google\", \"Apple\")\n   - Product names (e.g., \"ChatGPT\", \"Claude\", \"Gemini\", \"Perplexity\")\n   - Service names (e.g., \"Ahrefs Brand Radar\", \"Surfer SEO AI Tracker\")\n   - Platform names (e.g., \"LinkedIn\", \"Facebook\", \"Twitter\")\n   - Tool names (e.g., \"Profound\", \"Promptmonitor\", \"Writesonic GEO Platform\")\n   - Any branded entity that could be considered competition or alternative\n\n2. **What to Exclude:**\n   - The target organization itself and its variations\n   - Generic terms (e.g., \"AI tools\", \"analytics platforms\", \"search engines\")\n   - Non-competitive entities (e.g., \"users\", \"customers\", \"developers\")\n   - Technical terms or concepts (e.g., \"machine learning\", \"natural language processing\")\n   - Industry terms (e.g., \"credit unions\", \"financial services\")\n\n3. **Extraction Guidelines:**\n   - Extract the most commonly used or official name for each competitor\n   - If a company has multiple products mentioned, list each product separately\n   - Remove duplicates and variations of the same entity\n   - Focus on entities that could be considered alternatives or competitors\n   - Include both direct competitors and indirect competitors mentioned\n\n**EXAMPLES:**\n\nExample 1: \"Leading AI tools include ChatGPT, Claude, Gemini, and Senso.ai for content optimization.\"\n→ Extract: [\"ChatGPT\", \"Claude\", \"Gemini\"] (exclude Senso.ai as it's the target)\n\nExample 2: \"Microsoft's Azure competes with Google Cloud and Amazon Web Services in the enterprise market.\"\n→ Extract: [\"Microsoft\", \"Azure\", \"Google Cloud\", \"Amazon Web Services\"]\n\nExample 3: \"Popular analytics platforms like Google Analytics, Adobe Analytics, and Mixpanel offer similar features.\"\n→ Extract: [\"Google Analytics\", \"Adobe Analytics\", \"Mixpanel\"]\n\n**RESPONSE TO ANALYZE:**\n```\n%s\n```\n\n**INSTRUCTIONS:**\n- Return only the list of competitor names\n- Use the most recognizable/official name for each competitor\n- Remove any duplicates or very similar variations\n- If no competitors are mentioned, return an empty list\n- Do not include the target organization or generic terms", "`"+orgName+"`", responseText)
+	prompt := buildCompetitorExtractionPrompt(extractionVerticalByName(s.exclusions.VerticalForOrg(orgID)), orgName, responseText)
 
 	// Use gpt-4.1-mini for competitors
 	var model openai.ChatModel
 	if s.cfg.AzureOpenAIDeploymentName != "" {
-		// Use Azure with mini model
-		model = openai.ChatModel("gpt-4.1-mini")
-		fmt.Printf("[ExtractCompetitors] 🎯 Using Azure SDK with model: gpt-4.1-mini\n")
+		// Use the Azure mini deployment
+		model = openai.ChatModel(azureMiniDeployment(s.cfg))
+		fmt.Printf("[ExtractCompetitors] 🎯 Using Azure OpenAI mini deployment: %s\n", model)
 	} else {
 		model = openai.ChatModel("gpt-4.1-mini")
 		fmt.Printf("[ExtractCompetitors] 🎯 Using Standard OpenAI model: gpt-4.1-mini\n")
@@ -455,16 +493,7 @@ func (s *orgEvaluationService) ExtractCompetitors(ctx context.Context, questionR
 		},
 	}
 
-	// Conditional Temperature Setting
-	if !strings.HasPrefix(string(model), "gpt-5") {
-		params.Temperature = openai.Float(0.1) // Keep low for consistency in extraction when verified
-		fmt.Printf("[ExtractCompetitors] Setting temperature to 0.1 for model %s\n", model)
-	} else {
-		params.ReasoningEffort = "low"
-		fmt.Printf("[ExtractCompetitors] Skipping temperature setting for model gpt-5\n")
-	}
-
-	chatResponse, err := s.openAIClient.Chat.Completions.New(ctx, params)
+	chatResponse, err := newExtractionCompletion(ctx, s.cfg, s.openAIClient.Chat.Completions.New, params, s.cfg.ExtractionTemperature, "ExtractCompetitors")
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract competitors: %w", err)
@@ -477,7 +506,7 @@ func (s *orgEvaluationService) ExtractCompetitors(ctx context.Context, questionR
 	// Calculate cost
 	inputTokens := int(chatResponse.Usage.PromptTokens)
 	outputTokens := int(chatResponse.Usage.CompletionTokens)
-	totalCost := s.costService.CalculateCost("openai", string(model), inputTokens, outputTokens, false)
+	totalCost := s.costService.CalculateCostCached("openai", string(model), inputTokens, int(chatResponse.Usage.PromptTokensDetails.CachedTokens), outputTokens, false).TotalCost
 
 	// Parse the response
 	if len(chatResponse.Choices) == 0 {
@@ -496,16 +525,14 @@ func (s *orgEvaluationService) ExtractCompetitors(ctx context.Context, questionR
 	var competitors []*models.OrgCompetitor
 	now := time.Now()
 
-	for _, competitorName := range extractedData.Competitors {
-		if strings.TrimSpace(competitorName) == "" {
-			continue // Skip empty names
-		}
+	// Names() trims, dedupes and splits a comma-joined single-element list
+	for _, competitorName := range extractedData.Names(competitorListOptions(s.cfg)) {
 
 		competitor := &models.OrgCompetitor{
 			OrgCompetitorID: uuid.New(),
 			QuestionRunID:   questionRunID,
 			OrgID:           orgID,
-			Name:            strings.TrimSpace(competitorName),
+			Name:            competitorName,
 			InputTokens:     &inputTokens,
 			OutputTokens:    &outputTokens,
 			TotalCost:       &totalCost,
@@ -532,6 +559,7 @@ func (s *orgEvaluationService) ExtractCitations(ctx context.Context, questionRun
 	var citations []*models.OrgCitation
 	seenURLs := make(map[string]bool)
 	now := time.Now()
+	orgDomains := normalizeOrgWebsites(orgWebsites)
 
 	// Image extensions to skip
 	imageExtensions := []string{
@@ -596,7 +624,7 @@ func (s *orgEvaluationService) ExtractCitations(ctx context.Context, questionRun
 
 		// Determine if this is a primary or secondary citation
 		citationType := "secondary" // Default to secondary
-		if isPrimaryDomain(finalURL, orgWebsites) {
+		if isPrimaryDomain(finalURL, orgDomains) {
 			citationType = "primary"
 		}
 
@@ -736,6 +764,15 @@ func (s *orgEvaluationService) RunQuestionMatrixWithOrgEvaluation(ctx context.Co
 	summary := &OrgEvaluationSummary{
 		ProcessingErrors: make([]string, 0),
 	}
+	// Locations with an invalid country were left out when the org was loaded
+	for _, invalid := range orgDetails.InvalidLocations {
+		msg := invalid.Message()
+		summary.InvalidLocations = append(summary.InvalidLocations, msg)
+		fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ❌ %s\n", msg)
+	}
+
+	// Soft deadline: stop starting new questions and extractions once it passes
+	deadline := newSoftDeadline(s.cfg.OrgEvalSoftDeadlineMinutes)
 
 	// PHASE 1: Generate name variations ONCE for the entire org
 	fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] 🔍 Generating name variations for org: %s\n", orgDetails.Org.Name)
@@ -747,15 +784,21 @@ func (s *orgEvaluationService) RunQuestionMatrixWithOrgEvaluation(ctx context.Co
 
 	// PHASE 2: Execute all questions grouped by Model-Location pairs
 	fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] 🚀 PHASE 2: Executing questions (batched by model-location)\n")
-	allQuestionRuns, err := s.executeAllQuestions(ctx, orgDetails, batchID, summary)
+	allQuestionRuns, err := s.executeAllQuestions(ctx, orgDetails, batchID, deadline, summary)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute questions: %w", err)
 	}
 	fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ✅ Completed question execution: %d question runs created\n", len(allQuestionRuns))
+	if summary.ReusedRuns > 0 {
+		fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ♻️ %d question runs reused today's network responses\n", summary.ReusedRuns)
+	}
+	if summary.DeadlineReached {
+		fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ⏰ Soft deadline reached: %d question runs not started, finishing completed subset\n", summary.RemainingQuestions)
+	}
 
 	// PHASE 3: Process extractions for all completed question runs
 	fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] 🔍 PHASE 3: Processing extractions for %d question runs\n", len(allQuestionRuns))
-	err = s.processAllExtractions(ctx, allQuestionRuns, orgDetails.Org.OrgID, orgDetails.Org.Name, orgDetails.Websites, nameVariations, batchID, summary)
+	extractedRuns, err := s.processAllExtractions(ctx, allQuestionRuns, orgDetails.Org.OrgID, orgDetails.Org.Name, orgDetails.Websites, nameVariations, batchID, deadline, summary)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process extractions: %w", err)
 	}
@@ -763,17 +806,38 @@ func (s *orgEvaluationService) RunQuestionMatrixWithOrgEvaluation(ctx context.Co
 	fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] 🎉 Question matrix completed: %d processed, %d evaluations, %d citations, %d competitors, $%.6f total cost\n",
 		summary.TotalProcessed, summary.TotalEvaluations, summary.TotalCitations, summary.TotalCompetitors, summary.TotalCost)
 
-	// Update is_latest flags for all created question runs
-	if len(allQuestionRuns) > 0 {
-		if err := s.updateLatestFlags(ctx, orgDetails.Questions, allQuestionRuns); err != nil {
+	// Update is_latest flags for the extracted question runs; runs left
+	// unextracted by the soft deadline are picked up by a follow-up run
+	if len(extractedRuns) > 0 {
+		if err := s.updateLatestFlags(ctx, orgDetails.Questions, extractedRuns); err != nil {
 			return nil, fmt.Errorf("failed to update latest flags: %w", err)
 		}
-		fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ✅ Updated is_latest flags for %d question runs\n", len(allQuestionRuns))
+		fmt.Printf("[RunQuestionMatrixWithOrgEvaluation] ✅ Updated is_latest flags for %d question runs\n", len(extractedRuns))
 	}
 
 	return summary, nil
 }
 
+// softDeadline is the time after which an org evaluation run starts no new
+// questions or extractions. The zero value never passes.
+type softDeadline struct {
+	at  time.Time
+	now func() time.Time
+}
+
+// newSoftDeadline returns a deadline the given minutes from now; 0 disables it.
+func newSoftDeadline(minutes int) softDeadline {
+	if minutes <= 0 {
+		return softDeadline{}
+	}
+	return softDeadline{at: time.Now().Add(time.Duration(minutes) * time.Minute), now: time.Now}
+}
+
+// passed reports whether the deadline has passed.
+func (d softDeadline) passed() bool {
+	return !d.at.IsZero() && d.now().After(d.at)
+}
+
 // ModelLocationPair represents a unique combination of model and location
 type ModelLocationPair struct {
 	Model    *models.GeoModel
@@ -781,7 +845,7 @@ type ModelLocationPair struct {
 }
 
 // executeAllQuestions executes all questions grouped by model-location pairs (PHASE 2)
-func (s *orgEvaluationService) executeAllQuestions(ctx context.Context, orgDetails *RealOrgDetails, batchID uuid.UUID, summary *OrgEvaluationSummary) ([]*models.QuestionRun, error) {
+func (s *orgEvaluationService) executeAllQuestions(ctx context.Context, orgDetails *RealOrgDetails, batchID uuid.UUID, deadline softDeadline, summary *OrgEvaluationSummary) ([]*models.QuestionRun, error) {
 	var allQuestionRuns []*models.QuestionRun
 
 	// Create model-location pairs
@@ -790,6 +854,11 @@ func (s *orgEvaluationService) executeAllQuestions(ctx context.Context, orgDetai
 
 	// Process each model-location pair
 	for pairIdx, pair := range pairs {
+		if summary.DeadlineReached {
+			summary.RemainingQuestions += len(orgDetails.Questions)
+			continue
+		}
+
 		fmt.Printf("[executeAllQuestions] 📦 Processing pair %d/%d: model=%s, location=%s\n",
 			pairIdx+1, len(pairs), pair.Model.Name, pair.Location.CountryCode)
 
@@ -798,9 +867,16 @@ func (s *orgEvaluationService) executeAllQuestions(ctx context.Context, orgDetai
 		if err != nil {
 			return nil, fmt.Errorf("failed to get provider for model %s: %w", pair.Model.Name, err)
 		}
+		provider, err = applyWebSearchPolicy(s.cfg, provider, pair.Model.Name)
+		if err != nil {
+			errMsg := fmt.Sprintf("Skipped model %s, location %s: %v", pair.Model.Name, pair.Location.CountryCode, err)
+			summary.ProcessingErrors = append(summary.ProcessingErrors, errMsg)
+			fmt.Printf("[executeAllQuestions] ⚠️ %s\n", errMsg)
+			continue
+		}
 
 		// Execute questions for this pair (batched or sequential)
-		questionRuns, err := s.executeQuestionsForPair(ctx, orgDetails.Questions, pair, provider, batchID, summary)
+		questionRuns, err := s.executeQuestionsForPair(ctx, orgDetails.Questions, pair, provider, batchID, deadline, summary)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute questions for model %s, location %s: %w",
 				pair.Model.Name, pair.Location.CountryCode, err)
@@ -835,6 +911,7 @@ func (s *orgEvaluationService) executeQuestionsForPair(
 	pair ModelLocationPair,
 	provider AIProvider,
 	batchID uuid.UUID,
+	deadline softDeadline,
 	summary *OrgEvaluationSummary,
 ) ([]*models.QuestionRun, error) {
 	var questionRuns []*models.QuestionRun
@@ -858,6 +935,13 @@ func (s *orgEvaluationService) executeQuestionsForPair(
 			}
 			batch := questions[i:end]
 
+			if deadline.passed() {
+				summary.DeadlineReached = true
+				summary.RemainingQuestions += len(questions) - i
+				fmt.Printf("[executeQuestionsForPair] ⏰ Soft deadline reached, not starting remaining %d questions\n", len(questions)-i)
+				break
+			}
+
 			fmt.Printf("[executeQuestionsForPair] 📦 Processing batch %d-%d of %d questions\n", i+1, end, len(questions))
 
 			// Execute batch
@@ -874,12 +958,19 @@ func (s *orgEvaluationService) executeQuestionsForPair(
 		fmt.Printf("[executeQuestionsForPair] 🔄 Provider does not support batching, processing sequentially\n")
 
 		for idx, questionWithTags := range questions {
+			if deadline.passed() {
+				summary.DeadlineReached = true
+				summary.RemainingQuestions += len(questions) - idx
+				fmt.Printf("[executeQuestionsForPair] ⏰ Soft deadline reached, not starting remaining %d questions\n", len(questions)-idx)
+				break
+			}
+
 			question := questionWithTags.Question
 			fmt.Printf("[executeQuestionsForPair] 📝 Processing question %d/%d: %s\n",
 				idx+1, len(questions), question.QuestionText)
 
 			// Execute single question
-			run, err := s.executeSingleQuestion(ctx, question, pair, provider, workflowLocation, batchID, summary)
+			run, err := s.executeSingleQuestion(ctx, question, QuestionTags(questionWithTags), pair, provider, workflowLocation, batchID, summary)
 			if err != nil {
 				summary.ProcessingErrors = append(summary.ProcessingErrors,
 					fmt.Sprintf("Failed to execute question %s: %v", question.GeoQuestionID, err))
@@ -934,34 +1025,72 @@ func (s *orgEvaluationService) executeBatch(
 
 	fmt.Printf("[executeBatch] Executing %d new questions (skipped %d existing)\n", len(questionsToExecute), len(existingRuns))
 
-	// Extract query strings from questions that need execution
-	queries := make([]string, len(questionsToExecute))
-	for i, q := range questionsToExecute {
-		queries[i] = q.Question.QuestionText
+	// Normalize query strings; questions with unresolvable templating are recorded and dropped
+	// Each query carries its question ID so responses are matched by ID, not position
+	// Questions answered by today's network run (when the org allows reuse) are not queried
+	queries := make([]BatchQuery, 0, len(questionsToExecute))
+	validQuestions := make([]interfaces.GeoQuestionWithTags, 0, len(questionsToExecute))
+	reuseSources := make(map[string]*models.QuestionRun)
+	for _, q := range questionsToExecute {
+		queryText, err := NormalizeQuestionText(q.Question.QuestionText, workflowLocation)
+		if err != nil {
+			errMsg := fmt.Sprintf("Question %s failed validation: %v", q.Question.GeoQuestionID, err)
+			summary.ProcessingErrors = append(summary.ProcessingErrors, errMsg)
+			fmt.Printf("[executeBatch] ⚠️ %s\n", errMsg)
+			continue
+		}
+		validQuestions = append(validQuestions, q)
+		if source := s.lookupReusableNetworkRun(ctx, pair.Location.OrgID, pairReuseTarget(q.Question, pair), "executeBatch"); source != nil {
+			reuseSources[q.Question.GeoQuestionID.String()] = source
+			continue
+		}
+		queries = append(queries, BatchQuery{CorrelationID: q.Question.GeoQuestionID.String(), Query: queryText})
+	}
+	questionsToExecute = validQuestions
+	if len(questionsToExecute) == 0 {
+		return existingRuns, nil
 	}
 
-	fmt.Printf("[executeBatch] 🚀 Calling provider.RunQuestionBatch with %d queries\n", len(queries))
+	responsesByID := make(map[string]*AIResponse, len(questionsToExecute))
+	if len(queries) > 0 {
+		fmt.Printf("[executeBatch] 🚀 Calling provider.RunQuestionBatch with %d queries (%d reused from network runs)\n", len(queries), len(reuseSources))
 
-	// Execute batch API call
-	responses, err := provider.RunQuestionBatch(ctx, queries, true, workflowLocation)
-	if err != nil {
-		fmt.Printf("[executeBatch] ❌ Batch API call failed: %v\n", err)
-		return nil, fmt.Errorf("batch API call failed: %w", err)
-	}
+		// Execute batch API call
+		responses, err := provider.RunQuestionBatch(ctx, queries, true, workflowLocation)
+		if err != nil {
+			fmt.Printf("[executeBatch] ❌ Batch API call failed: %v\n", err)
+			return nil, fmt.Errorf("batch API call failed: %w", err)
+		}
 
-	fmt.Printf("[executeBatch] ✅ Batch API call succeeded, got %d responses\n", len(responses))
+		batchLatency := int64(0)
+		if len(responses) > 0 && responses[0] != nil {
+			batchLatency = responses[0].LatencyMs
+		}
+		fmt.Printf("[executeBatch] ✅ Batch API call succeeded, got %d responses in %dms\n", len(responses), batchLatency)
 
-	if len(responses) != len(questionsToExecute) {
-		errMsg := fmt.Sprintf("batch returned %d responses but expected %d", len(responses), len(questionsToExecute))
-		fmt.Printf("[executeBatch] ❌ %s\n", errMsg)
-		return nil, fmt.Errorf("%s", errMsg)
+		if responsesByID, err = MatchBatchResponses(queries, responses); err != nil {
+			fmt.Printf("[executeBatch] ❌ Batch response correlation failed: %v\n", err)
+			return nil, fmt.Errorf("batch response correlation failed: %w", err)
+		}
+	} else {
+		fmt.Printf("[executeBatch] All %d questions reused from network runs, skipping batch API call\n", len(reuseSources))
+	}
+	for questionID, source := range reuseSources {
+		responsesByID[questionID] = reusedAIResponse(source)
 	}
 
-	// Create and store new question runs
-	newQuestionRuns := make([]*models.QuestionRun, len(questionsToExecute))
-	for i, questionWithTags := range questionsToExecute {
+	// Create and store new question runs (truncated responses are dropped unless configured)
+	newQuestionRuns := make([]*models.QuestionRun, 0, len(questionsToExecute))
+	for _, questionWithTags := range questionsToExecute {
 		question := questionWithTags.Question
-		aiResponse := responses[i]
+		aiResponse := responsesByID[question.GeoQuestionID.String()]
+		if rejectTruncated(s.cfg, aiResponse) {
+			errorMsg := fmt.Sprintf("Question %s failed for model %s, location %s: %s",
+				question.GeoQuestionID, pair.Model.Name, pair.Location.CountryCode, skipDetail(aiResponse))
+			summary.ProcessingErrors = append(summary.ProcessingErrors, errorMsg)
+			fmt.Printf("[executeBatch] ⚠️ Skipping question run: %s\n", errorMsg)
+			continue
+		}
 
 		questionRun := &models.QuestionRun{
 			QuestionRunID: uuid.New(),
@@ -985,8 +1114,16 @@ func (s *orgEvaluationService) executeBatch(
 		if err := s.repos.QuestionRunRepo.Create(ctx, questionRun); err != nil {
 			return nil, fmt.Errorf("failed to store question run: %w", err)
 		}
+		recordTruncation(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+		recordWebSearchDowngrade(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+		summary.Languages = countLanguage(summary.Languages, recordResponseLanguage(ctx, s.repos, questionRun.QuestionRunID, aiResponse))
+		recordQuestionTags(ctx, s.repos, questionRun.QuestionRunID, QuestionTags(questionWithTags))
+		if source, ok := reuseSources[question.GeoQuestionID.String()]; ok {
+			recordQuestionRunReuse(ctx, s.repos, questionRun.QuestionRunID, source.QuestionRunID)
+			summary.ReusedRuns++
+		}
 
-		newQuestionRuns[i] = questionRun
+		newQuestionRuns = append(newQuestionRuns, questionRun)
 		summary.TotalProcessed++
 	}
 
@@ -1000,6 +1137,7 @@ func (s *orgEvaluationService) executeBatch(
 func (s *orgEvaluationService) executeSingleQuestion(
 	ctx context.Context,
 	question *models.GeoQuestion,
+	tags []string,
 	pair ModelLocationPair,
 	provider AIProvider,
 	workflowLocation *workflowModels.Location,
@@ -1018,10 +1156,26 @@ func (s *orgEvaluationService) executeSingleQuestion(
 		return existingRun, nil
 	}
 
-	// Execute AI call
-	aiResponse, err := provider.RunQuestion(ctx, question.QuestionText, true, workflowLocation)
+	queryText, err := NormalizeQuestionText(question.QuestionText, workflowLocation)
 	if err != nil {
-		return nil, fmt.Errorf("AI call failed: %w", err)
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
+
+	// Reuse today's network run of the same question when the org allows it
+	reuseSource := s.lookupReusableNetworkRun(ctx, pair.Location.OrgID, pairReuseTarget(question, pair), "executeSingleQuestion")
+
+	var aiResponse *AIResponse
+	if reuseSource != nil {
+		aiResponse = reusedAIResponse(reuseSource)
+	} else {
+		// Execute AI call
+		aiResponse, err = provider.RunQuestion(ctx, queryText, true, workflowLocation)
+		if err != nil {
+			return nil, fmt.Errorf("AI call failed: %w", ClassifyProviderError(pair.Model.Name, err))
+		}
+		if rejectTruncated(s.cfg, aiResponse) {
+			return nil, fmt.Errorf("AI call failed: %w", aiResponse.Err(pair.Model.Name))
+		}
 	}
 
 	// Create question run record
@@ -1047,12 +1201,21 @@ func (s *orgEvaluationService) executeSingleQuestion(
 	if err := s.repos.QuestionRunRepo.Create(ctx, questionRun); err != nil {
 		return nil, fmt.Errorf("failed to store question run: %w", err)
 	}
+	recordTruncation(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	recordWebSearchDowngrade(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	summary.Languages = countLanguage(summary.Languages, recordResponseLanguage(ctx, s.repos, questionRun.QuestionRunID, aiResponse))
+	recordQuestionTags(ctx, s.repos, questionRun.QuestionRunID, tags)
+	if reuseSource != nil {
+		recordQuestionRunReuse(ctx, s.repos, questionRun.QuestionRunID, reuseSource.QuestionRunID)
+		summary.ReusedRuns++
+	}
 
 	summary.TotalProcessed++
 	return questionRun, nil
 }
 
 // processAllExtractions processes extractions for all question runs (PHASE 3)
+// until the soft deadline passes, and returns the runs it got to
 func (s *orgEvaluationService) processAllExtractions(
 	ctx context.Context,
 	questionRuns []*models.QuestionRun,
@@ -1061,11 +1224,22 @@ func (s *orgEvaluationService) processAllExtractions(
 	websites []string,
 	nameVariations []string,
 	batchID uuid.UUID,
+	deadline softDeadline,
 	summary *OrgEvaluationSummary,
-) error {
+) ([]*models.QuestionRun, error) {
 	fmt.Printf("[processAllExtractions] Processing extractions for %d question runs\n", len(questionRuns))
 
+	progress := newBatchProgress(s.cfg, s.repos, batchID)
+	defer progress.Flush(ctx)
+
 	for idx, questionRun := range questionRuns {
+		if deadline.passed() {
+			summary.DeadlineReached = true
+			summary.RemainingQuestions += len(questionRuns) - idx
+			fmt.Printf("[processAllExtractions] ⏰ Soft deadline reached, not extracting remaining %d question runs\n", len(questionRuns)-idx)
+			return questionRuns[:idx], nil
+		}
+
 		fmt.Printf("[processAllExtractions] 🔍 Processing extraction %d/%d for question run %s\n",
 			idx+1, len(questionRuns), questionRun.QuestionRunID)
 
@@ -1079,9 +1253,7 @@ func (s *orgEvaluationService) processAllExtractions(
 			fmt.Printf("[processAllExtractions] ✓ Skipping extraction for question run %s - org_eval already exists (citations:%t competitors:%t)\n",
 				questionRun.QuestionRunID, hasCitations, hasCompetitors)
 			// Update batch as completed (since extraction was already done)
-			if updateErr := s.UpdateBatchProgress(ctx, batchID, 1, 0); updateErr != nil {
-				fmt.Printf("[processAllExtractions] Warning: Failed to update batch progress: %v\n", updateErr)
-			}
+			progress.Add(ctx, 1, 0)
 			continue
 		}
 
@@ -1090,18 +1262,14 @@ func (s *orgEvaluationService) processAllExtractions(
 			summary.ProcessingErrors = append(summary.ProcessingErrors,
 				fmt.Sprintf("Failed to process org evaluation for question run %s: %v", questionRun.QuestionRunID, err))
 			// Update batch with failed question
-			if updateErr := s.UpdateBatchProgress(ctx, batchID, 0, 1); updateErr != nil {
-				fmt.Printf("[processAllExtractions] Warning: Failed to update batch progress: %v\n", updateErr)
-			}
+			progress.Add(ctx, 0, 1)
 		} else {
 			// Update batch with completed question
-			if updateErr := s.UpdateBatchProgress(ctx, batchID, 1, 0); updateErr != nil {
-				fmt.Printf("[processAllExtractions] Warning: Failed to update batch progress: %v\n", updateErr)
-			}
+			progress.Add(ctx, 1, 0)
 		}
 	}
 
-	return nil
+	return questionRuns, nil
 }
 
 // executeAICall performs the actual AI model call using the proper AIProvider system with web search
@@ -1114,11 +1282,20 @@ func (s *orgEvaluationService) executeAICall(ctx context.Context, questionText,
 		Region:  location.RegionName,
 	}
 
+	questionText, err := NormalizeQuestionText(questionText, workflowLocation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
+
 	// Get the appropriate AI provider (same logic as QuestionRunnerService)
 	provider, err := s.getProvider(modelName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider: %w", err)
 	}
+	provider, err = applyWebSearchPolicy(s.cfg, provider, modelName)
+	if err != nil {
+		return nil, err
+	}
 
 	// Enable web search for question execution
 	webSearch := true
@@ -1134,12 +1311,24 @@ func (s *orgEvaluationService) executeAICall(ctx context.Context, questionText,
 	fmt.Printf("[executeAICall]   - Input tokens: %d\n", response.InputTokens)
 	fmt.Printf("[executeAICall]   - Output tokens: %d\n", response.OutputTokens)
 	fmt.Printf("[executeAICall]   - Cost: $%.6f\n", response.Cost)
+	fmt.Printf("[executeAICall]   - Latency: %dms\n", response.LatencyMs)
 
 	return response, nil
 }
 
-// getProvider returns the appropriate AI provider for the model (same logic as QuestionRunnerService)
+// getProvider returns the appropriate AI provider for the model (same logic as QuestionRunnerService),
+// with the capability overrides of the providers config and record/replay applied.
 func (s *orgEvaluationService) getProvider(model string) (AIProvider, error) {
+	provider, err := s.selectProvider(model, defaultBrightDataDatasets(s.cfg))
+	if err != nil {
+		return nil, err
+	}
+	return withReplay(s.cfg, withConfiguredCapabilities(provider, model), model), nil
+}
+
+// selectProvider routes the model: config overrides first, then the
+// built-in model-name rules.
+func (s *orgEvaluationService) selectProvider(model string, datasets config.BrightDataDatasets) (AIProvider, error) {
 	modelLower := strings.ToLower(model)
 
 	// Debug the config
@@ -1147,22 +1336,27 @@ func (s *orgEvaluationService) getProvider(model string) (AIProvider, error) {
 		return nil, fmt.Errorf("config is nil")
 	}
 
+	// Config overrides take precedence over the built-in routing below
+	if provider, err := overrideProvider(s.cfg, datasets, model, s.costService); provider != nil || err != nil {
+		return provider, err
+	}
+
 	// BrightData ChatGPT provider
 	if strings.Contains(modelLower, "chatgpt") {
 		fmt.Printf("[getProvider] 🎯 Selected BrightData ChatGPT provider for model: %s\n", model)
-		return NewBrightDataProvider(s.cfg, model, s.costService), nil
+		return NewBrightDataProvider(s.cfg, datasets.BrightDataDatasetID, model, s.costService), nil
 	}
 
 	// Perplexity provider (via BrightData)
 	if strings.Contains(modelLower, "perplexity") {
 		fmt.Printf("[getProvider] 🎯 Selected Perplexity provider for model: %s\n", model)
-		return NewPerplexityProvider(s.cfg, model, s.costService), nil
+		return NewPerplexityProvider(s.cfg, datasets.PerplexityDatasetID, model, s.costService), nil
 	}
 
 	// Gemini provider (via BrightData)
 	if strings.Contains(modelLower, "gemini") {
 		fmt.Printf("[getProvider] 🎯 Selected Gemini provider for model: %s\n", model)
-		return NewGeminiProvider(s.cfg, model, s.costService), nil
+		return NewGeminiProvider(s.cfg, datasets.GeminiDatasetID, model, s.costService), nil
 	}
 
 	// Linkup provider
@@ -1219,6 +1413,8 @@ func (s *orgEvaluationService) updateLatestFlags(ctx context.Context, questions
 	for qID := range questionIDMap {
 		questionIDs = append(questionIDs, qID)
 	}
+	audit := beginLatestFlagAudit(ctx, s.repos, LatestFlagSourceOrgBatch, batchID, questionIDs)
+	shiftSecondLatest(ctx, s.repos, questionIDs, runIDs(newRuns))
 
 	// Step 1: Mark old question runs as is_latest=false
 	// For each question in this batch, get all old runs and mark them as not latest
@@ -1251,6 +1447,8 @@ func (s *orgEvaluationService) updateLatestFlags(ctx context.Context, questions
 		}
 	}
 
+	audit.record(ctx, latestRunsByQuestion(newRuns))
+
 	fmt.Printf("[updateLatestFlags] ✅ Successfully updated is_latest flags for %d question runs in batch %s\n", len(newRuns), batchID)
 	return nil
 }
@@ -1323,6 +1521,29 @@ func (s *orgEvaluationService) FailBatch(ctx context.Context, batchID uuid.UUID)
 	return s.repos.QuestionRunBatchRepo.Update(ctx, batch)
 }
 
+// PartialBatch marks a batch as partially processed after the soft deadline was hit.
+// The batch keeps its place as today's batch, so a follow-up event resumes it and
+// skips the question runs that already exist.
+func (s *orgEvaluationService) PartialBatch(ctx context.Context, batchID uuid.UUID, remainingQuestions int) error {
+	batch, err := s.repos.QuestionRunBatchRepo.GetByID(ctx, batchID)
+	if err != nil {
+		return fmt.Errorf("failed to get batch: %w", err)
+	}
+
+	completed := batch.TotalQuestions - remainingQuestions
+	if completed < 0 {
+		completed = 0
+	}
+
+	batch.Status = "partial"
+	batch.CompletedQuestions = completed
+	batch.UpdatedAt = time.Now()
+
+	fmt.Printf("[PartialBatch] Batch %s marked partial: %d/%d completed, %d remaining\n",
+		batchID, completed, batch.TotalQuestions, remainingQuestions)
+	return s.repos.QuestionRunBatchRepo.Update(ctx, batch)
+}
+
 // UpdateBatchProgress updates the completed and failed question counts
 func (s *orgEvaluationService) UpdateBatchProgress(ctx context.Context, batchID uuid.UUID, completed, failed int) error {
 	batch, err := s.repos.QuestionRunBatchRepo.GetByID(ctx, batchID)
@@ -1365,6 +1586,7 @@ func (s *orgEvaluationService) CalculateQuestionMatrix(ctx context.Context, orgD
 					LocationName: locationName,
 					JobIndex:     jobIndex,
 					TotalJobs:    totalJobs,
+					Tags:         QuestionTags(questionWithTags),
 				}
 				jobs = append(jobs, job)
 				jobIndex++
@@ -1399,11 +1621,26 @@ func (s *orgEvaluationService) ProcessSingleQuestionJob(ctx context.Context, job
 		RegionName:    regionNamePtr,
 	}
 
-	// Execute AI call to get response
-	aiResponse, err := s.executeAICall(ctx, job.QuestionText, job.ModelName, location)
-	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("AI call failed: %v", err)
-		return result, nil // Return result with failed status, don't error the step
+	// Reuse today's network run of the same question when the org allows it
+	reuseSource := s.lookupReusableNetworkRun(ctx, orgID, reuseTarget{
+		QuestionText: job.QuestionText, Model: job.ModelName, Country: job.LocationCode, Region: job.LocationName,
+	}, "ProcessSingleQuestionJob")
+
+	var aiResponse *AIResponse
+	var err error
+	if reuseSource != nil {
+		aiResponse = reusedAIResponse(reuseSource)
+	} else {
+		// Execute AI call to get response
+		aiResponse, err = s.executeAICall(ctx, job.QuestionText, job.ModelName, location)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("AI call failed: %v", ClassifyProviderError(job.ModelName, err))
+			return result, nil // Return result with failed status, don't error the step
+		}
+		if rejectTruncated(s.cfg, aiResponse) {
+			result.ErrorMessage = fmt.Sprintf("AI call failed: %v", aiResponse.Err(job.ModelName))
+			return result, nil
+		}
 	}
 
 	// Create question run record
@@ -1430,6 +1667,14 @@ func (s *orgEvaluationService) ProcessSingleQuestionJob(ctx context.Context, job
 		result.ErrorMessage = fmt.Sprintf("Failed to store question run: %v", err)
 		return result, nil // Return result with failed status
 	}
+	recordTruncation(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	recordWebSearchDowngrade(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	recordResponseLanguage(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	recordQuestionTags(ctx, s.repos, questionRun.QuestionRunID, job.Tags)
+	if reuseSource != nil {
+		recordQuestionRunReuse(ctx, s.repos, questionRun.QuestionRunID, reuseSource.QuestionRunID)
+		result.ReusedFromRunID = &reuseSource.QuestionRunID
+	}
 
 	result.QuestionRunID = questionRun.QuestionRunID
 	result.TotalCost = aiResponse.Cost
@@ -1690,6 +1935,17 @@ func (s *orgEvaluationService) ProcessNetworkOrgQuestionRunReeval(ctx context.Co
 		Status:        "failed", // Default to failed, will update on success
 	}
 
+	// A missing-evaluation run extracting the pair right now would have its
+	// fresh rows deleted below; leave the pair to it
+	release, err := claimNetworkOrgRun(ctx, s.cfg, s.repos, questionRunID, orgID, "reeval:"+uuid.New().String())
+	if err != nil {
+		fmt.Printf("[ProcessNetworkOrgQuestionRunReeval] Skipping question run %s for org %s: %v\n", questionRunID, orgID, err)
+		result.Status = "skipped"
+		result.ErrorMessage = err.Error()
+		return result, nil
+	}
+	defer release()
+
 	// Step 1: Clean up existing network_org_* data for this question run + org
 	fmt.Printf("[ProcessNetworkOrgQuestionRunReeval] Cleaning up existing network org data for question run %s and org %s\n", questionRunID, orgID)
 
@@ -1757,6 +2013,7 @@ func (s *orgEvaluationService) ProcessNetworkOrgQuestionRunReeval(ctx context.Co
 		result.CitationCount = len(extractionResult.Citations)
 		// Use actual cost tracking from NetworkOrgExtractionResult
 		result.TotalCost = extractionResult.TotalCost
+		saveNetworkOrgStageUsage(ctx, s.repos, questionRunID, orgID, extractionResult)
 
 		fmt.Printf("[ProcessNetworkOrgQuestionRunReeval] ✅ Network org evaluation completed and stored: 1 eval, %d competitors, %d citations, $%.6f cost\n",
 			result.CompetitorCount, result.CitationCount, result.TotalCost)
@@ -1955,6 +2212,8 @@ func countCitationsByType(citations []*models.OrgCitation, citationType string)
 
 // getBaseDomain extracts the base domain (eTLD+1) from a URL using publicsuffix
 func getBaseDomain(urlStr string) (string, error) {
+	urlStr = strings.ToLower(strings.TrimSpace(urlStr))
+
 	// Handle URLs without protocol
 	if !strings.HasPrefix(urlStr, "http://") && !strings.HasPrefix(urlStr, "https://") {
 		urlStr = "https://" + urlStr
@@ -1980,6 +2239,30 @@ func getBaseDomain(urlStr string) (string, error) {
 	return baseDomain, nil
 }
 
+// normalizeOrgWebsites reduces org websites, which arrive with or without a
+// scheme, www. or a path, to their distinct registrable domains
+// ("https://www.Senso.ai/about" -> "senso.ai") for domain matching. Entries
+// without a parseable domain are dropped. Prompts keep showing the raw list.
+func normalizeOrgWebsites(websites []string) []string {
+	domains := make([]string, 0, len(websites))
+	seen := make(map[string]bool, len(websites))
+	for _, website := range websites {
+		if strings.TrimSpace(website) == "" {
+			continue
+		}
+		domain, err := getBaseDomain(website)
+		if err != nil {
+			fmt.Printf("[normalizeOrgWebsites] ⚠️ Ignoring org website %q: %v\n", website, err)
+			continue
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
 // isPrimaryDomain checks if a citation URL belongs to any of the organization's domains
 func isPrimaryDomain(citationURL string, orgDomains []string) bool {
 	citationBase, err := getBaseDomain(citationURL)
@@ -2007,9 +2290,9 @@ func isPrimaryDomain(citationURL string, orgDomains []string) bool {
 func (s *orgEvaluationService) GetOrCreateTodaysBatch(ctx context.Context, orgID uuid.UUID, totalQuestions int) (*models.QuestionRunBatch, bool, error) {
 	fmt.Printf("[GetOrCreateTodaysBatch] Checking for existing batch for org: %s\n", orgID)
 
-	// Try to find an existing batch from today that's not completed
-	today := time.Now().UTC()
-	todayStart := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	// Try to find an existing batch from today (in the org's batch day time zone)
+	dayLoc := BatchDayLocation(s.cfg, orgID)
+	todayStart := DayStart(time.Now(), dayLoc)
 
 	// Fetch batches directly for this org (do not infer from question runs)
 	batches, err := s.repos.QuestionRunBatchRepo.GetByOrg(ctx, orgID)
@@ -2044,11 +2327,12 @@ func (s *orgEvaluationService) GetOrCreateTodaysBatch(ctx context.Context, orgID
 		IsLatest:           true,
 	}
 
-	if err := s.repos.QuestionRunBatchRepo.Create(ctx, batch); err != nil {
+	// Clears is_latest on the org's previous batch in the same transaction
+	if err := CreateLatestBatch(ctx, s.repos, batch); err != nil {
 		return nil, false, fmt.Errorf("failed to create batch: %w", err)
 	}
 
-	fmt.Printf("[GetOrCreateTodaysBatch] Created new batch %s with %d total questions\n", batch.BatchID, totalQuestions)
+	fmt.Printf("[GetOrCreateTodaysBatch] Created new batch %s with %d total questions (batch day %s, tz=%s)\n", batch.BatchID, totalQuestions, todayStart.Format("2006-01-02"), dayLoc)
 	return batch, false, nil
 }
 
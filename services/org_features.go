@@ -0,0 +1,69 @@
+// services/org_features.go
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Extraction stages that can be switched off per org by plan tier.
+const (
+	FeatureCompetitors = "competitors"
+	FeatureCitations   = "citations"
+)
+
+// OrgFeatures lists which optional extraction stages an org pays for. Mention
+// tracking is always on.
+type OrgFeatures struct {
+	Competitors bool
+	Citations   bool
+}
+
+// PlanSkipped returns the names of the stages disabled by the org's plan.
+func (f OrgFeatures) PlanSkipped() []string {
+	var skipped []string
+	if !f.Competitors {
+		skipped = append(skipped, FeatureCompetitors)
+	}
+	if !f.Citations {
+		skipped = append(skipped, FeatureCitations)
+	}
+	return skipped
+}
+
+// OrgFeatureProvider resolves an org's features. The config-backed provider is
+// a stopgap until the plan is readable from senso-api.
+type OrgFeatureProvider interface {
+	FeaturesForOrg(ctx context.Context, orgID uuid.UUID) OrgFeatures
+}
+
+type configOrgFeatureProvider struct {
+	disabled map[string]string
+}
+
+// NewConfigOrgFeatureProvider reads ORG_DISABLED_STAGES, a comma-separated list
+// of org_uuid=stage[|stage] entries (e.g. "<uuid>=competitors|citations").
+// Orgs not listed get every stage.
+func NewConfigOrgFeatureProvider(cfg *config.Config) OrgFeatureProvider {
+	p := &configOrgFeatureProvider{disabled: map[string]string{}}
+	if cfg != nil && cfg.OrgDisabledStages != nil {
+		p.disabled = cfg.OrgDisabledStages
+	}
+	return p
+}
+
+func (p *configOrgFeatureProvider) FeaturesForOrg(ctx context.Context, orgID uuid.UUID) OrgFeatures {
+	features := OrgFeatures{Competitors: true, Citations: true}
+	for _, stage := range strings.Split(p.disabled[strings.ToLower(orgID.String())], "|") {
+		switch strings.TrimSpace(stage) {
+		case FeatureCompetitors:
+			features.Competitors = false
+		case FeatureCitations:
+			features.Citations = false
+		}
+	}
+	return features
+}
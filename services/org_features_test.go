@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+func TestConfigOrgFeatureProvider(t *testing.T) {
+	orgID := uuid.New()
+	tests := []struct {
+		name     string
+		disabled map[string]string
+		want     OrgFeatures
+	}{
+		{name: "org not listed", disabled: map[string]string{uuid.NewString(): "competitors"}, want: OrgFeatures{Competitors: true, Citations: true}},
+		{name: "competitors disabled", disabled: map[string]string{orgID.String(): "competitors"}, want: OrgFeatures{Citations: true}},
+		{name: "both disabled", disabled: map[string]string{orgID.String(): "competitors| citations"}, want: OrgFeatures{}},
+		{name: "unknown stage ignored", disabled: map[string]string{orgID.String(): "mentions"}, want: OrgFeatures{Competitors: true, Citations: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewConfigOrgFeatureProvider(&config.Config{OrgDisabledStages: tt.disabled})
+			if got := provider.FeaturesForOrg(context.Background(), orgID); got != tt.want {
+				t.Errorf("FeaturesForOrg() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractNetworkOrgDataSkipsPlanStages checks an org whose plan excludes a
+// stage gets no AI call or rows for it, and the stage is marked skipped_plan.
+func TestExtractNetworkOrgDataSkipsPlanStages(t *testing.T) {
+	const response = "Acme Bank and Globex both offer savings accounts, see https://acmebank.com/savings."
+	orgID := uuid.New()
+
+	tests := []struct {
+		name            string
+		disabled        string
+		wantCompCalls   int
+		wantCompetitors int
+		wantCitations   int
+		wantSkipped     []string
+	}{
+		{name: "all stages", wantCompCalls: 1, wantCompetitors: 1, wantCitations: 1},
+		{name: "competitors disabled", disabled: "competitors", wantCitations: 1, wantSkipped: []string{FeatureCompetitors}},
+		{name: "citations disabled", disabled: "citations", wantCompCalls: 1, wantCompetitors: 1, wantSkipped: []string{FeatureCitations}},
+		{name: "both disabled", disabled: "competitors|citations", wantSkipped: []string{FeatureCompetitors, FeatureCitations}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features := NewConfigOrgFeatureProvider(&config.Config{OrgDisabledStages: map[string]string{orgID.String(): tt.disabled}})
+			s, mock := newExtractionService(t, features)
+
+			result, err := s.ExtractNetworkOrgData(context.Background(), uuid.New(), orgID, "Acme Bank",
+				[]string{"acmebank.com"}, "Which bank has the best savings account?", response, []string{"Acme Bank"})
+			if err != nil {
+				t.Fatalf("ExtractNetworkOrgData() error = %v", err)
+			}
+			if got := mock.count("network_org_competitor_extraction"); got != tt.wantCompCalls {
+				t.Errorf("competitor model called %d times, want %d", got, tt.wantCompCalls)
+			}
+			if got := mock.count("network_org_evaluation_extraction"); got != 1 {
+				t.Errorf("evaluation model called %d times, want 1 whatever the plan", got)
+			}
+			if len(result.Competitors) != tt.wantCompetitors || len(result.Citations) != tt.wantCitations {
+				t.Errorf("got %d competitors and %d citations, want %d and %d",
+					len(result.Competitors), len(result.Citations), tt.wantCompetitors, tt.wantCitations)
+			}
+			if got := result.Stages.PlanSkipped(); !reflect.DeepEqual(got, tt.wantSkipped) {
+				t.Errorf("plan-skipped stages = %q, want %q", got, tt.wantSkipped)
+			}
+			if tt.wantCompCalls == 0 && (result.Stages.Competitors.Cost != 0 || result.Stages.Competitors.InputTokens != 0) {
+				t.Errorf("skipped competitors stage = %+v, want no usage", result.Stages.Competitors)
+			}
+		})
+	}
+}
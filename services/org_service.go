@@ -16,6 +16,8 @@ type orgService struct {
 	cfg        *config.Config
 	httpClient *http.Client
 	repos      *RepositoryManager
+	// detailsCache is nil when ORG_DETAILS_CACHE_TTL_SECONDS is 0.
+	detailsCache *orgDetailsCache
 }
 
 func NewOrgService(cfg *config.Config, repos *RepositoryManager) OrgService {
@@ -24,11 +26,33 @@ func NewOrgService(cfg *config.Config, repos *RepositoryManager) OrgService {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		repos: repos,
+		repos:        repos,
+		detailsCache: newOrgDetailsCache(time.Duration(cfg.OrgDetailsCacheTTLSeconds) * time.Second),
 	}
 }
 
+// GetOrgDetails loads the org with its models, locations, questions, profiles
+// and websites, reusing a cached copy when the details cache is enabled.
 func (s *orgService) GetOrgDetails(ctx context.Context, orgID string) (*RealOrgDetails, error) {
+	if details, ok := s.detailsCache.get(orgID); ok {
+		fmt.Printf("[GetOrgDetails] Using cached details for org: %s\n", orgID)
+		return details, nil
+	}
+	details, err := s.fetchOrgDetails(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	s.detailsCache.put(orgID, details)
+	return details, nil
+}
+
+// InvalidateOrgDetails drops the cached details of an org, e.g. after its
+// questions or models changed mid-run.
+func (s *orgService) InvalidateOrgDetails(orgID string) {
+	s.detailsCache.invalidate(orgID)
+}
+
+func (s *orgService) fetchOrgDetails(ctx context.Context, orgID string) (*RealOrgDetails, error) {
 	fmt.Printf("[GetOrgDetails] Fetching real details for org: %s\n", orgID)
 
 	// Parse orgID to UUID
@@ -57,6 +81,10 @@ func (s *orgService) GetOrgDetails(ctx context.Context, orgID string) (*RealOrgD
 	if err != nil {
 		return nil, fmt.Errorf("failed to get org locations: %w", err)
 	}
+	locations, invalidLocations := ValidateLocations(locations)
+	for _, invalid := range invalidLocations {
+		fmt.Printf("[GetOrgDetails] ⚠️ %s\n", invalid.Message())
+	}
 
 	// 4. Get geo questions with tags
 	questions, err := s.repos.GeoQuestionRepo.GetByOrgWithTags(ctx, orgUUID)
@@ -96,6 +124,8 @@ func (s *orgService) GetOrgDetails(ctx context.Context, orgID string) (*RealOrgD
 		TargetCompany: targetCompany,
 		Profiles:      profiles,
 		Websites:      websiteURLs,
+
+		InvalidLocations: invalidLocations,
 	}
 
 	fmt.Printf("[GetOrgDetails] Successfully loaded org: %s with %d models, %d locations, %d questions, %d websites, target: %s\n",
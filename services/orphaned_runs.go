@@ -0,0 +1,270 @@
+// services/orphaned_runs.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OrphanedRun is a question run whose batch_id points at a batch that no
+// longer exists. Scope comes from the run's geo question.
+type OrphanedRun struct {
+	QuestionRunID uuid.UUID  `db:"question_run_id"`
+	BatchID       uuid.UUID  `db:"batch_id"`
+	NetworkID     *uuid.UUID `db:"network_id"`
+	OrgID         *uuid.UUID `db:"org_id"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+// OrphanedRunRepository finds orphaned runs and rewrites their batch_id.
+type OrphanedRunRepository interface {
+	ListOrphaned(ctx context.Context) ([]OrphanedRun, error)
+	// SetBatch points the runs at batchID, or clears batch_id when it is nil.
+	SetBatch(ctx context.Context, questionRunIDs []uuid.UUID, batchID *uuid.UUID) error
+}
+
+type orphanedRunRepo struct {
+	db *database.Client
+}
+
+func NewOrphanedRunRepo(db *database.Client) OrphanedRunRepository {
+	return &orphanedRunRepo{db: db}
+}
+
+func (r *orphanedRunRepo) ListOrphaned(ctx context.Context) ([]OrphanedRun, error) {
+	var runs []OrphanedRun
+	err := r.db.SelectContext(ctx, &runs, `
+		SELECT qr.question_run_id, qr.batch_id, gq.network_id, gq.org_id, qr.created_at
+		FROM question_runs qr
+		JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+		LEFT JOIN question_run_batches b ON b.batch_id = qr.batch_id
+		WHERE qr.batch_id IS NOT NULL AND b.batch_id IS NULL
+		ORDER BY qr.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orphaned question runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *orphanedRunRepo) SetBatch(ctx context.Context, questionRunIDs []uuid.UUID, batchID *uuid.UUID) error {
+	ids := make([]string, len(questionRunIDs))
+	for i, id := range questionRunIDs {
+		ids[i] = id.String()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE question_runs
+		SET batch_id = $1, updated_at = NOW()
+		WHERE question_run_id = ANY($2::uuid[])`, batchID, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to update batch_id of %d question runs: %w", len(questionRunIDs), err)
+	}
+	return nil
+}
+
+// OrphanedRunGroup is the orphaned runs of one network or org on one UTC day.
+// Scope is "network", "org", or "" when the geo question has neither.
+type OrphanedRunGroup struct {
+	Scope   string
+	ScopeID uuid.UUID
+	Day     time.Time
+	Runs    []OrphanedRun
+}
+
+// RunIDs returns the question run IDs of the group.
+func (g OrphanedRunGroup) RunIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(g.Runs))
+	for i, run := range g.Runs {
+		ids[i] = run.QuestionRunID
+	}
+	return ids
+}
+
+// GroupOrphanedRuns buckets runs by scope and UTC day, ordered by scope, ID and day.
+func GroupOrphanedRuns(runs []OrphanedRun) []OrphanedRunGroup {
+	type key struct {
+		scope   string
+		scopeID uuid.UUID
+		day     time.Time
+	}
+	index := make(map[key]int)
+	var groups []OrphanedRunGroup
+	for _, run := range runs {
+		k := key{day: utcDay(run.CreatedAt)}
+		switch {
+		case run.NetworkID != nil:
+			k.scope, k.scopeID = "network", *run.NetworkID
+		case run.OrgID != nil:
+			k.scope, k.scopeID = "org", *run.OrgID
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, OrphanedRunGroup{Scope: k.scope, ScopeID: k.scopeID, Day: k.day})
+		}
+		groups[i].Runs = append(groups[i].Runs, run)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.ScopeID != b.ScopeID {
+			return a.ScopeID.String() < b.ScopeID.String()
+		}
+		return a.Day.Before(b.Day)
+	})
+	return groups
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Orphan repair actions.
+const (
+	OrphanActionReattach = "reattach" // moved to an existing batch from the same day
+	OrphanActionCreate   = "create"   // moved to a new "repair" batch
+	OrphanActionDetach   = "detach"   // batch_id cleared
+	OrphanActionSkip     = "skip"     // no network/org to attach to
+)
+
+// OrphanRepairResult is what was (or, in dry-run, would be) done to one group.
+// BatchID is the target batch; it is nil for detach, skip, and dry-run creates.
+type OrphanRepairResult struct {
+	Group   OrphanedRunGroup
+	Action  string
+	BatchID *uuid.UUID
+	Err     error
+}
+
+// OrphanRepairOptions controls RepairOrphanedRuns.
+type OrphanRepairOptions struct {
+	// Detach clears batch_id instead of reattaching the runs to a batch.
+	Detach bool
+	// Apply writes the changes; without it the repair only plans.
+	Apply bool
+}
+
+// RepairOrphanedRuns reattaches every orphaned run to a batch of its network or
+// org from the same UTC day, creating a completed "repair" batch sized to the
+// group when none exists, or clears batch_id when opts.Detach is set. Group
+// failures are reported per result; only listing the runs is fatal.
+func RepairOrphanedRuns(ctx context.Context, runRepo OrphanedRunRepository, batchRepo interfaces.QuestionRunBatchRepository, opts OrphanRepairOptions) ([]OrphanRepairResult, error) {
+	runs, err := runRepo.ListOrphaned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeBatches := make(map[uuid.UUID][]*models.QuestionRunBatch)
+	var results []OrphanRepairResult
+	for _, group := range GroupOrphanedRuns(runs) {
+		result := OrphanRepairResult{Group: group}
+		switch {
+		case opts.Detach:
+			result.Action = OrphanActionDetach
+		case group.Scope == "":
+			result.Action = OrphanActionSkip
+			result.Err = fmt.Errorf("geo question has no network or org")
+		default:
+			batches, ok := scopeBatches[group.ScopeID]
+			if !ok {
+				if group.Scope == "network" {
+					batches, err = batchRepo.GetByNetwork(ctx, group.ScopeID)
+				} else {
+					batches, err = batchRepo.GetByOrg(ctx, group.ScopeID)
+				}
+				if err != nil {
+					result.Err = fmt.Errorf("failed to get %s batches: %w", group.Scope, err)
+					results = append(results, result)
+					continue
+				}
+				scopeBatches[group.ScopeID] = batches
+			}
+			if batch := batchOnDay(batches, group.Day); batch != nil {
+				result.Action = OrphanActionReattach
+				result.BatchID = &batch.BatchID
+			} else {
+				result.Action = OrphanActionCreate
+			}
+		}
+
+		if opts.Apply && result.Err == nil {
+			result.BatchID, result.Err = applyOrphanRepair(ctx, runRepo, batchRepo, group, result.Action, result.BatchID)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func applyOrphanRepair(ctx context.Context, runRepo OrphanedRunRepository, batchRepo interfaces.QuestionRunBatchRepository, group OrphanedRunGroup, action string, batchID *uuid.UUID) (*uuid.UUID, error) {
+	switch action {
+	case OrphanActionDetach:
+		return nil, runRepo.SetBatch(ctx, group.RunIDs(), nil)
+	case OrphanActionCreate:
+		batch := newRepairBatch(group)
+		if err := batchRepo.Create(ctx, batch); err != nil {
+			return nil, fmt.Errorf("failed to create repair batch: %w", err)
+		}
+		batchID = &batch.BatchID
+	}
+	return batchID, runRepo.SetBatch(ctx, group.RunIDs(), batchID)
+}
+
+// batchOnDay returns the earliest batch created on the given UTC day.
+func batchOnDay(batches []*models.QuestionRunBatch, day time.Time) *models.QuestionRunBatch {
+	var found *models.QuestionRunBatch
+	for _, b := range batches {
+		if b == nil || !utcDay(b.CreatedAt).Equal(day) {
+			continue
+		}
+		if found == nil || b.CreatedAt.Before(found.CreatedAt) {
+			found = b
+		}
+	}
+	return found
+}
+
+// newRepairBatch builds a completed batch spanning the group's runs. It is
+// never marked latest: it stands in for a historical batch.
+func newRepairBatch(group OrphanedRunGroup) *models.QuestionRunBatch {
+	first, last := group.Runs[0].CreatedAt, group.Runs[0].CreatedAt
+	for _, run := range group.Runs[1:] {
+		if run.CreatedAt.Before(first) {
+			first = run.CreatedAt
+		}
+		if run.CreatedAt.After(last) {
+			last = run.CreatedAt
+		}
+	}
+	batch := &models.QuestionRunBatch{
+		BatchID:            uuid.New(),
+		Scope:              group.Scope,
+		BatchType:          "repair",
+		Status:             "completed",
+		TotalQuestions:     len(group.Runs),
+		CompletedQuestions: len(group.Runs),
+		FailedQuestions:    0,
+		IsLatest:           false,
+		StartedAt:          &first,
+		CompletedAt:        &last,
+		CreatedAt:          first,
+		UpdatedAt:          time.Now(),
+	}
+	scopeID := group.ScopeID
+	if group.Scope == "network" {
+		batch.NetworkID = &scopeID
+	} else {
+		batch.OrgID = &scopeID
+	}
+	return batch
+}
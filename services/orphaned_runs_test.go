@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/google/uuid"
+)
+
+// orphanRuns is an in-memory OrphanedRunRepository; batchIDs holds the
+// batch_id SetBatch wrote per run.
+type orphanRuns struct {
+	runs     []OrphanedRun
+	batchIDs map[uuid.UUID]*uuid.UUID
+}
+
+func (o *orphanRuns) ListOrphaned(ctx context.Context) ([]OrphanedRun, error) { return o.runs, nil }
+
+func (o *orphanRuns) SetBatch(ctx context.Context, questionRunIDs []uuid.UUID, batchID *uuid.UUID) error {
+	for _, id := range questionRunIDs {
+		o.batchIDs[id] = batchID
+	}
+	return nil
+}
+
+// repairBatches is the batch repository RepairOrphanedRuns reads existing
+// batches from and creates repair batches in.
+type repairBatches struct {
+	interfaces.QuestionRunBatchRepository
+	rows    []*models.QuestionRunBatch
+	created []*models.QuestionRunBatch
+	errFor  uuid.UUID // scope whose batches fail to load
+}
+
+func (r *repairBatches) Create(ctx context.Context, batch *models.QuestionRunBatch) error {
+	r.created = append(r.created, batch)
+	return nil
+}
+
+func (r *repairBatches) list(scopeID uuid.UUID, owner func(*models.QuestionRunBatch) *uuid.UUID) ([]*models.QuestionRunBatch, error) {
+	if scopeID == r.errFor {
+		return nil, errors.New("connection reset")
+	}
+	var out []*models.QuestionRunBatch
+	for _, row := range r.rows {
+		if id := owner(row); id != nil && *id == scopeID {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *repairBatches) GetByNetwork(ctx context.Context, networkID uuid.UUID) ([]*models.QuestionRunBatch, error) {
+	return r.list(networkID, func(b *models.QuestionRunBatch) *uuid.UUID { return b.NetworkID })
+}
+
+func (r *repairBatches) GetByOrg(ctx context.Context, orgID uuid.UUID) ([]*models.QuestionRunBatch, error) {
+	return r.list(orgID, func(b *models.QuestionRunBatch) *uuid.UUID { return b.OrgID })
+}
+
+func TestRepairOrphanedRuns(t *testing.T) {
+	networkID, orgID, failingID := uuid.New(), uuid.New(), uuid.New()
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	run := func(network, org *uuid.UUID, created time.Time) OrphanedRun {
+		return OrphanedRun{QuestionRunID: uuid.New(), BatchID: uuid.New(), NetworkID: network, OrgID: org, CreatedAt: created}
+	}
+
+	// The network has two batches that day; the earlier one is the target
+	early := &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "network", NetworkID: &networkID, CreatedAt: at(6)}
+	late := &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "network", NetworkID: &networkID, CreatedAt: at(18)}
+	// The org's only batch is from the day before
+	yesterday := &models.QuestionRunBatch{BatchID: uuid.New(), Scope: "org", OrgID: &orgID, CreatedAt: at(-12)}
+
+	networkRuns := []OrphanedRun{run(&networkID, nil, at(20)), run(&networkID, nil, at(7))}
+	orgRuns := []OrphanedRun{run(nil, &orgID, at(9)), run(nil, &orgID, at(3)), run(nil, &orgID, at(15))}
+	unscoped := run(nil, nil, at(1))
+	failing := run(&failingID, nil, at(2))
+	all := append(append(append([]OrphanedRun{}, networkRuns...), orgRuns...), unscoped, failing)
+
+	repair := func(t *testing.T, opts OrphanRepairOptions) ([]OrphanRepairResult, *orphanRuns, *repairBatches) {
+		t.Helper()
+		runs := &orphanRuns{runs: all, batchIDs: map[uuid.UUID]*uuid.UUID{}}
+		batches := &repairBatches{rows: []*models.QuestionRunBatch{late, early, yesterday}, errFor: failingID}
+		results, err := RepairOrphanedRuns(context.Background(), runs, batches, opts)
+		if err != nil {
+			t.Fatalf("RepairOrphanedRuns() error = %v", err)
+		}
+		if len(results) != 4 {
+			t.Fatalf("%d results, want one per group: %+v", len(results), results)
+		}
+		return results, runs, batches
+	}
+	byScope := func(t *testing.T, results []OrphanRepairResult, scopeID uuid.UUID, scope string) OrphanRepairResult {
+		t.Helper()
+		for _, result := range results {
+			if result.Group.ScopeID == scopeID && result.Group.Scope == scope {
+				return result
+			}
+		}
+		t.Fatalf("no result for %s %s", scope, scopeID)
+		return OrphanRepairResult{}
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		results, runs, batches := repair(t, OrphanRepairOptions{Apply: true})
+
+		reattached := byScope(t, results, networkID, "network")
+		if reattached.Action != OrphanActionReattach || reattached.Err != nil || reattached.BatchID == nil || *reattached.BatchID != early.BatchID {
+			t.Errorf("network result = %+v, want reattach to the day's earliest batch %s", reattached, early.BatchID)
+		}
+		for _, r := range networkRuns {
+			if got := runs.batchIDs[r.QuestionRunID]; got == nil || *got != early.BatchID {
+				t.Errorf("network run %s batch = %v, want %s", r.QuestionRunID, got, early.BatchID)
+			}
+		}
+
+		created := byScope(t, results, orgID, "org")
+		if created.Action != OrphanActionCreate || created.Err != nil || len(batches.created) != 1 {
+			t.Fatalf("org result = %+v with %d batches created, want one repair batch", created, len(batches.created))
+		}
+		batch := batches.created[0]
+		if *created.BatchID != batch.BatchID || batch.OrgID == nil || *batch.OrgID != orgID || batch.NetworkID != nil {
+			t.Errorf("repair batch %+v, want it owned by org %s and targeted by the result", batch, orgID)
+		}
+		if batch.Scope != "org" || batch.BatchType != "repair" || batch.Status != "completed" || batch.IsLatest ||
+			batch.TotalQuestions != 3 || batch.CompletedQuestions != 3 ||
+			!batch.StartedAt.Equal(at(3)) || !batch.CompletedAt.Equal(at(15)) || !batch.CreatedAt.Equal(at(3)) {
+			t.Errorf("repair batch = %+v, want a completed, non-latest org batch spanning its 3 runs", batch)
+		}
+		for _, r := range orgRuns {
+			if got := runs.batchIDs[r.QuestionRunID]; got == nil || *got != batch.BatchID {
+				t.Errorf("org run %s batch = %v, want the repair batch %s", r.QuestionRunID, got, batch.BatchID)
+			}
+		}
+
+		skipped := byScope(t, results, uuid.Nil, "")
+		if skipped.Action != OrphanActionSkip || skipped.Err == nil {
+			t.Errorf("unscoped result = %+v, want skip with an error", skipped)
+		}
+		if failed := byScope(t, results, failingID, "network"); failed.Err == nil {
+			t.Errorf("result for a scope whose batches fail to load = %+v, want an error", failed)
+		}
+		for _, r := range []OrphanedRun{unscoped, failing} {
+			if _, ok := runs.batchIDs[r.QuestionRunID]; ok {
+				t.Errorf("run %s was updated, want it left alone", r.QuestionRunID)
+			}
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		results, runs, batches := repair(t, OrphanRepairOptions{})
+		if len(runs.batchIDs) != 0 || len(batches.created) != 0 {
+			t.Errorf("dry run updated %d runs and created %d batches, want no writes", len(runs.batchIDs), len(batches.created))
+		}
+		if created := byScope(t, results, orgID, "org"); created.Action != OrphanActionCreate || created.BatchID != nil {
+			t.Errorf("dry-run org result = %+v, want a planned create without a batch", created)
+		}
+		if reattached := byScope(t, results, networkID, "network"); reattached.Action != OrphanActionReattach || *reattached.BatchID != early.BatchID {
+			t.Errorf("dry-run network result = %+v, want a planned reattach to %s", reattached, early.BatchID)
+		}
+	})
+
+	t.Run("detach", func(t *testing.T) {
+		results, runs, batches := repair(t, OrphanRepairOptions{Detach: true, Apply: true})
+		for _, result := range results {
+			if result.Action != OrphanActionDetach || result.Err != nil {
+				t.Errorf("result = %+v, want detach", result)
+			}
+		}
+		if len(runs.batchIDs) != len(all) || len(batches.created) != 0 {
+			t.Fatalf("detach updated %d of %d runs and created %d batches", len(runs.batchIDs), len(all), len(batches.created))
+		}
+		for id, batchID := range runs.batchIDs {
+			if batchID != nil {
+				t.Errorf("run %s batch = %s, want it cleared", id, batchID)
+			}
+		}
+	})
+}
+
+func TestGroupOrphanedRunsByUTCDay(t *testing.T) {
+	networkID := uuid.New()
+	sydney := time.FixedZone("AEDT", 11*60*60)
+	runs := []OrphanedRun{
+		// 2026-03-05 08:00 in Sydney is still 2026-03-04 in UTC
+		{QuestionRunID: uuid.New(), NetworkID: &networkID, CreatedAt: time.Date(2026, 3, 5, 8, 0, 0, 0, sydney)},
+		{QuestionRunID: uuid.New(), NetworkID: &networkID, CreatedAt: time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC)},
+		{QuestionRunID: uuid.New(), NetworkID: &networkID, CreatedAt: time.Date(2026, 3, 5, 1, 0, 0, 0, time.UTC)},
+	}
+	groups := GroupOrphanedRuns(runs)
+	if len(groups) != 2 {
+		t.Fatalf("%d groups, want 2: %+v", len(groups), groups)
+	}
+	if !groups[0].Day.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)) || len(groups[0].Runs) != 2 {
+		t.Errorf("first group = %s with %d runs, want 2026-03-04 with 2", groups[0].Day, len(groups[0].Runs))
+	}
+	if len(groups[1].Runs) != 1 || groups[1].Runs[0].QuestionRunID != runs[2].QuestionRunID {
+		t.Errorf("second group = %+v, want the 2026-03-05 run", groups[1])
+	}
+}
@@ -23,25 +23,29 @@ type perplexityProvider struct {
 	baseURL     string
 	costService CostService
 	httpClient  *http.Client
+	poll        PollConfig
 }
 
-func NewPerplexityProvider(cfg *config.Config, model string, costService CostService) AIProvider {
+// NewPerplexityProvider submits to datasetID (see brightDataDatasetsFor);
+// the API key and polling settings come from cfg.
+func NewPerplexityProvider(cfg *config.Config, datasetID string, model string, costService CostService) AIProvider {
 	fmt.Printf("[NewPerplexityProvider] Creating Perplexity provider\n")
 	fmt.Printf("[NewPerplexityProvider]   - API Key: %s\n", maskAPIKey(cfg.BrightDataAPIKey))
-	fmt.Printf("[NewPerplexityProvider]   - Dataset ID: %s\n", cfg.PerplexityDatasetID)
+	fmt.Printf("[NewPerplexityProvider]   - Dataset ID: %s\n", datasetID)
 
-	if cfg.PerplexityDatasetID == "" {
+	if datasetID == "" {
 		fmt.Printf("[NewPerplexityProvider] ⚠️ WARNING: PERPLEXITY_DATASET_ID is empty!\n")
 	}
 
 	return &perplexityProvider{
 		apiKey:      cfg.BrightDataAPIKey,
-		datasetID:   cfg.PerplexityDatasetID,
+		datasetID:   datasetID,
 		baseURL:     "https://api.brightdata.com/datasets/v3",
 		costService: costService,
 		httpClient: &http.Client{
 			Timeout: 20 * time.Minute, // Long timeout for async operations
 		},
+		poll: pollConfigFromConfig(cfg),
 	}
 }
 
@@ -57,6 +61,11 @@ func (p *perplexityProvider) GetProviderName() string {
 	return "perplexity"
 }
 
+// DatasetID is the BrightData dataset jobs are submitted to.
+func (p *perplexityProvider) DatasetID() string {
+	return p.datasetID
+}
+
 // Perplexity API request structures (different from ChatGPT)
 type PerplexityRequest []PerplexityInput
 
@@ -99,7 +108,10 @@ type PerplexityInputEcho struct {
 	Index   int    `json:"index"`
 }
 
-func (p *perplexityProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+func (p *perplexityProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (out *AIResponse, err error) {
+	defer stampLatency(time.Now(), &out)
+	defer sanitizeResponse(&out)
+
 	fmt.Printf("[PerplexityProvider] 🚀 Making Perplexity call for query: %s\n", query)
 
 	// 1. Submit job to Perplexity dataset
@@ -118,20 +130,29 @@ func (p *perplexityProvider) RunQuestion(ctx context.Context, query string, webs
 
 	// 3. Check for errors in response
 	if result.Error != "" {
-		return nil, fmt.Errorf("Perplexity returned error: %s", result.Error)
+		return nil, &ProviderError{
+			Provider: "perplexity",
+			Code:     classifyErrorMessage(result.Error),
+			Detail:   result.Error,
+			Err:      fmt.Errorf("Perplexity returned error: %s", result.Error),
+		}
 	}
 
 	// 4. Handle response - use answer_text_markdown if available, otherwise create failed response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[PerplexityProvider] ⚠️ Perplexity returned error: %s\n", result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[PerplexityProvider] ⚠️ Perplexity returned empty answer_text_markdown\n")
 	} else {
 		responseText = result.AnswerTextMarkdown
@@ -158,6 +179,8 @@ func (p *perplexityProvider) RunQuestion(ctx context.Context, query string, webs
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               citations,
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}, nil
 }
 
@@ -245,41 +268,25 @@ func (p *perplexityProvider) submitJob(ctx context.Context, query string, locati
 	}
 
 	fmt.Printf("[PerplexityProvider] ❌ Trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("Perplexity API returned status %d: %s", lastStatus, lastBody)
+	return "", newHTTPStatusError("Perplexity", lastStatus, lastBody)
 }
 
-func (p *perplexityProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*PerplexityResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[PerplexityProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue // Retry on error
-			}
-
-			fmt.Printf("[PerplexityProvider] 📊 Job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[PerplexityProvider] ✅ Job completed after %d polls, retrieving results\n", pollCount)
-				return p.getResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("Perplexity job failed for snapshot %s", snapshotID)
-			}
-
-			// Continue polling if status is "running" or other non-terminal states
+// WaitForJob polls the job's progress with backoff until it is ready.
+func (p *perplexityProvider) WaitForJob(ctx context.Context, jobID string) error {
+	return pollJobStatus(ctx, p.poll, "PerplexityProvider", jobID, func(ctx context.Context) (string, error) {
+		status, err := p.checkProgress(ctx, jobID)
+		if err != nil {
+			return "", err
 		}
+		return status.Status, nil
+	})
+}
+
+func (p *perplexityProvider) pollUntilComplete(ctx context.Context, snapshotID string) (*PerplexityResult, error) {
+	if err := p.WaitForJob(ctx, snapshotID); err != nil {
+		return nil, err
 	}
+	return p.getResults(ctx, snapshotID)
 }
 
 func (p *perplexityProvider) checkProgress(ctx context.Context, snapshotID string) (*PerplexityProgressResponse, error) {
@@ -412,13 +419,28 @@ func (p *perplexityProvider) SupportsBatching() bool {
 	return true
 }
 
+// SupportsWebSearch returns true for Perplexity (answers are always search-grounded)
+func (p *perplexityProvider) SupportsWebSearch() bool {
+	return true
+}
+
 // GetMaxBatchSize returns 20 for Perplexity (can batch up to 20 questions)
 func (p *perplexityProvider) GetMaxBatchSize() int {
 	return 20
 }
 
-// RunQuestionBatch processes multiple questions in a single Perplexity API call
-func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, queries []string, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+// RunQuestionBatch awaits the batch job, like RunQuestionBatchAwait.
+func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatchAwait(ctx, batch, websearch, location)
+}
+
+// RunQuestionBatchAwait processes multiple questions in a single Perplexity API call:
+// submit, WaitForJob, then retrieve the snapshot.
+func (p *perplexityProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) (out []*AIResponse, err error) {
+	defer stampBatchLatency(time.Now(), &out)
+	defer sanitizeBatchResponses(&out)
+
+	queries := batchQueryTexts(batch)
 	fmt.Printf("[PerplexityProvider] 🚀 Making batched Perplexity call for %d queries\n", len(queries))
 
 	if len(queries) > 20 {
@@ -432,18 +454,12 @@ func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, queries []str
 	}
 	queries = localizedQueries
 
-	// 1. Submit batch job to Perplexity
-	snapshotID, err := p.submitBatchJob(ctx, queries, location)
-	if err != nil {
-		return nil, fmt.Errorf("failed to submit Perplexity batch job: %w", err)
-	}
-
-	fmt.Printf("[PerplexityProvider] 📋 Batch job submitted with snapshot ID: %s\n", snapshotID)
-
-	// 2. Poll until completion
-	results, err := p.pollBatchUntilComplete(ctx, snapshotID)
+	// 1-2. Submit the batch job, wait for it and retrieve its results
+	results, err := awaitJob(ctx, p, "Perplexity", func(ctx context.Context) (string, error) {
+		return p.submitBatchJob(ctx, queries, location)
+	}, p.getBatchResults)
 	if err != nil {
-		return nil, fmt.Errorf("failed to poll Perplexity batch job: %w", err)
+		return nil, err
 	}
 
 	// 3. Match results to queries
@@ -508,7 +524,7 @@ func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, queries []str
 				prompt = result.Input.Prompt // Error results have prompt in input
 			}
 			if prompt != "" {
-				allResults[prompt] = result
+				allResults[normalizeQuestionText(prompt)] = result
 			}
 		}
 		for _, result := range unmatchedResults {
@@ -517,15 +533,16 @@ func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, queries []str
 				prompt = result.Input.Prompt // Error results have prompt in input
 			}
 			if prompt != "" {
-				allResults[prompt] = result
+				allResults[normalizeQuestionText(prompt)] = result
 			}
 		}
 
 		fmt.Printf("[PerplexityProvider] 📊 Built result map with %d prompts\n", len(allResults))
 
-		// Match each query to its result by prompt text
+		// Match each query to its result by prompt text (normalized, so echoed
+		// whitespace or quote changes still match)
 		for i, query := range queries {
-			result, exists := allResults[query]
+			result, exists := allResults[normalizeQuestionText(query)]
 			if !exists {
 				return nil, fmt.Errorf("no result found for query: %q (have %d results)", query, len(allResults))
 			}
@@ -537,6 +554,7 @@ func (p *perplexityProvider) RunQuestionBatch(ctx context.Context, queries []str
 	fmt.Printf("[PerplexityProvider] ✅ Batch completed: %d questions processed, total cost: $%.4f\n",
 		len(responses), float64(len(responses))*0.0015)
 
+	tagBatchResponses(batch, responses)
 	return responses, nil
 }
 
@@ -545,14 +563,18 @@ func (p *perplexityProvider) convertResultToResponse(result *PerplexityResult, d
 	// Handle response
 	var responseText string
 	var shouldProcessEvaluation bool
+	var errorCode ErrorCode
+	var errorDetail string
 
 	if result.Error != "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = classifyErrorMessage(result.Error), result.Error
 		fmt.Printf("[PerplexityProvider] ⚠️ Question %d returned error: %s\n", displayIndex, result.Error)
 	} else if result.AnswerTextMarkdown == "" {
 		responseText = "Question run failed for this model and location"
 		shouldProcessEvaluation = false
+		errorCode, errorDetail = ErrorCodeUnknown, "empty answer_text_markdown"
 		fmt.Printf("[PerplexityProvider] ⚠️ Question %d returned empty answer_text_markdown\n", displayIndex)
 	} else {
 		responseText = result.AnswerTextMarkdown
@@ -572,6 +594,8 @@ func (p *perplexityProvider) convertResultToResponse(result *PerplexityResult, d
 		Cost:                    0.0015, // Fixed cost per API call
 		Citations:               citations,
 		ShouldProcessEvaluation: shouldProcessEvaluation,
+		ErrorCode:               errorCode,
+		ErrorDetail:             errorDetail,
 	}
 }
 
@@ -652,40 +676,7 @@ func (p *perplexityProvider) submitBatchJob(ctx context.Context, queries []strin
 	}
 
 	fmt.Printf("[PerplexityProvider] ❌ Batch trigger failed after %d attempts: status=%d body=%s\n", maxRetries, lastStatus, lastBody)
-	return "", fmt.Errorf("Perplexity API returned status %d: %s", lastStatus, lastBody)
-}
-
-// pollBatchUntilComplete polls for batch completion and returns all results
-func (p *perplexityProvider) pollBatchUntilComplete(ctx context.Context, snapshotID string) ([]PerplexityResult, error) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	// No timeout - let it run as long as needed
-	pollCount := 0
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			pollCount++
-			status, err := p.checkProgress(ctx, snapshotID)
-			if err != nil {
-				fmt.Printf("[PerplexityProvider] ⚠️ Progress check failed (attempt %d), retrying: %v\n", pollCount, err)
-				continue
-			}
-
-			fmt.Printf("[PerplexityProvider] 📊 Batch job status: %s (poll #%d)\n", status.Status, pollCount)
-
-			if status.Status == "ready" {
-				fmt.Printf("[PerplexityProvider] ✅ Batch job completed after %d polls, retrieving results\n", pollCount)
-				return p.getBatchResults(ctx, snapshotID)
-			}
-
-			if status.Status == "failed" {
-				return nil, fmt.Errorf("Perplexity batch job failed for snapshot %s", snapshotID)
-			}
-		}
-	}
+	return "", newHTTPStatusError("Perplexity", lastStatus, lastBody)
 }
 
 // getBatchResults retrieves all results from a completed batch job
@@ -0,0 +1,181 @@
+// services/provider_errors.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go"
+)
+
+// Providers report failures in different shapes: SDK errors with an HTTP
+// status, "API returned status N" errors, or a failed AIResponse whose
+// Response holds a placeholder. ErrorCode classifies all of them so callers
+// can tell a rate limit (worth retrying) from a content policy block (not).
+
+// ErrorCode classifies a provider failure.
+type ErrorCode string
+
+const (
+	ErrorCodeRateLimited    ErrorCode = "rate_limited"
+	ErrorCodeTimeout        ErrorCode = "timeout"
+	ErrorCodeContentPolicy  ErrorCode = "content_policy"
+	ErrorCodeProviderOutage ErrorCode = "provider_outage"
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+	ErrorCodeUnknown        ErrorCode = "unknown"
+	// ErrorCodeUnconfiguredModel marks a configured model no provider routes
+	// (see IsSupportedModel); it is never retryable.
+	ErrorCodeUnconfiguredModel ErrorCode = "unconfigured_model"
+	// ErrorCodeInvalidLocation marks a location whose country isn't a valid
+	// code (see ValidateLocations); it is never retryable.
+	ErrorCodeInvalidLocation ErrorCode = "invalid_location"
+)
+
+// Retryable reports whether the same request may succeed when retried.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorCodeRateLimited, ErrorCodeTimeout, ErrorCodeProviderOutage:
+		return true
+	}
+	return false
+}
+
+// ProviderError is a classified provider failure.
+type ProviderError struct {
+	Provider string
+	Code     ErrorCode
+	Detail   string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s [%s]: %v", e.Provider, e.Code, e.Err)
+	}
+	return fmt.Sprintf("%s [%s]: %s", e.Provider, e.Code, e.Detail)
+}
+
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// Retryable lets retry loops that don't know this package (fixer.Do) skip
+// retrying permanent failures.
+func (e *ProviderError) Retryable() bool { return e.Code.Retryable() }
+
+// newHTTPStatusError classifies a non-2xx provider response.
+func newHTTPStatusError(provider string, status int, body string) error {
+	return &ProviderError{
+		Provider: provider,
+		Code:     errorCodeForStatus(status),
+		Detail:   body,
+		Err:      fmt.Errorf("%s API returned status %d: %s", provider, status, body),
+	}
+}
+
+func errorCodeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrorCodeTimeout
+	case status == http.StatusUnavailableForLegalReasons:
+		return ErrorCodeContentPolicy
+	case status >= 500:
+		return ErrorCodeProviderOutage
+	case status >= 400:
+		return ErrorCodeInvalidRequest
+	}
+	return ErrorCodeUnknown
+}
+
+// ClassifyError returns the ErrorCode of a provider call error.
+func ClassifyError(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Code
+	}
+	if errors.Is(err, ErrContentFiltered) {
+		return ErrorCodeContentPolicy
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorCodeTimeout
+	}
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return errorCodeForStatus(openaiErr.StatusCode)
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return errorCodeForStatus(anthropicErr.StatusCode)
+	}
+	return classifyErrorMessage(err.Error())
+}
+
+// ClassifyProviderError wraps err in a *ProviderError carrying its code;
+// errors that already are one are returned unchanged.
+func ClassifyProviderError(provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return err
+	}
+	return &ProviderError{Provider: provider, Code: ClassifyError(err), Detail: err.Error(), Err: err}
+}
+
+// classifyErrorMessage maps free-text provider errors (e.g. BrightData's
+// per-record "error" field) by keyword.
+func classifyErrorMessage(msg string) ErrorCode {
+	msg = strings.ToLower(msg)
+	switch {
+	case containsAny(msg, "rate limit", "too many requests", "quota"):
+		return ErrorCodeRateLimited
+	case containsAny(msg, "timeout", "timed out", "deadline"):
+		return ErrorCodeTimeout
+	case containsAny(msg, "content policy", "content_filter", "content filter", "blocked", "refused", "safety"):
+		return ErrorCodeContentPolicy
+	case containsAny(msg, "unavailable", "internal error", "internal server error", "bad gateway", "overloaded"):
+		return ErrorCodeProviderOutage
+	case containsAny(msg, "invalid", "bad request", "not found", "dataset"):
+		return ErrorCodeInvalidRequest
+	}
+	return ErrorCodeUnknown
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// markFailed records a failed response: it is not processed and carries the
+// classified code and the provider's native detail.
+func (r *AIResponse) markFailed(code ErrorCode, detail string) {
+	r.ShouldProcessEvaluation = false
+	r.ErrorCode = code
+	r.ErrorDetail = detail
+}
+
+// Err returns the response's failure as a *ProviderError, or nil when the
+// response is usable or carries no code.
+func (r *AIResponse) Err(provider string) error {
+	if r == nil || r.ShouldProcessEvaluation || r.ErrorCode == "" {
+		return nil
+	}
+	return &ProviderError{Provider: provider, Code: r.ErrorCode, Detail: r.ErrorDetail}
+}
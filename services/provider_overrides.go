@@ -0,0 +1,79 @@
+// services/provider_overrides.go
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// overrideProvider returns the provider configured for model in
+// cfg.ModelProviderOverrides, then in the providers config, or nil when
+// neither routes it. When several substrings match, the longest one wins,
+// and among equally long ones the lexically smallest, so routing stays
+// deterministic.
+func overrideProvider(cfg *config.Config, datasets config.BrightDataDatasets, model string, costService CostService) (AIProvider, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.ModelProviderOverrides) == 0 {
+		return configuredProvider(cfg, datasets, model, costService)
+	}
+
+	modelLower := strings.ToLower(model)
+	matched := ""
+	for substr := range cfg.ModelProviderOverrides {
+		if !strings.Contains(modelLower, substr) {
+			continue
+		}
+		if matched == "" || len(substr) > len(matched) || (len(substr) == len(matched) && substr < matched) {
+			matched = substr
+		}
+	}
+	if matched == "" {
+		return configuredProvider(cfg, datasets, model, costService)
+	}
+
+	providerKey := cfg.ModelProviderOverrides[matched]
+	fmt.Printf("[getProvider] 🔀 Override %q -> %s for model: %s\n", matched, providerKey, model)
+	return newProviderByKey(cfg, datasets, providerKey, model, costService)
+}
+
+// configuredProvider returns the provider of the model's providers config
+// entry, or nil when the model has no entry with a provider.
+func configuredProvider(cfg *config.Config, datasets config.BrightDataDatasets, model string, costService CostService) (AIProvider, error) {
+	key, entry, ok := providerModelConfig(model)
+	if !ok || entry.Provider == "" {
+		return nil, nil
+	}
+	fmt.Printf("[getProvider] 📄 Providers config %q -> %s for model: %s\n", key, entry.Provider, model)
+	return newProviderByKey(cfg, datasets, entry.Provider, model, costService)
+}
+
+// newProviderByKey constructs a provider from its config key; the
+// BrightData-backed providers submit to their dataset in datasets.
+func newProviderByKey(cfg *config.Config, datasets config.BrightDataDatasets, providerKey, model string, costService CostService) (AIProvider, error) {
+	switch providerKey {
+	case "brightdata", "chatgpt":
+		return NewBrightDataProvider(cfg, datasets.BrightDataDatasetID, model, costService), nil
+	case "perplexity":
+		return NewPerplexityProvider(cfg, datasets.PerplexityDatasetID, model, costService), nil
+	case "gemini":
+		return NewGeminiProvider(cfg, datasets.GeminiDatasetID, model, costService), nil
+	case "linkup":
+		if cfg.LinkupAPIKey == "" {
+			return nil, fmt.Errorf("Linkup API key is empty in config")
+		}
+		return NewLinkupProvider(cfg, model, costService), nil
+	case "openai":
+		if cfg.OpenAIAPIKey == "" && cfg.AzureOpenAIKey == "" {
+			return nil, fmt.Errorf("OpenAI API key is empty in config")
+		}
+		return NewOpenAIProvider(cfg, model, costService), nil
+	case "anthropic":
+		return NewAnthropicProvider(cfg, model, costService), nil
+	default:
+		return nil, fmt.Errorf("unknown provider override %q for model: %s", providerKey, model)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+// routedTo describes a provider as "name" or "name:dataset" for the
+// BrightData-backed providers.
+func routedTo(p AIProvider) string {
+	if p == nil {
+		return ""
+	}
+	name := p.(interface{ GetProviderName() string }).GetProviderName()
+	switch p := p.(type) {
+	case *brightDataProvider:
+		return name + ":" + p.datasetID
+	case *perplexityProvider:
+		return name + ":" + p.datasetID
+	case *geminiProvider:
+		return name + ":" + p.datasetID
+	}
+	return name
+}
+
+func TestOverrideProvider(t *testing.T) {
+	datasets := config.BrightDataDatasets{BrightDataDatasetID: "gd_chatgpt", PerplexityDatasetID: "gd_pplx", GeminiDatasetID: "gd_gemini"}
+	keys := config.Config{OpenAIAPIKey: "sk-test", LinkupAPIKey: "linkup-test"}
+	withOverrides := func(overrides map[string]string) *config.Config {
+		cfg := keys
+		cfg.ModelProviderOverrides = overrides
+		return &cfg
+	}
+	providersConfig := &ProvidersConfig{Models: map[string]ModelProviderConfig{
+		"gpt-5":     {Provider: "openai"},
+		"sonar-pro": {Provider: "perplexity"},
+		"claude":    {MaxOutputTokens: 2000}, // pricing or limits only, no routing
+	}}
+
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		providers *ProvidersConfig
+		model     string
+		want      string
+		wantErr   string
+	}{
+		{name: "nil config", cfg: nil, model: "gpt-4.1", want: ""},
+		{name: "no overrides or providers config", cfg: withOverrides(nil), model: "gpt-4.1", want: ""},
+		{name: "override substring", cfg: withOverrides(map[string]string{"gpt": "brightdata"}), model: "GPT-4.1", want: "brightdata:gd_chatgpt"},
+		{name: "chatgpt alias", cfg: withOverrides(map[string]string{"4o": "chatgpt"}), model: "chatgpt-4o", want: "brightdata:gd_chatgpt"},
+		{
+			name:  "longest override wins",
+			cfg:   withOverrides(map[string]string{"gpt": "brightdata", "gpt-4.1": "openai", "4.1": "linkup"}),
+			model: "gpt-4.1-mini",
+			want:  "openai",
+		},
+		{
+			name:  "equally long overrides break ties lexically",
+			cfg:   withOverrides(map[string]string{"gpt-": "linkup", "-4.1": "openai"}),
+			model: "gpt-4.1",
+			want:  "openai",
+		},
+		{name: "gemini dataset", cfg: withOverrides(map[string]string{"gemini": "gemini"}), model: "gemini-2.5-flash", want: "gemini:gd_gemini"},
+		{name: "unmatched model", cfg: withOverrides(map[string]string{"sonar": "perplexity"}), model: "claude-sonnet-4", want: ""},
+		{name: "unknown provider key", cfg: withOverrides(map[string]string{"gpt": "bing"}), model: "gpt-4.1", wantErr: `unknown provider override "bing"`},
+		{name: "openai without a key", cfg: &config.Config{ModelProviderOverrides: map[string]string{"gpt": "openai"}}, model: "gpt-4.1", wantErr: "OpenAI API key is empty"},
+		{name: "linkup without a key", cfg: &config.Config{ModelProviderOverrides: map[string]string{"gpt": "linkup"}}, model: "gpt-4.1", wantErr: "Linkup API key is empty"},
+		{name: "providers config routes unmatched model", cfg: withOverrides(nil), providers: providersConfig, model: "sonar-pro", want: "perplexity:gd_pplx"},
+		{name: "providers config prefix match", cfg: withOverrides(nil), providers: providersConfig, model: "gpt-5.2", want: "openai"},
+		{name: "override beats providers config", cfg: withOverrides(map[string]string{"sonar": "brightdata"}), providers: providersConfig, model: "sonar-pro", want: "brightdata:gd_chatgpt"},
+		{name: "providers config entry without provider", cfg: withOverrides(nil), providers: providersConfig, model: "claude-sonnet-4", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order varies between runs; repeat to catch
+			// routing that depends on it
+			for i := 0; i < 20; i++ {
+				if routed := routeOnce(t, tt.cfg, tt.providers, datasets, tt.model, tt.wantErr); routed != tt.want && tt.wantErr == "" {
+					t.Fatalf("overrideProvider() routed to %q, want %q", routed, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// routeOnce runs overrideProvider with providers as the active providers
+// config and returns where it routed model.
+func routeOnce(t *testing.T, cfg *config.Config, providers *ProvidersConfig, datasets config.BrightDataDatasets, model, wantErr string) string {
+	t.Helper()
+	SetProvidersConfig(providers)
+	defer SetProvidersConfig(nil)
+
+	got, err := overrideProvider(cfg, datasets, model, NewCostService(nil))
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("overrideProvider() error = %v, want one containing %q", err, wantErr)
+		}
+		return ""
+	}
+	if err != nil {
+		t.Fatalf("overrideProvider() error = %v", err)
+	}
+	return routedTo(got)
+}
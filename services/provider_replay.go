@@ -0,0 +1,283 @@
+// services/provider_replay.go
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+// Record/replay of provider responses, for deterministic offline runs of the
+// pipeline. With PROVIDER_RECORD_DIR set, getProvider wraps every provider in
+// a RecordingProvider that saves each successful response as a fixture; with
+// PROVIDER_REPLAY_DIR set, it returns a ReplayProvider that answers from those
+// fixtures and never touches the network. Fixtures are keyed by model, query,
+// web search and location (FixtureRequest.Key), so the same question asked of
+// another model or location never replays the wrong answer. Only the question
+// call is replayed: extraction calls still go to their configured model.
+
+// ErrNoFixture is returned by ReplayProvider for a request without a fixture.
+var ErrNoFixture = errors.New("no replay fixture")
+
+// FixtureRequest is what a fixture answers.
+type FixtureRequest struct {
+	Model     string                   `json:"model"`
+	Query     string                   `json:"query"`
+	WebSearch bool                     `json:"web_search"`
+	Location  *workflowModels.Location `json:"location,omitempty"`
+}
+
+// Key is the request's fixture key: the hex SHA-256 of the model
+// (case-insensitive), query, web search flag and location country, region
+// and city.
+func (r FixtureRequest) Key() string {
+	var country, region, city string
+	if r.Location != nil {
+		country = strings.ToUpper(strings.TrimSpace(r.Location.Country))
+		region = stringOrEmpty(r.Location.Region)
+		city = stringOrEmpty(r.Location.City)
+	}
+	parts := []string{strings.ToLower(strings.TrimSpace(r.Model)), r.Query, strconv.FormatBool(r.WebSearch), country, region, city}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fixture is one saved response, stored as <Request.Key()>.json.
+type Fixture struct {
+	Request  FixtureRequest `json:"request"`
+	Response *AIResponse    `json:"response"`
+}
+
+// SaveFixture writes resp for req to dir, replacing any earlier fixture of
+// the request. The batch correlation ID is not saved.
+func SaveFixture(dir string, req FixtureRequest, resp *AIResponse) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture dir: %w", err)
+	}
+	saved := *resp
+	saved.CorrelationID = ""
+	data, err := json.MarshalIndent(Fixture{Request: req, Response: &saved}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, req.Key()+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// LoadFixture reads the fixture of req from dir. A missing fixture is
+// ErrNoFixture; a file whose request doesn't hash to its name is an error, so
+// hand-edited fixtures can't silently answer another request.
+func LoadFixture(dir string, req FixtureRequest) (*AIResponse, error) {
+	key := req.Key()
+	path := filepath.Join(dir, key+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w %s (model %s, query %q)", ErrNoFixture, key, req.Model, req.Query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("%s: invalid fixture: %w", path, err)
+	}
+	if fixture.Response == nil {
+		return nil, fmt.Errorf("%s: fixture has no response", path)
+	}
+	if fixture.Request.Key() != key {
+		return nil, fmt.Errorf("%s: fixture request does not match its file name", path)
+	}
+	return fixture.Response, nil
+}
+
+// ReplayProvider is an AIProvider that answers Model's questions from the
+// fixtures in Dir. Requests without a fixture fail with ErrNoFixture and are
+// listed by Misses. It is safe for concurrent use.
+type ReplayProvider struct {
+	Dir   string
+	Model string
+	// WebSearch is returned by SupportsWebSearch.
+	WebSearch bool
+	// Batching and MaxBatchSize are returned by SupportsBatching and GetMaxBatchSize.
+	Batching     bool
+	MaxBatchSize int
+
+	mu     sync.Mutex
+	misses []FixtureRequest
+}
+
+var _ AIProvider = (*ReplayProvider)(nil)
+
+// NewReplayProvider replays model from dir with the capabilities of live, the
+// provider it stands in for, so the pipeline takes the same batch and web
+// search paths as the recorded run.
+func NewReplayProvider(dir, model string, live AIProvider) *ReplayProvider {
+	p := &ReplayProvider{Dir: dir, Model: model}
+	if live != nil {
+		caps := ProviderCapabilities(live)
+		p.WebSearch = caps.WebSearch
+		p.Batching = caps.Batch
+		p.MaxBatchSize = live.GetMaxBatchSize()
+	}
+	return p
+}
+
+// Misses returns the requests asked so far that had no fixture, in order.
+func (p *ReplayProvider) Misses() []FixtureRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]FixtureRequest(nil), p.misses...)
+}
+
+func (p *ReplayProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.replay(FixtureRequest{Model: p.Model, Query: query, WebSearch: websearch, Location: location})
+}
+
+func (p *ReplayProvider) RunQuestionWebSearch(ctx context.Context, query string) (*AIResponse, error) {
+	return p.RunQuestion(ctx, query, true, nil)
+}
+
+func (p *ReplayProvider) SupportsWebSearch() bool {
+	return p.WebSearch
+}
+
+func (p *ReplayProvider) SupportsBatching() bool {
+	return p.Batching
+}
+
+func (p *ReplayProvider) GetMaxBatchSize() int {
+	return p.MaxBatchSize
+}
+
+// RunQuestionBatch replays every query, tagging each response with its
+// CorrelationID. A query without a fixture fails the whole batch.
+func (p *ReplayProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	if !p.Batching {
+		return nil, fmt.Errorf("replay provider: batching not enabled")
+	}
+	if p.MaxBatchSize > 0 && len(batch) > p.MaxBatchSize {
+		return nil, fmt.Errorf("replay provider: batch of %d exceeds max batch size %d", len(batch), p.MaxBatchSize)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*AIResponse, 0, len(batch))
+	for _, q := range batch {
+		resp, err := p.replay(FixtureRequest{Model: p.Model, Query: q.Query, WebSearch: websearch, Location: location})
+		if err != nil {
+			return nil, err
+		}
+		resp.CorrelationID = q.CorrelationID
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// RunQuestionBatchAwait calls RunQuestionBatch: fixtures are replayed synchronously.
+func (p *ReplayProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	return p.RunQuestionBatch(ctx, batch, websearch, location)
+}
+
+func (p *ReplayProvider) replay(req FixtureRequest) (*AIResponse, error) {
+	resp, err := LoadFixture(p.Dir, req)
+	if errors.Is(err, ErrNoFixture) {
+		p.mu.Lock()
+		p.misses = append(p.misses, req)
+		p.mu.Unlock()
+	}
+	return resp, err
+}
+
+// RecordingProvider passes every call to the wrapped provider and saves each
+// successful response to Dir as a fixture of Model for ReplayProvider. A
+// fixture that can't be saved is logged; the response is still returned.
+type RecordingProvider struct {
+	AIProvider
+	Dir   string
+	Model string
+}
+
+func (p *RecordingProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+	resp, err := p.AIProvider.RunQuestion(ctx, query, websearch, location)
+	if err == nil && resp != nil {
+		p.save(FixtureRequest{Model: p.Model, Query: query, WebSearch: websearch, Location: location}, resp)
+	}
+	return resp, err
+}
+
+func (p *RecordingProvider) RunQuestionWebSearch(ctx context.Context, query string) (*AIResponse, error) {
+	resp, err := p.AIProvider.RunQuestionWebSearch(ctx, query)
+	if err == nil && resp != nil {
+		p.save(FixtureRequest{Model: p.Model, Query: query, WebSearch: true}, resp)
+	}
+	return resp, err
+}
+
+// RunQuestionBatch saves each response under the query with its CorrelationID.
+func (p *RecordingProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	responses, err := p.AIProvider.RunQuestionBatch(ctx, batch, websearch, location)
+	return p.saveBatch(batch, websearch, location, responses, err)
+}
+
+// RunQuestionBatchAwait saves each response like RunQuestionBatch.
+func (p *RecordingProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	responses, err := p.AIProvider.RunQuestionBatchAwait(ctx, batch, websearch, location)
+	return p.saveBatch(batch, websearch, location, responses, err)
+}
+
+func (p *RecordingProvider) saveBatch(batch []BatchQuery, websearch bool, location *workflowModels.Location, responses []*AIResponse, err error) ([]*AIResponse, error) {
+	if err != nil {
+		return responses, err
+	}
+	queries := make(map[string]string, len(batch))
+	for _, q := range batch {
+		queries[q.CorrelationID] = q.Query
+	}
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if query, ok := queries[resp.CorrelationID]; ok {
+			p.save(FixtureRequest{Model: p.Model, Query: query, WebSearch: websearch, Location: location}, resp)
+		}
+	}
+	return responses, nil
+}
+
+func (p *RecordingProvider) save(req FixtureRequest, resp *AIResponse) {
+	if err := SaveFixture(p.Dir, req, resp); err != nil {
+		fmt.Printf("[RecordingProvider] Warning: failed to save fixture for %q: %v\n", req.Query, err)
+	}
+}
+
+// withReplay applies PROVIDER_REPLAY_DIR / PROVIDER_RECORD_DIR to the
+// provider getProvider chose for model.
+func withReplay(cfg *config.Config, provider AIProvider, model string) AIProvider {
+	if cfg == nil || provider == nil {
+		return provider
+	}
+	if cfg.ProviderReplayDir != "" {
+		return NewReplayProvider(cfg.ProviderReplayDir, model, provider)
+	}
+	if cfg.ProviderRecordDir != "" {
+		return &RecordingProvider{AIProvider: provider, Dir: cfg.ProviderRecordDir, Model: model}
+	}
+	return provider
+}
@@ -0,0 +1,226 @@
+// services/providers_config.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Model routing (getProvider), capabilities and pricing (CostService) can be
+// defined together in one providers config, loaded at startup from
+// --providers-config / PROVIDERS_CONFIG_FILE (JSON, or YAML for .yaml/.yml):
+//
+//	models:
+//	  gpt-5.2:
+//	    provider: openai
+//	    max_output_tokens: 4000
+//	    capabilities: {batch: false, web_search: true}
+//	    pricing: {input: 1.75, cached: 0.175, output: 14.00, web_search_per_1k: 10.00}
+//	  sonar-pro:
+//	    provider: perplexity
+//	    capabilities: {async: true, batch: true, web_search: true}
+//	    pricing: {input: 3.00, output: 15.00}
+//
+// Model keys match the model name exactly or as its longest prefix, like the
+// built-in pricing table. Every part of an entry is optional: routing falls
+// back to MODEL_PROVIDER_OVERRIDES and the built-in model-name rules,
+// capabilities to what the provider reports, pricing to the built-in table,
+// max_output_tokens to the provider's current default.
+// Token prices are per 1M tokens, web search per 1000 searches.
+
+// ProvidersConfig is the parsed providers config file.
+type ProvidersConfig struct {
+	Models map[string]ModelProviderConfig `json:"models" yaml:"models"`
+}
+
+// ModelProviderConfig is one model's entry.
+type ModelProviderConfig struct {
+	// Provider is a provider key (see newProviderByKey).
+	Provider string `json:"provider" yaml:"provider"`
+	// MaxOutputTokens caps the answer length sent with the provider request
+	// (Responses API max_output_tokens, chat max_tokens); 0 keeps the default.
+	MaxOutputTokens int                   `json:"max_output_tokens" yaml:"max_output_tokens"`
+	Capabilities    *ModelCapabilityFlags `json:"capabilities" yaml:"capabilities"`
+	Pricing         *ModelPricing         `json:"pricing" yaml:"pricing"`
+}
+
+// ModelCapabilityFlags overrides what the provider reports; nil keeps it.
+type ModelCapabilityFlags struct {
+	Async     *bool `json:"async" yaml:"async"`
+	Batch     *bool `json:"batch" yaml:"batch"`
+	WebSearch *bool `json:"web_search" yaml:"web_search"`
+}
+
+// ModelPricing is a model's rates. Cached 0 bills cached input at Input;
+// WebSearchPer1K nil uses the provider's built-in search rate.
+type ModelPricing struct {
+	Input          float64  `json:"input" yaml:"input"`
+	Cached         float64  `json:"cached" yaml:"cached"`
+	Output         float64  `json:"output" yaml:"output"`
+	WebSearchPer1K *float64 `json:"web_search_per_1k" yaml:"web_search_per_1k"`
+}
+
+// providerKeys are the keys newProviderByKey accepts.
+var providerKeys = map[string]bool{
+	"brightdata": true, "chatgpt": true, "perplexity": true, "gemini": true,
+	"linkup": true, "openai": true, "anthropic": true,
+}
+
+// activeProvidersConfig is the config loaded at startup; nil when none.
+var activeProvidersConfig atomic.Pointer[ProvidersConfig]
+
+// LoadProvidersConfig reads and validates the providers config at path and
+// makes it the active one. An empty path clears it (code defaults only).
+func LoadProvidersConfig(path string) (*ProvidersConfig, error) {
+	if strings.TrimSpace(path) == "" {
+		SetProvidersConfig(nil)
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read providers config: %w", err)
+	}
+
+	var raw ProvidersConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse providers config: %w", err)
+	}
+
+	cfg, err := raw.normalize()
+	if err != nil {
+		return nil, fmt.Errorf("invalid providers config %s: %w", path, err)
+	}
+	SetProvidersConfig(cfg)
+	return cfg, nil
+}
+
+// SetProvidersConfig makes cfg the active providers config (nil clears it).
+func SetProvidersConfig(cfg *ProvidersConfig) {
+	activeProvidersConfig.Store(cfg)
+}
+
+// normalize lowercases keys and validates every entry.
+func (c ProvidersConfig) normalize() (*ProvidersConfig, error) {
+	out := &ProvidersConfig{Models: make(map[string]ModelProviderConfig, len(c.Models))}
+	for model, entry := range c.Models {
+		key := strings.ToLower(strings.TrimSpace(model))
+		if key == "" {
+			return nil, fmt.Errorf("empty model key")
+		}
+		if _, dup := out.Models[key]; dup {
+			return nil, fmt.Errorf("model %q is defined more than once", key)
+		}
+		entry.Provider = strings.ToLower(strings.TrimSpace(entry.Provider))
+		if entry.Provider != "" && !providerKeys[entry.Provider] {
+			return nil, fmt.Errorf("unknown provider %q for model %q", entry.Provider, key)
+		}
+		if entry.MaxOutputTokens < 0 {
+			return nil, fmt.Errorf("negative max_output_tokens for model %q", key)
+		}
+		if p := entry.Pricing; p != nil {
+			if p.Input < 0 || p.Cached < 0 || p.Output < 0 || (p.WebSearchPer1K != nil && *p.WebSearchPer1K < 0) {
+				return nil, fmt.Errorf("negative price for model %q", key)
+			}
+		}
+		out.Models[key] = entry
+	}
+	return out, nil
+}
+
+// providerModelConfig returns the active entry for model: an exact key match,
+// else the longest key the model name starts with.
+func providerModelConfig(model string) (string, ModelProviderConfig, bool) {
+	cfg := activeProvidersConfig.Load()
+	if cfg == nil {
+		return "", ModelProviderConfig{}, false
+	}
+	modelKey := strings.ToLower(strings.TrimSpace(model))
+	if entry, ok := cfg.Models[modelKey]; ok {
+		return modelKey, entry, true
+	}
+	matched := ""
+	for k := range cfg.Models {
+		if strings.HasPrefix(modelKey, k) && len(k) > len(matched) {
+			matched = k
+		}
+	}
+	if matched == "" {
+		return "", ModelProviderConfig{}, false
+	}
+	return matched, cfg.Models[matched], true
+}
+
+// maxOutputTokens returns the model's configured output cap, or fallback (the
+// provider's default, 0 for none) when the providers config sets none.
+func maxOutputTokens(model string, fallback int) int {
+	if _, entry, ok := providerModelConfig(model); ok && entry.MaxOutputTokens > 0 {
+		return entry.MaxOutputTokens
+	}
+	return fallback
+}
+
+// Capabilities is what a provider can do for a model.
+type Capabilities struct {
+	// Async providers submit jobs and poll for them (AsyncJobWaiter).
+	Async     bool `json:"async"`
+	Batch     bool `json:"batch"`
+	WebSearch bool `json:"web_search"`
+}
+
+// ProviderCapabilities returns the provider's capabilities, including the
+// overrides of the model's providers config entry.
+func ProviderCapabilities(provider AIProvider) Capabilities {
+	if p, ok := provider.(*capabilityProvider); ok {
+		return p.caps
+	}
+	_, async := provider.(AsyncJobWaiter)
+	return Capabilities{
+		Async:     async,
+		Batch:     provider.SupportsBatching(),
+		WebSearch: provider.SupportsWebSearch(),
+	}
+}
+
+// capabilityProvider reports the capabilities set in the providers config
+// instead of the wrapped provider's own.
+type capabilityProvider struct {
+	AIProvider
+	caps Capabilities
+}
+
+func (p *capabilityProvider) SupportsBatching() bool  { return p.caps.Batch }
+func (p *capabilityProvider) SupportsWebSearch() bool { return p.caps.WebSearch }
+
+// withConfiguredCapabilities applies the capability overrides of the model's
+// entry to provider. Async only describes the provider and can't be switched,
+// so a mismatch is logged and the provider's own value kept.
+func withConfiguredCapabilities(provider AIProvider, model string) AIProvider {
+	_, entry, ok := providerModelConfig(model)
+	if !ok || entry.Capabilities == nil || provider == nil {
+		return provider
+	}
+	caps := ProviderCapabilities(provider)
+	flags := entry.Capabilities
+	if flags.Async != nil && *flags.Async != caps.Async {
+		fmt.Printf("[getProvider] Warning: providers config sets async=%t for %s but the provider is async=%t\n", *flags.Async, model, caps.Async)
+	}
+	if flags.Batch != nil {
+		caps.Batch = *flags.Batch
+	}
+	if flags.WebSearch != nil {
+		caps.WebSearch = *flags.WebSearch
+	}
+	return &capabilityProvider{AIProvider: provider, caps: caps}
+}
@@ -0,0 +1,61 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+	"github.com/google/uuid"
+)
+
+// runRows counts the question runs stored.
+type runRows struct {
+	interfaces.QuestionRunRepository
+	created int
+}
+
+func (r *runRows) Create(ctx context.Context, run *models.QuestionRun) error {
+	r.created++
+	return nil
+}
+
+// TestProcessSingleQuestionRejectsEmptyResponse checks that an org question
+// answered with an empty or too short response stores no run.
+func TestProcessSingleQuestionRejectsEmptyResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{name: "empty", response: ""},
+		{name: "whitespace", response: " \n "},
+		{name: "below minimum", response: "Sorry."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &FakeProvider{WebSearch: true, Default: &services.AIResponse{Response: tt.response}}
+			runs := &runRows{}
+			runner := services.NewQuestionRunnerServiceWithProviders(&config.Config{MinExtractionResponseChars: 20},
+				&services.RepositoryManager{QuestionRunRepo: runs}, nil, nil,
+				func(model string) (services.AIProvider, error) { return provider, nil })
+
+			question := &models.GeoQuestion{GeoQuestionID: uuid.New(), QuestionText: "Best bank?"}
+			model := &models.GeoModel{GeoModelID: uuid.New(), Name: "gpt-4.1"}
+			location := &models.OrgLocation{OrgLocationID: uuid.New(), CountryCode: "US"}
+			_, err := runner.ProcessSingleQuestion(context.Background(), question, nil, model, location, "Acme", nil)
+
+			if len(provider.Calls()) != 1 {
+				t.Fatalf("provider calls = %d, want 1", len(provider.Calls()))
+			}
+			if !errors.Is(err, services.ErrEmptyResponse) {
+				t.Errorf("ProcessSingleQuestion() error = %v, want ErrEmptyResponse", err)
+			}
+			if runs.created != 0 {
+				t.Errorf("%d runs stored for an empty response, want 0", runs.created)
+			}
+		})
+	}
+}
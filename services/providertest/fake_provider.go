@@ -0,0 +1,155 @@
+// services/providertest/fake_provider.go
+package providertest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// FakeProvider is a programmable services.AIProvider for tests and local
+// tooling: canned responses per query, injected errors, simulated latency and
+// configurable batching/web-search support. Calls are recorded. The zero value
+// answers every query with a generic response; it is safe for concurrent use.
+type FakeProvider struct {
+	// Responses maps a query to its canned response; copies are returned.
+	Responses map[string]*services.AIResponse
+	// Default answers queries without a canned response; when nil the
+	// response text is "fake response to: <query>".
+	Default *services.AIResponse
+	// Errors maps a query to the error returned for it.
+	Errors map[string]error
+	// Err, when set, is returned for every query.
+	Err error
+	// Latency delays every call (and each batch once), honouring ctx.
+	Latency time.Duration
+
+	// WebSearch is returned by SupportsWebSearch.
+	WebSearch bool
+	// Batching and MaxBatchSize are returned by SupportsBatching and GetMaxBatchSize.
+	Batching     bool
+	MaxBatchSize int
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call is one query the fake was asked, batched or not.
+type Call struct {
+	Query     string
+	WebSearch bool
+	Location  *workflowModels.Location
+	Batched   bool
+}
+
+var _ services.AIProvider = (*FakeProvider)(nil)
+
+// Calls returns a copy of the calls made so far, in order.
+func (f *FakeProvider) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+func (f *FakeProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*services.AIResponse, error) {
+	f.record(Call{Query: query, WebSearch: websearch, Location: location})
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.respond(query)
+}
+
+func (f *FakeProvider) RunQuestionWebSearch(ctx context.Context, query string) (*services.AIResponse, error) {
+	return f.RunQuestion(ctx, query, true, nil)
+}
+
+func (f *FakeProvider) SupportsWebSearch() bool {
+	return f.WebSearch
+}
+
+func (f *FakeProvider) SupportsBatching() bool {
+	return f.Batching
+}
+
+func (f *FakeProvider) GetMaxBatchSize() int {
+	return f.MaxBatchSize
+}
+
+// RunQuestionBatch answers every query like RunQuestion, tagging each response
+// with its CorrelationID. Any query's error fails the whole batch, as it does
+// for the real batch providers.
+func (f *FakeProvider) RunQuestionBatch(ctx context.Context, batch []services.BatchQuery, websearch bool, location *workflowModels.Location) ([]*services.AIResponse, error) {
+	if !f.Batching {
+		return nil, fmt.Errorf("fake provider: batching not enabled")
+	}
+	if f.MaxBatchSize > 0 && len(batch) > f.MaxBatchSize {
+		return nil, fmt.Errorf("fake provider: batch of %d exceeds max batch size %d", len(batch), f.MaxBatchSize)
+	}
+	for _, q := range batch {
+		f.record(Call{Query: q.Query, WebSearch: websearch, Location: location, Batched: true})
+	}
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*services.AIResponse, 0, len(batch))
+	for _, q := range batch {
+		resp, err := f.respond(q.Query)
+		if err != nil {
+			return nil, err
+		}
+		resp.CorrelationID = q.CorrelationID
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// RunQuestionBatchAwait calls RunQuestionBatch: the fake answers synchronously.
+func (f *FakeProvider) RunQuestionBatchAwait(ctx context.Context, batch []services.BatchQuery, websearch bool, location *workflowModels.Location) ([]*services.AIResponse, error) {
+	return f.RunQuestionBatch(ctx, batch, websearch, location)
+}
+
+func (f *FakeProvider) record(call Call) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *FakeProvider) wait(ctx context.Context) error {
+	if f.Latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(f.Latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (f *FakeProvider) respond(query string) (*services.AIResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err, ok := f.Errors[query]; ok {
+		return nil, err
+	}
+	if resp, ok := f.Responses[query]; ok {
+		copied := *resp
+		return &copied, nil
+	}
+	if f.Default != nil {
+		copied := *f.Default
+		return &copied, nil
+	}
+	return &services.AIResponse{
+		Response:                "fake response to: " + query,
+		ShouldProcessEvaluation: true,
+	}, nil
+}
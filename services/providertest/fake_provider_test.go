@@ -0,0 +1,138 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// TestQuestionRunnerWithFakeProvider drives the question runner's ad-hoc path
+// (normalization, routing, web search policy) through FakeProvider.
+func TestQuestionRunnerWithFakeProvider(t *testing.T) {
+	errOutage := errors.New("provider outage")
+	region := "California"
+	location := &workflowModels.Location{Country: "US", Region: &region}
+
+	tests := []struct {
+		name          string
+		policy        string
+		fake          *FakeProvider
+		model         string
+		question      string
+		webSearch     bool
+		wantResponse  string
+		wantCalls     []Call
+		wantErr       error
+		wantErrText   string
+		wantDowngrade bool
+	}{
+		{
+			name:         "canned response for the normalized question",
+			fake:         &FakeProvider{WebSearch: true, Responses: map[string]*services.AIResponse{"Best bank in California?": {Response: "Acme Bank"}}},
+			model:        "fake-model",
+			question:     "  Best bank in {{region}}?  ",
+			webSearch:    true,
+			wantResponse: "Acme Bank",
+			wantCalls:    []Call{{Query: "Best bank in California?", WebSearch: true, Location: location}},
+		},
+		{
+			name:         "smart quotes replaced before the call",
+			fake:         &FakeProvider{WebSearch: true},
+			model:        "fake-model",
+			question:     "What’s the best bank?",
+			webSearch:    true,
+			wantResponse: "fake response to: What's the best bank?",
+			wantCalls:    []Call{{Query: "What's the best bank?", WebSearch: true, Location: location}},
+		},
+		{
+			name:          "downgrade policy runs without search",
+			policy:        services.WebSearchPolicyDowngrade,
+			fake:          &FakeProvider{},
+			model:         "fake-model",
+			question:      "Best bank?",
+			webSearch:     true,
+			wantResponse:  "fake response to: Best bank?",
+			wantCalls:     []Call{{Query: "Best bank?", WebSearch: false, Location: location}},
+			wantDowngrade: true,
+		},
+		{
+			name:      "skip policy rejects search without a call",
+			fake:      &FakeProvider{},
+			model:     "fake-model",
+			question:  "Best bank?",
+			webSearch: true,
+			wantErr:   services.ErrWebSearchUnsupported,
+		},
+		{
+			name:         "no search needs no support",
+			fake:         &FakeProvider{},
+			model:        "fake-model",
+			question:     "Best bank?",
+			wantResponse: "fake response to: Best bank?",
+			wantCalls:    []Call{{Query: "Best bank?", Location: location}},
+		},
+		{
+			name:      "provider error is returned",
+			fake:      &FakeProvider{WebSearch: true, Errors: map[string]error{"Best bank?": errOutage}},
+			model:     "fake-model",
+			question:  "Best bank?",
+			webSearch: true,
+			wantErr:   errOutage,
+			wantCalls: []Call{{Query: "Best bank?", WebSearch: true, Location: location}},
+		},
+		{
+			name:        "unresolved placeholder never reaches the provider",
+			fake:        &FakeProvider{WebSearch: true},
+			model:       "fake-model",
+			question:    "Best bank in {{city}}?",
+			webSearch:   true,
+			wantErrText: "unsubstituted placeholders",
+		},
+		{
+			name:        "unknown model",
+			fake:        &FakeProvider{WebSearch: true},
+			model:       "other-model",
+			question:    "Best bank?",
+			webSearch:   true,
+			wantErrText: "failed to get provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{UnsupportedWebSearchPolicy: tt.policy}
+			runner := services.NewQuestionRunnerServiceWithProviders(cfg, nil, nil, nil, func(model string) (services.AIProvider, error) {
+				if model != "fake-model" {
+					return nil, fmt.Errorf("unsupported model: %s", model)
+				}
+				return tt.fake, nil
+			})
+
+			resp, err := runner.RunAdHocQuestion(context.Background(), tt.question, tt.model, location, tt.webSearch)
+			if calls := tt.fake.Calls(); !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %+v, want %+v", calls, tt.wantCalls)
+			}
+			if tt.wantErr != nil || tt.wantErrText != "" {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("RunAdHocQuestion() error = %v, want %v %q", err, tt.wantErr, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunAdHocQuestion() error = %v", err)
+			}
+			if resp.Response != tt.wantResponse {
+				t.Errorf("Response = %q, want %q", resp.Response, tt.wantResponse)
+			}
+			if resp.WebSearchDowngraded != tt.wantDowngrade {
+				t.Errorf("WebSearchDowngraded = %t, want %t", resp.WebSearchDowngraded, tt.wantDowngrade)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// TestRecordThenReplay records answers through the question runner with
+// PROVIDER_RECORD_DIR, then replays them with PROVIDER_REPLAY_DIR against a
+// provider that fails every call.
+func TestRecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	california := "California"
+	us := &workflowModels.Location{Country: "US"}
+	usCalifornia := &workflowModels.Location{Country: "us", Region: &california}
+
+	live := &FakeProvider{WebSearch: true, Responses: map[string]*services.AIResponse{
+		"Best bank?": {Response: "Acme Bank", InputTokens: 12, OutputTokens: 3, Cost: 0.01, Citations: []string{"https://acme.example"}},
+	}}
+	dead := &FakeProvider{WebSearch: true, Err: errors.New("network disabled")}
+	runner := func(cfg *config.Config, provider *FakeProvider) services.QuestionRunnerService {
+		return services.NewQuestionRunnerServiceWithProviders(cfg, nil, nil, nil, func(model string) (services.AIProvider, error) {
+			return provider, nil
+		})
+	}
+
+	ctx := context.Background()
+	recorder := runner(&config.Config{ProviderRecordDir: dir}, live)
+	for _, model := range []string{"gpt-4.1", "sonar-pro"} {
+		for _, location := range []*workflowModels.Location{us, usCalifornia} {
+			if _, err := recorder.RunAdHocQuestion(ctx, "Best bank?", model, location, true); err != nil {
+				t.Fatalf("recording %s: %v", model, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		model     string
+		location  *workflowModels.Location
+		webSearch bool
+		want      string
+		wantMiss  bool
+	}{
+		{name: "same request", model: "gpt-4.1", location: us, webSearch: true, want: "Acme Bank"},
+		{name: "model is case-insensitive", model: "GPT-4.1", location: us, webSearch: true, want: "Acme Bank"},
+		{name: "country is case-insensitive", model: "sonar-pro", location: &workflowModels.Location{Country: "US", Region: &california}, webSearch: true, want: "Acme Bank"},
+		{name: "never recorded without web search", model: "gpt-4.1", location: us, webSearch: false, wantMiss: true},
+		{name: "never recorded for the model", model: "gemini-2.5-flash", location: us, webSearch: true, wantMiss: true},
+		{name: "never recorded for the location", model: "gpt-4.1", location: &workflowModels.Location{Country: "GB"}, webSearch: true, wantMiss: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replayer := runner(&config.Config{ProviderReplayDir: dir}, dead)
+			resp, err := replayer.RunAdHocQuestion(ctx, "Best bank?", tt.model, tt.location, tt.webSearch)
+			if len(dead.Calls()) != 0 {
+				t.Fatalf("replay called the provider: %+v", dead.Calls())
+			}
+			if tt.wantMiss {
+				if !errors.Is(err, services.ErrNoFixture) {
+					t.Fatalf("RunAdHocQuestion() error = %v, want ErrNoFixture", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunAdHocQuestion() error = %v", err)
+			}
+			if resp.Response != tt.want || resp.InputTokens != 12 || resp.OutputTokens != 3 || resp.Cost != 0.01 {
+				t.Errorf("replayed %+v, want the recorded response", resp)
+			}
+			if !reflect.DeepEqual(resp.Citations, []string{"https://acme.example"}) {
+				t.Errorf("Citations = %v, want the recorded citations", resp.Citations)
+			}
+		})
+	}
+	if len(live.Calls()) != 4 {
+		t.Errorf("live provider calls = %d, want 4", len(live.Calls()))
+	}
+}
+
+func TestRecordThenReplayBatch(t *testing.T) {
+	dir := t.TempDir()
+	location := &workflowModels.Location{Country: "US"}
+	batch := []services.BatchQuery{{CorrelationID: "a", Query: "Best bank?"}, {CorrelationID: "b", Query: "Best card?"}}
+
+	live := &FakeProvider{Batching: true}
+	recorder := &services.RecordingProvider{AIProvider: live, Dir: dir, Model: "sonar-pro"}
+	if _, err := recorder.RunQuestionBatch(context.Background(), batch, true, location); err != nil {
+		t.Fatalf("recording batch: %v", err)
+	}
+
+	replayer := services.NewReplayProvider(dir, "sonar-pro", live)
+	reordered := []services.BatchQuery{{CorrelationID: "y", Query: "Best card?"}, {CorrelationID: "z", Query: "Best bank?"}}
+	responses, err := replayer.RunQuestionBatch(context.Background(), reordered, true, location)
+	if err != nil {
+		t.Fatalf("replaying batch: %v", err)
+	}
+	for i, want := range []struct{ id, text string }{{"y", "fake response to: Best card?"}, {"z", "fake response to: Best bank?"}} {
+		if responses[i].CorrelationID != want.id || responses[i].Response != want.text {
+			t.Errorf("response %d = %s %q, want %s %q", i, responses[i].CorrelationID, responses[i].Response, want.id, want.text)
+		}
+	}
+
+	_, err = replayer.RunQuestionBatch(context.Background(), batch, false, location)
+	if !errors.Is(err, services.ErrNoFixture) {
+		t.Fatalf("batch without web search error = %v, want ErrNoFixture", err)
+	}
+	if misses := replayer.Misses(); len(misses) != 1 || misses[0].WebSearch {
+		t.Errorf("Misses() = %+v, want the query without web search", misses)
+	}
+}
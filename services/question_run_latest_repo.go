@@ -0,0 +1,144 @@
+// services/question_run_latest_repo.go
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// QuestionRunLatestFlagRepository flips question_runs.is_latest for many runs in
+// a single statement. It is optional on RepositoryManager: when nil, callers fall
+// back to updating runs one at a time through QuestionRunRepo.
+//
+// It also keeps the second-latest runs, for "change since last run": the runs
+// that were latest right before a question's latest runs changed. question_runs
+// belongs to senso-api, so they are kept in a side table of this service:
+//
+//	migrations/000015_question_run_second_latest.up.sql
+type QuestionRunLatestFlagRepository interface {
+	// ClearLatestForQuestionsExceptBatch sets is_latest=false on every run of the
+	// given questions that does not belong to batchID.
+	ClearLatestForQuestionsExceptBatch(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, batchID uuid.UUID) (int64, error)
+	// SetLatestForBatch sets is_latest=true on every run in batchID.
+	SetLatestForBatch(ctx context.Context, tx *sqlx.Tx, batchID uuid.UUID) (int64, error)
+	// ShiftSecondLatest is called before an is_latest update for newRunIDs (the
+	// runs being flagged, which are stored with is_latest=true): for every
+	// question with an is_latest run outside newRunIDs it replaces the
+	// question's second-latest runs with those runs. Questions whose latest runs
+	// don't change keep their second-latest runs. It returns the number of
+	// second-latest runs recorded.
+	ShiftSecondLatest(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, newRunIDs []uuid.UUID) (int64, error)
+}
+
+type questionRunLatestFlagRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunLatestFlagRepo(db *database.Client) QuestionRunLatestFlagRepository {
+	return &questionRunLatestFlagRepo{db: db}
+}
+
+func (r *questionRunLatestFlagRepo) ClearLatestForQuestionsExceptBatch(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, batchID uuid.UUID) (int64, error) {
+	if len(questionIDs) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, len(questionIDs))
+	for i, id := range questionIDs {
+		ids[i] = id.String()
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE question_runs
+		SET is_latest = false, updated_at = NOW()
+		WHERE geo_question_id = ANY($1::uuid[])
+		  AND (batch_id IS NULL OR batch_id <> $2)
+		  AND is_latest = true`, pq.Array(ids), batchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear latest flags: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *questionRunLatestFlagRepo) SetLatestForBatch(ctx context.Context, tx *sqlx.Tx, batchID uuid.UUID) (int64, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE question_runs
+		SET is_latest = true, updated_at = NOW()
+		WHERE batch_id = $1`, batchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to set latest flags: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *questionRunLatestFlagRepo) ShiftSecondLatest(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, newRunIDs []uuid.UUID) (int64, error) {
+	if len(questionIDs) == 0 {
+		return 0, nil
+	}
+	args := []interface{}{pq.Array(uuidStrings(questionIDs)), pq.Array(uuidStrings(newRunIDs))}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM question_run_second_latest
+		WHERE geo_question_id IN (
+			SELECT geo_question_id
+			FROM question_runs
+			WHERE geo_question_id = ANY($1::uuid[])
+			  AND is_latest = true
+			  AND NOT (question_run_id = ANY($2::uuid[]))
+		)`, args...); err != nil {
+		return 0, fmt.Errorf("failed to clear second latest runs: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO question_run_second_latest (question_run_id, geo_question_id)
+		SELECT question_run_id, geo_question_id
+		FROM question_runs
+		WHERE geo_question_id = ANY($1::uuid[])
+		  AND is_latest = true
+		  AND NOT (question_run_id = ANY($2::uuid[]))
+		ON CONFLICT (question_run_id) DO NOTHING`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record second latest runs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// shiftSecondLatest runs ShiftSecondLatest in its own transaction for the
+// latest-flag paths that don't have one. It must run before is_latest changes.
+// Failures are logged, not fatal: second-latest only feeds the dashboards.
+func shiftSecondLatest(ctx context.Context, repos *RepositoryManager, questionIDs []uuid.UUID, newRunIDs []uuid.UUID) {
+	if repos.QuestionRunLatestRepo == nil || len(questionIDs) == 0 {
+		return
+	}
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		fmt.Printf("[shiftSecondLatest] Warning: failed to begin transaction: %v\n", err)
+		return
+	}
+	defer tx.Rollback() // Rollback on error
+
+	shifted, err := repos.QuestionRunLatestRepo.ShiftSecondLatest(ctx, tx, questionIDs, newRunIDs)
+	if err != nil {
+		fmt.Printf("[shiftSecondLatest] Warning: %v\n", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Printf("[shiftSecondLatest] Warning: failed to commit transaction: %v\n", err)
+		return
+	}
+	if shifted > 0 {
+		fmt.Printf("[shiftSecondLatest] Recorded %d second latest runs of %d questions\n", shifted, len(questionIDs))
+	}
+}
+
+// runIDs returns the runs' IDs, in order.
+func runIDs(runs []*models.QuestionRun) []uuid.UUID {
+	ids := make([]uuid.UUID, len(runs))
+	for i, run := range runs {
+		ids[i] = run.QuestionRunID
+	}
+	return ids
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// TestShiftSecondLatest runs the shift against a Postgres database. It needs
+// TEST_DATABASE_URL and works on temporary question_runs and
+// question_run_second_latest tables that are dropped with the transaction, so
+// any scratch database will do.
+func TestShiftSecondLatest(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer db.Close()
+
+	type run struct {
+		id, question         string
+		latest, secondLatest bool
+	}
+	tests := []struct {
+		name         string
+		runs         []run
+		questions    []string
+		newRuns      []string
+		wantSecond   []string
+		wantAffected int64
+	}{
+		{
+			name:         "previous latest becomes second latest",
+			runs:         []run{{id: "a", question: "q1", latest: true}, {id: "b", question: "q1", latest: true}},
+			questions:    []string{"q1"},
+			newRuns:      []string{"b"},
+			wantSecond:   []string{"a"},
+			wantAffected: 1,
+		},
+		{
+			name: "older second latest is cleared",
+			runs: []run{
+				{id: "x", question: "q1", secondLatest: true},
+				{id: "a", question: "q1", latest: true},
+				{id: "b", question: "q1", latest: true},
+			},
+			questions:    []string{"q1"},
+			newRuns:      []string{"b"},
+			wantSecond:   []string{"a"},
+			wantAffected: 1,
+		},
+		{
+			name: "unchanged latest keeps second latest",
+			runs: []run{
+				{id: "x", question: "q1", secondLatest: true},
+				{id: "b", question: "q1", latest: true},
+			},
+			questions:  []string{"q1"},
+			newRuns:    []string{"b"},
+			wantSecond: []string{"x"},
+		},
+		{
+			name: "every model and location run shifts",
+			runs: []run{
+				{id: "a1", question: "q1", latest: true},
+				{id: "a2", question: "q1", latest: true},
+				{id: "b1", question: "q1", latest: true},
+				{id: "b2", question: "q1", latest: true},
+			},
+			questions:    []string{"q1"},
+			newRuns:      []string{"b1", "b2"},
+			wantSecond:   []string{"a1", "a2"},
+			wantAffected: 2,
+		},
+		{
+			name: "questions outside the list are untouched",
+			runs: []run{
+				{id: "a", question: "q1", latest: true},
+				{id: "b", question: "q1", latest: true},
+				{id: "c", question: "q2", latest: true},
+				{id: "y", question: "q2", secondLatest: true},
+			},
+			questions:    []string{"q1"},
+			newRuns:      []string{"b"},
+			wantSecond:   []string{"a", "y"},
+			wantAffected: 1,
+		},
+		{
+			name:       "no questions",
+			runs:       []run{{id: "a", question: "q1", latest: true}},
+			wantSecond: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tx, err := db.BeginTxx(ctx, nil)
+			if err != nil {
+				t.Fatalf("failed to begin transaction: %v", err)
+			}
+			defer tx.Rollback()
+
+			if _, err := tx.ExecContext(ctx, `
+				CREATE TEMP TABLE question_runs (
+					question_run_id UUID PRIMARY KEY,
+					geo_question_id UUID NOT NULL,
+					is_latest BOOLEAN NOT NULL DEFAULT false,
+					updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+				) ON COMMIT DROP;
+				CREATE TEMP TABLE question_run_second_latest (
+					question_run_id UUID PRIMARY KEY REFERENCES question_runs(question_run_id),
+					geo_question_id UUID NOT NULL,
+					created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+				) ON COMMIT DROP`); err != nil {
+				t.Fatalf("failed to create tables: %v", err)
+			}
+
+			ids := make(map[string]uuid.UUID)
+			id := func(label string) uuid.UUID {
+				if _, ok := ids[label]; !ok {
+					ids[label] = uuid.New()
+				}
+				return ids[label]
+			}
+			labels := make(map[uuid.UUID]string)
+			for _, r := range tt.runs {
+				labels[id(r.id)] = r.id
+				if _, err := tx.ExecContext(ctx, `
+					INSERT INTO question_runs (question_run_id, geo_question_id, is_latest)
+					VALUES ($1, $2, $3)`, id(r.id), id(r.question), r.latest); err != nil {
+					t.Fatalf("failed to insert run %s: %v", r.id, err)
+				}
+				if r.secondLatest {
+					if _, err := tx.ExecContext(ctx, `
+						INSERT INTO question_run_second_latest (question_run_id, geo_question_id)
+						VALUES ($1, $2)`, id(r.id), id(r.question)); err != nil {
+						t.Fatalf("failed to mark run %s second latest: %v", r.id, err)
+					}
+				}
+			}
+			var questionIDs, newRunIDs []uuid.UUID
+			for _, q := range tt.questions {
+				questionIDs = append(questionIDs, id(q))
+			}
+			for _, r := range tt.newRuns {
+				newRunIDs = append(newRunIDs, id(r))
+			}
+
+			affected, err := (&questionRunLatestFlagRepo{}).ShiftSecondLatest(ctx, tx, questionIDs, newRunIDs)
+			if err != nil {
+				t.Fatalf("ShiftSecondLatest() error = %v", err)
+			}
+			if affected != tt.wantAffected {
+				t.Errorf("rows affected = %d, want %d", affected, tt.wantAffected)
+			}
+
+			var second []uuid.UUID
+			if err := tx.SelectContext(ctx, &second, `SELECT question_run_id FROM question_run_second_latest`); err != nil {
+				t.Fatalf("failed to read second latest runs: %v", err)
+			}
+			got := []string{}
+			for _, runID := range second {
+				got = append(got, labels[runID])
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantSecond) {
+				t.Errorf("second latest runs = %v, want %v", got, tt.wantSecond)
+			}
+		})
+	}
+}
+
+// statementPrefixes is each recorded statement cut to its first n words.
+func statementPrefixes(rec *sqlRecorder, n int) []string {
+	var out []string
+	for _, query := range rec.Queries() {
+		words := strings.Fields(query)
+		if len(words) > n {
+			words = words[:n]
+		}
+		out = append(out, strings.Join(words, " "))
+	}
+	return out
+}
+
+// TestShiftSecondLatestOrdering checks, without a database, that the
+// second-latest runs are replaced in the side table before is_latest moves,
+// all in one transaction.
+func TestShiftSecondLatestOrdering(t *testing.T) {
+	questionIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	newRunIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	batchID := uuid.New()
+	errDelete := errors.New("connection reset")
+
+	tests := []struct {
+		name    string
+		run     func(repos *RepositoryManager) error
+		execErr func(query string) error
+		want    []string
+	}{
+		{
+			name: "shiftSecondLatest",
+			run: func(repos *RepositoryManager) error {
+				shiftSecondLatest(context.Background(), repos, questionIDs, newRunIDs)
+				return nil
+			},
+			want: []string{
+				"BEGIN",
+				"DELETE FROM question_run_second_latest",
+				"INSERT INTO question_run_second_latest",
+				"COMMIT",
+			},
+		},
+		{
+			name: "shiftSecondLatest without questions",
+			run: func(repos *RepositoryManager) error {
+				shiftSecondLatest(context.Background(), repos, nil, newRunIDs)
+				return nil
+			},
+		},
+		{
+			name: "failed delete records nothing",
+			run: func(repos *RepositoryManager) error {
+				shiftSecondLatest(context.Background(), repos, questionIDs, newRunIDs)
+				return nil
+			},
+			execErr: func(query string) error {
+				if strings.HasPrefix(query, "DELETE") {
+					return errDelete
+				}
+				return nil
+			},
+			want: []string{"BEGIN", "DELETE FROM question_run_second_latest", "ROLLBACK"},
+		},
+		{
+			name: "bulkUpdateNetworkLatestFlags",
+			run: func(repos *RepositoryManager) error {
+				s := &questionRunnerService{repos: repos}
+				_, _, err := s.bulkUpdateNetworkLatestFlags(context.Background(), questionIDs, batchID, newRunIDs)
+				return err
+			},
+			want: []string{
+				"BEGIN",
+				"DELETE FROM question_run_second_latest",
+				"INSERT INTO question_run_second_latest",
+				"UPDATE question_runs SET is_latest = false",
+				"UPDATE question_runs SET is_latest = true",
+				"COMMIT",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &sqlRecorder{Exec: func(query string, args []any) (int64, error) {
+				if tt.execErr != nil {
+					return 0, tt.execErr(query)
+				}
+				return 1, nil
+			}}
+			repos := newRecordingRepos(t, rec)
+			repos.QuestionRunLatestRepo = NewQuestionRunLatestFlagRepo(repos.db)
+
+			if err := tt.run(repos); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			got := statementPrefixes(rec, 6)
+			if len(got) != len(tt.want) {
+				t.Fatalf("statements = %q, want %q", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("statement %d = %q, want it to start with %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+// countingLatestRepo is a QuestionRunLatestFlagRepository that counts its calls.
+type countingLatestRepo struct {
+	shift, clear, set int
+	clearedQuestions  []uuid.UUID
+	setBatch          uuid.UUID
+	err               error
+}
+
+func (r *countingLatestRepo) ClearLatestForQuestionsExceptBatch(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, batchID uuid.UUID) (int64, error) {
+	r.clear++
+	r.clearedQuestions = questionIDs
+	return int64(len(questionIDs)), r.err
+}
+
+func (r *countingLatestRepo) SetLatestForBatch(ctx context.Context, tx *sqlx.Tx, batchID uuid.UUID) (int64, error) {
+	r.set++
+	r.setBatch = batchID
+	return 1, nil
+}
+
+func (r *countingLatestRepo) ShiftSecondLatest(ctx context.Context, tx *sqlx.Tx, questionIDs []uuid.UUID, newRunIDs []uuid.UUID) (int64, error) {
+	r.shift++
+	return 0, nil
+}
+
+// countingRunRepo counts the per-run calls of the fallback path.
+type countingRunRepo struct {
+	interfaces.QuestionRunRepository
+	calls int
+}
+
+func (r *countingRunRepo) GetByQuestion(ctx context.Context, questionID uuid.UUID) ([]*models.QuestionRun, error) {
+	r.calls++
+	return nil, nil
+}
+
+func (r *countingRunRepo) Update(ctx context.Context, run *models.QuestionRun) error {
+	r.calls++
+	return nil
+}
+
+// TestUpdateNetworkLatestFlagsBulk checks that a batch of many runs over many
+// questions makes exactly two bulk is_latest calls, and falls back to per-run
+// updates only when they fail.
+func TestUpdateNetworkLatestFlagsBulk(t *testing.T) {
+	batchID := uuid.New()
+	questionIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	var runs []*models.QuestionRun
+	for _, questionID := range questionIDs {
+		for i := 0; i < 4; i++ {
+			runs = append(runs, &models.QuestionRun{QuestionRunID: uuid.New(), GeoQuestionID: questionID, BatchID: &batchID})
+		}
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantRunCalls bool
+	}{
+		{name: "bulk update"},
+		{name: "bulk failure falls back", err: errors.New("deadlock detected"), wantRunCalls: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := &countingLatestRepo{err: tt.err}
+			runRepo := &countingRunRepo{}
+			repos := newRecordingRepos(t, &sqlRecorder{})
+			repos.QuestionRunLatestRepo = latest
+			repos.QuestionRunRepo = runRepo
+			s := &questionRunnerService{repos: repos}
+
+			if err := s.updateNetworkLatestFlagsForRuns(context.Background(), nil, runs); err != nil {
+				t.Fatalf("updateNetworkLatestFlagsForRuns() error = %v", err)
+			}
+			if tt.wantRunCalls {
+				if runRepo.calls == 0 {
+					t.Error("no per-run updates after the bulk update failed")
+				}
+				return
+			}
+			if latest.clear != 1 || latest.set != 1 {
+				t.Errorf("bulk calls: clear %d, set %d; want 1 each", latest.clear, latest.set)
+			}
+			if runRepo.calls != 0 {
+				t.Errorf("%d per-run repository calls, want 0", runRepo.calls)
+			}
+			if !reflect.DeepEqual(latest.clearedQuestions, questionIDs) {
+				t.Errorf("cleared questions = %v, want %v", latest.clearedQuestions, questionIDs)
+			}
+			if latest.setBatch != batchID {
+				t.Errorf("set batch = %s, want %s", latest.setBatch, batchID)
+			}
+			for _, run := range runs {
+				if !run.IsLatest {
+					t.Fatalf("run %s not marked latest", run.QuestionRunID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// services/question_run_metrics_repo.go
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// QuestionRunMetricsRepository writes only the competitive metric columns of
+// a question run. QuestionRunRepo.Update rewrites the whole row, which loses
+// any change another process made to the run between our read and write
+// (is_latest in particular). It is optional on RepositoryManager: when nil,
+// callers fall back to the full-row update.
+type QuestionRunMetricsRepository interface {
+	UpdateMetrics(ctx context.Context, questionRunID uuid.UUID, targetMentioned bool, shareOfVoice *float64, targetRank *int, targetSentiment *float64) error
+}
+
+type questionRunMetricsRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunMetricsRepo(db *database.Client) QuestionRunMetricsRepository {
+	return &questionRunMetricsRepo{db: db}
+}
+
+func (r *questionRunMetricsRepo) UpdateMetrics(ctx context.Context, questionRunID uuid.UUID, targetMentioned bool, shareOfVoice *float64, targetRank *int, targetSentiment *float64) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE question_runs
+		SET target_mentioned = $2, target_sov = $3, target_rank = $4, target_sentiment = $5, updated_at = NOW()
+		WHERE question_run_id = $1`,
+		questionRunID, targetMentioned, shareOfVoice, targetRank, targetSentiment)
+	if err != nil {
+		return fmt.Errorf("failed to update question run metrics: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("failed to update question run metrics: run %s not found", questionRunID)
+	}
+	return nil
+}
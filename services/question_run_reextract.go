@@ -0,0 +1,468 @@
+// services/question_run_reextract.go
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Re-extraction of one question run, for redoing a single run after an
+// extraction prompt fix without re-evaluating the whole org. The stored
+// outputs of the selected stages are deleted and the stages run again against
+// the stored response; the response itself is never re-fetched.
+//
+// Scope follows the run: org pipeline runs carry a model and a location and
+// are re-extracted through the org stages (runExtractionStages); network runs
+// carry neither and are re-extracted per org into the network_org_* tables.
+// A re-extraction that fails leaves the org stage pending or failed for the
+// next repair pass, and the network org pair dead-lettered, like any other
+// failed extraction.
+
+// ReextractScope is the pipeline a question run belongs to.
+type ReextractScope string
+
+const (
+	ReextractScopeOrg     ReextractScope = "org"
+	ReextractScopeNetwork ReextractScope = "network"
+)
+
+// Re-extraction stages of org runs.
+const (
+	ReextractMentions  = "mentions"
+	ReextractClaims    = "claims"
+	ReextractCitations = "citations"
+	ReextractMetrics   = "metrics"
+)
+
+// Re-extraction stages of network runs; citations is shared with org runs.
+const (
+	ReextractEvals       = "evals"
+	ReextractCompetitors = "competitors"
+)
+
+var reextractStages = map[ReextractScope][]string{
+	ReextractScopeOrg:     {ReextractMentions, ReextractClaims, ReextractCitations, ReextractMetrics},
+	ReextractScopeNetwork: {ReextractEvals, ReextractCompetitors, ReextractCitations},
+}
+
+// SelectReextractStages validates the requested stages for the scope and
+// adds the stages that depend on them: metrics are computed from mentions and
+// citations hang off claims, so re-extracting mentions or claims redoes those
+// too. A network org pair is extracted by one call, so any network stage
+// selects all three. No stages selects every stage of the scope. The result
+// is in pipeline order.
+func SelectReextractStages(scope ReextractScope, requested []string) ([]string, error) {
+	all, ok := reextractStages[scope]
+	if !ok {
+		return nil, fmt.Errorf("unknown re-extraction scope %q", scope)
+	}
+
+	selected := make(map[string]bool)
+	for _, stage := range requested {
+		stage = strings.ToLower(strings.TrimSpace(stage))
+		if stage == "" {
+			continue
+		}
+		valid := false
+		for _, s := range all {
+			valid = valid || s == stage
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown %s stage %q (want one of %s)", scope, stage, strings.Join(all, ", "))
+		}
+		selected[stage] = true
+	}
+	if len(selected) == 0 || scope == ReextractScopeNetwork {
+		return all, nil
+	}
+	if selected[ReextractMentions] {
+		selected[ReextractMetrics] = true
+	}
+	if selected[ReextractClaims] {
+		selected[ReextractCitations] = true
+	}
+
+	stages := make([]string, 0, len(selected))
+	for _, s := range all {
+		if selected[s] {
+			stages = append(stages, s)
+		}
+	}
+	return stages, nil
+}
+
+// ReextractOptions control ReextractQuestionRun.
+type ReextractOptions struct {
+	// OrgID is the org to re-extract a network run for; uuid.Nil re-extracts
+	// it for every org that has outputs stored on it. Ignored for org runs.
+	OrgID uuid.UUID
+	// DryRun only lists what would be deleted and recreated.
+	DryRun bool
+}
+
+// ReextractTarget is one stage output of the run: the rows stored for it
+// (deleted, or for metrics overwritten, by the re-extraction) and, unless
+// dry-running, the outcome.
+type ReextractTarget struct {
+	// OrgID is the org of a network org output, uuid.Nil for org runs.
+	OrgID  uuid.UUID
+	Stage  string
+	Stored int
+	// Outcome is the stage status for org runs and "ok", "skipped: ..." or
+	// "failed: ..." per network org pair.
+	Outcome string
+}
+
+// ReextractPlan is what ReextractQuestionRun did, or would do on a dry run.
+type ReextractPlan struct {
+	QuestionRunID uuid.UUID
+	Scope         ReextractScope
+	// OwnerID is the org or network whose question the run answers.
+	OwnerID uuid.UUID
+	Stages  []string
+	Targets []*ReextractTarget
+	DryRun  bool
+}
+
+// Failed returns the targets whose re-extraction failed.
+func (p *ReextractPlan) Failed() []*ReextractTarget {
+	var failed []*ReextractTarget
+	for _, t := range p.Targets {
+		if t.Outcome == string(StageFailed) || strings.HasPrefix(t.Outcome, "failed") {
+			failed = append(failed, t)
+		}
+	}
+	return failed
+}
+
+// NetworkOrgOutputCount is the network org rows stored for one org on a run.
+type NetworkOrgOutputCount struct {
+	OrgID       uuid.UUID `db:"org_id"`
+	Evals       int       `db:"evals"`
+	Competitors int       `db:"competitors"`
+	Citations   int       `db:"citations"`
+}
+
+// QuestionRunReextractRepository reads the owner and outputs of a run and
+// deletes its org stage outputs.
+type QuestionRunReextractRepository interface {
+	// GetRunOwner returns the org and network of the run's question; exactly
+	// one is set.
+	GetRunOwner(ctx context.Context, questionRunID uuid.UUID) (orgID, networkID *uuid.UUID, err error)
+	CountNetworkOrgOutputs(ctx context.Context, questionRunID uuid.UUID) ([]NetworkOrgOutputCount, error)
+	// DeleteStageOutputs deletes the run's mentions, claims and/or citations,
+	// as selected by stages, in one transaction.
+	DeleteStageOutputs(ctx context.Context, questionRunID uuid.UUID, stages []string) error
+}
+
+type questionRunReextractRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunReextractRepo(db *database.Client) QuestionRunReextractRepository {
+	return &questionRunReextractRepo{db: db}
+}
+
+func (r *questionRunReextractRepo) GetRunOwner(ctx context.Context, questionRunID uuid.UUID) (*uuid.UUID, *uuid.UUID, error) {
+	var owner struct {
+		OrgID     *uuid.UUID `db:"org_id"`
+		NetworkID *uuid.UUID `db:"network_id"`
+	}
+	err := r.db.GetContext(ctx, &owner, `
+		SELECT gq.org_id, gq.network_id
+		FROM question_runs qr
+		JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+		WHERE qr.question_run_id = $1`, questionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("question run %s not found", questionRunID)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get owner of question run %s: %w", questionRunID, err)
+	}
+	return owner.OrgID, owner.NetworkID, nil
+}
+
+func (r *questionRunReextractRepo) CountNetworkOrgOutputs(ctx context.Context, questionRunID uuid.UUID) ([]NetworkOrgOutputCount, error) {
+	var counts []NetworkOrgOutputCount
+	err := r.db.SelectContext(ctx, &counts, `
+		SELECT o.org_id,
+		       (SELECT COUNT(*) FROM network_org_evals e WHERE e.question_run_id = $1 AND e.org_id = o.org_id) AS evals,
+		       (SELECT COUNT(*) FROM network_org_competitors c WHERE c.question_run_id = $1 AND c.org_id = o.org_id) AS competitors,
+		       (SELECT COUNT(*) FROM network_org_citations c WHERE c.question_run_id = $1 AND c.org_id = o.org_id) AS citations
+		FROM (
+			SELECT org_id FROM network_org_evals WHERE question_run_id = $1
+			UNION SELECT org_id FROM network_org_competitors WHERE question_run_id = $1
+			UNION SELECT org_id FROM network_org_citations WHERE question_run_id = $1
+		) o
+		ORDER BY o.org_id`, questionRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count network org outputs of question run %s: %w", questionRunID, err)
+	}
+	return counts, nil
+}
+
+func (r *questionRunReextractRepo) DeleteStageOutputs(ctx context.Context, questionRunID uuid.UUID, stages []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback on error
+
+	for _, stage := range stages {
+		var queries []string
+		switch stage {
+		case ReextractMentions:
+			queries = []string{`DELETE FROM question_run_mentions WHERE question_run_id = $1`}
+		case ReextractClaims:
+			queries = []string{
+				`DELETE FROM question_run_citations WHERE question_run_claim_id IN (
+					SELECT question_run_claim_id FROM question_run_claims WHERE question_run_id = $1)`,
+				`DELETE FROM question_run_claims WHERE question_run_id = $1`,
+			}
+		case ReextractCitations:
+			queries = []string{`DELETE FROM question_run_citations WHERE question_run_claim_id IN (
+				SELECT question_run_claim_id FROM question_run_claims WHERE question_run_id = $1)`}
+		}
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query, questionRunID); err != nil {
+				return fmt.Errorf("failed to delete %s of question run %s: %w", stage, questionRunID, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// ReextractQuestionRun deletes the stored outputs of the selected stages of
+// one question run and extracts them again from its stored response. The
+// scope is detected from the run; see SelectReextractStages for how stages
+// are selected. A network org pair another processor is extracting is
+// skipped. With opts.DryRun nothing is deleted or extracted.
+func (s *questionRunnerService) ReextractQuestionRun(ctx context.Context, questionRunID uuid.UUID, stages []string, opts ReextractOptions) (*ReextractPlan, error) {
+	if s.repos.QuestionRunReextractRepo == nil {
+		return nil, fmt.Errorf("re-extraction is not configured")
+	}
+
+	runs, err := s.repos.QuestionRunRepo.GetByIDs(ctx, []uuid.UUID{questionRunID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question run: %w", err)
+	}
+	if len(runs) == 0 || runs[0].ResponseText == nil {
+		return nil, fmt.Errorf("question run %s not found or has no response text", questionRunID)
+	}
+	run := runs[0]
+
+	orgID, networkID, err := s.repos.QuestionRunReextractRepo.GetRunOwner(ctx, questionRunID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ReextractPlan{QuestionRunID: questionRunID, DryRun: opts.DryRun}
+	// Org pipeline runs are stored with their model and location, network runs without
+	switch {
+	case run.ModelID != nil && run.LocationID != nil && orgID != nil:
+		plan.Scope, plan.OwnerID = ReextractScopeOrg, *orgID
+	case run.ModelID == nil && run.LocationID == nil && networkID != nil:
+		plan.Scope, plan.OwnerID = ReextractScopeNetwork, *networkID
+	default:
+		return nil, fmt.Errorf("question run %s is neither an org nor a network run", questionRunID)
+	}
+	if plan.Stages, err = SelectReextractStages(plan.Scope, stages); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("[ReextractQuestionRun] Run %s: %s %s, stages=%s dry_run=%t\n",
+		questionRunID, plan.Scope, plan.OwnerID, strings.Join(plan.Stages, ","), opts.DryRun)
+	if plan.Scope == ReextractScopeOrg {
+		return plan, s.reextractOrgRun(ctx, plan, run, opts)
+	}
+	return plan, s.reextractNetworkRun(ctx, plan, run.GeoQuestionID, *run.ResponseText, opts)
+}
+
+func (s *questionRunnerService) reextractOrgRun(ctx context.Context, plan *ReextractPlan, run *models.QuestionRun, opts ReextractOptions) error {
+	questionRunID := run.QuestionRunID
+	stored := map[string]int{}
+	if s.repos.QuestionRunStageRepo != nil {
+		mentions, err := s.repos.QuestionRunStageRepo.GetMentions(ctx, questionRunID)
+		if err != nil {
+			return err
+		}
+		claims, err := s.repos.QuestionRunStageRepo.GetClaims(ctx, questionRunID)
+		if err != nil {
+			return err
+		}
+		citations, err := s.repos.QuestionRunStageRepo.GetCitations(ctx, questionRunID)
+		if err != nil {
+			return err
+		}
+		stored[ReextractMentions], stored[ReextractClaims], stored[ReextractCitations] = len(mentions), len(claims), len(citations)
+	}
+	selected := make(map[string]bool, len(plan.Stages))
+	for _, stage := range plan.Stages {
+		selected[stage] = true
+		// Metrics live on the run itself, so they have no rows to count
+		plan.Targets = append(plan.Targets, &ReextractTarget{Stage: stage, Stored: stored[stage]})
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	details, err := s.orgService.GetOrgDetails(ctx, plan.OwnerID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get org details: %w", err)
+	}
+
+	// Stages that are not re-extracted keep their status, so any still
+	// pending or failed are repaired along the way. Without a recorded status
+	// they predate stage tracking and are taken as done.
+	status := newPendingStageStatus(questionRunID)
+	recorded := false
+	if s.repos.QuestionRunStageRepo != nil {
+		existing, err := s.repos.QuestionRunStageRepo.GetByQuestionRun(ctx, questionRunID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			status, recorded = existing, true
+		}
+	}
+	for stage, field := range map[string]*StageStatus{
+		ReextractMentions:  &status.MentionsStatus,
+		ReextractClaims:    &status.ClaimsStatus,
+		ReextractCitations: &status.CitationsStatus,
+		ReextractMetrics:   &status.MetricsStatus,
+	} {
+		switch {
+		case selected[stage]:
+			*field = StagePending
+		case !recorded:
+			*field = StageOK
+		}
+	}
+	if selected[ReextractCitations] && !s.features.FeaturesForOrg(ctx, plan.OwnerID).Citations {
+		status.CitationsStatus = StageSkippedPlan
+	}
+
+	// Stages kept feed the re-extracted ones from storage, as in a repair
+	var mentions []*models.QuestionRunMention
+	if status.MentionsStatus == StageOK && stageNeedsRun(status.MetricsStatus) && s.repos.QuestionRunStageRepo != nil {
+		if mentions, err = s.repos.QuestionRunStageRepo.GetMentions(ctx, questionRunID); err != nil {
+			return err
+		}
+	}
+	var claims []*models.QuestionRunClaim
+	if status.ClaimsStatus == StageOK && stageNeedsRun(status.CitationsStatus) && s.repos.QuestionRunStageRepo != nil {
+		if claims, err = s.repos.QuestionRunStageRepo.GetClaims(ctx, questionRunID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repos.QuestionRunReextractRepo.DeleteStageOutputs(ctx, questionRunID, plan.Stages); err != nil {
+		return err
+	}
+	s.saveStageStatus(ctx, status)
+
+	// Whether the stored response was truncated is not known here; the
+	// selected stages' rows were just deleted, so there are none to reuse
+	s.runExtractionStages(ctx, run, status, mentions, claims, plan.OwnerID, *run.ResponseText, false, false, details.TargetCompany, details.Websites, "ReextractQuestionRun")
+
+	outcomes := map[string]StageStatus{
+		ReextractMentions:  status.MentionsStatus,
+		ReextractClaims:    status.ClaimsStatus,
+		ReextractCitations: status.CitationsStatus,
+		ReextractMetrics:   status.MetricsStatus,
+	}
+	for _, target := range plan.Targets {
+		target.Outcome = string(outcomes[target.Stage])
+	}
+	return nil
+}
+
+func (s *questionRunnerService) reextractNetworkRun(ctx context.Context, plan *ReextractPlan, questionID uuid.UUID, responseText string, opts ReextractOptions) error {
+	counts, err := s.repos.QuestionRunReextractRepo.CountNetworkOrgOutputs(ctx, plan.QuestionRunID)
+	if err != nil {
+		return err
+	}
+	if opts.OrgID != uuid.Nil {
+		// The org may not have been evaluated on the run yet
+		var own []NetworkOrgOutputCount
+		for _, count := range counts {
+			if count.OrgID == opts.OrgID {
+				own = append(own, count)
+			}
+		}
+		if len(own) == 0 {
+			own = []NetworkOrgOutputCount{{OrgID: opts.OrgID}}
+		}
+		counts = own
+	}
+	if len(counts) == 0 {
+		return fmt.Errorf("no org has network org outputs on question run %s; pass the org to extract it for", plan.QuestionRunID)
+	}
+
+	type orgTargets struct {
+		orgID   uuid.UUID
+		targets []*ReextractTarget
+	}
+	var orgs []orgTargets
+	for _, count := range counts {
+		entry := orgTargets{orgID: count.OrgID}
+		for _, stage := range plan.Stages {
+			stored := map[string]int{ReextractEvals: count.Evals, ReextractCompetitors: count.Competitors, ReextractCitations: count.Citations}[stage]
+			target := &ReextractTarget{OrgID: count.OrgID, Stage: stage, Stored: stored}
+			entry.targets = append(entry.targets, target)
+			plan.Targets = append(plan.Targets, target)
+		}
+		orgs = append(orgs, entry)
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	question, err := s.repos.GeoQuestionRepo.GetByID(ctx, questionID)
+	if err != nil || question == nil {
+		return fmt.Errorf("failed to get question %s: %v", questionID, err)
+	}
+
+	for _, org := range orgs {
+		outcome := s.reextractNetworkOrgPair(ctx, plan, org.orgID, question.QuestionText, responseText)
+		for _, target := range org.targets {
+			target.Outcome = outcome
+		}
+	}
+	return nil
+}
+
+// reextractNetworkOrgPair re-extracts the network run for one org and returns
+// the outcome to report.
+func (s *questionRunnerService) reextractNetworkOrgPair(ctx context.Context, plan *ReextractPlan, orgID uuid.UUID, questionText, responseText string) string {
+	details, err := s.GetOrgDetailsForNetworkProcessing(ctx, orgID.String())
+	if err != nil {
+		return fmt.Sprintf("failed: %v", err)
+	}
+	if details.NetworkID != plan.OwnerID.String() {
+		return fmt.Sprintf("failed: org %s is not in network %s", orgID, plan.OwnerID)
+	}
+	nameVariations, err := s.GenerateOrgNameVariations(ctx, details.OrgName, details.Websites)
+	if err != nil {
+		return fmt.Sprintf("failed: failed to generate name variations: %v", err)
+	}
+
+	// The cleanup below would delete a concurrent extraction's fresh rows
+	release, err := claimNetworkOrgRun(ctx, s.cfg, s.repos, plan.QuestionRunID, orgID, "reextract:"+uuid.New().String())
+	if err != nil {
+		fmt.Printf("[ReextractQuestionRun] Skipping question run %s for org %s: %v\n", plan.QuestionRunID, orgID, err)
+		return fmt.Sprintf("skipped: %v", err)
+	}
+	defer release()
+
+	if _, err := s.ProcessNetworkOrgQuestionRunWithCleanup(ctx, plan.QuestionRunID, orgID, details.OrgName, details.Websites, nameVariations, questionText, responseText); err != nil {
+		return fmt.Sprintf("failed: %v", err)
+	}
+	return string(StageOK)
+}
@@ -0,0 +1,173 @@
+// services/question_run_stages.go
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Per-stage extraction status for question runs. ProcessSingleQuestion records
+// the outcome of each stage so a repair pass can re-run only what failed
+// instead of redoing (or skipping) the whole run.
+//
+// The status lives in its own table because question_runs is owned by the
+// senso-api migrations:
+//
+//	migrations/000001_question_run_stage_status.up.sql
+
+type StageStatus string
+
+const (
+	StagePending StageStatus = "pending"
+	StageOK      StageStatus = "ok"
+	StageFailed  StageStatus = "failed"
+	StageSkipped StageStatus = "skipped"
+	// StageSkippedPlan marks a stage the org's plan does not include.
+	StageSkippedPlan StageStatus = "skipped_plan"
+	// StageFiltered marks a stage whose extraction call was blocked by the
+	// provider's content filter; it is final, repairs do not retry it.
+	StageFiltered StageStatus = "filtered"
+	// StageEmptyResponse marks a stage skipped because the response text was
+	// empty; it is final, there is nothing to extract.
+	StageEmptyResponse StageStatus = "empty_response"
+)
+
+// QuestionRunStageStatus is the extraction status of one question run.
+type QuestionRunStageStatus struct {
+	QuestionRunID   uuid.UUID   `db:"question_run_id" json:"question_run_id"`
+	MentionsStatus  StageStatus `db:"mentions_status" json:"mentions_status"`
+	ClaimsStatus    StageStatus `db:"claims_status" json:"claims_status"`
+	CitationsStatus StageStatus `db:"citations_status" json:"citations_status"`
+	MetricsStatus   StageStatus `db:"metrics_status" json:"metrics_status"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+func newPendingStageStatus(questionRunID uuid.UUID) *QuestionRunStageStatus {
+	return &QuestionRunStageStatus{
+		QuestionRunID:   questionRunID,
+		MentionsStatus:  StagePending,
+		ClaimsStatus:    StagePending,
+		CitationsStatus: StagePending,
+		MetricsStatus:   StagePending,
+	}
+}
+
+func stageNeedsRun(status StageStatus) bool {
+	return status == StagePending || status == StageFailed
+}
+
+// NeedsRepair reports whether any stage is still pending or failed.
+func (s *QuestionRunStageStatus) NeedsRepair() bool {
+	return stageNeedsRun(s.MentionsStatus) || stageNeedsRun(s.ClaimsStatus) ||
+		stageNeedsRun(s.CitationsStatus) || stageNeedsRun(s.MetricsStatus)
+}
+
+// QuestionRunStageRepository persists QuestionRunStageStatus and loads the
+// stored stage outputs a repair needs as input for downstream stages.
+type QuestionRunStageRepository interface {
+	Upsert(ctx context.Context, status *QuestionRunStageStatus) error
+	GetByQuestionRun(ctx context.Context, questionRunID uuid.UUID) (*QuestionRunStageStatus, error)
+	ListIncompleteByOrg(ctx context.Context, orgID uuid.UUID) ([]*QuestionRunStageStatus, error)
+	GetMentions(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunMention, error)
+	GetClaims(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunClaim, error)
+	// GetCitations returns the citations of all the run's claims.
+	GetCitations(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunCitation, error)
+}
+
+type questionRunStageRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunStageRepo(db *database.Client) QuestionRunStageRepository {
+	return &questionRunStageRepo{db: db}
+}
+
+func (r *questionRunStageRepo) Upsert(ctx context.Context, status *QuestionRunStageStatus) error {
+	status.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_stage_status
+			(question_run_id, mentions_status, claims_status, citations_status, metrics_status, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (question_run_id) DO UPDATE SET
+			mentions_status = EXCLUDED.mentions_status,
+			claims_status = EXCLUDED.claims_status,
+			citations_status = EXCLUDED.citations_status,
+			metrics_status = EXCLUDED.metrics_status,
+			updated_at = EXCLUDED.updated_at`,
+		status.QuestionRunID, status.MentionsStatus, status.ClaimsStatus, status.CitationsStatus, status.MetricsStatus, status.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert stage status: %w", err)
+	}
+	return nil
+}
+
+func (r *questionRunStageRepo) GetByQuestionRun(ctx context.Context, questionRunID uuid.UUID) (*QuestionRunStageStatus, error) {
+	var status QuestionRunStageStatus
+	err := r.db.GetContext(ctx, &status, `
+		SELECT question_run_id, mentions_status, claims_status, citations_status, metrics_status, updated_at
+		FROM question_run_stage_status
+		WHERE question_run_id = $1`, questionRunID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stage status: %w", err)
+	}
+	return &status, nil
+}
+
+func (r *questionRunStageRepo) ListIncompleteByOrg(ctx context.Context, orgID uuid.UUID) ([]*QuestionRunStageStatus, error) {
+	var statuses []*QuestionRunStageStatus
+	err := r.db.SelectContext(ctx, &statuses, `
+		SELECT s.question_run_id, s.mentions_status, s.claims_status, s.citations_status, s.metrics_status, s.updated_at
+		FROM question_run_stage_status s
+		JOIN question_runs qr ON qr.question_run_id = s.question_run_id
+		JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+		WHERE gq.org_id = $1
+		  AND (s.mentions_status IN ('pending', 'failed')
+		    OR s.claims_status IN ('pending', 'failed')
+		    OR s.citations_status IN ('pending', 'failed')
+		    OR s.metrics_status IN ('pending', 'failed'))
+		ORDER BY s.updated_at`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incomplete stage statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+func (r *questionRunStageRepo) GetMentions(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunMention, error) {
+	var mentions []*models.QuestionRunMention
+	if err := r.db.SelectContext(ctx, &mentions, `
+		SELECT * FROM question_run_mentions WHERE question_run_id = $1`, questionRunID); err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	return mentions, nil
+}
+
+func (r *questionRunStageRepo) GetClaims(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunClaim, error) {
+	var claims []*models.QuestionRunClaim
+	if err := r.db.SelectContext(ctx, &claims, `
+		SELECT * FROM question_run_claims WHERE question_run_id = $1 ORDER BY claim_order`, questionRunID); err != nil {
+		return nil, fmt.Errorf("failed to get claims: %w", err)
+	}
+	return claims, nil
+}
+
+func (r *questionRunStageRepo) GetCitations(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunCitation, error) {
+	var citations []*models.QuestionRunCitation
+	if err := r.db.SelectContext(ctx, &citations, `
+		SELECT c.* FROM question_run_citations c
+		JOIN question_run_claims cl ON cl.question_run_claim_id = c.question_run_claim_id
+		WHERE cl.question_run_id = $1
+		ORDER BY cl.claim_order, c.citation_order`, questionRunID); err != nil {
+		return nil, fmt.Errorf("failed to get citations: %w", err)
+	}
+	return citations, nil
+}
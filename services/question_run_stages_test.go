@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// stageStore is an in-memory question_run_stage_status plus the stage rows
+// stored for each run, shared by the stage repository and the row fakes.
+type stageStore struct {
+	statuses  map[uuid.UUID]*QuestionRunStageStatus
+	mentions  map[uuid.UUID][]*models.QuestionRunMention
+	claims    map[uuid.UUID][]*models.QuestionRunClaim
+	citations map[uuid.UUID][]*models.QuestionRunCitation
+}
+
+func newStageStore() *stageStore {
+	return &stageStore{
+		statuses:  make(map[uuid.UUID]*QuestionRunStageStatus),
+		mentions:  make(map[uuid.UUID][]*models.QuestionRunMention),
+		claims:    make(map[uuid.UUID][]*models.QuestionRunClaim),
+		citations: make(map[uuid.UUID][]*models.QuestionRunCitation),
+	}
+}
+
+func (s *stageStore) Upsert(ctx context.Context, status *QuestionRunStageStatus) error {
+	copied := *status
+	s.statuses[status.QuestionRunID] = &copied
+	return nil
+}
+
+func (s *stageStore) GetByQuestionRun(ctx context.Context, questionRunID uuid.UUID) (*QuestionRunStageStatus, error) {
+	status, ok := s.statuses[questionRunID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *status
+	return &copied, nil
+}
+
+func (s *stageStore) ListIncompleteByOrg(ctx context.Context, orgID uuid.UUID) ([]*QuestionRunStageStatus, error) {
+	var out []*QuestionRunStageStatus
+	for _, status := range s.statuses {
+		if status.NeedsRepair() {
+			out = append(out, status)
+		}
+	}
+	return out, nil
+}
+
+func (s *stageStore) GetMentions(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunMention, error) {
+	return s.mentions[questionRunID], nil
+}
+
+func (s *stageStore) GetClaims(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunClaim, error) {
+	return s.claims[questionRunID], nil
+}
+
+func (s *stageStore) GetCitations(ctx context.Context, questionRunID uuid.UUID) ([]*models.QuestionRunCitation, error) {
+	return s.citations[questionRunID], nil
+}
+
+type stageClaimRows struct {
+	interfaces.QuestionRunClaimRepository
+	store *stageStore
+}
+
+func (r stageClaimRows) BulkCreate(ctx context.Context, claims []*models.QuestionRunClaim) error {
+	for _, c := range claims {
+		r.store.claims[c.QuestionRunID] = append(r.store.claims[c.QuestionRunID], c)
+	}
+	return nil
+}
+
+type stageCitationRows struct {
+	interfaces.QuestionRunCitationRepository
+	store  *stageStore
+	runIDs map[uuid.UUID]uuid.UUID // claim ID -> run ID
+}
+
+func (r stageCitationRows) BulkCreate(ctx context.Context, citations []*models.QuestionRunCitation) error {
+	for _, c := range citations {
+		runID := r.runIDs[c.QuestionRunClaimID]
+		r.store.citations[runID] = append(r.store.citations[runID], c)
+	}
+	return nil
+}
+
+type stageRunRows struct {
+	interfaces.QuestionRunRepository
+	runs []*models.QuestionRun
+}
+
+func (r stageRunRows) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.QuestionRun, error) {
+	var out []*models.QuestionRun
+	for _, run := range r.runs {
+		for _, id := range ids {
+			if run.QuestionRunID == id {
+				out = append(out, run)
+			}
+		}
+	}
+	return out, nil
+}
+
+// stageExtractor records which extraction stages ran.
+type stageExtractor struct {
+	DataExtractionService
+	calls        []string
+	citedClaims  []*models.QuestionRunClaim
+	claimsResult []*models.QuestionRunClaim
+}
+
+func (e *stageExtractor) ExtractMentions(ctx context.Context, questionRunID, orgID uuid.UUID, response, targetCompany string, orgWebsites []string) ([]*models.QuestionRunMention, error) {
+	e.calls = append(e.calls, "mentions")
+	return nil, nil
+}
+
+func (e *stageExtractor) ExtractClaims(ctx context.Context, questionRunID uuid.UUID, response, targetCompany string, orgWebsites []string) ([]*models.QuestionRunClaim, error) {
+	e.calls = append(e.calls, "claims")
+	return e.claimsResult, nil
+}
+
+func (e *stageExtractor) ExtractCitations(ctx context.Context, claims []*models.QuestionRunClaim, response string, orgWebsites []string) ([]*models.QuestionRunCitation, error) {
+	e.calls = append(e.calls, "citations")
+	e.citedClaims = claims
+	url := "https://acme.example"
+	return []*models.QuestionRunCitation{{QuestionRunCitationID: uuid.New(), QuestionRunClaimID: claims[0].QuestionRunClaimID, SourceURL: &url}}, nil
+}
+
+func (e *stageExtractor) CalculateMetrics(ctx context.Context, mentions []*models.QuestionRunMention, response, targetCompany string) (*CompetitiveMetrics, error) {
+	e.calls = append(e.calls, "metrics")
+	return nil, errors.New("metrics must not be recalculated")
+}
+
+// TestRepairQuestionRunStages repairs runs with failed stages and checks only
+// those stages are extracted again, fed from the stored outputs of the rest.
+func TestRepairQuestionRunStages(t *testing.T) {
+	orgID := uuid.New()
+	response := "Acme Bank has the best rates."
+
+	tests := []struct {
+		name          string
+		stages        QuestionRunStageStatus
+		storedClaims  int
+		newClaims     int
+		wantCalls     []string
+		wantStages    QuestionRunStageStatus
+		wantCitations int
+	}{
+		{
+			name:          "citations failed",
+			stages:        QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageOK, CitationsStatus: StageFailed, MetricsStatus: StageOK},
+			storedClaims:  2,
+			wantCalls:     []string{"citations"},
+			wantStages:    QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageOK, CitationsStatus: StageOK, MetricsStatus: StageOK},
+			wantCitations: 1,
+		},
+		{
+			name:          "claims failed repairs claims then citations",
+			stages:        QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageFailed, CitationsStatus: StagePending, MetricsStatus: StageOK},
+			newClaims:     1,
+			wantCalls:     []string{"claims", "citations"},
+			wantStages:    QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageOK, CitationsStatus: StageOK, MetricsStatus: StageOK},
+			wantCitations: 1,
+		},
+		{
+			name:       "citations skipped by plan are not repaired",
+			stages:     QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageOK, CitationsStatus: StageSkippedPlan, MetricsStatus: StageOK},
+			wantStages: QuestionRunStageStatus{MentionsStatus: StageOK, ClaimsStatus: StageOK, CitationsStatus: StageSkippedPlan, MetricsStatus: StageOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &models.QuestionRun{QuestionRunID: uuid.New(), ResponseText: &response}
+			store := newStageStore()
+			stages := tt.stages
+			stages.QuestionRunID = run.QuestionRunID
+			store.statuses[run.QuestionRunID] = &stages
+
+			claimRuns := make(map[uuid.UUID]uuid.UUID)
+			claim := func(order int) *models.QuestionRunClaim {
+				c := &models.QuestionRunClaim{QuestionRunClaimID: uuid.New(), QuestionRunID: run.QuestionRunID, ClaimText: "Acme Bank has the best rates.", ClaimOrder: order}
+				claimRuns[c.QuestionRunClaimID] = run.QuestionRunID
+				return c
+			}
+			for i := 0; i < tt.storedClaims; i++ {
+				store.claims[run.QuestionRunID] = append(store.claims[run.QuestionRunID], claim(i))
+			}
+			extractor := &stageExtractor{}
+			for i := 0; i < tt.newClaims; i++ {
+				extractor.claimsResult = append(extractor.claimsResult, claim(i))
+			}
+
+			s := &questionRunnerService{
+				cfg:                   &config.Config{},
+				dataExtractionService: extractor,
+				repos: &RepositoryManager{
+					QuestionRunRepo:      stageRunRows{runs: []*models.QuestionRun{run}},
+					ClaimRepo:            stageClaimRows{store: store},
+					CitationRepo:         stageCitationRows{store: store, runIDs: claimRuns},
+					QuestionRunStageRepo: store,
+				},
+			}
+
+			incomplete, err := s.ListRunsWithIncompleteStages(context.Background(), orgID)
+			if err != nil {
+				t.Fatalf("ListRunsWithIncompleteStages() error = %v", err)
+			}
+			if wantListed := len(tt.wantCalls) > 0; (len(incomplete) == 1) != wantListed {
+				t.Errorf("%d runs listed for repair, want listed = %t", len(incomplete), wantListed)
+			}
+
+			got, err := s.RepairQuestionRunStages(context.Background(), run.QuestionRunID, orgID, "Acme Bank", nil)
+			if err != nil {
+				t.Fatalf("RepairQuestionRunStages() error = %v", err)
+			}
+			if !reflect.DeepEqual(extractor.calls, tt.wantCalls) {
+				t.Errorf("stages extracted = %v, want %v", extractor.calls, tt.wantCalls)
+			}
+			tt.wantStages.QuestionRunID = run.QuestionRunID
+			saved := store.statuses[run.QuestionRunID]
+			for name, status := range map[string]*QuestionRunStageStatus{"returned": got, "saved": saved} {
+				status.UpdatedAt = tt.wantStages.UpdatedAt
+				if *status != tt.wantStages {
+					t.Errorf("%s stages = %+v, want %+v", name, *status, tt.wantStages)
+				}
+			}
+			if n := len(store.citations[run.QuestionRunID]); n != tt.wantCitations {
+				t.Errorf("%d citations stored, want %d", n, tt.wantCitations)
+			}
+			if tt.storedClaims > 0 && len(extractor.citedClaims) != tt.storedClaims {
+				t.Errorf("citations extracted for %d claims, want the %d stored", len(extractor.citedClaims), tt.storedClaims)
+			}
+		})
+	}
+}
+
+func TestRepairQuestionRunStagesErrors(t *testing.T) {
+	s := &questionRunnerService{repos: &RepositoryManager{}}
+	if _, err := s.RepairQuestionRunStages(context.Background(), uuid.New(), uuid.New(), "Acme", nil); err == nil {
+		t.Error("repair without a stage repository succeeded")
+	}
+	s.repos.QuestionRunStageRepo = newStageStore()
+	if _, err := s.RepairQuestionRunStages(context.Background(), uuid.New(), uuid.New(), "Acme", nil); err == nil {
+		t.Error("repair of a run without stage status succeeded")
+	}
+}
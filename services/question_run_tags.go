@@ -0,0 +1,152 @@
+// services/question_run_tags.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Every run stores the tags its question had when it ran, so analytics can
+// segment SOV by question category without joining back through the current
+// question tags. It is an intentional snapshot: the row is written once when
+// the run is created and later tag edits don't rewrite it. question_runs is
+// owned by the senso-api migrations, so the tags live in their own table:
+//
+//	migrations/000012_question_run_tags.up.sql
+
+// UntaggedLabel stands for runs without tags in tag breakdowns.
+const UntaggedLabel = "(untagged)"
+
+// QuestionTags returns the normalized tag names of a question (see
+// NormalizeQuestionTags), the snapshot stored with each of its runs.
+func QuestionTags(question interfaces.GeoQuestionWithTags) []string {
+	names := make([]string, 0, len(question.Tags))
+	for _, tag := range question.Tags {
+		names = append(names, tag.Name)
+	}
+	return NormalizeQuestionTags(names)
+}
+
+// NormalizeQuestionTags trims and lowercases tag names, drops blanks and
+// duplicates and sorts the rest, so the same set of tags always compares equal.
+func NormalizeQuestionTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag := strings.ToLower(strings.TrimSpace(name))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// QuestionRunTagRepository stores the tag snapshot of question runs.
+type QuestionRunTagRepository interface {
+	// SetTags stores the run's tags; a run that already has a snapshot keeps it.
+	SetTags(ctx context.Context, questionRunID uuid.UUID, tags []string) error
+	// GetByRunIDs returns the tags of the given runs; runs without a snapshot are absent.
+	GetByRunIDs(ctx context.Context, questionRunIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+	// CountByBatch returns the number of runs of a batch per tag; runs without
+	// tags are counted under UntaggedLabel.
+	CountByBatch(ctx context.Context, batchID uuid.UUID) (map[string]int, error)
+}
+
+type questionRunTagRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunTagRepo(db *database.Client) QuestionRunTagRepository {
+	return &questionRunTagRepo{db: db}
+}
+
+func (r *questionRunTagRepo) SetTags(ctx context.Context, questionRunID uuid.UUID, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_tags (question_run_id, tags)
+		VALUES ($1, $2)
+		ON CONFLICT (question_run_id) DO NOTHING`,
+		questionRunID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to store question run tags: %w", err)
+	}
+	return nil
+}
+
+func (r *questionRunTagRepo) GetByRunIDs(ctx context.Context, questionRunIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string, len(questionRunIDs))
+	if len(questionRunIDs) == 0 {
+		return tags, nil
+	}
+	ids := make([]string, len(questionRunIDs))
+	for i, id := range questionRunIDs {
+		ids[i] = id.String()
+	}
+
+	var rows []struct {
+		QuestionRunID uuid.UUID      `db:"question_run_id"`
+		Tags          pq.StringArray `db:"tags"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT question_run_id, tags
+		FROM question_run_tags
+		WHERE question_run_id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question run tags: %w", err)
+	}
+	for _, row := range rows {
+		tags[row.QuestionRunID] = []string(row.Tags)
+	}
+	return tags, nil
+}
+
+func (r *questionRunTagRepo) CountByBatch(ctx context.Context, batchID uuid.UUID) (map[string]int, error) {
+	var rows []struct {
+		Tag  string `db:"tag"`
+		Runs int    `db:"runs"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT COALESCE(t.tag, $2) AS tag, COUNT(*) AS runs
+		FROM question_runs qr
+		LEFT JOIN question_run_tags qrt ON qrt.question_run_id = qr.question_run_id
+		LEFT JOIN LATERAL unnest(qrt.tags) AS t(tag) ON true
+		WHERE qr.batch_id = $1
+		GROUP BY 1`, batchID, UntaggedLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch runs by tag: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Tag] = row.Runs
+	}
+	return counts, nil
+}
+
+// SetQuestionRunTags stores the tag snapshot of a newly created run. It is a
+// no-op when the repository is not configured.
+func SetQuestionRunTags(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, tags []string) error {
+	if repos.QuestionRunTagRepo == nil {
+		return nil
+	}
+	return repos.QuestionRunTagRepo.SetTags(ctx, questionRunID, tags)
+}
+
+// recordQuestionTags is SetQuestionRunTags for the pipelines; failures are
+// logged, not fatal.
+func recordQuestionTags(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, tags []string) {
+	if err := SetQuestionRunTags(ctx, repos, questionRunID, tags); err != nil {
+		fmt.Printf("[recordQuestionTags] Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,130 @@
+// services/question_run_truncation.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Truncated responses: a streaming provider that hits its deadline returns the
+// content generated so far with AIResponse.Truncated set. The pipeline drops
+// those by default (STORE_TRUNCATED_RESPONSES=false); when they are kept, the
+// run is recorded here so it can be told apart from a complete answer.
+// Answers cut at the output token limit (TruncationReasonLength) were always
+// stored and still are, but are recorded here too, and claims extraction
+// ignores their trailing incomplete sentence.
+//
+// question_runs is owned by the senso-api migrations, so the marker lives in
+// its own table:
+//
+//	migrations/000002_question_run_truncations.up.sql
+
+// TruncationReasonLength marks a response cut at the model's output token
+// limit (finish_reason=length, Responses API max_output_tokens).
+const TruncationReasonLength = "length"
+
+// markLengthTruncated flags response as cut at the output limit and logs the
+// model and the limit in effect (0 is the provider's own default).
+func markLengthTruncated(response *AIResponse, logTag, model string, limit int) {
+	response.Truncated = true
+	response.TruncationReason = TruncationReasonLength
+	limitDesc := "provider default"
+	if limit > 0 {
+		limitDesc = fmt.Sprintf("%d", limit)
+	}
+	fmt.Printf("[%s] ⚠️ Response for model %s hit the output token limit (%s, output_tokens=%d), marking truncated\n", logTag, model, limitDesc, response.OutputTokens)
+}
+
+// trimTrailingIncompleteSentence drops what follows the last complete sentence
+// or line of a truncated response, so "The credit union offers" doesn't become
+// a claim. Text that has no complete sentence or line is returned unchanged.
+func trimTrailingIncompleteSentence(text string) string {
+	trimmed := strings.TrimRightFunc(text, unicode.IsSpace)
+	runes := []rune(trimmed)
+	if len(runes) == 0 || isSentenceEnd(runes, len(runes)-1) {
+		return trimmed
+	}
+	for i := len(runes) - 2; i >= 0; i-- {
+		if runes[i] == '\n' || (isSentenceEnd(runes, i) && unicode.IsSpace(runes[i+1])) {
+			return strings.TrimRightFunc(string(runes[:i+1]), unicode.IsSpace)
+		}
+	}
+	return text
+}
+
+// isSentenceEnd reports whether runes[i] ends a sentence: a terminator, or a
+// closing quote/bracket right after one.
+func isSentenceEnd(runes []rune, i int) bool {
+	for i >= 0 && strings.ContainsRune(`"')]”’*`, runes[i]) {
+		i--
+	}
+	return i >= 0 && strings.ContainsRune(".!?…", runes[i])
+}
+
+// QuestionRunTruncationRepository records which question runs hold a partial response.
+type QuestionRunTruncationRepository interface {
+	MarkTruncated(ctx context.Context, questionRunID uuid.UUID, outputTokens int) error
+}
+
+type questionRunTruncationRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunTruncationRepo(db *database.Client) QuestionRunTruncationRepository {
+	return &questionRunTruncationRepo{db: db}
+}
+
+func (r *questionRunTruncationRepo) MarkTruncated(ctx context.Context, questionRunID uuid.UUID, outputTokens int) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_truncations (question_run_id, output_tokens)
+		VALUES ($1, $2)
+		ON CONFLICT (question_run_id) DO UPDATE SET output_tokens = EXCLUDED.output_tokens`,
+		questionRunID, outputTokens)
+	if err != nil {
+		return fmt.Errorf("failed to mark question run truncated: %w", err)
+	}
+	return nil
+}
+
+// MarkQuestionRunTruncated records a stored run as holding a partial response.
+// It is a no-op when the repository is not configured.
+func MarkQuestionRunTruncated(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, outputTokens int) error {
+	if repos.QuestionRunTruncationRepo == nil {
+		return nil
+	}
+	return repos.QuestionRunTruncationRepo.MarkTruncated(ctx, questionRunID, outputTokens)
+}
+
+// rejectTruncated reports whether a truncated response must be treated as a
+// failed run, marking it non-processable. Partial runs are only kept when
+// STORE_TRUNCATED_RESPONSES is enabled; answers cut at the output limit are
+// always kept.
+func rejectTruncated(cfg *config.Config, aiResponse *AIResponse) bool {
+	if aiResponse == nil || !aiResponse.Truncated || aiResponse.TruncationReason == TruncationReasonLength {
+		return false
+	}
+	if cfg != nil && cfg.StoreTruncatedResponses {
+		return false
+	}
+	aiResponse.SkipReason = "response truncated before completion (STORE_TRUNCATED_RESPONSES=false)"
+	aiResponse.markFailed(ErrorCodeTimeout, aiResponse.SkipReason)
+	return true
+}
+
+// recordTruncation marks a stored run as partial; failures are logged, not fatal.
+func recordTruncation(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, aiResponse *AIResponse) {
+	if !aiResponse.Truncated {
+		return
+	}
+	if err := MarkQuestionRunTruncated(ctx, repos, questionRunID, aiResponse.OutputTokens); err != nil {
+		fmt.Printf("[recordTruncation] Warning: %v\n", err)
+	} else {
+		fmt.Printf("[recordTruncation] ⚠️ Stored partial response for question run %s\n", questionRunID)
+	}
+}
@@ -0,0 +1,81 @@
+// services/question_run_yield.go
+package services
+
+import (
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/google/uuid"
+)
+
+// QuestionRunYield counts the rows a question run's extraction stages stored
+// and what the extraction calls cost. The extraction calls report their cost
+// only on the rows they return (every row of a call carries the call's
+// cost), so a call that extracted nothing adds no cost.
+type QuestionRunYield struct {
+	Mentions       int
+	Claims         int
+	Citations      int
+	ExtractionCost float64
+}
+
+func (y *QuestionRunYield) add(other QuestionRunYield) {
+	y.Mentions += other.Mentions
+	y.Claims += other.Claims
+	y.Citations += other.Citations
+	y.ExtractionCost += other.ExtractionCost
+}
+
+// QuestionRunResult is a processed question run and its extraction yield.
+type QuestionRunResult struct {
+	Run   *models.QuestionRun
+	Yield QuestionRunYield
+}
+
+// QuestionMatrixSummary is the outcome of RunQuestionMatrix.
+type QuestionMatrixSummary struct {
+	Runs []*models.QuestionRun
+	// Yield sums the extraction yield of every run in Runs.
+	Yield QuestionRunYield
+	// ResponseCost sums the question (AI call) cost of every run in Runs.
+	ResponseCost float64
+}
+
+// callCost returns the cost of the call that produced rows, read from the
+// first row that has one; every row of a call carries the same cost.
+func callCost(costs ...*float64) float64 {
+	for _, cost := range costs {
+		if cost != nil {
+			return *cost
+		}
+	}
+	return 0
+}
+
+func mentionsCost(mentions []*models.QuestionRunMention) float64 {
+	costs := make([]*float64, 0, len(mentions))
+	for _, m := range mentions {
+		costs = append(costs, m.TotalCost)
+	}
+	return callCost(costs...)
+}
+
+func claimsCost(claims []*models.QuestionRunClaim) float64 {
+	costs := make([]*float64, 0, len(claims))
+	for _, c := range claims {
+		costs = append(costs, c.TotalCost)
+	}
+	return callCost(costs...)
+}
+
+// citationsCost sums the per-claim citation calls: one cost per claim.
+func citationsCost(citations []*models.QuestionRunCitation) float64 {
+	total := 0.0
+	seen := make(map[uuid.UUID]bool)
+	for _, c := range citations {
+		if seen[c.QuestionRunClaimID] || c.TotalCost == nil {
+			continue
+		}
+		seen[c.QuestionRunClaimID] = true
+		total += *c.TotalCost
+	}
+	return total
+}
@@ -3,6 +3,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -20,24 +21,41 @@ type questionRunnerService struct {
 	repos                 *RepositoryManager
 	dataExtractionService DataExtractionService
 	orgService            OrgService
+	features              OrgFeatureProvider
+	providers             ProviderFactory // nil: routed by selectProvider
 }
 
+// ProviderFactory returns the provider to run model with.
+type ProviderFactory func(model string) (AIProvider, error)
+
 func NewQuestionRunnerService(cfg *config.Config, repos *RepositoryManager, dataExtractionService DataExtractionService, orgService OrgService) QuestionRunnerService {
 	return &questionRunnerService{
 		cfg:                   cfg,
-		costService:           NewCostService(),
+		costService:           NewCostService(cfg),
 		repos:                 repos,
 		dataExtractionService: dataExtractionService,
 		orgService:            orgService,
+		features:              NewConfigOrgFeatureProvider(cfg),
 	}
 }
 
+// NewQuestionRunnerServiceWithProviders is NewQuestionRunnerService with every
+// model's provider built by providers instead of the config routing, for tests
+// and offline tooling (see providertest.FakeProvider).
+func NewQuestionRunnerServiceWithProviders(cfg *config.Config, repos *RepositoryManager, dataExtractionService DataExtractionService, orgService OrgService, providers ProviderFactory) QuestionRunnerService {
+	s := NewQuestionRunnerService(cfg, repos, dataExtractionService, orgService).(*questionRunnerService)
+	s.providers = providers
+	return s
+}
+
 // RunQuestionMatrix processes all questions across models and locations, storing results in database
-func (s *questionRunnerService) RunQuestionMatrix(ctx context.Context, orgDetails *RealOrgDetails) ([]*models.QuestionRun, error) {
+func (s *questionRunnerService) RunQuestionMatrix(ctx context.Context, orgDetails *RealOrgDetails) (*QuestionMatrixSummary, error) {
 	fmt.Printf("[RunQuestionMatrix] Processing %d questions across %d models and %d locations\n",
 		len(orgDetails.Questions), len(orgDetails.Models), len(orgDetails.Locations))
 
-	var allRuns []*models.QuestionRun
+	summary := &QuestionMatrixSummary{}
+	unsupportedWebSearch := 0
+	emptyResponses := 0
 
 	// Process each question
 	for _, questionWithTags := range orgDetails.Questions {
@@ -47,14 +65,24 @@ func (s *questionRunnerService) RunQuestionMatrix(ctx context.Context, orgDetail
 		for _, model := range orgDetails.Models {
 			for _, location := range orgDetails.Locations {
 				// Process single question run with full pipeline
-				run, err := s.ProcessSingleQuestion(ctx, question, model, location, orgDetails.TargetCompany, orgDetails.Websites)
+				result, err := s.ProcessSingleQuestion(ctx, question, QuestionTags(questionWithTags), model, location, orgDetails.TargetCompany, orgDetails.Websites)
 				if err != nil {
 					fmt.Printf("[RunQuestionMatrix] Error processing question %s with model %s at location %s: %v\n",
 						question.GeoQuestionID, model.Name, location.CountryCode, err)
+					if errors.Is(err, ErrWebSearchUnsupported) {
+						unsupportedWebSearch++
+					}
+					if errors.Is(err, ErrEmptyResponse) {
+						emptyResponses++
+					}
 					continue
 				}
 
-				allRuns = append(allRuns, run)
+				summary.Runs = append(summary.Runs, result.Run)
+				summary.Yield.add(result.Yield)
+				if result.Run.TotalCost != nil {
+					summary.ResponseCost += *result.Run.TotalCost
+				}
 				fmt.Printf("[RunQuestionMatrix] Successfully processed question %s with model %s at location %s\n",
 					question.GeoQuestionID, model.Name, location.CountryCode)
 			}
@@ -62,22 +90,42 @@ func (s *questionRunnerService) RunQuestionMatrix(ctx context.Context, orgDetail
 	}
 
 	// Update latest flags for all questions
-	if err := s.updateLatestFlags(ctx, orgDetails.Questions, allRuns); err != nil {
+	if err := s.updateLatestFlags(ctx, orgDetails.Questions, summary.Runs); err != nil {
 		fmt.Printf("[RunQuestionMatrix] Warning: Failed to update latest flags: %v\n", err)
 	}
 
-	fmt.Printf("[RunQuestionMatrix] Completed processing: %d total runs created\n", len(allRuns))
-	return allRuns, nil
+	fmt.Printf("[RunQuestionMatrix] Completed processing: %d total runs created, %d mentions, %d claims, %d citations, $%.6f question cost, $%.6f extraction cost\n",
+		len(summary.Runs), summary.Yield.Mentions, summary.Yield.Claims, summary.Yield.Citations, summary.ResponseCost, summary.Yield.ExtractionCost)
+	if unsupportedWebSearch > 0 {
+		fmt.Printf("[RunQuestionMatrix] ⚠️ Skipped %d question runs: %v\n", unsupportedWebSearch, ErrWebSearchUnsupported)
+	}
+	if emptyResponses > 0 {
+		fmt.Printf("[RunQuestionMatrix] ⚠️ Failed %d question runs: %v\n", emptyResponses, ErrEmptyResponse)
+	}
+	return summary, nil
 }
 
-// ProcessSingleQuestion handles the complete pipeline for one question run
-func (s *questionRunnerService) ProcessSingleQuestion(ctx context.Context, question *models.GeoQuestion, model *models.GeoModel, location *models.OrgLocation, targetCompany string, orgWebsites []string) (*models.QuestionRun, error) {
-	fmt.Printf("[ProcessSingleQuestion] Processing question %s with model %s\n", question.GeoQuestionID, model.Name)
+// ProcessSingleQuestion handles the complete pipeline for one question run and
+// reports how many mentions, claims and citations it stored.
+func (s *questionRunnerService) ProcessSingleQuestion(ctx context.Context, question *models.GeoQuestion, tags []string, model *models.GeoModel, location *models.OrgLocation, targetCompany string, orgWebsites []string) (*QuestionRunResult, error) {
+	// Every log line of this run, down to the extraction stages, carries the ID
+	if correlationID(ctx) == "" {
+		ctx = WithCorrelationID(ctx, uuid.NewString())
+	}
+	fmt.Printf("[%s] Processing question %s with model %s\n", correlationTag(ctx, "ProcessSingleQuestion"), question.GeoQuestionID, model.Name)
 
 	// 1. Execute AI call
 	aiResponse, err := s.executeAICall(ctx, question.QuestionText, model.Name, location)
 	if err != nil {
-		return nil, fmt.Errorf("AI call failed: %w", err)
+		return nil, fmt.Errorf("AI call failed: %w", ClassifyProviderError(model.Name, err))
+	}
+	if rejectTruncated(s.cfg, aiResponse) {
+		return nil, fmt.Errorf("AI call failed: %w", aiResponse.Err(model.Name))
+	}
+	if isEmptyResponse(s.cfg, aiResponse.Response) {
+		fmt.Printf("[%s] ⚠️ Empty response from model %s for question %s (%q), not storing run\n", correlationTag(ctx, "ProcessSingleQuestion"),
+			model.Name, question.GeoQuestionID, question.QuestionText)
+		return nil, fmt.Errorf("AI call failed: %w", emptyResponseError(aiResponse.Response))
 	}
 
 	// 2. Create initial question run record
@@ -99,62 +147,273 @@ func (s *questionRunnerService) ProcessSingleQuestion(ctx context.Context, quest
 	if err := s.repos.QuestionRunRepo.Create(ctx, run); err != nil {
 		return nil, fmt.Errorf("failed to create question run: %w", err)
 	}
+	recordTruncation(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordWebSearchDowngrade(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordResponseLanguage(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordQuestionTags(ctx, s.repos, run.QuestionRunID, tags)
+
+	// Track each extraction stage so a repair pass can re-run only what failed
+	stages := newPendingStageStatus(run.QuestionRunID)
+	// The org pipeline has no separate competitor stage (mentions feed the
+	// metrics), so only citations can be switched off by plan here.
+	if !s.features.FeaturesForOrg(ctx, location.OrgID).Citations {
+		fmt.Printf("[%s] Citations disabled by plan for org %s, skipping\n", correlationTag(ctx, "ProcessSingleQuestion"), location.OrgID)
+		stages.CitationsStatus = StageSkippedPlan
+	}
+	s.saveStageStatus(ctx, stages)
+
+	yield := s.runExtractionStages(ctx, run, stages, nil, nil, location.OrgID, aiResponse.Response, aiResponse.Truncated, false, targetCompany, orgWebsites, correlationTag(ctx, "ProcessSingleQuestion"))
+
+	fmt.Printf("[%s] Successfully completed full pipeline for question %s (%d mentions, %d claims, %d citations, extraction cost $%.6f)\n", correlationTag(ctx, "ProcessSingleQuestion"),
+		question.GeoQuestionID, yield.Mentions, yield.Claims, yield.Citations, yield.ExtractionCost)
+	return &QuestionRunResult{Run: run, Yield: yield}, nil
+}
+
+// runExtractionStages runs every stage whose status is pending or failed and
+// records the outcome after each one. Stages that are already ok feed later
+// stages from storage (mentions for metrics, claims for citations) via the
+// existing arguments, which callers load when repairing. With checkStored, a
+// stage whose rows an earlier attempt already stored reuses them instead of
+// calling the extractor again; callers pass false for a run they just created.
+// Claims of a truncated response are extracted without its trailing
+// incomplete sentence.
+// It returns the rows the stages it ran left stored and the cost of their
+// extraction calls.
+func (s *questionRunnerService) runExtractionStages(ctx context.Context, run *models.QuestionRun, stages *QuestionRunStageStatus, mentions []*models.QuestionRunMention, claims []*models.QuestionRunClaim, orgID uuid.UUID, responseText string, truncated, checkStored bool, targetCompany string, orgWebsites []string, logTag string) QuestionRunYield {
+	var yield QuestionRunYield
 
 	// 3. Extract mentions
-	mentions, err := s.dataExtractionService.ExtractMentions(ctx, run.QuestionRunID, aiResponse.Response, targetCompany, orgWebsites)
-	if err != nil {
-		fmt.Printf("[ProcessSingleQuestion] Warning: Failed to extract mentions: %v\n", err)
-	} else if len(mentions) > 0 {
-		if err := s.repos.MentionRepo.BulkCreate(ctx, mentions); err != nil {
-			fmt.Printf("[ProcessSingleQuestion] Warning: Failed to store mentions: %v\n", err)
+	if stageNeedsRun(stages.MentionsStatus) {
+		var stored []*models.QuestionRunMention
+		if checkStored {
+			stored = storedStageRows(ctx, s.repos.QuestionRunStageRepo, run.QuestionRunID, "mentions", logTag, QuestionRunStageRepository.GetMentions)
+		}
+		var extracted []*models.QuestionRunMention
+		var err error
+		if len(stored) == 0 {
+			extracted, err = s.dataExtractionService.ExtractMentions(ctx, run.QuestionRunID, orgID, responseText, targetCompany, orgWebsites)
+			yield.ExtractionCost += mentionsCost(extracted)
 		}
+		if len(stored) > 0 {
+			// An earlier attempt already stored them; use its rows
+			extracted = stored
+			stages.MentionsStatus = StageOK
+		} else if err != nil {
+			fmt.Printf("[%s] Warning: Failed to extract mentions: %v\n", logTag, err)
+			stages.MentionsStatus = extractionFailureStatus(err)
+		} else if len(extracted) > 0 {
+			if err := s.repos.MentionRepo.BulkCreate(ctx, extracted); err != nil {
+				fmt.Printf("[%s] Warning: Failed to store mentions: %v\n", logTag, err)
+				stages.MentionsStatus = StageFailed
+			} else {
+				stages.MentionsStatus = StageOK
+			}
+		} else {
+			stages.MentionsStatus = StageOK
+		}
+		if stages.MentionsStatus == StageOK {
+			yield.Mentions = len(extracted)
+		}
+		mentions = extracted
+		s.saveStageStatus(ctx, stages)
 	}
 
 	// 4. Extract claims
-	claims, err := s.dataExtractionService.ExtractClaims(ctx, run.QuestionRunID, aiResponse.Response, targetCompany, orgWebsites)
-	if err != nil {
-		fmt.Printf("[ProcessSingleQuestion] Warning: Failed to extract claims: %v\n", err)
-	} else if len(claims) > 0 {
-		if err := s.repos.ClaimRepo.BulkCreate(ctx, claims); err != nil {
-			fmt.Printf("[ProcessSingleQuestion] Warning: Failed to store claims: %v\n", err)
+	if stageNeedsRun(stages.ClaimsStatus) {
+		claimsText := responseText
+		if truncated {
+			claimsText = trimTrailingIncompleteSentence(responseText)
 		}
-
-		// 5. Extract citations for claims - now passing org websites
-		citations, err := s.dataExtractionService.ExtractCitations(ctx, claims, aiResponse.Response, orgWebsites)
-		if err != nil {
-			fmt.Printf("[ProcessSingleQuestion] Warning: Failed to extract citations: %v\n", err)
-		} else if len(citations) > 0 {
-			if err := s.repos.CitationRepo.BulkCreate(ctx, citations); err != nil {
-				fmt.Printf("[ProcessSingleQuestion] Warning: Failed to store citations: %v\n", err)
+		var stored []*models.QuestionRunClaim
+		if checkStored {
+			stored = storedStageRows(ctx, s.repos.QuestionRunStageRepo, run.QuestionRunID, "claims", logTag, QuestionRunStageRepository.GetClaims)
+		}
+		var extracted []*models.QuestionRunClaim
+		var err error
+		if len(stored) == 0 {
+			extracted, err = s.dataExtractionService.ExtractClaims(ctx, run.QuestionRunID, claimsText, targetCompany, orgWebsites)
+			yield.ExtractionCost += claimsCost(extracted)
+		}
+		if len(stored) > 0 {
+			// Citations must hang off the stored claims
+			extracted = stored
+			stages.ClaimsStatus = StageOK
+		} else if err != nil {
+			fmt.Printf("[%s] Warning: Failed to extract claims: %v\n", logTag, err)
+			stages.ClaimsStatus = extractionFailureStatus(err)
+		} else if len(extracted) > 0 {
+			if err := s.repos.ClaimRepo.BulkCreate(ctx, extracted); err != nil {
+				fmt.Printf("[%s] Warning: Failed to store claims: %v\n", logTag, err)
+				stages.ClaimsStatus = StageFailed
+			} else {
+				stages.ClaimsStatus = StageOK
+			}
+		} else {
+			stages.ClaimsStatus = StageOK
+		}
+		if stages.ClaimsStatus == StageOK {
+			yield.Claims = len(extracted)
+		}
+		claims = extracted
+		s.saveStageStatus(ctx, stages)
+	}
+
+	// 5. Extract citations for claims - now passing org websites
+	if stageNeedsRun(stages.CitationsStatus) {
+		switch {
+		case stages.ClaimsStatus == StageFiltered:
+			stages.CitationsStatus = StageSkipped
+		case stages.ClaimsStatus != StageOK:
+			// Citations are extracted per claim; retry once claims succeed
+			stages.CitationsStatus = StagePending
+		case len(claims) == 0:
+			stages.CitationsStatus = StageSkipped
+		default:
+			var stored []*models.QuestionRunCitation
+			if checkStored {
+				stored = storedStageRows(ctx, s.repos.QuestionRunStageRepo, run.QuestionRunID, "citations", logTag, QuestionRunStageRepository.GetCitations)
+			}
+			if len(stored) > 0 {
+				stages.CitationsStatus = StageOK
+				yield.Citations = len(stored)
+				break
+			}
+			citations, err := s.dataExtractionService.ExtractCitations(ctx, claims, responseText, orgWebsites)
+			yield.ExtractionCost += citationsCost(citations)
+			if err != nil {
+				fmt.Printf("[%s] Warning: Failed to extract citations: %v\n", logTag, err)
+				stages.CitationsStatus = StageFailed
+			} else if len(citations) > 0 {
+				if err := s.repos.CitationRepo.BulkCreate(ctx, citations); err != nil {
+					fmt.Printf("[%s] Warning: Failed to store citations: %v\n", logTag, err)
+					stages.CitationsStatus = StageFailed
+				} else {
+					stages.CitationsStatus = StageOK
+					yield.Citations = len(citations)
+				}
+			} else {
+				stages.CitationsStatus = StageOK
 			}
 		}
+		s.saveStageStatus(ctx, stages)
 	}
 
 	// 6. Calculate competitive metrics
-	if len(mentions) > 0 {
-		metrics, err := s.dataExtractionService.CalculateMetrics(ctx, mentions, aiResponse.Response, targetCompany)
-		if err != nil {
-			fmt.Printf("[ProcessSingleQuestion] Warning: Failed to calculate metrics: %v\n", err)
-		} else {
-			// Update question run with metrics
-			run.TargetMentioned = metrics.TargetMentioned
-			run.TargetSOV = metrics.ShareOfVoice
-			run.TargetRank = metrics.TargetRank
-			run.TargetSentiment = metrics.TargetSentiment
-
-			if err := s.repos.QuestionRunRepo.Update(ctx, run); err != nil {
-				fmt.Printf("[ProcessSingleQuestion] Warning: Failed to update run with metrics: %v\n", err)
+	if stageNeedsRun(stages.MetricsStatus) {
+		switch {
+		case stages.MentionsStatus == StageFiltered:
+			stages.MetricsStatus = StageSkipped
+		case stages.MentionsStatus != StageOK:
+			stages.MetricsStatus = StagePending
+		case len(mentions) == 0:
+			stages.MetricsStatus = StageSkipped
+		default:
+			metrics, err := s.dataExtractionService.CalculateMetrics(ctx, mentions, responseText, targetCompany)
+			if err != nil {
+				fmt.Printf("[%s] Warning: Failed to calculate metrics: %v\n", logTag, err)
+				stages.MetricsStatus = StageFailed
+			} else {
+				// Update question run with metrics
+				run.TargetMentioned = metrics.TargetMentioned
+				run.TargetSOV = metrics.ShareOfVoice
+				run.TargetRank = metrics.TargetRank
+				run.TargetSentiment = metrics.TargetSentiment
+
+				if err := s.updateRunMetrics(ctx, run); err != nil {
+					fmt.Printf("[%s] Warning: Failed to update run with metrics: %v\n", logTag, err)
+					stages.MetricsStatus = StageFailed
+				} else {
+					stages.MetricsStatus = StageOK
+					recordShareOfVoice(ctx, s.repos, run.QuestionRunID, metrics)
+				}
 			}
 		}
+		s.saveStageStatus(ctx, stages)
 	}
+	return yield
+}
 
-	fmt.Printf("[ProcessSingleQuestion] Successfully completed full pipeline for question %s\n", question.GeoQuestionID)
-	return run, nil
+// updateRunMetrics stores the run's metric fields without rewriting the
+// rest of the row, falling back to a full Update when the metrics repo is not
+// configured.
+func (s *questionRunnerService) updateRunMetrics(ctx context.Context, run *models.QuestionRun) error {
+	if s.repos.QuestionRunMetricsRepo == nil {
+		return s.repos.QuestionRunRepo.Update(ctx, run)
+	}
+	return s.repos.QuestionRunMetricsRepo.UpdateMetrics(ctx, run.QuestionRunID, run.TargetMentioned, run.TargetSOV, run.TargetRank, run.TargetSentiment)
+}
+
+// saveStageStatus persists stage status; failures are logged, never fatal.
+func (s *questionRunnerService) saveStageStatus(ctx context.Context, stages *QuestionRunStageStatus) {
+	if s.repos.QuestionRunStageRepo == nil {
+		return
+	}
+	if err := s.repos.QuestionRunStageRepo.Upsert(ctx, stages); err != nil {
+		fmt.Printf("[saveStageStatus] Warning: Failed to save stage status for run %s: %v\n", stages.QuestionRunID, err)
+	}
+}
+
+// ListRunsWithIncompleteStages returns the stage status of every question run of
+// the org with at least one pending or failed stage.
+func (s *questionRunnerService) ListRunsWithIncompleteStages(ctx context.Context, orgID uuid.UUID) ([]*QuestionRunStageStatus, error) {
+	if s.repos.QuestionRunStageRepo == nil {
+		return nil, fmt.Errorf("stage status tracking is not configured")
+	}
+	return s.repos.QuestionRunStageRepo.ListIncompleteByOrg(ctx, orgID)
+}
+
+// RepairQuestionRunStages re-runs only the pending/failed extraction stages of an
+// existing question run. Outputs of stages that already succeeded are loaded
+// from storage rather than re-extracted.
+func (s *questionRunnerService) RepairQuestionRunStages(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, targetCompany string, orgWebsites []string) (*QuestionRunStageStatus, error) {
+	if s.repos.QuestionRunStageRepo == nil {
+		return nil, fmt.Errorf("stage status tracking is not configured")
+	}
+
+	stages, err := s.repos.QuestionRunStageRepo.GetByQuestionRun(ctx, questionRunID)
+	if err != nil {
+		return nil, err
+	}
+	if stages == nil {
+		return nil, fmt.Errorf("no stage status recorded for question run %s", questionRunID)
+	}
+	if !stages.NeedsRepair() {
+		return stages, nil
+	}
+
+	runs, err := s.repos.QuestionRunRepo.GetByIDs(ctx, []uuid.UUID{questionRunID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question run: %w", err)
+	}
+	if len(runs) == 0 || runs[0].ResponseText == nil {
+		return nil, fmt.Errorf("question run %s not found or has no response text", questionRunID)
+	}
+	run := runs[0]
+
+	var mentions []*models.QuestionRunMention
+	if stages.MentionsStatus == StageOK && stageNeedsRun(stages.MetricsStatus) {
+		if mentions, err = s.repos.QuestionRunStageRepo.GetMentions(ctx, questionRunID); err != nil {
+			return nil, err
+		}
+	}
+	var claims []*models.QuestionRunClaim
+	if stages.ClaimsStatus == StageOK && stageNeedsRun(stages.CitationsStatus) {
+		if claims, err = s.repos.QuestionRunStageRepo.GetClaims(ctx, questionRunID); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("[RepairQuestionRunStages] Repairing run %s (mentions=%s claims=%s citations=%s metrics=%s)\n",
+		questionRunID, stages.MentionsStatus, stages.ClaimsStatus, stages.CitationsStatus, stages.MetricsStatus)
+
+	// Whether the stored response was truncated is not known here
+	s.runExtractionStages(ctx, run, stages, mentions, claims, orgID, *run.ResponseText, false, true, targetCompany, orgWebsites, "RepairQuestionRunStages")
+	return stages, nil
 }
 
 // executeAICall performs the actual AI model call
 func (s *questionRunnerService) executeAICall(ctx context.Context, questionText, modelName string, location *models.OrgLocation) (*AIResponse, error) {
-	fmt.Printf("[executeAICall] 🚀 Making AI call for model: %s", modelName)
+	fmt.Printf("[%s] 🚀 Making AI call for model: %s", correlationTag(ctx, "executeAICall"), modelName)
 
 	// Convert location to workflow model format
 	workflowLocation := &workflowModels.Location{
@@ -162,15 +421,24 @@ func (s *questionRunnerService) executeAICall(ctx context.Context, questionText,
 		Region:  location.RegionName,
 	}
 
+	questionText, err := NormalizeQuestionText(questionText, workflowLocation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
+
 	// Get the appropriate AI provider
 	provider, err := s.getProvider(modelName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider: %w", err)
 	}
+	provider, err = applyWebSearchPolicy(s.cfg, provider, modelName)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine if web search should be enabled (for now, disable it)
 	webSearch := true
-	fmt.Printf("[executeAICall] 🌐 Web search enabled: %t", webSearch)
+	fmt.Printf("[%s] 🌐 Web search enabled: %t", correlationTag(ctx, "executeAICall"), webSearch)
 
 	// Execute the AI call
 	response, err := provider.RunQuestion(ctx, questionText, webSearch, workflowLocation)
@@ -178,16 +446,75 @@ func (s *questionRunnerService) executeAICall(ctx context.Context, questionText,
 		return nil, fmt.Errorf("failed to run question: %w", err)
 	}
 
-	fmt.Printf("[executeAICall] ✅ AI call completed successfully")
-	fmt.Printf("[executeAICall]   - Input tokens: %d", response.InputTokens)
-	fmt.Printf("[executeAICall]   - Output tokens: %d", response.OutputTokens)
-	fmt.Printf("[executeAICall]   - Cost: $%.6f", response.Cost)
+	fmt.Printf("[%s] ✅ AI call completed successfully", correlationTag(ctx, "executeAICall"))
+	fmt.Printf("[%s]   - Input tokens: %d", correlationTag(ctx, "executeAICall"), response.InputTokens)
+	fmt.Printf("[%s]   - Output tokens: %d", correlationTag(ctx, "executeAICall"), response.OutputTokens)
+	fmt.Printf("[%s]   - Cost: $%.6f", correlationTag(ctx, "executeAICall"), response.Cost)
+	fmt.Printf("[%s]   - Latency: %dms", correlationTag(ctx, "executeAICall"), response.LatencyMs)
+
+	return response, nil
+}
+
+// RunAdHocQuestion runs questionText like executeAICall but with the caller's
+// location and web search choice, and stores nothing.
+func (s *questionRunnerService) RunAdHocQuestion(ctx context.Context, questionText, modelName string, location *workflowModels.Location, webSearch bool) (*AIResponse, error) {
+	questionText, err := NormalizeQuestionText(questionText, location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
+
+	provider, err := s.getProvider(modelName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	if webSearch {
+		if provider, err = applyWebSearchPolicy(s.cfg, provider, modelName); err != nil {
+			return nil, err
+		}
+	}
 
+	fmt.Printf("[RunAdHocQuestion] Running model=%s country=%s web_search=%t\n", modelName, location.Country, webSearch)
+	response, err := provider.RunQuestion(ctx, questionText, webSearch, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run question: %w", err)
+	}
 	return response, nil
 }
 
-// getProvider returns the appropriate AI provider for the model
+// getProvider returns the appropriate AI provider for the model, with the
+// capability overrides of the providers config and record/replay applied.
 func (s *questionRunnerService) getProvider(model string) (AIProvider, error) {
+	return s.getProviderWithDatasets(model, defaultBrightDataDatasets(s.cfg))
+}
+
+// getNetworkProvider is getProvider for a network's pairs: BrightData-backed
+// providers submit to the network's datasets (see brightDataDatasetsFor).
+func (s *questionRunnerService) getNetworkProvider(model string, networkID uuid.UUID) (AIProvider, error) {
+	provider, err := s.getProviderWithDatasets(model, brightDataDatasetsFor(s.cfg, networkID))
+	if err != nil {
+		return nil, err
+	}
+	logProviderDataset("getNetworkProvider", s.cfg, provider, model, networkID)
+	return provider, nil
+}
+
+func (s *questionRunnerService) getProviderWithDatasets(model string, datasets config.BrightDataDatasets) (AIProvider, error) {
+	var provider AIProvider
+	var err error
+	if s.providers != nil {
+		provider, err = s.providers(model)
+	} else {
+		provider, err = s.selectProvider(model, datasets)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withReplay(s.cfg, withConfiguredCapabilities(provider, model), model), nil
+}
+
+// selectProvider routes the model: config overrides first, then the
+// built-in model-name rules.
+func (s *questionRunnerService) selectProvider(model string, datasets config.BrightDataDatasets) (AIProvider, error) {
 	modelLower := strings.ToLower(model)
 
 	// Debug the config
@@ -195,22 +522,27 @@ func (s *questionRunnerService) getProvider(model string) (AIProvider, error) {
 		return nil, fmt.Errorf("config is nil")
 	}
 
+	// Config overrides take precedence over the built-in routing below
+	if provider, err := overrideProvider(s.cfg, datasets, model, s.costService); provider != nil || err != nil {
+		return provider, err
+	}
+
 	// BrightData ChatGPT provider
 	if strings.Contains(modelLower, "chatgpt") {
 		fmt.Printf("[getProvider] 🎯 Selected BrightData ChatGPT provider for model: %s", model)
-		return NewBrightDataProvider(s.cfg, model, s.costService), nil
+		return NewBrightDataProvider(s.cfg, datasets.BrightDataDatasetID, model, s.costService), nil
 	}
 
 	// Perplexity provider (via BrightData)
 	if strings.Contains(modelLower, "perplexity") {
 		fmt.Printf("[getProvider] 🎯 Selected Perplexity provider for model: %s", model)
-		return NewPerplexityProvider(s.cfg, model, s.costService), nil
+		return NewPerplexityProvider(s.cfg, datasets.PerplexityDatasetID, model, s.costService), nil
 	}
 
 	// Gemini provider (via BrightData)
 	if strings.Contains(modelLower, "gemini") {
 		fmt.Printf("[getProvider] 🎯 Selected Gemini provider for model: %s", model)
-		return NewGeminiProvider(s.cfg, model, s.costService), nil
+		return NewGeminiProvider(s.cfg, datasets.GeminiDatasetID, model, s.costService), nil
 	}
 
 	// Linkup provider
@@ -240,7 +572,7 @@ func (s *questionRunnerService) getProvider(model string) (AIProvider, error) {
 	return nil, fmt.Errorf("unsupported model: %s", model)
 }
 
-// updateLatestFlags manages the is_latest and is_second_latest flags
+// updateLatestFlags manages the is_latest flags and the second-latest runs
 func (s *questionRunnerService) updateLatestFlags(ctx context.Context, questions []interfaces.GeoQuestionWithTags, newRuns []*models.QuestionRun) error {
 	// Group runs by question
 	runsByQuestion := make(map[uuid.UUID][]*models.QuestionRun)
@@ -248,6 +580,15 @@ func (s *questionRunnerService) updateLatestFlags(ctx context.Context, questions
 		runsByQuestion[run.GeoQuestionID] = append(runsByQuestion[run.GeoQuestionID], run)
 	}
 
+	questionIDs := make([]uuid.UUID, 0, len(runsByQuestion))
+	for questionID := range runsByQuestion {
+		questionIDs = append(questionIDs, questionID)
+	}
+	audit := beginLatestFlagAudit(ctx, s.repos, LatestFlagSourceOrgQuestions, nil, questionIDs)
+	updated := make(map[uuid.UUID]uuid.UUID, len(questionIDs))
+	defer audit.record(ctx, updated)
+	shiftSecondLatest(ctx, s.repos, questionIDs, runIDs(newRuns))
+
 	// Update latest flags for each question
 	for questionID, runs := range runsByQuestion {
 		if len(runs) == 0 {
@@ -266,6 +607,7 @@ func (s *questionRunnerService) updateLatestFlags(ctx context.Context, questions
 		if err := s.repos.QuestionRunRepo.UpdateLatestFlags(ctx, questionID, latestRun.QuestionRunID); err != nil {
 			return fmt.Errorf("failed to update latest flags for question %s: %w", questionID, err)
 		}
+		updated[questionID] = latestRun.QuestionRunID
 	}
 
 	return nil
@@ -279,6 +621,15 @@ func (s *questionRunnerService) updateNetworkLatestFlags(ctx context.Context, ne
 		runsByQuestion[run.GeoQuestionID] = append(runsByQuestion[run.GeoQuestionID], run)
 	}
 
+	questionIDs := make([]uuid.UUID, 0, len(runsByQuestion))
+	for questionID := range runsByQuestion {
+		questionIDs = append(questionIDs, questionID)
+	}
+	audit := beginLatestFlagAudit(ctx, s.repos, LatestFlagSourceNetworkQuestions, nil, questionIDs)
+	updated := make(map[uuid.UUID]uuid.UUID, len(questionIDs))
+	defer audit.record(ctx, updated)
+	shiftSecondLatest(ctx, s.repos, questionIDs, runIDs(newRuns))
+
 	// Update latest flags for each question
 	for questionID, runs := range runsByQuestion {
 		if len(runs) == 0 {
@@ -297,6 +648,7 @@ func (s *questionRunnerService) updateNetworkLatestFlags(ctx context.Context, ne
 		if err := s.repos.QuestionRunRepo.UpdateLatestFlags(ctx, questionID, latestRun.QuestionRunID); err != nil {
 			return fmt.Errorf("failed to update latest flags for question %s: %w", questionID, err)
 		}
+		updated[questionID] = latestRun.QuestionRunID
 	}
 
 	return nil
@@ -312,8 +664,8 @@ func (s *questionRunnerService) RunNetworkQuestionsQuestionOnly(ctx context.Cont
 		return nil, fmt.Errorf("invalid network ID format: %w", err)
 	}
 
-	// Get network questions
-	questions, err := s.repos.GeoQuestionRepo.GetByNetwork(ctx, networkUUID)
+	// Get network questions (with tags, which are snapshotted on the runs)
+	questions, err := s.repos.GeoQuestionRepo.GetByNetworkWithTags(ctx, networkUUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network questions: %w", err)
 	}
@@ -323,9 +675,10 @@ func (s *questionRunnerService) RunNetworkQuestionsQuestionOnly(ctx context.Cont
 	var allRuns []*models.QuestionRun
 
 	// Process each question
-	for _, question := range questions {
+	for _, questionWithTags := range questions {
+		question := questionWithTags.Question
 		// Process single network question run (question only, no extractions/evals)
-		run, err := s.ProcessNetworkQuestionOnly(ctx, question)
+		run, err := s.ProcessNetworkQuestionOnly(ctx, question, QuestionTags(questionWithTags))
 		if err != nil {
 			fmt.Printf("[RunNetworkQuestionsQuestionOnly] Error processing question %s: %v\n",
 				question.GeoQuestionID, err)
@@ -349,13 +702,16 @@ func (s *questionRunnerService) RunNetworkQuestionsQuestionOnly(ctx context.Cont
 }
 
 // ProcessNetworkQuestionOnly handles the question-only pipeline for one network question run
-func (s *questionRunnerService) ProcessNetworkQuestionOnly(ctx context.Context, question *models.GeoQuestion) (*models.QuestionRun, error) {
+func (s *questionRunnerService) ProcessNetworkQuestionOnly(ctx context.Context, question *models.GeoQuestion, tags []string) (*models.QuestionRun, error) {
 	fmt.Printf("[ProcessNetworkQuestionOnly] Processing question %s\n", question.GeoQuestionID)
 
 	// Execute AI call with websearch (no location)
 	aiResponse, err := s.executeNetworkAICall(ctx, question.QuestionText)
 	if err != nil {
-		return nil, fmt.Errorf("AI call failed: %w", err)
+		return nil, fmt.Errorf("AI call failed: %w", ClassifyProviderError("network", err))
+	}
+	if rejectTruncated(s.cfg, aiResponse) {
+		return nil, fmt.Errorf("AI call failed: %w", aiResponse.Err("network"))
 	}
 
 	// Create question run record (no model_id, no location_id for network questions)
@@ -380,6 +736,10 @@ func (s *questionRunnerService) ProcessNetworkQuestionOnly(ctx context.Context,
 	if err := s.repos.QuestionRunRepo.Create(ctx, run); err != nil {
 		return nil, fmt.Errorf("failed to create question run: %w", err)
 	}
+	recordTruncation(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordWebSearchDowngrade(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordResponseLanguage(ctx, s.repos, run.QuestionRunID, aiResponse)
+	recordQuestionTags(ctx, s.repos, run.QuestionRunID, tags)
 
 	fmt.Printf("[ProcessNetworkQuestionOnly] Successfully completed question-only pipeline for question %s\n", question.GeoQuestionID)
 	return run, nil
@@ -394,6 +754,16 @@ func (s *questionRunnerService) executeNetworkAICall(ctx context.Context, questi
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider: %w", err)
 	}
+	provider, err = applyWebSearchPolicy(s.cfg, provider, "gpt-4.1")
+	if err != nil {
+		return nil, err
+	}
+
+	// No location context here, so location placeholders can't be substituted
+	questionText, err = NormalizeQuestionText(questionText, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
 
 	// Execute the AI call with websearch (no location)
 	response, err := provider.RunQuestionWebSearch(ctx, questionText)
@@ -405,6 +775,7 @@ func (s *questionRunnerService) executeNetworkAICall(ctx context.Context, questi
 	fmt.Printf("[executeNetworkAICall]   - Input tokens: %d", response.InputTokens)
 	fmt.Printf("[executeNetworkAICall]   - Output tokens: %d", response.OutputTokens)
 	fmt.Printf("[executeNetworkAICall]   - Cost: $%.6f", response.Cost)
+	fmt.Printf("[executeNetworkAICall]   - Latency: %dms", response.LatencyMs)
 
 	return response, nil
 }
@@ -446,6 +817,14 @@ func (s *questionRunnerService) UpdateNetworkLatestFlags(ctx context.Context, ne
 		return nil
 	}
 
+	questionIDs := make([]uuid.UUID, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.GeoQuestionID
+	}
+	audit := beginLatestFlagAudit(ctx, s.repos, LatestFlagSourceNetworkQuestions, nil, questionIDs)
+	updated := make(map[uuid.UUID]uuid.UUID, len(questions))
+	defer audit.record(ctx, updated)
+
 	// Update latest flags for each question
 	for _, question := range questions {
 		// Get all runs for this question
@@ -473,10 +852,13 @@ func (s *questionRunnerService) UpdateNetworkLatestFlags(ctx context.Context, ne
 		}
 
 		// Update latest flags using the existing method
+		shiftSecondLatest(ctx, s.repos, []uuid.UUID{question.GeoQuestionID}, []uuid.UUID{latestRun.QuestionRunID})
 		if err := s.repos.QuestionRunRepo.UpdateLatestFlags(ctx, question.GeoQuestionID, latestRun.QuestionRunID); err != nil {
 			fmt.Printf("[UpdateNetworkLatestFlags] Warning: failed to update latest flags for question %s: %v\n",
 				question.GeoQuestionID, err)
+			continue
 		}
+		updated[question.GeoQuestionID] = latestRun.QuestionRunID
 	}
 
 	fmt.Printf("[UpdateNetworkLatestFlags] Successfully updated latest flags for %d questions in network: %s\n",
@@ -495,13 +877,13 @@ func (s *questionRunnerService) RunNetworkOrgProcessing(ctx context.Context, org
 	}
 
 	// Get latest network question runs
-	questionRuns, err := s.GetLatestNetworkQuestionRuns(ctx, orgDetails.NetworkID)
+	questionRuns, questionDeleted, err := s.GetLatestNetworkQuestionRuns(ctx, orgDetails.NetworkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network question runs: %w", err)
 	}
 
-	fmt.Printf("[RunNetworkOrgProcessing] Processing %d question runs for org %s in network %s\n",
-		len(questionRuns), orgDetails.OrgName, orgDetails.NetworkID)
+	fmt.Printf("[RunNetworkOrgProcessing] Processing %d question runs for org %s in network %s (%s=%d)\n",
+		len(questionRuns), orgDetails.OrgName, orgDetails.NetworkID, SkipReasonQuestionDeleted, questionDeleted)
 
 	// Generate name variations ONCE for this org (before processing question runs)
 	fmt.Printf("[RunNetworkOrgProcessing] Generating name variations for org: %s\n", orgDetails.OrgName)
@@ -515,6 +897,7 @@ func (s *questionRunnerService) RunNetworkOrgProcessing(ctx context.Context, org
 	totalEvaluations := 0
 	totalCompetitors := 0
 	totalCitations := 0
+	var stageTotals NetworkOrgStageBreakdown
 
 	// Process each question run (with pre-generated name variations)
 	for _, questionRun := range questionRuns {
@@ -544,6 +927,7 @@ func (s *questionRunnerService) RunNetworkOrgProcessing(ctx context.Context, org
 		totalEvaluations++
 		totalCompetitors += len(result.Competitors)
 		totalCitations += len(result.Citations)
+		stageTotals.Add(result.Stages)
 
 		fmt.Printf("[RunNetworkOrgProcessing] Successfully processed question run %s\n", questionRunID)
 	}
@@ -557,11 +941,16 @@ func (s *questionRunnerService) RunNetworkOrgProcessing(ctx context.Context, org
 		Competitors:  totalCompetitors,
 		Citations:    totalCitations,
 		Status:       "completed",
+		Stages:       stageTotals,
 	}
 	results = append(results, summaryResult)
 
 	fmt.Printf("[RunNetworkOrgProcessing] Completed processing for org %s: %d evaluations, %d competitors, %d citations\n",
 		orgDetails.OrgName, totalEvaluations, totalCompetitors, totalCitations)
+	fmt.Printf("[RunNetworkOrgProcessing] stage_usage org=%s runs=%d %s\n", orgID, totalEvaluations, stageTotals)
+	if skipped := stageTotals.PlanSkipped(); len(skipped) > 0 {
+		fmt.Printf("[RunNetworkOrgProcessing] plan_skipped org=%s stages=%s\n", orgID, strings.Join(skipped, ","))
+	}
 
 	return results, nil
 }
@@ -589,19 +978,39 @@ func (s *questionRunnerService) GetOrgDetailsForNetworkProcessing(ctx context.Co
 	}, nil
 }
 
+// ensureOrgWebsites returns orgWebsites, loading them from the org when the
+// caller passed none. Citations are classified primary by comparing against
+// these domains, so an empty list silently turns every citation secondary.
+func (s *questionRunnerService) ensureOrgWebsites(ctx context.Context, logTag string, orgID uuid.UUID, orgWebsites []string) []string {
+	if len(orgWebsites) > 0 {
+		return orgWebsites
+	}
+	details, err := s.GetOrgDetailsForNetworkProcessing(ctx, orgID.String())
+	if err != nil {
+		fmt.Printf("[%s] Warning: no websites passed and failed to load them for org %s: %v\n", logTag, orgID, err)
+		return orgWebsites
+	}
+	if len(details.Websites) == 0 {
+		fmt.Printf("[%s] Warning: org %s has no websites, all citations will be secondary\n", logTag, orgID)
+	} else {
+		fmt.Printf("[%s] Loaded %d websites for org %s\n", logTag, len(details.Websites), orgID)
+	}
+	return details.Websites
+}
+
 // GetLatestNetworkQuestionRuns fetches the latest question runs for a network
 // Returns ALL latest runs across all models and locations (multiple runs per question)
-func (s *questionRunnerService) GetLatestNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, error) {
+func (s *questionRunnerService) GetLatestNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, int, error) {
 	// Parse networkID to UUID
 	networkUUID, err := uuid.Parse(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid network ID format: %w", err)
+		return nil, 0, fmt.Errorf("invalid network ID format: %w", err)
 	}
 
 	// Get network questions first, then get latest runs for each question
 	questions, err := s.repos.GeoQuestionRepo.GetByNetwork(ctx, networkUUID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get network questions: %w", err)
+		return nil, 0, fmt.Errorf("failed to get network questions: %w", err)
 	}
 
 	// Get ALL latest question runs for each question (one per model×location combination)
@@ -622,36 +1031,22 @@ func (s *questionRunnerService) GetLatestNetworkQuestionRuns(ctx context.Context
 		}
 	}
 
-	// Convert to map format for workflow
-	var result []map[string]interface{}
-	for _, run := range questionRuns {
-		// Get the question text for this run
-		question, err := s.repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID)
-		if err != nil {
-			fmt.Printf("[GetLatestNetworkQuestionRuns] Warning: failed to get question for run %s: %v\n", run.QuestionRunID, err)
-			continue
-		}
-
-		responseText := ""
-		if run.ResponseText != nil {
-			responseText = *run.ResponseText
-		}
-
-		result = append(result, map[string]interface{}{
-			"question_run_id": run.QuestionRunID.String(),
-			"question_text":   question.QuestionText,
-			"response_text":   responseText,
-		})
-	}
+	result, questionDeleted := s.networkRunsToMaps(ctx, "GetLatestNetworkQuestionRuns", questionRuns, questions)
 
-	fmt.Printf("[GetLatestNetworkQuestionRuns] Found %d latest question runs across all models/locations for network %s\n", len(result), networkID)
-	return result, nil
+	fmt.Printf("[GetLatestNetworkQuestionRuns] Found %d latest question runs across all models/locations for network %s (%s=%d)\n", len(result), networkID, SkipReasonQuestionDeleted, questionDeleted)
+	return result, questionDeleted, nil
 }
 
 // ProcessNetworkOrgQuestionRun processes a single question run for network org data extraction
 func (s *questionRunnerService) ProcessNetworkOrgQuestionRun(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error) {
 	fmt.Printf("[ProcessNetworkOrgQuestionRun] Processing question run %s for org %s\n", questionRunID, orgName)
 
+	if isEmptyResponse(s.cfg, responseText) {
+		return nil, rejectEmptyNetworkOrgRun(ctx, s.repos, "ProcessNetworkOrgQuestionRun", questionRunID, orgID, questionText, responseText)
+	}
+
+	orgWebsites = s.ensureOrgWebsites(ctx, "ProcessNetworkOrgQuestionRun", orgID, orgWebsites)
+
 	// Extract network org data using the data extraction service (no pre-generated variations)
 	result, err := s.dataExtractionService.ExtractNetworkOrgData(ctx, questionRunID, orgID, orgName, orgWebsites, questionText, responseText, nil)
 	if err != nil {
@@ -683,6 +1078,8 @@ func (s *questionRunnerService) ProcessNetworkOrgQuestionRun(ctx context.Context
 		}
 	}
 
+	saveNetworkOrgStageUsage(ctx, s.repos, questionRunID, orgID, result)
+
 	fmt.Printf("[ProcessNetworkOrgQuestionRun] Successfully processed question run %s: 1 evaluation, %d competitors, %d citations\n",
 		questionRunID, len(result.Competitors), len(result.Citations))
 
@@ -690,17 +1087,17 @@ func (s *questionRunnerService) ProcessNetworkOrgQuestionRun(ctx context.Context
 }
 
 // GetAllNetworkQuestionRuns fetches ALL question runs for a network (not just latest)
-func (s *questionRunnerService) GetAllNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, error) {
+func (s *questionRunnerService) GetAllNetworkQuestionRuns(ctx context.Context, networkID string) ([]map[string]interface{}, int, error) {
 	// Parse networkID to UUID
 	networkUUID, err := uuid.Parse(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid network ID format: %w", err)
+		return nil, 0, fmt.Errorf("invalid network ID format: %w", err)
 	}
 
 	// Get network questions first, then get all runs for each question
 	questions, err := s.repos.GeoQuestionRepo.GetByNetwork(ctx, networkUUID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get network questions: %w", err)
+		return nil, 0, fmt.Errorf("failed to get network questions: %w", err)
 	}
 
 	// Get all question runs for each question
@@ -714,14 +1111,47 @@ func (s *questionRunnerService) GetAllNetworkQuestionRuns(ctx context.Context, n
 		allQuestionRuns = append(allQuestionRuns, runs...)
 	}
 
-	// Convert to map format for workflow
+	result, questionDeleted := s.networkRunsToMaps(ctx, "GetAllNetworkQuestionRuns", allQuestionRuns, questions)
+
+	fmt.Printf("[GetAllNetworkQuestionRuns] Found %d total question runs for network %s (%s=%d)\n", len(result), networkID, SkipReasonQuestionDeleted, questionDeleted)
+	return result, questionDeleted, nil
+}
+
+// networkRunsToMaps converts network question runs to the workflow's map
+// format. Question text comes from the already loaded network questions; a run
+// whose question is not among them is looked up by ID, and counted as
+// question_deleted when that lookup finds nothing. Tags are the run's snapshot
+// (see QuestionRunTagRepository), not the question's current tags.
+func (s *questionRunnerService) networkRunsToMaps(ctx context.Context, caller string, runs []*models.QuestionRun, questions []*models.GeoQuestion) ([]map[string]interface{}, int) {
+	questionsByID := make(map[uuid.UUID]*models.GeoQuestion, len(questions))
+	for _, question := range questions {
+		questionsByID[question.GeoQuestionID] = question
+	}
+
+	runIDs := make([]uuid.UUID, len(runs))
+	for i, run := range runs {
+		runIDs[i] = run.QuestionRunID
+	}
+	runTags := s.runTags(ctx, caller, runIDs)
+
 	var result []map[string]interface{}
-	for _, run := range allQuestionRuns {
-		// Get the question text for this run
-		question, err := s.repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID)
-		if err != nil {
-			fmt.Printf("[GetAllNetworkQuestionRuns] Warning: failed to get question for run %s: %v\n", run.QuestionRunID, err)
-			continue
+	questionDeleted := 0
+	for _, run := range runs {
+		question, ok := questionsByID[run.GeoQuestionID]
+		if !ok {
+			fmt.Printf("[%s] Warning: question %s of run %s not in the network question list, looking it up\n", caller, run.GeoQuestionID, run.QuestionRunID)
+			var err error
+			question, err = s.repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID)
+			if isQuestionNotFound(question, err) {
+				// Deleted in the product since the question list was loaded
+				questionDeleted++
+				continue
+			}
+			if err != nil {
+				fmt.Printf("[%s] Warning: failed to get question for run %s: %v\n", caller, run.QuestionRunID, err)
+				continue
+			}
+			questionsByID[run.GeoQuestionID] = question
 		}
 
 		responseText := ""
@@ -733,11 +1163,24 @@ func (s *questionRunnerService) GetAllNetworkQuestionRuns(ctx context.Context, n
 			"question_run_id": run.QuestionRunID.String(),
 			"question_text":   question.QuestionText,
 			"response_text":   responseText,
+			"tags":            runTags[run.QuestionRunID],
 		})
 	}
+	return result, questionDeleted
+}
 
-	fmt.Printf("[GetAllNetworkQuestionRuns] Found %d total question runs for network %s\n", len(result), networkID)
-	return result, nil
+// runTags returns the tag snapshots of the given runs for the workflow
+// payloads; a lookup failure is logged and yields no tags.
+func (s *questionRunnerService) runTags(ctx context.Context, caller string, runIDs []uuid.UUID) map[uuid.UUID][]string {
+	if s.repos.QuestionRunTagRepo == nil {
+		return nil
+	}
+	tags, err := s.repos.QuestionRunTagRepo.GetByRunIDs(ctx, runIDs)
+	if err != nil {
+		fmt.Printf("[%s] Warning: failed to load run tags, continuing without them: %v\n", caller, err)
+		return nil
+	}
+	return tags
 }
 
 // GetMissingNetworkOrgQuestionRuns fetches all question runs for a network that don't have network_org_eval records for the given org
@@ -768,6 +1211,14 @@ func (s *questionRunnerService) GetMissingNetworkOrgQuestionRuns(ctx context.Con
 
 	// Convert to map format for workflow
 	fmt.Printf("[GetMissingNetworkOrgQuestionRuns] Converting %d results to map format...\n", len(missingRuns))
+	runIDs := make([]uuid.UUID, 0, len(missingRuns))
+	for _, run := range missingRuns {
+		if run != nil {
+			runIDs = append(runIDs, run.QuestionRunID)
+		}
+	}
+	runTags := s.runTags(ctx, "GetMissingNetworkOrgQuestionRuns", runIDs)
+
 	var result []map[string]interface{}
 	for i, run := range missingRuns {
 		if run == nil {
@@ -784,6 +1235,7 @@ func (s *questionRunnerService) GetMissingNetworkOrgQuestionRuns(ctx context.Con
 			"question_run_id": run.QuestionRunID.String(),
 			"question_text":   run.QuestionText,
 			"response_text":   responseText,
+			"tags":            runTags[run.QuestionRunID],
 		})
 	}
 
@@ -801,7 +1253,14 @@ func (s *questionRunnerService) GenerateOrgNameVariations(ctx context.Context, o
 // ProcessNetworkOrgQuestionRunWithCleanup processes a single question run for network org data extraction
 // and deletes any existing eval/citation/competitor data for that org+question run before saving new results
 // nameVariations can be pre-generated and passed in to avoid redundant API calls; pass nil to generate on-the-fly
+// A failure is recorded as a dead letter for ReplayNetworkOrgDeadLetters; a success clears it.
 func (s *questionRunnerService) ProcessNetworkOrgQuestionRunWithCleanup(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error) {
+	result, err := s.processNetworkOrgQuestionRunWithCleanup(ctx, questionRunID, orgID, orgName, orgWebsites, nameVariations, questionText, responseText)
+	updateNetworkOrgDeadLetter(ctx, s.repos, questionRunID, orgID, err)
+	return result, err
+}
+
+func (s *questionRunnerService) processNetworkOrgQuestionRunWithCleanup(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, orgWebsites []string, nameVariations []string, questionText string, responseText string) (*NetworkOrgExtractionResult, error) {
 	fmt.Printf("[ProcessNetworkOrgQuestionRunWithCleanup] Processing question run %s for org %s with cleanup\n", questionRunID, orgName)
 
 	// Step 1: Delete existing data for this org+question run combination
@@ -824,6 +1283,13 @@ func (s *questionRunnerService) ProcessNetworkOrgQuestionRunWithCleanup(ctx cont
 
 	fmt.Printf("[ProcessNetworkOrgQuestionRunWithCleanup] Cleanup completed for org %s, question run %s\n", orgID, questionRunID)
 
+	// Checked after cleanup: data left from an earlier extraction came from the same empty text
+	if isEmptyResponse(s.cfg, responseText) {
+		return nil, rejectEmptyNetworkOrgRun(ctx, s.repos, "ProcessNetworkOrgQuestionRunWithCleanup", questionRunID, orgID, questionText, responseText)
+	}
+
+	orgWebsites = s.ensureOrgWebsites(ctx, "ProcessNetworkOrgQuestionRunWithCleanup", orgID, orgWebsites)
+
 	// Step 2: Extract network org data using the data extraction service (with pre-generated variations if provided)
 	result, err := s.dataExtractionService.ExtractNetworkOrgData(ctx, questionRunID, orgID, orgName, orgWebsites, questionText, responseText, nameVariations)
 	if err != nil {
@@ -855,12 +1321,136 @@ func (s *questionRunnerService) ProcessNetworkOrgQuestionRunWithCleanup(ctx cont
 		}
 	}
 
+	saveNetworkOrgStageUsage(ctx, s.repos, questionRunID, orgID, result)
+
 	fmt.Printf("[ProcessNetworkOrgQuestionRunWithCleanup] Successfully processed question run %s: 1 evaluation, %d competitors, %d citations, $%.6f cost\n",
 		questionRunID, len(result.Competitors), len(result.Citations), result.TotalCost)
 
 	return result, nil
 }
 
+// ReplayNetworkOrgDeadLetters retries the org's dead-lettered extractions that
+// are below NETWORK_ORG_DEAD_LETTER_MAX_ATTEMPTS. Entries whose run or
+// question is gone are removed; exhausted entries are left for the weekly
+// reconciliation report.
+func (s *questionRunnerService) ReplayNetworkOrgDeadLetters(ctx context.Context, orgID string) (*NetworkOrgDeadLetterReplaySummary, error) {
+	summary := &NetworkOrgDeadLetterReplaySummary{OrgID: orgID}
+	if s.repos.NetworkOrgDeadLetterRepo == nil {
+		return summary, nil
+	}
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid org ID %s: %w", orgID, err)
+	}
+
+	entries, err := s.repos.NetworkOrgDeadLetterRepo.ListByOrg(ctx, orgUUID)
+	if err != nil {
+		return nil, err
+	}
+	pending, exhausted := SplitDeadLetters(entries, s.cfg.NetworkOrgDeadLetterMaxAttempts)
+	summary.Pending, summary.Exhausted = len(pending), len(exhausted)
+	if len(pending) == 0 {
+		fmt.Printf("[ReplayNetworkOrgDeadLetters] Nothing to replay for org %s (%d exhausted)\n", orgID, summary.Exhausted)
+		return summary, nil
+	}
+
+	orgDetails, err := s.GetOrgDetailsForNetworkProcessing(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org details: %w", err)
+	}
+	nameVariations, err := s.GenerateOrgNameVariations(ctx, orgDetails.OrgName, orgDetails.Websites)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate name variations: %w", err)
+	}
+
+	runIDs := make([]uuid.UUID, len(pending))
+	for i, entry := range pending {
+		runIDs[i] = entry.QuestionRunID
+	}
+	runs, err := s.repos.QuestionRunRepo.GetByIDs(ctx, runIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question runs: %w", err)
+	}
+	runsByID := make(map[uuid.UUID]*models.QuestionRun, len(runs))
+	for _, run := range runs {
+		runsByID[run.QuestionRunID] = run
+	}
+
+	questionTexts := make(map[uuid.UUID]string)
+	for _, entry := range pending {
+		run := runsByID[entry.QuestionRunID]
+		questionText := ""
+		if run != nil {
+			text, ok := questionTexts[run.GeoQuestionID]
+			if !ok {
+				question, err := s.repos.GeoQuestionRepo.GetByID(ctx, run.GeoQuestionID)
+				if err != nil {
+					// Left in place for the next replay
+					fmt.Printf("[ReplayNetworkOrgDeadLetters] Warning: failed to get question %s: %v\n", run.GeoQuestionID, err)
+					summary.Failed++
+					continue
+				}
+				if question != nil {
+					text = question.QuestionText
+				}
+				questionTexts[run.GeoQuestionID] = text
+			}
+			questionText = text
+		}
+		if questionText == "" {
+			if err := s.repos.NetworkOrgDeadLetterRepo.Delete(ctx, entry.QuestionRunID, orgUUID); err != nil {
+				fmt.Printf("[ReplayNetworkOrgDeadLetters] Warning: %v\n", err)
+			}
+			summary.Dropped++
+			continue
+		}
+
+		responseText := ""
+		if run.ResponseText != nil {
+			responseText = *run.ResponseText
+		}
+		if _, err := s.ProcessNetworkOrgQuestionRunWithCleanup(ctx, run.QuestionRunID, orgUUID, orgDetails.OrgName, orgDetails.Websites, nameVariations, questionText, responseText); err != nil {
+			fmt.Printf("[ReplayNetworkOrgDeadLetters] Warning: run %s failed again (attempt %d): %v\n", run.QuestionRunID, entry.Attempts+1, err)
+			summary.Failed++
+			continue
+		}
+		summary.Replayed++
+		summary.ReplayedRunIDs = append(summary.ReplayedRunIDs, run.QuestionRunID)
+	}
+
+	fmt.Printf("[ReplayNetworkOrgDeadLetters] org=%s pending=%d replayed=%d failed=%d exhausted=%d dropped=%d\n",
+		orgID, summary.Pending, summary.Replayed, summary.Failed, summary.Exhausted, summary.Dropped)
+	return summary, nil
+}
+
+// ProcessNetworkOrgCompetitorsOnly re-extracts the competitors of an org+question run
+// (mini model) and replaces the stored ones, leaving evaluations and citations untouched.
+// Used to refresh competitors after taxonomy changes without re-running the full extraction.
+func (s *questionRunnerService) ProcessNetworkOrgCompetitorsOnly(ctx context.Context, questionRunID uuid.UUID, orgID uuid.UUID, orgName string, responseText string) (*NetworkOrgCompetitorResult, error) {
+	fmt.Printf("[ProcessNetworkOrgCompetitorsOnly] Reprocessing competitors for question run %s, org %s\n", questionRunID, orgName)
+
+	// Extract first so a failed call leaves the existing competitors in place
+	result, err := s.dataExtractionService.ExtractNetworkOrgCompetitors(ctx, questionRunID, orgID, orgName, responseText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract network org competitors: %w", err)
+	}
+
+	if err := s.repos.NetworkOrgCompetitorRepo.DeleteByQuestionRunAndOrg(ctx, questionRunID, orgID); err != nil {
+		return nil, fmt.Errorf("failed to delete existing competitors: %w", err)
+	}
+
+	for _, competitor := range result.Competitors {
+		if err := s.repos.NetworkOrgCompetitorRepo.Create(ctx, competitor); err != nil {
+			return nil, fmt.Errorf("failed to store competitor: %w", err)
+		}
+	}
+
+	fmt.Printf("[ProcessNetworkOrgCompetitorsOnly] Successfully reprocessed question run %s: %d competitors, $%.6f cost\n",
+		questionRunID, len(result.Competitors), result.TotalCost)
+
+	return result, nil
+}
+
 // GetNetworkDetails fetches complete network data including models, locations, and questions
 func (s *questionRunnerService) GetNetworkDetails(ctx context.Context, networkID string) (*NetworkDetails, error) {
 	fmt.Printf("[GetNetworkDetails] Fetching network details for network: %s\n", networkID)
@@ -944,11 +1534,17 @@ func (s *questionRunnerService) GetNetworkDetails(ctx context.Context, networkID
 		}
 	}
 
+	locations, invalidLocations := ValidateLocations(locations)
+	for _, invalid := range invalidLocations {
+		fmt.Printf("[GetNetworkDetails] ⚠️ %s\n", invalid.Message())
+	}
+
 	networkDetails := &NetworkDetails{
-		Network:   network,
-		Models:    geoModels,
-		Locations: locations,
-		Questions: questions,
+		Network:          network,
+		Models:           geoModels,
+		Locations:        locations,
+		Questions:        questions,
+		InvalidLocations: invalidLocations,
 	}
 
 	fmt.Printf("[GetNetworkDetails] Successfully loaded network with %d models, %d locations, %d questions\n",
@@ -964,8 +1560,9 @@ func (s *questionRunnerService) GetOrCreateNetworkBatch(ctx context.Context, net
 
 	// Try to get all org batches and filter for network (since there's no GetByNetwork method yet)
 	// We'll check recently created batches for this network
-	today := time.Now().UTC()
-	todayStart := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	// "Today" is the network's batch day (BATCH_DAY_TIMEZONES, default UTC)
+	dayLoc := BatchDayLocation(s.cfg, networkID)
+	todayStart := DayStart(time.Now(), dayLoc)
 
 	// Fetch batches directly for this network (do not infer from question runs)
 	batches, err := s.repos.QuestionRunBatchRepo.GetByNetwork(ctx, networkID)
@@ -980,6 +1577,17 @@ func (s *questionRunnerService) GetOrCreateNetworkBatch(ctx context.Context, net
 			if batch.CreatedAt.After(todayStart) {
 				fmt.Printf("[GetOrCreateNetworkBatch] ✅ Found existing batch %s from today (status: %s, completed: %d/%d)\n",
 					batch.BatchID, batch.Status, batch.CompletedQuestions, batch.TotalQuestions)
+				// The plan may have changed since the batch was created (questions,
+				// models or locations added/removed); resume against the current plan.
+				if batch.Status != "completed" && batch.TotalQuestions != totalQuestions {
+					fmt.Printf("[GetOrCreateNetworkBatch] Updating batch %s total questions %d -> %d\n",
+						batch.BatchID, batch.TotalQuestions, totalQuestions)
+					batch.TotalQuestions = totalQuestions
+					batch.UpdatedAt = time.Now()
+					if err := s.repos.QuestionRunBatchRepo.Update(ctx, batch); err != nil {
+						return nil, false, fmt.Errorf("failed to update batch total questions: %w", err)
+					}
+				}
 				return batch, true, nil
 			}
 		}
@@ -1000,11 +1608,12 @@ func (s *questionRunnerService) GetOrCreateNetworkBatch(ctx context.Context, net
 		IsLatest:           true,
 	}
 
-	if err := s.repos.QuestionRunBatchRepo.Create(ctx, batch); err != nil {
+	// Clears is_latest on the network's previous batch in the same transaction
+	if err := CreateLatestBatch(ctx, s.repos, batch); err != nil {
 		return nil, false, fmt.Errorf("failed to create batch: %w", err)
 	}
 
-	fmt.Printf("[GetOrCreateNetworkBatch] Created new batch %s with %d total questions\n", batch.BatchID, totalQuestions)
+	fmt.Printf("[GetOrCreateNetworkBatch] Created new batch %s with %d total questions (batch day %s, tz=%s)\n", batch.BatchID, totalQuestions, todayStart.Format("2006-01-02"), dayLoc)
 	return batch, false, nil
 }
 
@@ -1077,9 +1686,10 @@ func (s *questionRunnerService) UpdateNetworkBatchProgress(ctx context.Context,
 	return nil
 }
 
-// CompleteNetworkBatch marks batch as completed and sets completion timestamp
-func (s *questionRunnerService) CompleteNetworkBatch(ctx context.Context, batchID uuid.UUID, totalProcessed int, totalFailed int) error {
-	fmt.Printf("[CompleteNetworkBatch] Completing batch: %s (processed=%d, failed=%d)\n", batchID, totalProcessed, totalFailed)
+// CompleteNetworkBatch marks batch as completed and sets completion timestamp.
+// Skipped jobs are taken off the batch total (see ReconcileBatchCounts).
+func (s *questionRunnerService) CompleteNetworkBatch(ctx context.Context, batchID uuid.UUID, totalProcessed int, totalFailed int, totalSkipped int) error {
+	fmt.Printf("[CompleteNetworkBatch] Completing batch: %s (processed=%d, failed=%d, skipped=%d)\n", batchID, totalProcessed, totalFailed, totalSkipped)
 
 	// Fetch existing batch
 	batch, err := s.repos.QuestionRunBatchRepo.GetByID(ctx, batchID)
@@ -1089,9 +1699,12 @@ func (s *questionRunnerService) CompleteNetworkBatch(ctx context.Context, batchI
 
 	// Update fields
 	now := time.Now()
+	planned := batch.TotalQuestions
+	if drift := ReconcileBatchCounts(batch, totalProcessed, totalFailed, totalSkipped); drift != 0 {
+		fmt.Printf("[CompleteNetworkBatch] ⚠️ Reconciliation: batch %s planned %d but completed=%d failed=%d skipped=%d (drift=%d)\n",
+			batchID, planned, totalProcessed, totalFailed, totalSkipped, drift)
+	}
 	batch.Status = "completed"
-	batch.CompletedQuestions = totalProcessed
-	batch.FailedQuestions = totalFailed
 	batch.CompletedAt = &now
 	batch.UpdatedAt = now
 
@@ -1100,6 +1713,15 @@ func (s *questionRunnerService) CompleteNetworkBatch(ctx context.Context, batchI
 		return fmt.Errorf("failed to complete batch: %w", err)
 	}
 
+	if s.repos.BatchErrorRepo != nil {
+		stored, err := s.repos.BatchErrorRepo.CountByBatch(ctx, batchID)
+		if err != nil {
+			fmt.Printf("[CompleteNetworkBatch] Warning: %v\n", err)
+		} else {
+			fmt.Printf("[CompleteNetworkBatch] 🧾 Batch %s final error count: %d (%d error records stored)\n", batchID, totalFailed, stored)
+		}
+	}
+
 	fmt.Printf("[CompleteNetworkBatch] ✅ Batch %s marked as completed\n", batchID)
 	return nil
 }
@@ -1131,15 +1753,24 @@ func (s *questionRunnerService) CheckQuestionRunExists(ctx context.Context, ques
 func (s *questionRunnerService) RunNetworkQuestionMatrix(ctx context.Context, networkDetails *NetworkDetails, batchID uuid.UUID) (*NetworkProcessingSummary, error) {
 	fmt.Printf("[RunNetworkQuestionMatrix] 🚀 Starting question matrix for network: %s (ID: %s)\n",
 		networkDetails.Network.Name, networkDetails.Network.NetworkID)
+	networkDetails = s.sampleNetworkQuestions(ctx, networkDetails, batchID)
 	fmt.Printf("[RunNetworkQuestionMatrix] 📋 Processing %d questions across %d models and %d locations\n",
 		len(networkDetails.Questions), len(networkDetails.Models), len(networkDetails.Locations))
 
 	summary := &NetworkProcessingSummary{
 		ProcessingErrors: make([]string, 0),
 	}
+	// Intermediate progress only; the caller reconciles the final counts
+	progress := newBatchProgress(s.cfg, s.repos, batchID)
+	defer progress.Flush(ctx)
+
+	// Models no provider recognizes would silently produce no runs every day;
+	// they are reported up front and their pairs skipped
+	supportedModels := s.validateNetworkModels(ctx, networkDetails, batchID, summary)
+	s.reportInvalidNetworkLocations(ctx, networkDetails, batchID, summary)
 
 	// Create model-location pairs
-	pairs := s.createModelLocationPairs(networkDetails.Models, networkDetails.Locations)
+	pairs := s.createModelLocationPairs(supportedModels, networkDetails.Locations)
 	fmt.Printf("[RunNetworkQuestionMatrix] Created %d model-location pairs\n", len(pairs))
 
 	// Process each model-location pair
@@ -1148,14 +1779,22 @@ func (s *questionRunnerService) RunNetworkQuestionMatrix(ctx context.Context, ne
 		fmt.Printf("[RunNetworkQuestionMatrix] 📦 Processing pair %d/%d: model=%s, location=%s\n",
 			pairIdx+1, len(pairs), pair.Model.Name, pair.Location.CountryCode)
 
-		// Get provider for this model
-		provider, err := s.getProvider(pair.Model.Name)
+		// Get provider for this model, in the network's BrightData datasets
+		provider, err := s.getNetworkProvider(pair.Model.Name, networkDetails.Network.NetworkID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get provider for model %s: %w", pair.Model.Name, err)
 		}
+		provider, err = applyWebSearchPolicy(s.cfg, provider, pair.Model.Name)
+		if err != nil {
+			// Never attempted: counted as skipped so the batch total drops accordingly.
+			summary.TotalSkipped += len(networkDetails.Questions)
+			fmt.Printf("[RunNetworkQuestionMatrix] ⚠️ Skipped model %s, location %s (%d questions): %v\n",
+				pair.Model.Name, pair.Location.CountryCode, len(networkDetails.Questions), err)
+			continue
+		}
 
 		// Execute questions for this pair (batched or sequential)
-		questionRuns, err := s.executeQuestionsForPair(ctx, networkDetails.Questions, pair, provider, batchID, summary)
+		questionRuns, err := s.executeQuestionsForPair(ctx, networkDetails.Questions, pair, provider, batchID, summary, progress)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute questions for model %s, location %s: %w",
 				pair.Model.Name, pair.Location.CountryCode, err)
@@ -1166,8 +1805,30 @@ func (s *questionRunnerService) RunNetworkQuestionMatrix(ctx context.Context, ne
 			pairIdx+1, len(pairs), len(questionRuns))
 	}
 
-	fmt.Printf("[RunNetworkQuestionMatrix] 🎉 Question matrix completed: %d processed, $%.6f total cost\n",
-		summary.TotalProcessed, summary.TotalCost)
+	fmt.Printf("[RunNetworkQuestionMatrix] 🎉 Question matrix completed: %d processed, %d resumed, %d skipped, $%.6f total cost\n",
+		summary.TotalProcessed, summary.TotalResumed, summary.TotalSkipped, summary.TotalCost)
+
+	// Questions deleted while the matrix ran are left out of the latest-flag update
+	// (updating them would fail on every retry of this step)
+	if len(allQuestionRuns) > 0 {
+		live, err := s.liveNetworkQuestionIDs(ctx, networkDetails.Network.NetworkID)
+		if err != nil {
+			fmt.Printf("[RunNetworkQuestionMatrix] Warning: could not check for deleted questions: %v\n", err)
+		} else {
+			kept := allQuestionRuns[:0]
+			for _, run := range allQuestionRuns {
+				if !live[run.GeoQuestionID] {
+					summary.QuestionDeleted++
+					continue
+				}
+				kept = append(kept, run)
+			}
+			allQuestionRuns = kept
+			if summary.QuestionDeleted > 0 {
+				fmt.Printf("[RunNetworkQuestionMatrix] ⚠️ Skipping %d runs of deleted questions (%s)\n", summary.QuestionDeleted, SkipReasonQuestionDeleted)
+			}
+		}
+	}
 
 	// Update is_latest flags for all created question runs
 	if len(allQuestionRuns) > 0 {
@@ -1180,6 +1841,42 @@ func (s *questionRunnerService) RunNetworkQuestionMatrix(ctx context.Context, ne
 	return summary, nil
 }
 
+// validateNetworkModels returns the network's models getProvider recognizes.
+// Each other model is recorded in summary.UnconfiguredModels and stored as one
+// batch error, and its planned runs are counted as skipped (not failed).
+func (s *questionRunnerService) validateNetworkModels(ctx context.Context, networkDetails *NetworkDetails, batchID uuid.UUID, summary *NetworkProcessingSummary) []*models.GeoModel {
+	supported := make([]*models.GeoModel, 0, len(networkDetails.Models))
+	for _, model := range networkDetails.Models {
+		if IsSupportedModel(s.cfg, model.Name) {
+			supported = append(supported, model)
+			continue
+		}
+		summary.UnconfiguredModels = append(summary.UnconfiguredModels, model.Name)
+		summary.TotalSkipped += len(networkDetails.Questions) * len(networkDetails.Locations)
+		msg := fmt.Sprintf("Unconfigured model %s: no provider recognizes it, skipped %d questions x %d locations (supported patterns: %s)",
+			model.Name, len(networkDetails.Questions), len(networkDetails.Locations), strings.Join(ListSupportedModelPatterns(s.cfg), ", "))
+		fmt.Printf("[RunNetworkQuestionMatrix] ❌ %s\n", msg)
+		s.storeNetworkBatchError(ctx, batchID, nil, ModelLocationPair{Model: model}, ErrorCodeUnconfiguredModel, msg)
+	}
+	if len(summary.UnconfiguredModels) > 0 {
+		fmt.Printf("[RunNetworkQuestionMatrix] metric network_unconfigured_models=%d network_id=%s batch_id=%s models=%q\n",
+			len(summary.UnconfiguredModels), networkDetails.Network.NetworkID, batchID, summary.UnconfiguredModels)
+	}
+	return supported
+}
+
+// reportInvalidNetworkLocations records each location GetNetworkDetails left
+// out of the matrix in summary.InvalidLocations and stores it as one batch
+// error, so a bad country code doesn't fail every question of the location.
+func (s *questionRunnerService) reportInvalidNetworkLocations(ctx context.Context, networkDetails *NetworkDetails, batchID uuid.UUID, summary *NetworkProcessingSummary) {
+	for _, invalid := range networkDetails.InvalidLocations {
+		msg := invalid.Message()
+		summary.InvalidLocations = append(summary.InvalidLocations, msg)
+		fmt.Printf("[RunNetworkQuestionMatrix] ❌ %s\n", msg)
+		s.storeNetworkBatchError(ctx, batchID, nil, ModelLocationPair{Location: invalid.Location}, ErrorCodeInvalidLocation, msg)
+	}
+}
+
 // createModelLocationPairs creates all unique combinations of models and locations
 func (s *questionRunnerService) createModelLocationPairs(models []*models.GeoModel, locations []*models.OrgLocation) []ModelLocationPair {
 	pairs := make([]ModelLocationPair, 0, len(models)*len(locations))
@@ -1202,6 +1899,7 @@ func (s *questionRunnerService) executeQuestionsForPair(
 	provider AIProvider,
 	batchID uuid.UUID,
 	summary *NetworkProcessingSummary,
+	progress *batchProgress,
 ) ([]*models.QuestionRun, error) {
 	var questionRuns []*models.QuestionRun
 
@@ -1234,6 +1932,7 @@ func (s *questionRunnerService) executeQuestionsForPair(
 			}
 
 			questionRuns = append(questionRuns, runs...)
+			progress.Observe(ctx, summary.TotalProcessed, len(summary.ProcessingErrors))
 		}
 	} else {
 		// Sequential processing for OpenAI/Anthropic
@@ -1245,10 +1944,13 @@ func (s *questionRunnerService) executeQuestionsForPair(
 				idx+1, len(questions), question.QuestionText)
 
 			// Execute single question
-			run, err := s.executeSingleNetworkQuestion(ctx, question, pair, provider, workflowLocation, batchID, summary)
+			run, err := s.executeSingleNetworkQuestion(ctx, question, QuestionTags(questionWithTags), pair, provider, workflowLocation, batchID, summary)
 			if err != nil {
-				summary.ProcessingErrors = append(summary.ProcessingErrors,
+				s.recordNetworkBatchError(ctx, summary, batchID, &question.GeoQuestionID, pair, ClassifyError(err),
 					fmt.Sprintf("Failed to execute question %s: %v", question.GeoQuestionID, err))
+			}
+			progress.Observe(ctx, summary.TotalProcessed, len(summary.ProcessingErrors))
+			if err != nil {
 				continue
 			}
 
@@ -1295,6 +1997,8 @@ func (s *questionRunnerService) executeBatchForNetwork(
 		}
 	}
 
+	summary.TotalResumed += len(existingRuns)
+
 	// If all questions already exist, return existing runs
 	if len(questionsToExecute) == 0 {
 		fmt.Printf("[executeBatchForNetwork] All %d questions already executed, skipping batch API call\n", len(batch))
@@ -1303,40 +2007,59 @@ func (s *questionRunnerService) executeBatchForNetwork(
 
 	fmt.Printf("[executeBatchForNetwork] Executing %d new questions (skipped %d existing)\n", len(questionsToExecute), len(existingRuns))
 
-	// Extract query strings from questions that need execution
-	queries := make([]string, len(questionsToExecute))
-	for i, q := range questionsToExecute {
-		queries[i] = q.Question.QuestionText
+	// Normalize query strings; questions with unresolvable templating are recorded and dropped
+	// Each query carries its question ID so responses are matched by ID, not position
+	queries := make([]BatchQuery, 0, len(questionsToExecute))
+	validQuestions := make([]interfaces.GeoQuestionWithTags, 0, len(questionsToExecute))
+	for _, q := range questionsToExecute {
+		queryText, err := NormalizeQuestionText(q.Question.QuestionText, workflowLocation)
+		if err != nil {
+			errMsg := fmt.Sprintf("Question %s failed validation: %v", q.Question.GeoQuestionID, err)
+			s.recordNetworkBatchError(ctx, summary, batchID, &q.Question.GeoQuestionID, pair, ErrorCodeInvalidRequest, errMsg)
+			fmt.Printf("[executeBatchForNetwork] ⚠️ %s\n", errMsg)
+			continue
+		}
+		queries = append(queries, BatchQuery{CorrelationID: q.Question.GeoQuestionID.String(), Query: queryText})
+		validQuestions = append(validQuestions, q)
+	}
+	questionsToExecute = validQuestions
+	if len(questionsToExecute) == 0 {
+		return existingRuns, nil
 	}
 
-	fmt.Printf("[executeBatchForNetwork] 🚀 Calling provider.RunQuestionBatch with %d queries\n", len(queries))
+	fmt.Printf("[executeBatchForNetwork] 🚀 Calling provider.RunQuestionBatchAwait with %d queries\n", len(queries))
 
-	// Execute batch API call
-	responses, err := provider.RunQuestionBatch(ctx, queries, true, workflowLocation)
+	// Execute batch API call; async providers submit, poll and retrieve within it
+	responses, err := provider.RunQuestionBatchAwait(ctx, queries, true, workflowLocation)
 	if err != nil {
 		fmt.Printf("[executeBatchForNetwork] ❌ Batch API call failed: %v\n", err)
 		return nil, fmt.Errorf("batch API call failed: %w", err)
 	}
 
-	fmt.Printf("[executeBatchForNetwork] ✅ Batch API call succeeded, got %d responses\n", len(responses))
+	batchLatency := int64(0)
+	if len(responses) > 0 && responses[0] != nil {
+		batchLatency = responses[0].LatencyMs
+	}
+	fmt.Printf("[executeBatchForNetwork] ✅ Batch API call succeeded, got %d responses in %dms\n", len(responses), batchLatency)
 
-	if len(responses) != len(questionsToExecute) {
-		errMsg := fmt.Sprintf("batch returned %d responses but expected %d", len(responses), len(questionsToExecute))
-		fmt.Printf("[executeBatchForNetwork] ❌ %s\n", errMsg)
-		return nil, fmt.Errorf("%s", errMsg)
+	responsesByID, err := MatchBatchResponses(queries, responses)
+	if err != nil {
+		fmt.Printf("[executeBatchForNetwork] ❌ Batch response correlation failed: %v\n", err)
+		return nil, fmt.Errorf("batch response correlation failed: %w", err)
 	}
 
 	// Create and store new question runs (skip failed ones)
 	newQuestionRuns := make([]*models.QuestionRun, 0, len(questionsToExecute))
-	for i, questionWithTags := range questionsToExecute {
+	for _, questionWithTags := range questionsToExecute {
 		question := questionWithTags.Question
-		aiResponse := responses[i]
+		aiResponse := responsesByID[question.GeoQuestionID.String()]
+		rejectTruncated(s.cfg, aiResponse)
 
 		// Skip failed runs - don't save to DB
 		if !aiResponse.ShouldProcessEvaluation {
 			errorMsg := fmt.Sprintf("Question %s (%s) failed for model %s, location %s: %s",
-				question.GeoQuestionID, question.QuestionText, pair.Model.Name, pair.Location.CountryCode, aiResponse.Response)
-			summary.ProcessingErrors = append(summary.ProcessingErrors, errorMsg)
+				question.GeoQuestionID, question.QuestionText, pair.Model.Name, pair.Location.CountryCode, skipDetail(aiResponse))
+			s.recordNetworkBatchError(ctx, summary, batchID, &question.GeoQuestionID, pair, aiResponse.ErrorCode, errorMsg)
 			fmt.Printf("[executeBatchForNetwork] ⚠️ Skipping failed question run: %s\n", errorMsg)
 			continue
 		}
@@ -1365,6 +2088,10 @@ func (s *questionRunnerService) executeBatchForNetwork(
 		if err := s.repos.QuestionRunRepo.Create(ctx, questionRun); err != nil {
 			return nil, fmt.Errorf("failed to store question run: %w", err)
 		}
+		recordTruncation(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+		recordWebSearchDowngrade(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+		summary.Languages = countLanguage(summary.Languages, recordResponseLanguage(ctx, s.repos, questionRun.QuestionRunID, aiResponse))
+		recordQuestionTags(ctx, s.repos, questionRun.QuestionRunID, QuestionTags(questionWithTags))
 
 		newQuestionRuns = append(newQuestionRuns, questionRun)
 		summary.TotalProcessed++
@@ -1381,6 +2108,7 @@ func (s *questionRunnerService) executeBatchForNetwork(
 func (s *questionRunnerService) executeSingleNetworkQuestion(
 	ctx context.Context,
 	question *models.GeoQuestion,
+	tags []string,
 	pair ModelLocationPair,
 	provider AIProvider,
 	workflowLocation *workflowModels.Location,
@@ -1396,20 +2124,27 @@ func (s *questionRunnerService) executeSingleNetworkQuestion(
 
 	if existingRun != nil {
 		fmt.Printf("[executeSingleNetworkQuestion] ✓ Skipping question %s - already executed\n", question.GeoQuestionID)
+		summary.TotalResumed++
 		return existingRun, nil
 	}
 
+	queryText, err := NormalizeQuestionText(question.QuestionText, workflowLocation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question text: %w", err)
+	}
+
 	// Execute AI call
-	aiResponse, err := provider.RunQuestion(ctx, question.QuestionText, true, workflowLocation)
+	aiResponse, err := provider.RunQuestion(ctx, queryText, true, workflowLocation)
 	if err != nil {
-		return nil, fmt.Errorf("AI call failed: %w", err)
+		return nil, fmt.Errorf("AI call failed: %w", ClassifyProviderError(pair.Model.Name, err))
 	}
+	rejectTruncated(s.cfg, aiResponse)
 
 	// Skip failed runs - don't save to DB
 	if !aiResponse.ShouldProcessEvaluation {
 		errorMsg := fmt.Sprintf("Question %s (%s) failed for model %s, location %s: %s",
-			question.GeoQuestionID, question.QuestionText, pair.Model.Name, pair.Location.CountryCode, aiResponse.Response)
-		summary.ProcessingErrors = append(summary.ProcessingErrors, errorMsg)
+			question.GeoQuestionID, question.QuestionText, pair.Model.Name, pair.Location.CountryCode, skipDetail(aiResponse))
+		s.recordNetworkBatchError(ctx, summary, batchID, &question.GeoQuestionID, pair, aiResponse.ErrorCode, errorMsg)
 		fmt.Printf("[executeSingleNetworkQuestion] ⚠️ Skipping failed question run: %s\n", errorMsg)
 		return nil, nil // Return nil without error - this is an expected failure
 	}
@@ -1439,13 +2174,34 @@ func (s *questionRunnerService) executeSingleNetworkQuestion(
 	if err := s.repos.QuestionRunRepo.Create(ctx, questionRun); err != nil {
 		return nil, fmt.Errorf("failed to store question run: %w", err)
 	}
+	recordTruncation(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	recordWebSearchDowngrade(ctx, s.repos, questionRun.QuestionRunID, aiResponse)
+	summary.Languages = countLanguage(summary.Languages, recordResponseLanguage(ctx, s.repos, questionRun.QuestionRunID, aiResponse))
+	recordQuestionTags(ctx, s.repos, questionRun.QuestionRunID, tags)
 
 	summary.TotalProcessed++
 	summary.TotalCost += aiResponse.Cost
 	return questionRun, nil
 }
 
-// updateNetworkLatestFlagsForRuns updates is_latest flags for network question runs
+// skipDetail returns the provider's skip reason (or error detail) when set,
+// otherwise the response text, prefixed with the error code when there is one.
+func skipDetail(aiResponse *AIResponse) string {
+	detail := aiResponse.Response
+	if aiResponse.SkipReason != "" {
+		detail = aiResponse.SkipReason
+	} else if aiResponse.ErrorDetail != "" {
+		detail = aiResponse.ErrorDetail
+	}
+	if aiResponse.ErrorCode != "" {
+		return fmt.Sprintf("[%s] %s", aiResponse.ErrorCode, detail)
+	}
+	return detail
+}
+
+// updateNetworkLatestFlagsForRuns updates is_latest flags for network question runs.
+// With a bulk-capable repository this is two set-based UPDATEs in one transaction;
+// otherwise it falls back to updating each run individually.
 func (s *questionRunnerService) updateNetworkLatestFlagsForRuns(ctx context.Context, questions []interfaces.GeoQuestionWithTags, newRuns []*models.QuestionRun) error {
 	if len(newRuns) == 0 {
 		return nil
@@ -1458,15 +2214,38 @@ func (s *questionRunnerService) updateNetworkLatestFlagsForRuns(ctx context.Cont
 	}
 
 	fmt.Printf("[updateNetworkLatestFlagsForRuns] Updating is_latest flags for batch %s with %d question runs\n", batchID, len(newRuns))
+	start := time.Now()
 
-	// Step 1: Mark all old question runs (from previous batches) as is_latest=false for this network
 	questionIDMap := make(map[uuid.UUID]bool)
+	questionIDs := make([]uuid.UUID, 0, len(newRuns))
 	for _, run := range newRuns {
-		questionIDMap[run.GeoQuestionID] = true
+		if !questionIDMap[run.GeoQuestionID] {
+			questionIDMap[run.GeoQuestionID] = true
+			questionIDs = append(questionIDs, run.GeoQuestionID)
+		}
 	}
 
+	audit := beginLatestFlagAudit(ctx, s.repos, LatestFlagSourceNetworkBatch, batchID, questionIDs)
+
+	if s.repos.QuestionRunLatestRepo != nil {
+		cleared, set, err := s.bulkUpdateNetworkLatestFlags(ctx, questionIDs, *batchID, runIDs(newRuns))
+		if err == nil {
+			now := time.Now()
+			for _, run := range newRuns {
+				run.IsLatest = true
+				run.UpdatedAt = now
+			}
+			audit.record(ctx, latestRunsByQuestion(newRuns))
+			fmt.Printf("[updateNetworkLatestFlagsForRuns] ✅ Bulk updated is_latest flags for batch %s in %v (cleared=%d set=%d)\n", batchID, time.Since(start), cleared, set)
+			return nil
+		}
+		fmt.Printf("[updateNetworkLatestFlagsForRuns] Warning: Bulk update failed after %v, falling back to per-run updates: %v\n", time.Since(start), err)
+	}
+
+	shiftSecondLatest(ctx, s.repos, questionIDs, runIDs(newRuns))
+
 	// Step 1: Mark old question runs as is_latest=false
-	for questionID := range questionIDMap {
+	for _, questionID := range questionIDs {
 		// Get all runs for this question (to find old ones)
 		allRuns, err := s.repos.QuestionRunRepo.GetByQuestion(ctx, questionID)
 		if err != nil {
@@ -1495,6 +2274,36 @@ func (s *questionRunnerService) updateNetworkLatestFlagsForRuns(ctx context.Cont
 		}
 	}
 
-	fmt.Printf("[updateNetworkLatestFlagsForRuns] ✅ Successfully updated is_latest flags for %d question runs in batch %s\n", len(newRuns), batchID)
+	audit.record(ctx, latestRunsByQuestion(newRuns))
+
+	fmt.Printf("[updateNetworkLatestFlagsForRuns] ✅ Successfully updated is_latest flags for %d question runs in batch %s in %v\n", len(newRuns), batchID, time.Since(start))
 	return nil
 }
+
+// bulkUpdateNetworkLatestFlags shifts second-latest, clears is_latest on older
+// runs of the questions and sets it on the batch's runs inside a single transaction.
+func (s *questionRunnerService) bulkUpdateNetworkLatestFlags(ctx context.Context, questionIDs []uuid.UUID, batchID uuid.UUID, batchRunIDs []uuid.UUID) (int64, int64, error) {
+	tx, err := s.repos.BeginTx(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback on error
+
+	if _, err := s.repos.QuestionRunLatestRepo.ShiftSecondLatest(ctx, tx, questionIDs, batchRunIDs); err != nil {
+		return 0, 0, err
+	}
+
+	cleared, err := s.repos.QuestionRunLatestRepo.ClearLatestForQuestionsExceptBatch(ctx, tx, questionIDs, batchID)
+	if err != nil {
+		return 0, 0, err
+	}
+	set, err := s.repos.QuestionRunLatestRepo.SetLatestForBatch(ctx, tx, batchID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return cleared, set, nil
+}
@@ -0,0 +1,187 @@
+// services/question_sampling.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+// Very large networks can't afford the full question matrix every day, so a
+// network listed in NETWORK_QUESTION_SAMPLING runs a rotating sample instead.
+// A fraction f gives a rotation of ceil(1/f) days; each question hashes to
+// one day of the rotation and runs on that day, so every question is covered
+// once per rotation. Selection depends only on the network, the question and
+// the UTC date, so the matrix, its retries and the missing-runs fixers all
+// agree on the day's sample. Questions tagged PRIORITY_QUESTION_TAG run
+// every day. Each sampled batch records what it selected:
+//
+//	migrations/000008_question_run_batch_sampling.up.sql
+
+// QuestionSampler selects a network's questions for one day. A nil
+// *QuestionSampler (sampling not configured) includes every question.
+type QuestionSampler struct {
+	NetworkID uuid.UUID
+	Fraction  float64
+	// Day is the UTC date the sample is for.
+	Day time.Time
+	// PriorityTag is the normalized tag of questions included every day.
+	PriorityTag string
+}
+
+// NewQuestionSampler returns the network's sampler for day, or nil when the
+// network is not sampled (not configured, or a fraction outside (0, 1)).
+func NewQuestionSampler(cfg *config.Config, networkID uuid.UUID, day time.Time) *QuestionSampler {
+	if cfg == nil {
+		return nil
+	}
+	key := strings.ToLower(networkID.String())
+	raw, ok := cfg.NetworkQuestionSampling[key]
+	if !ok {
+		return nil
+	}
+	fraction, err := strconv.ParseFloat(raw, 64)
+	if err != nil || fraction <= 0 || fraction >= 1 {
+		fmt.Printf("[NewQuestionSampler] Warning: ignoring sampling fraction %q for network %s (want 0 < f < 1)\n", raw, networkID)
+		return nil
+	}
+
+	utc := day.UTC()
+	return &QuestionSampler{
+		NetworkID:   networkID,
+		Fraction:    fraction,
+		Day:         time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC),
+		PriorityTag: strings.ToLower(strings.TrimSpace(cfg.PriorityQuestionTag)),
+	}
+}
+
+// RotationDays is the number of days over which every question runs once.
+func (s *QuestionSampler) RotationDays() int {
+	if s == nil {
+		return 1
+	}
+	return int(math.Ceil(1 / s.Fraction))
+}
+
+// RotationDay is the position of Day within the rotation, 0-based.
+func (s *QuestionSampler) RotationDay() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.Day.Unix()/86400) % s.RotationDays()
+}
+
+// Includes reports whether the question runs on the sampler's day.
+func (s *QuestionSampler) Includes(question interfaces.GeoQuestionWithTags) bool {
+	if s == nil {
+		return true
+	}
+	if s.PriorityTag != "" && slices.Contains(QuestionTags(question), s.PriorityTag) {
+		return true
+	}
+	questionID := question.Question.GeoQuestionID
+	h := fnv.New64a()
+	h.Write(s.NetworkID[:])
+	h.Write(questionID[:])
+	return int(h.Sum64()%uint64(s.RotationDays())) == s.RotationDay()
+}
+
+// Filter returns the questions included on the sampler's day, in order.
+func (s *QuestionSampler) Filter(questions []interfaces.GeoQuestionWithTags) []interfaces.GeoQuestionWithTags {
+	if s == nil {
+		return questions
+	}
+	selected := make([]interfaces.GeoQuestionWithTags, 0, len(questions))
+	for _, q := range questions {
+		if s.Includes(q) {
+			selected = append(selected, q)
+		}
+	}
+	return selected
+}
+
+// BatchSampling is the sampling record of one network batch.
+type BatchSampling struct {
+	BatchID           uuid.UUID `db:"batch_id" json:"batch_id"`
+	SamplingFraction  float64   `db:"sampling_fraction" json:"sampling_fraction"`
+	RotationDays      int       `db:"rotation_days" json:"rotation_days"`
+	RotationDay       int       `db:"rotation_day" json:"rotation_day"`
+	SelectedQuestions int       `db:"selected_questions" json:"selected_questions"`
+	TotalQuestions    int       `db:"total_questions" json:"total_questions"`
+}
+
+// BatchSamplingRepository stores the sampling record of network batches.
+type BatchSamplingRepository interface {
+	// Upsert records the batch's sampling; re-running a batch overwrites it.
+	Upsert(ctx context.Context, sampling *BatchSampling) error
+}
+
+type batchSamplingRepo struct {
+	db *database.Client
+}
+
+func NewBatchSamplingRepo(db *database.Client) BatchSamplingRepository {
+	return &batchSamplingRepo{db: db}
+}
+
+func (r *batchSamplingRepo) Upsert(ctx context.Context, s *BatchSampling) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_batch_sampling (batch_id, sampling_fraction, rotation_days, rotation_day, selected_questions, total_questions)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (batch_id) DO UPDATE SET
+		    sampling_fraction = EXCLUDED.sampling_fraction,
+		    rotation_days = EXCLUDED.rotation_days,
+		    rotation_day = EXCLUDED.rotation_day,
+		    selected_questions = EXCLUDED.selected_questions,
+		    total_questions = EXCLUDED.total_questions`,
+		s.BatchID, s.SamplingFraction, s.RotationDays, s.RotationDay, s.SelectedQuestions, s.TotalQuestions)
+	if err != nil {
+		return fmt.Errorf("failed to record batch sampling for batch %s: %w", s.BatchID, err)
+	}
+	return nil
+}
+
+// sampleNetworkQuestions returns networkDetails with its questions narrowed
+// to the sample for the batch's day, and records the selection on the batch.
+// The day is the batch's creation date so a retry after midnight keeps the
+// same sample. Unsampled networks are returned unchanged.
+func (s *questionRunnerService) sampleNetworkQuestions(ctx context.Context, networkDetails *NetworkDetails, batchID uuid.UUID) *NetworkDetails {
+	day := time.Now()
+	if batch, err := s.repos.QuestionRunBatchRepo.GetByID(ctx, batchID); err == nil && batch != nil && !batch.CreatedAt.IsZero() {
+		day = batch.CreatedAt
+	}
+	sampler := NewQuestionSampler(s.cfg, networkDetails.Network.NetworkID, day)
+	if sampler == nil {
+		return networkDetails
+	}
+
+	sampled := *networkDetails
+	sampled.Questions = sampler.Filter(networkDetails.Questions)
+	fmt.Printf("[RunNetworkQuestionMatrix] 🎲 Sampling %.0f%% of questions: %d/%d selected (rotation day %d/%d)\n",
+		sampler.Fraction*100, len(sampled.Questions), len(networkDetails.Questions), sampler.RotationDay()+1, sampler.RotationDays())
+
+	if s.repos.BatchSamplingRepo != nil {
+		err := s.repos.BatchSamplingRepo.Upsert(ctx, &BatchSampling{
+			BatchID:           batchID,
+			SamplingFraction:  sampler.Fraction,
+			RotationDays:      sampler.RotationDays(),
+			RotationDay:       sampler.RotationDay(),
+			SelectedQuestions: len(sampled.Questions),
+			TotalQuestions:    len(networkDetails.Questions),
+		})
+		if err != nil {
+			fmt.Printf("[RunNetworkQuestionMatrix] Warning: %v\n", err)
+		}
+	}
+	return &sampled
+}
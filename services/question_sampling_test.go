@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/models"
+	"github.com/AI-Template-SDK/senso-api/pkg/repositories/interfaces"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/google/uuid"
+)
+
+func sampledQuestion(tags ...string) interfaces.GeoQuestionWithTags {
+	q := interfaces.GeoQuestionWithTags{Question: &models.GeoQuestion{GeoQuestionID: uuid.New()}}
+	for _, name := range tags {
+		q.Tags = append(q.Tags, &interfaces.Tag{Name: name})
+	}
+	return q
+}
+
+func TestNewQuestionSampler(t *testing.T) {
+	networkID := uuid.New()
+	day := time.Date(2026, 3, 4, 15, 30, 0, 0, time.FixedZone("EST", -5*3600))
+
+	tests := []struct {
+		name     string
+		sampling map[string]string
+		wantNil  bool
+		wantDays int
+	}{
+		{name: "not configured", sampling: map[string]string{}, wantNil: true},
+		{name: "other network", sampling: map[string]string{uuid.New().String(): "0.2"}, wantNil: true},
+		{name: "not a number", sampling: map[string]string{networkID.String(): "a fifth"}, wantNil: true},
+		{name: "zero", sampling: map[string]string{networkID.String(): "0"}, wantNil: true},
+		{name: "one", sampling: map[string]string{networkID.String(): "1"}, wantNil: true},
+		{name: "a fifth", sampling: map[string]string{networkID.String(): "0.2"}, wantDays: 5},
+		{name: "a seventh rounds up", sampling: map[string]string{networkID.String(): "0.15"}, wantDays: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{NetworkQuestionSampling: tt.sampling, PriorityQuestionTag: "priority"}
+			sampler := NewQuestionSampler(cfg, networkID, day)
+			if tt.wantNil {
+				if sampler != nil {
+					t.Fatalf("NewQuestionSampler() = %+v, want nil", sampler)
+				}
+				return
+			}
+			if sampler == nil {
+				t.Fatal("NewQuestionSampler() = nil")
+			}
+			if got := sampler.RotationDays(); got != tt.wantDays {
+				t.Errorf("RotationDays() = %d, want %d", got, tt.wantDays)
+			}
+			if want := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC); !sampler.Day.Equal(want) {
+				t.Errorf("Day = %v, want %v", sampler.Day, want)
+			}
+		})
+	}
+}
+
+// TestQuestionSamplerRotation simulates consecutive days and checks every
+// question runs exactly once per rotation and priority questions run daily.
+func TestQuestionSamplerRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction string
+		days     int
+	}{
+		{name: "daily fifth over a week", fraction: "0.2", days: 7},
+		{name: "daily seventh over a week", fraction: "0.15", days: 7},
+		{name: "half over two rotations", fraction: "0.5", days: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networkID := uuid.New()
+			cfg := &config.Config{
+				NetworkQuestionSampling: map[string]string{networkID.String(): tt.fraction},
+				PriorityQuestionTag:     "priority",
+			}
+
+			var questions []interfaces.GeoQuestionWithTags
+			for i := 0; i < 200; i++ {
+				questions = append(questions, sampledQuestion("branding"))
+			}
+			priority := sampledQuestion(" Priority ", "branding")
+			questions = append(questions, priority)
+
+			start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+			counts := make(map[uuid.UUID]int)
+			var rotationDays int
+			for d := 0; d < tt.days; d++ {
+				day := start.AddDate(0, 0, d).Add(13 * time.Hour)
+				sampler := NewQuestionSampler(cfg, networkID, day)
+				rotationDays = sampler.RotationDays()
+
+				selected := sampler.Filter(questions)
+				again := NewQuestionSampler(cfg, networkID, day.Add(5*time.Hour)).Filter(questions)
+				if len(again) != len(selected) {
+					t.Fatalf("day %d: selection changed within the day: %d then %d questions", d, len(selected), len(again))
+				}
+				for i := range selected {
+					if selected[i].Question.GeoQuestionID != again[i].Question.GeoQuestionID {
+						t.Fatalf("day %d: selection changed within the day", d)
+					}
+					counts[selected[i].Question.GeoQuestionID]++
+				}
+				if len(selected) == len(questions) {
+					t.Errorf("day %d: every question selected", d)
+				}
+			}
+
+			if got := counts[priority.Question.GeoQuestionID]; got != tt.days {
+				t.Errorf("priority question ran %d times in %d days, want every day", got, tt.days)
+			}
+			for _, q := range questions[:len(questions)-1] {
+				got := counts[q.Question.GeoQuestionID]
+				fewest, most := tt.days/rotationDays, (tt.days+rotationDays-1)/rotationDays
+				if got < fewest || got > most {
+					t.Fatalf("question %s ran %d times in %d days with a %d-day rotation", q.Question.GeoQuestionID, got, tt.days, rotationDays)
+				}
+			}
+		})
+	}
+}
+
+func TestQuestionSamplerNilIncludesEverything(t *testing.T) {
+	var sampler *QuestionSampler
+	questions := []interfaces.GeoQuestionWithTags{sampledQuestion(), sampledQuestion("priority")}
+	if got := sampler.Filter(questions); len(got) != len(questions) {
+		t.Errorf("nil sampler Filter() kept %d of %d questions", len(got), len(questions))
+	}
+	if got := sampler.RotationDays(); got != 1 {
+		t.Errorf("nil sampler RotationDays() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,94 @@
+// services/question_text.go
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+// Onboarded questions sometimes carry templating artifacts (smart quotes, double
+// spaces, unsubstituted {{placeholders}}). NormalizeQuestionText cleans the text
+// that is actually sent to providers and logged; the stored GeoQuestion is never
+// modified.
+
+var (
+	questionWhitespaceRe  = regexp.MustCompile(`\s+`)
+	questionPlaceholderRe = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+	questionASCIIReplacer = strings.NewReplacer(
+		"‘", "'", "’", "'", "‚", "'", "‛", "'",
+		"“", `"`, "”", `"`, "„", `"`, "‟", `"`,
+		"′", "'", "″", `"`,
+		"–", "-", "—", "-", "―", "-", "−", "-",
+		"…", "...",
+		"\u00a0", " ", "\u200b", "",
+	)
+)
+
+// NormalizeQuestionText collapses whitespace, replaces smart quotes/dashes with
+// ASCII equivalents and substitutes {{location}}, {{country}} and {{region}}
+// from the location context. Any other (or unresolvable) placeholder is
+// returned as a validation error instead of being sent to the model verbatim.
+func NormalizeQuestionText(text string, location *workflowModels.Location) (string, error) {
+	normalized := questionASCIIReplacer.Replace(text)
+
+	var unresolved []string
+	normalized = questionPlaceholderRe.ReplaceAllStringFunc(normalized, func(match string) string {
+		name := strings.ToLower(strings.TrimSpace(questionPlaceholderRe.FindStringSubmatch(match)[1]))
+		if value := placeholderValue(name, location); value != "" {
+			return value
+		}
+		unresolved = append(unresolved, match)
+		return match
+	})
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("question text has unsubstituted placeholders: %s", strings.Join(unresolved, ", "))
+	}
+
+	normalized = normalizeQuestionText(normalized)
+	if normalized == "" {
+		return "", fmt.Errorf("question text is empty after normalization")
+	}
+	return normalized, nil
+}
+
+// normalizeQuestionText is the form question text is compared, hashed and
+// deduplicated in: trimmed, internal whitespace collapsed to single spaces and
+// smart quotes/dashes replaced with ASCII. Questions that differ only
+// cosmetically normalize equal. It is a key, not display text: prompts and
+// logs keep the original (or NormalizeQuestionText's output).
+func normalizeQuestionText(s string) string {
+	s = questionASCIIReplacer.Replace(s)
+	return strings.TrimSpace(questionWhitespaceRe.ReplaceAllString(s, " "))
+}
+
+// placeholderValue resolves a supported placeholder name, or returns "" when it can't.
+func placeholderValue(name string, location *workflowModels.Location) string {
+	if location == nil {
+		return ""
+	}
+	region := ""
+	if location.Region != nil {
+		region = strings.TrimSpace(*location.Region)
+	}
+	country := strings.TrimSpace(location.Country)
+
+	switch name {
+	case "country":
+		return country
+	case "region", "state":
+		return region
+	case "location":
+		if region != "" && country != "" {
+			return region + ", " + country
+		}
+		if region != "" {
+			return region
+		}
+		return country
+	}
+	return ""
+}
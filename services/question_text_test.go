@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+)
+
+func TestNormalizeQuestionText(t *testing.T) {
+	california := "California"
+	blank := "  "
+	us := &workflowModels.Location{Country: "US"}
+	usCalifornia := &workflowModels.Location{Country: "US", Region: &california}
+
+	tests := []struct {
+		name     string
+		text     string
+		location *workflowModels.Location
+		want     string
+		wantErr  string
+	}{
+		{name: "plain text unchanged", text: "Which bank is best?", location: us, want: "Which bank is best?"},
+		{name: "smart quotes", text: "What’s the ‘best’ “bank”?", location: us, want: `What's the 'best' "bank"?`},
+		{name: "dashes and ellipsis", text: "Banks – or credit unions — best…", location: us, want: "Banks - or credit unions - best..."},
+		{name: "whitespace collapsed", text: "  Which\tbank\n\nis   best?  ", location: us, want: "Which bank is best?"},
+		{name: "country", text: "Best bank in {{country}}?", location: us, want: "Best bank in US?"},
+		{name: "region", text: "Best bank in {{region}}?", location: usCalifornia, want: "Best bank in California?"},
+		{name: "state", text: "Best bank in {{ state }}?", location: usCalifornia, want: "Best bank in California?"},
+		{name: "location with region", text: "Best bank in {{location}}?", location: usCalifornia, want: "Best bank in California, US?"},
+		{name: "location without region", text: "Best bank in {{Location}}?", location: us, want: "Best bank in US?"},
+		{name: "region unresolved without one", text: "Best bank in {{region}}?", location: &workflowModels.Location{Country: "US", Region: &blank}, wantErr: "{{region}}"},
+		{name: "unknown placeholder", text: "Best bank in {{city}} for {{brand}}?", location: usCalifornia, wantErr: "{{city}}, {{brand}}"},
+		{name: "no location", text: "Best bank in {{country}}?", location: nil, wantErr: "unsubstituted placeholders"},
+		{name: "empty after normalization", text: "\u00a0\u200b ", location: us, wantErr: "empty after normalization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeQuestionText(tt.text, tt.location)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeQuestionText() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeQuestionText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeQuestionText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// services/reconciliation.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Reconciliation checks invariants the pipelines are supposed to keep but
+// sometimes don't: a finished batch's completed_questions matches the runs
+// attached to it, at most one run is is_latest per question/model/location,
+// every network org eval points at an existing run, and no network org
+// extraction keeps failing past its replay attempts. Each check is a pure
+// function over rows the repository loads, so it can be exercised with
+// hand-built data. Reports are kept one row per run:
+//
+//	migrations/000009_reconciliation_reports.up.sql
+
+// ReconciliationViolation names a broken invariant.
+type ReconciliationViolation string
+
+const (
+	// ViolationBatchCount: a finished batch's completed_questions differs
+	// from the number of runs attached to it.
+	ViolationBatchCount ReconciliationViolation = "batch_count_mismatch"
+	// ViolationDuplicateLatest: several runs of one question/model/location
+	// are is_latest.
+	ViolationDuplicateLatest ReconciliationViolation = "duplicate_latest"
+	// ViolationDanglingEval: a network org eval references a run that no
+	// longer exists.
+	ViolationDanglingEval ReconciliationViolation = "dangling_network_org_eval"
+	// ViolationExhaustedDeadLetter: a network org extraction failed on every
+	// replay attempt and is no longer retried.
+	ViolationExhaustedDeadLetter ReconciliationViolation = "network_org_dead_letter_exhausted"
+)
+
+// ReconciliationIssue is one violation. IDs are the affected rows: the batch,
+// the is_latest runs (newest first), the eval and its missing run, or the
+// dead-lettered run.
+type ReconciliationIssue struct {
+	Violation ReconciliationViolation `json:"violation"`
+	Scope     string                  `json:"scope,omitempty"`
+	ScopeID   *uuid.UUID              `json:"scope_id,omitempty"`
+	IDs       []uuid.UUID             `json:"ids"`
+	Detail    string                  `json:"detail"`
+	// Fixed is set when the auto-fix repaired the issue.
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+// ReconciliationReport is the outcome of one reconciliation pass.
+type ReconciliationReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// BatchesSince bounds the batch check to batches created after it.
+	BatchesSince time.Time                       `json:"batches_since"`
+	AutoFix      bool                            `json:"auto_fix"`
+	Counts       map[ReconciliationViolation]int `json:"counts"`
+	Fixed        map[ReconciliationViolation]int `json:"fixed"`
+	Issues       []ReconciliationIssue           `json:"issues"`
+}
+
+// String renders the counts as one line for logs and Slack.
+func (r *ReconciliationReport) String() string {
+	parts := make([]string, 0, len(r.Counts))
+	for _, v := range []ReconciliationViolation{ViolationBatchCount, ViolationDuplicateLatest, ViolationDanglingEval, ViolationExhaustedDeadLetter} {
+		parts = append(parts, fmt.Sprintf("%s=%d (fixed %d)", v, r.Counts[v], r.Fixed[v]))
+	}
+	return fmt.Sprintf("reconciliation %s: %s", r.GeneratedAt.UTC().Format(time.RFC3339), strings.Join(parts, ", "))
+}
+
+// BatchRunCount is a finished batch with the number of runs attached to it.
+type BatchRunCount struct {
+	BatchID            uuid.UUID  `db:"batch_id"`
+	Scope              string     `db:"scope"`
+	ScopeID            *uuid.UUID `db:"scope_id"`
+	Status             string     `db:"status"`
+	CompletedQuestions int        `db:"completed_questions"`
+	AttachedRuns       int        `db:"attached_runs"`
+}
+
+// LatestRunRef is one is_latest run. ModelKey and LocationKey are the model
+// and location IDs of org runs, or run_model and run_country/run_region of
+// network runs.
+type LatestRunRef struct {
+	QuestionRunID uuid.UUID  `db:"question_run_id"`
+	GeoQuestionID uuid.UUID  `db:"geo_question_id"`
+	ModelKey      string     `db:"model_key"`
+	LocationKey   string     `db:"location_key"`
+	NetworkID     *uuid.UUID `db:"network_id"`
+	OrgID         *uuid.UUID `db:"org_id"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+// NetworkOrgEvalRef is a network org eval and whether its run still exists.
+type NetworkOrgEvalRef struct {
+	NetworkOrgEvalID uuid.UUID `db:"network_org_eval_id"`
+	OrgID            uuid.UUID `db:"org_id"`
+	QuestionRunID    uuid.UUID `db:"question_run_id"`
+	RunExists        bool      `db:"run_exists"`
+}
+
+// CheckBatchCounts reports finished batches whose completed_questions is not
+// the number of attached runs. Pending and running batches are still filling.
+func CheckBatchCounts(batches []BatchRunCount) []ReconciliationIssue {
+	var issues []ReconciliationIssue
+	for _, b := range batches {
+		if b.Status == "pending" || b.Status == "running" || b.CompletedQuestions == b.AttachedRuns {
+			continue
+		}
+		issues = append(issues, ReconciliationIssue{
+			Violation: ViolationBatchCount,
+			Scope:     b.Scope,
+			ScopeID:   b.ScopeID,
+			IDs:       []uuid.UUID{b.BatchID},
+			Detail:    fmt.Sprintf("completed_questions=%d attached_runs=%d", b.CompletedQuestions, b.AttachedRuns),
+		})
+	}
+	return issues
+}
+
+// CheckDuplicateLatest reports every question/model/location with more than
+// one is_latest run, listing the runs newest first (the first is the one to
+// keep). Issues are ordered by question for stable reports.
+func CheckDuplicateLatest(runs []LatestRunRef) []ReconciliationIssue {
+	type key struct {
+		question uuid.UUID
+		model    string
+		location string
+	}
+	groups := make(map[key][]LatestRunRef)
+	var order []key
+	for _, run := range runs {
+		k := key{run.GeoQuestionID, run.ModelKey, run.LocationKey}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], run)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].question.String() < order[j].question.String()
+	})
+
+	var issues []ReconciliationIssue
+	for _, k := range order {
+		group := groups[k]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool { return group[i].CreatedAt.After(group[j].CreatedAt) })
+		ids := make([]uuid.UUID, len(group))
+		for i, run := range group {
+			ids[i] = run.QuestionRunID
+		}
+		issue := ReconciliationIssue{
+			Violation: ViolationDuplicateLatest,
+			IDs:       ids,
+			Detail:    fmt.Sprintf("question %s model %s location %s has %d latest runs", k.question, k.model, k.location, len(group)),
+		}
+		switch {
+		case group[0].NetworkID != nil:
+			issue.Scope, issue.ScopeID = "network", group[0].NetworkID
+		case group[0].OrgID != nil:
+			issue.Scope, issue.ScopeID = "org", group[0].OrgID
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// CheckDanglingEvaluations reports network org evals whose run is gone.
+func CheckDanglingEvaluations(evals []NetworkOrgEvalRef) []ReconciliationIssue {
+	var issues []ReconciliationIssue
+	for _, e := range evals {
+		if e.RunExists {
+			continue
+		}
+		orgID := e.OrgID
+		issues = append(issues, ReconciliationIssue{
+			Violation: ViolationDanglingEval,
+			Scope:     "org",
+			ScopeID:   &orgID,
+			IDs:       []uuid.UUID{e.NetworkOrgEvalID, e.QuestionRunID},
+			Detail:    fmt.Sprintf("network org eval %s references missing run %s", e.NetworkOrgEvalID, e.QuestionRunID),
+		})
+	}
+	return issues
+}
+
+// CheckExhaustedDeadLetters reports the dead-lettered network org extractions
+// that reached maxAttempts.
+func CheckExhaustedDeadLetters(entries []NetworkOrgDeadLetter, maxAttempts int) []ReconciliationIssue {
+	_, exhausted := SplitDeadLetters(entries, maxAttempts)
+	var issues []ReconciliationIssue
+	for _, d := range exhausted {
+		orgID := d.OrgID
+		issues = append(issues, ReconciliationIssue{
+			Violation: ViolationExhaustedDeadLetter,
+			Scope:     "org",
+			ScopeID:   &orgID,
+			IDs:       []uuid.UUID{d.QuestionRunID},
+			Detail:    fmt.Sprintf("network org extraction of run %s failed %d times, last: %s", d.QuestionRunID, d.Attempts, d.Error),
+		})
+	}
+	return issues
+}
+
+// ReconciliationRepository loads the rows the checks need, applies the safe
+// fixes and stores reports.
+type ReconciliationRepository interface {
+	// BatchRunCounts returns the batches created since the given time.
+	BatchRunCounts(ctx context.Context, since time.Time) ([]BatchRunCount, error)
+	LatestRuns(ctx context.Context) ([]LatestRunRef, error)
+	// DanglingNetworkOrgEvals returns only the evals whose run is missing.
+	DanglingNetworkOrgEvals(ctx context.Context) ([]NetworkOrgEvalRef, error)
+	NetworkOrgDeadLetters(ctx context.Context) ([]NetworkOrgDeadLetter, error)
+	SetBatchCompleted(ctx context.Context, batchID uuid.UUID, completed int) error
+	ClearLatest(ctx context.Context, questionRunIDs []uuid.UUID) error
+	Save(ctx context.Context, report *ReconciliationReport) error
+}
+
+type reconciliationRepo struct {
+	db *database.Client
+}
+
+func NewReconciliationRepo(db *database.Client) ReconciliationRepository {
+	return &reconciliationRepo{db: db}
+}
+
+func (r *reconciliationRepo) BatchRunCounts(ctx context.Context, since time.Time) ([]BatchRunCount, error) {
+	var rows []BatchRunCount
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT b.batch_id, b.scope, COALESCE(b.network_id, b.org_id) AS scope_id, b.status,
+		       b.completed_questions, COUNT(qr.question_run_id) AS attached_runs
+		FROM question_run_batches b
+		LEFT JOIN question_runs qr ON qr.batch_id = b.batch_id
+		WHERE b.created_at >= $1
+		GROUP BY b.batch_id, b.scope, b.network_id, b.org_id, b.status, b.completed_questions`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch runs: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *reconciliationRepo) LatestRuns(ctx context.Context) ([]LatestRunRef, error) {
+	var rows []LatestRunRef
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT qr.question_run_id, qr.geo_question_id,
+		       COALESCE(qr.model_id::text, qr.run_model, '') AS model_key,
+		       COALESCE(qr.location_id::text, COALESCE(qr.run_country, '') || '/' || COALESCE(qr.run_region, '')) AS location_key,
+		       gq.network_id, gq.org_id, qr.created_at
+		FROM question_runs qr
+		JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+		WHERE qr.is_latest = true`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list latest runs: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *reconciliationRepo) DanglingNetworkOrgEvals(ctx context.Context) ([]NetworkOrgEvalRef, error) {
+	var rows []NetworkOrgEvalRef
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT e.network_org_eval_id, e.org_id, e.question_run_id, false AS run_exists
+		FROM network_org_evals e
+		LEFT JOIN question_runs qr ON qr.question_run_id = e.question_run_id
+		WHERE qr.question_run_id IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dangling network org evals: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *reconciliationRepo) NetworkOrgDeadLetters(ctx context.Context) ([]NetworkOrgDeadLetter, error) {
+	return NewNetworkOrgDeadLetterRepo(r.db).List(ctx)
+}
+
+func (r *reconciliationRepo) SetBatchCompleted(ctx context.Context, batchID uuid.UUID, completed int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE question_run_batches
+		SET completed_questions = $2, updated_at = NOW()
+		WHERE batch_id = $1`, batchID, completed)
+	if err != nil {
+		return fmt.Errorf("failed to recount batch %s: %w", batchID, err)
+	}
+	return nil
+}
+
+func (r *reconciliationRepo) ClearLatest(ctx context.Context, questionRunIDs []uuid.UUID) error {
+	ids := make([]string, len(questionRunIDs))
+	for i, id := range questionRunIDs {
+		ids[i] = id.String()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE question_runs
+		SET is_latest = false, updated_at = NOW()
+		WHERE question_run_id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to clear is_latest on %d question runs: %w", len(questionRunIDs), err)
+	}
+	return nil
+}
+
+func (r *reconciliationRepo) Save(ctx context.Context, report *ReconciliationReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode reconciliation report: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO reconciliation_reports (generated_at, report)
+		VALUES ($1, $2)
+		ON CONFLICT (generated_at) DO UPDATE SET report = EXCLUDED.report`, report.GeneratedAt, body)
+	if err != nil {
+		return fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+	return nil
+}
+
+// ReconcileOptions controls Reconcile.
+type ReconcileOptions struct {
+	// BatchesSince bounds the batch check; older batches are not rechecked.
+	BatchesSince time.Time
+	// AutoFix applies the safe fixes: batch counts are reset to the attached
+	// runs and duplicate is_latest runs other than the newest are cleared.
+	// Dangling evals and exhausted dead letters are only reported.
+	AutoFix bool
+	// DeadLetterMaxAttempts is the replay limit of network org dead letters;
+	// 0 skips the dead letter check.
+	DeadLetterMaxAttempts int
+}
+
+// Reconcile runs every check and, with opts.AutoFix, the safe fixes. A failed
+// fix is left unfixed in the report; only loading the rows is fatal.
+func Reconcile(ctx context.Context, repo ReconciliationRepository, opts ReconcileOptions) (*ReconciliationReport, error) {
+	report := &ReconciliationReport{
+		GeneratedAt:  time.Now().UTC(),
+		BatchesSince: opts.BatchesSince,
+		AutoFix:      opts.AutoFix,
+		Counts:       make(map[ReconciliationViolation]int),
+		Fixed:        make(map[ReconciliationViolation]int),
+	}
+
+	batches, err := repo.BatchRunCounts(ctx, opts.BatchesSince)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := repo.LatestRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	evals, err := repo.DanglingNetworkOrgEvals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var deadLetters []NetworkOrgDeadLetter
+	if opts.DeadLetterMaxAttempts > 0 {
+		if deadLetters, err = repo.NetworkOrgDeadLetters(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	attached := make(map[uuid.UUID]int, len(batches))
+	for _, b := range batches {
+		attached[b.BatchID] = b.AttachedRuns
+	}
+
+	issues := CheckBatchCounts(batches)
+	issues = append(issues, CheckDuplicateLatest(latest)...)
+	issues = append(issues, CheckDanglingEvaluations(evals)...)
+	issues = append(issues, CheckExhaustedDeadLetters(deadLetters, opts.DeadLetterMaxAttempts)...)
+	for i := range issues {
+		issue := &issues[i]
+		report.Counts[issue.Violation]++
+		if !opts.AutoFix {
+			continue
+		}
+		var fixErr error
+		switch issue.Violation {
+		case ViolationBatchCount:
+			fixErr = repo.SetBatchCompleted(ctx, issue.IDs[0], attached[issue.IDs[0]])
+		case ViolationDuplicateLatest:
+			fixErr = repo.ClearLatest(ctx, issue.IDs[1:])
+		default:
+			continue
+		}
+		if fixErr != nil {
+			fmt.Printf("[Reconcile] Warning: %v\n", fixErr)
+			continue
+		}
+		issue.Fixed = true
+		report.Fixed[issue.Violation]++
+	}
+	report.Issues = issues
+	return report, nil
+}
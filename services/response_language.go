@@ -0,0 +1,144 @@
+// services/response_language.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Web-search models sometimes answer in the locale's language even when asked
+// in English, so every stored response gets a detected language. Codes are ISO
+// 639-1 from a bounded set (DetectableLanguages) plus "und" when undetermined,
+// so they are safe to use as report columns or metric labels.
+//
+// question_runs is owned by the senso-api migrations, so the code lives in its
+// own table:
+//
+//	migrations/000005_question_run_languages.up.sql
+
+// LanguageUndetermined is returned when no language scores clearly.
+const LanguageUndetermined = "und"
+
+// LanguageDetector returns the language code of a response text.
+type LanguageDetector interface {
+	Detect(text string) string
+}
+
+// ResponseLanguageDetector is the detector used for stored responses; swap it
+// for a library-backed implementation without touching the call sites.
+var ResponseLanguageDetector LanguageDetector = stopwordLanguageDetector{}
+
+// languageStopwords are frequent function words per language. Words shared by
+// several languages still count for each; the overall score decides.
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "for", "with", "that", "this", "you", "your", "it", "be", "on", "as", "or", "can", "have"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "en", "un", "una", "es", "por", "para", "con", "del", "se", "su", "como", "más", "son"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "en", "du", "que", "pour", "dans", "avec", "sur", "vous", "pas", "au", "sont"},
+	"de": {"der", "die", "das", "und", "ist", "ein", "eine", "zu", "den", "mit", "für", "nicht", "auf", "von", "sie", "es", "im", "dem", "sind", "auch"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "é", "por", "mais", "são", "seu"},
+	"it": {"il", "lo", "la", "gli", "le", "di", "che", "e", "un", "una", "per", "con", "non", "è", "sono", "del", "della", "in", "da", "anche"},
+}
+
+// DetectableLanguages is the bounded set of codes Detect can return besides "und".
+var DetectableLanguages = []string{"en", "es", "fr", "de", "pt", "it"}
+
+var stopwordIndex = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range languageStopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}()
+
+// stopwordLanguageDetector scores each language by how many of the text's
+// words are among its stopwords. It needs no dependency and is accurate
+// enough for whole answers; very short texts come back "und".
+type stopwordLanguageDetector struct{}
+
+func (stopwordLanguageDetector) Detect(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	scores := make(map[string]int, len(languageStopwords))
+	for _, w := range words {
+		for _, lang := range stopwordIndex[w] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, secondScore := LanguageUndetermined, 0, 0
+	for _, lang := range DetectableLanguages {
+		switch score := scores[lang]; {
+		case score > bestScore:
+			best, bestScore, secondScore = lang, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+	if bestScore < 2 || bestScore == secondScore {
+		return LanguageUndetermined
+	}
+	return best
+}
+
+// QuestionRunLanguageRepository stores the detected response language of a run.
+type QuestionRunLanguageRepository interface {
+	SetDetectedLanguage(ctx context.Context, questionRunID uuid.UUID, language string) error
+}
+
+type questionRunLanguageRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunLanguageRepo(db *database.Client) QuestionRunLanguageRepository {
+	return &questionRunLanguageRepo{db: db}
+}
+
+func (r *questionRunLanguageRepo) SetDetectedLanguage(ctx context.Context, questionRunID uuid.UUID, language string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_languages (question_run_id, detected_language)
+		VALUES ($1, $2)
+		ON CONFLICT (question_run_id) DO UPDATE SET detected_language = EXCLUDED.detected_language`,
+		questionRunID, language)
+	if err != nil {
+		return fmt.Errorf("failed to store question run language: %w", err)
+	}
+	return nil
+}
+
+// recordResponseLanguage detects the language of a stored run's response, sets
+// AIResponse.DetectedLanguage and persists it; failures are logged, not fatal.
+// Empty and failed responses are skipped and return "".
+func recordResponseLanguage(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, aiResponse *AIResponse) string {
+	if aiResponse == nil || !aiResponse.ShouldProcessEvaluation || strings.TrimSpace(aiResponse.Response) == "" {
+		return ""
+	}
+	aiResponse.DetectedLanguage = ResponseLanguageDetector.Detect(aiResponse.Response)
+	if repos.QuestionRunLanguageRepo != nil {
+		if err := repos.QuestionRunLanguageRepo.SetDetectedLanguage(ctx, questionRunID, aiResponse.DetectedLanguage); err != nil {
+			fmt.Printf("[recordResponseLanguage] Warning: %v\n", err)
+		}
+	}
+	return aiResponse.DetectedLanguage
+}
+
+// countLanguage adds a detected language to a summary's counts; "" (detection
+// skipped) is not counted.
+func countLanguage(counts map[string]int, language string) map[string]int {
+	if language == "" {
+		return counts
+	}
+	if counts == nil {
+		counts = make(map[string]int)
+	}
+	counts[language]++
+	return counts
+}
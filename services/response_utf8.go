@@ -0,0 +1,45 @@
+// services/response_utf8.go
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Some web-search results reach the providers' responses with invalid UTF-8
+// byte sequences. Postgres rejects them in text columns and encoding/json
+// silently rewrites them, so providers sanitize every AIResponse before
+// returning it and stored response text is always valid UTF-8.
+
+// ValidUTF8 returns s with each run of invalid UTF-8 bytes replaced by the
+// replacement rune (U+FFFD). Valid strings are returned unchanged.
+func ValidUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	return strings.ToValidUTF8(s, string(utf8.RuneError))
+}
+
+// sanitizeResponse makes the text fields of *resp valid UTF-8.
+// Providers defer it at the top of RunQuestion so every return path is covered.
+func sanitizeResponse(resp **AIResponse) {
+	r := *resp
+	if r == nil {
+		return
+	}
+	if !utf8.ValidString(r.Response) {
+		fmt.Printf("[sanitizeResponse] Replacing invalid UTF-8 in a %d-byte response\n", len(r.Response))
+		r.Response = ValidUTF8(r.Response)
+	}
+	for i, citation := range r.Citations {
+		r.Citations[i] = ValidUTF8(citation)
+	}
+}
+
+// sanitizeBatchResponses is sanitizeResponse for every response of a batch.
+func sanitizeBatchResponses(responses *[]*AIResponse) {
+	for i := range *responses {
+		sanitizeResponse(&(*responses)[i])
+	}
+}
@@ -0,0 +1,136 @@
+// services/sampling_params.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// Extraction calls set either a sampling temperature or, for reasoning models
+// (gpt-5 family, o-series), reasoning_effort; each kind of model rejects the
+// other parameter with a 400. Azure deployment names don't reliably say which
+// kind a deployment is (an alias like gpt5-prod-eu), so the first call to a
+// model uses its EXTRACTION_PARAM_STYLES entry or a guess from its name, and a
+// 400 naming the unsupported parameter is retried once with the other style.
+// The style that worked is remembered per model name for the life of the
+// process, so later calls go straight to it.
+
+// samplingStyle is how an extraction call controls sampling.
+type samplingStyle string
+
+const (
+	samplingTemperature samplingStyle = "temperature"
+	samplingReasoning   samplingStyle = "reasoning"
+)
+
+func (s samplingStyle) other() samplingStyle {
+	if s == samplingReasoning {
+		return samplingTemperature
+	}
+	return samplingReasoning
+}
+
+// detectedSamplingStyles maps a model name to the samplingStyle its calls
+// succeeded with.
+var detectedSamplingStyles sync.Map
+
+// chatCompletionFunc is the signature of openai.Client.Chat.Completions.New.
+type chatCompletionFunc func(ctx context.Context, params openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error)
+
+// isReasoningModel reports whether model's name looks like a model that
+// rejects temperature and takes a reasoning effort instead (gpt-5 family and
+// o-series). It is only the first guess; see newExtractionCompletion.
+func isReasoningModel(model string) bool {
+	model = strings.ToLower(model)
+	for _, prefix := range []string{"gpt-5", "o1", "o3", "o4"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// initialSamplingStyle returns the style to try first for model: the detected
+// one, then the configured one, then a guess from the name.
+func initialSamplingStyle(cfg *config.Config, model string) samplingStyle {
+	if style, ok := detectedSamplingStyles.Load(model); ok {
+		return style.(samplingStyle)
+	}
+	if cfg != nil {
+		switch style := samplingStyle(cfg.ExtractionParamStyles[strings.ToLower(model)]); style {
+		case samplingTemperature, samplingReasoning:
+			return style
+		}
+	}
+	if isReasoningModel(model) {
+		return samplingReasoning
+	}
+	return samplingTemperature
+}
+
+// withSamplingStyle returns a copy of params using style.
+func withSamplingStyle(params openai.ChatCompletionNewParams, style samplingStyle, temperature float64) openai.ChatCompletionNewParams {
+	if style == samplingReasoning {
+		params.ReasoningEffort = "low"
+	} else {
+		params.Temperature = openai.Float(temperature)
+	}
+	return params
+}
+
+// rejectsSamplingStyle reports whether err is a 400 naming the parameter of style.
+func rejectsSamplingStyle(err error, style samplingStyle) bool {
+	var openaiErr *openai.Error
+	if !errors.As(err, &openaiErr) || openaiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	text := strings.ToLower(openaiErr.Param + " " + openaiErr.Message)
+	if style == samplingReasoning {
+		return strings.Contains(text, "reasoning_effort") || strings.Contains(text, "reasoning.effort")
+	}
+	return strings.Contains(text, "temperature")
+}
+
+// rememberSamplingStyle caches the style model's calls succeeded with and
+// logs it the first time.
+func rememberSamplingStyle(model string, style samplingStyle, logTag string) {
+	if previous, loaded := detectedSamplingStyles.Swap(model, style); !loaded || previous != style {
+		fmt.Printf("[%s] Model %s takes %s parameters, using them for its later calls\n", logTag, model, style)
+	}
+}
+
+// newExtractionCompletion sends params (which must not set Temperature or
+// ReasoningEffort) with the model's sampling style, retrying once with the
+// other style when the model rejects the first. temperature is used when the
+// model takes one. It is safe for concurrent use: params is copied per call,
+// and the request options (see WithExtractionTimeout) come from ctx.
+func newExtractionCompletion(ctx context.Context, cfg *config.Config, create chatCompletionFunc, params openai.ChatCompletionNewParams, temperature float64, logTag string) (*openai.ChatCompletion, error) {
+	model := string(params.Model)
+	opts := extractionRequestOptions(ctx)
+	style := initialSamplingStyle(cfg, model)
+	chatResponse, err := create(ctx, withSamplingStyle(params, style, temperature), opts...)
+	if err == nil {
+		rememberSamplingStyle(model, style, logTag)
+		return chatResponse, nil
+	}
+	if !rejectsSamplingStyle(err, style) {
+		return nil, err
+	}
+
+	fallback := style.other()
+	fmt.Printf("[%s] Model %s rejected %s parameters, retrying with %s: %v\n", logTag, model, style, fallback, err)
+	chatResponse, err = create(ctx, withSamplingStyle(params, fallback, temperature), opts...)
+	if err != nil {
+		return nil, err
+	}
+	rememberSamplingStyle(model, fallback, logTag)
+	return chatResponse, nil
+}
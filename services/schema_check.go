@@ -0,0 +1,151 @@
+// services/schema_check.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// responseSchemas lists every response type sent to OpenAI as a strict
+// structured-output schema. Add new response types here so the startup
+// self-test covers them.
+var responseSchemas = map[string]func() interface{}{
+	"MentionsExtractionResponse":   GenerateSchema[MentionsExtractionResponse],
+	"ClaimsExtractionResponse":     GenerateSchema[ClaimsExtractionResponse],
+	"CitationsExtractionResponse":  GenerateSchema[CitationsExtractionResponse],
+	"NameListResponse":             GenerateSchema[NameListResponse],
+	"CompetitorListResponse":       GenerateSchema[CompetitorListResponse],
+	"NetworkOrgEvaluationResponse": GenerateSchema[NetworkOrgEvaluationResponse],
+	"OrgEvaluationResponse":        GenerateSchema[OrgEvaluationResponse],
+	"ExtractResponse":              GenerateSchema[ExtractResponse],
+}
+
+// CheckResponseSchemas generates the schema of every response type and checks
+// it against OpenAI's strict-mode constraints, so a struct change that breaks
+// structured outputs fails at startup instead of on the first API call.
+func CheckResponseSchemas() error {
+	names := make([]string, 0, len(responseSchemas))
+	for name := range responseSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if err := ValidateStrictSchema(responseSchemas[name]()); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d response schema(s) not valid for OpenAI strict mode: %s", len(problems), strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ValidateStrictSchema checks a generated schema against the OpenAI strict
+// structured-output rules: the root is an object, every object sets
+// additionalProperties=false and lists all of its properties as required,
+// and no $ref/$defs indirection is used.
+func ValidateStrictSchema(schema interface{}) error {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return fmt.Errorf("failed to decode schema: %w", err)
+	}
+	if root["type"] != "object" {
+		return fmt.Errorf("root type must be object, got %v", root["type"])
+	}
+
+	var problems []string
+	validateSchemaNode("$", root, &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+var strictSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true, "null": true,
+}
+
+func validateSchemaNode(path string, node map[string]interface{}, problems *[]string) {
+	for _, key := range []string{"$ref", "$defs", "definitions"} {
+		if _, ok := node[key]; ok {
+			*problems = append(*problems, fmt.Sprintf("%s uses %s", path, key))
+		}
+	}
+
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		branches, _ := node[key].([]interface{})
+		for i, branch := range branches {
+			if child, ok := branch.(map[string]interface{}); ok {
+				validateSchemaNode(fmt.Sprintf("%s.%s[%d]", path, key, i), child, problems)
+			}
+		}
+	}
+
+	types := schemaTypes(node["type"])
+	if len(types) == 0 && node["anyOf"] == nil {
+		*problems = append(*problems, fmt.Sprintf("%s has no type", path))
+	}
+	for _, t := range types {
+		if !strictSchemaTypes[t] {
+			*problems = append(*problems, fmt.Sprintf("%s has unsupported type %q", path, t))
+		}
+	}
+
+	if slices.Contains(types, "object") {
+		if additional, ok := node["additionalProperties"].(bool); !ok || additional {
+			*problems = append(*problems, fmt.Sprintf("%s must set additionalProperties=false", path))
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		required := make(map[string]bool)
+		if list, ok := node["required"].([]interface{}); ok {
+			for _, r := range list {
+				if s, ok := r.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !required[key] {
+				*problems = append(*problems, fmt.Sprintf("%s.%s is not required", path, key))
+			}
+			if child, ok := properties[key].(map[string]interface{}); ok {
+				validateSchemaNode(path+"."+key, child, problems)
+			}
+		}
+	}
+
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		validateSchemaNode(path+"[]", items, problems)
+	}
+}
+
+// schemaTypes returns the "type" keyword as a list ("string" or ["string","null"]).
+func schemaTypes(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// services/sov_normalization.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Share of voice is mention length over response length, but web-search
+// responses end with reference lists and tables of sources (Perplexity's
+// citation blocks are huge) that inflate the denominator and make SOV
+// incomparable across models. The normalized SOV divides by the response with
+// that boilerplate removed instead. During the transition target_sov keeps the
+// raw value and, with SOV_NORMALIZATION on, both values are also stored here:
+//
+//	migrations/000011_question_run_sov.up.sql
+
+var (
+	// sovReferencesHeadingRe matches a line that opens a trailing reference
+	// section ("Sources:", "## References", "**Citations**").
+	sovReferencesHeadingRe = regexp.MustCompile(`(?im)^[ \t]*(?:#{1,6}[ \t]*)?(?:\*\*|__)?[ \t]*(?:sources|references|citations|further reading|learn more|read more)[ \t]*:?[ \t]*(?:\*\*|__)?[ \t]*:?[ \t]*$`)
+	// sovReferenceLineRe matches a line that is only a reference: "[1] https://...",
+	// "1. https://...", "- [Title](https://...)".
+	sovReferenceLineRe   = regexp.MustCompile(`^\s*(?:[-*+]\s*)?(?:\[\d+\]:?|\d+[.)])?\s*(?:\[[^\]]*\]\(\s*https?://[^)]*\)|<?https?://\S+>?)\s*$`)
+	sovMarkdownLinkRe    = regexp.MustCompile(`\[([^\]]*)\]\(\s*https?://[^)]*\)`)
+	sovBareURLRe         = regexp.MustCompile(`<?https?://[^\s)>\]]+>?`)
+	sovCitationMarkerRe  = regexp.MustCompile(`\[\d+(?:\s*[,-]\s*\d+)*\]`)
+	sovTableSeparatorRe  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(?:\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	sovEmptyTableCellsRe = regexp.MustCompile(`^[\s|]*$`)
+)
+
+// SOVDenominator returns the response text normalized SOV divides by: a
+// trailing reference/citation section is cut (only when it starts in the
+// second half of the response, so an answer that opens with "Sources" is kept),
+// markdown links keep their text but lose the URL, bare URLs, citation markers
+// ([1]) and table separator rows are dropped, and so are lines left empty by
+// that (link-only list items and table rows).
+func SOVDenominator(response string) string {
+	text := response
+	if locs := sovReferencesHeadingRe.FindAllStringIndex(text, -1); len(locs) > 0 {
+		if start := locs[len(locs)-1][0]; start >= len(text)/2 {
+			text = text[:start]
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if sovReferenceLineRe.MatchString(line) || sovTableSeparatorRe.MatchString(line) {
+			continue
+		}
+		stripped := sovMarkdownLinkRe.ReplaceAllString(line, "$1")
+		stripped = sovBareURLRe.ReplaceAllString(stripped, "")
+		stripped = sovCitationMarkerRe.ReplaceAllString(stripped, "")
+		if strings.TrimSpace(line) != "" && sovEmptyTableCellsRe.MatchString(stripped) {
+			continue
+		}
+		kept = append(kept, stripped)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// normalizedShareOfVoice is mentionLen over the length of SOVDenominator(response),
+// capped at 1 (the mention itself may quote boilerplate). ok is false when
+// nothing is left of the response.
+func normalizedShareOfVoice(mentionLen int, response string) (sov float64, ok bool) {
+	denominator := len(SOVDenominator(response))
+	if denominator == 0 {
+		return 0, false
+	}
+	ratio := float64(mentionLen) / float64(denominator)
+	if ratio > 1 {
+		ratio = 1
+	}
+	return ratio, true
+}
+
+// QuestionRunSOVRepository stores a run's raw and normalized share of voice.
+type QuestionRunSOVRepository interface {
+	// Upsert stores both values; re-running the metrics stage overwrites them.
+	Upsert(ctx context.Context, questionRunID uuid.UUID, rawSOV, normalizedSOV float64) error
+}
+
+type questionRunSOVRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunSOVRepo(db *database.Client) QuestionRunSOVRepository {
+	return &questionRunSOVRepo{db: db}
+}
+
+func (r *questionRunSOVRepo) Upsert(ctx context.Context, questionRunID uuid.UUID, rawSOV, normalizedSOV float64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_sov (question_run_id, raw_sov, normalized_sov)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (question_run_id) DO UPDATE SET
+		    raw_sov = EXCLUDED.raw_sov,
+		    normalized_sov = EXCLUDED.normalized_sov`,
+		questionRunID, rawSOV, normalizedSOV)
+	if err != nil {
+		return fmt.Errorf("failed to store share of voice for question run %s: %w", questionRunID, err)
+	}
+	return nil
+}
+
+// recordShareOfVoice stores the run's raw and normalized SOV when both were
+// computed; failures are logged, never fatal.
+func recordShareOfVoice(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, metrics *CompetitiveMetrics) {
+	if repos.QuestionRunSOVRepo == nil || metrics.ShareOfVoice == nil || metrics.NormalizedShareOfVoice == nil {
+		return
+	}
+	if err := repos.QuestionRunSOVRepo.Upsert(ctx, questionRunID, *metrics.ShareOfVoice, *metrics.NormalizedShareOfVoice); err != nil {
+		fmt.Printf("[recordShareOfVoice] Warning: %v\n", err)
+	}
+}
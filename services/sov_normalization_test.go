@@ -0,0 +1,81 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSOVDenominator(t *testing.T) {
+	answer := "Acme Bank offers the lowest fees and Globex has the best app for most people today."
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{name: "plain answer unchanged", response: answer, want: answer},
+		{
+			name:     "trailing sources section cut",
+			response: answer + "\n\nSources:\n[1] https://acme.example\n[2] https://globex.example",
+			want:     answer,
+		},
+		{
+			name:     "markdown heading references cut",
+			response: answer + "\n\n## References\n- [Acme](https://acme.example)",
+			want:     answer,
+		},
+		{
+			name:     "leading sources heading kept",
+			response: "Sources:\n" + answer + " " + answer,
+			want:     "Sources:\n" + answer + " " + answer,
+		},
+		{
+			name:     "links keep their text, URLs and markers dropped",
+			response: "See [Acme Bank](https://acme.example) [1] or https://globex.example [2, 3].",
+			want:     "See Acme Bank  or  .",
+		},
+		{
+			name:     "link-only list items dropped",
+			response: "Options:\n- [Acme](https://acme.example)\n1. https://globex.example\n- Initech",
+			want:     "Options:\n- Initech",
+		},
+		{
+			name:     "table separator and URL-only rows dropped",
+			response: "| Bank | Fee |\n|---|:---:|\n| Acme | $0 |\n| https://acme.example | |",
+			want:     "| Bank | Fee |\n| Acme | $0 |",
+		},
+		{name: "only boilerplate", response: "https://acme.example\n[1] https://globex.example", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SOVDenominator(tt.response); got != tt.want {
+				t.Errorf("SOVDenominator() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedShareOfVoice(t *testing.T) {
+	answer := strings.Repeat("a", 100)
+	tests := []struct {
+		name       string
+		mentionLen int
+		response   string
+		want       float64
+		wantOK     bool
+	}{
+		{name: "plain response", mentionLen: 25, response: answer, want: 0.25, wantOK: true},
+		{name: "references excluded", mentionLen: 25, response: answer + "\nSources:\n[1] https://acme.example", want: 0.25, wantOK: true},
+		{name: "capped at one", mentionLen: 150, response: answer, want: 1, wantOK: true},
+		{name: "no mention", mentionLen: 0, response: answer, want: 0, wantOK: true},
+		{name: "nothing left", mentionLen: 10, response: "https://acme.example", want: 0, wantOK: false},
+		{name: "empty response", mentionLen: 10, response: "", want: 0, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := normalizedShareOfVoice(tt.mentionLen, tt.response)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("normalizedShareOfVoice() = %v, %t; want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
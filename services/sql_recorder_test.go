@@ -0,0 +1,16 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/sqltest"
+)
+
+// sqlRecorder records the SQL the repositories run instead of running it.
+type sqlRecorder = sqltest.Recorder
+
+// newRecordingRepos returns a RepositoryManager whose database is rec.
+func newRecordingRepos(t *testing.T, rec *sqlRecorder) *RepositoryManager {
+	t.Helper()
+	return &RepositoryManager{db: sqltest.NewClient(t, rec)}
+}
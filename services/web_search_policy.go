@@ -0,0 +1,126 @@
+// services/web_search_policy.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/google/uuid"
+)
+
+// Question runs are always requested with web search. A provider that cannot
+// search (AIProvider.SupportsWebSearch) would otherwise return an ungrounded
+// answer stored as if it were web-grounded, so UNSUPPORTED_WEB_SEARCH_POLICY
+// decides what happens:
+//
+//	skip      (default) the model/location combination fails with ErrWebSearchUnsupported
+//	downgrade the question runs without search and the run is marked here
+//
+// question_runs is owned by the senso-api migrations, so the marker lives in
+// its own table:
+//
+//	migrations/000004_question_run_web_search.up.sql
+
+const (
+	WebSearchPolicySkip      = "skip"
+	WebSearchPolicyDowngrade = "downgrade"
+)
+
+// ErrWebSearchUnsupported is returned for combinations skipped by the policy.
+var ErrWebSearchUnsupported = errors.New("web search requested but not supported by provider")
+
+// applyWebSearchPolicy returns the provider to run modelName with. Providers
+// that support web search are returned as is; otherwise the policy either wraps
+// the provider to run without search or rejects the combination.
+func applyWebSearchPolicy(cfg *config.Config, provider AIProvider, modelName string) (AIProvider, error) {
+	if provider.SupportsWebSearch() {
+		return provider, nil
+	}
+	if cfg != nil && cfg.UnsupportedWebSearchPolicy == WebSearchPolicyDowngrade {
+		fmt.Printf("[applyWebSearchPolicy] ⚠️ Model %s cannot web search; running without it (web_search_used=false)\n", modelName)
+		return &noWebSearchProvider{AIProvider: provider}, nil
+	}
+	return nil, fmt.Errorf("%w: model %s (set UNSUPPORTED_WEB_SEARCH_POLICY=downgrade to run it without search)", ErrWebSearchUnsupported, modelName)
+}
+
+// noWebSearchProvider runs every question with websearch=false and flags the
+// responses whose caller asked for search.
+type noWebSearchProvider struct {
+	AIProvider
+}
+
+func (p *noWebSearchProvider) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+	response, err := p.AIProvider.RunQuestion(ctx, query, false, location)
+	if response != nil {
+		response.WebSearchDowngraded = websearch
+	}
+	return response, err
+}
+
+func (p *noWebSearchProvider) RunQuestionWebSearch(ctx context.Context, query string) (*AIResponse, error) {
+	response, err := p.AIProvider.RunQuestionWebSearch(ctx, query)
+	if response != nil {
+		response.WebSearchDowngraded = true
+	}
+	return response, err
+}
+
+func (p *noWebSearchProvider) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	responses, err := p.AIProvider.RunQuestionBatch(ctx, batch, false, location)
+	for _, response := range responses {
+		if response != nil {
+			response.WebSearchDowngraded = websearch
+		}
+	}
+	return responses, err
+}
+
+func (p *noWebSearchProvider) RunQuestionBatchAwait(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	responses, err := p.AIProvider.RunQuestionBatchAwait(ctx, batch, false, location)
+	for _, response := range responses {
+		if response != nil {
+			response.WebSearchDowngraded = websearch
+		}
+	}
+	return responses, err
+}
+
+// QuestionRunWebSearchRepository records whether a stored run used web search.
+type QuestionRunWebSearchRepository interface {
+	MarkWebSearchUsed(ctx context.Context, questionRunID uuid.UUID, used bool) error
+}
+
+type questionRunWebSearchRepo struct {
+	db *database.Client
+}
+
+func NewQuestionRunWebSearchRepo(db *database.Client) QuestionRunWebSearchRepository {
+	return &questionRunWebSearchRepo{db: db}
+}
+
+func (r *questionRunWebSearchRepo) MarkWebSearchUsed(ctx context.Context, questionRunID uuid.UUID, used bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO question_run_web_search (question_run_id, web_search_used)
+		VALUES ($1, $2)
+		ON CONFLICT (question_run_id) DO UPDATE SET web_search_used = EXCLUDED.web_search_used`,
+		questionRunID, used)
+	if err != nil {
+		return fmt.Errorf("failed to mark question run web search: %w", err)
+	}
+	return nil
+}
+
+// recordWebSearchDowngrade marks a stored run as answered without web search;
+// failures are logged, not fatal.
+func recordWebSearchDowngrade(ctx context.Context, repos *RepositoryManager, questionRunID uuid.UUID, aiResponse *AIResponse) {
+	if !aiResponse.WebSearchDowngraded || repos.QuestionRunWebSearchRepo == nil {
+		return
+	}
+	if err := repos.QuestionRunWebSearchRepo.MarkWebSearchUsed(ctx, questionRunID, false); err != nil {
+		fmt.Printf("[recordWebSearchDowngrade] Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	workflowModels "github.com/AI-Template-SDK/senso-workflows/internal/models"
+	"github.com/google/uuid"
+)
+
+// searchFake records the websearch flag of every question it runs.
+type searchFake struct {
+	AIProvider
+	webSearch bool
+	asked     []bool
+}
+
+func (f *searchFake) SupportsWebSearch() bool { return f.webSearch }
+
+func (f *searchFake) RunQuestion(ctx context.Context, query string, websearch bool, location *workflowModels.Location) (*AIResponse, error) {
+	f.asked = append(f.asked, websearch)
+	return &AIResponse{Response: "answer to " + query}, nil
+}
+
+func (f *searchFake) RunQuestionBatch(ctx context.Context, batch []BatchQuery, websearch bool, location *workflowModels.Location) ([]*AIResponse, error) {
+	responses := make([]*AIResponse, len(batch))
+	for i, query := range batch {
+		f.asked = append(f.asked, websearch)
+		responses[i] = &AIResponse{Response: "answer to " + query.Query, CorrelationID: query.CorrelationID}
+	}
+	return responses, nil
+}
+
+func TestApplyWebSearchPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		webSearch     bool
+		wantErr       bool
+		wantAsked     []bool
+		wantDowngrade bool
+	}{
+		{name: "supported", policy: WebSearchPolicySkip, webSearch: true, wantAsked: []bool{true}},
+		{name: "supported ignores downgrade", policy: WebSearchPolicyDowngrade, webSearch: true, wantAsked: []bool{true}},
+		{name: "unsupported is skipped", policy: WebSearchPolicySkip, wantErr: true},
+		{name: "unset policy skips", wantErr: true},
+		{name: "unsupported is downgraded", policy: WebSearchPolicyDowngrade, wantAsked: []bool{false}, wantDowngrade: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &searchFake{webSearch: tt.webSearch}
+			provider, err := applyWebSearchPolicy(&config.Config{UnsupportedWebSearchPolicy: tt.policy}, fake, "sonar")
+			if tt.wantErr {
+				if !errors.Is(err, ErrWebSearchUnsupported) || !strings.Contains(err.Error(), "sonar") {
+					t.Fatalf("applyWebSearchPolicy() error = %v, want ErrWebSearchUnsupported naming the model", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyWebSearchPolicy() error = %v", err)
+			}
+
+			response, err := provider.RunQuestion(context.Background(), "Best bank?", true, nil)
+			if err != nil {
+				t.Fatalf("RunQuestion() error = %v", err)
+			}
+			if !reflect.DeepEqual(fake.asked, tt.wantAsked) {
+				t.Errorf("provider asked with websearch %v, want %v", fake.asked, tt.wantAsked)
+			}
+			if response.WebSearchDowngraded != tt.wantDowngrade {
+				t.Errorf("WebSearchDowngraded = %t, want %t", response.WebSearchDowngraded, tt.wantDowngrade)
+			}
+		})
+	}
+}
+
+func TestNoWebSearchProviderBatch(t *testing.T) {
+	batch := []BatchQuery{{CorrelationID: "q1", Query: "Best bank?"}, {CorrelationID: "q2", Query: "Best broker?"}}
+	for _, websearch := range []bool{true, false} {
+		fake := &searchFake{}
+		responses, err := (&noWebSearchProvider{AIProvider: fake}).RunQuestionBatch(context.Background(), batch, websearch, nil)
+		if err != nil {
+			t.Fatalf("RunQuestionBatch() error = %v", err)
+		}
+		if !reflect.DeepEqual(fake.asked, []bool{false, false}) {
+			t.Errorf("websearch=%t: provider asked with websearch %v, want it off", websearch, fake.asked)
+		}
+		for _, response := range responses {
+			if response.WebSearchDowngraded != websearch {
+				t.Errorf("websearch=%t: response %s WebSearchDowngraded = %t", websearch, response.CorrelationID, response.WebSearchDowngraded)
+			}
+		}
+	}
+}
+
+func TestRecordWebSearchDowngrade(t *testing.T) {
+	questionRunID := uuid.New()
+	tests := []struct {
+		name       string
+		downgraded bool
+		wantWrites int
+	}{
+		{name: "downgraded run is marked", downgraded: true, wantWrites: 1},
+		{name: "searched run is not", downgraded: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &sqlRecorder{Exec: func(query string, args []any) (int64, error) { return 1, nil }}
+			repos := newRecordingRepos(t, rec)
+			repos.QuestionRunWebSearchRepo = NewQuestionRunWebSearchRepo(repos.db)
+
+			recordWebSearchDowngrade(context.Background(), repos, questionRunID, &AIResponse{WebSearchDowngraded: tt.downgraded})
+			statements := rec.Statements()
+			if len(statements) != tt.wantWrites {
+				t.Fatalf("statements = %q, want %d", rec.Queries(), tt.wantWrites)
+			}
+			if tt.wantWrites > 0 {
+				if args := statements[0].Args; !strings.Contains(statements[0].Query, "question_run_web_search") || args[0] != questionRunID || args[1] != false {
+					t.Errorf("statement %q with %v, want web_search_used=false for %s", statements[0].Query, args, questionRunID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// services/weekly_load_report.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AI-Template-SDK/senso-api/pkg/database"
+	"github.com/google/uuid"
+)
+
+// Weekly capacity-planning report: batch and run volume, failures, provider
+// mix, spend, batch duration and the most expensive orgs/networks of the past
+// week. Everything is aggregated in SQL so no runs are loaded into memory.
+// Reports are kept one row per window:
+//
+//	migrations/000006_weekly_load_reports.up.sql
+
+// weeklyLoadTopN is how many orgs and networks are listed by cost.
+const weeklyLoadTopN = 10
+
+// WeeklyLoadReport is the aggregate of the batches and runs created in
+// [WindowStart, WindowEnd).
+type WeeklyLoadReport struct {
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+
+	Batches          int `json:"batches"`
+	BatchesCompleted int `json:"batches_completed"`
+	BatchesPartial   int `json:"batches_partial"`
+	BatchesFailed    int `json:"batches_failed"`
+	// BatchesOpen are batches still pending or running at report time.
+	BatchesOpen int `json:"batches_open"`
+	// P95BatchSeconds is the 95th percentile of started_at -> completed_at
+	// over the finished batches.
+	P95BatchSeconds float64 `json:"p95_batch_seconds"`
+
+	Runs int `json:"runs"`
+	// FailedRuns sums the failed_questions counters of the window's batches;
+	// failed runs are not stored as question runs.
+	FailedRuns int     `json:"failed_runs"`
+	TotalCost  float64 `json:"total_cost"`
+
+	Providers   []ProviderLoad `json:"providers"`
+	TopOrgs     []ScopeLoad    `json:"top_orgs"`
+	TopNetworks []ScopeLoad    `json:"top_networks"`
+}
+
+// ProviderLoad is the run count and cost of one provider in the window.
+type ProviderLoad struct {
+	Provider string  `db:"provider" json:"provider"`
+	Runs     int     `db:"runs" json:"runs"`
+	Cost     float64 `db:"cost" json:"cost"`
+}
+
+// ScopeLoad is the run count and cost of one org or network in the window.
+type ScopeLoad struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Runs int       `db:"runs" json:"runs"`
+	Cost float64   `db:"cost" json:"cost"`
+}
+
+// WeeklyLoadWindow returns the 7 days ending at the most recent UTC midnight
+// at or before now, so a run shortly after Sunday midnight reports Sunday to
+// Saturday and a retried run reports the same week.
+func WeeklyLoadWindow(now time.Time) (start, end time.Time) {
+	end = now.UTC().Truncate(24 * time.Hour)
+	return end.AddDate(0, 0, -7), end
+}
+
+// String renders the report as a short multi-line summary for Slack.
+func (r *WeeklyLoadReport) String() string {
+	summary := fmt.Sprintf("*Weekly load %s → %s*\n"+
+		"Batches: %d (%d completed, %d partial, %d failed, %d open), p95 duration %.0fs\n"+
+		"Runs: %d stored, %d failed, $%.2f total cost\n",
+		r.WindowStart.Format("2006-01-02"), r.WindowEnd.Format("2006-01-02"),
+		r.Batches, r.BatchesCompleted, r.BatchesPartial, r.BatchesFailed, r.BatchesOpen, r.P95BatchSeconds,
+		r.Runs, r.FailedRuns, r.TotalCost)
+	for _, p := range r.Providers {
+		summary += fmt.Sprintf("• %s: %d runs, $%.2f\n", p.Provider, p.Runs, p.Cost)
+	}
+	if len(r.TopOrgs) > 0 {
+		summary += fmt.Sprintf("Top org: %s ($%.2f)\n", r.TopOrgs[0].ID, r.TopOrgs[0].Cost)
+	}
+	if len(r.TopNetworks) > 0 {
+		summary += fmt.Sprintf("Top network: %s ($%.2f)\n", r.TopNetworks[0].ID, r.TopNetworks[0].Cost)
+	}
+	return summary
+}
+
+// WeeklyLoadRepository aggregates and stores weekly load reports.
+type WeeklyLoadRepository interface {
+	Aggregate(ctx context.Context, start, end time.Time) (*WeeklyLoadReport, error)
+	Save(ctx context.Context, report *WeeklyLoadReport) error
+}
+
+type weeklyLoadRepo struct {
+	db *database.Client
+}
+
+func NewWeeklyLoadRepo(db *database.Client) WeeklyLoadRepository {
+	return &weeklyLoadRepo{db: db}
+}
+
+// runProviderSQL classifies question_runs.run_model like the built-in
+// routing in getProvider (MODEL_PROVIDER_OVERRIDES are not reflected).
+const runProviderSQL = `CASE
+		WHEN qr.run_model IS NULL THEN 'unknown'
+		WHEN qr.run_model ILIKE '%chatgpt%' THEN 'brightdata'
+		WHEN qr.run_model ILIKE '%perplexity%' THEN 'perplexity'
+		WHEN qr.run_model ILIKE '%gemini%' THEN 'gemini'
+		WHEN qr.run_model ILIKE '%linkup%' THEN 'linkup'
+		WHEN qr.run_model ILIKE '%gpt%' OR qr.run_model ILIKE '%4.1%' THEN 'openai'
+		WHEN qr.run_model ~* '(claude|sonnet|opus|haiku)' THEN 'anthropic'
+		ELSE 'other'
+	END`
+
+func (r *weeklyLoadRepo) Aggregate(ctx context.Context, start, end time.Time) (*WeeklyLoadReport, error) {
+	report := &WeeklyLoadReport{WindowStart: start, WindowEnd: end}
+
+	var batches struct {
+		Total      int     `db:"total"`
+		Completed  int     `db:"completed"`
+		Partial    int     `db:"partial"`
+		Failed     int     `db:"failed"`
+		FailedRuns int     `db:"failed_runs"`
+		P95Seconds float64 `db:"p95_seconds"`
+	}
+	err := r.db.GetContext(ctx, &batches, `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE status = 'partial') AS partial,
+			COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+			COALESCE(SUM(failed_questions), 0) AS failed_runs,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at))
+				FILTER (WHERE started_at IS NOT NULL AND completed_at IS NOT NULL), 0) AS p95_seconds
+		FROM question_run_batches
+		WHERE created_at >= $1 AND created_at < $2 AND deleted_at IS NULL`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate batches: %w", err)
+	}
+	report.Batches = batches.Total
+	report.BatchesCompleted = batches.Completed
+	report.BatchesPartial = batches.Partial
+	report.BatchesFailed = batches.Failed
+	report.BatchesOpen = batches.Total - batches.Completed - batches.Partial - batches.Failed
+	report.FailedRuns = batches.FailedRuns
+	report.P95BatchSeconds = batches.P95Seconds
+
+	if err := r.db.SelectContext(ctx, &report.Providers, `
+		SELECT `+runProviderSQL+` AS provider, COUNT(*) AS runs, COALESCE(SUM(qr.total_cost), 0) AS cost
+		FROM question_runs qr
+		WHERE qr.created_at >= $1 AND qr.created_at < $2
+		GROUP BY 1
+		ORDER BY cost DESC`, start, end); err != nil {
+		return nil, fmt.Errorf("failed to aggregate provider mix: %w", err)
+	}
+	for _, p := range report.Providers {
+		report.Runs += p.Runs
+		report.TotalCost += p.Cost
+	}
+
+	for _, scope := range []struct {
+		column string
+		dest   *[]ScopeLoad
+	}{
+		{"org_id", &report.TopOrgs},
+		{"network_id", &report.TopNetworks},
+	} {
+		query := fmt.Sprintf(`
+			SELECT gq.%[1]s AS id, COUNT(*) AS runs, COALESCE(SUM(qr.total_cost), 0) AS cost
+			FROM question_runs qr
+			JOIN geo_questions gq ON gq.geo_question_id = qr.geo_question_id
+			WHERE qr.created_at >= $1 AND qr.created_at < $2 AND gq.%[1]s IS NOT NULL
+			GROUP BY gq.%[1]s
+			ORDER BY cost DESC
+			LIMIT $3`, scope.column)
+		if err := r.db.SelectContext(ctx, scope.dest, query, start, end, weeklyLoadTopN); err != nil {
+			return nil, fmt.Errorf("failed to aggregate cost by %s: %w", scope.column, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (r *weeklyLoadRepo) Save(ctx context.Context, report *WeeklyLoadReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal weekly load report: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO weekly_load_reports (window_start, window_end, report)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (window_start) DO UPDATE SET
+			window_end = EXCLUDED.window_end,
+			report = EXCLUDED.report,
+			created_at = NOW()`,
+		report.WindowStart, report.WindowEnd, data)
+	if err != nil {
+		return fmt.Errorf("failed to save weekly load report: %w", err)
+	}
+	return nil
+}
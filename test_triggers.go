@@ -0,0 +1,134 @@
+// test_triggers.go
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
+)
+
+// scopedTrigger sends one workflow event for an org or network and returns its ID.
+type scopedTrigger func(ctx context.Context, send trigger.Sender, id string) (string, error)
+
+// Trigger scopes: which ID a test endpoint reads from the request body.
+const (
+	triggerScopeOrg     = "org"
+	triggerScopeNetwork = "network"
+)
+
+type testTriggerRequest struct {
+	OrgID     string `json:"org_id"`
+	NetworkID string `json:"network_id"`
+}
+
+// registerTestTriggers mounts the /test/trigger-* endpoints. They kick off real
+// workflows (and real provider spend), so unless ENVIRONMENT is explicitly
+// development they are only registered when TEST_TRIGGER_TOKEN is set, and
+// every request must carry it as a bearer token.
+func registerTestTriggers(mux *http.ServeMux, cfg *config.Config, send trigger.Sender) {
+	wrap := func(h http.HandlerFunc) http.Handler { return h }
+	if !cfg.ExplicitDevelopment {
+		if cfg.TestTriggerToken == "" {
+			log.Printf("Test trigger endpoints disabled (environment=%s, TEST_TRIGGER_TOKEN not set)", cfg.Environment)
+			return
+		}
+		wrap = func(h http.HandlerFunc) http.Handler { return requireBearerToken(cfg.TestTriggerToken, h) }
+		log.Printf("Test trigger endpoints enabled with bearer token auth")
+	}
+
+	// Test endpoint to trigger ProcessOrg workflow
+	mux.Handle("/test/trigger-org", wrap(testTriggerHandler(send, trigger.EventOrgProcess, "Test", triggerScopeOrg,
+		func(ctx context.Context, send trigger.Sender, orgID string) (string, error) {
+			return trigger.TriggerOrgProcess(ctx, send, trigger.OrgProcessEvent{OrgID: orgID, TriggeredBy: trigger.TriggeredByManualTest, UserID: testTriggerUserID})
+		})))
+	// Test endpoint to trigger ProcessOrgEvaluation workflow
+	mux.Handle("/test/trigger-org-evaluation", wrap(testTriggerHandler(send, trigger.EventOrgEvaluation, "Org evaluation test", triggerScopeOrg,
+		func(ctx context.Context, send trigger.Sender, orgID string) (string, error) {
+			return trigger.TriggerOrgEvaluation(ctx, send, trigger.OrgEvaluationProcessEvent{OrgID: orgID, TriggeredBy: trigger.TriggeredByManualTest, UserID: testTriggerUserID})
+		})))
+	// Test endpoint to trigger ProcessOrgReeval workflow
+	mux.Handle("/test/trigger-org-reeval", wrap(testTriggerHandler(send, trigger.EventOrgReeval, "Org re-evaluation test", triggerScopeOrg,
+		func(ctx context.Context, send trigger.Sender, orgID string) (string, error) {
+			return trigger.TriggerOrgReeval(ctx, send, trigger.OrgReevalProcessEvent{OrgID: orgID, TriggeredBy: trigger.TriggeredByManualTest, UserID: testTriggerUserID})
+		})))
+	// Test endpoint to trigger ReplayNetworkOrgDeadLetters workflow
+	mux.Handle("/test/trigger-network-org-dead-letter-replay", wrap(testTriggerHandler(send, trigger.EventNetworkOrgDeadLetter, "Network org dead letter replay test", triggerScopeOrg,
+		func(ctx context.Context, send trigger.Sender, orgID string) (string, error) {
+			return trigger.TriggerNetworkOrgDeadLetterReplay(ctx, send, trigger.NetworkOrgDeadLetterReplayEvent{OrgID: orgID, TriggeredBy: trigger.TriggeredByManualTest, UserID: testTriggerUserID})
+		})))
+	// Test endpoint to trigger the network question matrix workflow
+	mux.Handle("/test/trigger-network", wrap(testTriggerHandler(send, trigger.EventNetworkQuestions, "Network test", triggerScopeNetwork,
+		func(ctx context.Context, send trigger.Sender, networkID string) (string, error) {
+			return trigger.TriggerNetworkQuestions(ctx, send, trigger.NetworkProcessEvent{NetworkID: networkID, TriggeredBy: trigger.TriggeredByManualTest, UserID: testTriggerUserID})
+		})))
+}
+
+// testTriggerUserID is the user_id the test endpoints put on their events.
+const testTriggerUserID = "test-user"
+
+// requireBearerToken rejects requests whose Authorization header is not "Bearer <token>".
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// testTriggerHandler fires eventName via fire for the org_id or network_id
+// (per scope) given in the JSON body.
+func testTriggerHandler(send trigger.Sender, eventName, label, scope string, fire scopedTrigger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req testTriggerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		rawID := req.OrgID
+		if scope == triggerScopeNetwork {
+			rawID = req.NetworkID
+		}
+		id, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": scope + "_id must be a valid UUID"})
+			return
+		}
+
+		result, err := fire(r.Context(), send, id.String())
+		if err != nil {
+			log.Printf("Failed to send %s event: %v", eventName, err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to send event: %v", err)})
+			return
+		}
+
+		log.Printf("%s event sent successfully: %+v", label, result)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":    "success",
+			"message":   fmt.Sprintf("%s event sent for %s %s", label, scope, id),
+			"event_ids": []string{result},
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
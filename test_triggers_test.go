@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+)
+
+type recordingSender struct {
+	events []any
+}
+
+func (s *recordingSender) Send(ctx context.Context, evt any) (string, error) {
+	s.events = append(s.events, evt)
+	return "evt-1", nil
+}
+
+func TestTestTriggers(t *testing.T) {
+	const (
+		orgID     = "0b7c6a54-2f5e-4b1e-9a52-0d5f3f1f6c11"
+		networkID = "5d2f3c1e-8a4b-4f6e-b1a2-7c9d0e1f2a33"
+	)
+	production := &config.Config{Environment: "production", TestTriggerToken: "secret"}
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		path       string
+		auth       string
+		body       string
+		wantStatus int
+		wantEvents int
+		wantBody   string
+	}{
+		{
+			name: "explicit development needs no token", cfg: &config.Config{Environment: "development", ExplicitDevelopment: true},
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusOK, wantEvents: 1,
+		},
+		{
+			name: "defaulted development is not open", cfg: &config.Config{Environment: "development"},
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "defaulted development with token requires it", cfg: &config.Config{Environment: "development", TestTriggerToken: "secret"},
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "no token configured outside development", cfg: &config.Config{Environment: "staging"},
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "missing token", cfg: production,
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong token", cfg: production, auth: "Bearer nope",
+			path: "/test/trigger-org", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "bad org UUID", cfg: production, auth: "Bearer secret",
+			path: "/test/trigger-org", body: `{"org_id":"test-org-123"}`, wantStatus: http.StatusBadRequest, wantBody: "org_id must be a valid UUID",
+		},
+		{
+			name: "org success", cfg: production, auth: "Bearer secret",
+			path: "/test/trigger-org-evaluation", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusOK, wantEvents: 1, wantBody: "org " + orgID,
+		},
+		{
+			name: "network success", cfg: production, auth: "Bearer secret",
+			path: "/test/trigger-network", body: `{"network_id":"` + networkID + `"}`, wantStatus: http.StatusOK, wantEvents: 1, wantBody: "network " + networkID,
+		},
+		{
+			name: "network endpoint ignores org_id", cfg: production, auth: "Bearer secret",
+			path: "/test/trigger-network", body: `{"org_id":"` + orgID + `"}`, wantStatus: http.StatusBadRequest, wantBody: "network_id must be a valid UUID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send := &recordingSender{}
+			mux := http.NewServeMux()
+			registerTestTriggers(mux, tt.cfg, send)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if len(send.events) != tt.wantEvents {
+				t.Errorf("sent %d events, want %d", len(send.events), tt.wantEvents)
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/inngest/inngestgo"
 	"github.com/inngest/inngestgo/step"
+
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 )
 
 // DummyProcessor is for testing the scheduler
@@ -24,11 +26,8 @@ func (p *DummyProcessor) SetClient(client inngestgo.Client) {
 	p.client = client
 }
 
-// DummyProcessEvent represents the input event for dummy processing
-type DummyProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by,omitempty"`
-}
+// DummyProcessEvent is the event payload; its schema lives in pkg/trigger.
+type DummyProcessEvent = trigger.DummyProcessEvent
 
 // ProcessDummy is a test workflow that just logs its input
 func (p *DummyProcessor) ProcessDummy() inngestgo.ServableFunction {
@@ -38,7 +37,7 @@ func (p *DummyProcessor) ProcessDummy() inngestgo.ServableFunction {
 			ID:   "dummy-org-processor",
 			Name: "Dummy Org Processor (Scheduler Test)",
 		},
-		inngestgo.EventTrigger("dummy.org.process", nil), // <-- This matches the scheduler
+		inngestgo.EventTrigger(trigger.EventDummyOrgProcess, nil), // <-- This matches the scheduler
 		func(ctx context.Context, input inngestgo.Input[DummyProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 
@@ -3,13 +3,21 @@ package workflows
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/inngest/inngestgo"
 	"github.com/inngest/inngestgo/step"
+
+	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
-// Add a monitoring function to check load distribution
+// WeeklyLoadAnalyzer reports the org schedule distribution across weekdays
+// and, when the weekly load repository is configured, last week's batch and
+// run load. The load report is stored, logged as one structured record, sent
+// as a load.weekly_report event and posted to WEEKLY_LOAD_WEBHOOK_URL if set.
 func (p *ScheduledProcessor) WeeklyLoadAnalyzer() inngestgo.ServableFunction {
 	fn, err := inngestgo.CreateFunction(
 		p.client,
@@ -48,14 +56,58 @@ func (p *ScheduledProcessor) WeeklyLoadAnalyzer() inngestgo.ServableFunction {
 				}
 			}
 
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"total_orgs":       total,
 				"avg_orgs_per_day": avgPerDay,
 				"distribution":     distribution,
 				"high_load_days":   highLoadDays,
 				"low_load_days":    lowLoadDays,
 				"recommendation":   generateLoadRecommendation(distribution, avgPerDay),
-			}, nil
+			}
+
+			if p.repos == nil || p.repos.WeeklyLoadRepo == nil {
+				return result, nil
+			}
+
+			// Aggregate last week's batches and runs for capacity planning
+			report, err := step.Run(ctx, "aggregate-weekly-load", func(ctx context.Context) (*services.WeeklyLoadReport, error) {
+				start, end := services.WeeklyLoadWindow(time.Now())
+				return p.repos.WeeklyLoadRepo.Aggregate(ctx, start, end)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to aggregate weekly load: %w", err)
+			}
+
+			if _, err := step.Run(ctx, "save-weekly-load-report", func(ctx context.Context) (bool, error) {
+				return true, p.repos.WeeklyLoadRepo.Save(ctx, report)
+			}); err != nil {
+				return nil, fmt.Errorf("failed to save weekly load report: %w", err)
+			}
+
+			if record, err := json.Marshal(report); err == nil {
+				fmt.Printf("[WeeklyLoadAnalyzer] weekly_load_report %s\n", record)
+			}
+
+			// Downstream consumers (e.g. a Slack notifier) can subscribe to the event
+			if _, err := step.Run(ctx, "send-weekly-load-event", func(ctx context.Context) (string, error) {
+				return p.client.Send(ctx, inngestgo.Event{
+					Name: "load.weekly_report",
+					Data: map[string]interface{}{"report": report},
+				})
+			}); err != nil {
+				fmt.Printf("[WeeklyLoadAnalyzer] Warning: Failed to send weekly load event: %v\n", err)
+			}
+
+			if webhookURL := os.Getenv("WEEKLY_LOAD_WEBHOOK_URL"); webhookURL != "" {
+				if _, err := step.Run(ctx, "post-weekly-load-webhook", func(ctx context.Context) (bool, error) {
+					return true, postSlackText(webhookURL, report.String())
+				}); err != nil {
+					fmt.Printf("[WeeklyLoadAnalyzer] Warning: Failed to post weekly load report: %v\n", err)
+				}
+			}
+
+			result["weekly_load"] = report
+			return result, nil
 		},
 	)
 
@@ -3,6 +3,7 @@ package workflows
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
@@ -45,11 +47,15 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 			Name:    "Process Network Org Missing Evaluations",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("network.org.missing.process", nil),
+		inngestgo.EventTrigger(trigger.EventNetworkOrgMissing, nil),
 		func(ctx context.Context, input inngestgo.Input[NetworkOrgMissingProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessNetworkOrgMissing] Starting network org missing evaluation processing for org: %s\n", orgID)
 
+			// Runs of this workflow (retries included) share one claim holder, so a
+			// concurrent missing or re-eval run never extracts the same pair
+			claimHolder := "missing:" + input.InputCtx.RunID
+
 			// Step 1: Fetch org details and network
 			orgDetailsResult, err := step.Run(ctx, "fetch-org-details", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessNetworkOrgMissing] Step 1: Fetching org details and network for org: %s\n", orgID)
@@ -114,13 +120,16 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 				}
 			}
 			networkID := orgDetailsData["network_id"].(string)
-			orgWebsites := orgDetailsData["org_websites"].([]interface{})
+			// A JSON null (org without websites) must not panic; an empty list is
+			// reloaded by ProcessNetworkOrgQuestionRunWithCleanup.
+			orgWebsites, _ := orgDetailsData["org_websites"].([]interface{})
 
 			// Convert orgWebsites to string slice
 			websites := make([]string, len(orgWebsites))
 			for i, website := range orgWebsites {
 				websites[i] = website.(string)
 			}
+			fmt.Printf("[ProcessNetworkOrgMissing] Using %d websites for org %s\n", len(websites), orgID)
 
 			// If no missing evaluations, return early
 			if questionCount == 0 {
@@ -195,6 +204,7 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 
 			// Step 3: Process each question run individually (with pre-generated name variations)
 			var allResults []interface{}
+			failedRuns, emptyResponses, skippedRuns := 0, 0, 0
 			var processedRunIDs []uuid.UUID
 			totalCost := 0.0
 			totalCompetitors := 0
@@ -205,6 +215,8 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 				questionRunID := questionRun["question_run_id"].(string)
 				questionText := questionRun["question_text"].(string)
 				responseText := questionRun["response_text"].(string)
+				// Tag snapshot of the run, echoed into the step result for segmenting
+				tags, _ := questionRun["tags"].([]interface{})
 				questionIndex := i + 1
 				stepName := fmt.Sprintf("process-question-run-%d", questionIndex)
 
@@ -222,8 +234,23 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 						return nil, fmt.Errorf("invalid org ID format: %w", err)
 					}
 
-					// Extract network org data (with cleanup to prevent duplicates and pre-generated name variations)
-					result, err := p.questionRunnerService.ProcessNetworkOrgQuestionRunWithCleanup(ctx, questionRunUUID, orgUUID, orgName, websites, nameVariationsStr, questionText, responseText)
+					// Extract network org data (claimed, with cleanup to prevent duplicates and pre-generated name variations)
+					result, err := p.questionRunnerService.ProcessMissingNetworkOrgQuestionRun(ctx, claimHolder, questionRunUUID, orgUUID, orgName, websites, nameVariationsStr, questionText, responseText)
+					if errors.Is(err, services.ErrNetworkOrgRunClaimed) || errors.Is(err, services.ErrNetworkOrgEvalExists) {
+						// Another processor has it or already stored it: nothing to do or charge
+						return map[string]interface{}{
+							"question_run_id": questionRunID,
+							"status":          "skipped",
+							"reason":          err.Error(),
+						}, nil
+					}
+					if errors.Is(err, services.ErrEmptyResponse) {
+						// Retrying cannot fill in the response: report it instead of failing the step
+						return map[string]interface{}{
+							"question_run_id": questionRunID,
+							"status":          "empty_response",
+						}, nil
+					}
 					if err != nil {
 						return nil, fmt.Errorf("failed to process question run %s: %w", questionRunID, err)
 					}
@@ -236,6 +263,7 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 						"evaluation_id":   result.Evaluation.NetworkOrgEvalID,
 						"competitors":     len(result.Competitors),
 						"citations":       len(result.Citations),
+						"tags":            tags,
 						"total_cost":      result.TotalCost,
 						"status":          "completed",
 					}, nil
@@ -243,6 +271,20 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 				if err != nil {
 					fmt.Printf("[ProcessNetworkOrgMissing] Warning: Failed to process question run %d/%d: %v\n",
 						questionIndex, questionCount, err)
+					failedRuns++
+					continue
+				}
+				if m, ok := stepResult.(map[string]interface{}); ok && m["status"] == "skipped" {
+					fmt.Printf("[ProcessNetworkOrgMissing] Skipped question run %d/%d: %s (%v)\n",
+						questionIndex, questionCount, questionRunID, m["reason"])
+					skippedRuns++
+					continue
+				}
+				if m, ok := stepResult.(map[string]interface{}); ok && m["status"] == "empty_response" {
+					fmt.Printf("[ProcessNetworkOrgMissing] Warning: Question run %d/%d has an empty response: %s\n",
+						questionIndex, questionCount, questionRunID)
+					failedRuns++
+					emptyResponses++
 					continue
 				}
 
@@ -308,6 +350,9 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 				"status":                  "completed",
 				"pipeline":                "network_org_missing_processing",
 				"question_runs_processed": questionCount,
+				"question_runs_failed":    failedRuns,
+				"question_runs_skipped":   skippedRuns,
+				"empty_responses":         emptyResponses,
 				"total_competitors":       totalCompetitors,
 				"total_citations":         totalCitations,
 				"total_cost":              totalCost,
@@ -332,9 +377,52 @@ func (p *NetworkOrgMissingProcessor) ProcessNetworkOrgMissing() inngestgo.Servab
 	return fn
 }
 
-// Event types
-type NetworkOrgMissingProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by"`
-	UserID      string `json:"user_id,omitempty"`
+// ReplayNetworkOrgDeadLetters retries the org's failed network org extractions
+// (see QuestionRunnerService.ReplayNetworkOrgDeadLetters) and charges the runs
+// that now succeed, like ProcessNetworkOrgMissing does.
+func (p *NetworkOrgMissingProcessor) ReplayNetworkOrgDeadLetters() inngestgo.ServableFunction {
+	fn, err := inngestgo.CreateFunction(
+		p.client,
+		inngestgo.FunctionOpts{
+			ID:   "replay-network-org-dead-letters",
+			Name: "Replay Failed Network Org Extractions",
+		},
+		inngestgo.EventTrigger(trigger.EventNetworkOrgDeadLetter, nil),
+		func(ctx context.Context, input inngestgo.Input[NetworkOrgDeadLetterReplayEvent]) (any, error) {
+			orgID := input.Event.Data.OrgID
+			fmt.Printf("[ReplayNetworkOrgDeadLetters] Replaying failed network org extractions for org: %s\n", orgID)
+
+			summary, err := step.Run(ctx, "replay-dead-letters", func(ctx context.Context) (*services.NetworkOrgDeadLetterReplaySummary, error) {
+				return p.questionRunnerService.ReplayNetworkOrgDeadLetters(ctx, orgID)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay dead letters: %w", err)
+			}
+
+			if len(summary.ReplayedRunIDs) > 0 {
+				if _, err := step.Run(ctx, "track-usage", func(ctx context.Context) (int, error) {
+					orgUUID, err := uuid.Parse(orgID)
+					if err != nil {
+						return 0, fmt.Errorf("invalid org ID: %w", err)
+					}
+					return p.usageService.TrackIndividualRuns(ctx, orgUUID, summary.ReplayedRunIDs, "network")
+				}); err != nil {
+					// The extractions are stored either way
+					fmt.Printf("[ReplayNetworkOrgDeadLetters] Warning: usage tracking failed: %v\n", err)
+				}
+			}
+
+			return summary, nil
+		},
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to create ReplayNetworkOrgDeadLetters function: %w", err))
+	}
+	return fn
 }
+
+// NetworkOrgDeadLetterReplayEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkOrgDeadLetterReplayEvent = trigger.NetworkOrgDeadLetterReplayEvent
+
+// NetworkOrgMissingProcessEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkOrgMissingProcessEvent = trigger.NetworkOrgMissingProcessEvent
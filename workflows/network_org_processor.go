@@ -3,6 +3,7 @@ package workflows
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
@@ -42,7 +44,7 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 			Name:    "Process Network Org Data Extraction",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("network.org.process", nil),
+		inngestgo.EventTrigger(trigger.EventNetworkOrgProcess, nil),
 		func(ctx context.Context, input inngestgo.Input[NetworkOrgProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessNetworkOrg] Starting network org processing for org: %s\n", orgID)
@@ -72,15 +74,16 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 				orgDetailsData := orgDetailsResult.(map[string]interface{})
 				networkID := orgDetailsData["network_id"].(string)
 
-				questionRuns, err := p.questionRunnerService.GetLatestNetworkQuestionRuns(ctx, networkID)
+				questionRuns, questionDeleted, err := p.questionRunnerService.GetLatestNetworkQuestionRuns(ctx, networkID)
 				if err != nil {
 					return nil, fmt.Errorf("failed to fetch network question runs: %w", err)
 				}
 
-				fmt.Printf("[ProcessNetworkOrg] Found %d latest network question runs\n", len(questionRuns))
+				fmt.Printf("[ProcessNetworkOrg] Found %d latest network question runs (skipped %d with deleted questions)\n", len(questionRuns), questionDeleted)
 				return map[string]interface{}{
-					"question_runs": questionRuns,
-					"count":         len(questionRuns),
+					"question_runs":    questionRuns,
+					"count":            len(questionRuns),
+					"question_deleted": questionDeleted,
 				}, nil
 			})
 			if err != nil {
@@ -125,15 +128,20 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 
 			// Step 3: Process each question run individually (with pre-generated name variations)
 			var allResults []interface{}
+			failedRuns, emptyResponses := 0, 0
+			var planSkipped []string
+			seenPlanSkipped := map[string]bool{}
 			for i, questionRunInterface := range questionRuns {
 				questionRun := questionRunInterface.(map[string]interface{})
 				questionRunID := questionRun["question_run_id"].(string)
 				questionText := questionRun["question_text"].(string)
 				responseText := questionRun["response_text"].(string)
+				// Tag snapshot of the run, echoed into the step result for segmenting
+				tags, _ := questionRun["tags"].([]interface{})
 				questionIndex := i + 1
 				stepName := fmt.Sprintf("process-question-run-%d", questionIndex)
 
-				_, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
+				stepResult, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
 					fmt.Printf("[ProcessNetworkOrg] Step %d: Processing question run %d/%d: %s\n",
 						questionIndex+2, questionIndex, questionCount, questionRunID)
 
@@ -149,6 +157,13 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 
 					// Extract network org data (with cleanup to prevent duplicates and pre-generated name variations)
 					result, err := p.questionRunnerService.ProcessNetworkOrgQuestionRunWithCleanup(ctx, questionRunUUID, orgUUID, orgName, websites, nameVariationsStr, questionText, responseText)
+					if errors.Is(err, services.ErrEmptyResponse) {
+						// Retrying cannot fill in the response: report it instead of failing the step
+						return map[string]interface{}{
+							"question_run_id": questionRunID,
+							"status":          "empty_response",
+						}, nil
+					}
 					if err != nil {
 						return nil, fmt.Errorf("failed to process question run %s: %w", questionRunID, err)
 					}
@@ -161,15 +176,37 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 						"evaluation_id":   result.Evaluation.NetworkOrgEvalID,
 						"competitors":     len(result.Competitors),
 						"citations":       len(result.Citations),
+						"tags":            tags,
+						"plan_skipped":    result.Stages.PlanSkipped(),
 						"status":          "completed",
 					}, nil
 				})
 				if err != nil {
 					fmt.Printf("[ProcessNetworkOrg] Warning: Failed to process question run %d/%d: %v\n",
 						questionIndex, questionCount, err)
+					failedRuns++
+					continue
+				}
+				if m, ok := stepResult.(map[string]interface{}); ok && m["status"] == "empty_response" {
+					fmt.Printf("[ProcessNetworkOrg] Warning: Question run %d/%d has an empty response: %s\n",
+						questionIndex, questionCount, questionRunID)
+					failedRuns++
+					emptyResponses++
 					continue
 				}
 
+				// Collect stages skipped by the org's plan for the summary
+				if m, ok := stepResult.(map[string]interface{}); ok {
+					if stages, ok := m["plan_skipped"].([]interface{}); ok {
+						for _, stage := range stages {
+							if name, ok := stage.(string); ok && !seenPlanSkipped[name] {
+								seenPlanSkipped[name] = true
+								planSkipped = append(planSkipped, name)
+							}
+						}
+					}
+				}
+
 				// Track that this question run was processed
 				allResults = append(allResults, map[string]interface{}{
 					"question_run_id": questionRunID,
@@ -185,8 +222,14 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 				"status":                  "completed",
 				"pipeline":                "network_org_processing",
 				"question_runs_processed": questionCount,
+				"question_runs_failed":    failedRuns,
+				"empty_responses":         emptyResponses,
+				"plan_skipped_stages":     planSkipped,
 				"completed_at":            time.Now().UTC(),
 			}
+			if len(planSkipped) > 0 {
+				fmt.Printf("[ProcessNetworkOrg] ⏭️ Stages skipped by plan: %v\n", planSkipped)
+			}
 
 			fmt.Printf("[ProcessNetworkOrg] ✅ COMPLETED: Network org processing for org %s\n", orgID)
 			fmt.Printf("[ProcessNetworkOrg] 📊 Data stored: network_org_evals, network_org_competitors, network_org_citations\n")
@@ -200,9 +243,5 @@ func (p *NetworkOrgProcessor) ProcessNetworkOrg() inngestgo.ServableFunction {
 	return fn
 }
 
-// Event types
-type NetworkOrgProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by"`
-	UserID      string `json:"user_id,omitempty"`
-}
+// NetworkOrgProcessEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkOrgProcessEvent = trigger.NetworkOrgProcessEvent
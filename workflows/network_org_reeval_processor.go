@@ -11,6 +11,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
@@ -38,12 +39,8 @@ func (p *NetworkOrgReevalProcessor) SetClient(client inngestgo.Client) {
 	p.client = client
 }
 
-// NetworkOrgReevalProcessEvent represents the event data for network org re-evaluation processing
-type NetworkOrgReevalProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by,omitempty"`
-	UserID      string `json:"user_id,omitempty"`
-}
+// NetworkOrgReevalProcessEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkOrgReevalProcessEvent = trigger.NetworkOrgReevalProcessEvent
 
 func (p *NetworkOrgReevalProcessor) ProcessNetworkOrgReeval() inngestgo.ServableFunction {
 	fn, err := inngestgo.CreateFunction(
@@ -53,7 +50,7 @@ func (p *NetworkOrgReevalProcessor) ProcessNetworkOrgReeval() inngestgo.Servable
 			Name:    "Process Network Org Re-evaluation - Enhanced with Org Evaluation Methodology",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("network.org.reeval.enhanced", nil),
+		inngestgo.EventTrigger(trigger.EventNetworkOrgReevalFull, nil),
 		func(ctx context.Context, input inngestgo.Input[NetworkOrgReevalProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessNetworkOrgReevalEnhanced] Starting enhanced network org re-evaluation for org: %s\n", orgID)
@@ -120,15 +117,16 @@ func (p *NetworkOrgReevalProcessor) ProcessNetworkOrgReeval() inngestgo.Servable
 			questionRunsResult, err := step.Run(ctx, "fetch-all-network-question-runs", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessNetworkOrgReevalEnhanced] Step 3: Fetching ALL network question runs for network: %s\n", networkID)
 
-				questionRuns, err := p.questionRunnerService.GetAllNetworkQuestionRuns(ctx, networkID)
+				questionRuns, questionDeleted, err := p.questionRunnerService.GetAllNetworkQuestionRuns(ctx, networkID)
 				if err != nil {
 					return nil, fmt.Errorf("failed to fetch all network question runs: %w", err)
 				}
 
-				fmt.Printf("[ProcessNetworkOrgReevalEnhanced] ✅ Found %d total network question runs to re-evaluate\n", len(questionRuns))
+				fmt.Printf("[ProcessNetworkOrgReevalEnhanced] ✅ Found %d total network question runs to re-evaluate (skipped %d with deleted questions)\n", len(questionRuns), questionDeleted)
 				return map[string]interface{}{
-					"question_runs": questionRuns,
-					"total_runs":    len(questionRuns),
+					"question_runs":    questionRuns,
+					"total_runs":       len(questionRuns),
+					"question_deleted": questionDeleted,
 				}, nil
 			})
 			if err != nil {
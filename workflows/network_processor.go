@@ -11,6 +11,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
@@ -48,7 +49,7 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 			Name:    "Process Network Questions - Multi-Model/Location Pipeline with Batching",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("network.questions.process", nil),
+		inngestgo.EventTrigger(trigger.EventNetworkQuestions, nil),
 		func(ctx context.Context, input inngestgo.Input[NetworkProcessEvent]) (any, error) {
 			networkID := input.Event.Data.NetworkID
 			fmt.Printf("[ProcessNetwork] 🚀 Starting network questions pipeline for network: %s\n", networkID)
@@ -68,8 +69,10 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 					return nil, fmt.Errorf("failed to get network details: %w", err)
 				}
 
-				// Calculate total questions: questions × models × locations
-				totalQuestions := len(networkDetails.Questions) * len(networkDetails.Models) * len(networkDetails.Locations)
+				// Calculate total questions: questions × models × locations, counting
+				// only today's sample for networks configured for sampling
+				sampledQuestions := services.NewQuestionSampler(p.cfg, networkUUID, time.Now()).Filter(networkDetails.Questions)
+				totalQuestions := len(sampledQuestions) * len(networkDetails.Models) * len(networkDetails.Locations)
 
 				// Step 1.5 Check Partner Balance
 				_, err = step.Run(ctx, "check-balance", func(ctx context.Context) (interface{}, error) {
@@ -199,19 +202,26 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 				fmt.Printf("[ProcessNetwork] ✅ Question matrix completed: %d processed, $%.6f total cost\n",
 					summary.TotalProcessed, summary.TotalCost)
 
-				// Update batch progress with completed counts
+				// Update batch progress with completed counts; runs resumed from an
+				// earlier attempt of this step are already complete.
 				failedCount := len(summary.ProcessingErrors)
-				if err := p.questionRunnerService.UpdateNetworkBatchProgress(ctx, batchUUID, summary.TotalProcessed, failedCount); err != nil {
+				if err := p.questionRunnerService.UpdateNetworkBatchProgress(ctx, batchUUID, summary.TotalProcessed+summary.TotalResumed, failedCount); err != nil {
 					fmt.Printf("[ProcessNetwork] Warning: Failed to update batch progress: %v\n", err)
 					// Don't fail the step, just log the warning
 				}
 
 				return map[string]interface{}{
-					"total_processed":   summary.TotalProcessed,
-					"total_cost":        summary.TotalCost,
-					"processing_errors": summary.ProcessingErrors,
-					"models_used":       len(networkDetails.Models),
-					"locations_used":    len(networkDetails.Locations),
+					"total_processed":     summary.TotalProcessed,
+					"total_resumed":       summary.TotalResumed,
+					"total_skipped":       summary.TotalSkipped,
+					"question_deleted":    summary.QuestionDeleted,
+					"languages":           summary.Languages,
+					"unconfigured_models": summary.UnconfiguredModels,
+					"invalid_locations":   summary.InvalidLocations,
+					"total_cost":          summary.TotalCost,
+					"processing_errors":   summary.ProcessingErrors,
+					"models_used":         len(networkDetails.Models),
+					"locations_used":      len(networkDetails.Locations),
 				}, nil
 			})
 			if err != nil {
@@ -269,16 +279,23 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 				}
 
 				// Calculate failed questions from processing errors
-				totalProcessed := int(processingSummary["total_processed"].(float64))
+				// total_resumed/total_skipped are missing from step results memoized before they existed
+				resumed, _ := processingSummary["total_resumed"].(float64)
+				skipped, _ := processingSummary["total_skipped"].(float64)
+				totalProcessed := int(processingSummary["total_processed"].(float64)) + int(resumed)
+				totalSkipped := int(skipped)
 				processingErrorsList := processingSummary["processing_errors"].([]interface{})
 				totalFailed := len(processingErrorsList)
 
 				// Mark batch as completed with final counts and completion timestamp
-				if err := p.questionRunnerService.CompleteNetworkBatch(ctx, batchUUID, totalProcessed, totalFailed); err != nil {
+				if err := p.questionRunnerService.CompleteNetworkBatch(ctx, batchUUID, totalProcessed, totalFailed, totalSkipped); err != nil {
 					return nil, fmt.Errorf("failed to complete batch: %w", err)
 				}
 
-				fmt.Printf("[ProcessNetwork] ✅ Batch %s completed successfully (processed=%d, failed=%d)\n", batchID, totalProcessed, totalFailed)
+				fmt.Printf("[ProcessNetwork] ✅ Batch %s completed successfully (processed=%d, failed=%d, skipped=%d)\n", batchID, totalProcessed, totalFailed, totalSkipped)
+				if unconfigured := unconfiguredModels(processingSummary); len(unconfigured) > 0 {
+					fmt.Printf("[ProcessNetwork] ⚠️ Batch %s is degraded: unconfigured models %v produced no runs\n", batchID, unconfigured)
+				}
 				return map[string]interface{}{
 					"batch_id": batchID,
 					"status":   "completed",
@@ -298,6 +315,23 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 				return nil, fmt.Errorf("step 5 failed: %w", err)
 			}
 
+			// Degraded batches (configured models no provider recognizes) are
+			// announced so they don't go unnoticed day after day
+			if unconfigured := unconfiguredModels(processingSummary); len(unconfigured) > 0 {
+				if _, err := step.Run(ctx, "send-degraded-batch-event", func(ctx context.Context) (string, error) {
+					return p.client.Send(ctx, inngestgo.Event{
+						Name: "network.batch.degraded",
+						Data: map[string]interface{}{
+							"network_id":          networkID,
+							"batch_id":            batchID,
+							"unconfigured_models": unconfigured,
+						},
+					})
+				}); err != nil {
+					fmt.Printf("[ProcessNetwork] Warning: Failed to send degraded batch event: %v\n", err)
+				}
+			}
+
 			// Step 6: Trigger Org-Level Processing for All Network Organizations
 			orgTriggerData, err := step.Run(ctx, "trigger-org-level-processing", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessNetwork] Step 6: Triggering org-level processing for network: %s\n", networkID)
@@ -327,16 +361,11 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 				// Trigger network.org.missing.process event for each org
 				triggeredCount := 0
 				for _, orgID := range orgIDs {
-					evt := inngestgo.Event{
-						Name: "network.org.missing.process",
-						Data: map[string]interface{}{
-							"org_id":       orgID.String(),
-							"triggered_by": "network_completion",
-							"network_id":   networkID,
-						},
-					}
-
-					_, err := p.client.Send(ctx, evt)
+					_, err := trigger.TriggerNetworkOrgMissing(ctx, p.client, trigger.NetworkOrgMissingProcessEvent{
+						OrgID:       orgID.String(),
+						TriggeredBy: trigger.TriggeredByNetworkComplete,
+						NetworkID:   networkID,
+					})
 					if err != nil {
 						// Log error but continue with other orgs
 						fmt.Printf("[ProcessNetwork] Warning: Failed to trigger org-level processing for org %s: %v\n", orgID.String(), err)
@@ -377,6 +406,9 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 				"processing_errors":   processingSummary["processing_errors"],
 				"models_used":         processingSummary["models_used"],
 				"locations_used":      processingSummary["locations_used"],
+				"languages":           processingSummary["languages"],
+				"unconfigured_models": processingSummary["unconfigured_models"],
+				"invalid_locations":   processingSummary["invalid_locations"],
 				"completed_at":        time.Now().UTC(),
 			}
 
@@ -394,9 +426,24 @@ func (p *NetworkProcessor) ProcessNetwork() inngestgo.ServableFunction {
 	return fn
 }
 
-// Event types
-type NetworkProcessEvent struct {
-	NetworkID   string `json:"network_id"`
-	TriggeredBy string `json:"triggered_by"`
-	UserID      string `json:"user_id,omitempty"`
+// unconfiguredModels reads the unconfigured_models of the run-question-matrix
+// step output: []string when the step just ran, []interface{} when its output
+// was memoized, missing for outputs memoized before the field existed.
+func unconfiguredModels(processingSummary map[string]interface{}) []string {
+	switch v := processingSummary["unconfigured_models"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, name := range v {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
 }
+
+// NetworkProcessEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkProcessEvent = trigger.NetworkProcessEvent
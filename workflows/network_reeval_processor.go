@@ -3,6 +3,7 @@ package workflows
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
@@ -42,7 +44,7 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 			Name:    "Process Network Org Data Re-evaluation - All Question Runs",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("network.org.reeval", nil),
+		inngestgo.EventTrigger(trigger.EventNetworkOrgReeval, nil),
 		func(ctx context.Context, input inngestgo.Input[NetworkReevalProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessNetworkReeval] Starting network org re-evaluation for org: %s\n", orgID)
@@ -72,15 +74,16 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 				orgDetailsData := orgDetailsResult.(map[string]interface{})
 				networkID := orgDetailsData["network_id"].(string)
 
-				questionRuns, err := p.questionRunnerService.GetAllNetworkQuestionRuns(ctx, networkID)
+				questionRuns, questionDeleted, err := p.questionRunnerService.GetAllNetworkQuestionRuns(ctx, networkID)
 				if err != nil {
 					return nil, fmt.Errorf("failed to fetch all network question runs: %w", err)
 				}
 
-				fmt.Printf("[ProcessNetworkReeval] Found %d total network question runs\n", len(questionRuns))
+				fmt.Printf("[ProcessNetworkReeval] Found %d total network question runs (skipped %d with deleted questions)\n", len(questionRuns), questionDeleted)
 				return map[string]interface{}{
-					"question_runs": questionRuns,
-					"count":         len(questionRuns),
+					"question_runs":    questionRuns,
+					"count":            len(questionRuns),
+					"question_deleted": questionDeleted,
 				}, nil
 			})
 			if err != nil {
@@ -125,6 +128,7 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 
 			// Step 3: Process each question run individually with cleanup (and pre-generated name variations)
 			var allResults []interface{}
+			failedRuns, emptyResponses := 0, 0
 			for i, questionRunInterface := range questionRuns {
 				questionRun := questionRunInterface.(map[string]interface{})
 				questionRunID := questionRun["question_run_id"].(string)
@@ -133,7 +137,7 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 				questionIndex := i + 1
 				stepName := fmt.Sprintf("process-question-run-%d", questionIndex)
 
-				_, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
+				stepResult, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
 					fmt.Printf("[ProcessNetworkReeval] Step %d: Processing question run %d/%d: %s\n",
 						questionIndex+2, questionIndex, questionCount, questionRunID)
 
@@ -149,6 +153,13 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 
 					// Process with cleanup - delete existing data before saving new (with pre-generated name variations)
 					result, err := p.questionRunnerService.ProcessNetworkOrgQuestionRunWithCleanup(ctx, questionRunUUID, orgUUID, orgName, websites, nameVariationsStr, questionText, responseText)
+					if errors.Is(err, services.ErrEmptyResponse) {
+						// Retrying cannot fill in the response: report it instead of failing the step
+						return map[string]interface{}{
+							"question_run_id": questionRunID,
+							"status":          "empty_response",
+						}, nil
+					}
 					if err != nil {
 						return nil, fmt.Errorf("failed to process question run %s: %w", questionRunID, err)
 					}
@@ -167,6 +178,14 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 				if err != nil {
 					fmt.Printf("[ProcessNetworkReeval] Warning: Failed to process question run %d/%d: %v\n",
 						questionIndex, questionCount, err)
+					failedRuns++
+					continue
+				}
+				if m, ok := stepResult.(map[string]interface{}); ok && m["status"] == "empty_response" {
+					fmt.Printf("[ProcessNetworkReeval] Warning: Question run %d/%d has an empty response: %s\n",
+						questionIndex, questionCount, questionRunID)
+					failedRuns++
+					emptyResponses++
 					continue
 				}
 
@@ -185,6 +204,8 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 				"status":                  "completed",
 				"pipeline":                "network_org_reeval",
 				"question_runs_processed": questionCount,
+				"question_runs_failed":    failedRuns,
+				"empty_responses":         emptyResponses,
 				"completed_at":            time.Now().UTC(),
 			}
 
@@ -200,9 +221,5 @@ func (p *NetworkReevalProcessor) ProcessNetworkReeval() inngestgo.ServableFuncti
 	return fn
 }
 
-// Event types
-type NetworkReevalProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by"`
-	UserID      string `json:"user_id,omitempty"`
-}
+// NetworkReevalProcessEvent is the event payload; its schema lives in pkg/trigger.
+type NetworkReevalProcessEvent = trigger.NetworkReevalProcessEvent
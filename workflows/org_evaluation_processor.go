@@ -9,6 +9,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
 )
@@ -39,12 +40,8 @@ func (p *OrgEvaluationProcessor) SetClient(client inngestgo.Client) {
 	p.client = client
 }
 
-// OrgEvaluationProcessEvent represents the event data for org evaluation processing
-type OrgEvaluationProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by,omitempty"`
-	UserID      string `json:"user_id,omitempty"`
-}
+// OrgEvaluationProcessEvent is the event payload; its schema lives in pkg/trigger.
+type OrgEvaluationProcessEvent = trigger.OrgEvaluationProcessEvent
 
 func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFunction {
 	fn, err := inngestgo.CreateFunction(
@@ -54,7 +51,7 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 			Name:    "Process Organization Evaluation - Advanced Brand Analysis Pipeline",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("org.evaluation.process", nil),
+		inngestgo.EventTrigger(trigger.EventOrgEvaluation, nil),
 		func(ctx context.Context, input inngestgo.Input[OrgEvaluationProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessOrgEvaluation] Starting advanced brand analysis pipeline for org: %s\n", orgID)
@@ -232,12 +229,16 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 					summary.TotalProcessed, summary.TotalEvaluations, summary.TotalCitations, summary.TotalCompetitors, summary.TotalCost)
 
 				return map[string]interface{}{
-					"total_processed":   summary.TotalProcessed,
-					"total_evaluations": summary.TotalEvaluations,
-					"total_citations":   summary.TotalCitations,
-					"total_competitors": summary.TotalCompetitors,
-					"total_cost":        summary.TotalCost,
-					"errors":            summary.ProcessingErrors,
+					"total_processed":     summary.TotalProcessed,
+					"total_evaluations":   summary.TotalEvaluations,
+					"total_citations":     summary.TotalCitations,
+					"total_competitors":   summary.TotalCompetitors,
+					"total_cost":          summary.TotalCost,
+					"errors":              summary.ProcessingErrors,
+					"deadline_reached":    summary.DeadlineReached,
+					"remaining_questions": summary.RemainingQuestions,
+					"languages":           summary.Languages,
+					"invalid_locations":   summary.InvalidLocations,
 				}, nil
 			})
 			if err != nil {
@@ -255,6 +256,14 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 			}
 
 			processingSummary := processingData.(map[string]interface{})
+			deadlineReached, _ := processingSummary["deadline_reached"].(bool)
+			remainingQuestions := 0
+			switch v := processingSummary["remaining_questions"].(type) {
+			case int:
+				remainingQuestions = v
+			case float64:
+				remainingQuestions = int(v)
+			}
 
 			// Step 4: Track Usage for Successful Runs
 			usageData, err := step.Run(ctx, "track-usage", func(ctx context.Context) (interface{}, error) {
@@ -286,7 +295,7 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 				fmt.Printf("[ProcessOrgEvaluation] Warning: Step 4 (track-usage) failed: %v\n", err)
 			}
 
-			// Step 5: Complete Batch
+			// Step 5: Complete Batch (or mark it partial if the soft deadline was hit)
 			_, err = step.Run(ctx, "complete-batch", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessOrgEvaluation] Step 4: Completing batch: %s\n", batchID)
 
@@ -295,6 +304,17 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 					return nil, fmt.Errorf("invalid batch ID: %w", err)
 				}
 
+				if deadlineReached {
+					if err := p.orgEvaluationService.PartialBatch(ctx, batchUUID, remainingQuestions); err != nil {
+						return nil, fmt.Errorf("failed to mark batch partial: %w", err)
+					}
+					fmt.Printf("[ProcessOrgEvaluation] ⏰ Batch %s marked partial, %d questions remaining for a follow-up run\n", batchID, remainingQuestions)
+					return map[string]interface{}{
+						"batch_id": batchID,
+						"status":   "partial",
+					}, nil
+				}
+
 				if err := p.orgEvaluationService.CompleteBatch(ctx, batchUUID); err != nil {
 					return nil, fmt.Errorf("failed to complete batch: %w", err)
 				}
@@ -324,6 +344,11 @@ func (p *OrgEvaluationProcessor) ProcessOrgEvaluation() inngestgo.ServableFuncti
 				"processing_errors": processingSummary["errors"],
 				"status":            "completed",
 			}
+			if deadlineReached {
+				// Re-sending org.evaluation.process resumes today's batch and skips existing runs
+				finalResult["status"] = "partial"
+				finalResult["remaining_questions"] = remainingQuestions
+			}
 			if usageData != nil {
 				finalResult["usage_data"] = usageData
 			}
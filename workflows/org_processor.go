@@ -10,6 +10,7 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 	"github.com/google/uuid"
 )
@@ -48,7 +49,7 @@ func (p *OrgProcessor) ProcessOrg() inngestgo.ServableFunction {
 			Name:    "Process Organization - Full Competitive Intelligence Pipeline",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("org.process", nil),
+		inngestgo.EventTrigger(trigger.EventOrgProcess, nil),
 		func(ctx context.Context, input inngestgo.Input[OrgProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessOrg] Starting full competitive intelligence pipeline for org: %s\n", orgID)
@@ -72,19 +73,26 @@ func (p *OrgProcessor) ProcessOrg() inngestgo.ServableFunction {
 			// Step 2: Execute Question Matrix & Store Question Runs
 			questionRuns, err := step.Run(ctx, "execute-and-store-question-matrix", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessOrg] Step 2: Executing AI calls and storing question runs\n")
-				runs, err := p.questionRunnerService.RunQuestionMatrix(ctx, orgDetails)
+				summary, err := p.questionRunnerService.RunQuestionMatrix(ctx, orgDetails)
 				if err != nil {
 					return nil, fmt.Errorf("failed to run question matrix: %w", err)
 				}
 
-				fmt.Printf("[ProcessOrg] Successfully processed %d question runs with full data extraction\n", len(runs))
+				fmt.Printf("[ProcessOrg] Successfully processed %d question runs with full data extraction\n", len(summary.Runs))
 				return map[string]interface{}{
-					"total_runs":      len(runs),
+					"total_runs":      len(summary.Runs),
+					"total_mentions":  summary.Yield.Mentions,
+					"total_claims":    summary.Yield.Claims,
+					"total_citations": summary.Yield.Citations,
+					"response_cost":   summary.ResponseCost,
+					"extraction_cost": summary.Yield.ExtractionCost,
 					"org_name":        orgDetails.Org.Name,
 					"target_company":  orgDetails.TargetCompany,
 					"questions_count": len(orgDetails.Questions),
 					"models_count":    len(orgDetails.Models),
 					"locations_count": len(orgDetails.Locations),
+					// Stages the org's plan excludes (recorded as skipped_plan per run)
+					"plan_skipped_stages": services.NewConfigOrgFeatureProvider(p.cfg).FeaturesForOrg(ctx, orgDetails.Org.OrgID).PlanSkipped(),
 				}, nil
 			})
 			if err != nil {
@@ -158,10 +166,5 @@ func (p *OrgProcessor) ProcessOrg() inngestgo.ServableFunction {
 	return fn
 }
 
-// Event types
-type OrgProcessEvent struct {
-	OrgID         string `json:"org_id"`
-	TriggeredBy   string `json:"triggered_by"`
-	UserID        string `json:"user_id,omitempty"`
-	ScheduledDate string `json:"scheduled_date,omitempty"`
-}
+// OrgProcessEvent is the event payload; its schema lives in pkg/trigger.
+type OrgProcessEvent = trigger.OrgProcessEvent
@@ -10,21 +10,24 @@ import (
 	"github.com/inngest/inngestgo/step"
 
 	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
 // OrgReevalProcessor handles org re-evaluation workflows
 type OrgReevalProcessor struct {
-	client               inngestgo.Client
-	orgService           services.OrgService
-	orgEvaluationService services.OrgEvaluationService
+	client                inngestgo.Client
+	orgService            services.OrgService
+	orgEvaluationService  services.OrgEvaluationService
+	questionRunnerService services.QuestionRunnerService
 }
 
 // NewOrgReevalProcessor creates a new org re-evaluation processor
-func NewOrgReevalProcessor(cfg *config.Config, orgService services.OrgService, orgEvaluationService services.OrgEvaluationService) *OrgReevalProcessor {
+func NewOrgReevalProcessor(cfg *config.Config, orgService services.OrgService, orgEvaluationService services.OrgEvaluationService, questionRunnerService services.QuestionRunnerService) *OrgReevalProcessor {
 	return &OrgReevalProcessor{
-		orgService:           orgService,
-		orgEvaluationService: orgEvaluationService,
+		orgService:            orgService,
+		orgEvaluationService:  orgEvaluationService,
+		questionRunnerService: questionRunnerService,
 	}
 }
 
@@ -33,12 +36,8 @@ func (p *OrgReevalProcessor) SetClient(client inngestgo.Client) {
 	p.client = client
 }
 
-// OrgReevalProcessEvent represents the event data for org re-evaluation processing
-type OrgReevalProcessEvent struct {
-	OrgID       string `json:"org_id"`
-	TriggeredBy string `json:"triggered_by,omitempty"`
-	UserID      string `json:"user_id,omitempty"`
-}
+// OrgReevalProcessEvent is the event payload; its schema lives in pkg/trigger.
+type OrgReevalProcessEvent = trigger.OrgReevalProcessEvent
 
 func (p *OrgReevalProcessor) ProcessOrgReeval() inngestgo.ServableFunction {
 	fn, err := inngestgo.CreateFunction(
@@ -48,7 +47,7 @@ func (p *OrgReevalProcessor) ProcessOrgReeval() inngestgo.ServableFunction {
 			Name:    "Process Organization Re-evaluation - All Question Runs",
 			Retries: inngestgo.IntPtr(3),
 		},
-		inngestgo.EventTrigger("org.reeval.all.process", nil),
+		inngestgo.EventTrigger(trigger.EventOrgReeval, nil),
 		func(ctx context.Context, input inngestgo.Input[OrgReevalProcessEvent]) (any, error) {
 			orgID := input.Event.Data.OrgID
 			fmt.Printf("[ProcessOrgReeval] Starting org re-evaluation for ALL question runs for org: %s\n", orgID)
@@ -66,9 +65,10 @@ func (p *OrgReevalProcessor) ProcessOrgReeval() inngestgo.ServableFunction {
 					orgDetails.Org.Name, len(orgDetails.Questions), len(orgDetails.Websites))
 
 				return map[string]interface{}{
-					"org_id":   orgID,
-					"org_name": orgDetails.Org.Name,
-					"websites": orgDetails.Websites,
+					"org_id":         orgID,
+					"org_name":       orgDetails.Org.Name,
+					"websites":       orgDetails.Websites,
+					"target_company": orgDetails.TargetCompany,
 				}, nil
 			})
 			if err != nil {
@@ -85,6 +85,11 @@ func (p *OrgReevalProcessor) ProcessOrgReeval() inngestgo.ServableFunction {
 				websites[i] = v.(string)
 			}
 
+			if input.Event.Data.RepairFailedStages {
+				targetCompany, _ := orgDetailsData["target_company"].(string)
+				return p.repairFailedStages(ctx, orgID, orgName, targetCompany, websites)
+			}
+
 			// Step 2: Generate Name Variations
 			nameVariationsResult, err := step.Run(ctx, "generate-name-variations", func(ctx context.Context) (interface{}, error) {
 				fmt.Printf("[ProcessOrgReeval] Step 2: Generating name variations for org: %s\n", orgName)
@@ -254,3 +259,92 @@ func (p *OrgReevalProcessor) ProcessOrgReeval() inngestgo.ServableFunction {
 
 	return fn
 }
+
+// repairFailedStages runs the targeted repair mode: it lists the org's question
+// runs with a failed or pending extraction stage and re-runs only those stages,
+// one Inngest step per run.
+func (p *OrgReevalProcessor) repairFailedStages(ctx context.Context, orgID, orgName, targetCompany string, websites []string) (any, error) {
+	incompleteResult, err := step.Run(ctx, "list-incomplete-stage-runs", func(ctx context.Context) (interface{}, error) {
+		orgUUID, err := uuid.Parse(orgID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid org ID: %w", err)
+		}
+
+		statuses, err := p.questionRunnerService.ListRunsWithIncompleteStages(ctx, orgUUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runs with incomplete stages: %w", err)
+		}
+
+		runIDs := make([]string, len(statuses))
+		for i, status := range statuses {
+			runIDs[i] = status.QuestionRunID.String()
+		}
+		fmt.Printf("[ProcessOrgReeval] 🔧 Repair mode: %d question runs with failed/pending stages\n", len(runIDs))
+		return map[string]interface{}{
+			"question_run_ids": runIDs,
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list incomplete stage runs step failed: %w", err)
+	}
+
+	runIDsInterface := incompleteResult.(map[string]interface{})["question_run_ids"].([]interface{})
+	repaired, stillIncomplete, failed := 0, 0, 0
+	var processingErrors []string
+	for i, v := range runIDsInterface {
+		runIDStr := v.(string)
+		runIndex := i + 1
+
+		result, err := step.Run(ctx, fmt.Sprintf("repair-question-run-%d", runIndex), func(ctx context.Context) (interface{}, error) {
+			runID, err := uuid.Parse(runIDStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid question run ID: %w", err)
+			}
+			orgUUID, err := uuid.Parse(orgID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid org ID: %w", err)
+			}
+
+			status, err := p.questionRunnerService.RepairQuestionRunStages(ctx, runID, orgUUID, targetCompany, websites)
+			if err != nil {
+				return nil, fmt.Errorf("failed to repair question run %s: %w", runID, err)
+			}
+
+			fmt.Printf("[ProcessOrgReeval] 🔧 Repaired run %d/%d: %s (mentions=%s claims=%s citations=%s metrics=%s)\n",
+				runIndex, len(runIDsInterface), runID, status.MentionsStatus, status.ClaimsStatus, status.CitationsStatus, status.MetricsStatus)
+			return map[string]interface{}{
+				"question_run_id": runID.String(),
+				"needs_repair":    status.NeedsRepair(),
+			}, nil
+		})
+		if err != nil {
+			fmt.Printf("[ProcessOrgReeval] Warning: Failed to repair question run %d/%d: %v\n", runIndex, len(runIDsInterface), err)
+			processingErrors = append(processingErrors, err.Error())
+			failed++
+			continue
+		}
+
+		if result.(map[string]interface{})["needs_repair"].(bool) {
+			stillIncomplete++
+		} else {
+			repaired++
+		}
+	}
+
+	fmt.Printf("[ProcessOrgReeval] 🎉 Repair completed for org %s: repaired=%d still_incomplete=%d failed=%d\n",
+		orgName, repaired, stillIncomplete, failed)
+
+	return map[string]interface{}{
+		"org_id":            orgID,
+		"org_name":          orgName,
+		"processing_time":   time.Now().Format("2006-01-02 15:04:05"),
+		"mode":              "repair_failed_stages",
+		"total_candidates":  len(runIDsInterface),
+		"total_repaired":    repaired,
+		"still_incomplete":  stillIncomplete,
+		"total_failed":      failed,
+		"processing_errors": processingErrors,
+		"pipeline_version":  "org_reeval_v1.0",
+		"status":            "completed",
+	}, nil
+}
@@ -0,0 +1,71 @@
+// workflows/reconciliation.go
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/inngest/inngestgo"
+	"github.com/inngest/inngestgo/step"
+
+	"github.com/AI-Template-SDK/senso-workflows/services"
+)
+
+// WeeklyReconciliation checks batches, is_latest flags, network org evals and
+// network org dead letters for divergence (see services.Reconcile), applies the safe fixes when
+// RECONCILE_AUTO_FIX is set, and stores and logs the report.
+func (p *ScheduledProcessor) WeeklyReconciliation() inngestgo.ServableFunction {
+	fn, err := inngestgo.CreateFunction(
+		p.client,
+		inngestgo.FunctionOpts{
+			ID:   "weekly-reconciliation",
+			Name: "Reconcile Batches, Runs and Evaluations",
+		},
+		inngestgo.CronTrigger("0 3 * * 0"), // Every Sunday at 3 AM UTC
+		func(ctx context.Context, input inngestgo.Input[any]) (any, error) {
+			if p.repos == nil || p.repos.ReconciliationRepo == nil {
+				return map[string]interface{}{"status": "skipped", "reason": "reconciliation repository not configured"}, nil
+			}
+
+			report, err := step.Run(ctx, "reconcile", func(ctx context.Context) (*services.ReconciliationReport, error) {
+				opts := services.ReconcileOptions{
+					BatchesSince: time.Now().UTC().AddDate(0, 0, -p.cfg.ReconcileLookbackDays),
+					AutoFix:      p.cfg.ReconcileAutoFix,
+					// Entries at the limit are no longer replayed, so this is where they surface
+					DeadLetterMaxAttempts: p.cfg.NetworkOrgDeadLetterMaxAttempts,
+				}
+				return services.Reconcile(ctx, p.repos.ReconciliationRepo, opts)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to reconcile: %w", err)
+			}
+
+			if _, err := step.Run(ctx, "save-reconciliation-report", func(ctx context.Context) (bool, error) {
+				return true, p.repos.ReconciliationRepo.Save(ctx, report)
+			}); err != nil {
+				// The report is still logged below
+				fmt.Printf("[WeeklyReconciliation] Warning: %v\n", err)
+			}
+
+			fmt.Printf("[WeeklyReconciliation] %s\n", report)
+			if record, err := json.Marshal(report); err == nil {
+				fmt.Printf("[WeeklyReconciliation] reconciliation_report %s\n", record)
+			}
+
+			return map[string]interface{}{
+				"status":   "completed",
+				"auto_fix": report.AutoFix,
+				"counts":   report.Counts,
+				"fixed":    report.Fixed,
+			}, nil
+		},
+	)
+
+	if err != nil {
+		fmt.Printf("Failed to create weekly reconciliation function: %v\n", err)
+	}
+
+	return fn
+}
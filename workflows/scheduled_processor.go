@@ -10,17 +10,21 @@ import (
 	"github.com/inngest/inngestgo"
 	"github.com/inngest/inngestgo/step"
 
+	"github.com/AI-Template-SDK/senso-workflows/internal/config"
+	"github.com/AI-Template-SDK/senso-workflows/pkg/trigger"
 	"github.com/AI-Template-SDK/senso-workflows/services"
 )
 
 type ScheduledProcessor struct {
+	cfg        *config.Config
 	orgService services.OrgService
 	repos      *services.RepositoryManager
 	client     inngestgo.Client
 }
 
-func NewScheduledProcessor(orgService services.OrgService, repos *services.RepositoryManager) *ScheduledProcessor {
+func NewScheduledProcessor(cfg *config.Config, orgService services.OrgService, repos *services.RepositoryManager) *ScheduledProcessor {
 	return &ScheduledProcessor{
+		cfg:        cfg,
 		orgService: orgService,
 		repos:      repos,
 	}
@@ -72,15 +76,10 @@ func (p *ScheduledProcessor) DailyOrgProcessor() inngestgo.ServableFunction {
 
 				// This step.Run is now *inside* the loop and is idempotent per-org
 				_, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
-					evt := inngestgo.Event{
-						Name: "org.evaluation.process",
-						Data: map[string]interface{}{
-							"org_id":       orgID.String(),
-							"triggered_by": "automatic_scheduler",
-						},
-					}
-					// Send the single event
-					return p.client.Send(ctx, evt)
+					return trigger.TriggerOrgEvaluation(ctx, p.client, trigger.OrgEvaluationProcessEvent{
+						OrgID:       orgID.String(),
+						TriggeredBy: trigger.TriggeredByScheduler,
+					})
 				})
 
 				if err != nil {
@@ -150,15 +149,10 @@ func (p *ScheduledProcessor) DailyNetworkProcessor() inngestgo.ServableFunction
 
 				// This step.Run is now *inside* the loop and is idempotent per-network
 				_, err := step.Run(ctx, stepName, func(ctx context.Context) (interface{}, error) {
-					evt := inngestgo.Event{
-						Name: "network.questions.process",
-						Data: map[string]interface{}{
-							"network_id":   networkID.String(),
-							"triggered_by": "automatic_scheduler",
-						},
-					}
-					// Send the single event
-					return p.client.Send(ctx, evt)
+					return trigger.TriggerNetworkQuestions(ctx, p.client, trigger.NetworkProcessEvent{
+						NetworkID:   networkID.String(),
+						TriggeredBy: trigger.TriggeredByScheduler,
+					})
 				})
 
 				if err != nil {
@@ -33,6 +33,11 @@ func ReportErrorToSlack(err error) error {
 		err.Error(),
 	)
 
+	return postSlackText(webhookURL, message)
+}
+
+// postSlackText posts a plain text message to a Slack incoming webhook.
+func postSlackText(webhookURL, message string) error {
 	payload := SlackPayload{
 		Text: message,
 	}